@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Level is the part of a grid-trading level the scenarios inspect (the API
+// encodes levels with their Go field names)
+type Level struct {
+	ID          int
+	Symbol      string
+	BuyPrice    decimal.Decimal
+	SellPrice   decimal.Decimal
+	State       string
+	BuyOrderID  nullString
+	SellOrderID nullString
+}
+
+type nullString struct {
+	String string
+	Valid  bool
+}
+
+// Status is the subset of grid-trading's /status used for assertions
+type Status struct {
+	BuysToday     int             `json:"buys_today"`
+	SellsToday    int             `json:"sells_today"`
+	ProfitAllTime decimal.Decimal `json:"profit_all_time"`
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// getJSON and postJSON send the grid-trading key with every request, the mock exchange ignores it
+func getJSON(url string, out interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-API-Key", e2eGridKey)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decodeResponse(resp, out)
+}
+
+func postJSON(url string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", e2eGridKey)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decodeResponse(resp, out)
+}
+
+func decodeResponse(resp *http.Response, out interface{}) error {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s returned status %d: %s", resp.Request.URL, resp.StatusCode, bytes.TrimSpace(body))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// setPrice moves the mock exchange price, filling any orders it crosses
+func (c *Cluster) setPrice(symbol string, price string) error {
+	return postJSON(c.URL("mock-exchange")+"/mock/price", map[string]string{"symbol": symbol, "price": price}, nil)
+}
+
+func (c *Cluster) createGrid(symbol, minPrice, maxPrice, step, buyAmount string) error {
+	return postJSON(c.URL("grid-trading")+"/levels/init", map[string]string{
+		"symbol":     symbol,
+		"min_price":  minPrice,
+		"max_price":  maxPrice,
+		"grid_step":  step,
+		"buy_amount": buyAmount,
+	}, nil)
+}
+
+func (c *Cluster) levels(symbol string) ([]Level, error) {
+	var levels []Level
+	err := getJSON(c.URL("grid-trading")+"/levels/"+symbol, &levels)
+	return levels, err
+}
+
+func (c *Cluster) status() (*Status, error) {
+	var status Status
+	if err := getJSON(c.URL("grid-trading")+"/status", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// waitForState polls a symbol's only level until it reaches the wanted state
+func (c *Cluster) waitForState(symbol, state string, timeout time.Duration) (*Level, error) {
+	deadline := time.Now().Add(timeout)
+	last := "unknown"
+	for time.Now().Before(deadline) {
+		levels, err := c.levels(symbol)
+		if err == nil && len(levels) == 1 {
+			if levels[0].State == state {
+				return &levels[0], nil
+			}
+			last = levels[0].State
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	return nil, fmt.Errorf("%s level did not reach %s within %s (last state: %s)", symbol, state, timeout, last)
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Services in start order - each one only talks to those started before it or
+// retries until the others are up
+var serviceNames = []string{"mock-exchange", "order-assurance", "grid-trading", "price-monitor"}
+
+// e2eAPIKey is the shared key grid-trading sends to order-assurance in the cluster
+const e2eAPIKey = "e2e-assurance-key"
+
+// e2eGridKey is the shared key order-assurance, price-monitor and the harness send to grid-trading
+const e2eGridKey = "e2e-grid-key"
+
+// process is one service binary running as a child of the harness
+type process struct {
+	name    string
+	bin     string
+	env     []string
+	port    int
+	logPath string
+
+	cmd  *exec.Cmd
+	done chan struct{}
+}
+
+// Cluster runs the whole bot against the mock exchange, each service with its own
+// port, database and log file under a temp directory
+type Cluster struct {
+	root      string // repo root - services resolve their migrations relative to it
+	dir       string
+	processes map[string]*process
+}
+
+func NewCluster(root, dir string) (*Cluster, error) {
+	ports := make(map[string]int, len(serviceNames))
+	for _, name := range serviceNames {
+		port, err := freePort()
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve port for %s: %w", name, err)
+		}
+		ports[name] = port
+	}
+
+	url := func(name string) string {
+		return fmt.Sprintf("http://localhost:%d", ports[name])
+	}
+
+	env := map[string][]string{
+		"mock-exchange": {
+			"MOCK_PRICE_PATH=" + mockPricePath,
+			"MOCK_TICK_MS=3600000", // Scenarios set prices themselves
+			"MOCK_BALANCES=USDT:100000",
+			// Fine enough that selling the net held amount never rounds above the balance
+			"MOCK_STEP_SIZE=0.0000001",
+		},
+		"order-assurance": {
+			"DB_PATH=" + filepath.Join(dir, "order_assurance.db"),
+			"BINANCE_API_KEY=e2e",
+			"BINANCE_API_SECRET=e2e",
+			"BINANCE_API_URL=" + url("mock-exchange"),
+			"GRID_TRADING_URL=" + url("grid-trading"),
+			"OUTBOX_RETRY_INTERVAL_SEC=1",
+			"ORDER_ASSURANCE_API_KEY=" + e2eAPIKey,
+			"GRID_TRADING_API_KEY=" + e2eGridKey,
+		},
+		"grid-trading": {
+			"DB_PATH=" + filepath.Join(dir, "grid_trading.db"),
+			"ORDER_ASSURANCE_URL=" + url("order-assurance"),
+			"ORDER_ASSURANCE_API_KEY=" + e2eAPIKey,
+			"GRID_TRADING_API_KEY=" + e2eGridKey,
+		},
+		"price-monitor": {
+			"GRID_TRADING_URL=" + url("grid-trading"),
+			"GRID_TRADING_API_KEY=" + e2eGridKey,
+			"BINANCE_API_URL=" + url("mock-exchange"),
+			"PRICE_CHECK_INTERVAL_MS=300",
+			"MIN_PRICE_CHANGE_PCT=0", // Trigger on every check so fills are picked up promptly
+		},
+	}
+
+	c := &Cluster{root: root, dir: dir, processes: make(map[string]*process, len(serviceNames))}
+	for _, name := range serviceNames {
+		c.processes[name] = &process{
+			name:    name,
+			bin:     filepath.Join(dir, "bin", name),
+			env:     append([]string{"SERVER_PORT=" + strconv.Itoa(ports[name])}, env[name]...),
+			port:    ports[name],
+			logPath: filepath.Join(dir, name+".log"),
+		}
+	}
+	return c, nil
+}
+
+// Build compiles every service into the cluster directory
+func (c *Cluster) Build() error {
+	for _, name := range serviceNames {
+		log.Printf("INFO: Building %s", name)
+		cmd := exec.Command("go", "build", "-o", c.processes[name].bin, "./services/"+name+"/cmd")
+		cmd.Dir = c.root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to build %s: %w\n%s", name, err, out)
+		}
+	}
+	return nil
+}
+
+// Start launches every service and waits until each reports healthy
+func (c *Cluster) Start() error {
+	for _, name := range serviceNames {
+		if err := c.StartService(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartService (re)starts one service against its existing database
+func (c *Cluster) StartService(name string) error {
+	p := c.processes[name]
+
+	logFile, err := os.OpenFile(p.logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log for %s: %w", name, err)
+	}
+
+	p.cmd = exec.Command(p.bin)
+	p.cmd.Dir = c.root
+	p.cmd.Env = append(os.Environ(), p.env...)
+	p.cmd.Stdout = logFile
+	p.cmd.Stderr = logFile
+	if err := p.cmd.Start(); err != nil {
+		logFile.Close()
+		return fmt.Errorf("failed to start %s: %w", name, err)
+	}
+
+	p.done = make(chan struct{})
+	go func(cmd *exec.Cmd, done chan struct{}) {
+		cmd.Wait()
+		logFile.Close()
+		close(done)
+	}(p.cmd, p.done)
+
+	if err := c.waitHealthy(p); err != nil {
+		return err
+	}
+	log.Printf("INFO: %s running on port %d (pid %d)", name, p.port, p.cmd.Process.Pid)
+	return nil
+}
+
+// Kill ends a service with SIGKILL - no shutdown hooks run, as in a crash
+func (c *Cluster) Kill(name string) error {
+	p := c.processes[name]
+	if p.cmd == nil {
+		return nil
+	}
+	log.Printf("INFO: Killing %s (pid %d)", name, p.cmd.Process.Pid)
+	if err := p.cmd.Process.Signal(syscall.SIGKILL); err != nil {
+		return fmt.Errorf("failed to kill %s: %w", name, err)
+	}
+	<-p.done
+	p.cmd = nil
+	return nil
+}
+
+// Stop shuts every service down gracefully, killing any that hang
+func (c *Cluster) Stop() {
+	for i := len(serviceNames) - 1; i >= 0; i-- {
+		p := c.processes[serviceNames[i]]
+		if p.cmd == nil {
+			continue
+		}
+		p.cmd.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-p.done:
+		case <-time.After(10 * time.Second):
+			log.Printf("WARNING: %s did not stop in time, killing it", p.name)
+			p.cmd.Process.Kill()
+			<-p.done
+		}
+		p.cmd = nil
+	}
+}
+
+// URL returns the base URL of a service
+func (c *Cluster) URL(name string) string {
+	return fmt.Sprintf("http://localhost:%d", c.processes[name].port)
+}
+
+// LogPaths lists where each service writes its output
+func (c *Cluster) LogPaths() []string {
+	paths := make([]string, 0, len(serviceNames))
+	for _, name := range serviceNames {
+		paths = append(paths, c.processes[name].logPath)
+	}
+	return paths
+}
+
+func (c *Cluster) waitHealthy(p *process) error {
+	url := c.URL(p.name) + "/health"
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case <-p.done:
+			return fmt.Errorf("%s exited during startup - see %s", p.name, p.logPath)
+		default:
+		}
+
+		if resp, err := http.Get(url); err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return fmt.Errorf("%s not healthy after 30s - see %s", p.name, p.logPath)
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,96 @@
+// Command e2e runs grid-trading, order-assurance and price-monitor against the
+// mock exchange and checks whole trading cycles end to end.
+//
+// Run from the repo root with `make e2e` or `go run ./e2e`.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func main() {
+	only := flag.String("run", "", "Comma-separated scenario names to run (default all)")
+	keep := flag.Bool("keep", false, "Keep the work directory (databases, logs) after a passing run")
+	flag.Parse()
+
+	root, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("Failed to get working directory: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "go.mod")); err != nil {
+		log.Fatalf("Run e2e from the repo root - services load their migrations from there")
+	}
+
+	dir, err := os.MkdirTemp("", "grid-e2e-")
+	if err != nil {
+		log.Fatalf("Failed to create work directory: %v", err)
+	}
+
+	cluster, err := NewCluster(root, dir)
+	if err != nil {
+		log.Fatalf("Failed to set up cluster: %v", err)
+	}
+	if err := cluster.Build(); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if err := cluster.Start(); err != nil {
+		cluster.Stop()
+		log.Fatalf("Failed to start cluster: %v (work directory: %s)", err, dir)
+	}
+
+	failed := 0
+	for _, sc := range selected(*only) {
+		log.Printf("INFO: === RUN %s", sc.name)
+		start := time.Now()
+		if err := sc.run(cluster); err != nil {
+			failed++
+			log.Printf("ERROR: --- FAIL %s (%s): %v", sc.name, time.Since(start).Round(time.Millisecond), err)
+		} else {
+			log.Printf("SUCCESS: --- PASS %s (%s)", sc.name, time.Since(start).Round(time.Millisecond))
+		}
+	}
+
+	cluster.Stop()
+
+	if failed > 0 {
+		log.Printf("ERROR: %d scenario(s) failed - service logs:", failed)
+		for _, path := range cluster.LogPaths() {
+			log.Printf("  %s", path)
+		}
+		os.Exit(1)
+	}
+
+	if *keep {
+		log.Printf("INFO: Work directory kept at %s", dir)
+	} else {
+		os.RemoveAll(dir)
+	}
+	log.Printf("SUCCESS: All scenarios passed")
+}
+
+func selected(only string) []scenario {
+	if only == "" {
+		return scenarios
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(only, ",") {
+		wanted[strings.TrimSpace(name)] = true
+	}
+
+	var result []scenario
+	for _, sc := range scenarios {
+		if wanted[sc.name] {
+			result = append(result, sc)
+		}
+	}
+	if len(result) == 0 {
+		log.Fatalf("No scenario matches %q", only)
+	}
+	return result
+}
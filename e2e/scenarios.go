@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/shopspring/decimal"
+)
+
+// Each scenario trades its own symbol so they don't see each other's levels
+const mockPricePath = "ETHUSDT:2995;BNBUSDT:595;BTCUSDT:59950"
+
+// Buy amounts are the buy price times a round quantity, so what's held after the
+// mock's 0.1% commission in the bought coin can be sold without rounding up
+
+// stepTimeout bounds every wait for the bot to react to a price move
+const stepTimeout = 20 * time.Second
+
+type scenario struct {
+	name string
+	run  func(c *Cluster) error
+}
+
+var scenarios = []scenario{
+	{"full-cycle", fullCycle},
+	{"duplicate-notifications", duplicateNotifications},
+	{"crash-recovery", crashRecovery},
+}
+
+// fullCycle buys on the way down and sells one step higher, booking a profit
+func fullCycle(c *Cluster) error {
+	before, err := c.status()
+	if err != nil {
+		return err
+	}
+
+	// One level: buy at 2990, sell at 3000
+	if err := c.createGrid("ETHUSDT", "2990", "3000", "10", "149.5"); err != nil {
+		return err
+	}
+	if _, err := c.waitForState("ETHUSDT", "BUY_ACTIVE", stepTimeout); err != nil {
+		return err
+	}
+
+	if err := c.setPrice("ETHUSDT", "2990"); err != nil {
+		return err
+	}
+	if _, err := c.waitForState("ETHUSDT", "SELL_ACTIVE", stepTimeout); err != nil {
+		return fmt.Errorf("buy fill not processed: %w", err)
+	}
+
+	if err := c.setPrice("ETHUSDT", "3000"); err != nil {
+		return err
+	}
+	if _, err := c.waitForState("ETHUSDT", "READY", stepTimeout); err != nil {
+		return fmt.Errorf("sell fill not processed: %w", err)
+	}
+
+	after, err := c.status()
+	if err != nil {
+		return err
+	}
+	if after.BuysToday-before.BuysToday != 1 || after.SellsToday-before.SellsToday != 1 {
+		return fmt.Errorf("expected 1 buy and 1 sell, got %d buys and %d sells",
+			after.BuysToday-before.BuysToday, after.SellsToday-before.SellsToday)
+	}
+	if profit := after.ProfitAllTime.Sub(before.ProfitAllTime); !profit.IsPositive() {
+		return fmt.Errorf("expected a positive profit, got %s", profit)
+	}
+	return nil
+}
+
+// duplicateNotifications replays fill and placed notifications the way a retrying
+// outbox would, and checks none of them are applied twice
+func duplicateNotifications(c *Cluster) error {
+	if err := c.createGrid("BNBUSDT", "590", "600", "10", "59"); err != nil {
+		return err
+	}
+	level, err := c.waitForState("BNBUSDT", "BUY_ACTIVE", stepTimeout)
+	if err != nil {
+		return err
+	}
+	buyOrderID := level.BuyOrderID.String
+
+	if err := c.setPrice("BNBUSDT", "590"); err != nil {
+		return err
+	}
+	level, err = c.waitForState("BNBUSDT", "SELL_ACTIVE", stepTimeout)
+	if err != nil {
+		return fmt.Errorf("buy fill not processed: %w", err)
+	}
+	sellOrderID := level.SellOrderID.String
+
+	before, err := c.status()
+	if err != nil {
+		return err
+	}
+
+	buyFill := contracts.FillNotification{
+		OrderID:      buyOrderID,
+		Symbol:       "BNB",
+		Price:        decimal.NewFromInt(590),
+		Side:         string(contracts.SideBuy),
+		Status:       contracts.StatusFilled,
+		FilledAmount: decimal.RequireFromString("0.1"),
+		FillPrice:    decimal.NewFromInt(590),
+	}
+	buyPlaced := buyFill
+	buyPlaced.Status = contracts.StatusPlaced
+	buyPlaced.Symbol = "BNBUSDT"
+	for _, notification := range []contracts.FillNotification{buyFill, buyFill, buyPlaced} {
+		if err := c.sendFillNotification(notification); err != nil {
+			return err
+		}
+	}
+
+	level, err = c.waitForState("BNBUSDT", "SELL_ACTIVE", stepTimeout)
+	if err != nil {
+		return err
+	}
+	if level.SellOrderID.String != sellOrderID {
+		return fmt.Errorf("replayed buy notification replaced sell order %s with %s", sellOrderID, level.SellOrderID.String)
+	}
+
+	if err := c.setPrice("BNBUSDT", "600"); err != nil {
+		return err
+	}
+	if _, err := c.waitForState("BNBUSDT", "READY", stepTimeout); err != nil {
+		return fmt.Errorf("sell fill not processed: %w", err)
+	}
+	afterSell, err := c.status()
+	if err != nil {
+		return err
+	}
+
+	sellFill := buyFill
+	sellFill.OrderID = sellOrderID
+	sellFill.Side = string(contracts.SideSell)
+	sellFill.Price = decimal.NewFromInt(600)
+	sellFill.FillPrice = decimal.NewFromInt(600)
+	for i := 0; i < 2; i++ {
+		if err := c.sendFillNotification(sellFill); err != nil {
+			return err
+		}
+	}
+
+	after, err := c.status()
+	if err != nil {
+		return err
+	}
+	if after.BuysToday != before.BuysToday {
+		return fmt.Errorf("replayed buy fills changed buys today from %d to %d", before.BuysToday, after.BuysToday)
+	}
+	if after.SellsToday != afterSell.SellsToday || !after.ProfitAllTime.Equal(afterSell.ProfitAllTime) {
+		return fmt.Errorf("replayed sell fills changed sells/profit from %d/%s to %d/%s",
+			afterSell.SellsToday, afterSell.ProfitAllTime, after.SellsToday, after.ProfitAllTime)
+	}
+	if _, err := c.waitForState("BNBUSDT", "READY", time.Second); err != nil {
+		return err
+	}
+	return nil
+}
+
+// crashRecovery kills each stateful service while its order fills on the exchange
+// and checks the fill is picked up after the restart
+func crashRecovery(c *Cluster) error {
+	if err := c.createGrid("BTCUSDT", "59900", "60000", "100", "59.9"); err != nil {
+		return err
+	}
+	if _, err := c.waitForState("BTCUSDT", "BUY_ACTIVE", stepTimeout); err != nil {
+		return err
+	}
+
+	// Buy fills while order-assurance is down
+	if err := c.Kill("order-assurance"); err != nil {
+		return err
+	}
+	if err := c.setPrice("BTCUSDT", "59900"); err != nil {
+		return err
+	}
+	time.Sleep(time.Second)
+	if err := c.StartService("order-assurance"); err != nil {
+		return err
+	}
+	if _, err := c.waitForState("BTCUSDT", "SELL_ACTIVE", stepTimeout); err != nil {
+		return fmt.Errorf("buy fill not recovered after order-assurance restart: %w", err)
+	}
+
+	// Sell fills while grid-trading is down
+	if err := c.Kill("grid-trading"); err != nil {
+		return err
+	}
+	if err := c.setPrice("BTCUSDT", "60000"); err != nil {
+		return err
+	}
+	time.Sleep(time.Second)
+	if err := c.StartService("grid-trading"); err != nil {
+		return err
+	}
+	if _, err := c.waitForState("BTCUSDT", "READY", stepTimeout); err != nil {
+		return fmt.Errorf("sell fill not recovered after grid-trading restart: %w", err)
+	}
+	return nil
+}
+
+func (c *Cluster) sendFillNotification(notification contracts.FillNotification) error {
+	if err := postJSON(c.URL("grid-trading")+"/order-fill-notification", notification, nil); err != nil {
+		return fmt.Errorf("replaying %s %s notification for order %s: %w",
+			notification.Side, notification.Status, notification.OrderID, err)
+	}
+	log.Printf("INFO: Replayed %s %s notification for order %s", notification.Side, notification.Status, notification.OrderID)
+	return nil
+}
@@ -0,0 +1,18 @@
+package contracts
+
+import "github.com/shopspring/decimal"
+
+// BalancesResponse is a snapshot of an account's free spot balances (GET /balances?account=)
+type BalancesResponse struct {
+	Account  string                     `json:"account,omitempty"` // Sub-account (empty = master account)
+	Balances map[string]decimal.Decimal `json:"balances"`          // Asset → free (unlocked) amount, zero balances omitted
+}
+
+// SymbolAssets names the assets of a symbol from the exchange's trading rules (GET /symbols/{symbol}?account=)
+type SymbolAssets struct {
+	Symbol     string          `json:"symbol"`
+	BaseAsset  string          `json:"base_asset"`  // Traded coin, e.g. ETH
+	QuoteAsset string          `json:"quote_asset"` // Pricing asset, e.g. USDT
+	StepSize   decimal.Decimal `json:"step_size"`   // LOT_SIZE quantity step (0 = unknown)
+	TickSize   decimal.Decimal `json:"tick_size"`   // PRICE_FILTER price tick (0 = unknown)
+}
@@ -0,0 +1,10 @@
+// Package contracts holds the JSON payloads exchanged between the services, so
+// both sides of every call encode and decode the same types.
+//
+// Changes within a version must be additive: new fields are optional (omitempty or
+// zero-value safe) and existing fields keep their JSON names and meaning. Anything
+// else needs a new Version and a transition period where both are accepted.
+package contracts
+
+// Version of the payloads in this package
+const Version = 1
@@ -0,0 +1,433 @@
+package contracts
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// roundTrip encodes v, decodes it into a fresh T and encodes that again. Both encodings must be
+// identical, so every field survives the trip; the decoded value is returned for field checks.
+func roundTrip[T any](t *testing.T, v T) (T, map[string]json.RawMessage) {
+	t.Helper()
+
+	first, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal %T: %v", v, err)
+	}
+
+	var decoded T
+	if err := json.Unmarshal(first, &decoded); err != nil {
+		t.Fatalf("unmarshal %T from %s: %v", v, first, err)
+	}
+
+	second, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("re-marshal %T: %v", v, err)
+	}
+	if string(first) != string(second) {
+		t.Fatalf("%T changed in a round trip:\n first: %s\nsecond: %s", v, first, second)
+	}
+
+	var fields map[string]json.RawMessage
+	if strings.HasPrefix(string(first), "{") {
+		if err := json.Unmarshal(first, &fields); err != nil {
+			t.Fatalf("decode %T fields: %v", v, err)
+		}
+	}
+	return decoded, fields
+}
+
+// requireKeys fails unless the encoded payload has exactly these JSON keys
+func requireKeys(t *testing.T, fields map[string]json.RawMessage, keys ...string) {
+	t.Helper()
+
+	want := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		want[key] = true
+		if _, ok := fields[key]; !ok {
+			t.Errorf("missing key %q", key)
+		}
+	}
+	for key := range fields {
+		if !want[key] {
+			t.Errorf("unexpected key %q", key)
+		}
+	}
+}
+
+func requireDecimal(t *testing.T, name string, got, want decimal.Decimal) {
+	t.Helper()
+	if !got.Equal(want) {
+		t.Errorf("%s = %s, want %s", name, got, want)
+	}
+}
+
+func requireTime(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if !got.Equal(want) {
+		t.Errorf("%s = %s, want %s", name, got, want)
+	}
+}
+
+var (
+	testTime = time.Date(2024, 6, 10, 6, 13, 20, 123000000, time.UTC)
+	// Decimals with more precision than a float64 holds, and trailing zeros
+	testPrice  = decimal.RequireFromString("3753.123456789012345678")
+	testAmount = decimal.RequireFromString("0.29400000")
+)
+
+func TestPriceTriggerRoundTrip(t *testing.T) {
+	exchangeTime := testTime
+	got, fields := roundTrip(t, PriceTrigger{
+		Symbol:       "ETHUSDT",
+		Price:        testPrice,
+		Sequence:     1718000000000000001,
+		ExchangeTime: &exchangeTime,
+		Source:       "secondary",
+		Feed:         FeedAggregated,
+		Exchange:     "binance+bybit",
+		Confidence:   0.5,
+	})
+	requireKeys(t, fields, "symbol", "price", "sequence", "exchange_time", "source", "feed", "exchange", "confidence")
+	requireDecimal(t, "price", got.Price, testPrice)
+	if got.Sequence != 1718000000000000001 {
+		t.Errorf("sequence = %d", got.Sequence)
+	}
+	if got.Feed != FeedAggregated || got.Exchange != "binance+bybit" || got.Confidence != 0.5 {
+		t.Errorf("trigger source decoded as %q %q %v", got.Feed, got.Exchange, got.Confidence)
+	}
+	if got.ExchangeTime == nil {
+		t.Fatal("exchange_time lost")
+	}
+	requireTime(t, "exchange_time", *got.ExchangeTime, testTime)
+}
+
+func TestPriceTriggerOmitsUnsetOrdering(t *testing.T) {
+	got, fields := roundTrip(t, PriceTrigger{Symbol: "ETHUSDT", Price: testPrice})
+	requireKeys(t, fields, "symbol", "price")
+	if got.ExchangeTime != nil || got.Sequence != 0 || got.Source != "" || got.Feed != "" || got.Confidence != 0 {
+		t.Errorf("unset ordering fields decoded as %+v", got)
+	}
+}
+
+func TestOrderRequestRoundTrip(t *testing.T) {
+	want := OrderRequest{
+		Symbol:     "ETHUSDT",
+		Price:      testPrice,
+		Side:       SideSell,
+		Amount:     testAmount,
+		TTLSeconds: 300,
+		Account:    "alt",
+		OrderType:  OrderTypeMarket,
+	}
+	got, fields := roundTrip(t, want)
+	requireKeys(t, fields, "symbol", "price", "side", "amount", "ttl_seconds", "account", "order_type")
+	requireDecimal(t, "price", got.Price, want.Price)
+	requireDecimal(t, "amount", got.Amount, want.Amount)
+	if got.Side != SideSell || got.TTLSeconds != 300 || got.Account != "alt" || got.OrderType != OrderTypeMarket {
+		t.Errorf("decoded %+v", got)
+	}
+
+	// OCO sells add the stop price
+	stop := decimal.RequireFromString("2850.5")
+	got, fields = roundTrip(t, OrderRequest{Symbol: "ETHUSDT", Price: testPrice, Side: SideSell, Amount: testAmount, StopPrice: &stop})
+	requireKeys(t, fields, "symbol", "price", "side", "amount", "stop_price")
+	if got.StopPrice == nil {
+		t.Fatalf("decoded %+v", got)
+	}
+	requireDecimal(t, "stop_price", *got.StopPrice, stop)
+}
+
+func TestOrderResponseRoundTrip(t *testing.T) {
+	got, fields := roundTrip(t, OrderResponse{OrderID: "12345", Status: "assured"})
+	requireKeys(t, fields, "order_id", "status")
+	if got != (OrderResponse{OrderID: "12345", Status: "assured"}) {
+		t.Errorf("decoded %+v", got)
+	}
+
+	oco := OrderResponse{OrderID: "12345", Status: "assured", StopOrderID: "12346"}
+	got, fields = roundTrip(t, oco)
+	requireKeys(t, fields, "order_id", "status", "stop_order_id")
+	if got != oco {
+		t.Errorf("decoded %+v", got)
+	}
+}
+
+func TestOrderStatusRoundTrip(t *testing.T) {
+	filled, commission := testAmount, decimal.RequireFromString("0.000294")
+	got, fields := roundTrip(t, OrderStatus{
+		OrderID:         "12345",
+		Status:          StatusFilled,
+		FilledAmount:    &filled,
+		FillPrice:       &testPrice,
+		Commission:      &commission,
+		CommissionAsset: "ETH",
+		BaseAsset:       "ETH",
+	})
+	requireKeys(t, fields, "order_id", "status", "filled_amount", "fill_price", "commission", "commission_asset", "base_asset")
+	if got.FilledAmount == nil || got.FillPrice == nil || got.Commission == nil {
+		t.Fatalf("fill details lost: %+v", got)
+	}
+	requireDecimal(t, "filled_amount", *got.FilledAmount, filled)
+	requireDecimal(t, "fill_price", *got.FillPrice, testPrice)
+	requireDecimal(t, "commission", got.CommissionAmount(), commission)
+
+	// A cancelled order carries the part that filled before the cancel
+	partial := decimal.RequireFromString("0.1")
+	cancelled, fields := roundTrip(t, OrderStatus{OrderID: "12345", Status: StatusCancelled, FilledAmount: &partial, FillPrice: &testPrice})
+	requireKeys(t, fields, "order_id", "status", "filled_amount", "fill_price")
+	if cancelled.FilledAmount == nil {
+		t.Fatalf("part fill lost: %+v", cancelled)
+	}
+	requireDecimal(t, "filled_amount", *cancelled.FilledAmount, partial)
+
+	open, fields := roundTrip(t, OrderStatus{OrderID: "12345", Status: StatusOpen})
+	requireKeys(t, fields, "order_id", "status")
+	if !open.CommissionAmount().IsZero() {
+		t.Errorf("commission of an open order = %s", open.CommissionAmount())
+	}
+}
+
+func TestBatchOrderStatusRoundTrip(t *testing.T) {
+	request, fields := roundTrip(t, BatchOrderStatusRequest{Orders: []OrderStatusQuery{
+		{Symbol: "ETHUSDT", OrderID: "1"},
+		{Symbol: "BTCUSDT", OrderID: "2", Account: "alt"},
+	}})
+	requireKeys(t, fields, "orders")
+	if len(request.Orders) != 2 || request.Orders[1].Account != "alt" {
+		t.Errorf("decoded %+v", request)
+	}
+
+	filled := testAmount
+	response, _ := roundTrip(t, BatchOrderStatusResponse{Orders: []BatchOrderStatus{
+		{Symbol: "ETHUSDT", OrderStatus: OrderStatus{OrderID: "1", Status: StatusFilled, FilledAmount: &filled, FillPrice: &testPrice}},
+		{Symbol: "BTCUSDT", Account: "alt", OrderStatus: OrderStatus{OrderID: "2", Status: StatusError}, Error: "timeout"},
+	}})
+	if len(response.Orders) != 2 {
+		t.Fatalf("decoded %d orders", len(response.Orders))
+	}
+	requireDecimal(t, "filled_amount", *response.Orders[0].FilledAmount, filled)
+	if response.Orders[1].Error != "timeout" || response.Orders[1].Account != "alt" {
+		t.Errorf("decoded %+v", response.Orders[1])
+	}
+
+	// The embedded OrderStatus is flattened into the batch entry
+	_, fields = roundTrip(t, response.Orders[1])
+	requireKeys(t, fields, "symbol", "account", "order_id", "status", "error")
+}
+
+func TestOpenOrdersRoundTrip(t *testing.T) {
+	got, fields := roundTrip(t, OpenOrdersResponse{Symbol: "ETHUSDT", Account: "alt", Orders: []OpenOrder{
+		{OrderID: "1", Side: SideSell, Price: testPrice, Quantity: testAmount, ExecutedQty: decimal.Zero, CreatedAt: testTime},
+	}})
+	requireKeys(t, fields, "symbol", "account", "orders")
+	if len(got.Orders) != 1 || got.Orders[0].OrderID != "1" || got.Orders[0].Side != SideSell {
+		t.Fatalf("decoded %+v", got)
+	}
+	requireDecimal(t, "price", got.Orders[0].Price, testPrice)
+	requireDecimal(t, "quantity", got.Orders[0].Quantity, testAmount)
+	requireTime(t, "created_at", got.Orders[0].CreatedAt, testTime)
+
+	_, fields = roundTrip(t, got.Orders[0])
+	requireKeys(t, fields, "order_id", "side", "price", "quantity", "executed_qty", "created_at")
+}
+
+func TestFillNotificationRoundTrip(t *testing.T) {
+	want := FillNotification{
+		OrderID:         "12345",
+		Symbol:          "ETHUSDT",
+		Price:           testPrice,
+		Side:            "buy",
+		Status:          StatusFilled,
+		FilledAmount:    testAmount,
+		FillPrice:       testPrice,
+		Account:         "alt",
+		Commission:      decimal.RequireFromString("0.000294"),
+		CommissionAsset: "ETH",
+		BaseAsset:       "ETH",
+	}
+	got, fields := roundTrip(t, want)
+	requireKeys(t, fields, "order_id", "symbol", "price", "side", "status", "filled_amount", "fill_price",
+		"account", "commission", "commission_asset", "base_asset")
+	requireDecimal(t, "price", got.Price, want.Price)
+	requireDecimal(t, "filled_amount", got.FilledAmount, want.FilledAmount)
+	requireDecimal(t, "fill_price", got.FillPrice, want.FillPrice)
+	requireDecimal(t, "commission", got.Commission, want.Commission)
+}
+
+func TestErrorNotificationRoundTrip(t *testing.T) {
+	want := ErrorNotification{
+		Symbol:    "ETHUSDT",
+		Side:      "buy",
+		Price:     testPrice,
+		ErrorCode: "insufficient_balance",
+		Error:     "Account has insufficient balance",
+		Account:   "alt",
+	}
+	got, fields := roundTrip(t, want)
+	// order_id is always sent, empty for rejections before placement
+	requireKeys(t, fields, "order_id", "symbol", "side", "price", "error_code", "error", "account")
+	requireDecimal(t, "price", got.Price, want.Price)
+	if got.OrderID != "" || got.ErrorCode != want.ErrorCode {
+		t.Errorf("decoded %+v", got)
+	}
+}
+
+func TestExchangeStatusRoundTrip(t *testing.T) {
+	want := ExchangeStatus{Degraded: true, Reason: "system maintenance", CheckedAt: testTime, ValidForSec: 90}
+	got, fields := roundTrip(t, want)
+	requireKeys(t, fields, "degraded", "reason", "checked_at", "valid_for_sec")
+	requireTime(t, "checked_at", got.CheckedAt, testTime)
+	if !got.Degraded || got.ValidForSec != 90 {
+		t.Errorf("decoded %+v", got)
+	}
+}
+
+func TestEventRoundTrip(t *testing.T) {
+	want := Event{
+		ID:         "evt-1",
+		Type:       EventSellFilled,
+		Service:    "grid-trading",
+		Symbol:     "ETHUSDT",
+		Message:    "Sold",
+		Fields:     map[string]string{"profit_usdt": "1.23"},
+		OccurredAt: testTime,
+	}
+	got, fields := roundTrip(t, want)
+	requireKeys(t, fields, "id", "type", "service", "symbol", "message", "fields", "occurred_at")
+	requireTime(t, "occurred_at", got.OccurredAt, testTime)
+	if !reflect.DeepEqual(got.Fields, want.Fields) {
+		t.Errorf("fields = %v", got.Fields)
+	}
+}
+
+func TestPriceQuoteRoundTrip(t *testing.T) {
+	got, fields := roundTrip(t, PriceQuote{Symbol: "ETHUSDT", Price: testPrice, UpdatedAt: testTime})
+	requireKeys(t, fields, "symbol", "price", "updated_at")
+	requireDecimal(t, "price", got.Price, testPrice)
+	requireTime(t, "updated_at", got.UpdatedAt, testTime)
+}
+
+func TestBookTickerRoundTrip(t *testing.T) {
+	bid, ask := decimal.RequireFromString("2990"), decimal.RequireFromString("3010")
+	want := BookTicker{Symbol: "ETHUSDT", BidPrice: bid, BidQty: testAmount, AskPrice: ask, AskQty: testAmount, Source: "websocket"}
+	want.SpreadPct = want.Spread().Round(4)
+	got, fields := roundTrip(t, want)
+	requireKeys(t, fields, "symbol", "bid_price", "bid_qty", "ask_price", "ask_qty", "spread_pct", "source")
+	requireDecimal(t, "bid_price", got.BidPrice, bid)
+	requireDecimal(t, "ask_price", got.AskPrice, ask)
+	requireDecimal(t, "spread_pct", got.SpreadPct, decimal.RequireFromString("0.6667"))
+	requireDecimal(t, "empty side spread", BookTicker{AskPrice: ask}.Spread(), decimal.Zero)
+}
+
+func TestBalancesRoundTrip(t *testing.T) {
+	want := BalancesResponse{Account: "alt", Balances: map[string]decimal.Decimal{"USDT": testPrice, "ETH": testAmount}}
+	got, fields := roundTrip(t, want)
+	requireKeys(t, fields, "account", "balances")
+	requireDecimal(t, "USDT", got.Balances["USDT"], testPrice)
+	requireDecimal(t, "ETH", got.Balances["ETH"], testAmount)
+
+	step, tick := decimal.RequireFromString("0.00010000"), decimal.RequireFromString("0.01000000")
+	assets, fields := roundTrip(t, SymbolAssets{Symbol: "ETHUSDT", BaseAsset: "ETH", QuoteAsset: "USDT", StepSize: step, TickSize: tick})
+	requireKeys(t, fields, "symbol", "base_asset", "quote_asset", "step_size", "tick_size")
+	requireDecimal(t, "step_size", assets.StepSize, step)
+	requireDecimal(t, "tick_size", assets.TickSize, tick)
+	if assets.Symbol != "ETHUSDT" || assets.BaseAsset != "ETH" || assets.QuoteAsset != "USDT" {
+		t.Errorf("decoded %+v", assets)
+	}
+}
+
+func TestSweepRoundTrip(t *testing.T) {
+	request, fields := roundTrip(t, SweepRequest{Account: "alt", Asset: "USDT", Amount: testPrice, Destination: SweepToEarn, DryRun: true})
+	requireKeys(t, fields, "account", "asset", "amount", "destination", "dry_run")
+	requireDecimal(t, "amount", request.Amount, testPrice)
+
+	result, fields := roundTrip(t, SweepResult{
+		Asset:       "USDT",
+		Amount:      testPrice,
+		Destination: SweepToWithdrawal,
+		Address:     "TXYZ",
+		FreeBalance: testAmount,
+		Reference:   "w-1",
+	})
+	requireKeys(t, fields, "asset", "amount", "destination", "address", "dry_run", "free_balance", "reference")
+	requireDecimal(t, "free_balance", result.FreeBalance, testAmount)
+}
+
+func TestFuturesRoundTrip(t *testing.T) {
+	positions, fields := roundTrip(t, PositionsResponse{Positions: []FuturesPosition{{
+		Symbol:           "ETHUSDT",
+		PositionSide:     "SHORT",
+		PositionAmt:      testAmount.Neg(),
+		EntryPrice:       testPrice,
+		MarkPrice:        testPrice,
+		UnrealizedProfit: decimal.RequireFromString("-1.5"),
+		Leverage:         3,
+	}}})
+	requireKeys(t, fields, "positions")
+	requireDecimal(t, "position_amt", positions.Positions[0].PositionAmt, testAmount.Neg())
+
+	fees, _ := roundTrip(t, FundingFeesResponse{FundingFees: []FundingFee{{
+		ID: "1", Symbol: "ETHUSDT", Asset: "USDT", Amount: decimal.RequireFromString("-0.01"), FundedAt: testTime,
+	}}})
+	requireTime(t, "funded_at", fees.FundingFees[0].FundedAt, testTime)
+	requireDecimal(t, "amount", fees.FundingFees[0].Amount, decimal.RequireFromString("-0.01"))
+}
+
+func TestMarginRoundTrip(t *testing.T) {
+	debts, fields := roundTrip(t, MarginDebtsResponse{Debts: []MarginDebt{{
+		Asset: "USDT", Free: testPrice, Borrowed: testAmount, Interest: decimal.RequireFromString("0.0001"), NetAsset: testPrice,
+	}}})
+	requireKeys(t, fields, "debts")
+	requireDecimal(t, "borrowed", debts.Debts[0].Borrowed, testAmount)
+
+	interest, _ := roundTrip(t, MarginInterestResponse{Interest: []MarginInterest{{
+		ID: "1", Symbol: "ETHUSDT", Asset: "USDT", Amount: testAmount, ChargedAt: testTime,
+	}}})
+	requireTime(t, "charged_at", interest.Interest[0].ChargedAt, testTime)
+}
+
+func TestTokenVerifyRoundTrip(t *testing.T) {
+	request, _ := roundTrip(t, TokenVerifyRequest{Token: "secret"})
+	if request.Token != "secret" {
+		t.Errorf("token = %q", request.Token)
+	}
+
+	expires := testTime
+	response, fields := roundTrip(t, TokenVerifyResponse{Valid: true, Name: "ci", Scopes: []string{ScopeRead, ScopeWrite}, ExpiresAt: &expires})
+	requireKeys(t, fields, "valid", "name", "scopes", "expires_at")
+	if response.ExpiresAt == nil {
+		t.Fatal("expires_at lost")
+	}
+	requireTime(t, "expires_at", *response.ExpiresAt, testTime)
+
+	_, fields = roundTrip(t, TokenVerifyResponse{})
+	requireKeys(t, fields, "valid")
+}
+
+func TestTransactionPageRoundTrip(t *testing.T) {
+	fee := decimal.RequireFromString("0.0375")
+	page, fields := roundTrip(t, TransactionPage{NextAfterID: 42, Transactions: []TransactionRecord{{
+		ID: 42, GridLevelID: 7, Account: "alt", Symbol: "ETHUSDT",
+		LevelBuyPrice: testPrice, LevelSellPrice: testPrice, Side: "SELL", Status: "FILLED",
+		OrderID: "12345", TargetPrice: testPrice, ExecutedPrice: &testPrice, AmountCoin: &testAmount,
+		ProfitUSDT: &fee, FeeUSDT: &fee, CommissionAsset: "USDT", CreatedAt: testTime,
+	}}})
+	requireKeys(t, fields, "transactions", "next_after_id")
+	got := page.Transactions[0]
+	requireDecimal(t, "level_buy_price", got.LevelBuyPrice, testPrice)
+	requireTime(t, "created_at", got.CreatedAt, testTime)
+	if got.ExecutedPrice == nil || got.AmountUSDT != nil {
+		t.Errorf("decoded %+v", got)
+	}
+
+	// Errors carry no amounts
+	_, fields = roundTrip(t, TransactionRecord{ID: 1, Symbol: "ETHUSDT", Side: "BUY", Status: "ERROR", ErrorCode: "insufficient_balance", CreatedAt: testTime})
+	requireKeys(t, fields, "id", "grid_level_id", "symbol", "level_buy_price", "level_sell_price", "side", "status",
+		"target_price", "error_code", "created_at")
+}
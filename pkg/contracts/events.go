@@ -0,0 +1,38 @@
+package contracts
+
+import "time"
+
+// Event types grid-trading reports to the notifier
+const (
+	EventBuyFilled        = "buy_filled"
+	EventSellFilled       = "sell_filled"
+	EventOrderFailed      = "order_failed"      // Level moved to ERROR
+	EventTradingPaused    = "trading_paused"    // Exchange circuit breaker open
+	EventDrawdownExceeded = "drawdown_exceeded" // New buys paused by MAX_DRAWDOWN_PCT
+	EventQuoteDepegged    = "quote_depegged"    // New buys paused by DEPEG_THRESHOLD_PCT
+	EventExchangeDegraded = "exchange_degraded" // Maintenance or repeated exchange errors, triggering paused
+	EventPlacementStuck   = "placement_stuck"   // Level locked in PLACING_* without an order ID (PLACING_WATCHDOG_SEC)
+	EventReconciliation   = "reconciliation"    // Levels and exchange disagree (RECONCILIATION_CRON)
+	EventSafeMode         = "safe_mode"         // Started in safe mode, waiting for an operator (STARTUP_SAFE_MODE)
+	EventGridInconsistent = "grid_inconsistent" // Levels break a grid invariant (startup consistency check)
+	EventGridTrailed      = "grid_trailed"      // Trailing grid moved a level from one edge of its band to the other
+	EventCalendarPause    = "calendar_pause"    // Buys paused around a trading calendar event
+	EventCalendarResume   = "calendar_resume"   // Buys resumed after a trading calendar event
+	EventSummary          = "summary"           // Periodic report (SUMMARY_CRON)
+	EventDailyReport      = "daily_report"      // End-of-day report (DAILY_REPORT_CRON)
+	EventWeeklyDigest     = "weekly_digest"     // Seven-day performance digest (WEEKLY_DIGEST_CRON)
+	EventLevelState       = "level_state"       // Level state transition - sent to webhooks only
+)
+
+// Event is something worth telling a human about (POST /events on the notifier,
+// or EventSubjectPrefix + type on the event bus). Fields carry the event-specific
+// values templates can use, e.g. {{.Fields.profit_usdt}}.
+type Event struct {
+	ID         string            `json:"id"` // Unique per event - redeliveries with the same ID are ignored
+	Type       string            `json:"type"`
+	Service    string            `json:"service"`
+	Symbol     string            `json:"symbol,omitempty"`
+	Message    string            `json:"message"` // Plain-text summary, used when no template matches the type
+	Fields     map[string]string `json:"fields,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
@@ -0,0 +1,14 @@
+package contracts
+
+import "time"
+
+// ExchangeStatus is order-assurance's view of whether Binance is trading normally
+// (POST /exchange-status on grid-trading, or ExchangeStatusSubject). A degraded status
+// is re-sent on every check and lapses after ValidForSec, so a lost recovery
+// broadcast can't pause grid-trading for good.
+type ExchangeStatus struct {
+	Degraded    bool      `json:"degraded"`
+	Reason      string    `json:"reason,omitempty"` // e.g. "system maintenance", "orders circuit open"
+	CheckedAt   time.Time `json:"checked_at"`
+	ValidForSec int       `json:"valid_for_sec"`
+}
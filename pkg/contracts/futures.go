@@ -0,0 +1,41 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FuturesAccount is the order-assurance account that trades USDT-M perpetuals
+// (FUTURES_ENABLED=true). Grids created with this account run on futures.
+const FuturesAccount = "futures"
+
+// FuturesPosition is an open USDT-M position (GET /positions?account=futures)
+type FuturesPosition struct {
+	Symbol           string          `json:"symbol"`
+	PositionSide     string          `json:"position_side"` // BOTH (one-way mode) or LONG/SHORT (hedge mode)
+	PositionAmt      decimal.Decimal `json:"position_amt"`  // Coins, negative for shorts
+	EntryPrice       decimal.Decimal `json:"entry_price"`
+	MarkPrice        decimal.Decimal `json:"mark_price"`
+	UnrealizedProfit decimal.Decimal `json:"unrealized_profit"`
+	Leverage         int             `json:"leverage"`
+}
+
+// FundingFee is one funding payment, negative when paid (GET /funding-fees?account=futures&since=)
+type FundingFee struct {
+	ID       string          `json:"id"` // Binance tranId, unique per payment
+	Symbol   string          `json:"symbol"`
+	Asset    string          `json:"asset"`
+	Amount   decimal.Decimal `json:"amount"`
+	FundedAt time.Time       `json:"funded_at"`
+}
+
+// PositionsResponse lists an account's open positions
+type PositionsResponse struct {
+	Positions []FuturesPosition `json:"positions"`
+}
+
+// FundingFeesResponse lists an account's funding payments, oldest first
+type FundingFeesResponse struct {
+	FundingFees []FundingFee `json:"funding_fees"`
+}
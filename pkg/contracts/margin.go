@@ -0,0 +1,40 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MarginAccount is the order-assurance account that trades on cross or isolated margin
+// (MARGIN_ENABLED=true). Grids created with this account run on margin.
+const MarginAccount = "margin"
+
+// MarginDebt is one asset of the margin account (GET /margin/debts?account=margin)
+type MarginDebt struct {
+	Symbol   string          `json:"symbol,omitempty"` // Isolated pair (empty = cross margin)
+	Asset    string          `json:"asset"`
+	Free     decimal.Decimal `json:"free"`
+	Borrowed decimal.Decimal `json:"borrowed"`
+	Interest decimal.Decimal `json:"interest"`  // Accrued, not yet repaid
+	NetAsset decimal.Decimal `json:"net_asset"` // Free + locked - borrowed - interest
+}
+
+// MarginInterest is one interest charge (GET /margin/interest?account=margin&since=)
+type MarginInterest struct {
+	ID        string          `json:"id"` // Binance txId, unique per charge
+	Symbol    string          `json:"symbol,omitempty"`
+	Asset     string          `json:"asset"`
+	Amount    decimal.Decimal `json:"amount"`
+	ChargedAt time.Time       `json:"charged_at"`
+}
+
+// MarginDebtsResponse lists the margin account's borrowed assets
+type MarginDebtsResponse struct {
+	Debts []MarginDebt `json:"debts"`
+}
+
+// MarginInterestResponse lists interest charges, oldest first
+type MarginInterestResponse struct {
+	Interest []MarginInterest `json:"interest"`
+}
@@ -0,0 +1,56 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FillNotification is sent by order-assurance when an order fills, is cancelled,
+// or was placed without grid-trading getting the response (POST /order-fill-notification)
+type FillNotification struct {
+	OrderID      string          `json:"order_id"`
+	Symbol       string          `json:"symbol"`
+	Price        decimal.Decimal `json:"price"`
+	Side         string          `json:"side"`
+	Status       string          `json:"status"` // filled, cancelled, placed
+	FilledAmount decimal.Decimal `json:"filled_amount"`
+	FillPrice    decimal.Decimal `json:"fill_price"`
+	Account      string          `json:"account,omitempty"`
+
+	// Fee actually charged by Binance (asset empty if it could not be determined)
+	Commission      decimal.Decimal `json:"commission"`
+	CommissionAsset string          `json:"commission_asset,omitempty"`
+	BaseAsset       string          `json:"base_asset,omitempty"` // Traded coin from the exchange's symbol rules
+
+	Seq int64 `json:"seq,omitempty"` // Position in order-assurance's notification log (0 = not logged)
+}
+
+// ErrorNotification reports a failed order (POST /order-fill-error-notification).
+// OrderID is empty when the order was rejected before reaching the book.
+type ErrorNotification struct {
+	OrderID   string          `json:"order_id"`
+	Symbol    string          `json:"symbol"`
+	Side      string          `json:"side"`
+	Price     decimal.Decimal `json:"price"`
+	ErrorCode string          `json:"error_code"`
+	Error     string          `json:"error"`
+	Account   string          `json:"account,omitempty"`
+	Seq       int64           `json:"seq,omitempty"` // Shares the sequence of fill notifications
+}
+
+// ReplayedNotification is a logged fill or error notification; exactly one of Fill and Error is set
+type ReplayedNotification struct {
+	Seq       int64              `json:"seq"`
+	CreatedAt time.Time          `json:"created_at"`
+	Fill      *FillNotification  `json:"fill,omitempty"`
+	Error     *ErrorNotification `json:"error,omitempty"`
+}
+
+// NotificationReplay answers GET /notifications/replay?after_seq=&limit= on order-assurance,
+// oldest first. LastSeq is the newest sequence logged, so a caller knows where the log ends
+// (and that it was reset when LastSeq is below its cursor).
+type NotificationReplay struct {
+	Notifications []ReplayedNotification `json:"notifications"`
+	LastSeq       int64                  `json:"last_seq"`
+}
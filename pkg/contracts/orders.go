@@ -0,0 +1,132 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type OrderSide string
+
+const (
+	SideBuy  OrderSide = "buy"
+	SideSell OrderSide = "sell"
+)
+
+// OrderType is how an order meets the book
+type OrderType string
+
+const (
+	OrderTypeLimit  OrderType = "limit"  // Rests at Price until filled (the default)
+	OrderTypeMarket OrderType = "market" // Takes liquidity at once; Price is only the estimate the filters are checked with
+)
+
+// OrderRequest asks order-assurance to place an order (POST /order-assurance), a LIMIT order unless
+// OrderType says otherwise
+type OrderRequest struct {
+	Symbol     string          `json:"symbol"`
+	Price      decimal.Decimal `json:"price"`
+	Side       OrderSide       `json:"side"`
+	Amount     decimal.Decimal `json:"amount"`                // USDT for buy, coin amount for sell
+	TTLSeconds int             `json:"ttl_seconds,omitempty"` // Cancel order after this many seconds (0 = no expiry)
+	Account    string          `json:"account,omitempty"`     // Sub-account to trade on (empty = master account)
+	OrderType  OrderType       `json:"order_type,omitempty"`  // limit (empty) or market; a market buy still buys Amount/Price coins
+
+	// QuoteOrderQty buys with a MARKET order spending exactly Amount USDT (quoteOrderQty) instead of
+	// a LIMIT order for Amount/Price coins. Buys only; Price is kept for the order record.
+	QuoteOrderQty bool `json:"quote_order_qty,omitempty"`
+
+	// Market sells Amount coins with a MARKET order at the best bid instead of a LIMIT order at
+	// Price (e.g. closing a grid's holdings). Sells only; Price is kept for the order record.
+	Market bool `json:"market,omitempty"`
+
+	// StopPrice sells with a Binance OCO instead of a single order: a LIMIT_MAKER at Price and a
+	// STOP_LOSS selling at market once the price falls to StopPrice. Whichever fills first cancels
+	// the other. Limit sells on spot accounts only.
+	StopPrice *decimal.Decimal `json:"stop_price,omitempty"`
+}
+
+// OrderResponse is returned once the order is on the exchange
+type OrderResponse struct {
+	OrderID     string `json:"order_id"`                // The limit leg of an OCO sell
+	Status      string `json:"status"`                  // "assured" means order placed on exchange
+	StopOrderID string `json:"stop_order_id,omitempty"` // The stop-loss leg of an OCO sell
+}
+
+// Order statuses reported by order-assurance
+const (
+	StatusOpen      = "open"
+	StatusFilled    = "filled"
+	StatusCancelled = "cancelled"
+
+	// Batch-only statuses for orders that could not be resolved
+	StatusNotFound = "not_found"
+	StatusError    = "error"
+
+	// Corrective notification for an order whose placement response was lost
+	StatusPlaced = "placed"
+)
+
+// OrderStatus is the exchange state of one order (GET /order-status/{symbol}/{order_id}).
+// Fill details are set when it filled, and on a cancelled order for the part filled before the cancel.
+type OrderStatus struct {
+	OrderID      string           `json:"order_id"`
+	Status       string           `json:"status"` // open, filled, cancelled
+	FilledAmount *decimal.Decimal `json:"filled_amount,omitempty"`
+	FillPrice    *decimal.Decimal `json:"fill_price,omitempty"`
+
+	// Fee actually charged by Binance (omitted if it could not be determined)
+	Commission      *decimal.Decimal `json:"commission,omitempty"`
+	CommissionAsset string           `json:"commission_asset,omitempty"`
+	BaseAsset       string           `json:"base_asset,omitempty"` // Traded coin from the exchange's symbol rules, set with fills
+}
+
+// CommissionAmount returns the reported commission, or zero when unknown
+func (s *OrderStatus) CommissionAmount() decimal.Decimal {
+	if s.Commission == nil {
+		return decimal.Zero
+	}
+	return *s.Commission
+}
+
+// OrderStatusQuery identifies one order in a batch status request
+type OrderStatusQuery struct {
+	Symbol  string `json:"symbol"`
+	OrderID string `json:"order_id"`
+	Account string `json:"account,omitempty"` // Sub-account (empty = master account)
+}
+
+// BatchOrderStatusRequest asks for the status of many orders at once (POST /order-status/batch)
+type BatchOrderStatusRequest struct {
+	Orders []OrderStatusQuery `json:"orders"`
+}
+
+// BatchOrderStatus is the result for one queried order, in request order
+type BatchOrderStatus struct {
+	Symbol  string `json:"symbol"`
+	Account string `json:"account,omitempty"`
+	OrderStatus
+	Error string `json:"error,omitempty"` // Set when Status is "error"
+}
+
+// BatchOrderStatusResponse holds one result per queried order
+type BatchOrderStatusResponse struct {
+	Orders []BatchOrderStatus `json:"orders"`
+}
+
+// OpenOrder is an order resting on the exchange, whoever placed it
+type OpenOrder struct {
+	OrderID     string          `json:"order_id"`
+	Side        OrderSide       `json:"side"`
+	Price       decimal.Decimal `json:"price"`
+	Quantity    decimal.Decimal `json:"quantity"`     // Ordered coin amount
+	ExecutedQty decimal.Decimal `json:"executed_qty"` // Filled so far
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// OpenOrdersResponse lists the open orders of a symbol on an account (GET /open-orders/{symbol}?account=)
+type OpenOrdersResponse struct {
+	Symbol  string      `json:"symbol"`
+	Account string      `json:"account,omitempty"` // Sub-account (empty = master account)
+	Orders  []OpenOrder `json:"orders"`
+}
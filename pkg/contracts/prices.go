@@ -0,0 +1,38 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceKeyPrefix namespaces the shared price cache in Redis (+ symbol, e.g. grid:price:ETHUSDT)
+const PriceKeyPrefix = "grid:price:"
+
+// PriceQuote is the latest price price-monitor fetched for a symbol, stored as JSON
+// in the shared price cache on every poll (not just when a trigger is sent)
+type PriceQuote struct {
+	Symbol    string          `json:"symbol"`
+	Price     decimal.Decimal `json:"price"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// BookTicker is the best bid and ask of a symbol (GET /book/{symbol}?account= on order-assurance)
+type BookTicker struct {
+	Symbol    string          `json:"symbol"`
+	BidPrice  decimal.Decimal `json:"bid_price"`
+	BidQty    decimal.Decimal `json:"bid_qty"`
+	AskPrice  decimal.Decimal `json:"ask_price"`
+	AskQty    decimal.Decimal `json:"ask_qty"`
+	SpreadPct decimal.Decimal `json:"spread_pct"` // Ask minus bid, in % of the mid price
+	Source    string          `json:"source"`     // websocket | rest
+}
+
+// Spread returns ask minus bid in % of the mid price (0 for an empty side)
+func (b BookTicker) Spread() decimal.Decimal {
+	if !b.BidPrice.IsPositive() || !b.AskPrice.IsPositive() {
+		return decimal.Zero
+	}
+	mid := b.BidPrice.Add(b.AskPrice).Div(decimal.NewFromInt(2))
+	return b.AskPrice.Sub(b.BidPrice).Div(mid).Mul(decimal.NewFromInt(100))
+}
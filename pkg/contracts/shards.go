@@ -0,0 +1,29 @@
+package contracts
+
+import "time"
+
+// ShardMap is how symbols are split between grid-trading instances that run sharded
+// (GET /shards). price-monitor sends each symbol's triggers to the URL of the instance
+// holding its lease, and symbols without a live lease (e.g. the depeg guard's peg symbol)
+// to every instance.
+type ShardMap struct {
+	InstanceID string          `json:"instance_id"` // Instance that answered
+	Leader     string          `json:"leader"`      // Instance running the cluster-wide jobs (reports, reconciliation, sweeps)
+	Instances  []ShardInstance `json:"instances"`
+	Leases     []SymbolLease   `json:"leases"`
+}
+
+// ShardInstance is a live grid-trading instance
+type ShardInstance struct {
+	InstanceID  string    `json:"instance_id"`
+	URL         string    `json:"url"`
+	HeartbeatAt time.Time `json:"heartbeat_at"`
+}
+
+// SymbolLease gives one instance a symbol until ExpiresAt; the owner renews it while running
+type SymbolLease struct {
+	Symbol     string    `json:"symbol"`
+	InstanceID string    `json:"instance_id"`
+	URL        string    `json:"url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
@@ -0,0 +1,53 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/grid-trading-bot/pkg/natsjs"
+)
+
+// Message-queue transport (TRANSPORT=nats): the same payloads as the HTTP webhooks,
+// published to these JetStream subjects instead
+const (
+	TriggerSubjectPrefix = "grid.triggers." // + symbol, e.g. grid.triggers.ETHUSDT
+
+	FillSubject           = "grid.notifications.fill"            // FillNotification
+	ErrorSubject          = "grid.notifications.error"           // ErrorNotification
+	ExchangeStatusSubject = "grid.notifications.exchange_status" // ExchangeStatus
+	NotificationSubject   = "grid.notifications.*"
+
+	EventSubjectPrefix = "grid.events." // + Event.Type, e.g. grid.events.sell_filled
+)
+
+// Publishers and the consumer all ensure these streams, so whichever service starts first creates them
+var (
+	// Only the latest price per symbol is kept - a newer trigger replaces one not yet consumed
+	TriggerStream = natsjs.StreamConfig{
+		Name:              "GRID_TRIGGERS",
+		Subjects:          []string{TriggerSubjectPrefix + "*"},
+		Retention:         "workqueue", // Deleted once acked
+		Storage:           "file",
+		Discard:           "old",
+		MaxMsgsPerSubject: 1,
+	}
+
+	// Notifications are kept until consumed, for up to a week of grid-trading downtime
+	NotificationStream = natsjs.StreamConfig{
+		Name:      "GRID_NOTIFICATIONS",
+		Subjects:  []string{NotificationSubject},
+		Retention: "workqueue",
+		Storage:   "file",
+		Discard:   "old",
+		MaxAge:    7 * 24 * time.Hour,
+	}
+
+	// Events for the notifier - limits retention so other consumers can read them too
+	EventStream = natsjs.StreamConfig{
+		Name:      "GRID_EVENTS",
+		Subjects:  []string{EventSubjectPrefix + "*"},
+		Retention: "limits",
+		Storage:   "file",
+		Discard:   "old",
+		MaxAge:    7 * 24 * time.Hour,
+	}
+)
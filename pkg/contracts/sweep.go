@@ -0,0 +1,30 @@
+package contracts
+
+import "github.com/shopspring/decimal"
+
+// Profit sweep destinations
+const (
+	SweepToEarn       = "earn"     // Simple Earn flexible product
+	SweepToWithdrawal = "withdraw" // Whitelisted external address
+)
+
+// SweepRequest asks order-assurance to move realized profit off the spot wallet (POST /profit-sweep)
+type SweepRequest struct {
+	Account     string          `json:"account,omitempty"` // Sub-account (empty = master account)
+	Asset       string          `json:"asset"`
+	Amount      decimal.Decimal `json:"amount"`
+	Destination string          `json:"destination"` // earn | withdraw
+	DryRun      bool            `json:"dry_run"`
+}
+
+// SweepResult describes a completed (or simulated) sweep
+type SweepResult struct {
+	Account     string          `json:"account,omitempty"`
+	Asset       string          `json:"asset"`
+	Amount      decimal.Decimal `json:"amount"`
+	Destination string          `json:"destination"`
+	Address     string          `json:"address,omitempty"` // Withdrawal address
+	DryRun      bool            `json:"dry_run"`
+	FreeBalance decimal.Decimal `json:"free_balance"`        // Spot balance before the sweep
+	Reference   string          `json:"reference,omitempty"` // Earn purchase ID or withdrawal ID
+}
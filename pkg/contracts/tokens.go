@@ -0,0 +1,64 @@
+package contracts
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// API token scopes, each including the ones before it
+const (
+	ScopeRead  = "read"  // GET endpoints
+	ScopeWrite = "write" // Everything that changes state (orders, levels, sweeps)
+	ScopeAdmin = "admin" // Token management, exchange API key rotation, the operator log and grid consistency
+)
+
+// ActorHeader names who made a request, set by the gateway from the key or token it verified
+// (gateway-key, token:<name>) so services can log operator actions by their actor
+const ActorHeader = "X-Actor"
+
+// adminPaths need ScopeAdmin whatever the method
+var adminPaths = []string{"/tokens", "/api-keys", "/operator-actions", "/consistency"}
+
+// RequiredScope returns the scope a request needs. path is relative to the service,
+// e.g. /tokens rather than the gateway's /assurance/tokens.
+func RequiredScope(method, path string) string {
+	for _, prefix := range adminPaths {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return ScopeAdmin
+		}
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return ScopeRead
+	}
+	return ScopeWrite
+}
+
+// HasScope reports whether granted covers required
+func HasScope(granted []string, required string) bool {
+	rank := map[string]int{ScopeRead: 1, ScopeWrite: 2, ScopeAdmin: 3}
+	for _, scope := range granted {
+		if rank[scope] >= rank[required] {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidScope reports whether scope is one of the known scopes
+func ValidScope(scope string) bool {
+	return scope == ScopeRead || scope == ScopeWrite || scope == ScopeAdmin
+}
+
+// TokenVerifyRequest asks order-assurance whether a token is valid (POST /tokens/verify)
+type TokenVerifyRequest struct {
+	Token string `json:"token"`
+}
+
+// TokenVerifyResponse describes a verified token. Name and Scopes are only set when Valid.
+type TokenVerifyResponse struct {
+	Valid     bool       `json:"valid"`
+	Name      string     `json:"name,omitempty"`
+	Scopes    []string   `json:"scopes,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
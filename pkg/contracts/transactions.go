@@ -0,0 +1,39 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransactionRecord is one row of grid-trading's transaction audit log, with the prices and
+// account of its grid level (GET /transactions). Amount, profit and fee fields are only set
+// where the transaction carries them - fills, cancels with a part fill, filled sells.
+type TransactionRecord struct {
+	ID              int              `json:"id"`
+	GridLevelID     int              `json:"grid_level_id"`
+	Account         string           `json:"account,omitempty"`
+	Symbol          string           `json:"symbol"`
+	LevelBuyPrice   decimal.Decimal  `json:"level_buy_price"`
+	LevelSellPrice  decimal.Decimal  `json:"level_sell_price"`
+	Side            string           `json:"side"`   // BUY | SELL
+	Status          string           `json:"status"` // PLACED | FILLED | CANCELLED | ERROR
+	OrderID         string           `json:"order_id,omitempty"`
+	TargetPrice     decimal.Decimal  `json:"target_price"`
+	ExecutedPrice   *decimal.Decimal `json:"executed_price,omitempty"`
+	AmountCoin      *decimal.Decimal `json:"amount_coin,omitempty"`
+	AmountUSDT      *decimal.Decimal `json:"amount_usdt,omitempty"`
+	ProfitUSDT      *decimal.Decimal `json:"profit_usdt,omitempty"`
+	Commission      *decimal.Decimal `json:"commission,omitempty"`
+	CommissionAsset string           `json:"commission_asset,omitempty"`
+	FeeUSDT         *decimal.Decimal `json:"fee_usdt,omitempty"`
+	ErrorCode       string           `json:"error_code,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+}
+
+// TransactionPage is a batch of transactions in ID order. Pass NextAfterID as after_id to
+// read the next page; an empty page means the reader has caught up.
+type TransactionPage struct {
+	Transactions []TransactionRecord `json:"transactions"`
+	NextAfterID  int                 `json:"next_after_id"`
+}
@@ -0,0 +1,36 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceTrigger is sent by price-monitor when a symbol's price moves (POST /trigger-for-price).
+// Sequence increases with every trigger price-monitor sends, across restarts, and ExchangeTime
+// is when the exchange reported the price - grid-trading ignores a trigger older than the last
+// one it applied for the symbol. Source names the market data feed that reported the price
+// ("primary", "secondary"); exchange times are only compared between triggers of the same
+// source, as each feed has its own clock. All three are optional, triggers without them are
+// always applied.
+//
+// Feed, Exchange and Confidence tell grid-trading what the price is based on, so it can hold
+// back orders a single venue's price shouldn't place (TRIGGER_MIN_CONFIDENCE,
+// TRIGGER_AGGREGATED_ABOVE). Triggers without them count as an unknown feed with no confidence.
+type PriceTrigger struct {
+	Symbol       string          `json:"symbol"`
+	Price        decimal.Decimal `json:"price"`
+	Sequence     uint64          `json:"sequence,omitempty"`
+	ExchangeTime *time.Time      `json:"exchange_time,omitempty"`
+	Source       string          `json:"source,omitempty"`
+	Feed         string          `json:"feed,omitempty"`       // websocket | rest | aggregated
+	Exchange     string          `json:"exchange,omitempty"`   // Venue of the price, venues joined by "+" when aggregated
+	Confidence   float64         `json:"confidence,omitempty"` // 0-1, share of the venues read that agree on the price
+}
+
+// Price trigger feeds
+const (
+	FeedWebsocket  = "websocket"  // Pushed by an exchange market data stream
+	FeedREST       = "rest"       // Polled from one exchange's REST API
+	FeedAggregated = "aggregated" // Median of several exchanges polled together
+)
@@ -0,0 +1,156 @@
+// Package klines downloads Binance candlesticks and keeps them in a local SQLite
+// store, so backtests, parameter suggestions and volatility estimates read history
+// from disk and only ask the exchange for candles they don't have yet.
+package klines
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// BinanceAPIURL serves public market data; no API key is needed
+	BinanceAPIURL = "https://api.binance.com"
+
+	// pageLimit is the most klines Binance returns per request
+	pageLimit = 1000
+)
+
+// Intervals Binance serves klines for
+var Intervals = []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w", "1M"}
+
+// ValidInterval reports whether interval is one of Intervals
+func ValidInterval(interval string) bool {
+	for _, i := range Intervals {
+		if i == interval {
+			return true
+		}
+	}
+	return false
+}
+
+// IntervalDuration is the length of one candle of a valid interval, counting a month as 31 days
+func IntervalDuration(interval string) time.Duration {
+	if interval == "1M" {
+		return 31 * 24 * time.Hour
+	}
+	unit := map[byte]time.Duration{'m': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}[interval[len(interval)-1]]
+	n, _ := strconv.Atoi(interval[:len(interval)-1])
+	return time.Duration(n) * unit
+}
+
+// Kline is one candlestick. Times are UTC; CloseTime is the last millisecond of the candle.
+type Kline struct {
+	OpenTime    time.Time       `json:"open_time"`
+	CloseTime   time.Time       `json:"close_time"`
+	Open        decimal.Decimal `json:"open"`
+	High        decimal.Decimal `json:"high"`
+	Low         decimal.Decimal `json:"low"`
+	Close       decimal.Decimal `json:"close"`
+	Volume      decimal.Decimal `json:"volume"`       // Base asset
+	QuoteVolume decimal.Decimal `json:"quote_volume"` // Quote asset
+	Trades      int             `json:"trades"`
+}
+
+// Fetcher reads klines from the Binance REST API (GET /api/v3/klines)
+type Fetcher struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewFetcher creates a fetcher for baseURL (empty = BinanceAPIURL)
+func NewFetcher(baseURL string) *Fetcher {
+	if baseURL == "" {
+		baseURL = BinanceAPIURL
+	}
+	return &Fetcher{
+		client:  &http.Client{Timeout: 15 * time.Second},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Fetch returns up to 1000 klines opening in [from, to], oldest first
+func (f *Fetcher) Fetch(symbol, interval string, from, to time.Time) ([]Kline, error) {
+	params := url.Values{}
+	params.Set("symbol", strings.ToUpper(symbol))
+	params.Set("interval", interval)
+	params.Set("startTime", strconv.FormatInt(from.UnixMilli(), 10))
+	params.Set("endTime", strconv.FormatInt(to.UnixMilli(), 10))
+	params.Set("limit", strconv.Itoa(pageLimit))
+
+	resp, err := f.client.Get(f.baseURL + "/api/v3/klines?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("binance API error %d: %s", resp.StatusCode, body)
+	}
+
+	// Each kline is an array: [openTime, open, high, low, close, volume, closeTime, quoteVolume, trades, ...]
+	var rows [][]json.RawMessage
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode klines: %w", err)
+	}
+
+	klines := make([]Kline, 0, len(rows))
+	for _, row := range rows {
+		k, err := parseRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode kline: %w", err)
+		}
+		klines = append(klines, k)
+	}
+	return klines, nil
+}
+
+func parseRow(row []json.RawMessage) (Kline, error) {
+	var k Kline
+	if len(row) < 9 {
+		return k, fmt.Errorf("expected at least 9 fields, got %d", len(row))
+	}
+
+	var openMs, closeMs int64
+	var open, high, low, closePrice, volume, quoteVolume string
+	fields := []struct {
+		raw  json.RawMessage
+		dest interface{}
+	}{
+		{row[0], &openMs}, {row[1], &open}, {row[2], &high}, {row[3], &low}, {row[4], &closePrice},
+		{row[5], &volume}, {row[6], &closeMs}, {row[7], &quoteVolume}, {row[8], &k.Trades},
+	}
+	for _, field := range fields {
+		if err := json.Unmarshal(field.raw, field.dest); err != nil {
+			return k, err
+		}
+	}
+
+	k.OpenTime = time.UnixMilli(openMs).UTC()
+	k.CloseTime = time.UnixMilli(closeMs).UTC()
+	var err error
+	for _, d := range []struct {
+		s    string
+		dest *decimal.Decimal
+	}{
+		{open, &k.Open}, {high, &k.High}, {low, &k.Low}, {closePrice, &k.Close},
+		{volume, &k.Volume}, {quoteVolume, &k.QuoteVolume},
+	} {
+		if *d.dest, err = decimal.NewFromString(d.s); err != nil {
+			return k, err
+		}
+	}
+	return k, nil
+}
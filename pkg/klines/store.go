@@ -0,0 +1,209 @@
+package klines
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// schema is created by NewStore in the database of the service using the store.
+// Times are Binance's epoch milliseconds, the key klines are paged by.
+const schema = `
+CREATE TABLE IF NOT EXISTS klines (
+    symbol TEXT NOT NULL,
+    interval TEXT NOT NULL,
+    open_time INTEGER NOT NULL,   -- Epoch ms, UTC
+    close_time INTEGER NOT NULL,  -- Epoch ms, last millisecond of the candle
+    open TEXT NOT NULL,
+    high TEXT NOT NULL,
+    low TEXT NOT NULL,
+    close TEXT NOT NULL,
+    volume TEXT NOT NULL,
+    quote_volume TEXT NOT NULL,
+    trades INTEGER NOT NULL,
+    PRIMARY KEY (symbol, interval, open_time)
+);
+`
+
+// Store keeps closed klines per symbol and interval. Every symbol/interval series is
+// contiguous: Sync only extends it at either end, so the stored range has no gaps.
+type Store struct {
+	db      *sql.DB
+	fetcher *Fetcher
+
+	mu sync.Mutex // One download at a time, so concurrent callers don't fetch the same candles
+}
+
+// NewStore creates the klines table in db if needed. fetcher downloads missing klines;
+// with a nil fetcher the store only serves what is already stored.
+func NewStore(db *sql.DB, fetcher *Fetcher) (*Store, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to create klines table: %w", err)
+	}
+	return &Store{db: db, fetcher: fetcher}, nil
+}
+
+// Load returns the klines opening in [from, to), downloading the ones not stored yet
+func (s *Store) Load(symbol, interval string, from, to time.Time) ([]Kline, error) {
+	if _, err := s.Sync(symbol, interval, from, to); err != nil {
+		return nil, err
+	}
+	return s.Range(symbol, interval, from, to)
+}
+
+// Sync downloads the closed klines of [from, to) missing before or after the stored series
+// and returns how many were added. The candle still open is never stored.
+func (s *Store) Sync(symbol, interval string, from, to time.Time) (int, error) {
+	symbol = strings.ToUpper(symbol)
+	if !ValidInterval(interval) {
+		return 0, fmt.Errorf("invalid interval %q", interval)
+	}
+	if s.fetcher == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	if to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		return 0, nil
+	}
+	last := to.Add(-time.Millisecond) // to is exclusive, Binance's endTime inclusive
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	earliest, latest, ok, err := s.bounds(symbol, interval)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read stored klines: %w", err)
+	}
+	if !ok {
+		return s.download(symbol, interval, from, last, now)
+	}
+
+	added := 0
+	if from.Before(earliest) {
+		n, err := s.download(symbol, interval, from, earliest.Add(-time.Millisecond), now)
+		added += n
+		if err != nil {
+			return added, err
+		}
+	}
+	if last.After(latest) {
+		n, err := s.download(symbol, interval, latest.Add(time.Millisecond), last, now)
+		added += n
+		if err != nil {
+			return added, err
+		}
+	}
+	return added, nil
+}
+
+// Range returns the stored klines opening in [from, to), oldest first
+func (s *Store) Range(symbol, interval string, from, to time.Time) ([]Kline, error) {
+	query := `
+		SELECT open_time, close_time, open, high, low, close, volume, quote_volume, trades
+		FROM klines
+		WHERE symbol = $1 AND interval = $2 AND open_time >= $3 AND open_time < $4
+		ORDER BY open_time
+	`
+
+	rows, err := s.db.Query(query, strings.ToUpper(symbol), interval, from.UnixMilli(), to.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query klines: %w", err)
+	}
+	defer rows.Close()
+
+	var klines []Kline
+	for rows.Next() {
+		var k Kline
+		var openMs, closeMs int64
+		if err := rows.Scan(&openMs, &closeMs, &k.Open, &k.High, &k.Low, &k.Close, &k.Volume, &k.QuoteVolume, &k.Trades); err != nil {
+			return nil, fmt.Errorf("failed to scan kline: %w", err)
+		}
+		k.OpenTime = time.UnixMilli(openMs).UTC()
+		k.CloseTime = time.UnixMilli(closeMs).UTC()
+		klines = append(klines, k)
+	}
+	return klines, rows.Err()
+}
+
+// bounds returns the open times of the first and last stored kline
+func (s *Store) bounds(symbol, interval string) (earliest, latest time.Time, ok bool, err error) {
+	var minMs, maxMs sql.NullInt64
+	err = s.db.QueryRow(`SELECT MIN(open_time), MAX(open_time) FROM klines WHERE symbol = $1 AND interval = $2`,
+		symbol, interval).Scan(&minMs, &maxMs)
+	if err != nil || !minMs.Valid {
+		return time.Time{}, time.Time{}, false, err
+	}
+	return time.UnixMilli(minMs.Int64), time.UnixMilli(maxMs.Int64), true, nil
+}
+
+// download pages through the klines opening in [from, last] and stores the closed ones
+func (s *Store) download(symbol, interval string, from, last, now time.Time) (int, error) {
+	added := 0
+	for !from.After(last) {
+		page, err := s.fetcher.Fetch(symbol, interval, from, last)
+		if err != nil {
+			return added, fmt.Errorf("failed to download %s %s klines: %w", symbol, interval, err)
+		}
+
+		closed := page
+		for i, k := range page {
+			if !k.CloseTime.Before(now) {
+				closed = page[:i]
+				break
+			}
+		}
+
+		n, err := s.insert(symbol, interval, closed)
+		added += n
+		if err != nil {
+			return added, err
+		}
+		if len(closed) < pageLimit {
+			break
+		}
+		from = closed[len(closed)-1].OpenTime.Add(time.Millisecond)
+	}
+	return added, nil
+}
+
+func (s *Store) insert(symbol, interval string, klines []Kline) (int, error) {
+	if len(klines) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO klines (symbol, interval, open_time, close_time, open, high, low, close, volume, quote_volume, trades)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (symbol, interval, open_time) DO NOTHING
+	`
+
+	added := 0
+	for _, k := range klines {
+		result, err := tx.Exec(query, symbol, interval, k.OpenTime.UnixMilli(), k.CloseTime.UnixMilli(),
+			k.Open.String(), k.High.String(), k.Low.String(), k.Close.String(),
+			k.Volume.String(), k.QuoteVolume.String(), k.Trades)
+		if err != nil {
+			return 0, fmt.Errorf("failed to store kline %s %s %d: %w", symbol, interval, k.OpenTime.UnixMilli(), err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to store klines: %w", err)
+	}
+	return added, nil
+}
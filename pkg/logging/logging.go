@@ -0,0 +1,123 @@
+// Package logging sets up the structured logger every service writes through. Services call
+// Setup first thing in main; after that slog's default logger writes LOG_FORMAT lines at
+// LOG_LEVEL, tagged with the service, and the standard log package is routed through it.
+//
+// New code logs with slog and the field keys below, e.g.
+//
+//	slog.Info("Placed buy order", logging.KeyLevelID, level.ID, logging.KeyOrderID, orderID)
+//
+// Older log.Printf lines keep working: their "ERROR:", "WARNING:", "INFO:", "SUCCESS:" or
+// "DEBUG:" prefix becomes the level. Lines without one - startup messages, log.Fatal - are
+// written at INFO whatever LOG_LEVEL, so a service never exits without saying why.
+package logging
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Field keys shared by all services, so log queries work across them
+const (
+	KeyService = "service"
+	KeySymbol  = "symbol"
+	KeyLevelID = "level_id"
+	KeyOrderID = "order_id"
+	KeyAccount = "account"
+	KeyError   = "error"
+)
+
+// Supported values of LOG_FORMAT
+const (
+	FormatText = "text" // key=value pairs, the default
+	FormatJSON = "json" // One JSON object per line, for Loki/ELK
+)
+
+// Setup configures the default slog logger from LOG_FORMAT (text) and LOG_LEVEL (info) and
+// returns it. Invalid settings stop the service like any other config error.
+func Setup(service string) *slog.Logger {
+	level, ok := parseLevel(os.Getenv("LOG_LEVEL"))
+	if !ok {
+		log.Fatal("LOG_LEVEL must be debug, info, warn or error")
+	}
+	format := os.Getenv("LOG_FORMAT")
+	if format == "" {
+		format = FormatText
+	}
+	if format != FormatText && format != FormatJSON {
+		log.Fatal("LOG_FORMAT must be text or json")
+	}
+
+	logger := New(os.Stderr, service, format, level)
+	slog.SetDefault(logger)
+
+	// slog.SetDefault logs everything from the log package at INFO; read the level from the prefix instead
+	log.SetFlags(0)
+	log.SetOutput(&stdBridge{logger: logger, unleveled: New(os.Stderr, service, format, min(level, slog.LevelInfo))})
+	return logger
+}
+
+// New builds a logger writing format lines at level and above to w, tagged with the service
+func New(w io.Writer, service, format string, level slog.Level) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler = slog.NewTextHandler(w, opts)
+	if format == FormatJSON {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(handler).With(KeyService, service)
+}
+
+// Err is the error attribute of a failed operation, under the shared key
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.String(KeyError, err.Error())
+}
+
+func parseLevel(value string) (slog.Level, bool) {
+	switch strings.ToLower(value) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "", "info":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	}
+	return 0, false
+}
+
+// prefixLevels maps the prefixes of log.Printf lines to levels, SUCCESS being a notable INFO
+var prefixLevels = []struct {
+	prefix string
+	level  slog.Level
+}{
+	{"ERROR: ", slog.LevelError},
+	{"WARNING: ", slog.LevelWarn},
+	{"INFO: ", slog.LevelInfo},
+	{"SUCCESS: ", slog.LevelInfo},
+	{"DEBUG: ", slog.LevelDebug},
+}
+
+// stdBridge writes lines of the standard log package to a slog logger at their prefix's level
+type stdBridge struct {
+	logger    *slog.Logger
+	unleveled *slog.Logger // Lines without a prefix, at INFO even above LOG_LEVEL
+}
+
+func (b *stdBridge) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+	for _, pl := range prefixLevels {
+		if strings.HasPrefix(msg, pl.prefix) {
+			b.logger.Log(context.Background(), pl.level, strings.TrimPrefix(msg, pl.prefix))
+			return len(p), nil
+		}
+	}
+	b.unleveled.Info(msg)
+	return len(p), nil
+}
@@ -0,0 +1,481 @@
+// Package natsjs is a small NATS client with just the JetStream features the
+// services use for the message-queue transport: publish with acknowledgement,
+// durable pull consumers and explicit acks.
+package natsjs
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrNotConnected = errors.New("nats: not connected")
+	ErrTimeout      = errors.New("nats: timeout")
+	ErrNoResponders = errors.New("nats: no responders")
+	ErrClosed       = errors.New("nats: connection closed")
+)
+
+const (
+	dialTimeout  = 5 * time.Second
+	writeTimeout = 5 * time.Second
+	maxBackoff   = 30 * time.Second
+)
+
+// Msg is a message delivered to a subscription. Status is the code from a
+// NATS/1.0 status header (e.g. 404, 408, 503), 0 for regular messages.
+type Msg struct {
+	Subject string
+	Reply   string
+	Header  textproto.MIMEHeader
+	Status  int
+	Data    []byte
+
+	conn *Conn
+}
+
+// Subscription receives messages published to its subject
+type Subscription struct {
+	sid     int64
+	subject string
+	msgs    chan *Msg
+	done    chan struct{}
+}
+
+// Conn is a NATS connection that reconnects with backoff and restores its
+// subscriptions when the server goes away
+type Conn struct {
+	addr string
+	name string
+	user *url.Userinfo
+
+	mu      sync.Mutex
+	nc      net.Conn
+	bw      *bufio.Writer
+	subs    map[int64]*Subscription
+	nextSID int64
+	closed  bool
+
+	inboxPrefix string
+	inboxSeq    atomic.Int64
+}
+
+// Connect dials a NATS server (nats://[user:pass@]host:port) and keeps the connection alive in the background
+func Connect(serverURL, name string) (*Conn, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid NATS URL %q", serverURL)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	c := &Conn{
+		addr:        addr,
+		name:        name,
+		user:        u.User,
+		subs:        make(map[int64]*Subscription),
+		inboxPrefix: "_INBOX." + randomToken() + ".",
+	}
+
+	nc, br, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	c.nc = nc
+	c.bw = bufio.NewWriter(nc)
+
+	go c.readLoop(nc, br)
+	return c, nil
+}
+
+// Close stops reconnecting and closes the connection
+func (c *Conn) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	if c.nc != nil {
+		c.nc.Close()
+		c.nc = nil
+	}
+}
+
+// NewInbox returns a unique subject for replies
+func (c *Conn) NewInbox() string {
+	return c.inboxPrefix + strconv.FormatInt(c.inboxSeq.Add(1), 10)
+}
+
+// Publish sends a message, with an optional reply subject
+func (c *Conn) Publish(subject, reply string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+	if c.nc == nil {
+		return ErrNotConnected
+	}
+
+	if reply != "" {
+		fmt.Fprintf(c.bw, "PUB %s %s %d\r\n", subject, reply, len(data))
+	} else {
+		fmt.Fprintf(c.bw, "PUB %s %d\r\n", subject, len(data))
+	}
+	c.bw.Write(data)
+	c.bw.WriteString("\r\n")
+	return c.flushLocked()
+}
+
+// Subscribe starts delivering messages on subject. Subscriptions made while
+// disconnected are sent to the server on reconnect.
+func (c *Conn) Subscribe(subject string, buffer int) (*Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrClosed
+	}
+
+	c.nextSID++
+	sub := &Subscription{
+		sid:     c.nextSID,
+		subject: subject,
+		msgs:    make(chan *Msg, buffer),
+		done:    make(chan struct{}),
+	}
+	c.subs[sub.sid] = sub
+
+	if c.nc != nil {
+		fmt.Fprintf(c.bw, "SUB %s %d\r\n", subject, sub.sid)
+		if err := c.flushLocked(); err != nil {
+			return nil, err
+		}
+	}
+	return sub, nil
+}
+
+// Unsubscribe stops delivery to sub
+func (c *Conn) Unsubscribe(sub *Subscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.subs[sub.sid]; !ok {
+		return
+	}
+	delete(c.subs, sub.sid)
+	close(sub.done)
+
+	if c.nc != nil {
+		fmt.Fprintf(c.bw, "UNSUB %d\r\n", sub.sid)
+		c.flushLocked()
+	}
+}
+
+// Request publishes data and waits for the first reply
+func (c *Conn) Request(subject string, data []byte, timeout time.Duration) (*Msg, error) {
+	inbox := c.NewInbox()
+	sub, err := c.Subscribe(inbox, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Unsubscribe(sub)
+
+	if err := c.Publish(subject, inbox, data); err != nil {
+		return nil, err
+	}
+
+	select {
+	case msg := <-sub.msgs:
+		if msg.Status == 503 {
+			return nil, ErrNoResponders
+		}
+		return msg, nil
+	case <-time.After(timeout):
+		return nil, ErrTimeout
+	}
+}
+
+// Next waits up to timeout for the subscription's next message
+func (s *Subscription) Next(timeout time.Duration) (*Msg, error) {
+	select {
+	case msg := <-s.msgs:
+		return msg, nil
+	case <-s.done:
+		return nil, ErrClosed
+	case <-time.After(timeout):
+		return nil, ErrTimeout
+	}
+}
+
+// Respond replies to a message that carries a reply subject
+func (m *Msg) Respond(data []byte) error {
+	if m.Reply == "" {
+		return errors.New("nats: message has no reply subject")
+	}
+	return m.conn.Publish(m.Reply, "", data)
+}
+
+func (c *Conn) flushLocked() error {
+	c.nc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := c.bw.Flush(); err != nil {
+		// The read loop notices the closed socket and reconnects
+		c.nc.Close()
+		return fmt.Errorf("nats: write failed: %w", err)
+	}
+	return nil
+}
+
+// dial connects and completes the INFO / CONNECT / PING handshake
+func (c *Conn) dial() (net.Conn, *bufio.Reader, error) {
+	nc, err := net.DialTimeout("tcp", c.addr, dialTimeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to NATS at %s: %w", c.addr, err)
+	}
+	nc.SetDeadline(time.Now().Add(dialTimeout))
+	br := bufio.NewReader(nc)
+
+	line, err := readLine(br)
+	if err != nil || !strings.HasPrefix(line, "INFO ") {
+		nc.Close()
+		return nil, nil, fmt.Errorf("unexpected NATS greeting %q: %v", line, err)
+	}
+
+	options := map[string]interface{}{
+		"verbose":       false,
+		"pedantic":      false,
+		"lang":          "go",
+		"version":       "1.0.0",
+		"protocol":      1,
+		"name":          c.name,
+		"headers":       true,
+		"no_responders": true,
+	}
+	if c.user != nil {
+		if pass, ok := c.user.Password(); ok {
+			options["user"] = c.user.Username()
+			options["pass"] = pass
+		} else {
+			options["auth_token"] = c.user.Username()
+		}
+	}
+	connect, _ := json.Marshal(options)
+
+	if _, err := fmt.Fprintf(nc, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		nc.Close()
+		return nil, nil, fmt.Errorf("failed to send NATS handshake: %w", err)
+	}
+
+	for {
+		line, err := readLine(br)
+		if err != nil {
+			nc.Close()
+			return nil, nil, fmt.Errorf("NATS handshake failed: %w", err)
+		}
+		switch {
+		case line == "PONG":
+			nc.SetDeadline(time.Time{})
+			return nc, br, nil
+		case strings.HasPrefix(line, "-ERR"):
+			nc.Close()
+			return nil, nil, fmt.Errorf("NATS rejected connection: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+func (c *Conn) readLoop(nc net.Conn, br *bufio.Reader) {
+	for {
+		err := c.readMessages(br)
+
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			return
+		}
+		nc.Close()
+		c.nc = nil
+		c.mu.Unlock()
+
+		log.Printf("WARNING: NATS connection to %s lost: %v - reconnecting", c.addr, err)
+		if nc, br = c.reconnect(); nc == nil {
+			return
+		}
+	}
+}
+
+// reconnect retries with exponential backoff and restores subscriptions; nil once closed
+func (c *Conn) reconnect() (net.Conn, *bufio.Reader) {
+	backoff := time.Second
+	for {
+		time.Sleep(backoff)
+
+		c.mu.Lock()
+		closed := c.closed
+		c.mu.Unlock()
+		if closed {
+			return nil, nil
+		}
+
+		nc, br, err := c.dial()
+		if err != nil {
+			log.Printf("WARNING: NATS reconnect failed: %v (retrying in %s)", err, backoff)
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			nc.Close()
+			return nil, nil
+		}
+		c.nc = nc
+		c.bw = bufio.NewWriter(nc)
+		for _, sub := range c.subs {
+			fmt.Fprintf(c.bw, "SUB %s %d\r\n", sub.subject, sub.sid)
+		}
+		err = c.flushLocked()
+		c.mu.Unlock()
+
+		if err != nil {
+			continue
+		}
+		log.Printf("INFO: NATS reconnected to %s", c.addr)
+		return nc, br
+	}
+}
+
+func (c *Conn) readMessages(br *bufio.Reader) error {
+	for {
+		line, err := readLine(br)
+		if err != nil {
+			return err
+		}
+
+		op, args, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(op) {
+		case "MSG":
+			if err := c.readMsg(br, strings.Fields(args), false); err != nil {
+				return err
+			}
+		case "HMSG":
+			if err := c.readMsg(br, strings.Fields(args), true); err != nil {
+				return err
+			}
+		case "PING":
+			c.mu.Lock()
+			if c.nc != nil {
+				c.bw.WriteString("PONG\r\n")
+				c.flushLocked()
+			}
+			c.mu.Unlock()
+		case "-ERR":
+			log.Printf("ERROR: NATS server error: %s", args)
+		case "PONG", "+OK", "INFO":
+		default:
+			return fmt.Errorf("unexpected NATS protocol line %q", line)
+		}
+	}
+}
+
+// readMsg parses MSG <subject> <sid> [reply] <size> or HMSG <subject> <sid> [reply] <hdr size> <total size>
+func (c *Conn) readMsg(br *bufio.Reader, args []string, hasHeaders bool) error {
+	sizes := 1
+	if hasHeaders {
+		sizes = 2
+	}
+	if len(args) != 2+sizes && len(args) != 3+sizes {
+		return fmt.Errorf("malformed NATS message arguments %v", args)
+	}
+
+	msg := &Msg{Subject: args[0], conn: c}
+	sid, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed NATS subscription id %q", args[1])
+	}
+	if len(args) == 3+sizes {
+		msg.Reply = args[2]
+	}
+
+	total, err := strconv.Atoi(args[len(args)-1])
+	if err != nil {
+		return fmt.Errorf("malformed NATS message size %q", args[len(args)-1])
+	}
+	headerSize := 0
+	if hasHeaders {
+		if headerSize, err = strconv.Atoi(args[len(args)-2]); err != nil || headerSize > total {
+			return fmt.Errorf("malformed NATS header size %q", args[len(args)-2])
+		}
+	}
+
+	payload := make([]byte, total+2)
+	if _, err := io.ReadFull(br, payload); err != nil {
+		return err
+	}
+	msg.Data = payload[headerSize:total]
+	if hasHeaders {
+		msg.Header, msg.Status = parseHeaders(payload[:headerSize])
+	}
+
+	c.mu.Lock()
+	sub := c.subs[sid]
+	c.mu.Unlock()
+	if sub == nil {
+		return nil
+	}
+
+	select {
+	case sub.msgs <- msg:
+	case <-sub.done:
+	}
+	return nil
+}
+
+// parseHeaders reads a "NATS/1.0 [status [description]]" block
+func parseHeaders(block []byte) (textproto.MIMEHeader, int) {
+	r := textproto.NewReader(bufio.NewReader(bytes.NewReader(block)))
+	first, err := r.ReadLine()
+	if err != nil {
+		return nil, 0
+	}
+
+	status := 0
+	if fields := strings.Fields(first); len(fields) > 1 {
+		status, _ = strconv.Atoi(fields[1])
+	}
+
+	header, _ := r.ReadMIMEHeader()
+	return header, status
+}
+
+func readLine(br *bufio.Reader) (string, error) {
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func randomToken() string {
+	b := make([]byte, 11)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,202 @@
+package natsjs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// JetStream API error codes the client reacts to
+const (
+	errCodeStreamNameInUse = 10058 // Stream exists with a different configuration
+)
+
+// APIError is an error returned by the JetStream API
+type APIError struct {
+	Code        int    `json:"code"`
+	ErrCode     int    `json:"err_code"`
+	Description string `json:"description"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("jetstream: %s (code %d, err_code %d)", e.Description, e.Code, e.ErrCode)
+}
+
+// StreamConfig is the subset of stream settings the services use. Zero limits mean unlimited.
+type StreamConfig struct {
+	Name              string        `json:"name"`
+	Subjects          []string      `json:"subjects"`
+	Retention         string        `json:"retention"` // limits, workqueue
+	Storage           string        `json:"storage"`   // file
+	Discard           string        `json:"discard"`   // old
+	MaxAge            time.Duration `json:"max_age,omitempty"`
+	MaxMsgsPerSubject int64         `json:"max_msgs_per_subject,omitempty"`
+}
+
+// ConsumerConfig describes a durable pull consumer with explicit acks
+type ConsumerConfig struct {
+	Durable       string        `json:"durable_name"`
+	FilterSubject string        `json:"filter_subject,omitempty"`
+	DeliverPolicy string        `json:"deliver_policy"` // all
+	AckPolicy     string        `json:"ack_policy"`     // explicit
+	ReplayPolicy  string        `json:"replay_policy"`  // instant
+	AckWait       time.Duration `json:"ack_wait,omitempty"`
+	MaxAckPending int           `json:"max_ack_pending,omitempty"`
+}
+
+// PubAck confirms a message was stored by a stream
+type PubAck struct {
+	Stream    string `json:"stream"`
+	Sequence  uint64 `json:"seq"`
+	Duplicate bool   `json:"duplicate,omitempty"`
+}
+
+// JetStream wraps a connection with the JetStream API
+type JetStream struct {
+	conn    *Conn
+	timeout time.Duration
+}
+
+func New(conn *Conn) *JetStream {
+	return &JetStream{conn: conn, timeout: 5 * time.Second}
+}
+
+// EnsureStream creates the stream, or updates it if its configuration changed
+func (js *JetStream) EnsureStream(cfg StreamConfig) error {
+	err := js.request("$JS.API.STREAM.CREATE."+cfg.Name, cfg, nil)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.ErrCode == errCodeStreamNameInUse {
+		err = js.request("$JS.API.STREAM.UPDATE."+cfg.Name, cfg, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to ensure stream %s: %w", cfg.Name, err)
+	}
+	return nil
+}
+
+// EnsureConsumer creates (or updates) a durable pull consumer on a stream
+func (js *JetStream) EnsureConsumer(stream string, cfg ConsumerConfig) (*Consumer, error) {
+	req := struct {
+		Stream string         `json:"stream_name"`
+		Config ConsumerConfig `json:"config"`
+	}{stream, cfg}
+
+	if err := js.request("$JS.API.CONSUMER.DURABLE.CREATE."+stream+"."+cfg.Durable, req, nil); err != nil {
+		return nil, fmt.Errorf("failed to ensure consumer %s on %s: %w", cfg.Durable, stream, err)
+	}
+	return &Consumer{js: js, stream: stream, durable: cfg.Durable}, nil
+}
+
+// Publish stores a message in whichever stream captures the subject, waiting for its acknowledgement
+func (js *JetStream) Publish(subject string, data []byte) (*PubAck, error) {
+	msg, err := js.conn.Request(subject, data, js.timeout)
+	if errors.Is(err, ErrNoResponders) {
+		return nil, fmt.Errorf("no stream accepts subject %s: %w", subject, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ack struct {
+		PubAck
+		Error *APIError `json:"error"`
+	}
+	if err := json.Unmarshal(msg.Data, &ack); err != nil {
+		return nil, fmt.Errorf("invalid publish acknowledgement: %w", err)
+	}
+	if ack.Error != nil {
+		return nil, ack.Error
+	}
+	return &ack.PubAck, nil
+}
+
+func (js *JetStream) request(subject string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	msg, err := js.conn.Request(subject, data, js.timeout)
+	if errors.Is(err, ErrNoResponders) {
+		return fmt.Errorf("JetStream not enabled on the server: %w", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	var envelope struct {
+		Error *APIError `json:"error"`
+	}
+	if err := json.Unmarshal(msg.Data, &envelope); err != nil {
+		return fmt.Errorf("invalid JetStream response: %w", err)
+	}
+	if envelope.Error != nil {
+		return envelope.Error
+	}
+
+	if out != nil {
+		return json.Unmarshal(msg.Data, out)
+	}
+	return nil
+}
+
+// Consumer pulls messages from a durable consumer
+type Consumer struct {
+	js      *JetStream
+	stream  string
+	durable string
+}
+
+// Fetch waits up to wait for as many as batch messages - the server holds back a
+// partial batch until wait expires. Each message must be acked (or nacked) or it
+// is redelivered once the consumer's ack wait expires.
+func (c *Consumer) Fetch(batch int, wait time.Duration) ([]*Msg, error) {
+	conn := c.js.conn
+	sub, err := conn.Subscribe(conn.NewInbox(), batch+1)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Unsubscribe(sub)
+
+	req, _ := json.Marshal(map[string]interface{}{"batch": batch, "expires": wait})
+	if err := conn.Publish("$JS.API.CONSUMER.MSG.NEXT."+c.stream+"."+c.durable, sub.subject, req); err != nil {
+		return nil, err
+	}
+
+	var msgs []*Msg
+	deadline := time.Now().Add(wait + time.Second)
+	for len(msgs) < batch {
+		msg, err := sub.Next(time.Until(deadline))
+		if errors.Is(err, ErrTimeout) {
+			break
+		}
+		if err != nil {
+			return msgs, err
+		}
+
+		switch msg.Status {
+		case 0:
+			msgs = append(msgs, msg)
+			continue
+		case 404, 408, 409: // No messages / request expired / consumer changed
+		case 503:
+			return msgs, ErrNoResponders
+		default:
+			return msgs, fmt.Errorf("unexpected fetch status %d", msg.Status)
+		}
+		break
+	}
+	return msgs, nil
+}
+
+// Ack confirms a consumed message so it is never redelivered
+func (m *Msg) Ack() error {
+	return m.Respond([]byte("+ACK"))
+}
+
+// Nak asks for redelivery after delay
+func (m *Msg) Nak(delay time.Duration) error {
+	return m.Respond([]byte(fmt.Sprintf(`-NAK {"delay": %d}`, delay.Nanoseconds())))
+}
@@ -0,0 +1,69 @@
+package oplog
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// ServeHTTP lists logged actions, newest first (?actor=&action=&from=&to=&limit=).
+// action is the method and route as logged, e.g. "POST /levels/bulk"; from and to are
+// RFC3339 or YYYY-MM-DD.
+func (s *Store) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := Filter{
+		Actor:  q.Get("actor"),
+		Action: q.Get("action"),
+		Limit:  defaultLimit,
+	}
+
+	var err error
+	if filter.From, err = parseTime(q.Get("from")); err != nil {
+		http.Error(w, "Invalid from (RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = parseTime(q.Get("to")); err != nil {
+		http.Error(w, "Invalid to (RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			http.Error(w, "Invalid limit (1-1000)", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	actions, err := s.List(filter)
+	if err != nil {
+		log.Printf("ERROR: Failed to read operator actions: %v", err)
+		http.Error(w, "Failed to read operator actions", http.StatusInternalServerError)
+		return
+	}
+
+	if actions == nil {
+		actions = []*Action{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"actions": actions})
+}
+
+func parseTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
@@ -0,0 +1,140 @@
+package oplog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/contracts"
+)
+
+const (
+	// maxPayload is the most of a request body kept in the log
+	maxPayload = 16 * 1024
+
+	// Anonymous is the actor of requests nobody authenticated
+	Anonymous = "anonymous"
+)
+
+// sensitiveFields are redacted from logged payloads wherever they appear in a key
+var sensitiveFields = []string{"secret", "token", "password"}
+
+type actorKey struct{}
+
+// WithActor marks who made a request, for auth middleware that identified the caller
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set with WithActor, empty if none
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// Middleware logs every state-changing request (anything but GET, HEAD and OPTIONS) once it
+// has been answered. internal lists the route templates other services call on their own,
+// e.g. /trigger-for-price - those aren't operator actions. The actor is the one set with
+// WithActor, else the contracts.ActorHeader the gateway forwards, else Anonymous.
+// It must be added with mux's Router.Use so the matched route is known.
+func Middleware(store *Store, internal ...string) mux.MiddlewareFunc {
+	skip := make(map[string]bool, len(internal))
+	for _, path := range internal {
+		skip[path] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			template := r.URL.Path
+			if route := mux.CurrentRoute(r); route != nil {
+				if t, err := route.GetPathTemplate(); err == nil {
+					template = t
+				}
+			}
+			if skip[template] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var body []byte
+			if r.Body != nil {
+				body, _ = io.ReadAll(r.Body)
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			action := &Action{
+				Actor:      actor(r),
+				Action:     r.Method + " " + template,
+				Path:       r.URL.RequestURI(),
+				Payload:    redact(body),
+				Status:     recorder.status,
+				RemoteAddr: r.RemoteAddr,
+			}
+			if err := store.Append(action); err != nil {
+				log.Printf("ERROR: Failed to log operator action %s by %s: %v", action.Action, action.Actor, err)
+			}
+		})
+	}
+}
+
+func actor(r *http.Request) string {
+	if actor := ActorFrom(r.Context()); actor != "" {
+		return actor
+	}
+	if actor := r.Header.Get(contracts.ActorHeader); actor != "" {
+		return actor
+	}
+	return Anonymous
+}
+
+// redact blanks the sensitive fields of a JSON object body and caps its length
+func redact(body []byte) string {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) == nil {
+		changed := false
+		for key := range fields {
+			lower := strings.ToLower(key)
+			for _, sensitive := range sensitiveFields {
+				if strings.Contains(lower, sensitive) {
+					fields[key] = json.RawMessage(`"[redacted]"`)
+					changed = true
+					break
+				}
+			}
+		}
+		if changed {
+			if redacted, err := json.Marshal(fields); err == nil {
+				body = redacted
+			}
+		}
+	}
+
+	if len(body) > maxPayload {
+		return string(body[:maxPayload]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+// statusRecorder remembers the status a handler answered with
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
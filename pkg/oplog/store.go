@@ -0,0 +1,143 @@
+// Package oplog keeps an append-only log of the manual actions operators take through
+// a service's API - who called what, with which payload and how it was answered - so
+// team-operated deployments can tell who paused a grid or changed a level.
+package oplog
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// schema is created by NewStore in the database of the service using the store.
+// Triggers reject updates and deletes: the log is only ever appended to.
+const schema = `
+CREATE TABLE IF NOT EXISTS operator_actions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor TEXT NOT NULL,            -- token:<name>, gateway-key, api-key or anonymous
+    action TEXT NOT NULL,           -- Method and route, e.g. POST /levels/bulk
+    path TEXT NOT NULL,             -- Path and query as requested
+    payload TEXT NOT NULL DEFAULT '', -- Request body, secrets redacted
+    status INTEGER NOT NULL,        -- HTTP status answered
+    remote_addr TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_operator_actions_created_at ON operator_actions(created_at);
+CREATE INDEX IF NOT EXISTS idx_operator_actions_actor ON operator_actions(actor);
+
+CREATE TRIGGER IF NOT EXISTS operator_actions_no_update
+BEFORE UPDATE ON operator_actions
+BEGIN
+    SELECT RAISE(ABORT, 'operator_actions is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS operator_actions_no_delete
+BEFORE DELETE ON operator_actions
+BEGIN
+    SELECT RAISE(ABORT, 'operator_actions is append-only');
+END;
+`
+
+// Action is one manual API call as it was made and answered
+type Action struct {
+	ID         int       `json:"id"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	Path       string    `json:"path"`
+	Payload    string    `json:"payload,omitempty"`
+	Status     int       `json:"status"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Filter selects logged actions; zero values match everything
+type Filter struct {
+	Actor  string
+	Action string
+	From   time.Time
+	To     time.Time
+	Limit  int
+}
+
+// Store appends operator actions to the operator_actions table and reads them back
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates the operator_actions table in db if needed
+func NewStore(db *sql.DB) (*Store, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to create operator_actions table: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Append records one action
+func (s *Store) Append(action *Action) error {
+	query := `
+		INSERT INTO operator_actions (actor, action, path, payload, status, remote_addr)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := s.db.Exec(query, action.Actor, action.Action, action.Path, action.Payload, action.Status, action.RemoteAddr)
+	if err != nil {
+		return fmt.Errorf("failed to log %s by %s: %w", action.Action, action.Actor, err)
+	}
+	return nil
+}
+
+// List returns the logged actions matching filter, newest first
+func (s *Store) List(filter Filter) ([]*Action, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.Actor != "" {
+		addCondition("actor = $%d", filter.Actor)
+	}
+	if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+	if !filter.From.IsZero() {
+		addCondition("created_at >= $%d", filter.From.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if !filter.To.IsZero() {
+		addCondition("created_at < $%d", filter.To.UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit)
+	query := `
+		SELECT id, actor, action, path, payload, status, remote_addr, created_at
+		FROM operator_actions
+		` + where + `
+		ORDER BY id DESC
+		LIMIT $` + fmt.Sprint(len(args))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query operator actions: %w", err)
+	}
+	defer rows.Close()
+
+	var actions []*Action
+	for rows.Next() {
+		a := &Action{}
+		var createdAt string
+		if err := rows.Scan(&a.ID, &a.Actor, &a.Action, &a.Path, &a.Payload, &a.Status, &a.RemoteAddr, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan operator action: %w", err)
+		}
+		a.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		actions = append(actions, a)
+	}
+	return actions, rows.Err()
+}
@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// HeaderTraceParent carries the caller's span between services (W3C Trace Context)
+const HeaderTraceParent = "traceparent"
+
+// Inject sets the traceparent header of an outgoing request to the span in ctx, if any
+func Inject(ctx context.Context, header http.Header) {
+	if span := SpanFrom(ctx); span != nil {
+		header.Set(HeaderTraceParent, TraceParent(span.ctx))
+	}
+}
+
+// Extract continues the trace of an incoming request's traceparent header, if it has one
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if sc, ok := ParseTraceParent(header.Get(HeaderTraceParent)); ok {
+		return withRemote(ctx, sc)
+	}
+	return ctx
+}
+
+// Middleware wraps every request in a server span continuing the caller's trace, named
+// "METHOD /route/{template}" so IDs in paths don't make every request its own operation.
+// It must be added with mux's Router.Use so the matched route is known.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.URL.Path
+		if current := mux.CurrentRoute(r); current != nil {
+			if t, err := current.GetPathTemplate(); err == nil {
+				route = t
+			}
+		}
+
+		ctx, span := StartKind(Extract(r.Context(), r.Header), r.Method+" "+route, KindServer)
+		defer span.End()
+		span.SetAttr("http.method", r.Method)
+		span.SetAttr("http.route", route)
+		span.SetAttr("http.target", r.URL.Path)
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		span.SetAttr("http.status_code", recorder.status)
+		if recorder.status >= http.StatusInternalServerError {
+			span.RecordError(errStatus(recorder.status))
+		}
+	})
+}
+
+// Transport makes a client span of every request sent through base (http.DefaultTransport if
+// nil) and passes the trace on in its traceparent header. Requests must carry the caller's
+// context (http.NewRequestWithContext) to join its trace.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if SpanFrom(r.Context()) == nil {
+		return t.base.RoundTrip(r) // Not part of a trace - no root span per background call
+	}
+
+	ctx, span := StartKind(r.Context(), r.Method+" "+r.URL.Host+r.URL.Path, KindClient)
+	defer span.End()
+	span.SetAttr("http.method", r.Method)
+	span.SetAttr("http.url", r.URL.Redacted())
+
+	r = r.Clone(ctx)
+	Inject(ctx, r.Header)
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttr("http.status_code", resp.StatusCode)
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.RecordError(errStatus(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+type errStatus int
+
+func (e errStatus) Error() string {
+	return "HTTP " + http.StatusText(int(e))
+}
+
+// statusRecorder remembers the status code a handler wrote
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush keeps streaming responses (CSV downloads) working through the recorder
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,281 @@
+// Package tracing records OpenTelemetry spans of the price tick → trigger → placement chain and
+// carries the trace between services in the W3C traceparent header, without the OTel SDK.
+//
+// Services call Setup first thing in main. With OTEL_EXPORTER_OTLP_ENDPOINT set, finished spans
+// are sent to that collector over OTLP/HTTP JSON (Jaeger, Tempo, the OTel collector all accept
+// it); without it spans are not recorded, but trace IDs are still passed on so a downstream
+// service with an exporter joins the caller's trace.
+//
+//	ctx, span := tracing.Start(ctx, "place buy")
+//	span.SetAttr("level_id", level.ID)
+//	defer span.End()
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Span kinds, as OTLP numbers them
+const (
+	KindInternal = 1
+	KindServer   = 2
+	KindClient   = 3
+)
+
+// SpanContext identifies a span within its trace
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// Valid reports whether the trace and span IDs are set
+func (sc SpanContext) Valid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// Span is one timed operation. A span of an unsampled trace (or with tracing off) records
+// nothing and only carries its IDs on; all methods are safe on it and on nil.
+type Span struct {
+	tracer *Tracer
+	ctx    SpanContext
+	parent [8]byte
+	name   string
+	kind   int
+	start  time.Time
+
+	mu        sync.Mutex
+	end       time.Time
+	attrs     map[string]interface{}
+	errMsg    string
+	failed    bool
+	ended     bool
+	recording bool
+}
+
+// Context returns the span's IDs
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.ctx
+}
+
+// SetAttr records a string, bool, integer or float attribute; anything else is stored as text
+func (s *Span) SetAttr(key string, value interface{}) {
+	if s == nil || !s.recording {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attrs == nil {
+		s.attrs = make(map[string]interface{})
+	}
+	s.attrs[key] = value
+}
+
+// RecordError marks the span failed with err's message; nil errors are ignored
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil || !s.recording {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed = true
+	s.errMsg = err.Error()
+}
+
+// End finishes the span and queues it for export. Only the first call counts.
+func (s *Span) End() {
+	if s == nil || !s.recording {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+	s.tracer.export(s)
+}
+
+type spanKey struct{}
+
+// SpanFrom returns the span of ctx, nil if there is none
+func SpanFrom(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+type remoteKey struct{}
+
+// withRemote stores a caller's span context (from a traceparent header) as the parent of the next span
+func withRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// parentOf returns the span context a new span in ctx continues: its span, else a remote caller's
+func parentOf(ctx context.Context) (SpanContext, bool) {
+	if span := SpanFrom(ctx); span != nil {
+		return span.ctx, true
+	}
+	if sc, ok := ctx.Value(remoteKey{}).(SpanContext); ok && sc.Valid() {
+		return sc, true
+	}
+	return SpanContext{}, false
+}
+
+// Start begins an internal span as a child of the span in ctx, or a new trace without one
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return StartKind(ctx, name, KindInternal)
+}
+
+// StartKind is Start for server and client spans
+func StartKind(ctx context.Context, name string, kind int) (context.Context, *Span) {
+	tracer := global()
+	span := &Span{tracer: tracer, name: name, kind: kind, start: time.Now()}
+	if parent, ok := parentOf(ctx); ok {
+		span.ctx.TraceID = parent.TraceID
+		span.ctx.Sampled = parent.Sampled
+		span.parent = parent.SpanID
+	} else {
+		rand.Read(span.ctx.TraceID[:])
+		span.ctx.Sampled = tracer.sample(span.ctx.TraceID)
+	}
+	rand.Read(span.ctx.SpanID[:])
+	span.recording = span.ctx.Sampled && tracer.exporting()
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Tracer exports the finished spans of one service
+type Tracer struct {
+	service  string
+	endpoint string  // OTLP/HTTP base URL (empty = spans are not recorded)
+	ratio    float64 // Share of new traces sampled
+
+	queue   chan *Span
+	dropped atomic.Int64 // Spans lost to a full queue, reported with the next export
+	mu      sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+var (
+	globalMu     sync.RWMutex
+	globalTracer = &Tracer{ratio: 1}
+)
+
+func global() *Tracer {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return globalTracer
+}
+
+// Setup configures tracing from OTEL_EXPORTER_OTLP_ENDPOINT (empty = off) and
+// OTEL_TRACES_SAMPLER_ARG (share of new traces sampled, 0-1, default 1) and starts the exporter.
+// Call Shutdown on the returned tracer to send what is left before exiting.
+func Setup(service string) *Tracer {
+	ratio := 1.0
+	if v := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			log.Fatal("OTEL_TRACES_SAMPLER_ARG must be between 0 and 1")
+		}
+		ratio = parsed
+	}
+
+	tracer := &Tracer{
+		service:  service,
+		endpoint: strings.TrimRight(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "/"),
+		ratio:    ratio,
+		queue:    make(chan *Span, exportQueueSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if tracer.exporting() {
+		log.Printf("Exporting traces to %s/v1/traces (sampling %.0f%%)", tracer.endpoint, ratio*100)
+		go tracer.run()
+	} else {
+		close(tracer.done)
+	}
+
+	globalMu.Lock()
+	globalTracer = tracer
+	globalMu.Unlock()
+	return tracer
+}
+
+// Shutdown exports the queued spans and stops the exporter
+func (t *Tracer) Shutdown() {
+	if !t.exporting() {
+		return
+	}
+	t.mu.Lock()
+	select {
+	case <-t.stop:
+	default:
+		close(t.stop)
+	}
+	t.mu.Unlock()
+	<-t.done
+}
+
+func (t *Tracer) exporting() bool {
+	return t.endpoint != ""
+}
+
+// sample keeps a new trace with probability ratio, decided from its random trace ID so every
+// service that sees the ID alone would decide the same
+func (t *Tracer) sample(traceID [16]byte) bool {
+	if t.ratio >= 1 {
+		return true
+	}
+	var n uint64
+	for _, b := range traceID[8:] {
+		n = n<<8 | uint64(b)
+	}
+	return float64(n>>11)/float64(1<<53) < t.ratio
+}
+
+// TraceParent formats a span context as a W3C traceparent header value
+func TraceParent(sc SpanContext) string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", hex.EncodeToString(sc.TraceID[:]), hex.EncodeToString(sc.SpanID[:]), flags)
+}
+
+// ParseTraceParent reads a W3C traceparent header value
+func ParseTraceParent(value string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, false
+	}
+
+	var sc SpanContext
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, false
+	}
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil || !sc.Valid() {
+		return SpanContext{}, false
+	}
+	sc.Sampled = flags&1 == 1
+	return sc, true
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/analytics/internal/api"
+	"github.com/grid-trading-bot/services/analytics/internal/client"
+	"github.com/grid-trading-bot/services/analytics/internal/config"
+	"github.com/grid-trading-bot/services/analytics/internal/database"
+	"github.com/grid-trading-bot/services/analytics/internal/repository"
+	"github.com/grid-trading-bot/services/analytics/internal/service"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("No .env file found, using params from environment only.")
+	}
+	logging.Setup("analytics")
+
+	cfg := config.LoadConfig()
+
+	db, err := database.NewConnection(database.Config{Path: cfg.DBPath})
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	migrations := []string{
+		"services/analytics/migrations/001_create_transactions.sql",
+	}
+
+	for _, migrationFile := range migrations {
+		migrationSQL, err := os.ReadFile(migrationFile)
+		if err != nil {
+			log.Fatalf("Failed to read migration file %s: %v", migrationFile, err)
+		}
+
+		if err := database.RunMigrations(db, string(migrationSQL)); err != nil {
+			log.Fatalf("Failed to run migration %s: %v", migrationFile, err)
+		}
+	}
+
+	txRepo := repository.NewTransactionRepository(db)
+
+	// Copy new transactions from grid-trading; reports read only the local copy
+	syncer := service.NewSyncer(client.NewGridTradingClient(cfg.GridTradingURL, cfg.GridTradingKey), txRepo,
+		time.Duration(cfg.SyncInterval)*time.Second, cfg.SyncBatchSize)
+	syncer.Start()
+	defer syncer.Stop()
+
+	handlers := api.NewHandlers(service.NewReportService(txRepo), syncer)
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+
+	srv := &http.Server{
+		Addr:    ":" + cfg.ServerPort,
+		Handler: router,
+	}
+
+	go func() {
+		log.Printf("Analytics starting on port %s (source: %s)", cfg.ServerPort, cfg.GridTradingURL)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Server failed:", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+	if err := srv.Close(); err != nil {
+		log.Printf("Server close error: %v", err)
+	}
+	fmt.Println("Server stopped")
+}
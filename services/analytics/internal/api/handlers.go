@@ -0,0 +1,177 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/services/analytics/internal/models"
+	"github.com/grid-trading-bot/services/analytics/internal/service"
+)
+
+type Handlers struct {
+	reports *service.ReportService
+	syncer  *service.Syncer
+}
+
+func NewHandlers(reports *service.ReportService, syncer *service.Syncer) *Handlers {
+	return &Handlers{reports: reports, syncer: syncer}
+}
+
+func (h *Handlers) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/health", h.handleHealth).Methods("GET")
+	r.HandleFunc("/analytics/profit-by-hour", h.handleProfitByHour).Methods("GET")
+	r.HandleFunc("/analytics/level-heatmap", h.handleLevelHeatmap).Methods("GET")
+	r.HandleFunc("/analytics/fees", h.handleFees).Methods("GET")
+	r.HandleFunc("/analytics/profit-by-level", h.handleProfitByLevel).Methods("GET")
+	r.HandleFunc("/analytics/profit-by-grid", h.handleProfitByGrid).Methods("GET")
+}
+
+func (h *Handlers) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "healthy",
+		"sync":   h.syncer.Status(),
+	})
+}
+
+// handleProfitByHour totals filled sells per UTC hour of the day
+func (h *Handlers) handleProfitByHour(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseFilter(w, r)
+	if !ok {
+		return
+	}
+
+	hours, err := h.reports.ProfitByHour(filter)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to get profit by hour", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"hours": hours})
+}
+
+// handleLevelHeatmap totals fills, cycles, errors and profit per grid level
+func (h *Handlers) handleLevelHeatmap(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseFilter(w, r)
+	if !ok {
+		return
+	}
+
+	levels, err := h.reports.LevelHeatmap(filter)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to get level heatmap", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"levels": levels})
+}
+
+// handleFees breaks fees on fills down by symbol, side and commission asset
+func (h *Handlers) handleFees(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseFilter(w, r)
+	if !ok {
+		return
+	}
+
+	fees, err := h.reports.FeeBreakdown(filter)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to get fee breakdown", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"fees": fees})
+}
+
+// Profit ranking size bounds
+const (
+	defaultTopLevels = 10
+	maxTopLevels     = 1000
+)
+
+// handleProfitByLevel ranks grid levels by realized profit (?limit=, 10 by default)
+func (h *Handlers) handleProfitByLevel(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseFilter(w, r)
+	if !ok {
+		return
+	}
+
+	limit := defaultTopLevels
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxTopLevels {
+			http.Error(w, "Invalid limit (1-1000)", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	levels, err := h.reports.ProfitByLevel(filter, limit)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to get profit by level", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"levels": levels})
+}
+
+// handleProfitByGrid ranks grids (account + symbol) by realized profit
+func (h *Handlers) handleProfitByGrid(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseFilter(w, r)
+	if !ok {
+		return
+	}
+
+	grids, err := h.reports.ProfitByGrid(filter)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to get profit by grid", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"grids": grids})
+}
+
+// parseFilter reads ?symbol=&account=&from=&to=, writing a 400 when a time is invalid
+func parseFilter(w http.ResponseWriter, r *http.Request) (models.Filter, bool) {
+	q := r.URL.Query()
+	filter := models.Filter{
+		Symbol:  strings.ToUpper(q.Get("symbol")),
+		Account: q.Get("account"),
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(q.Get("from")); err != nil {
+		http.Error(w, "Invalid from (RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+		return filter, false
+	}
+	if filter.To, err = parseTimeParam(q.Get("to")); err != nil {
+		http.Error(w, "Invalid to (RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+		return filter, false
+	}
+	return filter, true
+}
+
+// parseTimeParam accepts RFC3339 or a bare date (midnight UTC); empty means unset
+func parseTimeParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+)
+
+type GridTradingClient struct {
+	baseURL    string
+	apiKey     string // Sent as X-API-Key
+	httpClient *http.Client
+}
+
+func NewGridTradingClient(baseURL, apiKey string) *GridTradingClient {
+	return &GridTradingClient{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetTransactions reads up to limit transactions with an ID above afterID, with prices and
+// amounts as stored rather than rounded for display
+func (c *GridTradingClient) GetTransactions(afterID, limit int) (*contracts.TransactionPage, error) {
+	url := fmt.Sprintf("%s/transactions?after_id=%d&limit=%d&raw=true", c.baseURL, afterID, limit)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var page contracts.TransactionPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &page, nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+type Config struct {
+	ServerPort     string
+	DBPath         string
+	GridTradingURL string
+	GridTradingKey string // Shared key grid-trading requires (GRID_TRADING_API_KEY)
+	SyncInterval   int    // Seconds between pulls of new transactions from grid-trading
+	SyncBatchSize  int    // Transactions per page
+}
+
+func LoadConfig() *Config {
+	serverPort := os.Getenv("SERVER_PORT")
+	if serverPort == "" {
+		serverPort = "4040"
+	}
+
+	dbPath := os.Getenv("DB_PATH")
+	if dbPath == "" {
+		dbPath = "./analytics.db"
+	}
+
+	gridTradingURL := os.Getenv("GRID_TRADING_URL")
+	if gridTradingURL == "" {
+		gridTradingURL = "http://localhost:8080"
+	}
+
+	syncInterval := 60
+	if v := os.Getenv("ANALYTICS_SYNC_INTERVAL_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			log.Fatal("ANALYTICS_SYNC_INTERVAL_SEC must be a positive integer")
+		}
+		syncInterval = parsed
+	}
+
+	syncBatchSize := 500
+	if v := os.Getenv("ANALYTICS_SYNC_BATCH_SIZE"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > 5000 {
+			log.Fatal("ANALYTICS_SYNC_BATCH_SIZE must be between 1 and 5000")
+		}
+		syncBatchSize = parsed
+	}
+
+	return &Config{
+		ServerPort:     serverPort,
+		DBPath:         dbPath,
+		GridTradingURL: gridTradingURL,
+		GridTradingKey: os.Getenv("GRID_TRADING_API_KEY"),
+		SyncInterval:   syncInterval,
+		SyncBatchSize:  syncBatchSize,
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Filter narrows a report to one symbol/account and a [From, To) time range (zero = unbounded)
+type Filter struct {
+	Symbol  string
+	Account string
+	From    time.Time
+	To      time.Time
+}
+
+// HourProfit totals the filled sells that completed in one UTC hour of the day
+type HourProfit struct {
+	Hour       int             `json:"hour"` // 0-23, UTC
+	Sells      int             `json:"sells"`
+	ProfitUSDT decimal.Decimal `json:"profit_usdt"`
+	AvgProfit  decimal.Decimal `json:"avg_profit_usdt"`
+}
+
+// LevelHeat is the activity of one grid level: fills, cycles, errors and profit
+type LevelHeat struct {
+	GridLevelID int             `json:"grid_level_id"`
+	Account     string          `json:"account,omitempty"`
+	Symbol      string          `json:"symbol"`
+	BuyPrice    decimal.Decimal `json:"buy_price"`
+	SellPrice   decimal.Decimal `json:"sell_price"`
+	Buys        int             `json:"buys"`
+	Sells       int             `json:"sells"` // Completed cycles
+	Cancels     int             `json:"cancels"`
+	Errors      int             `json:"errors"`
+	ProfitUSDT  decimal.Decimal `json:"profit_usdt"`
+	FeesUSDT    decimal.Decimal `json:"fees_usdt"`
+}
+
+// LevelProfit is the realized profit of one grid level's completed cycles
+type LevelProfit struct {
+	GridLevelID int             `json:"grid_level_id"`
+	Account     string          `json:"account,omitempty"`
+	Symbol      string          `json:"symbol"`
+	BuyPrice    decimal.Decimal `json:"buy_price"`
+	SellPrice   decimal.Decimal `json:"sell_price"`
+	Cycles      int             `json:"cycles"` // Filled sells
+	ProfitUSDT  decimal.Decimal `json:"profit_usdt"`
+	AvgProfit   decimal.Decimal `json:"avg_profit_usdt"`
+}
+
+// GridProfit is the realized profit of one grid - the levels of a symbol on one account
+type GridProfit struct {
+	Account    string          `json:"account,omitempty"`
+	Symbol     string          `json:"symbol"`
+	Levels     int             `json:"levels"` // Levels with at least one completed cycle
+	Cycles     int             `json:"cycles"`
+	ProfitUSDT decimal.Decimal `json:"profit_usdt"`
+	AvgProfit  decimal.Decimal `json:"avg_profit_usdt"`
+}
+
+// FeeBreakdown totals fees on fills by symbol, side and commission asset
+type FeeBreakdown struct {
+	Symbol        string          `json:"symbol"`
+	Side          string          `json:"side"`
+	Asset         string          `json:"asset"`
+	Fills         int             `json:"fills"`
+	Commission    decimal.Decimal `json:"commission"`
+	FeeUSDT       decimal.Decimal `json:"fee_usdt"`
+	UnpricedFills int             `json:"unpriced_fills"` // Fills whose fee could not be valued in USDT
+}
+
+// SyncStatus describes how far the local copy of the transaction log has caught up
+type SyncStatus struct {
+	LastID     int        `json:"last_transaction_id"`
+	LastSyncAt *time.Time `json:"last_sync_at,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
+}
@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/analytics/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type TransactionRepository struct {
+	db *sql.DB
+}
+
+func NewTransactionRepository(db *sql.DB) *TransactionRepository {
+	return &TransactionRepository{db: db}
+}
+
+// LastID returns the highest transaction ID copied so far (0 when empty)
+func (r *TransactionRepository) LastID() (int, error) {
+	var lastID int
+	err := r.db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM transactions`).Scan(&lastID)
+	return lastID, err
+}
+
+// InsertBatch copies a page of transactions in one database transaction. Transactions are
+// never updated in grid-trading, so a row already copied is skipped.
+func (r *TransactionRepository) InsertBatch(records []contracts.TransactionRecord) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO transactions (
+			id, grid_level_id, account, symbol, level_buy_price, level_sell_price,
+			side, status, order_id, target_price, executed_price,
+			amount_coin, amount_usdt, profit_usdt,
+			commission, commission_asset, fee_usdt, error_code, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	for _, rec := range records {
+		_, err := tx.Exec(
+			query,
+			rec.ID, rec.GridLevelID, rec.Account, rec.Symbol, rec.LevelBuyPrice, rec.LevelSellPrice,
+			rec.Side, rec.Status, nullString(rec.OrderID), rec.TargetPrice, nullDecimal(rec.ExecutedPrice),
+			nullDecimal(rec.AmountCoin), nullDecimal(rec.AmountUSDT), nullDecimal(rec.ProfitUSDT),
+			nullDecimal(rec.Commission), nullString(rec.CommissionAsset), nullDecimal(rec.FeeUSDT),
+			nullString(rec.ErrorCode), rec.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert transaction %d: %w", rec.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ProfitByHour totals filled sells per UTC hour of the day; every hour is present
+func (r *TransactionRepository) ProfitByHour(filter models.Filter) ([]models.HourProfit, error) {
+	where, args := filterClause(filter, "side = 'SELL' AND status = 'FILLED'")
+	query := `
+		SELECT CAST(strftime('%H', created_at) AS INTEGER), profit_usdt
+		FROM transactions
+		` + where
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hours := make([]models.HourProfit, 24)
+	for i := range hours {
+		hours[i].Hour = i
+	}
+	for rows.Next() {
+		var hour int
+		var profit decimal.NullDecimal
+		if err := rows.Scan(&hour, &profit); err != nil {
+			return nil, err
+		}
+		if hour < 0 || hour > 23 {
+			continue
+		}
+		hours[hour].Sells++
+		hours[hour].ProfitUSDT = hours[hour].ProfitUSDT.Add(profit.Decimal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range hours {
+		if hours[i].Sells > 0 {
+			hours[i].AvgProfit = hours[i].ProfitUSDT.Div(decimal.NewFromInt(int64(hours[i].Sells))).Round(8)
+		}
+	}
+	return hours, nil
+}
+
+// LevelHeatmap totals activity per grid level, ordered by symbol and buy price
+func (r *TransactionRepository) LevelHeatmap(filter models.Filter) ([]*models.LevelHeat, error) {
+	where, args := filterClause(filter, "")
+	query := `
+		SELECT grid_level_id, account, symbol, level_buy_price, level_sell_price,
+		       side, status, profit_usdt, fee_usdt
+		FROM transactions
+		` + where + `
+		ORDER BY grid_level_id
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels []*models.LevelHeat
+	for rows.Next() {
+		var levelID int
+		var account, symbol, side, status string
+		var buyPrice, sellPrice decimal.Decimal
+		var profit, fee decimal.NullDecimal
+		if err := rows.Scan(&levelID, &account, &symbol, &buyPrice, &sellPrice, &side, &status, &profit, &fee); err != nil {
+			return nil, err
+		}
+
+		if n := len(levels); n == 0 || levels[n-1].GridLevelID != levelID {
+			levels = append(levels, &models.LevelHeat{
+				GridLevelID: levelID, Account: account, Symbol: symbol, BuyPrice: buyPrice, SellPrice: sellPrice,
+			})
+		}
+		level := levels[len(levels)-1]
+
+		switch status {
+		case "FILLED":
+			if side == "BUY" {
+				level.Buys++
+			} else {
+				level.Sells++
+				level.ProfitUSDT = level.ProfitUSDT.Add(profit.Decimal)
+			}
+			level.FeesUSDT = level.FeesUSDT.Add(fee.Decimal)
+		case "CANCELLED":
+			level.Cancels++
+		case "ERROR":
+			level.Errors++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		if levels[i].Symbol != levels[j].Symbol {
+			return levels[i].Symbol < levels[j].Symbol
+		}
+		return levels[i].BuyPrice.LessThan(levels[j].BuyPrice)
+	})
+	return levels, nil
+}
+
+// ProfitByLevel totals filled sells per grid level, most profitable first
+// (ties by symbol and buy price, so the order is stable)
+func (r *TransactionRepository) ProfitByLevel(filter models.Filter) ([]*models.LevelProfit, error) {
+	where, args := filterClause(filter, "side = 'SELL' AND status = 'FILLED'")
+	query := `
+		SELECT grid_level_id, account, symbol, level_buy_price, level_sell_price, profit_usdt
+		FROM transactions
+		` + where + `
+		ORDER BY grid_level_id
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels []*models.LevelProfit
+	for rows.Next() {
+		var levelID int
+		var account, symbol string
+		var buyPrice, sellPrice decimal.Decimal
+		var profit decimal.NullDecimal
+		if err := rows.Scan(&levelID, &account, &symbol, &buyPrice, &sellPrice, &profit); err != nil {
+			return nil, err
+		}
+
+		if n := len(levels); n == 0 || levels[n-1].GridLevelID != levelID {
+			levels = append(levels, &models.LevelProfit{
+				GridLevelID: levelID, Account: account, Symbol: symbol, BuyPrice: buyPrice, SellPrice: sellPrice,
+			})
+		}
+		level := levels[len(levels)-1]
+		level.Cycles++
+		level.ProfitUSDT = level.ProfitUSDT.Add(profit.Decimal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, level := range levels {
+		level.AvgProfit = level.ProfitUSDT.Div(decimal.NewFromInt(int64(level.Cycles))).Round(8)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		if !levels[i].ProfitUSDT.Equal(levels[j].ProfitUSDT) {
+			return levels[i].ProfitUSDT.GreaterThan(levels[j].ProfitUSDT)
+		}
+		if levels[i].Symbol != levels[j].Symbol {
+			return levels[i].Symbol < levels[j].Symbol
+		}
+		return levels[i].BuyPrice.LessThan(levels[j].BuyPrice)
+	})
+	return levels, nil
+}
+
+// FeeBreakdown totals fees on fills by symbol, side and commission asset
+func (r *TransactionRepository) FeeBreakdown(filter models.Filter) ([]*models.FeeBreakdown, error) {
+	where, args := filterClause(filter, "status = 'FILLED'")
+	query := `
+		SELECT symbol, side, COALESCE(commission_asset, ''), commission, fee_usdt
+		FROM transactions
+		` + where + `
+		ORDER BY symbol, side, commission_asset
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.FeeBreakdown
+	for rows.Next() {
+		var symbol, side, asset string
+		var commission, fee decimal.NullDecimal
+		if err := rows.Scan(&symbol, &side, &asset, &commission, &fee); err != nil {
+			return nil, err
+		}
+
+		n := len(result)
+		if n == 0 || result[n-1].Symbol != symbol || result[n-1].Side != side || result[n-1].Asset != asset {
+			result = append(result, &models.FeeBreakdown{Symbol: symbol, Side: side, Asset: asset})
+		}
+		breakdown := result[len(result)-1]
+		breakdown.Fills++
+		breakdown.Commission = breakdown.Commission.Add(commission.Decimal)
+		if fee.Valid {
+			breakdown.FeeUSDT = breakdown.FeeUSDT.Add(fee.Decimal)
+		} else if asset != "" {
+			breakdown.UnpricedFills++
+		}
+	}
+
+	return result, rows.Err()
+}
+
+// filterClause builds the WHERE clause for filter, plus an optional fixed condition
+func filterClause(filter models.Filter, fixed string) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if fixed != "" {
+		conditions = append(conditions, fixed)
+	}
+	if filter.Symbol != "" {
+		addCondition("symbol = $%d", filter.Symbol)
+	}
+	if filter.Account != "" {
+		addCondition("account = $%d", filter.Account)
+	}
+	if !filter.From.IsZero() {
+		addCondition("created_at >= $%d", filter.From.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if !filter.To.IsZero() {
+		addCondition("created_at < $%d", filter.To.UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullDecimal(d *decimal.Decimal) decimal.NullDecimal {
+	if d == nil {
+		return decimal.NullDecimal{}
+	}
+	return decimal.NullDecimal{Decimal: *d, Valid: true}
+}
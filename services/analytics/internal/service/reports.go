@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/grid-trading-bot/services/analytics/internal/models"
+	"github.com/grid-trading-bot/services/analytics/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// ReportService answers report queries from the local copy of the transaction log
+type ReportService struct {
+	repo *repository.TransactionRepository
+}
+
+func NewReportService(repo *repository.TransactionRepository) *ReportService {
+	return &ReportService{repo: repo}
+}
+
+func (s *ReportService) ProfitByHour(filter models.Filter) ([]models.HourProfit, error) {
+	hours, err := s.repo.ProfitByHour(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profit by hour: %w", err)
+	}
+	return hours, nil
+}
+
+func (s *ReportService) LevelHeatmap(filter models.Filter) ([]*models.LevelHeat, error) {
+	levels, err := s.repo.LevelHeatmap(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get level heatmap: %w", err)
+	}
+	if levels == nil {
+		levels = []*models.LevelHeat{}
+	}
+	return levels, nil
+}
+
+func (s *ReportService) FeeBreakdown(filter models.Filter) ([]*models.FeeBreakdown, error) {
+	fees, err := s.repo.FeeBreakdown(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee breakdown: %w", err)
+	}
+	if fees == nil {
+		fees = []*models.FeeBreakdown{}
+	}
+	return fees, nil
+}
+
+// ProfitByLevel returns the most profitable levels first, at most limit of them (0 = all)
+func (s *ReportService) ProfitByLevel(filter models.Filter, limit int) ([]*models.LevelProfit, error) {
+	levels, err := s.repo.ProfitByLevel(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profit by level: %w", err)
+	}
+	if limit > 0 && len(levels) > limit {
+		levels = levels[:limit]
+	}
+	if levels == nil {
+		levels = []*models.LevelProfit{}
+	}
+	return levels, nil
+}
+
+// ProfitByGrid totals the levels' profit per account and symbol, most profitable grid first
+func (s *ReportService) ProfitByGrid(filter models.Filter) ([]*models.GridProfit, error) {
+	levels, err := s.repo.ProfitByLevel(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profit by grid: %w", err)
+	}
+
+	type gridKey struct{ account, symbol string }
+	byGrid := make(map[gridKey]*models.GridProfit)
+	grids := []*models.GridProfit{}
+	for _, level := range levels {
+		key := gridKey{level.Account, level.Symbol}
+		grid, ok := byGrid[key]
+		if !ok {
+			grid = &models.GridProfit{Account: level.Account, Symbol: level.Symbol}
+			byGrid[key] = grid
+			grids = append(grids, grid)
+		}
+		grid.Levels++
+		grid.Cycles += level.Cycles
+		grid.ProfitUSDT = grid.ProfitUSDT.Add(level.ProfitUSDT)
+	}
+
+	for _, grid := range grids {
+		grid.AvgProfit = grid.ProfitUSDT.Div(decimal.NewFromInt(int64(grid.Cycles))).Round(8)
+	}
+	sort.Slice(grids, func(i, j int) bool {
+		if !grids[i].ProfitUSDT.Equal(grids[j].ProfitUSDT) {
+			return grids[i].ProfitUSDT.GreaterThan(grids[j].ProfitUSDT)
+		}
+		if grids[i].Symbol != grids[j].Symbol {
+			return grids[i].Symbol < grids[j].Symbol
+		}
+		return grids[i].Account < grids[j].Account
+	})
+	return grids, nil
+}
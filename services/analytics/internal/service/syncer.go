@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/services/analytics/internal/client"
+	"github.com/grid-trading-bot/services/analytics/internal/models"
+	"github.com/grid-trading-bot/services/analytics/internal/repository"
+)
+
+// Syncer copies new grid-trading transactions into the local database, page by page in ID
+// order. Reports only read the local copy, so heavy queries never touch the trading DB.
+type Syncer struct {
+	grid      *client.GridTradingClient
+	repo      *repository.TransactionRepository
+	interval  time.Duration
+	batchSize int
+
+	mu     sync.Mutex
+	status models.SyncStatus
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewSyncer(grid *client.GridTradingClient, repo *repository.TransactionRepository, interval time.Duration, batchSize int) *Syncer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Syncer{
+		grid:      grid,
+		repo:      repo,
+		interval:  interval,
+		batchSize: batchSize,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+func (s *Syncer) Start() {
+	log.Printf("Starting transaction sync (every %s, %d per page)", s.interval, s.batchSize)
+	s.wg.Add(1)
+	go s.loop()
+}
+
+func (s *Syncer) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Status reports the last copied transaction and the outcome of the last sync
+func (s *Syncer) Status() models.SyncStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (s *Syncer) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.sync()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.sync()
+		}
+	}
+}
+
+// sync pulls pages until grid-trading has nothing newer
+func (s *Syncer) sync() {
+	lastID, err := s.repo.LastID()
+	if err != nil {
+		log.Printf("ERROR: Failed to read last synced transaction: %v", err)
+		s.record(0, err)
+		return
+	}
+
+	copied := 0
+	for s.ctx.Err() == nil {
+		page, err := s.grid.GetTransactions(lastID, s.batchSize)
+		if err != nil {
+			log.Printf("WARNING: Failed to fetch transactions after %d: %v", lastID, err)
+			s.record(lastID, err)
+			return
+		}
+		if len(page.Transactions) == 0 {
+			break
+		}
+
+		if err := s.repo.InsertBatch(page.Transactions); err != nil {
+			log.Printf("ERROR: Failed to store transactions after %d: %v", lastID, err)
+			s.record(lastID, err)
+			return
+		}
+		copied += len(page.Transactions)
+		lastID = page.NextAfterID
+
+		if len(page.Transactions) < s.batchSize {
+			break
+		}
+	}
+
+	if copied > 0 {
+		log.Printf("Synced %d transactions (up to #%d)", copied, lastID)
+	}
+	s.record(lastID, nil)
+}
+
+func (s *Syncer) record(lastID int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	if lastID > 0 {
+		s.status.LastID = lastID
+	}
+	s.status.LastSyncAt = &now
+	s.status.LastError = ""
+	if err != nil {
+		s.status.LastError = err.Error()
+	}
+}
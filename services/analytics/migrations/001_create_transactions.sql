@@ -0,0 +1,26 @@
+-- Copy of grid-trading's transaction log, pulled incrementally by ID (GET /transactions)
+CREATE TABLE IF NOT EXISTS transactions (
+    id INTEGER PRIMARY KEY,          -- grid-trading's transaction ID
+    grid_level_id INTEGER NOT NULL,
+    account TEXT NOT NULL DEFAULT '', -- Sub-account of the level (empty = master)
+    symbol TEXT NOT NULL,
+    level_buy_price TEXT NOT NULL,
+    level_sell_price TEXT NOT NULL,
+    side TEXT NOT NULL,              -- BUY | SELL
+    status TEXT NOT NULL,            -- PLACED | FILLED | CANCELLED | ERROR
+    order_id TEXT,
+    target_price TEXT NOT NULL,
+    executed_price TEXT,
+    amount_coin TEXT,
+    amount_usdt TEXT,
+    profit_usdt TEXT,                -- Filled sells only
+    commission TEXT,
+    commission_asset TEXT,
+    fee_usdt TEXT,                   -- NULL if the fee could not be valued in USDT
+    error_code TEXT,
+    created_at TEXT NOT NULL         -- UTC, as recorded by grid-trading
+);
+
+CREATE INDEX IF NOT EXISTS idx_tx_created ON transactions(created_at);
+CREATE INDEX IF NOT EXISTS idx_tx_symbol_status ON transactions(symbol, status);
+CREATE INDEX IF NOT EXISTS idx_tx_grid_level ON transactions(grid_level_id);
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/gateway/internal/api"
+	"github.com/grid-trading-bot/services/gateway/internal/config"
+	"github.com/grid-trading-bot/services/gateway/internal/proxy"
+)
+
+func main() {
+	logging.Setup("gateway")
+	cfg := config.LoadConfig()
+
+	upstreamConfigs := []struct {
+		name, prefix, url, statusPath, apiKey string
+	}{
+		{"grid-trading", "/grid", cfg.GridTradingURL, "/status", cfg.GridTradingAPIKey},
+		{"order-assurance", "/assurance", cfg.OrderAssuranceURL, "/circuit-breakers", cfg.OrderAssuranceAPIKey},
+		{"price-monitor", "/monitor", cfg.PriceMonitorURL, "/status", cfg.PriceMonitorAPIKey},
+	}
+
+	upstreams := make([]*proxy.Upstream, 0, len(upstreamConfigs))
+	var assurance *proxy.Upstream
+	for _, uc := range upstreamConfigs {
+		upstream, err := proxy.NewUpstream(uc.name, uc.prefix, uc.url, uc.statusPath, uc.apiKey)
+		if err != nil {
+			log.Fatalf("Failed to configure upstream: %v", err)
+		}
+		upstreams = append(upstreams, upstream)
+		if uc.name == "order-assurance" {
+			assurance = upstream
+		}
+		log.Printf("Routing %s/* to %s at %s", uc.prefix, uc.name, uc.url)
+	}
+
+	handlers := api.NewHandlers(upstreams)
+
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+
+	if cfg.RateLimitRPS > 0 {
+		router.Use(api.NewRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst).Middleware)
+		log.Printf("Rate limiting enabled - %.1f req/s per client, burst %d", cfg.RateLimitRPS, cfg.RateLimitBurst)
+	}
+
+	if cfg.APIKey != "" {
+		router.Use(api.APIKeyMiddleware(cfg.APIKey, api.NewTokenVerifier(assurance)))
+		log.Println("API key authentication enabled (gateway key or API tokens managed in order-assurance)")
+	} else {
+		log.Println("WARNING: GATEWAY_API_KEY not set - the gateway is unauthenticated")
+	}
+
+	srv := &http.Server{
+		Addr:    ":" + cfg.ServerPort,
+		Handler: router,
+	}
+
+	go func() {
+		log.Printf("Gateway starting on port %s", cfg.ServerPort)
+
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Server failed:", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+
+	if err := srv.Close(); err != nil {
+		log.Printf("Server close error: %v", err)
+	}
+
+	fmt.Println("Server stopped")
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/services/gateway/internal/proxy"
+)
+
+type Handlers struct {
+	upstreams []*proxy.Upstream
+}
+
+func NewHandlers(upstreams []*proxy.Upstream) *Handlers {
+	return &Handlers{upstreams: upstreams}
+}
+
+func (h *Handlers) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/health", h.handleHealth).Methods("GET")
+	r.HandleFunc("/status", h.handleStatus).Methods("GET")
+
+	// Everything else goes to the service owning the prefix, e.g. /grid/levels -> grid-trading /levels
+	for _, upstream := range h.upstreams {
+		r.PathPrefix(upstream.Prefix + "/").Handler(upstream)
+	}
+}
+
+type ServiceHealth struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	Status   string                   `json:"status"`
+	Services map[string]ServiceHealth `json:"services"`
+}
+
+// handleHealth is healthy only if every service is - 503 otherwise, naming the ones that are down
+func (h *Handlers) handleHealth(w http.ResponseWriter, r *http.Request) {
+	results := h.fanOut(func(upstream *proxy.Upstream) (json.RawMessage, error) {
+		return upstream.Get("/health")
+	})
+
+	response := HealthResponse{Status: "healthy", Services: make(map[string]ServiceHealth, len(results))}
+	for name, result := range results {
+		if result.err != nil {
+			response.Status = "unhealthy"
+			response.Services[name] = ServiceHealth{Status: "unhealthy", Error: result.err.Error()}
+			continue
+		}
+		response.Services[name] = ServiceHealth{Status: "healthy"}
+	}
+
+	status := http.StatusOK
+	if response.Status != "healthy" {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleStatus merges each service's status endpoint into one document keyed by service name
+func (h *Handlers) handleStatus(w http.ResponseWriter, r *http.Request) {
+	results := h.fanOut(func(upstream *proxy.Upstream) (json.RawMessage, error) {
+		return upstream.Get(upstream.StatusPath)
+	})
+
+	response := make(map[string]interface{}, len(results))
+	for name, result := range results {
+		if result.err != nil {
+			response[name] = map[string]string{"error": result.err.Error()}
+			continue
+		}
+		response[name] = result.body
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+type upstreamResult struct {
+	body json.RawMessage
+	err  error
+}
+
+// fanOut calls every upstream concurrently
+func (h *Handlers) fanOut(call func(*proxy.Upstream) (json.RawMessage, error)) map[string]upstreamResult {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]upstreamResult, len(h.upstreams))
+	)
+
+	for _, upstream := range h.upstreams {
+		wg.Add(1)
+		go func(upstream *proxy.Upstream) {
+			defer wg.Done()
+			body, err := call(upstream)
+
+			mu.Lock()
+			results[upstream.Name] = upstreamResult{body: body, err: err}
+			mu.Unlock()
+		}(upstream)
+	}
+
+	wg.Wait()
+	return results
+}
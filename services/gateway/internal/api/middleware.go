@@ -0,0 +1,157 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/oplog"
+	"github.com/grid-trading-bot/services/gateway/internal/proxy"
+)
+
+// APIKeyMiddleware rejects requests without the gateway API key or an active API token
+// (verified by order-assurance) with the scope the request needs.
+// Health checks stay open so orchestrators can probe the gateway. The caller is passed on to
+// the services as the request's actor (gateway-key or token:<name>) for their operator logs.
+func APIKeyMiddleware(apiKey string, tokens *TokenVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			provided := r.Header.Get(proxy.APIKeyHeader)
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) == 1 {
+				next.ServeHTTP(w, r.WithContext(oplog.WithActor(r.Context(), "gateway-key")))
+				return
+			}
+
+			if provided == "" {
+				log.Printf("WARNING: Rejected unauthenticated request %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+				writeError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			token, err := tokens.Verify(provided)
+			if err != nil {
+				log.Printf("ERROR: Failed to verify API token: %v", err)
+				writeError(w, http.StatusServiceUnavailable, "token verification unavailable")
+				return
+			}
+			if !token.Valid {
+				log.Printf("WARNING: Rejected unauthenticated request %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+				writeError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if required := contracts.RequiredScope(r.Method, servicePath(r.URL.Path)); !contracts.HasScope(token.Scopes, required) {
+				log.Printf("WARNING: Token %q lacks %s scope for %s %s", token.Name, required, r.Method, r.URL.Path)
+				writeError(w, http.StatusForbidden, "token lacks the "+required+" scope")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(oplog.WithActor(r.Context(), "token:"+token.Name)))
+		})
+	}
+}
+
+// servicePath drops the routing prefix, e.g. /assurance/tokens -> /tokens
+func servicePath(path string) string {
+	if _, rest, ok := strings.Cut(strings.TrimPrefix(path, "/"), "/"); ok {
+		return "/" + rest
+	}
+	return path
+}
+
+// bucketIdleTTL is how long an unused client bucket is kept - a full bucket is no different from a new one
+const bucketIdleTTL = 10 * time.Minute
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// RateLimiter is a token bucket per client IP
+type RateLimiter struct {
+	rps   float64
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rps:       rps,
+		burst:     float64(burst),
+		buckets:   make(map[string]*bucket),
+		lastPrune: time.Now(),
+	}
+}
+
+// Allow takes a token from the client's bucket, refilling it for the time since its last request
+func (l *RateLimiter) Allow(client string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastPrune) > bucketIdleTTL {
+		for key, b := range l.buckets {
+			if now.Sub(b.lastSeen) > bucketIdleTTL {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: l.burst}
+		l.buckets[client] = b
+	} else {
+		b.tokens += now.Sub(b.lastSeen).Seconds() * l.rps
+		if b.tokens > l.burst {
+			b.tokens = l.burst
+		}
+	}
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Middleware answers 429 once a client runs out of tokens
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+
+		if !l.Allow(client) {
+			log.Printf("WARNING: Rate limited %s %s from %s", r.Method, r.URL.Path, client)
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/gateway/internal/proxy"
+)
+
+const (
+	// tokenCacheTTL bounds how long a revoked token keeps working at the gateway
+	tokenCacheTTL  = 30 * time.Second
+	tokenCacheSize = 1000
+)
+
+type cachedToken struct {
+	result  contracts.TokenVerifyResponse
+	expires time.Time
+}
+
+// TokenVerifier checks client tokens against order-assurance, which owns the token store,
+// caching answers briefly so every proxied request doesn't cost a verification call
+type TokenVerifier struct {
+	assurance *proxy.Upstream
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]cachedToken
+}
+
+func NewTokenVerifier(assurance *proxy.Upstream) *TokenVerifier {
+	return &TokenVerifier{
+		assurance: assurance,
+		cache:     make(map[[sha256.Size]byte]cachedToken),
+	}
+}
+
+// Verify returns what order-assurance knows about token. Errors mean it couldn't be asked.
+func (v *TokenVerifier) Verify(token string) (contracts.TokenVerifyResponse, error) {
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+
+	v.mu.Lock()
+	cached, ok := v.cache[key]
+	v.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.result, nil
+	}
+
+	body, err := v.assurance.Post("/tokens/verify", contracts.TokenVerifyRequest{Token: token})
+	if err != nil {
+		return contracts.TokenVerifyResponse{}, err
+	}
+
+	var result contracts.TokenVerifyResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return contracts.TokenVerifyResponse{}, fmt.Errorf("invalid token verification response: %w", err)
+	}
+
+	v.mu.Lock()
+	if len(v.cache) >= tokenCacheSize {
+		for k, c := range v.cache {
+			if now.After(c.expires) {
+				delete(v.cache, k)
+			}
+		}
+		// Still full of live entries - start over rather than grow without bound
+		if len(v.cache) >= tokenCacheSize {
+			v.cache = make(map[[sha256.Size]byte]cachedToken)
+		}
+	}
+	v.cache[key] = cachedToken{result: result, expires: now.Add(tokenCacheTTL)}
+	v.mu.Unlock()
+
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+type Config struct {
+	ServerPort string
+
+	GridTradingURL    string
+	OrderAssuranceURL string
+	PriceMonitorURL   string
+
+	// Key clients must send to the gateway (empty = open)
+	APIKey string
+	// Shared key the gateway forwards to order-assurance
+	OrderAssuranceAPIKey string
+	// Shared keys the gateway forwards to grid-trading and price-monitor
+	GridTradingAPIKey  string
+	PriceMonitorAPIKey string
+
+	// Per-client token bucket (0 rps = no limit)
+	RateLimitRPS   float64
+	RateLimitBurst int
+}
+
+func LoadConfig() *Config {
+	serverPort := os.Getenv("SERVER_PORT")
+	if serverPort == "" {
+		serverPort = "8000"
+	}
+
+	gridTradingURL := os.Getenv("GRID_TRADING_URL")
+	if gridTradingURL == "" {
+		gridTradingURL = "http://localhost:8080"
+	}
+
+	orderAssuranceURL := os.Getenv("ORDER_ASSURANCE_URL")
+	if orderAssuranceURL == "" {
+		orderAssuranceURL = "http://localhost:9090"
+	}
+
+	priceMonitorURL := os.Getenv("PRICE_MONITOR_URL")
+	if priceMonitorURL == "" {
+		priceMonitorURL = "http://localhost:7070"
+	}
+
+	rateLimitRPS := 10.0
+	if v := os.Getenv("GATEWAY_RATE_LIMIT_RPS"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 {
+			log.Fatalf("Invalid GATEWAY_RATE_LIMIT_RPS %q: must be a non-negative number", v)
+		}
+		rateLimitRPS = parsed
+	}
+
+	rateLimitBurst := 20
+	if v := os.Getenv("GATEWAY_RATE_LIMIT_BURST"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			log.Fatalf("Invalid GATEWAY_RATE_LIMIT_BURST %q: must be a positive integer", v)
+		}
+		rateLimitBurst = parsed
+	}
+
+	return &Config{
+		ServerPort:           serverPort,
+		GridTradingURL:       gridTradingURL,
+		OrderAssuranceURL:    orderAssuranceURL,
+		PriceMonitorURL:      priceMonitorURL,
+		APIKey:               os.Getenv("GATEWAY_API_KEY"),
+		OrderAssuranceAPIKey: os.Getenv("ORDER_ASSURANCE_API_KEY"),
+		GridTradingAPIKey:    os.Getenv("GRID_TRADING_API_KEY"),
+		PriceMonitorAPIKey:   os.Getenv("PRICE_MONITOR_API_KEY"),
+		RateLimitRPS:         rateLimitRPS,
+		RateLimitBurst:       rateLimitBurst,
+	}
+}
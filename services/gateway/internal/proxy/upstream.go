@@ -0,0 +1,130 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/oplog"
+)
+
+// APIKeyHeader carries API keys both from clients and to order-assurance
+const APIKeyHeader = "X-API-Key"
+
+// Upstream is one backend service reachable under a path prefix of the gateway
+type Upstream struct {
+	Name       string
+	Prefix     string // e.g. /grid - stripped before forwarding
+	StatusPath string // Endpoint aggregated into the gateway's /status
+
+	target *url.URL
+	apiKey string
+	proxy  *httputil.ReverseProxy
+	client *http.Client
+}
+
+// NewUpstream creates an upstream. apiKey, if set, replaces whatever key the client sent.
+func NewUpstream(name, prefix, baseURL, statusPath, apiKey string) (*Upstream, error) {
+	target, err := url.Parse(baseURL)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid %s URL %q", name, baseURL)
+	}
+
+	u := &Upstream{
+		Name:       name,
+		Prefix:     prefix,
+		StatusPath: statusPath,
+		target:     target,
+		apiKey:     apiKey,
+		client:     &http.Client{Timeout: 5 * time.Second},
+	}
+	u.proxy = &httputil.ReverseProxy{
+		Rewrite:      u.rewrite,
+		ErrorHandler: u.proxyError,
+	}
+	return u, nil
+}
+
+// ServeHTTP forwards a request under the upstream's prefix
+func (u *Upstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.proxy.ServeHTTP(w, r)
+}
+
+func (u *Upstream) rewrite(pr *httputil.ProxyRequest) {
+	pr.Out.URL.Path = strings.TrimPrefix(pr.In.URL.Path, u.Prefix)
+	pr.Out.URL.RawPath = ""
+	pr.SetURL(u.target)
+	pr.SetXForwarded()
+
+	// The gateway key is only for the gateway - never leak it to a service
+	pr.Out.Header.Del(APIKeyHeader)
+	if u.apiKey != "" {
+		pr.Out.Header.Set(APIKeyHeader, u.apiKey)
+	}
+
+	// Only the actor the gateway verified reaches the services - never one the client sent
+	pr.Out.Header.Del(contracts.ActorHeader)
+	if actor := oplog.ActorFrom(pr.In.Context()); actor != "" {
+		pr.Out.Header.Set(contracts.ActorHeader, actor)
+	}
+}
+
+func (u *Upstream) proxyError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("ERROR: %s unreachable for %s %s: %v", u.Name, r.Method, r.URL.Path, err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadGateway)
+	json.NewEncoder(w).Encode(map[string]string{"error": u.Name + " unavailable"})
+}
+
+// Get calls an upstream endpoint directly, returning the raw JSON body
+func (u *Upstream) Get(path string) (json.RawMessage, error) {
+	return u.call(http.MethodGet, path, nil)
+}
+
+// Post sends payload as JSON to an upstream endpoint, returning the raw JSON body
+func (u *Upstream) Post(path string, payload interface{}) (json.RawMessage, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return u.call(http.MethodPost, path, bytes.NewReader(body))
+}
+
+func (u *Upstream) call(method, path string, body io.Reader) (json.RawMessage, error) {
+	req, err := http.NewRequest(method, u.target.String()+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if u.apiKey != "" {
+		req.Header.Set(APIKeyHeader, u.apiKey)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", u.Name, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", u.Name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", u.Name, resp.StatusCode)
+	}
+	if !json.Valid(respBody) {
+		return nil, fmt.Errorf("%s returned invalid JSON", u.Name)
+	}
+	return respBody, nil
+}
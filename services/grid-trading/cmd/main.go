@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/klines"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/pkg/natsjs"
+	"github.com/grid-trading-bot/pkg/oplog"
+	"github.com/grid-trading-bot/pkg/redis"
+	"github.com/grid-trading-bot/pkg/tracing"
 	"github.com/grid-trading-bot/services/grid-trading/internal/api"
 	"github.com/grid-trading-bot/services/grid-trading/internal/client"
 	"github.com/grid-trading-bot/services/grid-trading/internal/config"
@@ -17,6 +25,7 @@ import (
 	"github.com/grid-trading-bot/services/grid-trading/internal/service"
 	"github.com/joho/godotenv"
 	"github.com/robfig/cron/v3"
+	"github.com/shopspring/decimal"
 )
 
 func main() {
@@ -24,6 +33,8 @@ func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Printf("No .env file found, using params from environment only.")
 	}
+	logging.Setup("grid-trading")
+	tracer := tracing.Setup("grid-trading")
 
 	cfg := config.LoadConfig()
 
@@ -37,10 +48,36 @@ func main() {
 	}
 	defer db.Close()
 
+	// Recorded before migrating, so the self-check can tell a database from a newer build
+	schemaVersion, err := database.SchemaVersion(db)
+	if err != nil {
+		log.Fatal("Failed to read the schema version:", err)
+	}
+
 	// Run migrations
 	migrations := []string{
 		"services/grid-trading/migrations/001_create_grid_levels.sql",
 		"services/grid-trading/migrations/002_create_transactions.sql",
+		"services/grid-trading/migrations/003_create_profit_sweeps.sql",
+		"services/grid-trading/migrations/004_create_price_history.sql",
+		"services/grid-trading/migrations/005_create_funding_fees.sql",
+		"services/grid-trading/migrations/006_create_margin_interest.sql",
+		"services/grid-trading/migrations/007_create_daily_reports.sql",
+		"services/grid-trading/migrations/008_create_webhooks.sql",
+		"services/grid-trading/migrations/009_create_sell_dust.sql",
+		"services/grid-trading/migrations/010_create_reconciliation_reports.sql",
+		"services/grid-trading/migrations/011_create_grid_levels_archive.sql",
+		"services/grid-trading/migrations/012_create_shard_leases.sql",
+		"services/grid-trading/migrations/013_create_grid_fingerprints.sql",
+		"services/grid-trading/migrations/014_create_grid_configs.sql",
+		"services/grid-trading/migrations/015_create_capital_flows.sql",
+		"services/grid-trading/migrations/016_create_shadow_transactions.sql",
+		"services/grid-trading/migrations/017_create_shadow_strategies.sql",
+		"services/grid-trading/migrations/018_create_notification_cursor.sql",
+		"services/grid-trading/migrations/019_create_jobs.sql",
+		"services/grid-trading/migrations/020_create_sell_tranches.sql",
+		"services/grid-trading/migrations/021_create_calendar_events.sql",
+		"services/grid-trading/migrations/022_create_stop_loss_orders.sql",
 	}
 
 	for _, migrationFile := range migrations {
@@ -53,11 +90,189 @@ func main() {
 			log.Fatalf("Failed to run migration %s: %v", migrationFile, err)
 		}
 	}
+	if schemaVersion <= len(migrations) {
+		if err := database.SetSchemaVersion(db, len(migrations)); err != nil {
+			log.Fatal("Failed to record the schema version:", err)
+		}
+	}
 
 	repo := repository.NewGridLevelRepository(db)
 	txRepo := repository.NewTransactionRepository(db)
-	assuranceClient := client.NewOrderAssuranceClient(cfg.OrderAssuranceURL)
+	assuranceClient := client.NewOrderAssuranceClient(cfg.OrderAssuranceURL, cfg.OrderAssuranceKey)
 	gridService := service.NewGridService(repo, txRepo, assuranceClient, cfg.TradingFee)
+	if cfg.LevelCacheTTLSec > 0 {
+		gridService.UseLevelCache(time.Duration(cfg.LevelCacheTTLSec) * time.Second)
+		log.Printf("Caching levels per symbol in memory for up to %ds", cfg.LevelCacheTTLSec)
+	}
+	if cfg.OrderStatusCacheTTLSec > 0 {
+		gridService.UseOrderStatusCache(time.Duration(cfg.OrderStatusCacheTTLSec) * time.Second)
+		log.Printf("Triggers re-check open orders after %ds or once the price reaches them", cfg.OrderStatusCacheTTLSec)
+	}
+	gridService.UsePriceHistory(repository.NewPriceHistoryRepository(db))
+	gridService.UseDailyReports(repository.NewDailyReportRepository(db))
+	gridService.UseReconciliation(repository.NewReconciliationRepository(db))
+	gridService.UseGridFingerprints(repository.NewGridFingerprintRepository(db))
+	gridService.UseSellLadders(repository.NewSellTrancheRepository(db))
+	gridService.UseGridConfigs(repository.NewGridConfigRepository(db))
+	gridService.UseStopLossOrders(repository.NewStopLossOrderRepository(db))
+	gridService.UseCapitalFlows(repository.NewCapitalFlowRepository(db))
+	gridService.UseShadowTransactions(repository.NewShadowTransactionRepository(db))
+	gridService.UseShadowStrategies(repository.NewShadowStrategyRepository(db))
+	if err := gridService.UseCalendar(repository.NewCalendarEventRepository(db), service.CalendarSettings{
+		Symbols:     cfg.CalendarSymbols,
+		PauseBefore: time.Duration(cfg.CalendarPauseBeforeMin) * time.Minute,
+		ResumeAfter: time.Duration(cfg.CalendarResumeAfterMin) * time.Minute,
+	}); err != nil {
+		log.Fatal("Failed to load the trading calendar:", err)
+	}
+	if cfg.WatchOnly {
+		gridService.EnableWatchOnly()
+		log.Println("Watch-only mode: triggers record shadow orders (GET /grids/{symbol}/shadow), nothing is placed")
+	}
+	klineStore, err := klines.NewStore(db, klines.NewFetcher(cfg.BinanceAPIURL))
+	if err != nil {
+		log.Fatal("Failed to set up the kline store:", err)
+	}
+	gridService.UseKlines(klineStore)
+	gridService.SetReportTimezone(cfg.ReportTimezone)
+	gridService.SetOrderTTL(time.Duration(cfg.OrderTTLSec) * time.Second)
+	gridService.SetRecoveryBudget(cfg.MaxRecoveryAttempts)
+	gridService.SetDeadLevelPolicy(service.DeadLevelPolicy{
+		DisabledDays: cfg.DeadLevelDisabledDays,
+		IdleDays:     cfg.DeadLevelIdleDays,
+		CancelOrders: cfg.DeadLevelCancelOrders,
+	})
+	gridService.SetSyncSettings(service.SyncSettings{
+		Enabled:       cfg.SyncJobEnabled,
+		Cron:          cfg.SyncJobCron,
+		StuckAfterSec: cfg.SyncStuckAfterSec,
+		RecoveryBatch: cfg.SyncRecoveryBatch,
+		RecoverStuck:  cfg.SyncRecoverStuck,
+		CheckActive:   cfg.SyncCheckActive,
+	})
+	gridService.UseSellQuantityPolicy(cfg.SellQuantityPolicy, repository.NewSellDustRepository(db))
+	if cfg.FeeAwareSell {
+		gridService.EnableFeeAwareSellPrice()
+		log.Printf("Sell orders are raised to net each level's spread after %.4g%% fees", cfg.TradingFee)
+	}
+	gridService.SetRiskLimits(time.Duration(cfg.PriceStaleAfterSec)*time.Second, decimal.NewFromFloat(cfg.MaxDrawdownPct))
+	if cfg.MaxDrawdownPct > 0 {
+		log.Printf("New buys pause above %.2f%% unrealized drawdown", cfg.MaxDrawdownPct)
+	}
+	if cfg.BuyOrderType == "quote_market" {
+		gridService.UseQuoteQuantityBuys()
+		log.Printf("Buys are MARKET orders spending exactly the level's USDT amount")
+	}
+	if cfg.MaxSpreadPct > 0 {
+		gridService.UseSpreadGuard(decimal.NewFromFloat(cfg.MaxSpreadPct))
+		log.Printf("Placements are skipped while the bid/ask spread is above %.4g%%", cfg.MaxSpreadPct)
+	}
+	if cfg.TriggerMinConfidence > 0 || cfg.TriggerAggregatedAbove > 0 {
+		gridService.SetTriggerPolicy(service.TriggerPolicy{
+			MinConfidence:   cfg.TriggerMinConfidence,
+			AggregatedAbove: decimal.NewFromFloat(cfg.TriggerAggregatedAbove),
+		})
+		log.Printf("Triggers need confidence %.2f, orders above %.2f USDT an aggregated price (0 = off)", cfg.TriggerMinConfidence, cfg.TriggerAggregatedAbove)
+	}
+	if cfg.DepegThresholdPct > 0 {
+		gridService.UseDepegGuard(cfg.PegSymbol, decimal.NewFromFloat(cfg.DepegThresholdPct))
+		log.Printf("New buys pause while %s is more than %.2f%% off 1", cfg.PegSymbol, cfg.DepegThresholdPct)
+	}
+	if cfg.ReportCurrency != "" {
+		if cfg.FXSource == "fixed" {
+			gridService.UseReportingCurrency(cfg.ReportCurrency, "", decimal.NewFromFloat(cfg.FXRate))
+			log.Printf("Profit is also reported in %s at a fixed %g per USDT", cfg.ReportCurrency, cfg.FXRate)
+		} else {
+			gridService.UseReportingCurrency(cfg.ReportCurrency, cfg.FXSymbol, decimal.Zero)
+			log.Printf("Profit is also reported in %s at the latest %s price", cfg.ReportCurrency, cfg.FXSymbol)
+		}
+	}
+
+	// Self-check before any job or trigger can trade on the state it checks
+	selfCheck := gridService.RunSelfCheck(schemaVersion, len(migrations))
+	switch {
+	case cfg.StartupSafeMode == "always":
+		gridService.EnterSafeMode("STARTUP_SAFE_MODE=always")
+	case cfg.StartupSafeMode == "on_failure" && !selfCheck.Passed:
+		gridService.EnterSafeMode("startup self-check failed")
+	case !selfCheck.Passed:
+		log.Printf("WARNING: Startup self-check failed, trading anyway (STARTUP_SAFE_MODE=off) - see GET /self-check")
+	}
+
+	// Before any job or trigger - an instance trades only the symbols it holds a lease of
+	var shards *service.ShardCoordinator
+	if cfg.ShardingEnabled {
+		shards = service.NewShardCoordinator(repository.NewShardRepository(db), repo, cfg.InstanceID, cfg.InstanceURL,
+			time.Duration(cfg.ShardLeaseSec)*time.Second)
+		shards.Start()
+		defer shards.Stop()
+		gridService.UseSharding(shards)
+		log.Printf("Sharding symbols as instance %s (%s), leases of %ds", cfg.InstanceID, cfg.InstanceURL, cfg.ShardLeaseSec)
+	}
+
+	// clusterJob runs a job that covers every symbol on the shard leader only
+	clusterJob := func(name string, job func()) func() {
+		return func() {
+			if shards != nil && !shards.IsLeader() {
+				log.Printf("Not the shard leader, skipping %s job", name)
+				return
+			}
+			job()
+		}
+	}
+
+	// Before any job runs - webhooks wrap the level repository to report state changes
+	var webhooks *service.WebhookDispatcher
+	if cfg.WebhooksEnabled {
+		webhooks, err = service.NewWebhookDispatcher(repository.NewWebhookRepository(db), client.NewWebhookClient(),
+			time.Duration(cfg.WebhookRetryIntervalSec)*time.Second)
+		if err != nil {
+			log.Fatal("Failed to set up webhooks:", err)
+		}
+		webhooks.Start()
+		defer webhooks.Stop()
+		gridService.UseWebhooks(webhooks)
+		log.Printf("Delivering events to registered webhooks")
+	}
+
+	if cfg.RedisURL != "" {
+		rc, err := redis.Dial(cfg.RedisURL)
+		if err != nil {
+			log.Fatal("Failed to connect to Redis:", err)
+		}
+		defer rc.Close()
+
+		gridService.UsePriceSource(client.NewPriceCache(rc))
+		log.Printf("Reading latest prices from the Redis price cache (stale after %ds)", cfg.PriceStaleAfterSec)
+	}
+
+	sweepRepo := repository.NewProfitSweepRepository(db)
+	sweeper := service.NewProfitSweeper(txRepo, sweepRepo, assuranceClient, service.ProfitSweepConfig{
+		Threshold:   decimal.NewFromFloat(cfg.ProfitSweepThreshold),
+		Destination: cfg.ProfitSweepDestination,
+		DryRun:      cfg.ProfitSweepDryRun,
+	})
+
+	if cfg.ProfitSweepEnabled {
+		c := cron.New()
+		_, err := c.AddFunc(cfg.ProfitSweepCron, clusterJob("profit sweep", func() {
+			if inSafeMode, _ := gridService.SafeMode(); inSafeMode {
+				log.Println("Safe mode, skipping profit sweep job")
+				return
+			}
+			log.Println("Running profit sweep job...")
+			if _, err := sweeper.Run(); err != nil {
+				log.Printf("Profit sweep job failed: %v", err)
+			}
+		}))
+		if err != nil {
+			log.Fatal("Failed to add profit sweep cron job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Profit sweep scheduled with cron: %s (threshold %.2f USDT, destination %s, dry run %v)",
+			cfg.ProfitSweepCron, cfg.ProfitSweepThreshold, cfg.ProfitSweepDestination, cfg.ProfitSweepDryRun)
+	}
 
 	if cfg.SyncJobEnabled {
 		c := cron.New()
@@ -74,12 +289,298 @@ func main() {
 		}
 		c.Start()
 		defer c.Stop()
-		log.Printf("Sync job scheduled with cron: %s", cfg.SyncJobCron)
+		log.Printf("Sync job scheduled with cron: %s (stuck after %ds, batch %d, recover stuck %v, check active %v)",
+			cfg.SyncJobCron, cfg.SyncStuckAfterSec, cfg.SyncRecoveryBatch, cfg.SyncRecoverStuck, cfg.SyncCheckActive)
+	}
+
+	// Placements normally get their order ID within one order-assurance call; the sync job
+	// only recovers them after SYNC_STUCK_AFTER_SEC, so report them sooner
+	if cfg.PlacingWatchdogSec > 0 {
+		gridService.EnablePlacingWatchdog(time.Duration(cfg.PlacingWatchdogSec) * time.Second)
+		c := cron.New()
+		_, err := c.AddFunc(fmt.Sprintf("@every %ds", cfg.PlacingWatchdogSec), clusterJob("placing watchdog", func() {
+			if err := gridService.CheckStuckPlacements(); err != nil {
+				log.Printf("ERROR: Placing watchdog failed: %v", err)
+			}
+		}))
+		if err != nil {
+			log.Fatal("Failed to add placing watchdog job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Placing watchdog reports levels without an order ID after %ds", cfg.PlacingWatchdogSec)
+	}
+
+	if cfg.TrailingCheckSec > 0 {
+		c := cron.New()
+		_, err := c.AddFunc(fmt.Sprintf("@every %ds", cfg.TrailingCheckSec), clusterJob("trailing grid", func() {
+			if inSafeMode, _ := gridService.SafeMode(); inSafeMode {
+				log.Println("Safe mode, skipping trailing grid job")
+				return
+			}
+			if _, err := gridService.TrailGrids(); err != nil {
+				log.Printf("ERROR: Trailing grid job failed: %v", err)
+			}
+		}))
+		if err != nil {
+			log.Fatal("Failed to add trailing grid job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Trailing grids checked against the price every %ds", cfg.TrailingCheckSec)
+	}
+
+	handlers := api.NewHandlers(gridService, sweeper)
+
+	if cfg.FuturesEnabled {
+		futures := service.NewFuturesMonitor(repo, repository.NewFundingFeeRepository(db), assuranceClient)
+		futures.SetReportTimezone(cfg.ReportTimezone)
+		handlers.UseFuturesMonitor(futures)
+
+		c := cron.New()
+		_, err := c.AddFunc(cfg.FundingSyncCron, clusterJob("funding sync", func() {
+			log.Println("Running funding sync job...")
+			if err := futures.Sync(); err != nil {
+				log.Printf("Funding sync job failed: %v", err)
+			}
+		}))
+		if err != nil {
+			log.Fatal("Failed to add funding sync cron job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Funding sync scheduled with cron: %s", cfg.FundingSyncCron)
+	}
+
+	if cfg.MarginEnabled {
+		margin := service.NewMarginMonitor(repository.NewMarginInterestRepository(db), txRepo, assuranceClient, gridService)
+		margin.SetReportTimezone(cfg.ReportTimezone)
+		handlers.UseMarginMonitor(margin)
+
+		c := cron.New()
+		_, err := c.AddFunc(cfg.MarginSyncCron, clusterJob("margin interest sync", func() {
+			log.Println("Running margin interest sync job...")
+			if err := margin.Sync(); err != nil {
+				log.Printf("Margin interest sync job failed: %v", err)
+			}
+		}))
+		if err != nil {
+			log.Fatal("Failed to add margin interest sync cron job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Margin interest sync scheduled with cron: %s", cfg.MarginSyncCron)
+	}
+	if webhooks != nil {
+		handlers.UseWebhooks(webhooks)
+	}
+
+	operatorLog, err := oplog.NewStore(db)
+	if err != nil {
+		log.Fatal("Failed to set up the operator log:", err)
+	}
+	handlers.UseOperatorLog(operatorLog)
+	if shards != nil {
+		handlers.UseSharding(shards)
+	}
+	jobs := service.NewJobRunner(repository.NewJobRepository(db), cfg.JobWorkers, cfg.JobQueueSize,
+		time.Duration(cfg.JobRetentionDays)*24*time.Hour)
+	if shards != nil {
+		jobs.UseInstance(cfg.InstanceID)
+	}
+	jobs.Start()
+	defer jobs.Stop()
+	handlers.UseJobs(jobs)
+
+	if cfg.TriggerQueueSize > 0 {
+		handlers.UseTriggerLimit(cfg.TriggerQueueSize)
+		log.Printf("Price triggers limited to %d at once, more are refused with 429", cfg.TriggerQueueSize)
+	}
+
+	// Notifications carry order-assurance's sequence numbers; gaps are pulled from its log
+	// instead of waiting for the hourly sync
+	if cfg.NotifyReplaySec > 0 {
+		handlers.UseNotificationReplay(assuranceClient, repository.NewNotificationCursorRepository(db))
+		c := cron.New()
+		_, err := c.AddFunc(fmt.Sprintf("@every %ds", cfg.NotifyReplaySec), clusterJob("notification replay", func() {
+			if _, err := handlers.ReplayNotifications(); err != nil {
+				log.Printf("ERROR: Notification replay failed: %v", err)
+			}
+		}))
+		if err != nil {
+			log.Fatal("Failed to add notification replay job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Missed notifications are replayed from order-assurance every %ds", cfg.NotifyReplaySec)
 	}
 
-	handlers := api.NewHandlers(gridService)
 	router := mux.NewRouter()
 	handlers.RegisterRoutes(router)
+	router.Use(tracing.Middleware)
+
+	if cfg.APIKey != "" {
+		tokens := make([]api.BearerToken, 0, len(cfg.Tokens))
+		for _, token := range cfg.Tokens {
+			tokens = append(tokens, api.BearerToken(token))
+		}
+		router.Use(api.APIKeyMiddleware(cfg.APIKey, tokens))
+		log.Printf("API key authentication enabled with %d bearer tokens", len(tokens))
+	} else {
+		log.Println("WARNING: GRID_TRADING_AUTH_DISABLED=true - triggers, fill notifications and grid endpoints are unauthenticated")
+	}
+
+	// Log manual actions by the actor the gateway forwards. Triggers and order notifications
+	// are what price-monitor and order-assurance call on their own.
+	router.Use(oplog.Middleware(operatorLog, "/trigger-for-price", "/order-fill-notification", "/order-fill-error-notification", "/exchange-status"))
+	router.Use(handlers.SafeModeMiddleware)
+
+	// Webhook endpoints stay available either way, e.g. for manual replays
+	if cfg.Transport == "nats" {
+		conn, err := natsjs.Connect(cfg.NATSURL, "grid-trading")
+		if err != nil {
+			log.Fatal("Failed to connect to NATS:", err)
+		}
+		defer conn.Close()
+
+		js := natsjs.New(conn)
+
+		events, err := client.NewNATSEventPublisher(js)
+		if err != nil {
+			log.Fatal("Failed to set up event stream:", err)
+		}
+		defer events.Stop()
+		gridService.UseEventSink(events)
+
+		consumer := api.NewQueueConsumer(handlers, js)
+		if err := consumer.Start(); err != nil {
+			log.Fatal("Failed to start queue consumer:", err)
+		}
+		defer consumer.Stop()
+		log.Printf("Receiving triggers and notifications from NATS JetStream at %s", cfg.NATSURL)
+	} else if cfg.NotifierURL != "" {
+		events := client.NewHTTPEventPublisher(cfg.NotifierURL)
+		defer events.Stop()
+		gridService.UseEventSink(events)
+		log.Printf("Reporting events to notifier at %s", cfg.NotifierURL)
+	}
+
+	gridService.AnnounceSafeMode()
+	gridService.AnnounceInconsistencies()
+
+	// After the event sinks, so an event already under way reports its pause
+	if cfg.CalendarFile != "" {
+		windows, err := gridService.ImportCalendarFile(cfg.CalendarFile)
+		if err != nil {
+			log.Fatal("Failed to import CALENDAR_FILE:", err)
+		}
+		log.Printf("Imported trading calendar %s, %d events upcoming", cfg.CalendarFile, len(windows))
+	}
+	if cfg.CalendarCheckSec > 0 {
+		// Every instance keeps its calendar current; each window is recorded by one of them
+		c := cron.New()
+		_, err := c.AddFunc(fmt.Sprintf("@every %ds", cfg.CalendarCheckSec), func() {
+			if err := gridService.CheckCalendar(); err != nil {
+				log.Printf("ERROR: Trading calendar check failed: %v", err)
+			}
+		})
+		if err != nil {
+			log.Fatal("Failed to add trading calendar job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Buys pause %dmin before to %dmin after trading calendar events, checked every %ds",
+			cfg.CalendarPauseBeforeMin, cfg.CalendarResumeAfterMin, cfg.CalendarCheckSec)
+	}
+
+	if cfg.SummaryEnabled {
+		if cfg.Transport != "nats" && cfg.NotifierURL == "" {
+			log.Fatal("SUMMARY_ENABLED needs NOTIFIER_URL or TRANSPORT=nats to send summaries")
+		}
+
+		c := cron.New(cron.WithLocation(cfg.ReportTimezone))
+		_, err := c.AddFunc(cfg.SummaryCron, clusterJob("summary", func() {
+			log.Println("Sending summary...")
+			if err := gridService.SendSummary(); err != nil {
+				log.Printf("Summary job failed: %v", err)
+			}
+		}))
+		if err != nil {
+			log.Fatal("Failed to add summary cron job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Summary scheduled with cron: %s (%s)", cfg.SummaryCron, cfg.ReportTimezone)
+	}
+
+	if cfg.DailyReportEnabled {
+		c := cron.New(cron.WithLocation(cfg.ReportTimezone))
+		_, err := c.AddFunc(cfg.DailyReportCron, clusterJob("daily report", func() {
+			log.Println("Composing daily report...")
+			if _, err := gridService.SendDailyReport(); err != nil {
+				log.Printf("Daily report job failed: %v", err)
+			}
+		}))
+		if err != nil {
+			log.Fatal("Failed to add daily report cron job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Daily report scheduled with cron: %s (%s)", cfg.DailyReportCron, cfg.ReportTimezone)
+	}
+
+	if cfg.WeeklyDigestEnabled {
+		c := cron.New(cron.WithLocation(cfg.ReportTimezone))
+		_, err := c.AddFunc(cfg.WeeklyDigestCron, clusterJob("weekly digest", func() {
+			log.Println("Composing weekly digest...")
+			if _, err := gridService.SendWeeklyDigest(); err != nil {
+				log.Printf("Weekly digest job failed: %v", err)
+			}
+		}))
+		if err != nil {
+			log.Fatal("Failed to add weekly digest cron job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Weekly digest scheduled with cron: %s (%s)", cfg.WeeklyDigestCron, cfg.ReportTimezone)
+	}
+
+	if cfg.ReconciliationEnabled {
+		c := cron.New()
+		_, err := c.AddFunc(cfg.ReconciliationCron, clusterJob("reconciliation", func() {
+			log.Println("Running reconciliation...")
+			if _, err := gridService.Reconcile(context.Background()); err != nil {
+				log.Printf("Reconciliation job failed: %v", err)
+			}
+		}))
+		if err != nil {
+			log.Fatal("Failed to add reconciliation cron job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Reconciliation scheduled with cron: %s", cfg.ReconciliationCron)
+	}
+
+	if cfg.DeadLevelGCEnabled {
+		c := cron.New()
+		_, err := c.AddFunc(cfg.DeadLevelGCCron, clusterJob("dead-level cleanup", func() {
+			if inSafeMode, _ := gridService.SafeMode(); inSafeMode {
+				log.Println("Safe mode, skipping dead-level cleanup")
+				return
+			}
+			log.Println("Running dead-level cleanup...")
+			if _, err := gridService.CollectDeadLevels(context.Background(), cfg.DeadLevelCancelOrders); err != nil {
+				log.Printf("Dead-level cleanup failed: %v", err)
+			}
+		}))
+		if err != nil {
+			log.Fatal("Failed to add dead-level cleanup cron job:", err)
+		}
+		c.Start()
+		defer c.Stop()
+		log.Printf("Dead-level cleanup scheduled with cron: %s (disabled %d days, idle %d days, cancel orders %v)",
+			cfg.DeadLevelGCCron, cfg.DeadLevelDisabledDays, cfg.DeadLevelIdleDays, cfg.DeadLevelCancelOrders)
+	}
 
 	srv := &http.Server{
 		Addr:    ":" + cfg.ServerPort,
@@ -98,5 +599,6 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	tracer.Shutdown()
 	fmt.Println("Server stopped")
-}
\ No newline at end of file
+}
@@ -1,126 +1,257 @@
 package api
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/pkg/oplog"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
 	"github.com/grid-trading-bot/services/grid-trading/internal/service"
 	"github.com/shopspring/decimal"
 )
 
 type Handlers struct {
 	gridService *service.GridService
+	sweeper     *service.ProfitSweeper
+	futures     *service.FuturesMonitor    // nil unless FUTURES_ENABLED
+	margin      *service.MarginMonitor     // nil unless MARGIN_ENABLED
+	webhooks    *service.WebhookDispatcher // nil unless WEBHOOKS_ENABLED
+	operatorLog *oplog.Store               // nil = not logged
+	shards      *service.ShardCoordinator  // nil unless SHARDING_ENABLED
+	triggers    chan struct{}              // Slots of price triggers in processing, nil = unlimited
+	replay      *notificationReplay        // nil unless NOTIFICATION_REPLAY_SEC
+	jobs        *service.JobRunner         // nil = heavy endpoints run in the request
 }
 
-func NewHandlers(gridService *service.GridService) *Handlers {
+func NewHandlers(gridService *service.GridService, sweeper *service.ProfitSweeper) *Handlers {
 	return &Handlers{
 		gridService: gridService,
+		sweeper:     sweeper,
 	}
 }
 
+// UseFuturesMonitor serves the futures position and funding endpoints
+func (h *Handlers) UseFuturesMonitor(futures *service.FuturesMonitor) {
+	h.futures = futures
+}
+
+// UseMarginMonitor serves the margin debt and interest endpoints
+func (h *Handlers) UseMarginMonitor(margin *service.MarginMonitor) {
+	h.margin = margin
+}
+
+// UseOperatorLog serves the logged operator actions on GET /operator-actions
+func (h *Handlers) UseOperatorLog(store *oplog.Store) {
+	h.operatorLog = store
+}
+
+// UseSharding serves the symbol leases of the instances on GET /shards
+func (h *Handlers) UseSharding(shards *service.ShardCoordinator) {
+	h.shards = shards
+}
+
+// UseTriggerLimit processes at most n price triggers at once. Triggers beyond that get 429
+// with Retry-After, which price-monitor answers by sending triggers less often.
+func (h *Handlers) UseTriggerLimit(n int) {
+	h.triggers = make(chan struct{}, n)
+}
+
+// UseWebhooks serves the outbound webhook subscription endpoints
+func (h *Handlers) UseWebhooks(webhooks *service.WebhookDispatcher) {
+	h.webhooks = webhooks
+}
+
 func (h *Handlers) RegisterRoutes(r *mux.Router) {
 	// Grid management endpoints
 	r.HandleFunc("/levels/init", h.handleCreateGrid).Methods("POST")
 	r.HandleFunc("/levels/symbols", h.handleGetGridSymbols).Methods("GET")
+	r.HandleFunc("/levels/bulk", h.handleBulkLevels).Methods("POST")
+	r.HandleFunc("/levels/dead", h.handleGetDeadLevels).Methods("GET")
+	r.HandleFunc("/levels/dead/archive", h.handleArchiveDeadLevels).Methods("POST")
 	r.HandleFunc("/levels", h.handleGetAllGrids).Methods("GET")
 	r.HandleFunc("/levels/{symbol}", h.handleGetGrids).Methods("GET")
+	r.HandleFunc("/levels/{symbol}/capital", h.handleGetCapital).Methods("GET")
+	r.HandleFunc("/levels/{symbol}/seed", h.handleSeedLevels).Methods("POST")
+	r.HandleFunc("/levels/{id:[0-9]+}/cancel-order", h.handleCancelLevelOrder).Methods("POST")
+	r.HandleFunc("/levels/{id:[0-9]+}/tranches", h.handleGetSellLadder).Methods("GET")
+	r.HandleFunc("/levels/{id:[0-9]+}/notes", h.handleSetLevelNotes).Methods("PUT")
+	r.HandleFunc("/grids/{symbol}/simulate", h.handleSimulateGrid).Methods("POST")
+	r.HandleFunc("/grids/{symbol}/pause", h.handlePauseGrid).Methods("POST")
+	r.HandleFunc("/grids/{symbol}/resume", h.handleResumeGrid).Methods("POST")
+	r.HandleFunc("/grids/{symbol}/config", h.handleGetGridConfig).Methods("GET")
+	r.HandleFunc("/grids/{symbol}/config", h.handleUpdateGridConfig).Methods("PUT")
+	r.HandleFunc("/grids/{symbol}/shadow", h.handleGetShadow).Methods("GET")
+	r.HandleFunc("/grids/{symbol}/shadow", h.handleResetShadow).Methods("DELETE")
+	r.HandleFunc("/grids/{symbol}/strategies", h.handleCreateShadowStrategy).Methods("POST")
+	r.HandleFunc("/grids/{symbol}/strategies", h.handleGetShadowStrategies).Methods("GET")
+	r.HandleFunc("/grids/{symbol}/strategies/compare", h.handleCompareShadowStrategies).Methods("GET")
+	r.HandleFunc("/grids/{symbol}/strategies/{id}", h.handleDeleteShadowStrategy).Methods("DELETE")
+	r.HandleFunc("/grids/{symbol}", h.handleDeleteGrid).Methods("DELETE")
+	r.HandleFunc("/grids/levels/{id}", h.handleEditLevel).Methods("PATCH")
 
 	// Webhook endpoints
 	r.HandleFunc("/trigger-for-price", h.handlePriceTrigger).Methods("POST")
 	r.HandleFunc("/order-fill-notification", h.handleFillNotification).Methods("POST")
 	r.HandleFunc("/order-fill-error-notification", h.handleErrorNotification).Methods("POST")
+	r.HandleFunc("/exchange-status", h.handleExchangeStatus).Methods("POST")
+	r.HandleFunc("/notifications/replay", h.handleReplayNotifications).Methods("POST")
 	r.HandleFunc("/health", h.handleHealth).Methods("GET")
 	r.HandleFunc("/status", h.handleStatus).Methods("GET")
-}
-
-type PriceTriggerRequest struct {
-	Symbol string          `json:"symbol"`
-	Price  decimal.Decimal `json:"price"`
-}
+	r.HandleFunc("/fees", h.handleFees).Methods("GET")
+	r.HandleFunc("/dust", h.handleGetSellDust).Methods("GET")
+	r.HandleFunc("/metrics/placing", h.handlePlacingMetrics).Methods("GET")
+	r.HandleFunc("/sync/config", h.handleGetSyncConfig).Methods("GET")
+	r.HandleFunc("/transactions", h.handleGetTransactions).Methods("GET")
+	r.HandleFunc("/pnl/unrealized", h.handleUnrealizedPnL).Methods("GET")
+	r.HandleFunc("/benchmark", h.handleBenchmark).Methods("GET")
+	r.HandleFunc("/summary/send", h.handleSendSummary).Methods("POST")
+	r.HandleFunc("/reports/daily/send", h.handleSendDailyReport).Methods("POST")
+	r.HandleFunc("/reports/daily/{date}", h.handleGetDailyReport).Methods("GET")
+	r.HandleFunc("/reconciliation/latest", h.handleGetLatestReconciliation).Methods("GET")
+	r.HandleFunc("/reconciliation/run", h.handleRunReconciliation).Methods("POST")
+	r.HandleFunc("/reports/weekly", h.handleGetWeeklyDigest).Methods("GET")
+	r.HandleFunc("/reports/weekly/send", h.handleSendWeeklyDigest).Methods("POST")
+	r.HandleFunc("/klines/{symbol}", h.handleGetKlines).Methods("GET")
+	r.HandleFunc("/klines/{symbol}/download", h.handleDownloadKlines).Methods("POST")
+	r.HandleFunc("/capital", h.handleGetCapitalReturn).Methods("GET")
+	r.HandleFunc("/capital/flows", h.handleCreateCapitalFlow).Methods("POST")
+	r.HandleFunc("/capital/flows", h.handleGetCapitalFlows).Methods("GET")
+	r.HandleFunc("/capital/flows/{id}", h.handleDeleteCapitalFlow).Methods("DELETE")
+	r.HandleFunc("/calendar/events", h.handleImportCalendar).Methods("POST")
+	r.HandleFunc("/calendar/events", h.handleGetCalendar).Methods("GET")
+	r.HandleFunc("/calendar/events/{id}", h.handleDeleteCalendarEvent).Methods("DELETE")
+	r.HandleFunc("/profit-sweeps", h.handleGetProfitSweeps).Methods("GET")
+	r.HandleFunc("/profit-sweeps/run", h.handleRunProfitSweep).Methods("POST")
+	r.HandleFunc("/futures", h.handleFuturesStatus).Methods("GET")
+	r.HandleFunc("/futures/sync", h.handleFuturesSync).Methods("POST")
+	r.HandleFunc("/margin", h.handleMarginStatus).Methods("GET")
+	r.HandleFunc("/margin/sync", h.handleMarginSync).Methods("POST")
+	r.HandleFunc("/operator-actions", h.handleOperatorActions).Methods("GET")
+	r.HandleFunc("/self-check", h.handleGetSelfCheck).Methods("GET")
+	r.HandleFunc("/consistency", h.handleGetConsistency).Methods("GET")
+	r.HandleFunc("/consistency/check", h.handleCheckConsistency).Methods("POST")
+	r.HandleFunc("/shards", h.handleGetShards).Methods("GET")
+	r.HandleFunc("/safe-mode/resume", h.handleResumeFromSafeMode).Methods("POST")
+	r.HandleFunc("/jobs", h.handleGetJobs).Methods("GET")
+	r.HandleFunc("/jobs/{id}", h.handleGetJob).Methods("GET")
+	r.HandleFunc("/jobs/{id}/cancel", h.handleCancelJob).Methods("POST")
 
-type FillNotificationRequest struct {
-	OrderID      string          `json:"order_id"`
-	Symbol       string          `json:"symbol"`
-	Price        decimal.Decimal `json:"price"`
-	Side         string          `json:"side"`
-	Status       string          `json:"status"`
-	FilledAmount decimal.Decimal `json:"filled_amount"`
-	FillPrice    decimal.Decimal `json:"fill_price"`
+	// Outbound webhook subscriptions
+	r.HandleFunc("/webhooks", h.handleCreateWebhook).Methods("POST")
+	r.HandleFunc("/webhooks", h.handleGetWebhooks).Methods("GET")
+	r.HandleFunc("/webhooks/deliveries", h.handleGetWebhookDeliveries).Methods("GET")
+	r.HandleFunc("/webhooks/deliveries/{id}/requeue", h.handleRequeueWebhookDelivery).Methods("POST")
+	r.HandleFunc("/webhooks/{id}", h.handleDeleteWebhook).Methods("DELETE")
 }
 
-type ErrorNotificationRequest struct {
-	OrderID string `json:"order_id"`
-	Symbol  string `json:"symbol"`
-	Side    string `json:"side"`
-	Error   string `json:"error"`
-}
+// Triggers from price-monitor and notifications from order-assurance
+type (
+	PriceTriggerRequest      = contracts.PriceTrigger
+	FillNotificationRequest  = contracts.FillNotification
+	ErrorNotificationRequest = contracts.ErrorNotification
+)
 
 type CreateGridRequest struct {
-	Symbol   string          `json:"symbol"`
-	MinPrice decimal.Decimal `json:"min_price"`
-	MaxPrice decimal.Decimal `json:"max_price"`
-	GridStep decimal.Decimal `json:"grid_step"`
+	Symbol    string          `json:"symbol"`
+	MinPrice  decimal.Decimal `json:"min_price"`
+	MaxPrice  decimal.Decimal `json:"max_price"`
+	GridStep  decimal.Decimal `json:"grid_step"`
 	BuyAmount decimal.Decimal `json:"buy_amount"`
+	Account   string          `json:"account,omitempty"` // Binance sub-account for this grid (empty = master)
+
+	// geometric: levels spaced by grid_step_pct percent of their buy price instead of grid_step
+	GridType    string          `json:"grid_type,omitempty"` // arithmetic (default) | geometric
+	GridStepPct decimal.Decimal `json:"grid_step_pct,omitempty"`
+
+	// Optional scaled re-entry: each consecutive filled level above multiplies the buy, up to the cap
+	BuyMultiplier decimal.NullDecimal `json:"buy_multiplier,omitempty"`
+	MaxBuyAmount  decimal.NullDecimal `json:"max_buy_amount,omitempty"`
+
+	// Optional take-profit ladder: each level sells its bought amount in these parts (shares add up to 100)
+	SellTranches []service.SellTrancheSpec `json:"sell_tranches,omitempty"`
 }
 
+// CreateGridResponse is the capital estimate of the symbol's grid with how the new grid
+// compares to the ones already created on it
+type CreateGridResponse struct {
+	*service.CapitalEstimate
+	Fingerprint string                    `json:"fingerprint,omitempty"`
+	Created     int                       `json:"created"`             // Levels added by this request
+	Duplicate   bool                      `json:"duplicate,omitempty"` // The same grid already exists
+	Overlaps    []*models.GridFingerprint `json:"overlaps,omitempty"`  // Grids on the symbol whose price range overlaps
+}
+
+// How long price-monitor is told to hold triggers back when the trigger limit is reached
+const triggerRetryAfter = 5 * time.Second
+
 func (h *Handlers) handlePriceTrigger(w http.ResponseWriter, r *http.Request) {
 	var req PriceTriggerRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("ERROR: Invalid price trigger request body: %v", err)
+		slog.Error("Invalid price trigger request body", logging.Err(err))
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	log.Printf("INFO: Price trigger received - Symbol: %s, Price: %s", req.Symbol, req.Price)
+	if h.triggers != nil {
+		select {
+		case h.triggers <- struct{}{}:
+			defer func() { <-h.triggers }()
+		default:
+			slog.Warn("Too many price triggers in processing, refusing trigger", "in_processing", cap(h.triggers),
+				logging.KeySymbol, req.Symbol, "price", req.Price)
+			w.Header().Set("Retry-After", strconv.Itoa(int(triggerRetryAfter/time.Second)))
+			http.Error(w, "Too many price triggers in processing", http.StatusTooManyRequests)
+			return
+		}
+	}
 
-	if err := h.gridService.ProcessPriceTrigger(req.Symbol, req.Price); err != nil {
-		log.Printf("ERROR: Failed to process price trigger for %s @ %s: %v", req.Symbol, req.Price, err)
+	result, err := h.processPriceTrigger(r.Context(), req)
+	if errors.Is(err, service.ErrNotOwner) {
+		// price-monitor refreshes its shard map and sends the trigger to the owner
+		http.Error(w, err.Error(), http.StatusMisdirectedRequest)
+		return
+	}
+	if err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "processed"})
+	json.NewEncoder(w).Encode(map[string]string{"status": result})
 }
 
 func (h *Handlers) handleFillNotification(w http.ResponseWriter, r *http.Request) {
 	var req FillNotificationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("ERROR: Invalid fill notification request body: %v", err)
+		slog.Error("Invalid fill notification request body", logging.Err(err))
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	log.Printf("INFO: Fill notification received - OrderID: %s, Symbol: %s, Side: %s, Status: %s, Price: %s, Filled: %s",
-		req.OrderID, req.Symbol, req.Side, req.Status, req.Price, req.FilledAmount)
-
-	if req.Status != "filled" {
-		log.Printf("INFO: Ignoring non-filled notification - OrderID: %s, Status: %s", req.OrderID, req.Status)
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]string{"status": "ignored"})
-		return
-	}
-
-	var err error
-	if req.Side == "buy" {
-		err = h.gridService.ProcessBuyFillNotification(req.OrderID, req.FilledAmount, req.FillPrice)
-	} else if req.Side == "sell" {
-		err = h.gridService.ProcessSellFillNotification(req.OrderID, req.FilledAmount, req.FillPrice)
-	} else {
+	result, err := h.processFillNotification(req)
+	if errors.Is(err, errInvalidSide) {
 		http.Error(w, "Invalid side", http.StatusBadRequest)
 		return
 	}
-
 	if err != nil {
-		log.Printf("Error processing fill notification: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "processed"})
+	json.NewEncoder(w).Encode(map[string]string{"status": result})
 }
 
 func (h *Handlers) handleErrorNotification(w http.ResponseWriter, r *http.Request) {
@@ -130,18 +261,122 @@ func (h *Handlers) handleErrorNotification(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	log.Printf("Received error notification for order %s: %s", req.OrderID, req.Error)
-
-	if err := h.gridService.ProcessErrorNotification(req.OrderID, req.Side, req.Error); err != nil {
-		log.Printf("Error processing error notification: %v", err)
+	result, err := h.processErrorNotification(req)
+	if err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": result})
+}
+
+// handleExchangeStatus pauses or resumes order placement as order-assurance sees the exchange
+func (h *Handlers) handleExchangeStatus(w http.ResponseWriter, r *http.Request) {
+	var req contracts.ExchangeStatus
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.gridService.SetExchangeStatus(req)
+
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"status": "processed"})
 }
 
+// errInvalidSide rejects a notification that can never be applied, however often it is retried
+var errInvalidSide = errors.New("invalid side")
+
+// The process* methods apply a trigger or notification whichever transport delivered it
+
+// processPriceTrigger applies a trigger, returning "processed" or "ignored" (out of order)
+func (h *Handlers) processPriceTrigger(ctx context.Context, req PriceTriggerRequest) (string, error) {
+	logger := slog.With(logging.KeySymbol, req.Symbol, "price", req.Price)
+	logger.Info("Price trigger received", "sequence", req.Sequence)
+
+	applied, err := h.gridService.ProcessPriceTrigger(ctx, req)
+	if errors.Is(err, service.ErrNotOwner) {
+		logger.Info("Refusing trigger", logging.Err(err))
+		return "", err
+	}
+	if err != nil {
+		logger.Error("Failed to process price trigger", logging.Err(err))
+		return "", err
+	}
+	if !applied {
+		return "ignored", nil
+	}
+	return "processed", nil
+}
+
+// processFillNotification handles fill, cancel and placed notifications, returning "processed" or "ignored"
+func (h *Handlers) processFillNotification(req FillNotificationRequest) (string, error) {
+	logger := slog.With(logging.KeyOrderID, req.OrderID, logging.KeySymbol, req.Symbol, logging.KeyAccount, req.Account,
+		"side", req.Side, "status", req.Status)
+	logger.Info("Fill notification received", "price", req.Price, "filled", req.FilledAmount)
+
+	switch req.Status {
+	case contracts.StatusCancelled, contracts.StatusPlaced, contracts.StatusFilled:
+	default:
+		logger.Info("Ignoring non-filled notification")
+		h.noteNotification(req.Seq)
+		return "ignored", nil
+	}
+
+	if req.Side != "buy" && req.Side != "sell" {
+		return "", errInvalidSide
+	}
+
+	var err error
+	switch req.Status {
+	case contracts.StatusCancelled:
+		err = h.gridService.ProcessCancelNotification(req.OrderID, req.Side, req.FilledAmount, req.FillPrice, req.Commission, req.CommissionAsset, req.BaseAsset)
+	case contracts.StatusPlaced:
+		// Order placed before an order-assurance restart whose response we never got
+		err = h.gridService.ProcessPlacedNotification(req.OrderID, req.Symbol, req.Side, req.Account, req.Price)
+	case contracts.StatusFilled:
+		if req.Side == "buy" {
+			err = h.gridService.ProcessBuyFillNotification(req.OrderID, req.FilledAmount, req.FillPrice, req.Commission, req.CommissionAsset, req.BaseAsset)
+		} else {
+			err = h.gridService.ProcessSellFillNotification(req.OrderID, req.FilledAmount, req.FillPrice, req.Commission, req.CommissionAsset, req.BaseAsset)
+		}
+	}
+
+	if err != nil {
+		logger.Error("Failed to process notification", logging.Err(err))
+		return "", err
+	}
+	h.noteNotification(req.Seq)
+	return "processed", nil
+}
+
+// processErrorNotification applies a failed-order notification, returning "processed"
+func (h *Handlers) processErrorNotification(req ErrorNotificationRequest) (string, error) {
+	logger := slog.With(logging.KeyOrderID, req.OrderID, logging.KeySymbol, req.Symbol, logging.KeyAccount, req.Account)
+	logger.Info("Error notification received", "code", req.ErrorCode, logging.KeyError, req.Error)
+
+	// Rejected before reaching the book: there is no order ID, the level is found by its price
+	if req.OrderID == "" {
+		if req.Side != "buy" && req.Side != "sell" {
+			return "", errInvalidSide
+		}
+		if err := h.gridService.ProcessRejectionNotification(req.Account, req.Symbol, req.Side, req.Price, req.ErrorCode, req.Error); err != nil {
+			logger.Error("Failed to process rejection notification", logging.Err(err))
+			return "", err
+		}
+		h.noteNotification(req.Seq)
+		return "processed", nil
+	}
+
+	if err := h.gridService.ProcessErrorNotification(req.OrderID, req.Side, req.ErrorCode, req.Error); err != nil {
+		logger.Error("Failed to process error notification", logging.Err(err))
+		return "", err
+	}
+	h.noteNotification(req.Seq)
+	return "processed", nil
+}
+
 func (h *Handlers) handleHealth(w http.ResponseWriter, r *http.Request) {
 	// Check database connectivity
 	if err := h.gridService.CheckHealth(); err != nil {
@@ -181,9 +416,30 @@ func (h *Handlers) handleCreateGrid(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Min price must be less than max price", http.StatusBadRequest)
 		return
 	}
-	if req.GridStep.LessThanOrEqual(decimal.Zero) {
-		log.Printf("ERROR: Grid creation invalid step: %s", req.GridStep)
-		http.Error(w, "Grid step must be positive", http.StatusBadRequest)
+	gridStep := req.GridStep
+	switch req.GridType {
+	case "", service.GridTypeArithmetic:
+		req.GridType = service.GridTypeArithmetic
+		if req.GridStep.LessThanOrEqual(decimal.Zero) {
+			log.Printf("ERROR: Grid creation invalid step: %s", req.GridStep)
+			http.Error(w, "Grid step must be positive", http.StatusBadRequest)
+			return
+		}
+	case service.GridTypeGeometric:
+		if !req.GridStep.IsZero() {
+			log.Printf("ERROR: Grid creation geometric grid with grid_step")
+			http.Error(w, "A geometric grid takes grid_step_pct instead of grid_step", http.StatusBadRequest)
+			return
+		}
+		if req.GridStepPct.LessThanOrEqual(decimal.Zero) {
+			log.Printf("ERROR: Grid creation invalid step percentage: %s", req.GridStepPct)
+			http.Error(w, "Grid step percentage must be positive", http.StatusBadRequest)
+			return
+		}
+		gridStep = req.GridStepPct
+	default:
+		log.Printf("ERROR: Grid creation invalid grid type: %s", req.GridType)
+		http.Error(w, "Grid type must be arithmetic or geometric", http.StatusBadRequest)
 		return
 	}
 	if req.BuyAmount.LessThanOrEqual(decimal.Zero) {
@@ -192,75 +448,1614 @@ func (h *Handlers) handleCreateGrid(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("INFO: Creating grid for %s: min=%s, max=%s, step=%s, amount=%s",
-		req.Symbol, req.MinPrice, req.MaxPrice, req.GridStep, req.BuyAmount)
+	if req.BuyMultiplier.Valid {
+		if req.BuyMultiplier.Decimal.LessThanOrEqual(decimal.NewFromInt(1)) {
+			log.Printf("ERROR: Grid creation invalid buy multiplier: %s", req.BuyMultiplier.Decimal)
+			http.Error(w, "Buy multiplier must be greater than 1", http.StatusBadRequest)
+			return
+		}
+		if !req.MaxBuyAmount.Valid || req.MaxBuyAmount.Decimal.LessThan(req.BuyAmount) {
+			log.Printf("ERROR: Grid creation invalid max buy amount: %s", req.MaxBuyAmount.Decimal)
+			http.Error(w, "Max buy amount is required with a buy multiplier and must be at least the buy amount", http.StatusBadRequest)
+			return
+		}
+	} else if req.MaxBuyAmount.Valid {
+		log.Printf("ERROR: Grid creation max buy amount without multiplier")
+		http.Error(w, "Max buy amount requires a buy multiplier", http.StatusBadRequest)
+		return
+	}
+	if err := service.ValidateSellTranches(req.SellTranches); err != nil {
+		log.Printf("ERROR: Grid creation %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("INFO: Creating %s grid for %s: min=%s, max=%s, step=%s, amount=%s, multiplier=%s, max_amount=%s, account=%q, sell tranches=%d",
+		req.GridType, req.Symbol, req.MinPrice, req.MaxPrice, gridStep, req.BuyAmount, nullDecimalString(req.BuyMultiplier), nullDecimalString(req.MaxBuyAmount), req.Account, len(req.SellTranches))
+
+	check, err := h.gridService.CheckGrid(req.Symbol, req.Account, req.GridType, req.MinPrice, req.MaxPrice, gridStep, req.BuyAmount, req.BuyMultiplier, req.MaxBuyAmount)
+	if err != nil {
+		log.Printf("Error checking grid: %v", err)
+		http.Error(w, "Failed to create grid", http.StatusInternalServerError)
+		return
+	}
 
-	_, err := h.gridService.CreateGrid(req.Symbol, req.MinPrice, req.MaxPrice, req.GridStep, req.BuyAmount)
+	// A duplicate still runs: it adds back any level missing from the grid, usually none
+	levels, err := h.gridService.CreateGrid(req.Symbol, req.Account, req.GridType, req.MinPrice, req.MaxPrice, gridStep, req.BuyAmount, req.BuyMultiplier, req.MaxBuyAmount, req.SellTranches)
 	if err != nil {
 		log.Printf("Error creating grid: %v", err)
 		http.Error(w, "Failed to create grid", http.StatusInternalServerError)
 		return
 	}
+	if err := h.gridService.RecordGrid(check); err != nil {
+		log.Printf("WARNING: %v", err)
+	}
+
+	response := CreateGridResponse{Created: len(levels)}
+	if check != nil {
+		response.Fingerprint = check.Fingerprint
+		response.Duplicate = check.Duplicate
+		response.Overlaps = check.Overlaps
+	}
+
+	// Respond with the capital the whole grid (existing levels included) can lock up
+	estimate, err := h.gridService.EstimateCapital(req.Symbol)
+	if err != nil {
+		log.Printf("WARNING: Failed to estimate %s grid capital: %v", req.Symbol, err)
+	} else if estimate.ScalingRiskUSDT.IsPositive() {
+		log.Printf("WARNING: %s grid can lock up %s USDT if price falls through it, %s more than flat sizing",
+			req.Symbol, estimate.WorstCaseUSDT, estimate.ScalingRiskUSDT)
+	}
+	response.CapitalEstimate = estimate
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
 }
 
-func (h *Handlers) handleGetGrids(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	symbol := vars["symbol"]
+// handleGetCapital estimates the flat and worst-case capital of a symbol's grid
+func (h *Handlers) handleGetCapital(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
 
-	log.Printf("Fetching grid levels for symbol: %s", symbol)
+	estimate, err := h.gridService.EstimateCapital(symbol)
+	if err != nil {
+		log.Printf("ERROR: Failed to estimate %s grid capital: %v", symbol, err)
+		http.Error(w, "Failed to estimate capital", http.StatusInternalServerError)
+		return
+	}
 
-	levels, err := h.gridService.GetGridLevels(symbol)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(estimate)
+}
+
+// handleSeedLevels starts a grid sell-first with coins already held on the exchange
+func (h *Handlers) handleSeedLevels(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	var req service.SeedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("ERROR: Invalid seed request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if (req.Amount.Valid && !req.Amount.Decimal.IsPositive()) || (req.Price.Valid && !req.Price.Decimal.IsPositive()) {
+		http.Error(w, "Amount and price must be positive", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("INFO: Seeding %s levels: amount=%s, price=%s, account=%q, dry run: %v",
+		symbol, nullDecimalString(req.Amount), nullDecimalString(req.Price), req.Account, req.DryRun)
+
+	result, err := h.gridService.SeedInventory(symbol, req)
+	if errors.Is(err, service.ErrSeedRejected) {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
-		log.Printf("Error fetching grid levels: %v", err)
-		http.Error(w, "Failed to fetch grid levels", http.StatusInternalServerError)
+		log.Printf("ERROR: Failed to seed %s levels: %v", symbol, err)
+		http.Error(w, "Failed to seed levels", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(levels)
+	json.NewEncoder(w).Encode(result)
 }
 
-func (h *Handlers) handleGetAllGrids(w http.ResponseWriter, r *http.Request) {
-	log.Printf("Fetching all grid levels")
+// handleBulkLevels enables, disables or recovers every level matching a filter in one call
+func (h *Handlers) handleBulkLevels(w http.ResponseWriter, r *http.Request) {
+	var req service.BulkLevelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("ERROR: Invalid bulk request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
 
-	levels, err := h.gridService.GetAllGridLevels()
+	log.Printf("INFO: Bulk %s of %s levels: account=%q, buy price %s-%s, state=%q, dry run: %v",
+		req.Action, req.Symbol, req.Account, nullDecimalString(req.MinPrice), nullDecimalString(req.MaxPrice), req.State, req.DryRun)
+
+	result, done, err := h.runJob(w, r, "levels_bulk", req, func(ctx context.Context) (interface{}, error) {
+		return h.gridService.BulkUpdateLevels(ctx, req)
+	})
+	if done {
+		return
+	}
+	if errors.Is(err, service.ErrBulkRejected) {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
-		log.Printf("Error fetching all grid levels: %v", err)
-		http.Error(w, "Failed to fetch grid levels", http.StatusInternalServerError)
+		log.Printf("ERROR: Failed to %s %s levels: %v", req.Action, req.Symbol, err)
+		http.Error(w, "Failed to update levels", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(levels)
+	json.NewEncoder(w).Encode(result)
 }
 
-func (h *Handlers) handleGetGridSymbols(w http.ResponseWriter, r *http.Request) {
-	symbols, err := h.gridService.GetGridSymbols()
+// handleEditLevel changes the prices, buy amount or enabled flag of one level
+func (h *Handlers) handleEditLevel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
 	if err != nil {
-		log.Printf("ERROR: Failed to fetch grid symbols: %v", err)
-		http.Error(w, "Failed to fetch grid symbols", http.StatusInternalServerError)
+		http.Error(w, "Invalid level ID", http.StatusBadRequest)
 		return
 	}
 
-	log.Printf("INFO: Retrieved %d grid symbols: %v", len(symbols), symbols)
+	var req service.LevelEditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("ERROR: Invalid level edit body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("INFO: Editing level %d: buy_price=%s, sell_price=%s, buy_amount=%s",
+		id, nullDecimalString(req.BuyPrice), nullDecimalString(req.SellPrice), nullDecimalString(req.BuyAmount))
+
+	level, err := h.gridService.EditLevel(id, req)
+	if errors.Is(err, service.ErrLevelEditRejected) {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, service.ErrLevelEditConflict) {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to edit level %d: %v", id, err)
+		http.Error(w, "Failed to edit level", http.StatusInternalServerError)
+		return
+	}
+	if level == nil {
+		http.Error(w, "Level not found", http.StatusNotFound)
+		return
+	}
+	if !rawValues(r) {
+		level = h.gridService.FormatLevel(level)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string][]string{"symbols": symbols})
+	json.NewEncoder(w).Encode(level)
 }
 
-func (h *Handlers) handleStatus(w http.ResponseWriter, r *http.Request) {
-	status, err := h.gridService.GetStatus()
+// handleSetLevelNotes changes the operator tags and notes of one level, whatever its state
+func (h *Handlers) handleSetLevelNotes(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
 	if err != nil {
-		log.Printf("Error getting status: %v", err)
-		http.Error(w, "Failed to get status", http.StatusInternalServerError)
+		http.Error(w, "Invalid level ID", http.StatusBadRequest)
+		return
+	}
+
+	var req service.NotesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("ERROR: Invalid level notes body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	level, err := h.gridService.SetLevelNotes(id, req)
+	if errors.Is(err, service.ErrNotesRejected) {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to update notes of level %d: %v", id, err)
+		http.Error(w, "Failed to update level notes", http.StatusInternalServerError)
+		return
+	}
+	if level == nil {
+		http.Error(w, "Level not found", http.StatusNotFound)
 		return
 	}
+	if !rawValues(r) {
+		level = h.gridService.FormatLevel(level)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(status)
-}
\ No newline at end of file
+	json.NewEncoder(w).Encode(level)
+}
+
+// handleCancelLevelOrder cancels the open order of one level and moves the level back to READY or HOLDING
+func (h *Handlers) handleCancelLevelOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid level ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.gridService.CancelLevelOrder(id)
+	if errors.Is(err, service.ErrNoOpenOrder) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to cancel the order of level %d: %v", id, err)
+		http.Error(w, "Failed to cancel order", http.StatusInternalServerError)
+		return
+	}
+	if result == nil {
+		http.Error(w, "Level not found", http.StatusNotFound)
+		return
+	}
+	if !rawValues(r) && result.Level != nil {
+		result.Level = h.gridService.FormatLevel(result.Level)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleGetSellLadder returns the take-profit ladder of a level and how far its cycle got
+func (h *Handlers) handleGetSellLadder(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid level ID", http.StatusBadRequest)
+		return
+	}
+
+	ladder, err := h.gridService.GetSellLadder(id)
+	if err != nil {
+		log.Printf("ERROR: Failed to get the sell tranches of level %d: %v", id, err)
+		http.Error(w, "Failed to get sell tranches", http.StatusInternalServerError)
+		return
+	}
+	if ladder == nil {
+		http.Error(w, "Level not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ladder)
+}
+
+// PauseGridRequest is the optional body of POST /grids/{symbol}/pause
+type PauseGridRequest struct {
+	CancelOrders bool `json:"cancel_orders"` // Also cancel the symbol's open orders
+}
+
+// handlePauseGrid disables every level of a symbol, optionally cancelling its open orders
+func (h *Handlers) handlePauseGrid(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	// The body is optional - an empty one leaves open orders to fill
+	var req PauseGridRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		log.Printf("ERROR: Invalid pause request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("INFO: Pausing %s, cancel orders: %v", symbol, req.CancelOrders)
+
+	result, err := h.gridService.PauseGrid(symbol, req.CancelOrders)
+	h.writePauseResult(w, symbol, result, err)
+}
+
+// handleResumeGrid enables every level of a symbol again
+func (h *Handlers) handleResumeGrid(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	log.Printf("INFO: Resuming %s", symbol)
+
+	result, err := h.gridService.ResumeGrid(symbol)
+	h.writePauseResult(w, symbol, result, err)
+}
+
+// handleGetGridConfig returns a symbol's grid settings, the defaults if none were saved
+func (h *Handlers) handleGetGridConfig(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	config, err := h.gridService.GetGridConfig(symbol)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to get grid config", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(config)
+}
+
+// handleUpdateGridConfig changes a symbol's grid settings, such as trailing
+func (h *Handlers) handleUpdateGridConfig(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	var req service.GridConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("ERROR: Invalid grid config body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	config, err := h.gridService.UpdateGridConfig(symbol, req)
+	if errors.Is(err, service.ErrGridConfigRejected) {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to update grid config", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(config)
+}
+
+// handleGetShadow shows how a watch-only grid would have traded
+func (h *Handlers) handleGetShadow(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	summary, err := h.gridService.GetShadowSummary(symbol)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to get shadow transactions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(summary)
+}
+
+// handleResetShadow forgets a symbol's shadow transactions
+func (h *Handlers) handleResetShadow(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	deleted, err := h.gridService.ResetShadow(symbol)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to reset shadow transactions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"symbol": symbol, "deleted": deleted})
+}
+
+// handleCreateShadowStrategy starts running an alternative grid in shadow next to the symbol's live grid
+func (h *Handlers) handleCreateShadowStrategy(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	var req service.ShadowStrategyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("ERROR: Invalid shadow strategy body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	strategy, err := h.gridService.CreateShadowStrategy(symbol, req)
+	if errors.Is(err, service.ErrShadowStrategyRejected) {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to create %s shadow strategy: %v", symbol, err)
+		http.Error(w, "Failed to create shadow strategy", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(strategy)
+}
+
+// handleGetShadowStrategies lists the shadow strategies of a symbol
+func (h *Handlers) handleGetShadowStrategies(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	strategies, err := h.gridService.GetShadowStrategies(symbol)
+	if err != nil {
+		log.Printf("ERROR: Failed to get %s shadow strategies: %v", symbol, err)
+		http.Error(w, "Failed to get shadow strategies", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(strategies)
+}
+
+// handleCompareShadowStrategies reports how each shadow strategy of a symbol did against its live grid
+func (h *Handlers) handleCompareShadowStrategies(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	report, err := h.gridService.CompareShadowStrategies(symbol)
+	if err != nil {
+		log.Printf("ERROR: Failed to compare %s shadow strategies: %v", symbol, err)
+		http.Error(w, "Failed to compare shadow strategies", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleDeleteShadowStrategy stops a shadow strategy and forgets its shadow transactions
+func (h *Handlers) handleDeleteShadowStrategy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid shadow strategy ID", http.StatusBadRequest)
+		return
+	}
+
+	deleted, err := h.gridService.DeleteShadowStrategy(vars["symbol"], id)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete shadow strategy %d: %v", id, err)
+		http.Error(w, "Failed to delete shadow strategy", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Shadow strategy not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) writePauseResult(w http.ResponseWriter, symbol string, result *service.PauseResult, err error) {
+	if errors.Is(err, service.ErrNoLevels) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to pause or resume %s: %v", symbol, err)
+		http.Error(w, "Failed to update levels", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleDeleteGrid tears a symbol's grid down: open orders are cancelled, HOLDING levels
+// optionally sold at market (?market_sell=true) and the levels archived
+func (h *Handlers) handleDeleteGrid(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	marketSell := false
+	if v := r.URL.Query().Get("market_sell"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "market_sell must be true or false", http.StatusBadRequest)
+			return
+		}
+		marketSell = parsed
+	}
+
+	log.Printf("INFO: Tearing down %s, market sell: %v", symbol, marketSell)
+
+	result, err := h.gridService.TeardownGrid(symbol, marketSell)
+	if errors.Is(err, service.ErrNoLevels) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	status := http.StatusOK
+	if errors.Is(err, service.ErrTeardownIncomplete) {
+		log.Printf("WARNING: %v", err)
+		status = http.StatusConflict
+	} else if err != nil {
+		log.Printf("ERROR: Failed to tear down %s: %v", symbol, err)
+		http.Error(w, "Failed to tear down grid", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleGetDeadLevels lists the levels the dead-level cleanup would archive, and why some can't be
+func (h *Handlers) handleGetDeadLevels(w http.ResponseWriter, r *http.Request) {
+	report, err := h.gridService.FindDeadLevels()
+	if err != nil {
+		log.Printf("Error finding dead levels: %v", err)
+		http.Error(w, "Failed to find dead levels", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleArchiveDeadLevels runs the dead-level cleanup now; cancel_orders overrides DEAD_LEVEL_CANCEL_ORDERS
+func (h *Handlers) handleArchiveDeadLevels(w http.ResponseWriter, r *http.Request) {
+	cancelOrders := h.gridService.DeadLevelPolicy().CancelOrders
+	if v := r.URL.Query().Get("cancel_orders"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "cancel_orders must be true or false", http.StatusBadRequest)
+			return
+		}
+		cancelOrders = parsed
+	}
+
+	params := map[string]bool{"cancel_orders": cancelOrders}
+	report, done, err := h.runJob(w, r, "dead_levels_archive", params, func(ctx context.Context) (interface{}, error) {
+		return h.gridService.CollectDeadLevels(ctx, cancelOrders)
+	})
+	if done {
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Dead-level cleanup failed: %v", err)
+		http.Error(w, "Failed to archive dead levels", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleSimulateGrid estimates fills and monthly profit of a grid at the current price and volatility
+func (h *Handlers) handleSimulateGrid(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	// The body is optional - an empty one simulates at the latest price over the default window
+	var req service.SimulationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		log.Printf("ERROR: Invalid simulate request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Price.Valid && !req.Price.Decimal.IsPositive() {
+		http.Error(w, "Price must be positive", http.StatusBadRequest)
+		return
+	}
+
+	params := map[string]interface{}{"symbol": symbol, "request": req}
+	result, done, err := h.runJob(w, r, "simulate", params, func(ctx context.Context) (interface{}, error) {
+		return h.gridService.SimulateGrid(symbol, req)
+	})
+	if done {
+		return
+	}
+	if errors.Is(err, service.ErrSimulationRejected) {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to simulate %s grid: %v", symbol, err)
+		http.Error(w, "Failed to simulate grid", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// rawValues reports whether a request asked for prices and amounts as stored (?raw=true) instead
+// of rounded to the symbol's tick and step precision
+func rawValues(r *http.Request) bool {
+	raw, _ := strconv.ParseBool(r.URL.Query().Get("raw"))
+	return raw
+}
+
+func nullDecimalString(value decimal.NullDecimal) string {
+	if !value.Valid {
+		return "none"
+	}
+	return value.Decimal.String()
+}
+
+func (h *Handlers) handleGetGrids(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	symbol := vars["symbol"]
+
+	log.Printf("Fetching grid levels for symbol: %s", symbol)
+
+	levels, err := h.gridService.GetGridLevels(symbol)
+	if err != nil {
+		log.Printf("Error fetching grid levels: %v", err)
+		http.Error(w, "Failed to fetch grid levels", http.StatusInternalServerError)
+		return
+	}
+	levels = service.FilterLevelsByTag(levels, r.URL.Query().Get("tag"))
+	if !rawValues(r) {
+		h.gridService.FormatLevelDetails(levels)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(levels)
+}
+
+func (h *Handlers) handleGetAllGrids(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Fetching all grid levels")
+
+	levels, err := h.gridService.GetAllGridLevels()
+	if err != nil {
+		log.Printf("Error fetching all grid levels: %v", err)
+		http.Error(w, "Failed to fetch grid levels", http.StatusInternalServerError)
+		return
+	}
+	levels = service.FilterLevelsByTag(levels, r.URL.Query().Get("tag"))
+	if !rawValues(r) {
+		h.gridService.FormatLevelDetails(levels)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(levels)
+}
+
+func (h *Handlers) handleGetGridSymbols(w http.ResponseWriter, r *http.Request) {
+	var symbols []string
+	var err error
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		symbols, err = h.gridService.GetTaggedGridSymbols(tag)
+	} else {
+		symbols, err = h.gridService.GetGridSymbols()
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch grid symbols: %v", err)
+		http.Error(w, "Failed to fetch grid symbols", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("INFO: Retrieved %d grid symbols: %v", len(symbols), symbols)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string][]string{"symbols": symbols})
+}
+
+func (h *Handlers) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.gridService.GetStatus()
+	if err != nil {
+		log.Printf("Error getting status: %v", err)
+		http.Error(w, "Failed to get status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(status)
+}
+
+// handleFees reports trading fees paid, valued in USDT where possible
+func (h *Handlers) handleFees(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.gridService.GetFeeStats()
+	if err != nil {
+		log.Printf("Error getting fee stats: %v", err)
+		http.Error(w, "Failed to get fee stats", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
+
+// handlePlacingMetrics counts levels locked in PLACING_BUY/PLACING_SELL and lists stuck placements
+func (h *Handlers) handlePlacingMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics, err := h.gridService.GetPlacingMetrics()
+	if err != nil {
+		log.Printf("Error getting placing metrics: %v", err)
+		http.Error(w, "Failed to get placing metrics", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(metrics)
+}
+
+// handleGetSyncConfig reports the sync job's effective thresholds and switches
+func (h *Handlers) handleGetSyncConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.gridService.SyncSettings())
+}
+
+// handleReplayNotifications applies the missed order-assurance notifications now instead of on
+// the next NOTIFICATION_REPLAY_SEC run
+func (h *Handlers) handleReplayNotifications(w http.ResponseWriter, r *http.Request) {
+	report, err := h.ReplayNotifications()
+	if errors.Is(err, errNotificationReplayOff) {
+		http.Error(w, "Notification replay is not enabled (NOTIFICATION_REPLAY_SEC)", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to replay notifications: %v", err)
+		http.Error(w, "Failed to replay notifications", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleGetSellDust reports the coin left unsold by SELL_QUANTITY_POLICY rounding, per grid
+func (h *Handlers) handleGetSellDust(w http.ResponseWriter, r *http.Request) {
+	dust, err := h.gridService.GetSellDust()
+	if err != nil {
+		log.Printf("Error getting sell dust: %v", err)
+		http.Error(w, "Failed to get sell dust", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"dust": dust})
+}
+
+// Transaction export page size bounds
+const (
+	defaultTransactionsLimit = 500
+	maxTransactionsLimit     = 5000
+)
+
+// handleGetTransactions pages through the transaction log by ID (?after_id=&limit=) as JSON
+// or CSV (?format=csv)
+func (h *Handlers) handleGetTransactions(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, "Invalid format (json, csv)", http.StatusBadRequest)
+		return
+	}
+
+	afterID := 0
+	if v := r.URL.Query().Get("after_id"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid after_id", http.StatusBadRequest)
+			return
+		}
+		afterID = parsed
+	}
+
+	limit := defaultTransactionsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxTransactionsLimit {
+			http.Error(w, "Invalid limit (1-5000)", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := h.gridService.GetTransactionsAfter(afterID, limit)
+	if err != nil {
+		log.Printf("Error getting transactions: %v", err)
+		http.Error(w, "Failed to get transactions", http.StatusInternalServerError)
+		return
+	}
+	if !rawValues(r) {
+		h.gridService.FormatTransactionPage(page)
+	}
+
+	if format == "csv" {
+		h.writeTransactionsCSV(w, page)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(page)
+}
+
+// writeTransactionsCSV writes a transaction page as CSV, with the amount, profit and fee also in
+// REPORT_CURRENCY when one is set (left empty until its FX pair has a price). The next page's
+// after_id goes in the X-Next-After-ID header.
+func (h *Handlers) writeTransactionsCSV(w http.ResponseWriter, page *contracts.TransactionPage) {
+	header := []string{"id", "created_at", "account", "symbol", "grid_level_id", "side", "status", "order_id",
+		"target_price", "executed_price", "amount_coin", "amount_usdt", "profit_usdt", "commission", "commission_asset",
+		"fee_usdt", "error_code"}
+	currency := strings.ToLower(h.gridService.ReportCurrency())
+	rate := h.gridService.ReportingRate()
+	if currency != "" {
+		header = append(header, "fx_rate", "amount_"+currency, "profit_"+currency, "fee_"+currency)
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="transactions.csv"`)
+	w.Header().Set("X-Next-After-ID", strconv.Itoa(page.NextAfterID))
+	writer := csv.NewWriter(w)
+	writer.Write(header)
+	for _, tx := range page.Transactions {
+		row := []string{
+			strconv.Itoa(tx.ID), tx.CreatedAt.Format(time.RFC3339), tx.Account, tx.Symbol, strconv.Itoa(tx.GridLevelID),
+			tx.Side, tx.Status, tx.OrderID, tx.TargetPrice.String(), csvDecimal(tx.ExecutedPrice), csvDecimal(tx.AmountCoin),
+			csvDecimal(tx.AmountUSDT), csvDecimal(tx.ProfitUSDT), csvDecimal(tx.Commission), tx.CommissionAsset,
+			csvDecimal(tx.FeeUSDT), tx.ErrorCode,
+		}
+		if currency != "" {
+			if rate == nil {
+				row = append(row, "", "", "", "")
+			} else {
+				row = append(row, rate.Rate.String(), csvConverted(rate, tx.AmountUSDT), csvConverted(rate, tx.ProfitUSDT),
+					csvConverted(rate, tx.FeeUSDT))
+			}
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+}
+
+func csvDecimal(value *decimal.Decimal) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+func csvConverted(rate *service.FXRate, usdt *decimal.Decimal) string {
+	if usdt == nil {
+		return ""
+	}
+	return rate.Convert(*usdt).String()
+}
+
+// handleUnrealizedPnL values held coins at the latest known prices
+func (h *Handlers) handleUnrealizedPnL(w http.ResponseWriter, r *http.Request) {
+	pnl, err := h.gridService.GetUnrealizedPnL()
+	if err != nil {
+		log.Printf("Error getting unrealized pnl: %v", err)
+		http.Error(w, "Failed to get unrealized pnl", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(pnl)
+}
+
+// handleBenchmark compares each grid with buying and holding the same capital
+func (h *Handlers) handleBenchmark(w http.ResponseWriter, r *http.Request) {
+	benchmark, err := h.gridService.GetBenchmark()
+	if err != nil {
+		log.Printf("Error getting benchmark: %v", err)
+		http.Error(w, "Failed to get benchmark", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(benchmark)
+}
+
+// handleSendSummary sends the periodic summary now, e.g. to check the notifier channels
+func (h *Handlers) handleSendSummary(w http.ResponseWriter, r *http.Request) {
+	if err := h.gridService.SendSummary(); err != nil {
+		log.Printf("Error sending summary: %v", err)
+		http.Error(w, "Failed to send summary: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"status": "sent"})
+}
+
+// handleSendDailyReport composes, stores and sends today's report now
+func (h *Handlers) handleSendDailyReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.gridService.SendDailyReport()
+	if err != nil {
+		log.Printf("Error sending daily report: %v", err)
+		http.Error(w, "Failed to send daily report: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleGetDailyReport returns the stored report of a date (in the reporting timezone)
+func (h *Handlers) handleGetDailyReport(w http.ResponseWriter, r *http.Request) {
+	date := mux.Vars(r)["date"]
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		http.Error(w, "Invalid date - expected YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.gridService.GetDailyReport(date)
+	if err != nil {
+		log.Printf("Error getting daily report: %v", err)
+		http.Error(w, "Failed to get daily report", http.StatusInternalServerError)
+		return
+	}
+	if report == nil {
+		http.Error(w, "No report for "+date, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleRunReconciliation checks the levels against the exchange now and returns the stored report
+func (h *Handlers) handleRunReconciliation(w http.ResponseWriter, r *http.Request) {
+	report, done, err := h.runJob(w, r, "reconciliation", nil, func(ctx context.Context) (interface{}, error) {
+		return h.gridService.Reconcile(ctx)
+	})
+	if done {
+		return
+	}
+	if err != nil {
+		log.Printf("Error running reconciliation: %v", err)
+		http.Error(w, "Failed to run reconciliation: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleGetLatestReconciliation returns the newest reconciliation report
+func (h *Handlers) handleGetLatestReconciliation(w http.ResponseWriter, r *http.Request) {
+	report, err := h.gridService.GetLatestReconciliation()
+	if err != nil {
+		log.Printf("Error getting reconciliation report: %v", err)
+		http.Error(w, "Failed to get reconciliation report", http.StatusInternalServerError)
+		return
+	}
+	if report == nil {
+		http.Error(w, "No reconciliation has run yet", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleGetSelfCheck returns the startup self-check and whether grid-trading is in safe mode
+func (h *Handlers) handleGetSelfCheck(w http.ResponseWriter, r *http.Request) {
+	report := h.gridService.LastSelfCheck()
+	if report == nil {
+		http.Error(w, "No self-check has run", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleGetConsistency returns the latest grid consistency check, the startup one unless rerun
+func (h *Handlers) handleGetConsistency(w http.ResponseWriter, r *http.Request) {
+	report := h.gridService.LastConsistencyCheck()
+	if report == nil {
+		http.Error(w, "No consistency check has run", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleCheckConsistency checks the levels against the grid invariants again, e.g. after fixing some
+func (h *Handlers) handleCheckConsistency(w http.ResponseWriter, r *http.Request) {
+	report, err := h.gridService.CheckConsistency()
+	if err != nil {
+		log.Printf("ERROR: Grid consistency check failed: %v", err)
+		http.Error(w, "Failed to check grid consistency", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleResumeFromSafeMode confirms the state is sound and lets grid-trading trade again
+func (h *Handlers) handleResumeFromSafeMode(w http.ResponseWriter, r *http.Request) {
+	resumed := h.gridService.ResumeFromSafeMode()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]bool{"resumed": resumed})
+}
+
+// safeModeAllowed are the writes served in safe mode: the resume itself, rechecking consistency
+// before it, and what price-monitor and order-assurance send - triggers place nothing in safe
+// mode, and fills already happened
+var safeModeAllowed = map[string]bool{
+	"/safe-mode/resume":              true,
+	"/consistency/check":             true,
+	"/trigger-for-price":             true,
+	"/order-fill-notification":       true,
+	"/order-fill-error-notification": true,
+	"/exchange-status":               true,
+	"/notifications/replay":          true,
+}
+
+// SafeModeMiddleware answers every other state-changing request with 503 while in safe mode
+func (h *Handlers) SafeModeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && !safeModeAllowed[r.URL.Path] {
+			if inSafeMode, reason := h.gridService.SafeMode(); inSafeMode {
+				http.Error(w, "grid-trading is in safe mode ("+reason+") - check GET /self-check, then POST /safe-mode/resume", http.StatusServiceUnavailable)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// klineParams reads ?interval= (default 1h) and the [from, to) range, RFC3339 or YYYY-MM-DD (UTC)
+func klineParams(r *http.Request) (interval string, from, to time.Time, err error) {
+	q := r.URL.Query()
+	interval = q.Get("interval")
+	if interval == "" {
+		interval = "1h"
+	}
+	if from, err = parseTimeParam(q.Get("from")); err != nil {
+		return "", from, to, errors.New("Invalid from (RFC3339 or YYYY-MM-DD)")
+	}
+	if to, err = parseTimeParam(q.Get("to")); err != nil {
+		return "", from, to, errors.New("Invalid to (RFC3339 or YYYY-MM-DD)")
+	}
+	return interval, from, to, nil
+}
+
+// parseTimeParam accepts RFC3339 or a bare date (midnight UTC); empty means unset
+func parseTimeParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+// handleDownloadKlines stores a symbol's Binance klines of a range for backtests and post-mortems
+func (h *Handlers) handleDownloadKlines(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+	interval, from, to, err := klineParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params := map[string]interface{}{"symbol": symbol, "interval": interval, "from": from, "to": to}
+	download, done, err := h.runJob(w, r, "klines_download", params, func(ctx context.Context) (interface{}, error) {
+		return h.gridService.DownloadKlines(symbol, interval, from, to)
+	})
+	if done {
+		return
+	}
+	if errors.Is(err, service.ErrKlineRequestRejected) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to download %s klines: %v", symbol, err)
+		http.Error(w, "Failed to download klines", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(download)
+}
+
+// handleGetKlines exports a symbol's stored klines as JSON or CSV (?format=csv); nothing is downloaded
+func (h *Handlers) handleGetKlines(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.ToUpper(mux.Vars(r)["symbol"])
+	interval, from, to, err := klineParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, "Invalid format (json, csv)", http.StatusBadRequest)
+		return
+	}
+
+	stored, err := h.gridService.GetKlines(symbol, interval, from, to)
+	if errors.Is(err, service.ErrKlineRequestRejected) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to read %s klines: %v", symbol, err)
+		http.Error(w, "Failed to read klines", http.StatusInternalServerError)
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+symbol+`_`+interval+`.csv"`)
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"open_time", "close_time", "open", "high", "low", "close", "volume", "quote_volume", "trades"})
+		for _, k := range stored {
+			writer.Write([]string{
+				k.OpenTime.Format(time.RFC3339), k.CloseTime.Format(time.RFC3339Nano), k.Open.String(), k.High.String(),
+				k.Low.String(), k.Close.String(), k.Volume.String(), k.QuoteVolume.String(), strconv.Itoa(k.Trades),
+			})
+		}
+		writer.Flush()
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"symbol": symbol, "interval": interval, "klines": stored})
+}
+
+// handleGetWeeklyDigest returns the digest of the seven days up to now, or up to the start
+// of the date in ?to=YYYY-MM-DD (in the reporting timezone)
+func (h *Handlers) handleGetWeeklyDigest(w http.ResponseWriter, r *http.Request) {
+	to := time.Now()
+	if v := r.URL.Query().Get("to"); v != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", v, h.gridService.ReportTimezone())
+		if err != nil {
+			http.Error(w, "Invalid to - expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = parsed
+	}
+
+	digest, err := h.gridService.GetWeeklyDigest(to)
+	if err != nil {
+		log.Printf("Error getting weekly digest: %v", err)
+		http.Error(w, "Failed to get weekly digest", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(digest)
+}
+
+// handleSendWeeklyDigest sends the digest of the last seven days now
+func (h *Handlers) handleSendWeeklyDigest(w http.ResponseWriter, r *http.Request) {
+	digest, err := h.gridService.SendWeeklyDigest()
+	if err != nil {
+		log.Printf("Error sending weekly digest: %v", err)
+		http.Error(w, "Failed to send weekly digest: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(digest)
+}
+
+// handleGetCapitalReturn reports ROI against the capital deposited
+func (h *Handlers) handleGetCapitalReturn(w http.ResponseWriter, r *http.Request) {
+	capital, err := h.gridService.GetCapitalReturn()
+	if err != nil {
+		log.Printf("ERROR: Failed to get capital return: %v", err)
+		http.Error(w, "Failed to get capital return", http.StatusInternalServerError)
+		return
+	}
+	if capital == nil {
+		http.Error(w, "No deposits recorded (POST /capital/flows)", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(capital)
+}
+
+// handleCreateCapitalFlow records a deposit or withdrawal
+func (h *Handlers) handleCreateCapitalFlow(w http.ResponseWriter, r *http.Request) {
+	var req service.CapitalFlowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("ERROR: Invalid capital flow body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	flow, err := h.gridService.RecordCapitalFlow(req)
+	if errors.Is(err, service.ErrCapitalFlowRejected) {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to record capital flow: %v", err)
+		http.Error(w, "Failed to record capital flow", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(flow)
+}
+
+// handleGetCapitalFlows lists the deposits and withdrawals, optionally of one source (?source=)
+func (h *Handlers) handleGetCapitalFlows(w http.ResponseWriter, r *http.Request) {
+	flows, err := h.gridService.GetCapitalFlows(r.URL.Query().Get("source"))
+	if err != nil {
+		log.Printf("ERROR: Failed to get capital flows: %v", err)
+		http.Error(w, "Failed to get capital flows", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(flows)
+}
+
+// handleDeleteCapitalFlow removes a flow recorded by mistake
+func (h *Handlers) handleDeleteCapitalFlow(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid capital flow ID", http.StatusBadRequest)
+		return
+	}
+
+	deleted, err := h.gridService.DeleteCapitalFlow(id)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete capital flow %d: %v", id, err)
+		http.Error(w, "Failed to delete capital flow", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Capital flow not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleImportCalendar stores trading calendar events, updating those imported before
+func (h *Handlers) handleImportCalendar(w http.ResponseWriter, r *http.Request) {
+	var req service.CalendarImport
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("ERROR: Invalid calendar body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	windows, err := h.gridService.ImportCalendar(req, service.CalendarSourceAPI)
+	if errors.Is(err, service.ErrCalendarEventRejected) {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to import calendar events: %v", err)
+		http.Error(w, "Failed to import calendar events", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"imported": len(req.Events), "events": windows})
+}
+
+// handleGetCalendar lists the calendar events not over yet, or all of them with ?all=true
+func (h *Handlers) handleGetCalendar(w http.ResponseWriter, r *http.Request) {
+	windows, err := h.gridService.GetCalendar(r.URL.Query().Get("all") == "true")
+	if err != nil {
+		log.Printf("ERROR: Failed to get calendar events: %v", err)
+		http.Error(w, "Failed to get calendar events", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"events": windows})
+}
+
+// handleDeleteCalendarEvent removes a calendar event, ending the pause it holds
+func (h *Handlers) handleDeleteCalendarEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid calendar event ID", http.StatusBadRequest)
+		return
+	}
+
+	deleted, err := h.gridService.DeleteCalendarEvent(id)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete calendar event %d: %v", id, err)
+		http.Error(w, "Failed to delete calendar event", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Calendar event not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetProfitSweeps lists the latest profit sweep audit records
+func (h *Handlers) handleGetProfitSweeps(w http.ResponseWriter, r *http.Request) {
+	sweeps, err := h.sweeper.GetRecent(100)
+	if err != nil {
+		log.Printf("Error getting profit sweeps: %v", err)
+		http.Error(w, "Failed to get profit sweeps", http.StatusInternalServerError)
+		return
+	}
+
+	if sweeps == nil {
+		sweeps = []*models.ProfitSweep{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"sweeps": sweeps})
+}
+
+// handleRunProfitSweep runs a sweep now with the configured threshold, destination and dry-run mode
+func (h *Handlers) handleRunProfitSweep(w http.ResponseWriter, r *http.Request) {
+	sweeps, err := h.sweeper.Run()
+	if err != nil {
+		log.Printf("Error running profit sweep: %v", err)
+		http.Error(w, "Failed to run profit sweep", http.StatusInternalServerError)
+		return
+	}
+
+	if sweeps == nil {
+		sweeps = []*models.ProfitSweep{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"sweeps": sweeps})
+}
+
+// handleFuturesStatus compares futures grids with their positions and totals funding fees
+// handleGetShards returns the grid-trading instances and which symbols each trades
+func (h *Handlers) handleGetShards(w http.ResponseWriter, r *http.Request) {
+	if h.shards == nil {
+		http.Error(w, "Sharding is not enabled (SHARDING_ENABLED)", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.shards.ShardMap())
+}
+
+func (h *Handlers) handleFuturesStatus(w http.ResponseWriter, r *http.Request) {
+	if h.futures == nil {
+		http.Error(w, "Futures mode is not enabled (FUTURES_ENABLED)", http.StatusNotFound)
+		return
+	}
+
+	status, err := h.futures.GetStatus()
+	if err != nil {
+		log.Printf("Error getting futures status: %v", err)
+		http.Error(w, "Failed to get futures status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(status)
+}
+
+// handleFuturesSync records new funding payments and checks positions now
+func (h *Handlers) handleFuturesSync(w http.ResponseWriter, r *http.Request) {
+	if h.futures == nil {
+		http.Error(w, "Futures mode is not enabled (FUTURES_ENABLED)", http.StatusNotFound)
+		return
+	}
+
+	if err := h.futures.Sync(); err != nil {
+		log.Printf("Error syncing futures: %v", err)
+		http.Error(w, "Failed to sync futures: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.handleFuturesStatus(w, r)
+}
+
+// handleMarginStatus reports margin debts and the margin grids' profit net of interest
+func (h *Handlers) handleMarginStatus(w http.ResponseWriter, r *http.Request) {
+	if h.margin == nil {
+		http.Error(w, "Margin mode is not enabled (MARGIN_ENABLED)", http.StatusNotFound)
+		return
+	}
+
+	status, err := h.margin.GetStatus()
+	if err != nil {
+		log.Printf("Error getting margin status: %v", err)
+		http.Error(w, "Failed to get margin status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(status)
+}
+
+// handleMarginSync records new interest charges now
+func (h *Handlers) handleMarginSync(w http.ResponseWriter, r *http.Request) {
+	if h.margin == nil {
+		http.Error(w, "Margin mode is not enabled (MARGIN_ENABLED)", http.StatusNotFound)
+		return
+	}
+
+	if err := h.margin.Sync(); err != nil {
+		log.Printf("Error syncing margin interest: %v", err)
+		http.Error(w, "Failed to sync margin interest: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.handleMarginStatus(w, r)
+}
+
+// handleCreateWebhook registers a webhook. The response carries the signing secret, the only time it is shown.
+func (h *Handlers) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.webhooks == nil {
+		http.Error(w, "Webhooks are not enabled (WEBHOOKS_ENABLED)", http.StatusNotFound)
+		return
+	}
+
+	var req models.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("ERROR: Invalid webhook request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := h.webhooks.Register(&req)
+	if errors.Is(err, service.ErrWebhookRejected) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to register webhook: %v", err)
+		http.Error(w, "Failed to register webhook", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// handleOperatorActions lists the logged operator actions, newest first
+func (h *Handlers) handleOperatorActions(w http.ResponseWriter, r *http.Request) {
+	if h.operatorLog == nil {
+		http.Error(w, "Operator log not enabled", http.StatusNotFound)
+		return
+	}
+	h.operatorLog.ServeHTTP(w, r)
+}
+
+// handleGetWebhooks lists registered webhooks without their secrets
+func (h *Handlers) handleGetWebhooks(w http.ResponseWriter, r *http.Request) {
+	if h.webhooks == nil {
+		http.Error(w, "Webhooks are not enabled (WEBHOOKS_ENABLED)", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.webhooks.List())
+}
+
+// handleDeleteWebhook removes a webhook and drops its undelivered events
+func (h *Handlers) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.webhooks == nil {
+		http.Error(w, "Webhooks are not enabled (WEBHOOKS_ENABLED)", http.StatusNotFound)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	deleted, err := h.webhooks.Delete(id)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete webhook %d: %v", id, err)
+		http.Error(w, "Failed to delete webhook", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetWebhookDeliveries lists the latest webhook deliveries, the dead-letter set by default
+func (h *Handlers) handleGetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if h.webhooks == nil {
+		http.Error(w, "Webhooks are not enabled (WEBHOOKS_ENABLED)", http.StatusNotFound)
+		return
+	}
+
+	status := models.WebhookDeliveryStatus(r.URL.Query().Get("status"))
+	if status == "" {
+		status = models.WebhookDead
+	}
+	if status != models.WebhookPending && status != models.WebhookDead && status != models.WebhookDelivered {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	deliveries, err := h.webhooks.GetDeliveries(status)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch webhook deliveries: %v", err)
+		http.Error(w, "Failed to fetch webhook deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []*models.WebhookDelivery{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"deliveries": deliveries})
+}
+
+// handleRequeueWebhookDelivery sends a dead-letter delivery again
+func (h *Handlers) handleRequeueWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	if h.webhooks == nil {
+		http.Error(w, "Webhooks are not enabled (WEBHOOKS_ENABLED)", http.StatusNotFound)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid delivery ID", http.StatusBadRequest)
+		return
+	}
+
+	requeued, err := h.webhooks.Requeue(id)
+	if err != nil {
+		log.Printf("ERROR: Failed to requeue webhook delivery %d: %v", id, err)
+		http.Error(w, "Failed to requeue webhook delivery", http.StatusInternalServerError)
+		return
+	}
+	if !requeued {
+		http.Error(w, "Dead-letter delivery not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "requeued"})
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/oplog"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/grid-trading-bot/services/grid-trading/internal/service"
+)
+
+const (
+	defaultJobsLimit = 50
+	maxJobsLimit     = 500
+)
+
+// UseJobs runs simulations, reconciliations, kline downloads and bulk changes on the job workers
+// and serves the jobs on /jobs
+func (h *Handlers) UseJobs(jobs *service.JobRunner) {
+	h.jobs = jobs
+}
+
+// runJob runs fn as a job of kind. With ?async=true it answers 202 with the queued job right away;
+// otherwise it waits and returns what fn returned for the handler to answer as usual. done is
+// true once runJob has answered the request itself (async, queue full, job cancelled).
+func (h *Handlers) runJob(w http.ResponseWriter, r *http.Request, kind string, params interface{}, fn service.JobFunc) (result interface{}, done bool, err error) {
+	if h.jobs == nil {
+		result, err = fn(r.Context())
+		return result, false, err
+	}
+
+	actor := oplog.ActorFrom(r.Context())
+	if async, _ := strconv.ParseBool(r.URL.Query().Get("async")); async {
+		job, err := h.jobs.Submit(kind, params, actor, fn)
+		if err != nil {
+			h.jobError(w, kind, err)
+			return nil, true, nil
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", fmt.Sprintf("/jobs/%d", job.ID))
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(job)
+		return nil, true, nil
+	}
+
+	result, err = h.jobs.Run(r.Context(), kind, params, actor, fn)
+	if errors.Is(err, service.ErrJobQueueFull) || errors.Is(err, service.ErrJobCancelled) {
+		h.jobError(w, kind, err)
+		return nil, true, nil
+	}
+	return result, false, err
+}
+
+func (h *Handlers) jobError(w http.ResponseWriter, kind string, err error) {
+	switch {
+	case errors.Is(err, service.ErrJobQueueFull):
+		log.Printf("WARNING: Refused %s job: %v", kind, err)
+		w.Header().Set("Retry-After", "10")
+		http.Error(w, "Too many jobs queued, retry later", http.StatusServiceUnavailable)
+	case errors.Is(err, service.ErrJobCancelled):
+		http.Error(w, "The "+kind+" job was cancelled", http.StatusConflict)
+	default:
+		log.Printf("ERROR: Failed to queue %s job: %v", kind, err)
+		http.Error(w, "Failed to queue job", http.StatusInternalServerError)
+	}
+}
+
+// handleGetJobs lists the newest jobs (?kind=&status=&limit=)
+func (h *Handlers) handleGetJobs(w http.ResponseWriter, r *http.Request) {
+	if h.jobs == nil {
+		http.Error(w, "Jobs are not enabled", http.StatusNotFound)
+		return
+	}
+
+	status := models.JobStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", models.JobQueued, models.JobRunning, models.JobSucceeded, models.JobFailed, models.JobCancelled:
+	default:
+		http.Error(w, "Invalid status (QUEUED, RUNNING, SUCCEEDED, FAILED, CANCELLED)", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultJobsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxJobsLimit {
+			http.Error(w, "Invalid limit (1-500)", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	jobs, err := h.jobs.List(r.URL.Query().Get("kind"), status, limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to list jobs: %v", err)
+		http.Error(w, "Failed to list jobs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(jobs)
+}
+
+// handleGetJob returns a job with its progress, and its result once finished
+func (h *Handlers) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.findJob(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(job)
+}
+
+// handleCancelJob cancels a queued or running job; a running one stops at its next step
+func (h *Handlers) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.findJob(w, r)
+	if !ok {
+		return
+	}
+	if err := h.jobs.Cancel(job); err != nil {
+		status := http.StatusConflict
+		if errors.Is(err, service.ErrJobElsewhere) {
+			status = http.StatusMisdirectedRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"id": job.ID, "cancelled": true})
+}
+
+// findJob reads the job of the {id} route variable, answering 404 if there is none
+func (h *Handlers) findJob(w http.ResponseWriter, r *http.Request) (*models.Job, bool) {
+	if h.jobs == nil {
+		http.Error(w, "Jobs are not enabled", http.StatusNotFound)
+		return nil, false
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	job, err := h.jobs.Get(id)
+	if err != nil {
+		log.Printf("ERROR: Failed to get job %d: %v", id, err)
+		http.Error(w, "Failed to get job", http.StatusInternalServerError)
+		return nil, false
+	}
+	if job == nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return nil, false
+	}
+	return job, true
+}
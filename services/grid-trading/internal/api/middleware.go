@@ -0,0 +1,99 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/oplog"
+)
+
+// APIKeyHeader carries the shared key price-monitor, order-assurance, analytics and the
+// gateway use to call grid-trading
+const APIKeyHeader = "X-API-Key"
+
+// BearerToken lets an operator or dashboard manage grids without the shared key
+// (GRID_TRADING_TOKENS), e.g. through a reverse proxy
+type BearerToken struct {
+	Name  string
+	Scope string // contracts.ScopeRead, ScopeWrite or ScopeAdmin
+	Token string
+}
+
+// servicePaths are what price-monitor and order-assurance call on their own; only the shared
+// key can reach them, so no bearer token can send triggers or spoof fills
+var servicePaths = map[string]bool{
+	"/trigger-for-price":             true,
+	"/order-fill-notification":       true,
+	"/order-fill-error-notification": true,
+	"/exchange-status":               true,
+}
+
+// APIKeyMiddleware rejects requests without the shared API key or a bearer token with the scope
+// the request needs, so nobody else on the network can send price triggers, spoof fill
+// notifications or change grids. Plain health checks stay open so orchestrators can probe the
+// service. Requests with the shared key act as the contracts.ActorHeader the gateway sends, else
+// as api-key; bearer token requests as token:<name>.
+func APIKeyMiddleware(apiKey string, tokens []BearerToken) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get(APIKeyHeader)), []byte(apiKey)) == 1 {
+				actor := r.Header.Get(contracts.ActorHeader)
+				if actor == "" {
+					actor = "api-key"
+				}
+				next.ServeHTTP(w, r.WithContext(oplog.WithActor(r.Context(), actor)))
+				return
+			}
+
+			token := bearerToken(r, tokens)
+			if token == nil {
+				log.Printf("WARNING: Rejected unauthenticated request %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+				writeAuthError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+			if servicePaths[r.URL.Path] {
+				log.Printf("WARNING: Token %q tried service endpoint %s %s", token.Name, r.Method, r.URL.Path)
+				writeAuthError(w, http.StatusForbidden, "service endpoint, needs the shared API key")
+				return
+			}
+			if required := contracts.RequiredScope(r.Method, r.URL.Path); !contracts.HasScope([]string{token.Scope}, required) {
+				log.Printf("WARNING: Token %q lacks %s scope for %s %s", token.Name, required, r.Method, r.URL.Path)
+				writeAuthError(w, http.StatusForbidden, "token lacks the "+required+" scope")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(oplog.WithActor(r.Context(), "token:"+token.Name)))
+		})
+	}
+}
+
+// bearerToken returns the token sent as "Authorization: Bearer <token>", nil if none matches
+func bearerToken(r *http.Request, tokens []BearerToken) *BearerToken {
+	provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || provided == "" {
+		return nil
+	}
+	var match *BearerToken
+	for i := range tokens {
+		// Compare with every token, so the time taken doesn't tell which one came close
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(tokens[i].Token)) == 1 {
+			match = &tokens[i]
+		}
+	}
+	return match
+}
+
+func writeAuthError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+)
+
+const (
+	notificationReplayPage = 200
+	// Younger notifications may still be on their way - in-memory retries or the outbox
+	notificationReplayGrace = 30 * time.Second
+)
+
+// errNotificationReplayOff means NOTIFICATION_REPLAY_SEC is 0
+var errNotificationReplayOff = errors.New("notification replay is not enabled")
+
+// NotificationSource serves order-assurance's numbered notifications
+type NotificationSource interface {
+	GetNotifications(afterSeq int64, limit int) (*contracts.NotificationReplay, error)
+}
+
+// NotificationCursor stores the seq up to which every notification was applied
+type NotificationCursor interface {
+	Get() (int64, bool, error)
+	Save(seq int64) error
+}
+
+// NotificationReplayReport is what one replay run did
+type NotificationReplayReport struct {
+	AfterSeq int64 `json:"after_seq"` // Cursor before the run
+	LastSeq  int64 `json:"last_seq"`  // Cursor after the run
+	Replayed int   `json:"replayed"`  // Missed notifications applied now
+	Skipped  int   `json:"skipped"`   // Already received by this instance
+}
+
+// notificationReplay finds the fill and error notifications that never arrived - grid-trading
+// was down, a delivery was dropped or dead-lettered - by their sequence numbers and applies
+// them from order-assurance's log. Applying a notification twice is harmless: the level's state
+// no longer matches and it is skipped, so only what this instance saw is tracked.
+type notificationReplay struct {
+	source NotificationSource
+	cursor NotificationCursor
+
+	run  sync.Mutex // One replay at a time
+	mu   sync.Mutex
+	seen map[int64]bool // Seqs received above the cursor
+}
+
+// UseNotificationReplay records the seq of every notification received and lets
+// ReplayNotifications pull the missed ones from source
+func (h *Handlers) UseNotificationReplay(source NotificationSource, cursor NotificationCursor) {
+	h.replay = &notificationReplay{source: source, cursor: cursor, seen: make(map[int64]bool)}
+}
+
+// noteNotification remembers an applied notification, so a replay doesn't apply it again
+func (h *Handlers) noteNotification(seq int64) {
+	if h.replay == nil || seq <= 0 {
+		return
+	}
+	h.replay.mu.Lock()
+	defer h.replay.mu.Unlock()
+	h.replay.seen[seq] = true
+}
+
+// ReplayNotifications applies the notifications after the cursor that this instance never
+// received, oldest first, and moves the cursor past them. It stops at a notification younger
+// than the grace period or one that fails to apply; the next run continues there. The first
+// run starts the cursor at the end of the log - older gaps are left to the sync job.
+func (h *Handlers) ReplayNotifications() (*NotificationReplayReport, error) {
+	r := h.replay
+	if r == nil {
+		return nil, errNotificationReplayOff
+	}
+	r.run.Lock()
+	defer r.run.Unlock()
+
+	cursor, ok, err := r.cursor.Get()
+	if err != nil {
+		return nil, err
+	}
+	report := &NotificationReplayReport{AfterSeq: cursor, LastSeq: cursor}
+
+	for {
+		page, err := r.source.GetNotifications(cursor, notificationReplayPage)
+		if err != nil {
+			return report, err
+		}
+
+		if !ok {
+			log.Printf("INFO: Notification replay starts after seq %d", page.LastSeq)
+			report.LastSeq = page.LastSeq
+			return report, r.cursor.Save(page.LastSeq)
+		}
+		if page.LastSeq < cursor {
+			// order-assurance lost its log (new database): replay all of the new one
+			log.Printf("WARNING: order-assurance notification log ends at seq %d, before the cursor %d - replaying it from the start", page.LastSeq, cursor)
+			cursor = 0
+			r.forget(-1)
+			continue
+		}
+
+		done := len(page.Notifications) < notificationReplayPage
+		for _, notification := range page.Notifications {
+			if time.Since(notification.CreatedAt) < notificationReplayGrace {
+				done = true
+				break
+			}
+			if r.received(notification.Seq) {
+				report.Skipped++
+			} else if err := h.replayNotification(notification); err != nil {
+				log.Printf("ERROR: Failed to replay notification %d, retrying on the next run: %v", notification.Seq, err)
+				done = true
+				break
+			} else {
+				report.Replayed++
+			}
+			cursor = notification.Seq
+		}
+
+		if cursor != report.LastSeq {
+			if err := r.cursor.Save(cursor); err != nil {
+				return report, err
+			}
+			r.forget(cursor)
+			report.LastSeq = cursor
+		}
+		if done {
+			break
+		}
+	}
+
+	if report.Replayed > 0 {
+		log.Printf("WARNING: Replayed %d missed notifications (seq %d-%d)", report.Replayed, report.AfterSeq+1, report.LastSeq)
+	}
+	return report, nil
+}
+
+func (h *Handlers) replayNotification(notification contracts.ReplayedNotification) error {
+	var err error
+	switch {
+	case notification.Fill != nil:
+		log.Printf("INFO: Replaying missed fill notification %d", notification.Seq)
+		_, err = h.processFillNotification(*notification.Fill)
+	case notification.Error != nil:
+		log.Printf("INFO: Replaying missed error notification %d", notification.Seq)
+		_, err = h.processErrorNotification(*notification.Error)
+	}
+	if errors.Is(err, errInvalidSide) {
+		return nil // Can never be applied
+	}
+	return err
+}
+
+func (r *notificationReplay) received(seq int64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.seen[seq]
+}
+
+// forget drops the seqs up to the cursor, all of them with -1
+func (r *notificationReplay) forget(cursor int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for seq := range r.seen {
+		if cursor < 0 || seq <= cursor {
+			delete(r.seen, seq)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/pkg/natsjs"
+)
+
+const (
+	// Long-poll for the next message - the server answers as soon as one arrives
+	queueFetchWait = 5 * time.Second
+	// Unacked messages (e.g. we crashed mid-processing) are redelivered after this
+	queueAckWait = 60 * time.Second
+	// Failed messages are retried after this instead of straight away
+	queueRetryDelay = 5 * time.Second
+)
+
+// QueueConsumer applies triggers and notifications delivered over NATS JetStream
+// (TRANSPORT=nats). A message is acked only once applied, so one that fails or is
+// in flight during a crash is delivered again.
+type QueueConsumer struct {
+	handlers *Handlers
+	js       *natsjs.JetStream
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewQueueConsumer(handlers *Handlers, js *natsjs.JetStream) *QueueConsumer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &QueueConsumer{
+		handlers: handlers,
+		js:       js,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start creates the streams and durable consumers if needed and begins consuming
+func (q *QueueConsumer) Start() error {
+	consumers := []struct {
+		stream  natsjs.StreamConfig
+		durable string
+		apply   func(*natsjs.Msg) error
+	}{
+		{contracts.TriggerStream, "grid-trading-triggers", q.applyTrigger},
+		// One consumer for fills and errors keeps them in publish order
+		{contracts.NotificationStream, "grid-trading-notifications", q.applyNotification},
+	}
+
+	for _, c := range consumers {
+		if err := q.js.EnsureStream(c.stream); err != nil {
+			return err
+		}
+
+		consumer, err := q.js.EnsureConsumer(c.stream.Name, natsjs.ConsumerConfig{
+			Durable:       c.durable,
+			DeliverPolicy: "all",
+			AckPolicy:     "explicit",
+			ReplayPolicy:  "instant",
+			AckWait:       queueAckWait,
+		})
+		if err != nil {
+			return err
+		}
+
+		q.wg.Add(1)
+		go q.consume(c.durable, consumer, c.apply)
+	}
+
+	return nil
+}
+
+func (q *QueueConsumer) Stop() {
+	q.cancel()
+	q.wg.Wait()
+}
+
+// consume processes messages one at a time, so a symbol's triggers and an order's notifications never race
+func (q *QueueConsumer) consume(name string, consumer *natsjs.Consumer, apply func(*natsjs.Msg) error) {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		default:
+		}
+
+		msgs, err := consumer.Fetch(1, queueFetchWait)
+		if err != nil {
+			slog.Error("Failed to fetch from queue", "consumer", name, logging.Err(err))
+			select {
+			case <-q.ctx.Done():
+				return
+			case <-time.After(queueRetryDelay):
+			}
+		}
+
+		for _, msg := range msgs {
+			q.settle(msg, apply(msg))
+		}
+	}
+}
+
+// settle acks applied and unprocessable messages and asks for the rest to be redelivered
+func (q *QueueConsumer) settle(msg *natsjs.Msg, err error) {
+	switch {
+	case err == nil:
+		err = msg.Ack()
+	case errors.Is(err, errInvalidSide), errors.Is(err, errMalformedMessage):
+		slog.Error("Dropping unprocessable message", "subject", msg.Subject, "data", string(msg.Data), logging.Err(err))
+		err = msg.Ack()
+	default:
+		slog.Warn("Message failed, redelivering", "subject", msg.Subject, "retry_after", queueRetryDelay, logging.Err(err))
+		err = msg.Nak(queueRetryDelay)
+	}
+
+	if err != nil {
+		// Unsettled messages are redelivered once the ack wait expires
+		slog.Error("Failed to settle message", "subject", msg.Subject, logging.Err(err))
+	}
+}
+
+// errMalformedMessage marks a payload that can't be decoded
+var errMalformedMessage = errors.New("malformed message")
+
+func decodeMessage(msg *natsjs.Msg, v interface{}) error {
+	if err := json.Unmarshal(msg.Data, v); err != nil {
+		return fmt.Errorf("%w: %v", errMalformedMessage, err)
+	}
+	return nil
+}
+
+func (q *QueueConsumer) applyTrigger(msg *natsjs.Msg) error {
+	var req PriceTriggerRequest
+	if err := decodeMessage(msg, &req); err != nil {
+		return err
+	}
+	// NATS messages carry no trace, so each queued trigger starts its own
+	_, err := q.handlers.processPriceTrigger(context.Background(), req)
+	return err
+}
+
+func (q *QueueConsumer) applyNotification(msg *natsjs.Msg) error {
+	switch msg.Subject {
+	case contracts.ErrorSubject:
+		var req ErrorNotificationRequest
+		if err := decodeMessage(msg, &req); err != nil {
+			return err
+		}
+		_, err := q.handlers.processErrorNotification(req)
+		return err
+	case contracts.ExchangeStatusSubject:
+		var req contracts.ExchangeStatus
+		if err := decodeMessage(msg, &req); err != nil {
+			return err
+		}
+		q.handlers.gridService.SetExchangeStatus(req)
+		return nil
+	default:
+		var req FillNotificationRequest
+		if err := decodeMessage(msg, &req); err != nil {
+			return err
+		}
+		_, err := q.handlers.processFillNotification(req)
+		return err
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/natsjs"
+)
+
+// Event is reported to the notifier service
+type Event = contracts.Event
+
+const (
+	eventBufferSize  = 100
+	eventMaxAttempts = 3
+	eventRetryDelay  = 2 * time.Second
+)
+
+// EventPublisher hands events to the notifier in the background, so trading never
+// waits on (or fails because of) notification delivery. Events are dropped with a
+// log line when the notifier stays unreachable or the buffer is full.
+type EventPublisher struct {
+	send   func(event Event, data []byte) error
+	events chan Event
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newEventPublisher(send func(event Event, data []byte) error) *EventPublisher {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &EventPublisher{
+		send:   send,
+		events: make(chan Event, eventBufferSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	p.wg.Add(1)
+	go p.loop()
+	return p
+}
+
+// NewHTTPEventPublisher posts events to the notifier's /events endpoint
+func NewHTTPEventPublisher(notifierURL string) *EventPublisher {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+
+	return newEventPublisher(func(event Event, data []byte) error {
+		resp, err := httpClient.Post(notifierURL+"/events", "application/json", bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("notifier returned status %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// NewNATSEventPublisher publishes events to the GRID_EVENTS stream
+func NewNATSEventPublisher(js *natsjs.JetStream) (*EventPublisher, error) {
+	if err := js.EnsureStream(contracts.EventStream); err != nil {
+		return nil, err
+	}
+
+	return newEventPublisher(func(event Event, data []byte) error {
+		_, err := js.Publish(contracts.EventSubjectPrefix+event.Type, data)
+		return err
+	}), nil
+}
+
+// Publish queues an event without blocking
+func (p *EventPublisher) Publish(event Event) {
+	select {
+	case p.events <- event:
+	default:
+		log.Printf("WARNING: Event buffer full, dropping %s event %s", event.Type, event.ID)
+	}
+}
+
+// Stop delivers the events already queued and stops
+func (p *EventPublisher) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *EventPublisher) loop() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case event := <-p.events:
+			p.deliver(event)
+		case <-p.ctx.Done():
+			for {
+				select {
+				case event := <-p.events:
+					p.deliver(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *EventPublisher) deliver(event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("ERROR: Failed to marshal %s event %s: %v", event.Type, event.ID, err)
+		return
+	}
+
+	for attempt := 1; attempt <= eventMaxAttempts; attempt++ {
+		if err = p.send(event, data); err == nil {
+			return
+		}
+		if attempt < eventMaxAttempts {
+			time.Sleep(eventRetryDelay)
+		}
+	}
+	log.Printf("WARNING: Dropping %s event %s after %d attempts: %v", event.Type, event.ID, eventMaxAttempts, err)
+}
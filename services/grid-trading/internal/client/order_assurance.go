@@ -2,56 +2,87 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/tracing"
 	"github.com/shopspring/decimal"
 )
 
-type OrderSide string
+// Payloads shared with order-assurance
+type (
+	OrderSide        = contracts.OrderSide
+	OrderType        = contracts.OrderType
+	OrderRequest     = contracts.OrderRequest
+	OrderResponse    = contracts.OrderResponse
+	OrderStatus      = contracts.OrderStatus
+	OrderStatusQuery = contracts.OrderStatusQuery
+	BatchOrderStatus = contracts.BatchOrderStatus
+	SweepRequest     = contracts.SweepRequest
+	SweepResult      = contracts.SweepResult
+	BalancesResponse = contracts.BalancesResponse
+	SymbolAssets     = contracts.SymbolAssets
+	BookTicker       = contracts.BookTicker
+	OpenOrder        = contracts.OpenOrder
+	OpenOrders       = contracts.OpenOrdersResponse
+	FuturesPosition  = contracts.FuturesPosition
+	FundingFee       = contracts.FundingFee
+	MarginDebt       = contracts.MarginDebt
+	MarginInterest   = contracts.MarginInterest
+)
 
 const (
-	OrderSideBuy  OrderSide = "buy"
-	OrderSideSell OrderSide = "sell"
-)
+	OrderSideBuy  = contracts.SideBuy
+	OrderSideSell = contracts.SideSell
 
-type OrderRequest struct {
-	Symbol string          `json:"symbol"`
-	Price  decimal.Decimal `json:"price"`
-	Side   OrderSide       `json:"side"`
-	Amount decimal.Decimal `json:"amount"`
-}
+	OrderTypeLimit  = contracts.OrderTypeLimit
+	OrderTypeMarket = contracts.OrderTypeMarket
+
+	// Batch-only statuses for orders order-assurance could not resolve
+	OrderStatusNotFound = contracts.StatusNotFound
+	OrderStatusError    = contracts.StatusError
+
+	FuturesAccount = contracts.FuturesAccount
+	MarginAccount  = contracts.MarginAccount
+)
 
-type OrderResponse struct {
-	OrderID string `json:"order_id"`
-	Status  string `json:"status"`
+// OrderError is a classified rejection returned by order-assurance
+type OrderError struct {
+	Code          string
+	Message       string
+	RetryAfterSec int // Set when order-assurance asks callers to back off (e.g. circuit_open)
 }
 
-type OrderStatus struct {
-	OrderID      string           `json:"order_id"`
-	Status       string           `json:"status"`
-	FilledAmount *decimal.Decimal `json:"filled_amount,omitempty"`
-	FillPrice    *decimal.Decimal `json:"fill_price,omitempty"`
+func (e *OrderError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
 type OrderAssuranceClient struct {
 	baseURL    string
+	apiKey     string
 	httpClient *http.Client
 }
 
-func NewOrderAssuranceClient(baseURL string) *OrderAssuranceClient {
+func NewOrderAssuranceClient(baseURL, apiKey string) *OrderAssuranceClient {
 	return &OrderAssuranceClient{
 		baseURL: baseURL,
+		apiKey:  apiKey,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.Transport(nil),
 		},
 	}
 }
 
-func (c *OrderAssuranceClient) PlaceOrder(req OrderRequest) (*OrderResponse, error) {
+// PlaceOrder places an order through order-assurance, passing on the trace of ctx
+func (c *OrderAssuranceClient) PlaceOrder(ctx context.Context, req OrderRequest) (*OrderResponse, error) {
 	url := fmt.Sprintf("%s/order-assurance", c.baseURL)
 
 	jsonBody, err := json.Marshal(req)
@@ -59,12 +90,13 @@ func (c *OrderAssuranceClient) PlaceOrder(req OrderRequest) (*OrderResponse, err
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", c.apiKey)
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -82,7 +114,8 @@ func (c *OrderAssuranceClient) PlaceOrder(req OrderRequest) (*OrderResponse, err
 		var errorResp map[string]string
 		if err := json.Unmarshal(body, &errorResp); err == nil {
 			if msg, ok := errorResp["message"]; ok {
-				return nil, fmt.Errorf("%s", msg)
+				retryAfter, _ := strconv.Atoi(errorResp["retry_after_sec"])
+				return nil, &OrderError{Code: errorResp["error"], Message: msg, RetryAfterSec: retryAfter}
 			}
 		}
 		return nil, fmt.Errorf("unexpected status code: %d - %s", resp.StatusCode, string(body))
@@ -96,10 +129,20 @@ func (c *OrderAssuranceClient) PlaceOrder(req OrderRequest) (*OrderResponse, err
 	return &orderResp, nil
 }
 
-func (c *OrderAssuranceClient) GetOrderStatus(symbol, orderID string) (*OrderStatus, error) {
-	url := fmt.Sprintf("%s/order-status/%s?symbol=%s", c.baseURL, orderID, symbol)
+func (c *OrderAssuranceClient) GetOrderStatus(account, symbol, orderID string) (*OrderStatus, error) {
+	url := fmt.Sprintf("%s/order-status/%s/%s", c.baseURL, symbol, orderID)
+	if account != "" {
+		url += "?account=" + account
+	}
+
+	httpReq, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-API-Key", c.apiKey)
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -119,4 +162,280 @@ func (c *OrderAssuranceClient) GetOrderStatus(symbol, orderID string) (*OrderSta
 	}
 
 	return &status, nil
-}
\ No newline at end of file
+}
+
+// GetFreeBalances returns a snapshot of an account's free spot balances
+func (c *OrderAssuranceClient) GetFreeBalances(account string) (map[string]decimal.Decimal, error) {
+	url := c.baseURL + "/balances"
+	if account != "" {
+		url += "?account=" + account
+	}
+
+	httpReq, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var balances BalancesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&balances); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return balances.Balances, nil
+}
+
+// GetSymbolAssets names the base and quote asset of a symbol from the exchange's trading rules
+func (c *OrderAssuranceClient) GetSymbolAssets(account, symbol string) (*SymbolAssets, error) {
+	path := "/symbols/" + url.PathEscape(symbol)
+	if account != "" {
+		path += "?account=" + url.QueryEscape(account)
+	}
+
+	var assets SymbolAssets
+	if err := c.getJSON(path, &assets); err != nil {
+		return nil, err
+	}
+	return &assets, nil
+}
+
+// GetBookTicker reads the best bid and ask of a symbol on an account's market
+func (c *OrderAssuranceClient) GetBookTicker(account, symbol string) (*BookTicker, error) {
+	path := "/book/" + url.PathEscape(symbol)
+	if account != "" {
+		path += "?account=" + url.QueryEscape(account)
+	}
+
+	var book BookTicker
+	if err := c.getJSON(path, &book); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// GetOpenOrders lists every order open on the exchange for a symbol of an account
+func (c *OrderAssuranceClient) GetOpenOrders(account, symbol string) (*OpenOrders, error) {
+	path := "/open-orders/" + url.PathEscape(symbol)
+	if account != "" {
+		path += "?account=" + url.QueryEscape(account)
+	}
+
+	var orders OpenOrders
+	if err := c.getJSON(path, &orders); err != nil {
+		return nil, err
+	}
+	return &orders, nil
+}
+
+// CancelOrder cancels an open order of an account; the cancel itself arrives as a notification
+func (c *OrderAssuranceClient) CancelOrder(account, symbol, orderID string) error {
+	path := "/orders/" + url.PathEscape(symbol) + "/" + url.PathEscape(orderID)
+	if account != "" {
+		path += "?account=" + url.QueryEscape(account)
+	}
+
+	httpReq, err := http.NewRequest("DELETE", c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		var errorResp map[string]string
+		if err := json.Unmarshal(body, &errorResp); err == nil {
+			if msg, ok := errorResp["message"]; ok {
+				return &OrderError{Code: errorResp["error"], Message: msg}
+			}
+		}
+		return fmt.Errorf("unexpected status code: %d - %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// GetOrderStatuses resolves many orders in one request; results are in query order
+func (c *OrderAssuranceClient) GetOrderStatuses(queries []OrderStatusQuery) ([]BatchOrderStatus, error) {
+	jsonData, err := json.Marshal(contracts.BatchOrderStatusRequest{Orders: queries})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", c.baseURL+"/order-status/batch", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var batchResp contracts.BatchOrderStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(batchResp.Orders) != len(queries) {
+		return nil, fmt.Errorf("batch returned %d statuses for %d orders", len(batchResp.Orders), len(queries))
+	}
+
+	return batchResp.Orders, nil
+}
+
+// SweepProfit transfers profit to Earn or the whitelisted withdrawal address
+func (c *OrderAssuranceClient) SweepProfit(req SweepRequest) (*SweepResult, error) {
+	jsonBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", c.baseURL+"/profit-sweep", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp map[string]string
+		if err := json.Unmarshal(body, &errorResp); err == nil {
+			if msg, ok := errorResp["message"]; ok {
+				return nil, &OrderError{Code: errorResp["error"], Message: msg}
+			}
+		}
+		return nil, fmt.Errorf("unexpected status code: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var result SweepResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetPositions returns the open positions of a futures account
+func (c *OrderAssuranceClient) GetPositions(account string) ([]FuturesPosition, error) {
+	var positions contracts.PositionsResponse
+	if err := c.getJSON("/positions?account="+url.QueryEscape(account), &positions); err != nil {
+		return nil, err
+	}
+	return positions.Positions, nil
+}
+
+// GetFundingFees returns the funding payments of a futures account since the given time, oldest first
+func (c *OrderAssuranceClient) GetFundingFees(account string, since time.Time) ([]FundingFee, error) {
+	path := "/funding-fees?account=" + url.QueryEscape(account)
+	if !since.IsZero() {
+		path += "&since=" + url.QueryEscape(since.UTC().Format(time.RFC3339))
+	}
+
+	var fees contracts.FundingFeesResponse
+	if err := c.getJSON(path, &fees); err != nil {
+		return nil, err
+	}
+	return fees.FundingFees, nil
+}
+
+// GetMarginDebts returns the borrowed assets of a margin account
+func (c *OrderAssuranceClient) GetMarginDebts(account string) ([]MarginDebt, error) {
+	var debts contracts.MarginDebtsResponse
+	if err := c.getJSON("/margin/debts?account="+url.QueryEscape(account), &debts); err != nil {
+		return nil, err
+	}
+	return debts.Debts, nil
+}
+
+// GetMarginInterest returns the interest charged to a margin account since the given time, oldest first
+func (c *OrderAssuranceClient) GetMarginInterest(account string, since time.Time) ([]MarginInterest, error) {
+	path := "/margin/interest?account=" + url.QueryEscape(account)
+	if !since.IsZero() {
+		path += "&since=" + url.QueryEscape(since.UTC().Format(time.RFC3339))
+	}
+
+	var interest contracts.MarginInterestResponse
+	if err := c.getJSON(path, &interest); err != nil {
+		return nil, err
+	}
+	return interest.Interest, nil
+}
+
+// GetNotifications returns up to limit numbered fill and error notifications after afterSeq, oldest first
+func (c *OrderAssuranceClient) GetNotifications(afterSeq int64, limit int) (*contracts.NotificationReplay, error) {
+	var replay contracts.NotificationReplay
+	if err := c.getJSON(fmt.Sprintf("/notifications/replay?after_seq=%d&limit=%d", afterSeq, limit), &replay); err != nil {
+		return nil, err
+	}
+	return &replay, nil
+}
+
+// Ping checks that order-assurance is up and answering
+func (c *OrderAssuranceClient) Ping() error {
+	var health map[string]interface{}
+	return c.getJSON("/health", &health)
+}
+
+// getJSON sends an authenticated GET and decodes the JSON response into out
+func (c *OrderAssuranceClient) getJSON(path string, out interface{}) error {
+	httpReq, err := http.NewRequest("GET", c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d - %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/redis"
+)
+
+// PriceQuote is the latest price of a symbol as published by price-monitor
+type PriceQuote = contracts.PriceQuote
+
+// PriceCache reads the latest prices price-monitor publishes to Redis
+type PriceCache struct {
+	redis *redis.Client
+}
+
+func NewPriceCache(rc *redis.Client) *PriceCache {
+	return &PriceCache{redis: rc}
+}
+
+// GetLatestPrice returns the cached quote for symbol, or nil if there is none
+func (c *PriceCache) GetLatestPrice(symbol string) (*PriceQuote, error) {
+	data, ok, err := c.redis.Get(contracts.PriceKeyPrefix + symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached price for %s: %w", symbol, err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	var quote PriceQuote
+	if err := json.Unmarshal([]byte(data), &quote); err != nil {
+		return nil, fmt.Errorf("invalid cached price for %s: %w", symbol, err)
+	}
+	return &quote, nil
+}
@@ -1,17 +1,130 @@
 package config
 
 import (
+	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
 )
 
+// Token is a scoped bearer token for operators and dashboards (GRID_TRADING_TOKENS)
+type Token struct {
+	Name  string
+	Scope string // read | write | admin
+	Token string
+}
+
 type Config struct {
-	ServerPort        string
-	DBPath            string
-	OrderAssuranceURL string
-	SyncJobEnabled    bool
-	SyncJobCron       string
-	TradingFee        float64
+	ServerPort          string
+	DBPath              string
+	OrderAssuranceURL   string
+	OrderAssuranceKey   string
+	APIKey              string // Shared key callers send as X-API-Key (GRID_TRADING_API_KEY)
+	AuthDisabled        bool   // Explicit opt-out of API key auth (GRID_TRADING_AUTH_DISABLED)
+	Tokens              []Token
+	SyncJobEnabled      bool
+	SyncJobCron         string
+	SyncStuckAfterSec   int  // The sync job recovers PLACING_* levels older than this
+	SyncRecoveryBatch   int  // Stuck levels recovered per sync run, oldest first (0 = all)
+	SyncRecoverStuck    bool // The sync job retries or reverts stuck placements
+	SyncCheckActive     bool // The sync job checks the orders of active levels
+	PlacingWatchdogSec  int  // Report placements without an order ID after this long (0 = off)
+	NotifyReplaySec     int  // Pull missed fill/error notifications from order-assurance this often (0 = off)
+	MaxRecoveryAttempts int  // Failed sync job recoveries before a level is quarantined in ERROR (0 = never)
+	TradingFee          float64
+	FeeAwareSell        bool // Raise sell orders so each level nets its spread after TradingFee on both legs
+	OrderTTLSec         int  // order-assurance cancels grid orders still open after this long (0 = never)
+
+	SellQuantityPolicy string // nearest | round_down | keep_dust | top_up - rounding of sell amounts to the quantity step
+
+	MaxSpreadPct float64 // Check the order book before placing; skip placements while the bid/ask spread is wider (0 = off)
+
+	TriggerMinConfidence   float64 // Triggers less confident than this place no orders (0 = off)
+	TriggerAggregatedAbove float64 // Orders above this notional (USDT) need an aggregated price trigger (0 = off)
+
+	BuyOrderType string // limit (resting LIMIT buys) | quote_market (MARKET buys spending exactly the buy amount)
+
+	Transport string // Triggers and notifications via http (webhooks) or nats (JetStream)
+	NATSURL   string
+
+	NotifierURL string // Events go here with TRANSPORT=http (empty = not reported); nats publishes them to GRID_EVENTS
+
+	ReportTimezone *time.Location // Days, weeks and months of stats and reports; the report crons run in it too
+
+	SummaryEnabled bool   // Periodic summary event (needs NOTIFIER_URL or TRANSPORT=nats)
+	SummaryCron    string // Just before midnight by default - "today" stats reset at 00:00 REPORT_TIMEZONE
+
+	DailyReportEnabled  bool   // Scheduled end-of-day report, stored and sent to the notifier
+	DailyReportCron     string // Last minute of the day by default
+	WeeklyDigestEnabled bool   // Scheduled seven-day digest sent to the notifier
+	WeeklyDigestCron    string // Monday 00:00 by default, covering the week before
+
+	ReconciliationEnabled bool   // Scheduled check of levels against exchange orders and balances
+	ReconciliationCron    string // Every 30 minutes by default
+
+	DeadLevelGCEnabled    bool   // Scheduled archiving of dead levels
+	DeadLevelGCCron       string // Daily at 04:00 by default
+	DeadLevelDisabledDays int    // Disabled levels unchanged this long are dead (0 = never)
+	DeadLevelIdleDays     int    // Enabled READY levels without a state change this long are dead (0 = never)
+	DeadLevelCancelOrders bool   // The cleanup cancels the open buys of dead levels to archive them
+
+	BinanceAPIURL string // Public klines for grid simulations (empty = Binance production)
+
+	RedisURL           string  // Shared price cache written by price-monitor (empty = triggers only)
+	PriceStaleAfterSec int     // Prices older than this are left out of PnL and the drawdown guard
+	MaxDrawdownPct     float64 // Pause new buys above this unrealized drawdown (0 = off)
+	PegSymbol          string  // Quote stablecoin against another dollar stablecoin, e.g. USDCUSDT
+	DepegThresholdPct  float64 // Pause new buys while PegSymbol is further than this from 1 (0 = off)
+
+	ReportCurrency string  // Profit is also reported in this currency, e.g. EUR (empty = USDT only)
+	FXSource       string  // price (FXSymbol's latest price) | fixed (FXRate)
+	FXSymbol       string  // Pair of ReportCurrency and USDT, e.g. EURUSDT or USDTBRL
+	FXRate         float64 // Units of ReportCurrency per USDT when FXSource is fixed
+
+	ProfitSweepEnabled     bool
+	ProfitSweepCron        string
+	ProfitSweepThreshold   float64 // USDT
+	ProfitSweepDestination string  // earn | withdraw
+	ProfitSweepDryRun      bool
+
+	FuturesEnabled  bool   // Grids on the order-assurance "futures" account (needs FUTURES_ENABLED there too)
+	FundingSyncCron string // Funding is paid at 00:00, 08:00 and 16:00 UTC
+
+	MarginEnabled  bool   // Grids on the order-assurance "margin" account (needs MARGIN_ENABLED there too)
+	MarginSyncCron string // Interest accrues hourly
+
+	WebhooksEnabled         bool // Outbound webhook subscriptions (POST /webhooks)
+	WebhookRetryIntervalSec int  // Base retry interval of failed webhook deliveries
+
+	StartupSafeMode string // off | on_failure | always - boot serving read APIs only until POST /safe-mode/resume
+
+	TriggerQueueSize int // Price triggers processed at once, more are refused with 429 (0 = unlimited)
+
+	JobWorkers       int // Simulations, reconciliations, kline downloads and bulk changes run at once
+	JobQueueSize     int // Jobs waiting for a worker, more are refused with 503
+	JobRetentionDays int // Finished jobs are kept this long
+
+	ShardingEnabled bool   // Several instances share DB_PATH and lease disjoint symbol sets
+	InstanceID      string // This instance's name among them (default: hostname)
+	InstanceURL     string // Where price-monitor reaches this instance
+	ShardLeaseSec   int    // Leases and heartbeats lapse after this long without renewal
+
+	LevelCacheTTLSec int // Levels per symbol are served from memory this long at most (0 = no cache)
+
+	OrderStatusCacheTTLSec int // Triggers don't re-check an open order the price hasn't reached for this long (0 = check every trigger)
+
+	TrailingCheckSec int // Grids with trailing enabled are checked against the price this often (0 = off)
+
+	CalendarFile           string   // JSON file of trading calendar events imported on startup (empty = none)
+	CalendarSymbols        []string // Symbols an event without its own list pauses (empty = every symbol)
+	CalendarPauseBeforeMin int      // Buys pause this long before a calendar event starts
+	CalendarResumeAfterMin int      // and resume this long after it ends
+	CalendarCheckSec       int      // Calendar windows starting and ending are recorded this often (0 = off)
+
+	WatchOnly bool // Every grid records shadow orders instead of placing real ones
 }
 
 func LoadConfig() *Config {
@@ -30,6 +143,30 @@ func LoadConfig() *Config {
 		orderAssuranceURL = "http://localhost:9090"
 	}
 
+	orderAssuranceKey := os.Getenv("ORDER_ASSURANCE_API_KEY")
+
+	apiKey := os.Getenv("GRID_TRADING_API_KEY")
+	authDisabled, _ := strconv.ParseBool(os.Getenv("GRID_TRADING_AUTH_DISABLED"))
+	if apiKey == "" && !authDisabled {
+		log.Fatal("GRID_TRADING_API_KEY is required (set GRID_TRADING_AUTH_DISABLED=true to run unauthenticated)")
+	}
+
+	// name:scope:token,... - the token itself may contain colons
+	var tokens []Token
+	for _, entry := range strings.Split(os.Getenv("GRID_TRADING_TOKENS"), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" || !contracts.ValidScope(parts[1]) {
+			log.Fatal("GRID_TRADING_TOKENS must be a comma-separated list of name:scope:token with scope read, write or admin")
+		}
+		tokens = append(tokens, Token{Name: parts[0], Scope: parts[1], Token: parts[2]})
+	}
+	if len(tokens) > 0 && apiKey == "" {
+		log.Fatal("GRID_TRADING_TOKENS needs GRID_TRADING_API_KEY - with auth disabled every request is let through")
+	}
+
 	syncEnabled, _ := strconv.ParseBool(os.Getenv("SYNC_JOB_ENABLED"))
 
 	syncCron := os.Getenv("SYNC_JOB_CRON")
@@ -37,6 +174,38 @@ func LoadConfig() *Config {
 		syncCron = "0 * * * *"
 	}
 
+	syncStuckAfter := 300
+	if v := os.Getenv("SYNC_STUCK_AFTER_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			log.Fatal("SYNC_STUCK_AFTER_SEC must be a positive integer")
+		}
+		syncStuckAfter = parsed
+	}
+
+	syncRecoveryBatch := 0
+	if v := os.Getenv("SYNC_RECOVERY_BATCH"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("SYNC_RECOVERY_BATCH must be a non-negative integer")
+		}
+		syncRecoveryBatch = parsed
+	}
+
+	syncRecoverStuck := true
+	if v := os.Getenv("SYNC_RECOVER_STUCK"); v != "" {
+		if parsed, err := strconv.ParseBool(v); err == nil {
+			syncRecoverStuck = parsed
+		}
+	}
+
+	syncCheckActive := true
+	if v := os.Getenv("SYNC_CHECK_ACTIVE"); v != "" {
+		if parsed, err := strconv.ParseBool(v); err == nil {
+			syncCheckActive = parsed
+		}
+	}
+
 	tradingFeeStr := os.Getenv("TRADING_FEE")
 	tradingFee := 0.1
 	if tradingFeeStr != "" {
@@ -45,12 +214,522 @@ func LoadConfig() *Config {
 		}
 	}
 
+	sweepEnabled, _ := strconv.ParseBool(os.Getenv("PROFIT_SWEEP_ENABLED"))
+
+	sweepCron := os.Getenv("PROFIT_SWEEP_CRON")
+	if sweepCron == "" {
+		sweepCron = "0 0 * * *"
+	}
+
+	sweepThreshold := 50.0
+	if v := os.Getenv("PROFIT_SWEEP_THRESHOLD_USDT"); v != "" {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil && parsed > 0 {
+			sweepThreshold = parsed
+		}
+	}
+
+	sweepDestination := os.Getenv("PROFIT_SWEEP_DESTINATION")
+	if sweepDestination == "" {
+		sweepDestination = "earn"
+	}
+
+	// Dry run unless explicitly disabled - sweeps move real funds
+	sweepDryRun := true
+	if v := os.Getenv("PROFIT_SWEEP_DRY_RUN"); v != "" {
+		if parsed, err := strconv.ParseBool(v); err == nil {
+			sweepDryRun = parsed
+		}
+	}
+
+	transport := os.Getenv("TRANSPORT")
+	if transport == "" {
+		transport = "http"
+	}
+	if transport != "http" && transport != "nats" {
+		log.Fatal("TRANSPORT must be http or nats")
+	}
+
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://localhost:4222"
+	}
+
+	reportTimezone := time.UTC
+	if v := os.Getenv("REPORT_TIMEZONE"); v != "" {
+		loc, err := time.LoadLocation(v)
+		if err != nil {
+			log.Fatal("REPORT_TIMEZONE must be an IANA timezone name, e.g. Europe/Berlin")
+		}
+		reportTimezone = loc
+	}
+
+	summaryEnabled, _ := strconv.ParseBool(os.Getenv("SUMMARY_ENABLED"))
+
+	summaryCron := os.Getenv("SUMMARY_CRON")
+	if summaryCron == "" {
+		summaryCron = "55 23 * * *"
+	}
+
+	dailyReportEnabled, _ := strconv.ParseBool(os.Getenv("DAILY_REPORT_ENABLED"))
+
+	dailyReportCron := os.Getenv("DAILY_REPORT_CRON")
+	if dailyReportCron == "" {
+		dailyReportCron = "59 23 * * *"
+	}
+
+	reconciliationEnabled, _ := strconv.ParseBool(os.Getenv("RECONCILIATION_ENABLED"))
+
+	reconciliationCron := os.Getenv("RECONCILIATION_CRON")
+	if reconciliationCron == "" {
+		reconciliationCron = "*/30 * * * *"
+	}
+
+	deadLevelGCEnabled, _ := strconv.ParseBool(os.Getenv("DEAD_LEVEL_GC_ENABLED"))
+
+	deadLevelGCCron := os.Getenv("DEAD_LEVEL_GC_CRON")
+	if deadLevelGCCron == "" {
+		deadLevelGCCron = "0 4 * * *"
+	}
+
+	deadLevelDisabledDays := 30
+	if v := os.Getenv("DEAD_LEVEL_DISABLED_DAYS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("DEAD_LEVEL_DISABLED_DAYS must be a non-negative integer")
+		}
+		deadLevelDisabledDays = parsed
+	}
+
+	deadLevelIdleDays := 0
+	if v := os.Getenv("DEAD_LEVEL_IDLE_DAYS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("DEAD_LEVEL_IDLE_DAYS must be a non-negative integer")
+		}
+		deadLevelIdleDays = parsed
+	}
+
+	deadLevelCancelOrders, _ := strconv.ParseBool(os.Getenv("DEAD_LEVEL_CANCEL_ORDERS"))
+
+	weeklyDigestEnabled, _ := strconv.ParseBool(os.Getenv("WEEKLY_DIGEST_ENABLED"))
+
+	weeklyDigestCron := os.Getenv("WEEKLY_DIGEST_CRON")
+	if weeklyDigestCron == "" {
+		weeklyDigestCron = "0 0 * * 1"
+	}
+
+	placingWatchdog := 60
+	if v := os.Getenv("PLACING_WATCHDOG_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("PLACING_WATCHDOG_SEC must be a non-negative integer")
+		}
+		placingWatchdog = parsed
+	}
+
+	notificationReplay := 60
+	if v := os.Getenv("NOTIFICATION_REPLAY_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("NOTIFICATION_REPLAY_SEC must be a non-negative integer")
+		}
+		notificationReplay = parsed
+	}
+
+	maxRecoveryAttempts := 3
+	if v := os.Getenv("RECOVERY_MAX_ATTEMPTS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("RECOVERY_MAX_ATTEMPTS must be a non-negative integer")
+		}
+		maxRecoveryAttempts = parsed
+	}
+
+	feeAwareSell, _ := strconv.ParseBool(os.Getenv("FEE_AWARE_SELL"))
+
+	orderTTL := 0
+	if v := os.Getenv("ORDER_TTL_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("ORDER_TTL_SEC must be a non-negative integer")
+		}
+		orderTTL = parsed
+	}
+
+	sellQuantityPolicy := os.Getenv("SELL_QUANTITY_POLICY")
+	if sellQuantityPolicy == "" {
+		sellQuantityPolicy = "nearest"
+	}
+	switch sellQuantityPolicy {
+	case "nearest", "round_down", "keep_dust", "top_up":
+	default:
+		log.Fatal("SELL_QUANTITY_POLICY must be nearest, round_down, keep_dust or top_up")
+	}
+
+	maxSpread := 0.0
+	if v := os.Getenv("MAX_SPREAD_PCT"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 {
+			log.Fatal("MAX_SPREAD_PCT must be a non-negative number")
+		}
+		maxSpread = parsed
+	}
+
+	triggerMinConfidence := 0.0
+	if v := os.Getenv("TRIGGER_MIN_CONFIDENCE"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			log.Fatal("TRIGGER_MIN_CONFIDENCE must be a number between 0 and 1")
+		}
+		triggerMinConfidence = parsed
+	}
+
+	triggerAggregatedAbove := 0.0
+	if v := os.Getenv("TRIGGER_AGGREGATED_ABOVE"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 {
+			log.Fatal("TRIGGER_AGGREGATED_ABOVE must be a non-negative number")
+		}
+		triggerAggregatedAbove = parsed
+	}
+
+	buyOrderType := os.Getenv("BUY_ORDER_TYPE")
+	if buyOrderType == "" {
+		buyOrderType = "limit"
+	}
+	if buyOrderType != "limit" && buyOrderType != "quote_market" {
+		log.Fatal("BUY_ORDER_TYPE must be limit or quote_market")
+	}
+
+	priceStaleAfter := 60
+	if v := os.Getenv("PRICE_STALE_AFTER_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			log.Fatal("PRICE_STALE_AFTER_SEC must be a positive integer")
+		}
+		priceStaleAfter = parsed
+	}
+
+	maxDrawdown := 0.0
+	if v := os.Getenv("MAX_DRAWDOWN_PCT"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 {
+			log.Fatal("MAX_DRAWDOWN_PCT must be a non-negative number")
+		}
+		maxDrawdown = parsed
+	}
+
+	futuresEnabled, _ := strconv.ParseBool(os.Getenv("FUTURES_ENABLED"))
+
+	fundingSyncCron := os.Getenv("FUNDING_SYNC_CRON")
+	if fundingSyncCron == "" {
+		fundingSyncCron = "5 0,8,16 * * *"
+	}
+
+	marginEnabled, _ := strconv.ParseBool(os.Getenv("MARGIN_ENABLED"))
+
+	marginSyncCron := os.Getenv("MARGIN_SYNC_CRON")
+	if marginSyncCron == "" {
+		marginSyncCron = "10 * * * *"
+	}
+
+	webhooksEnabled, _ := strconv.ParseBool(os.Getenv("WEBHOOKS_ENABLED"))
+
+	webhookRetryInterval := 30
+	if v := os.Getenv("WEBHOOK_RETRY_INTERVAL_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			log.Fatal("WEBHOOK_RETRY_INTERVAL_SEC must be a positive integer")
+		}
+		webhookRetryInterval = parsed
+	}
+
+	pegSymbol := strings.ToUpper(os.Getenv("PEG_SYMBOL"))
+	if pegSymbol == "" {
+		pegSymbol = "USDCUSDT"
+	}
+
+	depegThreshold := 0.0
+	if v := os.Getenv("DEPEG_THRESHOLD_PCT"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 {
+			log.Fatal("DEPEG_THRESHOLD_PCT must be a non-negative number")
+		}
+		depegThreshold = parsed
+	}
+
+	reportCurrency := strings.ToUpper(os.Getenv("REPORT_CURRENCY"))
+	if reportCurrency == "USDT" {
+		reportCurrency = ""
+	}
+
+	fxSource := os.Getenv("FX_SOURCE")
+	if fxSource == "" {
+		fxSource = "price"
+	}
+	if fxSource != "price" && fxSource != "fixed" {
+		log.Fatal("FX_SOURCE must be price or fixed")
+	}
+
+	fxSymbol := strings.ToUpper(os.Getenv("FX_SYMBOL"))
+	if fxSymbol == "" && reportCurrency != "" {
+		fxSymbol = reportCurrency + "USDT"
+	}
+
+	fxRate := 0.0
+	if v := os.Getenv("FX_RATE"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed <= 0 {
+			log.Fatal("FX_RATE must be a positive number")
+		}
+		fxRate = parsed
+	}
+	if reportCurrency != "" && fxSource == "fixed" && fxRate == 0 {
+		log.Fatal("FX_RATE is required with FX_SOURCE=fixed")
+	}
+
+	startupSafeMode := os.Getenv("STARTUP_SAFE_MODE")
+	if startupSafeMode == "" {
+		startupSafeMode = "off"
+	}
+	if startupSafeMode != "off" && startupSafeMode != "on_failure" && startupSafeMode != "always" {
+		log.Fatal("STARTUP_SAFE_MODE must be off, on_failure or always")
+	}
+
+	triggerQueueSize := 8
+	if v := os.Getenv("TRIGGER_QUEUE_SIZE"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("TRIGGER_QUEUE_SIZE must be a non-negative integer")
+		}
+		triggerQueueSize = parsed
+	}
+
+	jobWorkers := 2
+	if v := os.Getenv("JOB_WORKERS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			log.Fatal("JOB_WORKERS must be a positive integer")
+		}
+		jobWorkers = parsed
+	}
+
+	jobQueueSize := 32
+	if v := os.Getenv("JOB_QUEUE_SIZE"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			log.Fatal("JOB_QUEUE_SIZE must be a positive integer")
+		}
+		jobQueueSize = parsed
+	}
+
+	jobRetention := 7
+	if v := os.Getenv("JOB_RETENTION_DAYS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			log.Fatal("JOB_RETENTION_DAYS must be a positive integer")
+		}
+		jobRetention = parsed
+	}
+
+	shardingEnabled := os.Getenv("SHARDING_ENABLED") == "true"
+	instanceID := os.Getenv("INSTANCE_ID")
+	instanceURL := os.Getenv("INSTANCE_URL")
+	shardLease := 30
+	if shardingEnabled {
+		if transport == "nats" {
+			log.Fatal("SHARDING_ENABLED needs TRANSPORT=http - price-monitor routes triggers to the owning instance over HTTP")
+		}
+		if instanceID == "" {
+			hostname, err := os.Hostname()
+			if err != nil {
+				log.Fatal("INSTANCE_ID is required when the hostname is unavailable")
+			}
+			instanceID = hostname
+		}
+		if instanceURL == "" {
+			log.Fatal("INSTANCE_URL is required with SHARDING_ENABLED")
+		}
+		if v := os.Getenv("SHARD_LEASE_SEC"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 3 {
+				log.Fatal("SHARD_LEASE_SEC must be an integer of at least 3")
+			}
+			shardLease = parsed
+		}
+	}
+
+	// Other instances write the shared database, which the cache wouldn't see
+	levelCacheTTL := 30
+	if shardingEnabled {
+		levelCacheTTL = 0
+	}
+	if v := os.Getenv("LEVEL_CACHE_TTL_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("LEVEL_CACHE_TTL_SEC must be a non-negative integer")
+		}
+		levelCacheTTL = parsed
+	}
+
+	orderStatusCacheTTL := 30
+	if v := os.Getenv("ORDER_STATUS_CACHE_TTL_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("ORDER_STATUS_CACHE_TTL_SEC must be a non-negative integer")
+		}
+		orderStatusCacheTTL = parsed
+	}
+
+	trailingCheck := 60
+	if v := os.Getenv("TRAILING_CHECK_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("TRAILING_CHECK_SEC must be a non-negative integer")
+		}
+		trailingCheck = parsed
+	}
+
+	watchOnly, _ := strconv.ParseBool(os.Getenv("WATCH_ONLY"))
+
+	var calendarSymbols []string
+	for _, symbol := range strings.Split(os.Getenv("CALENDAR_SYMBOLS"), ",") {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			calendarSymbols = append(calendarSymbols, symbol)
+		}
+	}
+	calendarPauseBefore := 30
+	if v := os.Getenv("CALENDAR_PAUSE_BEFORE_MIN"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("CALENDAR_PAUSE_BEFORE_MIN must be a non-negative integer")
+		}
+		calendarPauseBefore = parsed
+	}
+	calendarResumeAfter := 60
+	if v := os.Getenv("CALENDAR_RESUME_AFTER_MIN"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("CALENDAR_RESUME_AFTER_MIN must be a non-negative integer")
+		}
+		calendarResumeAfter = parsed
+	}
+	calendarCheck := 60
+	if v := os.Getenv("CALENDAR_CHECK_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("CALENDAR_CHECK_SEC must be a non-negative integer")
+		}
+		calendarCheck = parsed
+	}
+
 	return &Config{
-		ServerPort:        serverPort,
-		DBPath:            dbPath,
-		OrderAssuranceURL: orderAssuranceURL,
-		SyncJobEnabled:    syncEnabled,
-		SyncJobCron:       syncCron,
-		TradingFee:        tradingFee,
-	}
-}
\ No newline at end of file
+		ServerPort:          serverPort,
+		DBPath:              dbPath,
+		OrderAssuranceURL:   orderAssuranceURL,
+		OrderAssuranceKey:   orderAssuranceKey,
+		APIKey:              apiKey,
+		AuthDisabled:        authDisabled,
+		Tokens:              tokens,
+		SyncJobEnabled:      syncEnabled,
+		SyncJobCron:         syncCron,
+		SyncStuckAfterSec:   syncStuckAfter,
+		SyncRecoveryBatch:   syncRecoveryBatch,
+		SyncRecoverStuck:    syncRecoverStuck,
+		SyncCheckActive:     syncCheckActive,
+		PlacingWatchdogSec:  placingWatchdog,
+		NotifyReplaySec:     notificationReplay,
+		MaxRecoveryAttempts: maxRecoveryAttempts,
+		TradingFee:          tradingFee,
+		FeeAwareSell:        feeAwareSell,
+		OrderTTLSec:         orderTTL,
+
+		SellQuantityPolicy: sellQuantityPolicy,
+
+		MaxSpreadPct: maxSpread,
+
+		TriggerMinConfidence:   triggerMinConfidence,
+		TriggerAggregatedAbove: triggerAggregatedAbove,
+
+		BuyOrderType: buyOrderType,
+
+		Transport: transport,
+		NATSURL:   natsURL,
+
+		NotifierURL: os.Getenv("NOTIFIER_URL"),
+
+		ReportTimezone: reportTimezone,
+
+		SummaryEnabled: summaryEnabled,
+		SummaryCron:    summaryCron,
+
+		DailyReportEnabled:  dailyReportEnabled,
+		DailyReportCron:     dailyReportCron,
+		WeeklyDigestEnabled: weeklyDigestEnabled,
+		WeeklyDigestCron:    weeklyDigestCron,
+
+		ReconciliationEnabled: reconciliationEnabled,
+		ReconciliationCron:    reconciliationCron,
+
+		DeadLevelGCEnabled:    deadLevelGCEnabled,
+		DeadLevelGCCron:       deadLevelGCCron,
+		DeadLevelDisabledDays: deadLevelDisabledDays,
+		DeadLevelIdleDays:     deadLevelIdleDays,
+		DeadLevelCancelOrders: deadLevelCancelOrders,
+
+		BinanceAPIURL: os.Getenv("BINANCE_API_URL"),
+
+		RedisURL:           os.Getenv("REDIS_URL"),
+		PriceStaleAfterSec: priceStaleAfter,
+		MaxDrawdownPct:     maxDrawdown,
+		PegSymbol:          pegSymbol,
+		DepegThresholdPct:  depegThreshold,
+
+		ReportCurrency: reportCurrency,
+		FXSource:       fxSource,
+		FXSymbol:       fxSymbol,
+		FXRate:         fxRate,
+
+		ProfitSweepEnabled:     sweepEnabled,
+		ProfitSweepCron:        sweepCron,
+		ProfitSweepThreshold:   sweepThreshold,
+		ProfitSweepDestination: sweepDestination,
+		ProfitSweepDryRun:      sweepDryRun,
+
+		FuturesEnabled:  futuresEnabled,
+		FundingSyncCron: fundingSyncCron,
+
+		MarginEnabled:  marginEnabled,
+		MarginSyncCron: marginSyncCron,
+
+		WebhooksEnabled:         webhooksEnabled,
+		WebhookRetryIntervalSec: webhookRetryInterval,
+
+		StartupSafeMode: startupSafeMode,
+
+		TriggerQueueSize: triggerQueueSize,
+
+		JobWorkers:       jobWorkers,
+		JobQueueSize:     jobQueueSize,
+		JobRetentionDays: jobRetention,
+
+		ShardingEnabled: shardingEnabled,
+		InstanceID:      instanceID,
+		InstanceURL:     instanceURL,
+		ShardLeaseSec:   shardLease,
+
+		LevelCacheTTLSec: levelCacheTTL,
+
+		OrderStatusCacheTTLSec: orderStatusCacheTTL,
+
+		TrailingCheckSec: trailingCheck,
+
+		CalendarFile:           os.Getenv("CALENDAR_FILE"),
+		CalendarSymbols:        calendarSymbols,
+		CalendarPauseBeforeMin: calendarPauseBefore,
+		CalendarResumeAfterMin: calendarResumeAfter,
+		CalendarCheckSec:       calendarCheck,
+
+		WatchOnly: watchOnly,
+	}
+}
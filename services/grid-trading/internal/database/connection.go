@@ -35,10 +35,27 @@ func NewConnection(cfg Config) (*sql.DB, error) {
 	return db, nil
 }
 
+// SchemaVersion returns the number of migrations the database was last migrated with (0 = unknown)
+func SchemaVersion(db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// SetSchemaVersion records the number of migrations applied
+func SetSchemaVersion(db *sql.DB, version int) error {
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", version)); err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+	return nil
+}
+
 func RunMigrations(db *sql.DB, migrationSQL string) error {
 	_, err := db.Exec(migrationSQL)
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 	return nil
-}
\ No newline at end of file
+}
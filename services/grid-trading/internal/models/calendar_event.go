@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// CalendarEvent is a high-impact event (e.g. an FOMC decision) around which its symbols stop
+// buying, from CALENDAR_PAUSE_BEFORE_MIN before it starts to CALENDAR_RESUME_AFTER_MIN after it ends
+type CalendarEvent struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    time.Time  `json:"ends_at"`              // starts_at for an event at a point in time
+	Symbols   []string   `json:"symbols"`              // Empty = CALENDAR_SYMBOLS
+	Source    string     `json:"source"`               // api | file
+	PausedAt  *time.Time `json:"paused_at,omitempty"`  // When its window began pausing buys
+	ResumedAt *time.Time `json:"resumed_at,omitempty"` // When buys resumed after it
+	CreatedAt time.Time  `json:"created_at"`
+}
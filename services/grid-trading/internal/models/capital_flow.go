@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type CapitalFlowKind string
+
+const (
+	CapitalDeposit    CapitalFlowKind = "deposit"
+	CapitalWithdrawal CapitalFlowKind = "withdrawal"
+)
+
+// CapitalFlow is USDT put into or taken out of trading, tagged with where it came from or went
+type CapitalFlow struct {
+	ID         int             `json:"id"`
+	Kind       CapitalFlowKind `json:"kind"`
+	AmountUSDT decimal.Decimal `json:"amount_usdt"` // Positive either way
+	Source     string          `json:"source,omitempty"`
+	Note       string          `json:"note,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Signed is the flow's amount, negative for a withdrawal
+func (f *CapitalFlow) Signed() decimal.Decimal {
+	if f.Kind == CapitalWithdrawal {
+		return f.AmountUSDT.Neg()
+	}
+	return f.AmountUSDT
+}
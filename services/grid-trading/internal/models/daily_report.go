@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DailyReport is the end-of-day snapshot of a UTC day's trading, kept for GET /reports/daily/{date}
+type DailyReport struct {
+	Date string `json:"date"` // YYYY-MM-DD (UTC)
+	DayStats
+	Levels             map[string]int      `json:"levels"` // Enabled levels per state at report time
+	UnrealizedUSDT     decimal.Decimal     `json:"unrealized_usdt"`
+	ProfitAllTime      decimal.Decimal     `json:"profit_all_time_usdt"`
+	EquityUSDT         decimal.Decimal     `json:"equity_usdt"`           // Realized profit all time plus unrealized PnL
+	EquityChange       decimal.NullDecimal `json:"equity_change_usdt"`    // Since the previous report (NULL = first report)
+	NetDeposited       decimal.NullDecimal `json:"net_deposited_usdt"`    // Capital deposited less withdrawn (NULL = no flows recorded)
+	ROIPct             decimal.NullDecimal `json:"roi_pct"`               // Equity over net deposited capital
+	TimeWeightedROIPct decimal.NullDecimal `json:"time_weighted_roi_pct"` // Weighted by how long each flow was invested
+	Converted          *ConvertedTotals    `json:"converted,omitempty"`   // In REPORT_CURRENCY, at the rate of report time
+	CreatedAt          time.Time           `json:"created_at"`
+}
+
+// ConvertedTotals are a daily report's USDT totals in the reporting currency
+type ConvertedTotals struct {
+	Currency      string          `json:"currency"`
+	FXRate        decimal.Decimal `json:"fx_rate"` // Units of Currency per USDT
+	Profit        decimal.Decimal `json:"profit"`
+	Fees          decimal.Decimal `json:"fees"`
+	Unrealized    decimal.Decimal `json:"unrealized"`
+	ProfitAllTime decimal.Decimal `json:"profit_all_time"`
+	Equity        decimal.Decimal `json:"equity"`
+}
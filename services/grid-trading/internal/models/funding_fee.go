@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FundingFee is a funding payment of the futures account, negative when paid
+type FundingFee struct {
+	IncomeID string          `json:"income_id"`
+	Symbol   string          `json:"symbol"`
+	Asset    string          `json:"asset"`
+	Amount   decimal.Decimal `json:"amount"`
+	FundedAt time.Time       `json:"funded_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/shopspring/decimal"
+)
+
+// GridConfig holds the settings of a symbol's grid (defaults while none were saved)
+type GridConfig struct {
+	Symbol          string              `json:"symbol"`
+	TrailingEnabled bool                `json:"trailing_enabled"`     // Shift the band a level at a time after the price
+	TrailAfterMin   int                 `json:"trail_after_min"`      // Minutes outside the band before the first shift
+	WatchOnly       bool                `json:"watch_only"`           // Record shadow orders instead of placing real ones
+	OrderType       contracts.OrderType `json:"order_type"`           // limit, or market to take liquidity once a price reaches a level
+	StopLossPct     decimal.NullDecimal `json:"stop_loss_pct"`        // Sells are OCOs with a stop-loss this far below the buy price (NULL = off)
+	Tags            []string            `json:"tags"`                 // Operator tags, lowercase, to find the grid by (GET /levels/symbols?tag=)
+	Notes           string              `json:"notes,omitempty"`      // Free-form operator notes
+	UpdatedAt       *time.Time          `json:"updated_at,omitempty"` // Not saved yet when nil
+}
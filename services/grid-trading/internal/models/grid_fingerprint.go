@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// GridFingerprint records the parameters a grid was created with
+type GridFingerprint struct {
+	Fingerprint string          `json:"fingerprint"`
+	Symbol      string          `json:"symbol"`
+	Account     string          `json:"account,omitempty"`
+	GridType    string          `json:"grid_type"`
+	MinPrice    decimal.Decimal `json:"min_price"`
+	MaxPrice    decimal.Decimal `json:"max_price"`
+	GridStep    decimal.Decimal `json:"grid_step"` // Quote currency (arithmetic) or percent (geometric)
+	BuyAmount   decimal.Decimal `json:"buy_amount"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
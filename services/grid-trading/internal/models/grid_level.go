@@ -20,19 +20,35 @@ const (
 )
 
 type GridLevel struct {
-	ID             int                  `db:"id"`
-	Symbol         string               `db:"symbol"`
-	BuyPrice       decimal.Decimal      `db:"buy_price"`
-	SellPrice      decimal.Decimal      `db:"sell_price"`
-	BuyAmount      decimal.Decimal      `db:"buy_amount"`
-	FilledAmount   decimal.NullDecimal  `db:"filled_amount"`
-	State          GridState            `db:"state"`
-	BuyOrderID     sql.NullString       `db:"buy_order_id"`
-	SellOrderID    sql.NullString       `db:"sell_order_id"`
-	Enabled        bool                 `db:"enabled"`
-	StateChangedAt time.Time            `db:"state_changed_at"`
-	CreatedAt      time.Time            `db:"created_at"`
-	UpdatedAt      time.Time            `db:"updated_at"`
+	ID             int                 `db:"id"`
+	Symbol         string              `db:"symbol"`
+	BuyPrice       decimal.Decimal     `db:"buy_price"`
+	SellPrice      decimal.Decimal     `db:"sell_price"`
+	BuyAmount      decimal.Decimal     `db:"buy_amount"`
+	FilledAmount   decimal.NullDecimal `db:"filled_amount"`
+	State          GridState           `db:"state"`
+	BuyOrderID     sql.NullString      `db:"buy_order_id"`
+	SellOrderID    sql.NullString      `db:"sell_order_id"`
+	Enabled        bool                `db:"enabled"`
+	Account        string              `db:"account"`        // Binance sub-account orders are routed to (empty = master)
+	BuyMultiplier  decimal.NullDecimal `db:"buy_multiplier"` // Scales buy_amount per consecutive filled level above (NULL = flat)
+	MaxBuyAmount   decimal.NullDecimal `db:"max_buy_amount"` // Hard cap on a scaled buy
+	OrderAmount    decimal.NullDecimal `db:"order_amount"`   // USDT of the current cycle's buy (NULL = buy_amount)
+	StateChangedAt time.Time           `db:"state_changed_at"`
+	CreatedAt      time.Time           `db:"created_at"`
+	UpdatedAt      time.Time           `db:"updated_at"`
+
+	RecoveryAttempts int            `db:"recovery_attempts"` // Failed SyncOrders recoveries since the last placed order
+	ErrorMsg         sql.NullString `db:"error_msg"`         // Why the level is in ERROR
+
+	Tags  []string       `db:"tags"`  // Operator tags, lowercase (PUT /levels/{id}/notes)
+	Notes sql.NullString `db:"notes"` // Free-form operator notes
+}
+
+// LevelCounts counts the enabled levels of a symbol with an open order
+type LevelCounts struct {
+	Holding int // SELL_ACTIVE - waiting for their sell
+	Ready   int // BUY_ACTIVE - waiting for their buy
 }
 
 func (g *GridLevel) CanPlaceBuy(currentPrice decimal.Decimal) bool {
@@ -42,6 +58,14 @@ func (g *GridLevel) CanPlaceBuy(currentPrice decimal.Decimal) bool {
 		currentPrice.LessThan(g.SellPrice)
 }
 
+// CanPlaceQuoteBuy is CanPlaceBuy for market buys: they fill at once, so the level waits
+// for the price to come down to its buy price instead of resting an order above it
+func (g *GridLevel) CanPlaceQuoteBuy(currentPrice decimal.Decimal) bool {
+	return g.State == StateReady &&
+		g.Enabled &&
+		currentPrice.LessThanOrEqual(g.BuyPrice)
+}
+
 func (g *GridLevel) CanPlaceSell(currentPrice decimal.Decimal) bool {
 	return g.State == StateHolding &&
 		g.Enabled &&
@@ -49,3 +73,27 @@ func (g *GridLevel) CanPlaceSell(currentPrice decimal.Decimal) bool {
 		g.FilledAmount.Decimal.GreaterThan(decimal.Zero)
 }
 
+// CycleAmount is the USDT the current cycle's buy was placed for
+func (g *GridLevel) CycleAmount() decimal.Decimal {
+	if g.OrderAmount.Valid {
+		return g.OrderAmount.Decimal
+	}
+	return g.BuyAmount
+}
+
+// ScaledBuyAmount is buy_amount × buy_multiplier^streak, capped at max_buy_amount.
+// streak is the number of consecutive levels directly above that already hold coin.
+func (g *GridLevel) ScaledBuyAmount(streak int) decimal.Decimal {
+	if !g.BuyMultiplier.Valid || streak <= 0 {
+		return g.BuyAmount
+	}
+
+	amount := g.BuyAmount
+	for i := 0; i < streak; i++ {
+		amount = amount.Mul(g.BuyMultiplier.Decimal)
+		if g.MaxBuyAmount.Valid && amount.GreaterThanOrEqual(g.MaxBuyAmount.Decimal) {
+			return g.MaxBuyAmount.Decimal
+		}
+	}
+	return amount.Round(8)
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "QUEUED"
+	JobRunning   JobStatus = "RUNNING"
+	JobSucceeded JobStatus = "SUCCEEDED"
+	JobFailed    JobStatus = "FAILED"
+	JobCancelled JobStatus = "CANCELLED"
+)
+
+// Finished reports whether the job won't change anymore
+func (s JobStatus) Finished() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCancelled
+}
+
+// Job is a long-running operation run by the job workers
+type Job struct {
+	ID         int             `json:"id"`
+	Kind       string          `json:"kind"`
+	Params     json.RawMessage `json:"params,omitempty"`
+	Status     JobStatus       `json:"status"`
+	Done       int             `json:"done"`  // Steps done
+	Total      int             `json:"total"` // Steps in all (0 = not known yet)
+	Message    string          `json:"message,omitempty"`
+	Result     json.RawMessage `json:"result,omitempty"` // What the endpoint returns, partial for a cancelled job
+	Error      string          `json:"error,omitempty"`
+	Actor      string          `json:"actor,omitempty"`
+	InstanceID string          `json:"instance_id,omitempty"` // Instance running the job, with sharding
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
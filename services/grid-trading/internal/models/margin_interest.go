@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MarginInterest is an interest charge on the margin account's borrowed assets
+type MarginInterest struct {
+	IncomeID   string              `json:"income_id"`
+	Symbol     string              `json:"symbol,omitempty"`
+	Asset      string              `json:"asset"`
+	Amount     decimal.Decimal     `json:"amount"`
+	AmountUSDT decimal.NullDecimal `json:"amount_usdt"` // NULL when no price was known
+	ChargedAt  time.Time           `json:"charged_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PricePoint is one sample of the stored price history
+type PricePoint struct {
+	Symbol     string          `json:"symbol"`
+	Price      decimal.Decimal `json:"price"`
+	RecordedAt time.Time       `json:"recorded_at"`
+}
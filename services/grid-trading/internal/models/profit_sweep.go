@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type SweepStatus string
+
+const (
+	SweepDryRun    SweepStatus = "DRY_RUN"
+	SweepCompleted SweepStatus = "COMPLETED" // Only completed sweeps count against realized profit
+	SweepFailed    SweepStatus = "FAILED"
+)
+
+// ProfitSweep is one attempt to move realized profit off the spot wallet
+type ProfitSweep struct {
+	ID            int             `json:"id"`
+	Account       string          `json:"account,omitempty"`
+	Asset         string          `json:"asset"`
+	Amount        decimal.Decimal `json:"amount"`
+	Destination   string          `json:"destination"`
+	UnsweptProfit decimal.Decimal `json:"unswept_profit"`
+	Status        SweepStatus     `json:"status"`
+	Reference     string          `json:"reference,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Reconciliation report statuses
+const (
+	ReconciliationClean         = "clean"
+	ReconciliationDiscrepancies = "discrepancies"
+)
+
+// ReconciliationReport compares the levels with what the exchange holds, kept for GET /reconciliation/latest
+type ReconciliationReport struct {
+	ID            int             `json:"id"`
+	Status        string          `json:"status"` // clean | discrepancies
+	CheckedLevels int             `json:"checked_levels"`
+	CheckedOrders int             `json:"checked_orders"` // Open orders seen on the exchange
+	Mismatches    []OrderMismatch `json:"mismatches"`
+	OrphanOrders  []OrphanOrder   `json:"orphan_orders"`
+	Balances      []BalanceCheck  `json:"balances"`
+	Errors        []string        `json:"errors"` // Checks that could not run
+	Duration      string          `json:"duration"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// OrderMismatch is a level whose order the exchange no longer shows as open
+type OrderMismatch struct {
+	LevelID        int       `json:"level_id"`
+	Account        string    `json:"account,omitempty"`
+	Symbol         string    `json:"symbol"`
+	State          GridState `json:"state"`
+	OrderID        string    `json:"order_id"`
+	ExchangeStatus string    `json:"exchange_status"` // filled | cancelled | not_found
+}
+
+// OrphanOrder is an order open on the exchange that no level tracks
+type OrphanOrder struct {
+	Account  string          `json:"account,omitempty"`
+	Symbol   string          `json:"symbol"`
+	OrderID  string          `json:"order_id"`
+	Side     string          `json:"side"`
+	Price    decimal.Decimal `json:"price"`
+	Quantity decimal.Decimal `json:"quantity"`
+}
+
+// BalanceCheck compares the coins the levels hold with the account's balance of the coin
+type BalanceCheck struct {
+	Account   string          `json:"account,omitempty"`
+	Asset     string          `json:"asset"`
+	Expected  decimal.Decimal `json:"expected"`  // Filled amounts of HOLDING, PLACING_SELL and SELL_ACTIVE levels
+	Actual    decimal.Decimal `json:"actual"`    // Free balance plus the unfilled part of open sell orders
+	Shortfall decimal.Decimal `json:"shortfall"` // Expected minus actual, zero when covered
+	OK        bool            `json:"ok"`
+}
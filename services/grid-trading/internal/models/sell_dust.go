@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SellDust is the coin of one grid left unsold after sell quantities were rounded to the exchange's step
+type SellDust struct {
+	Account   string          `json:"account,omitempty"`
+	Symbol    string          `json:"symbol"`
+	DustCoin  decimal.Decimal `json:"dust_coin"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type TrancheStatus string
+
+const (
+	TranchePending TrancheStatus = "PENDING" // No sell open - placed with the level's next sell
+	TrancheOpen    TrancheStatus = "OPEN"
+	TrancheSold    TrancheStatus = "SOLD"
+)
+
+// SellTranche is one step of a level's take-profit ladder: share_pct of the bought amount sold
+// at profit_pct above the buy price. The cycle fields are cleared when the level's cycle completes.
+type SellTranche struct {
+	ID        int             `json:"id"`
+	LevelID   int             `json:"level_id"`
+	Position  int             `json:"position"`
+	SharePct  decimal.Decimal `json:"share_pct"`
+	ProfitPct decimal.Decimal `json:"profit_pct"`
+	Status    TrancheStatus   `json:"status"`
+
+	Amount       decimal.NullDecimal `json:"amount"` // Coin left for this tranche to sell (NULL = not planned yet)
+	OrderID      string              `json:"order_id,omitempty"`
+	Price        decimal.NullDecimal `json:"price"`
+	SoldAmount   decimal.NullDecimal `json:"sold_amount"`
+	ProceedsUSDT decimal.NullDecimal `json:"proceeds_usdt"`
+	Fee          Fee                 `json:"-"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// Done reports whether the tranche has nothing left to sell this cycle
+func (t *SellTranche) Done() bool {
+	return t.Status == TrancheSold || (t.Amount.Valid && !t.Amount.Decimal.IsPositive())
+}
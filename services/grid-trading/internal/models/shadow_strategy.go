@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ShadowStrategy is an alternative grid run in shadow on the same price triggers as a symbol's live grid
+type ShadowStrategy struct {
+	ID           int             `json:"id"`
+	Symbol       string          `json:"symbol"`
+	Name         string          `json:"name,omitempty"`
+	GridType     string          `json:"grid_type"` // arithmetic | geometric
+	MinPrice     decimal.Decimal `json:"min_price"`
+	MaxPrice     decimal.Decimal `json:"max_price"`
+	GridStep     decimal.Decimal `json:"grid_step"` // Percent for a geometric grid
+	BuyAmount    decimal.Decimal `json:"buy_amount"`
+	BuyOrderType string          `json:"buy_order_type"` // limit | quote_market
+	FeeAwareSell bool            `json:"fee_aware_sell"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ShadowTransaction is an order a watch-only grid or a shadow strategy would have placed, or its simulated fill
+type ShadowTransaction struct {
+	ID           int                 `json:"id"`
+	StrategyID   int                 `json:"strategy_id,omitempty"` // Shadow strategy, 0 for a watch-only grid
+	GridLevelID  int                 `json:"grid_level_id"`
+	Symbol       string              `json:"symbol"`
+	Account      string              `json:"account,omitempty"`
+	Side         TransactionSide     `json:"side"`
+	Status       TransactionStatus   `json:"status"` // PLACED | FILLED
+	TargetPrice  decimal.Decimal     `json:"target_price"`
+	TriggerPrice decimal.Decimal     `json:"trigger_price"`
+	AmountCoin   decimal.Decimal     `json:"amount_coin"`
+	AmountUSDT   decimal.Decimal     `json:"amount_usdt"`
+	ProfitUSDT   decimal.NullDecimal `json:"profit_usdt"`
+	CreatedAt    time.Time           `json:"created_at"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "github.com/grid-trading-bot/pkg/contracts"
+
+// Shard coordination records, served as-is on GET /shards
+type (
+	ShardInstance = contracts.ShardInstance
+	SymbolLease   = contracts.SymbolLease
+)
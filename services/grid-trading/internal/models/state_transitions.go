@@ -0,0 +1,68 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrIllegalTransition wraps level state changes the state machine doesn't allow
+var ErrIllegalTransition = errors.New("illegal level state transition")
+
+// stateTransitions lists the states a level may move to from each state, with the
+// repository method making the move (state machine in docs/SPEC.md)
+var stateTransitions = map[GridState][]GridState{
+	StateReady: {
+		StatePlacingBuy, // TryStartBuyOrder
+		StateHolding,    // SeedHolding
+		StateError,
+	},
+	StatePlacingBuy: {
+		StateBuyActive, // UpdateBuyOrderPlaced
+		StateReady,     // Placement failed
+		StateError,     // Quarantine
+	},
+	StateBuyActive: {
+		StateHolding, // ProcessBuyFill
+		StateReady,   // Buy cancelled or gone from the exchange
+		StateError,
+	},
+	StateHolding: {
+		StatePlacingSell, // TryStartSellOrder
+		StateError,
+	},
+	StatePlacingSell: {
+		StateSellActive, // UpdateSellOrderPlaced
+		StateHolding,    // Placement failed
+		StateError,      // Quarantine
+	},
+	StateSellActive: {
+		StateReady,   // ProcessSellFill
+		StateHolding, // Sell cancelled or gone from the exchange, KeepUnsold after a partial sell
+		StateError,
+	},
+	StateError: {
+		StateReady,   // Recover
+		StateHolding, // Recover
+	},
+}
+
+// CanTransition reports whether a level may move from one state to another
+func CanTransition(from, to GridState) bool {
+	for _, allowed := range stateTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns an ErrIllegalTransition error unless a level may move from one state to another
+func ValidateTransition(from, to GridState) error {
+	if _, known := stateTransitions[from]; !known {
+		return fmt.Errorf("%w: unknown state %q", ErrIllegalTransition, from)
+	}
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: %s → %s", ErrIllegalTransition, from, to)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StopLossOrder is the stop-loss leg of a level's OCO sell, placed with the limit leg the level
+// waits on as its sell order. Whichever leg fills completes the cycle; the exchange cancels the other.
+type StopLossOrder struct {
+	LevelID     int             `json:"level_id"`
+	SellOrderID string          `json:"sell_order_id"`
+	StopOrderID string          `json:"stop_order_id"`
+	StopPrice   decimal.Decimal `json:"stop_price"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
@@ -16,26 +16,104 @@ const (
 )
 
 const (
-	StatusPlaced TransactionStatus = "PLACED"
-	StatusFilled TransactionStatus = "FILLED"
-	StatusError  TransactionStatus = "ERROR"
+	StatusPlaced    TransactionStatus = "PLACED"
+	StatusFilled    TransactionStatus = "FILLED"
+	StatusCancelled TransactionStatus = "CANCELLED" // Order cancelled on the exchange, possibly part-filled
+	StatusError     TransactionStatus = "ERROR"
 )
 
 type Transaction struct {
-	ID            int                  `db:"id"`
-	GridLevelID   int                  `db:"grid_level_id"`
-	Symbol        string               `db:"symbol"`
-	Side          TransactionSide      `db:"side"`
-	Status        TransactionStatus    `db:"status"`
-	OrderID       sql.NullString       `db:"order_id"`
-	TargetPrice   decimal.Decimal      `db:"target_price"`
-	ExecutedPrice decimal.NullDecimal  `db:"executed_price"`
-	AmountCoin    decimal.NullDecimal  `db:"amount_coin"`
-	AmountUSDT    decimal.NullDecimal  `db:"amount_usdt"`
-	RelatedBuyID  sql.NullInt64        `db:"related_buy_id"`
-	ProfitUSDT    decimal.NullDecimal  `db:"profit_usdt"`
-	ProfitPct     decimal.NullDecimal  `db:"profit_pct"`
-	ErrorCode     sql.NullString       `db:"error_code"`
-	ErrorMsg      sql.NullString       `db:"error_msg"`
-	CreatedAt     time.Time            `db:"created_at"`
-}
\ No newline at end of file
+	ID              int                 `db:"id"`
+	GridLevelID     int                 `db:"grid_level_id"`
+	Symbol          string              `db:"symbol"`
+	Side            TransactionSide     `db:"side"`
+	Status          TransactionStatus   `db:"status"`
+	OrderID         sql.NullString      `db:"order_id"`
+	TargetPrice     decimal.Decimal     `db:"target_price"`
+	ExecutedPrice   decimal.NullDecimal `db:"executed_price"`
+	AmountCoin      decimal.NullDecimal `db:"amount_coin"`
+	AmountUSDT      decimal.NullDecimal `db:"amount_usdt"`
+	RelatedBuyID    sql.NullInt64       `db:"related_buy_id"`
+	ProfitUSDT      decimal.NullDecimal `db:"profit_usdt"`
+	ProfitPct       decimal.NullDecimal `db:"profit_pct"`
+	ErrorCode       sql.NullString      `db:"error_code"`
+	ErrorMsg        sql.NullString      `db:"error_msg"`
+	Commission      decimal.NullDecimal `db:"commission"`
+	CommissionAsset sql.NullString      `db:"commission_asset"`
+	FeeUSDT         decimal.NullDecimal `db:"fee_usdt"`
+	CreatedAt       time.Time           `db:"created_at"`
+}
+
+// LevelTransaction is a transaction with the account and prices of its grid level, as exported
+// to the analytics service
+type LevelTransaction struct {
+	Transaction
+	Account        string
+	LevelBuyPrice  decimal.Decimal
+	LevelSellPrice decimal.Decimal
+}
+
+// Fee is the commission Binance charged for a fill
+type Fee struct {
+	Commission decimal.Decimal
+	Asset      string              // Empty when order-assurance could not determine the fee
+	USDT       decimal.NullDecimal // Commission valued in USDT, when the asset can be priced
+}
+
+// FeeStats summarizes trading fees paid
+type FeeStats struct {
+	TodayUSDT     decimal.Decimal `json:"today_usdt"`
+	WeekUSDT      decimal.Decimal `json:"week_usdt"`
+	MonthUSDT     decimal.Decimal `json:"month_usdt"`
+	AllTimeUSDT   decimal.Decimal `json:"all_time_usdt"`
+	ByAsset       []AssetFees     `json:"by_asset"`
+	UnpricedFills int             `json:"unpriced_fills"` // Fills whose fee could not be valued in USDT (e.g. BNB)
+}
+
+// DayStats totals the fills and errors of one UTC day
+type DayStats struct {
+	Buys          int             `json:"buys"`
+	Sells         int             `json:"sells"`
+	Errors        int             `json:"errors"`
+	BoughtUSDT    decimal.Decimal `json:"bought_usdt"`
+	SoldUSDT      decimal.Decimal `json:"sold_usdt"`
+	ProfitUSDT    decimal.Decimal `json:"profit_usdt"`
+	FeesUSDT      decimal.Decimal `json:"fees_usdt"`
+	UnpricedFills int             `json:"unpriced_fills"` // Fills whose fee could not be valued in USDT
+}
+
+// ReportPeriods are the starts of the current reporting day, week (from Monday) and month
+// in the reporting timezone; the repositories compare them in UTC with the stored timestamps
+type ReportPeriods struct {
+	DayStart   time.Time
+	WeekStart  time.Time
+	MonthStart time.Time
+}
+
+// SymbolStats totals the fills, errors and realized profit of one symbol
+type SymbolStats struct {
+	Symbol          string
+	BuysToday       int
+	SellsToday      int
+	ErrorsToday     int
+	ProfitToday     decimal.Decimal
+	ProfitThisWeek  decimal.Decimal
+	ProfitThisMonth decimal.Decimal
+	ProfitAllTime   decimal.Decimal
+}
+
+// LevelCycles totals the completed buy/sell cycles (filled sells) of one grid level
+type LevelCycles struct {
+	GridLevelID int             `json:"grid_level_id"`
+	Symbol      string          `json:"symbol"`
+	Cycles      int             `json:"cycles"`
+	ProfitUSDT  decimal.Decimal `json:"profit_usdt"`
+}
+
+// AssetFees totals commission paid in one asset
+type AssetFees struct {
+	Asset      string          `json:"asset"`
+	Commission decimal.Decimal `json:"commission"`
+	FeeUSDT    decimal.Decimal `json:"fee_usdt"`
+	Fills      int             `json:"fills"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Webhook is an outbound subscription to grid-trading events
+type Webhook struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"` // Only returned when the webhook is created
+	Symbol     string    `json:"symbol,omitempty"` // Empty = all grids
+	EventTypes []string  `json:"event_types"`      // Empty = all event types
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Wants reports whether an event of eventType for symbol goes to this webhook.
+// Events without a symbol (pauses, summaries) concern every grid.
+func (w *Webhook) Wants(eventType, symbol string) bool {
+	if w.Symbol != "" && symbol != "" && w.Symbol != symbol {
+		return false
+	}
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDead      WebhookDeliveryStatus = "DEAD"
+)
+
+// WebhookDelivery is one event queued for one webhook
+type WebhookDelivery struct {
+	ID            int                   `json:"id"`
+	WebhookID     int                   `json:"webhook_id"`
+	URL           string                `json:"url"`
+	Secret        string                `json:"-"`
+	EventID       string                `json:"event_id"`
+	EventType     string                `json:"event_type"`
+	Payload       string                `json:"payload"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int                   `json:"attempts"`
+	LastError     string                `json:"last_error,omitempty"`
+	NextAttemptAt time.Time             `json:"next_attempt_at"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+type CalendarEventRepository struct {
+	db *sql.DB
+}
+
+func NewCalendarEventRepository(db *sql.DB) *CalendarEventRepository {
+	return &CalendarEventRepository{db: db}
+}
+
+const calendarEventColumns = `id, name, starts_at, ends_at, symbols, source, paused_at, resumed_at, created_at`
+
+// Upsert stores an event, updating the one with the same name and start if it was imported before.
+// A pause already recorded for it is kept.
+func (r *CalendarEventRepository) Upsert(event *models.CalendarEvent) error {
+	query := `
+		INSERT INTO calendar_events (name, starts_at, ends_at, symbols, source)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(name, starts_at) DO UPDATE SET
+			ends_at = excluded.ends_at,
+			symbols = excluded.symbols,
+			source = excluded.source
+	`
+	_, err := r.db.Exec(query, event.Name, event.StartsAt.UTC().Format("2006-01-02 15:04:05"), event.EndsAt.UTC().Format("2006-01-02 15:04:05"),
+		strings.Join(event.Symbols, ","), event.Source)
+	return err
+}
+
+// GetAll returns every event, earliest first
+func (r *CalendarEventRepository) GetAll() ([]*models.CalendarEvent, error) {
+	return r.query(`SELECT ` + calendarEventColumns + ` FROM calendar_events ORDER BY starts_at, id`)
+}
+
+// GetPending returns the events ending at or after endedAfter, and the older ones whose pause
+// hasn't been lifted yet, earliest first
+func (r *CalendarEventRepository) GetPending(endedAfter time.Time) ([]*models.CalendarEvent, error) {
+	return r.query(`
+		SELECT `+calendarEventColumns+` FROM calendar_events
+		WHERE ends_at >= $1 OR (paused_at IS NOT NULL AND resumed_at IS NULL)
+		ORDER BY starts_at, id
+	`, endedAfter.UTC().Format("2006-01-02 15:04:05"))
+}
+
+// MarkPaused records when an event's window began pausing buys. Returns false if that was
+// already recorded, e.g. by another instance.
+func (r *CalendarEventRepository) MarkPaused(id int, at time.Time) (bool, error) {
+	result, err := r.db.Exec(`UPDATE calendar_events SET paused_at = $1 WHERE id = $2 AND paused_at IS NULL`,
+		at.UTC().Format("2006-01-02 15:04:05"), id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// MarkResumed records when buys resumed after a paused event. Returns false if that was
+// already recorded.
+func (r *CalendarEventRepository) MarkResumed(id int, at time.Time) (bool, error) {
+	result, err := r.db.Exec(`UPDATE calendar_events SET resumed_at = $1 WHERE id = $2 AND paused_at IS NOT NULL AND resumed_at IS NULL`,
+		at.UTC().Format("2006-01-02 15:04:05"), id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// Delete removes an event. Returns false if there is no such event.
+func (r *CalendarEventRepository) Delete(id int) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM calendar_events WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *CalendarEventRepository) query(query string, args ...interface{}) ([]*models.CalendarEvent, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*models.CalendarEvent
+	for rows.Next() {
+		event := &models.CalendarEvent{Symbols: []string{}}
+		var startsAt, endsAt, symbols, createdAt string
+		var pausedAt, resumedAt sql.NullString
+		if err := rows.Scan(&event.ID, &event.Name, &startsAt, &endsAt, &symbols, &event.Source, &pausedAt, &resumedAt, &createdAt); err != nil {
+			return nil, err
+		}
+		event.StartsAt, _ = time.Parse("2006-01-02 15:04:05", startsAt)
+		event.EndsAt, _ = time.Parse("2006-01-02 15:04:05", endsAt)
+		if symbols != "" {
+			event.Symbols = strings.Split(symbols, ",")
+		}
+		if pausedAt.Valid {
+			t, _ := time.Parse("2006-01-02 15:04:05", pausedAt.String)
+			event.PausedAt = &t
+		}
+		if resumedAt.Valid {
+			t, _ := time.Parse("2006-01-02 15:04:05", resumedAt.String)
+			event.ResumedAt = &t
+		}
+		event.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+type CapitalFlowRepository struct {
+	db *sql.DB
+}
+
+func NewCapitalFlowRepository(db *sql.DB) *CapitalFlowRepository {
+	return &CapitalFlowRepository{db: db}
+}
+
+// Create records a deposit or withdrawal
+func (r *CapitalFlowRepository) Create(flow *models.CapitalFlow) error {
+	query := `
+		INSERT INTO capital_flows (kind, amount_usdt, source, note, occurred_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	result, err := r.db.Exec(query, flow.Kind, flow.AmountUSDT.String(), flow.Source, flow.Note, flow.OccurredAt.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Errorf("failed to record capital flow: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+	flow.ID = int(id)
+	return nil
+}
+
+// GetAll returns every deposit and withdrawal, oldest first
+func (r *CapitalFlowRepository) GetAll() ([]*models.CapitalFlow, error) {
+	rows, err := r.db.Query(`
+		SELECT id, kind, amount_usdt, source, note, occurred_at, created_at
+		FROM capital_flows
+		ORDER BY occurred_at, id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flows []*models.CapitalFlow
+	for rows.Next() {
+		flow := &models.CapitalFlow{}
+		var occurredAt, createdAt string
+		if err := rows.Scan(&flow.ID, &flow.Kind, &flow.AmountUSDT, &flow.Source, &flow.Note, &occurredAt, &createdAt); err != nil {
+			return nil, err
+		}
+		flow.OccurredAt, _ = time.Parse("2006-01-02 15:04:05", occurredAt)
+		flow.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		flows = append(flows, flow)
+	}
+	return flows, rows.Err()
+}
+
+// Delete removes a flow recorded by mistake. Returns false if there is no such flow.
+func (r *CapitalFlowRepository) Delete(id int) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM capital_flows WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+type DailyReportRepository struct {
+	db *sql.DB
+}
+
+func NewDailyReportRepository(db *sql.DB) *DailyReportRepository {
+	return &DailyReportRepository{db: db}
+}
+
+// Save stores the report of its date, replacing one composed earlier the same day
+func (r *DailyReportRepository) Save(report *models.DailyReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal daily report: %w", err)
+	}
+
+	query := `
+		INSERT INTO daily_reports (date, report, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT(date) DO UPDATE SET report = excluded.report, created_at = excluded.created_at
+	`
+	if _, err := r.db.Exec(query, report.Date, string(data), report.CreatedAt.UTC().Format("2006-01-02 15:04:05")); err != nil {
+		return fmt.Errorf("failed to save daily report: %w", err)
+	}
+	return nil
+}
+
+// GetByDate returns the report of a date, nil if there is none
+func (r *DailyReportRepository) GetByDate(date string) (*models.DailyReport, error) {
+	return r.getOne(`SELECT report FROM daily_reports WHERE date = $1`, date)
+}
+
+// GetLatestBefore returns the newest report of an earlier date, nil if there is none
+func (r *DailyReportRepository) GetLatestBefore(date string) (*models.DailyReport, error) {
+	return r.getOne(`SELECT report FROM daily_reports WHERE date < $1 ORDER BY date DESC LIMIT 1`, date)
+}
+
+func (r *DailyReportRepository) getOne(query string, args ...interface{}) (*models.DailyReport, error) {
+	var data string
+	err := r.db.QueryRow(query, args...).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.DailyReport{}
+	if err := json.Unmarshal([]byte(data), report); err != nil {
+		return nil, fmt.Errorf("failed to parse daily report: %w", err)
+	}
+	return report, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type FundingFeeRepository struct {
+	db *sql.DB
+}
+
+func NewFundingFeeRepository(db *sql.DB) *FundingFeeRepository {
+	return &FundingFeeRepository{db: db}
+}
+
+// Record stores a funding payment. Returns false if it was already recorded.
+func (r *FundingFeeRepository) Record(fee *models.FundingFee) (bool, error) {
+	query := `
+		INSERT OR IGNORE INTO funding_fees (income_id, symbol, asset, amount, funded_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	result, err := r.db.Exec(query, fee.IncomeID, fee.Symbol, fee.Asset, fee.Amount.String(), fee.FundedAt.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return false, fmt.Errorf("failed to record funding fee: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// GetLatestFundedAt returns when the newest recorded payment was funded (zero if none)
+func (r *FundingFeeRepository) GetLatestFundedAt() (time.Time, error) {
+	var fundedAt sql.NullString
+	if err := r.db.QueryRow(`SELECT MAX(funded_at) FROM funding_fees`).Scan(&fundedAt); err != nil {
+		return time.Time{}, err
+	}
+	if !fundedAt.Valid {
+		return time.Time{}, nil
+	}
+
+	latest, _ := time.Parse("2006-01-02 15:04:05", fundedAt.String)
+	return latest, nil
+}
+
+// GetTotalsBySymbol sums funding per symbol, since dayStart (today) and all time
+func (r *FundingFeeRepository) GetTotalsBySymbol(dayStart time.Time) (today, allTime map[string]decimal.Decimal, err error) {
+	query := `
+		SELECT symbol, amount, funded_at >= $1
+		FROM funding_fees
+	`
+
+	rows, err := r.db.Query(query, dayStart.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	// Summed in Go - SQLite SUM over TEXT would go through floats
+	today = make(map[string]decimal.Decimal)
+	allTime = make(map[string]decimal.Decimal)
+	for rows.Next() {
+		var symbol string
+		var amount decimal.Decimal
+		var isToday bool
+		if err := rows.Scan(&symbol, &amount, &isToday); err != nil {
+			return nil, nil, err
+		}
+		allTime[symbol] = allTime[symbol].Add(amount)
+		if isToday {
+			today[symbol] = today[symbol].Add(amount)
+		}
+	}
+
+	return today, allTime, rows.Err()
+}
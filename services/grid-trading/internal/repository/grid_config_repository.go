@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+type GridConfigRepository struct {
+	db *sql.DB
+}
+
+func NewGridConfigRepository(db *sql.DB) *GridConfigRepository {
+	return &GridConfigRepository{db: db}
+}
+
+// Get returns a symbol's config, nil if none was saved
+func (r *GridConfigRepository) Get(symbol string) (*models.GridConfig, error) {
+	row := r.db.QueryRow(`SELECT symbol, trailing_enabled, trail_after_min, watch_only, order_type, stop_loss_pct, tags, notes, updated_at FROM grid_configs WHERE symbol = $1`, symbol)
+	config, err := scanGridConfig(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return config, err
+}
+
+// GetTrailing returns the configs of the symbols with trailing enabled
+func (r *GridConfigRepository) GetTrailing() ([]*models.GridConfig, error) {
+	rows, err := r.db.Query(`
+		SELECT symbol, trailing_enabled, trail_after_min, watch_only, order_type, stop_loss_pct, tags, notes, updated_at FROM grid_configs
+		WHERE trailing_enabled = true ORDER BY symbol
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.GridConfig
+	for rows.Next() {
+		config, err := scanGridConfig(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, config)
+	}
+	return result, rows.Err()
+}
+
+// GetTagged returns the configs of the symbols tagged with tag
+func (r *GridConfigRepository) GetTagged(tag string) ([]*models.GridConfig, error) {
+	rows, err := r.db.Query(`
+		SELECT symbol, trailing_enabled, trail_after_min, watch_only, order_type, stop_loss_pct, tags, notes, updated_at FROM grid_configs
+		WHERE instr(',' || tags || ',', ',' || $1 || ',') > 0 ORDER BY symbol
+	`, tag)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.GridConfig
+	for rows.Next() {
+		config, err := scanGridConfig(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, config)
+	}
+	return result, rows.Err()
+}
+
+// Save creates or replaces a symbol's config
+func (r *GridConfigRepository) Save(config *models.GridConfig) error {
+	query := `
+		INSERT INTO grid_configs (symbol, trailing_enabled, trail_after_min, watch_only, order_type, stop_loss_pct, tags, notes, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, datetime('now'))
+		ON CONFLICT(symbol) DO UPDATE SET
+			trailing_enabled = excluded.trailing_enabled,
+			trail_after_min = excluded.trail_after_min,
+			watch_only = excluded.watch_only,
+			order_type = excluded.order_type,
+			stop_loss_pct = excluded.stop_loss_pct,
+			tags = excluded.tags,
+			notes = excluded.notes,
+			updated_at = excluded.updated_at
+	`
+	_, err := r.db.Exec(query, config.Symbol, config.TrailingEnabled, config.TrailAfterMin, config.WatchOnly, config.OrderType, config.StopLossPct,
+		joinTags(config.Tags), sql.NullString{String: config.Notes, Valid: config.Notes != ""})
+	return err
+}
+
+func scanGridConfig(scanner interface{ Scan(...interface{}) error }) (*models.GridConfig, error) {
+	config := &models.GridConfig{}
+	var tags, notes sql.NullString
+	var updatedAt string
+	if err := scanner.Scan(&config.Symbol, &config.TrailingEnabled, &config.TrailAfterMin, &config.WatchOnly, &config.OrderType, &config.StopLossPct,
+		&tags, &notes, &updatedAt); err != nil {
+		return nil, err
+	}
+	config.Tags = splitTags(tags)
+	config.Notes = notes.String
+	if t, err := time.Parse("2006-01-02 15:04:05", updatedAt); err == nil {
+		config.UpdatedAt = &t
+	}
+	return config, nil
+}
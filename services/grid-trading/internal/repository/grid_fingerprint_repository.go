@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+type GridFingerprintRepository struct {
+	db *sql.DB
+}
+
+func NewGridFingerprintRepository(db *sql.DB) *GridFingerprintRepository {
+	return &GridFingerprintRepository{db: db}
+}
+
+// Record stores a grid's fingerprint. Returns false if it was already recorded.
+func (r *GridFingerprintRepository) Record(grid *models.GridFingerprint) (bool, error) {
+	query := `
+		INSERT INTO grid_fingerprints (fingerprint, symbol, account, grid_type, min_price, max_price, grid_step, buy_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT(fingerprint) DO NOTHING
+	`
+	result, err := r.db.Exec(query, grid.Fingerprint, grid.Symbol, grid.Account, grid.GridType,
+		grid.MinPrice.String(), grid.MaxPrice.String(), grid.GridStep.String(), grid.BuyAmount.String())
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// GetBySymbol returns the grids recorded for a symbol, oldest first
+func (r *GridFingerprintRepository) GetBySymbol(symbol string) ([]*models.GridFingerprint, error) {
+	rows, err := r.db.Query(`
+		SELECT fingerprint, symbol, account, grid_type, min_price, max_price, grid_step, buy_amount, created_at
+		FROM grid_fingerprints WHERE symbol = $1 ORDER BY created_at, fingerprint
+	`, symbol)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.GridFingerprint
+	for rows.Next() {
+		grid := &models.GridFingerprint{}
+		var createdAt string
+		if err := rows.Scan(&grid.Fingerprint, &grid.Symbol, &grid.Account, &grid.GridType, &grid.MinPrice, &grid.MaxPrice,
+			&grid.GridStep, &grid.BuyAmount, &createdAt); err != nil {
+			return nil, err
+		}
+		grid.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		result = append(result, grid)
+	}
+	return result, rows.Err()
+}
+
+// DeleteBySymbol forgets the grids of a symbol once its levels are torn down
+func (r *GridFingerprintRepository) DeleteBySymbol(symbol string) error {
+	_, err := r.db.Exec(`DELETE FROM grid_fingerprints WHERE symbol = $1`, symbol)
+	return err
+}
@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/grid-trading-bot/services/grid-trading/internal/models"
@@ -20,16 +22,20 @@ func NewGridLevelRepository(db *sql.DB) *GridLevelRepository {
 
 func (r *GridLevelRepository) scanLevel(scanner interface{ Scan(...interface{}) error }) (*models.GridLevel, error) {
 	level := &models.GridLevel{}
+	var tags sql.NullString
 	var stateChangedAt, createdAt, updatedAt string
 	err := scanner.Scan(
 		&level.ID, &level.Symbol, &level.BuyPrice, &level.SellPrice,
 		&level.BuyAmount, &level.FilledAmount, &level.State,
-		&level.BuyOrderID, &level.SellOrderID, &level.Enabled,
+		&level.BuyOrderID, &level.SellOrderID, &level.Enabled, &level.Account,
+		&level.BuyMultiplier, &level.MaxBuyAmount, &level.OrderAmount,
+		&level.RecoveryAttempts, &level.ErrorMsg, &tags, &level.Notes,
 		&stateChangedAt, &createdAt, &updatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+	level.Tags = splitTags(tags)
 
 	// Parse timestamps from TEXT format
 	level.StateChangedAt, _ = time.Parse("2006-01-02 15:04:05", stateChangedAt)
@@ -42,7 +48,9 @@ func (r *GridLevelRepository) scanLevel(scanner interface{ Scan(...interface{})
 func (r *GridLevelRepository) GetBySymbol(symbol string) ([]*models.GridLevel, error) {
 	query := `
 		SELECT id, symbol, buy_price, sell_price, buy_amount, filled_amount,
-		       state, buy_order_id, sell_order_id, enabled,
+		       state, buy_order_id, sell_order_id, enabled, account,
+		       buy_multiplier, max_buy_amount, order_amount,
+		       recovery_attempts, error_msg, tags, notes,
 		       state_changed_at, created_at, updated_at
 		FROM grid_levels
 		WHERE symbol = $1
@@ -70,7 +78,9 @@ func (r *GridLevelRepository) GetBySymbol(symbol string) ([]*models.GridLevel, e
 func (r *GridLevelRepository) GetByID(id int) (*models.GridLevel, error) {
 	query := `
 		SELECT id, symbol, buy_price, sell_price, buy_amount, filled_amount,
-		       state, buy_order_id, sell_order_id, enabled,
+		       state, buy_order_id, sell_order_id, enabled, account,
+		       buy_multiplier, max_buy_amount, order_amount,
+		       recovery_attempts, error_msg, tags, notes,
 		       state_changed_at, created_at, updated_at
 		FROM grid_levels
 		WHERE id = $1
@@ -87,7 +97,9 @@ func (r *GridLevelRepository) GetByID(id int) (*models.GridLevel, error) {
 func (r *GridLevelRepository) GetByBuyOrderID(orderID string) (*models.GridLevel, error) {
 	query := `
 		SELECT id, symbol, buy_price, sell_price, buy_amount, filled_amount,
-		       state, buy_order_id, sell_order_id, enabled,
+		       state, buy_order_id, sell_order_id, enabled, account,
+		       buy_multiplier, max_buy_amount, order_amount,
+		       recovery_attempts, error_msg, tags, notes,
 		       state_changed_at, created_at, updated_at
 		FROM grid_levels
 		WHERE buy_order_id = $1
@@ -104,7 +116,9 @@ func (r *GridLevelRepository) GetByBuyOrderID(orderID string) (*models.GridLevel
 func (r *GridLevelRepository) GetBySellOrderID(orderID string) (*models.GridLevel, error) {
 	query := `
 		SELECT id, symbol, buy_price, sell_price, buy_amount, filled_amount,
-		       state, buy_order_id, sell_order_id, enabled,
+		       state, buy_order_id, sell_order_id, enabled, account,
+		       buy_multiplier, max_buy_amount, order_amount,
+		       recovery_attempts, error_msg, tags, notes,
 		       state_changed_at, created_at, updated_at
 		FROM grid_levels
 		WHERE sell_order_id = $1
@@ -122,7 +136,9 @@ func (r *GridLevelRepository) GetStuckInPlacingState(timeout time.Duration) ([]*
 	cutoff := time.Now().Add(-timeout)
 	query := `
 		SELECT id, symbol, buy_price, sell_price, buy_amount, filled_amount,
-		       state, buy_order_id, sell_order_id, enabled,
+		       state, buy_order_id, sell_order_id, enabled, account,
+		       buy_multiplier, max_buy_amount, order_amount,
+		       recovery_attempts, error_msg, tags, notes,
 		       state_changed_at, created_at, updated_at
 		FROM grid_levels
 		WHERE state IN ('PLACING_BUY', 'PLACING_SELL')
@@ -147,10 +163,43 @@ func (r *GridLevelRepository) GetStuckInPlacingState(timeout time.Duration) ([]*
 	return levels, rows.Err()
 }
 
+// GetPlacing returns every level in PLACING_BUY or PLACING_SELL, oldest state change first
+func (r *GridLevelRepository) GetPlacing() ([]*models.GridLevel, error) {
+	query := `
+		SELECT id, symbol, buy_price, sell_price, buy_amount, filled_amount,
+		       state, buy_order_id, sell_order_id, enabled, account,
+		       buy_multiplier, max_buy_amount, order_amount,
+		       recovery_attempts, error_msg, tags, notes,
+		       state_changed_at, created_at, updated_at
+		FROM grid_levels
+		WHERE state IN ('PLACING_BUY', 'PLACING_SELL')
+		ORDER BY state_changed_at, id
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels []*models.GridLevel
+	for rows.Next() {
+		level, err := r.scanLevel(rows)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, rows.Err()
+}
+
 func (r *GridLevelRepository) GetAllActive() ([]*models.GridLevel, error) {
 	query := `
 		SELECT id, symbol, buy_price, sell_price, buy_amount, filled_amount,
-		       state, buy_order_id, sell_order_id, enabled,
+		       state, buy_order_id, sell_order_id, enabled, account,
+		       buy_multiplier, max_buy_amount, order_amount,
+		       recovery_attempts, error_msg, tags, notes,
 		       state_changed_at, created_at, updated_at
 		FROM grid_levels
 		WHERE state IN ('BUY_ACTIVE', 'SELL_ACTIVE')
@@ -174,6 +223,9 @@ func (r *GridLevelRepository) GetAllActive() ([]*models.GridLevel, error) {
 	return levels, rows.Err()
 }
 
+// UpdateState moves a level to a state the state machine allows from its current one
+// (models.ValidateTransition); an illegal move returns an ErrIllegalTransition error and
+// changes nothing. Moving a level to the state it is in is a no-op.
 func (r *GridLevelRepository) UpdateState(id int, state models.GridState) error {
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -181,31 +233,80 @@ func (r *GridLevelRepository) UpdateState(id int, state models.GridState) error
 	}
 	defer tx.Rollback()
 
+	var current models.GridState
+	err = tx.QueryRow(`SELECT state FROM grid_levels WHERE id = $1`, id).Scan(&current)
+	if err == sql.ErrNoRows {
+		log.Printf("WARNING: Level %d state update to %s found no level", id, state)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current == state {
+		return nil
+	}
+	if err := models.ValidateTransition(current, state); err != nil {
+		log.Printf("ERROR: Level %d state update refused: %v", id, err)
+		return fmt.Errorf("level %d: %w", id, err)
+	}
+
 	query := `
 		UPDATE grid_levels
 		SET state = $1, state_changed_at = datetime('now'), updated_at = datetime('now')
-		WHERE id = $2
+		WHERE id = $2 AND state = $3
 	`
 
-	result, err := tx.Exec(query, state, id)
-	if err != nil {
+	if _, err := tx.Exec(query, state, id, current); err != nil {
 		log.Printf("ERROR: Failed to update state for level %d to %s: %v", id, state, err)
 		return err
 	}
 
-	rowsAffected, _ := result.RowsAffected()
 	if err := tx.Commit(); err != nil {
 		log.Printf("ERROR: Failed to commit state update for level %d: %v", id, err)
 		return err
 	}
 
-	if rowsAffected > 0 {
-		log.Printf("INFO: Level %d state → %s", id, state)
-	} else {
-		log.Printf("WARNING: Level %d state update to %s affected 0 rows", id, state)
+	log.Printf("INFO: Level %d state %s → %s", id, current, state)
+	return nil
+}
+
+// AddRecoveryFailure counts a failed SyncOrders recovery of a level, returning the new count.
+// Placing an order resets it.
+func (r *GridLevelRepository) AddRecoveryFailure(id int) (int, error) {
+	var attempts int
+	err := r.db.QueryRow(`
+		UPDATE grid_levels
+		SET recovery_attempts = recovery_attempts + 1, updated_at = datetime('now')
+		WHERE id = $1
+		RETURNING recovery_attempts
+	`, id).Scan(&attempts)
+	return attempts, err
+}
+
+// Quarantine moves a level from the given state to ERROR with the reason in error_msg.
+// Returns false if the level has left that state meanwhile.
+func (r *GridLevelRepository) Quarantine(id int, from models.GridState, reason string) (bool, error) {
+	if err := models.ValidateTransition(from, models.StateError); err != nil {
+		return false, fmt.Errorf("level %d: %w", id, err)
 	}
 
-	return nil
+	result, err := r.db.Exec(`
+		UPDATE grid_levels
+		SET state = $1, error_msg = $2, state_changed_at = datetime('now'), updated_at = datetime('now')
+		WHERE id = $3 AND state = $4
+	`, models.StateError, reason, id, from)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected > 0 {
+		log.Printf("INFO: Level %d state %s → %s (%s)", id, from, models.StateError, reason)
+	}
+	return rowsAffected > 0, nil
 }
 
 func (r *GridLevelRepository) UpdateBuyOrderPlaced(id int, orderID string) error {
@@ -217,7 +318,7 @@ func (r *GridLevelRepository) UpdateBuyOrderPlaced(id int, orderID string) error
 
 	query := `
 		UPDATE grid_levels
-		SET state = $1, buy_order_id = $2, state_changed_at = datetime('now'), updated_at = datetime('now')
+		SET state = $1, buy_order_id = $2, recovery_attempts = 0, state_changed_at = datetime('now'), updated_at = datetime('now')
 		WHERE id = $3 AND state = $4
 	`
 
@@ -255,7 +356,7 @@ func (r *GridLevelRepository) UpdateSellOrderPlaced(id int, orderID string) erro
 
 	query := `
 		UPDATE grid_levels
-		SET state = $1, sell_order_id = $2, state_changed_at = datetime('now'), updated_at = datetime('now')
+		SET state = $1, sell_order_id = $2, recovery_attempts = 0, state_changed_at = datetime('now'), updated_at = datetime('now')
 		WHERE id = $3 AND state = $4
 	`
 
@@ -332,7 +433,7 @@ func (r *GridLevelRepository) ProcessSellFill(id int) error {
 
 	query := `
 		UPDATE grid_levels
-		SET state = $1, filled_amount = NULL, sell_order_id = NULL,
+		SET state = $1, filled_amount = NULL, sell_order_id = NULL, order_amount = NULL,
 		    state_changed_at = datetime('now'), updated_at = datetime('now')
 		WHERE id = $2 AND state = $3
 	`
@@ -358,11 +459,325 @@ func (r *GridLevelRepository) ProcessSellFill(id int) error {
 		return err
 	}
 
-	log.Printf("INFO: Level %d → READY (cycle complete), cleared filled_amount and sell_order_id", id)
+	log.Printf("INFO: Level %d → READY (cycle complete), cleared filled_amount, sell_order_id and order_amount", id)
+	return nil
+}
+
+// KeepUnsold moves a SELL_ACTIVE level whose sell was cancelled after part of it filled
+// back to HOLDING with only the unsold remainder
+func (r *GridLevelRepository) KeepUnsold(id int, remaining decimal.Decimal) error {
+	query := `
+		UPDATE grid_levels
+		SET state = $1, filled_amount = $2,
+		    state_changed_at = datetime('now'), updated_at = datetime('now')
+		WHERE id = $3 AND state = $4
+	`
+
+	result, err := r.db.Exec(query, models.StateHolding, remaining, id, models.StateSellActive)
+	if err != nil {
+		log.Printf("ERROR: Failed to keep unsold amount for level %d: %v", id, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		log.Printf("WARNING: Level %d not in SELL_ACTIVE state, skipping partial sell", id)
+		return nil
+	}
+
+	log.Printf("INFO: Level %d → HOLDING, filled_amount=%s (unsold after partial sell)", id, remaining)
+	return nil
+}
+
+// ReplaceSellOrder keeps a SELL_ACTIVE level waiting on another of its open sell orders (a
+// take-profit ladder sells in several) with filled_amount down to the coin still unsold
+func (r *GridLevelRepository) ReplaceSellOrder(id int, orderID string, remaining decimal.Decimal) error {
+	query := `
+		UPDATE grid_levels
+		SET sell_order_id = $1, filled_amount = $2, updated_at = datetime('now')
+		WHERE id = $3 AND state = $4
+	`
+
+	result, err := r.db.Exec(query, orderID, remaining, id, models.StateSellActive)
+	if err != nil {
+		log.Printf("ERROR: Failed to replace sell order of level %d: %v", id, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		log.Printf("WARNING: Level %d not in SELL_ACTIVE state, keeping its sell order", id)
+	}
 	return nil
 }
 
-func (r *GridLevelRepository) TryStartBuyOrder(id int) (bool, error) {
+// SeedHolding moves a READY level straight to HOLDING with coins the user already owned,
+// costUSDT becoming the cycle's order_amount. Returns false if the level isn't READY.
+func (r *GridLevelRepository) SeedHolding(id int, filledAmount, costUSDT decimal.Decimal) (bool, error) {
+	query := `
+		UPDATE grid_levels
+		SET state = $1, filled_amount = $2, order_amount = $3,
+		    state_changed_at = datetime('now'), updated_at = datetime('now')
+		WHERE id = $4 AND state = $5 AND enabled = true
+	`
+
+	result, err := r.db.Exec(query, models.StateHolding, filledAmount, costUSDT, id, models.StateReady)
+	if err != nil {
+		log.Printf("ERROR: Failed to seed level %d: %v", id, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	log.Printf("INFO: Level %d → HOLDING (seeded), filled_amount=%s", id, filledAmount)
+	return true, nil
+}
+
+// SetEnabled enables or disables a level. A disabled level places no new orders; an order
+// already open is left to fill. Returns false if the level already was in that setting.
+func (r *GridLevelRepository) SetEnabled(id int, enabled bool) (bool, error) {
+	query := `
+		UPDATE grid_levels
+		SET enabled = $1, updated_at = datetime('now')
+		WHERE id = $2 AND enabled != $1
+	`
+
+	result, err := r.db.Exec(query, enabled, id)
+	if err != nil {
+		log.Printf("ERROR: Failed to set enabled=%v for level %d: %v", enabled, id, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected > 0 {
+		log.Printf("INFO: Level %d enabled → %v", id, enabled)
+	}
+	return rowsAffected > 0, nil
+}
+
+// UpdateSettings changes a level's prices, buy amount and enabled flag, provided it has no
+// order in flight (READY, HOLDING or ERROR). Returns false if the level is not in one of those states.
+func (r *GridLevelRepository) UpdateSettings(id int, buyPrice, sellPrice, buyAmount decimal.Decimal, enabled bool) (bool, error) {
+	query := `
+		UPDATE grid_levels
+		SET buy_price = $1, sell_price = $2, buy_amount = $3, enabled = $4, updated_at = datetime('now')
+		WHERE id = $5 AND state IN ('READY', 'HOLDING', 'ERROR')
+	`
+
+	result, err := r.db.Exec(query, buyPrice, sellPrice, buyAmount, enabled, id)
+	if err != nil {
+		log.Printf("ERROR: Failed to update settings of level %d: %v", id, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected > 0 {
+		log.Printf("INFO: Level %d updated - buy %s, sell %s, amount %s, enabled %v", id, buyPrice, sellPrice, buyAmount, enabled)
+	}
+	return rowsAffected > 0, nil
+}
+
+// UpdateNotes replaces a level's operator tags and notes, whatever its state.
+// Returns false if the level does not exist.
+func (r *GridLevelRepository) UpdateNotes(id int, tags []string, notes string) (bool, error) {
+	query := `
+		UPDATE grid_levels
+		SET tags = $1, notes = $2, updated_at = datetime('now')
+		WHERE id = $3
+	`
+
+	result, err := r.db.Exec(query, joinTags(tags), sql.NullString{String: notes, Valid: notes != ""}, id)
+	if err != nil {
+		log.Printf("ERROR: Failed to update notes of level %d: %v", id, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// SetSymbolEnabled enables or disables every level of a symbol in one statement.
+// Returns how many levels changed setting.
+func (r *GridLevelRepository) SetSymbolEnabled(symbol string, enabled bool) (int, error) {
+	query := `
+		UPDATE grid_levels
+		SET enabled = $1, updated_at = datetime('now')
+		WHERE symbol = $2 AND enabled != $1
+	`
+
+	result, err := r.db.Exec(query, enabled, symbol)
+	if err != nil {
+		log.Printf("ERROR: Failed to set enabled=%v for %s levels: %v", enabled, symbol, err)
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	log.Printf("INFO: %s levels enabled → %v (%d changed)", symbol, enabled, rowsAffected)
+	return int(rowsAffected), nil
+}
+
+// ArchiveSymbol moves every level of a symbol to grid_levels_archive, provided none has an
+// order placing or open (all READY, HOLDING or ERROR). Returns how many levels were archived -
+// 0 if one still had an order in flight.
+func (r *GridLevelRepository) ArchiveSymbol(symbol string) (int, error) {
+	archived, err := r.archive(`symbol = $1`, `state NOT IN ('READY', 'HOLDING', 'ERROR')`, symbol)
+	if err != nil {
+		log.Printf("ERROR: Failed to archive %s levels: %v", symbol, err)
+		return 0, err
+	}
+	if archived > 0 {
+		log.Printf("INFO: %s levels archived (%d)", symbol, archived)
+	}
+	return archived, nil
+}
+
+// ArchiveLevel moves one empty level - READY without coins - to grid_levels_archive.
+// Returns false if the level is gone or no longer empty.
+func (r *GridLevelRepository) ArchiveLevel(id int) (bool, error) {
+	archived, err := r.archive(`id = $1`, `state != 'READY' OR filled_amount IS NOT NULL`, id)
+	if err != nil {
+		log.Printf("ERROR: Failed to archive level %d: %v", id, err)
+		return false, err
+	}
+	if archived > 0 {
+		log.Printf("INFO: Level %d archived", id)
+	}
+	return archived > 0, nil
+}
+
+// archive moves the levels matching where to grid_levels_archive in one transaction, unless
+// one of them matches busy. The foreign key check is off for the move, as transactions keep
+// the level IDs.
+func (r *GridLevelRepository) archive(where, busy string, arg interface{}) (int, error) {
+	ctx := context.Background()
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	// Can't be changed inside a transaction
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return 0, fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var busyCount int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM grid_levels WHERE (`+where+`) AND (`+busy+`)`, arg).Scan(&busyCount)
+	if err != nil {
+		return 0, err
+	}
+	if busyCount > 0 {
+		return 0, nil
+	}
+
+	query := `
+		INSERT INTO grid_levels_archive (id, symbol, account, buy_price, sell_price, buy_amount, filled_amount, state, created_at)
+		SELECT id, symbol, account, buy_price, sell_price, buy_amount, filled_amount, state, created_at
+		FROM grid_levels
+		WHERE ` + where
+	if _, err := tx.Exec(query, arg); err != nil {
+		return 0, err
+	}
+
+	// The foreign key is off, so the ladders don't go with their levels by themselves
+	if _, err := tx.Exec(`DELETE FROM sell_tranches WHERE grid_level_id IN (SELECT id FROM grid_levels WHERE `+where+`)`, arg); err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(`DELETE FROM grid_levels WHERE `+where, arg)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(rowsAffected), nil
+}
+
+// Recover moves a level out of ERROR to READY with its failed cycle cleared, or to HOLDING
+// keeping the coins it bought. The recovery count and error_msg are reset.
+// Returns false if the level isn't in ERROR.
+func (r *GridLevelRepository) Recover(id int, to models.GridState) (bool, error) {
+	query := `
+		UPDATE grid_levels
+		SET state = $1, sell_order_id = NULL, error_msg = NULL, recovery_attempts = 0,
+		    state_changed_at = datetime('now'), updated_at = datetime('now')
+		WHERE id = $2 AND state = $3
+	`
+	if to == models.StateReady {
+		query = `
+			UPDATE grid_levels
+			SET state = $1, filled_amount = NULL, buy_order_id = NULL, sell_order_id = NULL, order_amount = NULL,
+			    error_msg = NULL, recovery_attempts = 0,
+			    state_changed_at = datetime('now'), updated_at = datetime('now')
+			WHERE id = $2 AND state = $3
+		`
+	} else if err := models.ValidateTransition(models.StateError, to); err != nil {
+		return false, fmt.Errorf("level %d can't be recovered: %w", id, err)
+	}
+
+	result, err := r.db.Exec(query, to, id, models.StateError)
+	if err != nil {
+		log.Printf("ERROR: Failed to recover level %d: %v", id, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected > 0 {
+		log.Printf("INFO: Level %d state %s → %s (recovered)", id, models.StateError, to)
+	}
+	return rowsAffected > 0, nil
+}
+
+// TryStartBuyOrder moves a READY level to PLACING_BUY and stores the USDT amount the
+// buy is placed for, so retries and cost tracking use the same (possibly scaled) amount
+func (r *GridLevelRepository) TryStartBuyOrder(id int, amount decimal.Decimal) (bool, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return false, err
@@ -371,11 +786,11 @@ func (r *GridLevelRepository) TryStartBuyOrder(id int) (bool, error) {
 
 	query := `
 		UPDATE grid_levels
-		SET state = $1, state_changed_at = datetime('now'), updated_at = datetime('now')
-		WHERE id = $2 AND state = $3 AND enabled = true
+		SET state = $1, order_amount = $2, state_changed_at = datetime('now'), updated_at = datetime('now')
+		WHERE id = $3 AND state = $4 AND enabled = true
 	`
 
-	result, err := tx.Exec(query, models.StatePlacingBuy, id, models.StateReady)
+	result, err := tx.Exec(query, models.StatePlacingBuy, amount, id, models.StateReady)
 	if err != nil {
 		log.Printf("ERROR: Failed to try start buy order for level %d: %v", id, err)
 		return false, err
@@ -439,8 +854,9 @@ func (r *GridLevelRepository) TryStartSellOrder(id int) (bool, error) {
 func (r *GridLevelRepository) Create(level *models.GridLevel) error {
 	query := `
 		INSERT INTO grid_levels (
-			symbol, buy_price, sell_price, buy_amount, state, enabled
-		) VALUES ($1, $2, $3, $4, $5, $6)
+			symbol, buy_price, sell_price, buy_amount, state, enabled, account,
+			buy_multiplier, max_buy_amount
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		ON CONFLICT (symbol, buy_price, sell_price) DO NOTHING
 		RETURNING id
 	`
@@ -453,6 +869,9 @@ func (r *GridLevelRepository) Create(level *models.GridLevel) error {
 		level.BuyAmount,
 		models.StateReady,
 		true,
+		level.Account,
+		level.BuyMultiplier,
+		level.MaxBuyAmount,
 	).Scan(&level.ID)
 
 	if err == sql.ErrNoRows {
@@ -466,7 +885,9 @@ func (r *GridLevelRepository) Create(level *models.GridLevel) error {
 func (r *GridLevelRepository) GetAll() ([]*models.GridLevel, error) {
 	query := `
 		SELECT id, symbol, buy_price, sell_price, buy_amount, filled_amount,
-		       state, buy_order_id, sell_order_id, enabled,
+		       state, buy_order_id, sell_order_id, enabled, account,
+		       buy_multiplier, max_buy_amount, order_amount,
+		       recovery_attempts, error_msg, tags, notes,
 		       state_changed_at, created_at, updated_at
 		FROM grid_levels
 		ORDER BY symbol, buy_price ASC
@@ -516,16 +937,68 @@ func (r *GridLevelRepository) GetDistinctSymbols() ([]string, error) {
 	return symbols, rows.Err()
 }
 
-func (r *GridLevelRepository) GetLevelCounts() (holding, ready int, err error) {
+// GetStateCounts counts enabled levels per state
+func (r *GridLevelRepository) GetStateCounts() (map[string]int, error) {
+	rows, err := r.db.Query(`SELECT state, COUNT(*) FROM grid_levels WHERE enabled = 1 GROUP BY state`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var state string
+		var count int
+		if err := rows.Scan(&state, &count); err != nil {
+			return nil, err
+		}
+		counts[state] = count
+	}
+	return counts, rows.Err()
+}
+
+// GetLevelCountsBySymbol counts enabled levels with an open order per symbol
+func (r *GridLevelRepository) GetLevelCountsBySymbol() (map[string]models.LevelCounts, error) {
 	query := `
 		SELECT
+			symbol,
 			COUNT(CASE WHEN state = 'SELL_ACTIVE' THEN 1 END) as holding,
 			COUNT(CASE WHEN state = 'BUY_ACTIVE' THEN 1 END) as ready
 		FROM grid_levels
 		WHERE enabled = 1
+		GROUP BY symbol
 	`
 
-	err = r.db.QueryRow(query).Scan(&holding, &ready)
-	return holding, ready, err
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]models.LevelCounts)
+	for rows.Next() {
+		var symbol string
+		var c models.LevelCounts
+		if err := rows.Scan(&symbol, &c.Holding, &c.Ready); err != nil {
+			return nil, err
+		}
+		counts[symbol] = c
+	}
+	return counts, rows.Err()
+}
+
+// joinTags stores operator tags comma-separated, NULL when there are none
+func joinTags(tags []string) sql.NullString {
+	if len(tags) == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: strings.Join(tags, ","), Valid: true}
 }
 
+// splitTags reads back tags stored by joinTags
+func splitTags(tags sql.NullString) []string {
+	if !tags.Valid || tags.String == "" {
+		return []string{}
+	}
+	return strings.Split(tags.String, ",")
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+type JobRepository struct {
+	db *sql.DB
+}
+
+func NewJobRepository(db *sql.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+const jobColumns = `id, kind, params, status, done, total, message, result, error, actor, instance_id, created_at, started_at, finished_at`
+
+// Create stores a queued job, setting its ID and creation time
+func (r *JobRepository) Create(job *models.Job) error {
+	query := `
+		INSERT INTO jobs (kind, params, status, actor, instance_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	var createdAtStr string
+	err := r.db.QueryRow(query, job.Kind, optionalString(string(job.Params)), job.Status, optionalString(job.Actor), job.InstanceID).Scan(&job.ID, &createdAtStr)
+	if err != nil {
+		return err
+	}
+
+	job.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	return nil
+}
+
+// Start moves a queued job to RUNNING
+func (r *JobRepository) Start(id int) error {
+	_, err := r.db.Exec(`UPDATE jobs SET status = 'RUNNING', started_at = datetime('now') WHERE id = $1 AND status = 'QUEUED'`, id)
+	return err
+}
+
+// UpdateProgress records how far a running job got
+func (r *JobRepository) UpdateProgress(id, done, total int, message string) error {
+	_, err := r.db.Exec(`UPDATE jobs SET done = $1, total = $2, message = $3 WHERE id = $4`, done, total, optionalString(message), id)
+	return err
+}
+
+// Finish records a job's outcome
+func (r *JobRepository) Finish(id int, status models.JobStatus, result []byte, errMsg string) error {
+	_, err := r.db.Exec(`
+		UPDATE jobs SET status = $1, result = $2, error = $3, finished_at = datetime('now')
+		WHERE id = $4
+	`, status, optionalString(string(result)), optionalString(errMsg), id)
+	return err
+}
+
+// Get returns a job, nil if there is none with the ID
+func (r *JobRepository) Get(id int) (*models.Job, error) {
+	job, err := scanJob(r.db.QueryRow(`SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// List returns the newest jobs, optionally of one kind and status
+func (r *JobRepository) List(kind string, status models.JobStatus, limit int) ([]*models.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE ($1 = '' OR kind = $1) AND ($2 = '' OR status = $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(query, kind, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*models.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// FailUnfinished fails the jobs a stopped instance left queued or running. Other instances
+// sharing the database keep theirs.
+func (r *JobRepository) FailUnfinished(instanceID, reason string) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE jobs SET status = 'FAILED', error = $1, finished_at = datetime('now')
+		WHERE status IN ('QUEUED', 'RUNNING') AND instance_id = $2
+	`, reason, instanceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteFinishedBefore prunes jobs that finished before the given time
+func (r *JobRepository) DeleteFinishedBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM jobs WHERE finished_at IS NOT NULL AND finished_at < $1`, before.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// optionalString stores an empty string as NULL
+func optionalString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func scanJob(scanner interface{ Scan(...interface{}) error }) (*models.Job, error) {
+	job := &models.Job{}
+	var params, message, result, errMsg, actor, startedAt, finishedAt sql.NullString
+	var createdAtStr string
+	err := scanner.Scan(&job.ID, &job.Kind, &params, &job.Status, &job.Done, &job.Total, &message, &result, &errMsg, &actor,
+		&job.InstanceID, &createdAtStr, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Valid {
+		job.Params = []byte(params.String)
+	}
+	if result.Valid {
+		job.Result = []byte(result.String)
+	}
+	job.Message, job.Error, job.Actor = message.String, errMsg.String, actor.String
+	job.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	if startedAt.Valid {
+		t, _ := time.Parse("2006-01-02 15:04:05", startedAt.String)
+		job.StartedAt = &t
+	}
+	if finishedAt.Valid {
+		t, _ := time.Parse("2006-01-02 15:04:05", finishedAt.String)
+		job.FinishedAt = &t
+	}
+	return job, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type MarginInterestRepository struct {
+	db *sql.DB
+}
+
+func NewMarginInterestRepository(db *sql.DB) *MarginInterestRepository {
+	return &MarginInterestRepository{db: db}
+}
+
+// Record stores an interest charge. Returns false if it was already recorded.
+func (r *MarginInterestRepository) Record(charge *models.MarginInterest) (bool, error) {
+	query := `
+		INSERT OR IGNORE INTO margin_interest (income_id, symbol, asset, amount, amount_usdt, charged_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	var amountUSDT sql.NullString
+	if charge.AmountUSDT.Valid {
+		amountUSDT = sql.NullString{String: charge.AmountUSDT.Decimal.String(), Valid: true}
+	}
+
+	result, err := r.db.Exec(query, charge.IncomeID, charge.Symbol, charge.Asset, charge.Amount.String(), amountUSDT,
+		charge.ChargedAt.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return false, fmt.Errorf("failed to record margin interest: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// GetLatestChargedAt returns when the newest recorded charge accrued (zero if none)
+func (r *MarginInterestRepository) GetLatestChargedAt() (time.Time, error) {
+	var chargedAt sql.NullString
+	if err := r.db.QueryRow(`SELECT MAX(charged_at) FROM margin_interest`).Scan(&chargedAt); err != nil {
+		return time.Time{}, err
+	}
+	if !chargedAt.Valid {
+		return time.Time{}, nil
+	}
+
+	latest, _ := time.Parse("2006-01-02 15:04:05", chargedAt.String)
+	return latest, nil
+}
+
+// GetTotalsUSDT sums interest valued in USDT, since dayStart (today) and all time, and counts charges without a value
+func (r *MarginInterestRepository) GetTotalsUSDT(dayStart time.Time) (today, allTime decimal.Decimal, unvalued int, err error) {
+	query := `
+		SELECT amount_usdt, charged_at >= $1
+		FROM margin_interest
+	`
+
+	rows, err := r.db.Query(query, dayStart.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return decimal.Zero, decimal.Zero, 0, err
+	}
+	defer rows.Close()
+
+	// Summed in Go - SQLite SUM over TEXT would go through floats
+	for rows.Next() {
+		var amountUSDT decimal.NullDecimal
+		var isToday bool
+		if err := rows.Scan(&amountUSDT, &isToday); err != nil {
+			return decimal.Zero, decimal.Zero, 0, err
+		}
+		if !amountUSDT.Valid {
+			unvalued++
+			continue
+		}
+		allTime = allTime.Add(amountUSDT.Decimal)
+		if isToday {
+			today = today.Add(amountUSDT.Decimal)
+		}
+	}
+
+	return today, allTime, unvalued, rows.Err()
+}
@@ -0,0 +1,33 @@
+package repository
+
+import "database/sql"
+
+type NotificationCursorRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationCursorRepository(db *sql.DB) *NotificationCursorRepository {
+	return &NotificationCursorRepository{db: db}
+}
+
+// Get returns the seq up to which notifications were applied, ok false before the first replay
+func (r *NotificationCursorRepository) Get() (int64, bool, error) {
+	var seq int64
+	err := r.db.QueryRow(`SELECT last_seq FROM notification_cursor WHERE id = 1`).Scan(&seq)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return seq, true, nil
+}
+
+// Save moves the cursor
+func (r *NotificationCursorRepository) Save(seq int64) error {
+	_, err := r.db.Exec(`
+		INSERT INTO notification_cursor (id, last_seq, updated_at) VALUES (1, $1, datetime('now'))
+		ON CONFLICT(id) DO UPDATE SET last_seq = excluded.last_seq, updated_at = excluded.updated_at
+	`, seq)
+	return err
+}
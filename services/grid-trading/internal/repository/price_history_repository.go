@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type PriceHistoryRepository struct {
+	db *sql.DB
+}
+
+func NewPriceHistoryRepository(db *sql.DB) *PriceHistoryRepository {
+	return &PriceHistoryRepository{db: db}
+}
+
+// Record stores a price sample
+func (r *PriceHistoryRepository) Record(symbol string, price decimal.Decimal, at time.Time) error {
+	query := `
+		INSERT INTO price_history (symbol, price, recorded_at)
+		VALUES ($1, $2, $3)
+	`
+
+	_, err := r.db.Exec(query, symbol, price.String(), at.UTC().Format("2006-01-02 15:04:05"))
+	return err
+}
+
+// GetFirstSince returns the earliest sample of symbol at or after since, or nil if there is none
+func (r *PriceHistoryRepository) GetFirstSince(symbol string, since time.Time) (*models.PricePoint, error) {
+	query := `
+		SELECT symbol, price, recorded_at
+		FROM price_history
+		WHERE symbol = $1 AND recorded_at >= $2
+		ORDER BY recorded_at ASC, id ASC
+		LIMIT 1
+	`
+
+	point := &models.PricePoint{}
+	var recordedAt string
+	err := r.db.QueryRow(query, symbol, since.UTC().Format("2006-01-02 15:04:05")).Scan(&point.Symbol, &point.Price, &recordedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	point.RecordedAt, _ = time.Parse("2006-01-02 15:04:05", recordedAt)
+	return point, nil
+}
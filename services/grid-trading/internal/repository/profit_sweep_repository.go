@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type ProfitSweepRepository struct {
+	db *sql.DB
+}
+
+func NewProfitSweepRepository(db *sql.DB) *ProfitSweepRepository {
+	return &ProfitSweepRepository{db: db}
+}
+
+func (r *ProfitSweepRepository) scanSweep(scanner interface{ Scan(...interface{}) error }) (*models.ProfitSweep, error) {
+	sweep := &models.ProfitSweep{}
+	var reference, errorMsg sql.NullString
+	var createdAt string
+	err := scanner.Scan(
+		&sweep.ID, &sweep.Account, &sweep.Asset, &sweep.Amount, &sweep.Destination,
+		&sweep.UnsweptProfit, &sweep.Status, &reference, &errorMsg, &createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sweep.Reference = reference.String
+	sweep.Error = errorMsg.String
+
+	// Parse timestamps from TEXT format
+	sweep.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+
+	return sweep, nil
+}
+
+// Create records a sweep attempt
+func (r *ProfitSweepRepository) Create(sweep *models.ProfitSweep) error {
+	query := `
+		INSERT INTO profit_sweeps (account, asset, amount, destination, unswept_profit, status, reference, error_msg)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	result, err := r.db.Exec(
+		query,
+		sweep.Account,
+		sweep.Asset,
+		sweep.Amount.String(),
+		sweep.Destination,
+		sweep.UnsweptProfit.String(),
+		sweep.Status,
+		sql.NullString{String: sweep.Reference, Valid: sweep.Reference != ""},
+		sql.NullString{String: sweep.Error, Valid: sweep.Error != ""},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record profit sweep: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+	sweep.ID = int(id)
+	return nil
+}
+
+// GetRecent returns the latest sweep attempts, newest first
+func (r *ProfitSweepRepository) GetRecent(limit int) ([]*models.ProfitSweep, error) {
+	query := `
+		SELECT id, account, asset, amount, destination, unswept_profit, status, reference, error_msg, created_at
+		FROM profit_sweeps
+		ORDER BY id DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sweeps []*models.ProfitSweep
+	for rows.Next() {
+		sweep, err := r.scanSweep(rows)
+		if err != nil {
+			return nil, err
+		}
+		sweeps = append(sweeps, sweep)
+	}
+
+	return sweeps, rows.Err()
+}
+
+// GetSweptByAccount totals completed sweeps per account
+func (r *ProfitSweepRepository) GetSweptByAccount() (map[string]decimal.Decimal, error) {
+	query := `
+		SELECT account, amount
+		FROM profit_sweeps
+		WHERE status = 'COMPLETED'
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Summed in Go - SQLite SUM over TEXT would go through floats
+	swept := make(map[string]decimal.Decimal)
+	for rows.Next() {
+		var account string
+		var amount decimal.Decimal
+		if err := rows.Scan(&account, &amount); err != nil {
+			return nil, err
+		}
+		swept[account] = swept[account].Add(amount)
+	}
+
+	return swept, rows.Err()
+}
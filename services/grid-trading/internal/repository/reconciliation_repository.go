@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+type ReconciliationRepository struct {
+	db *sql.DB
+}
+
+func NewReconciliationRepository(db *sql.DB) *ReconciliationRepository {
+	return &ReconciliationRepository{db: db}
+}
+
+// Save stores a report and sets its ID
+func (r *ReconciliationRepository) Save(report *models.ReconciliationReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reconciliation report: %w", err)
+	}
+
+	result, err := r.db.Exec(`INSERT INTO reconciliation_reports (status, report, created_at) VALUES ($1, $2, $3)`,
+		report.Status, string(data), report.CreatedAt.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Errorf("failed to save reconciliation report: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get reconciliation report ID: %w", err)
+	}
+	report.ID = int(id)
+	return nil
+}
+
+// GetLatest returns the newest report, nil if there is none
+func (r *ReconciliationRepository) GetLatest() (*models.ReconciliationReport, error) {
+	var id int
+	var data string
+	err := r.db.QueryRow(`SELECT id, report FROM reconciliation_reports ORDER BY id DESC LIMIT 1`).Scan(&id, &data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ReconciliationReport{}
+	if err := json.Unmarshal([]byte(data), report); err != nil {
+		return nil, fmt.Errorf("failed to parse reconciliation report: %w", err)
+	}
+	report.ID = id
+	return report, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type SellDustRepository struct {
+	db *sql.DB
+}
+
+func NewSellDustRepository(db *sql.DB) *SellDustRepository {
+	return &SellDustRepository{db: db}
+}
+
+// Get returns the dust of a grid, zero if none was recorded
+func (r *SellDustRepository) Get(account, symbol string) (decimal.Decimal, error) {
+	var dust decimal.Decimal
+	err := r.db.QueryRow(`SELECT dust_coin FROM sell_dust WHERE account = $1 AND symbol = $2`, account, symbol).Scan(&dust)
+	if err == sql.ErrNoRows {
+		return decimal.Zero, nil
+	}
+	return dust, err
+}
+
+// Set stores the dust of a grid
+func (r *SellDustRepository) Set(account, symbol string, dust decimal.Decimal) error {
+	query := `
+		INSERT INTO sell_dust (account, symbol, dust_coin, updated_at)
+		VALUES ($1, $2, $3, datetime('now'))
+		ON CONFLICT(account, symbol) DO UPDATE SET dust_coin = excluded.dust_coin, updated_at = excluded.updated_at
+	`
+	_, err := r.db.Exec(query, account, symbol, dust.String())
+	return err
+}
+
+// GetAll returns the dust of every grid, by symbol
+func (r *SellDustRepository) GetAll() ([]*models.SellDust, error) {
+	rows, err := r.db.Query(`SELECT account, symbol, dust_coin, updated_at FROM sell_dust ORDER BY symbol, account`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.SellDust
+	for rows.Next() {
+		dust := &models.SellDust{}
+		var updatedAt string
+		if err := rows.Scan(&dust.Account, &dust.Symbol, &dust.DustCoin, &updatedAt); err != nil {
+			return nil, err
+		}
+		dust.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+		result = append(result, dust)
+	}
+	return result, rows.Err()
+}
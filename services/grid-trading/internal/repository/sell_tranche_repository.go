@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type SellTrancheRepository struct {
+	db *sql.DB
+}
+
+func NewSellTrancheRepository(db *sql.DB) *SellTrancheRepository {
+	return &SellTrancheRepository{db: db}
+}
+
+const sellTrancheColumns = `id, grid_level_id, position, share_pct, profit_pct, status, amount, order_id, price,
+	sold_amount, proceeds_usdt, commission, commission_asset, fee_usdt, updated_at`
+
+// Create stores the ladder of a level, numbering the tranches in the order given
+func (r *SellTrancheRepository) Create(levelID int, tranches []*models.SellTranche) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, tranche := range tranches {
+		if _, err := tx.Exec(`INSERT INTO sell_tranches (grid_level_id, position, share_pct, profit_pct) VALUES ($1, $2, $3, $4)`,
+			levelID, i+1, tranche.SharePct, tranche.ProfitPct); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetByLevel returns the ladder of a level in position order, none if it sells in one order
+func (r *SellTrancheRepository) GetByLevel(levelID int) ([]*models.SellTranche, error) {
+	return r.query(`SELECT `+sellTrancheColumns+` FROM sell_tranches WHERE grid_level_id = $1 ORDER BY position`, levelID)
+}
+
+// GetByOrderID returns the open tranche an order was placed for, nil if none
+func (r *SellTrancheRepository) GetByOrderID(orderID string) (*models.SellTranche, error) {
+	tranches, err := r.query(`SELECT `+sellTrancheColumns+` FROM sell_tranches WHERE order_id = $1 AND status = $2`, orderID, models.TrancheOpen)
+	if err != nil || len(tranches) == 0 {
+		return nil, err
+	}
+	return tranches[0], nil
+}
+
+// GetOpen returns every tranche with an open sell order
+func (r *SellTrancheRepository) GetOpen() ([]*models.SellTranche, error) {
+	return r.query(`SELECT `+sellTrancheColumns+` FROM sell_tranches WHERE status = $1 ORDER BY grid_level_id, position`, models.TrancheOpen)
+}
+
+// SetAmount plans the coin a tranche sells this cycle
+func (r *SellTrancheRepository) SetAmount(id int, amount decimal.Decimal) error {
+	_, err := r.db.Exec(`UPDATE sell_tranches SET amount = $1, updated_at = datetime('now') WHERE id = $2`, amount, id)
+	return err
+}
+
+// MarkOpen records the sell order placed for a tranche
+func (r *SellTrancheRepository) MarkOpen(id int, orderID string, price decimal.Decimal) error {
+	query := `
+		UPDATE sell_tranches
+		SET status = $1, order_id = $2, price = $3, updated_at = datetime('now')
+		WHERE id = $4 AND status != $5
+	`
+	_, err := r.db.Exec(query, models.TrancheOpen, orderID, price, id, models.TrancheSold)
+	return err
+}
+
+// MarkSold records the fill of a tranche's order. Returns false if the tranche no longer
+// waits on that order (the fill was already applied).
+func (r *SellTrancheRepository) MarkSold(id int, orderID string, soldAmount, proceedsUSDT decimal.Decimal, fee models.Fee) (bool, error) {
+	query := `
+		UPDATE sell_tranches
+		SET status = $1, sold_amount = $2, proceeds_usdt = $3, commission = $4, commission_asset = $5, fee_usdt = $6,
+		    updated_at = datetime('now')
+		WHERE id = $7 AND status = $8 AND order_id = $9
+	`
+	result, err := r.db.Exec(query, models.TrancheSold, soldAmount, proceedsUSDT, feeCommission(fee), feeAsset(fee), fee.USDT,
+		id, models.TrancheOpen, orderID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// Release returns a tranche whose order was cancelled to PENDING with the coin it has left.
+// Returns false if the tranche no longer waits on that order.
+func (r *SellTrancheRepository) Release(id int, orderID string, remaining decimal.Decimal) (bool, error) {
+	query := `
+		UPDATE sell_tranches
+		SET status = $1, amount = $2, order_id = NULL, updated_at = datetime('now')
+		WHERE id = $3 AND status = $4 AND order_id = $5
+	`
+	result, err := r.db.Exec(query, models.TranchePending, remaining, id, models.TrancheOpen, orderID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// ReleaseOpen returns the tranches of a level still marked OPEN to PENDING - used when the
+// level is placing its sells again, so none of their old orders is open
+func (r *SellTrancheRepository) ReleaseOpen(levelID int) error {
+	query := `
+		UPDATE sell_tranches
+		SET status = $1, order_id = NULL, updated_at = datetime('now')
+		WHERE grid_level_id = $2 AND status = $3
+	`
+	_, err := r.db.Exec(query, models.TranchePending, levelID, models.TrancheOpen)
+	return err
+}
+
+// Reset clears the cycle of a level's ladder
+func (r *SellTrancheRepository) Reset(levelID int) error {
+	query := `
+		UPDATE sell_tranches
+		SET status = $1, amount = NULL, order_id = NULL, price = NULL, sold_amount = NULL, proceeds_usdt = NULL,
+		    commission = NULL, commission_asset = NULL, fee_usdt = NULL, updated_at = datetime('now')
+		WHERE grid_level_id = $2
+	`
+	_, err := r.db.Exec(query, models.TranchePending, levelID)
+	return err
+}
+
+func (r *SellTrancheRepository) query(query string, args ...interface{}) ([]*models.SellTranche, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.SellTranche
+	for rows.Next() {
+		tranche := &models.SellTranche{}
+		var orderID, commissionAsset sql.NullString
+		var commission decimal.NullDecimal
+		var updatedAt string
+		if err := rows.Scan(&tranche.ID, &tranche.LevelID, &tranche.Position, &tranche.SharePct, &tranche.ProfitPct, &tranche.Status,
+			&tranche.Amount, &orderID, &tranche.Price, &tranche.SoldAmount, &tranche.ProceedsUSDT,
+			&commission, &commissionAsset, &tranche.Fee.USDT, &updatedAt); err != nil {
+			return nil, err
+		}
+		tranche.OrderID = orderID.String
+		tranche.Fee.Commission = commission.Decimal
+		tranche.Fee.Asset = commissionAsset.String
+		tranche.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+		result = append(result, tranche)
+	}
+	return result, rows.Err()
+}
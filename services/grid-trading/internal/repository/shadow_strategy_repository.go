@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+type ShadowStrategyRepository struct {
+	db *sql.DB
+}
+
+func NewShadowStrategyRepository(db *sql.DB) *ShadowStrategyRepository {
+	return &ShadowStrategyRepository{db: db}
+}
+
+const shadowStrategyColumns = `id, symbol, name, grid_type, min_price, max_price, grid_step, buy_amount, buy_order_type, fee_aware_sell, created_at`
+
+// Create stores a shadow strategy
+func (r *ShadowStrategyRepository) Create(strategy *models.ShadowStrategy) error {
+	query := `
+		INSERT INTO shadow_strategies (symbol, name, grid_type, min_price, max_price, grid_step, buy_amount, buy_order_type, fee_aware_sell)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	result, err := r.db.Exec(query, strategy.Symbol, strategy.Name, strategy.GridType,
+		strategy.MinPrice.String(), strategy.MaxPrice.String(), strategy.GridStep.String(),
+		strategy.BuyAmount.String(), strategy.BuyOrderType, strategy.FeeAwareSell)
+	if err != nil {
+		return fmt.Errorf("failed to create shadow strategy: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+	strategy.ID = int(id)
+	return nil
+}
+
+// Get returns a shadow strategy, nil if there is none with the id
+func (r *ShadowStrategyRepository) Get(id int) (*models.ShadowStrategy, error) {
+	strategy, err := scanShadowStrategy(r.db.QueryRow(`SELECT `+shadowStrategyColumns+` FROM shadow_strategies WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return strategy, err
+}
+
+// GetBySymbol returns the shadow strategies of a symbol, oldest first
+func (r *ShadowStrategyRepository) GetBySymbol(symbol string) ([]*models.ShadowStrategy, error) {
+	rows, err := r.db.Query(`SELECT `+shadowStrategyColumns+` FROM shadow_strategies WHERE symbol = $1 ORDER BY id`, symbol)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var strategies []*models.ShadowStrategy
+	for rows.Next() {
+		strategy, err := scanShadowStrategy(rows)
+		if err != nil {
+			return nil, err
+		}
+		strategies = append(strategies, strategy)
+	}
+	return strategies, rows.Err()
+}
+
+// Delete removes a shadow strategy. Returns false if there is no such strategy.
+func (r *ShadowStrategyRepository) Delete(id int) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM shadow_strategies WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func scanShadowStrategy(scanner interface{ Scan(...interface{}) error }) (*models.ShadowStrategy, error) {
+	strategy := &models.ShadowStrategy{}
+	var createdAt string
+	err := scanner.Scan(&strategy.ID, &strategy.Symbol, &strategy.Name, &strategy.GridType,
+		&strategy.MinPrice, &strategy.MaxPrice, &strategy.GridStep, &strategy.BuyAmount,
+		&strategy.BuyOrderType, &strategy.FeeAwareSell, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	strategy.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+	return strategy, nil
+}
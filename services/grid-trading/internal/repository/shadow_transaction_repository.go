@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+type ShadowTransactionRepository struct {
+	db *sql.DB
+}
+
+func NewShadowTransactionRepository(db *sql.DB) *ShadowTransactionRepository {
+	return &ShadowTransactionRepository{db: db}
+}
+
+const shadowColumns = `id, strategy_id, grid_level_id, symbol, account, side, status, target_price, trigger_price, amount_coin, amount_usdt, profit_usdt, created_at`
+
+// Record stores a shadow order or fill
+func (r *ShadowTransactionRepository) Record(tx *models.ShadowTransaction) error {
+	query := `
+		INSERT INTO shadow_transactions (strategy_id, grid_level_id, symbol, account, side, status, target_price, trigger_price, amount_coin, amount_usdt, profit_usdt)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	var profit sql.NullString
+	if tx.ProfitUSDT.Valid {
+		profit = sql.NullString{String: tx.ProfitUSDT.Decimal.String(), Valid: true}
+	}
+	result, err := r.db.Exec(query, tx.StrategyID, tx.GridLevelID, tx.Symbol, tx.Account, tx.Side, tx.Status,
+		tx.TargetPrice.String(), tx.TriggerPrice.String(), tx.AmountCoin.String(), tx.AmountUSDT.String(), profit)
+	if err != nil {
+		return fmt.Errorf("failed to record shadow transaction: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+	tx.ID = int(id)
+	return nil
+}
+
+// GetLatestByLevel returns the newest shadow transaction of each level of a symbol's watch-only
+// grid (strategy 0) or shadow strategy - what the level is doing in the shadow
+func (r *ShadowTransactionRepository) GetLatestByLevel(symbol string, strategyID int) (map[int]*models.ShadowTransaction, error) {
+	rows, err := r.db.Query(`
+		SELECT `+shadowColumns+` FROM shadow_transactions
+		WHERE id IN (SELECT MAX(id) FROM shadow_transactions WHERE symbol = $1 AND strategy_id = $2 GROUP BY grid_level_id)
+	`, symbol, strategyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	latest := make(map[int]*models.ShadowTransaction)
+	for rows.Next() {
+		tx, err := scanShadowTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		latest[tx.GridLevelID] = tx
+	}
+	return latest, rows.Err()
+}
+
+// GetBySymbol returns the shadow transactions of a symbol's watch-only grid (strategy 0) or shadow strategy, oldest first
+func (r *ShadowTransactionRepository) GetBySymbol(symbol string, strategyID int) ([]*models.ShadowTransaction, error) {
+	rows, err := r.db.Query(`SELECT `+shadowColumns+` FROM shadow_transactions WHERE symbol = $1 AND strategy_id = $2 ORDER BY id`, symbol, strategyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.ShadowTransaction
+	for rows.Next() {
+		tx, err := scanShadowTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, tx)
+	}
+	return result, rows.Err()
+}
+
+// DeleteBySymbol forgets the shadow transactions of a symbol's watch-only grid (strategy 0) or
+// shadow strategy, returning how many there were
+func (r *ShadowTransactionRepository) DeleteBySymbol(symbol string, strategyID int) (int, error) {
+	result, err := r.db.Exec(`DELETE FROM shadow_transactions WHERE symbol = $1 AND strategy_id = $2`, symbol, strategyID)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	return int(rows), err
+}
+
+func scanShadowTransaction(scanner interface{ Scan(...interface{}) error }) (*models.ShadowTransaction, error) {
+	tx := &models.ShadowTransaction{}
+	var createdAt string
+	err := scanner.Scan(&tx.ID, &tx.StrategyID, &tx.GridLevelID, &tx.Symbol, &tx.Account, &tx.Side, &tx.Status,
+		&tx.TargetPrice, &tx.TriggerPrice, &tx.AmountCoin, &tx.AmountUSDT, &tx.ProfitUSDT, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	tx.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+	return tx, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// Format of the shard timestamps - compared with datetime('now'), which is UTC
+const shardTimeFormat = "2006-01-02 15:04:05"
+
+// ShardRepository keeps the instances and symbol leases of a sharded grid-trading in the shared database
+type ShardRepository struct {
+	db *sql.DB
+}
+
+func NewShardRepository(db *sql.DB) *ShardRepository {
+	return &ShardRepository{db: db}
+}
+
+func shardExpiry(ttl time.Duration) string {
+	return time.Now().UTC().Add(ttl).Format(shardTimeFormat)
+}
+
+// Heartbeat registers an instance, or keeps it registered, for another ttl
+func (r *ShardRepository) Heartbeat(instanceID, url string, ttl time.Duration) error {
+	query := `
+		INSERT INTO shard_instances (instance_id, url, heartbeat_at, expires_at)
+		VALUES ($1, $2, datetime('now'), $3)
+		ON CONFLICT(instance_id) DO UPDATE SET url = excluded.url, heartbeat_at = excluded.heartbeat_at, expires_at = excluded.expires_at
+	`
+	_, err := r.db.Exec(query, instanceID, url, shardExpiry(ttl))
+	return err
+}
+
+// Deregister removes an instance and frees its symbols for the others
+func (r *ShardRepository) Deregister(instanceID string) error {
+	if _, err := r.db.Exec(`DELETE FROM symbol_leases WHERE instance_id = $1`, instanceID); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`DELETE FROM shard_instances WHERE instance_id = $1`, instanceID)
+	return err
+}
+
+// Instances returns the instances with a current heartbeat, by instance ID
+func (r *ShardRepository) Instances() ([]*models.ShardInstance, error) {
+	rows, err := r.db.Query(`
+		SELECT instance_id, url, heartbeat_at FROM shard_instances
+		WHERE expires_at > datetime('now')
+		ORDER BY instance_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instances []*models.ShardInstance
+	for rows.Next() {
+		instance := &models.ShardInstance{}
+		var heartbeatAt string
+		if err := rows.Scan(&instance.InstanceID, &instance.URL, &heartbeatAt); err != nil {
+			return nil, err
+		}
+		instance.HeartbeatAt, _ = time.Parse(shardTimeFormat, heartbeatAt)
+		instances = append(instances, instance)
+	}
+	return instances, rows.Err()
+}
+
+// Leases returns the symbol leases that haven't expired, by symbol
+func (r *ShardRepository) Leases() ([]*models.SymbolLease, error) {
+	rows, err := r.db.Query(`
+		SELECT symbol, instance_id, url, expires_at FROM symbol_leases
+		WHERE expires_at > datetime('now')
+		ORDER BY symbol
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leases []*models.SymbolLease
+	for rows.Next() {
+		lease := &models.SymbolLease{}
+		var expiresAt string
+		if err := rows.Scan(&lease.Symbol, &lease.InstanceID, &lease.URL, &expiresAt); err != nil {
+			return nil, err
+		}
+		lease.ExpiresAt, _ = time.Parse(shardTimeFormat, expiresAt)
+		leases = append(leases, lease)
+	}
+	return leases, rows.Err()
+}
+
+// RenewLeases extends every unexpired lease of an instance by ttl. An expired lease is not
+// renewed - another instance may have taken the symbol over already.
+func (r *ShardRepository) RenewLeases(instanceID string, ttl time.Duration) (int, error) {
+	result, err := r.db.Exec(`
+		UPDATE symbol_leases SET expires_at = $1
+		WHERE instance_id = $2 AND expires_at > datetime('now')
+	`, shardExpiry(ttl), instanceID)
+	if err != nil {
+		return 0, err
+	}
+	renewed, err := result.RowsAffected()
+	return int(renewed), err
+}
+
+// Claim leases a symbol to an instance for ttl, if it is free, expired or the instance's already.
+// Returns false if another instance holds it.
+func (r *ShardRepository) Claim(symbol, instanceID, url string, ttl time.Duration) (bool, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO symbol_leases (symbol, instance_id, url, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(symbol) DO UPDATE SET instance_id = excluded.instance_id, url = excluded.url, expires_at = excluded.expires_at
+		WHERE symbol_leases.instance_id = excluded.instance_id OR symbol_leases.expires_at <= datetime('now')
+	`, symbol, instanceID, url, shardExpiry(ttl))
+	if err != nil {
+		return false, err
+	}
+	claimed, err := result.RowsAffected()
+	return claimed > 0, err
+}
+
+// Release gives up an instance's lease of a symbol
+func (r *ShardRepository) Release(symbol, instanceID string) error {
+	_, err := r.db.Exec(`DELETE FROM symbol_leases WHERE symbol = $1 AND instance_id = $2`, symbol, instanceID)
+	return err
+}
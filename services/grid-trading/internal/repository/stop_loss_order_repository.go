@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+type StopLossOrderRepository struct {
+	db *sql.DB
+}
+
+func NewStopLossOrderRepository(db *sql.DB) *StopLossOrderRepository {
+	return &StopLossOrderRepository{db: db}
+}
+
+// Save records the stop-loss leg of a level's OCO sell, replacing the one of an earlier sell
+func (r *StopLossOrderRepository) Save(order *models.StopLossOrder) error {
+	query := `
+		INSERT INTO stop_loss_orders (grid_level_id, sell_order_id, stop_order_id, stop_price, created_at)
+		VALUES ($1, $2, $3, $4, datetime('now'))
+		ON CONFLICT(grid_level_id) DO UPDATE SET
+			sell_order_id = excluded.sell_order_id,
+			stop_order_id = excluded.stop_order_id,
+			stop_price = excluded.stop_price,
+			created_at = excluded.created_at
+	`
+	_, err := r.db.Exec(query, order.LevelID, order.SellOrderID, order.StopOrderID, order.StopPrice)
+	return err
+}
+
+// GetByLevel returns the stop-loss leg recorded for a level, nil if none
+func (r *StopLossOrderRepository) GetByLevel(levelID int) (*models.StopLossOrder, error) {
+	return r.getOne(`SELECT grid_level_id, sell_order_id, stop_order_id, stop_price, created_at FROM stop_loss_orders WHERE grid_level_id = $1`, levelID)
+}
+
+// GetByStopOrderID returns the stop-loss leg with an order ID, nil if none
+func (r *StopLossOrderRepository) GetByStopOrderID(orderID string) (*models.StopLossOrder, error) {
+	return r.getOne(`SELECT grid_level_id, sell_order_id, stop_order_id, stop_price, created_at FROM stop_loss_orders WHERE stop_order_id = $1`, orderID)
+}
+
+// Delete forgets the stop-loss leg of a level once its OCO is over
+func (r *StopLossOrderRepository) Delete(levelID int) error {
+	_, err := r.db.Exec(`DELETE FROM stop_loss_orders WHERE grid_level_id = $1`, levelID)
+	return err
+}
+
+func (r *StopLossOrderRepository) getOne(query string, args ...interface{}) (*models.StopLossOrder, error) {
+	order := &models.StopLossOrder{}
+	var createdAt string
+	err := r.db.QueryRow(query, args...).Scan(&order.LevelID, &order.SellOrderID, &order.StopOrderID, &order.StopPrice, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	order.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+	return order, nil
+}
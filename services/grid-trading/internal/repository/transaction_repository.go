@@ -93,13 +93,15 @@ func (r *TransactionRepository) RecordBuyFilled(
 	executedPrice decimal.Decimal,
 	amountCoin decimal.Decimal,
 	amountUSDT decimal.Decimal,
+	fee models.Fee,
 ) error {
 	query := `
 		INSERT INTO transactions (
 			grid_level_id, symbol, side, status,
 			order_id, target_price, executed_price,
-			amount_coin, amount_usdt
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			amount_coin, amount_usdt,
+			commission, commission_asset, fee_usdt
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id
 	`
 
@@ -115,6 +117,9 @@ func (r *TransactionRepository) RecordBuyFilled(
 		executedPrice,
 		amountCoin,
 		amountUSDT,
+		feeCommission(fee),
+		feeAsset(fee),
+		fee.USDT,
 	).Scan(&txID)
 
 	if err != nil {
@@ -138,14 +143,16 @@ func (r *TransactionRepository) RecordSellFilled(
 	relatedBuyID int,
 	profitUSDT decimal.Decimal,
 	profitPct decimal.Decimal,
+	fee models.Fee,
 ) error {
 	query := `
 		INSERT INTO transactions (
 			grid_level_id, symbol, side, status,
 			order_id, target_price, executed_price,
 			amount_coin, amount_usdt,
-			related_buy_id, profit_usdt, profit_pct
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			related_buy_id, profit_usdt, profit_pct,
+			commission, commission_asset, fee_usdt
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		RETURNING id
 	`
 
@@ -164,6 +171,9 @@ func (r *TransactionRepository) RecordSellFilled(
 		relatedBuyID,
 		profitUSDT,
 		profitPct,
+		feeCommission(fee),
+		feeAsset(fee),
+		fee.USDT,
 	).Scan(&txID)
 
 	if err != nil {
@@ -181,6 +191,87 @@ func (r *TransactionRepository) RecordSellFilled(
 	return err
 }
 
+// feeCommission stores NULL rather than zero when the fee is unknown
+func feeCommission(fee models.Fee) decimal.NullDecimal {
+	return decimal.NullDecimal{Decimal: fee.Commission, Valid: fee.Asset != ""}
+}
+
+func feeAsset(fee models.Fee) sql.NullString {
+	return sql.NullString{String: fee.Asset, Valid: fee.Asset != ""}
+}
+
+// RecordCancelled records an order cancelled on the exchange with what filled before the cancel
+// (amountCoin zero and executedPrice unset when nothing did)
+func (r *TransactionRepository) RecordCancelled(
+	gridLevelID int,
+	symbol string,
+	side models.TransactionSide,
+	orderID string,
+	targetPrice decimal.Decimal,
+	executedPrice decimal.Decimal,
+	amountCoin decimal.Decimal,
+) error {
+	query := `
+		INSERT INTO transactions (
+			grid_level_id, symbol, side, status,
+			order_id, target_price, executed_price,
+			amount_coin, amount_usdt
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	var executed decimal.NullDecimal
+	if amountCoin.IsPositive() {
+		executed = decimal.NewNullDecimal(executedPrice)
+	}
+
+	_, err := r.db.Exec(
+		query,
+		gridLevelID,
+		symbol,
+		side,
+		models.StatusCancelled,
+		orderID,
+		targetPrice,
+		executed,
+		amountCoin,
+		amountCoin.Mul(executedPrice),
+	)
+
+	if err != nil {
+		log.Printf("ERROR: Failed to record %s CANCELLED transaction for level %d: %v", side, gridLevelID, err)
+	} else {
+		log.Printf("INFO: Recorded %s CANCELLED - Level: %d, Order: %s, Target: %s, Filled before cancel: %s coins @ %s",
+			side, gridLevelID, orderID, targetPrice, amountCoin, executedPrice)
+	}
+
+	return err
+}
+
+// GetCancelledSellProceeds sums the USDT from sells that part-filled before being cancelled,
+// since the level's buy transaction buyTxID - revenue of the cycle the final sell completes
+func (r *TransactionRepository) GetCancelledSellProceeds(gridLevelID, buyTxID int) (decimal.Decimal, error) {
+	query := `
+		SELECT amount_usdt FROM transactions
+		WHERE grid_level_id = $1 AND side = $2 AND status = $3 AND id > $4 AND amount_usdt IS NOT NULL
+	`
+
+	rows, err := r.db.Query(query, gridLevelID, models.SideSell, models.StatusCancelled, buyTxID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	defer rows.Close()
+
+	total := decimal.Zero
+	for rows.Next() {
+		var amount decimal.Decimal
+		if err := rows.Scan(&amount); err != nil {
+			return decimal.Zero, err
+		}
+		total = total.Add(amount)
+	}
+	return total, rows.Err()
+}
+
 func (r *TransactionRepository) RecordBuyError(
 	gridLevelID int,
 	symbol string,
@@ -259,7 +350,8 @@ func (r *TransactionRepository) GetLastBuyForLevel(gridLevelID int) (*models.Tra
 		       order_id, target_price, executed_price,
 		       amount_coin, amount_usdt,
 		       related_buy_id, profit_usdt, profit_pct,
-		       error_code, error_msg, created_at
+		       error_code, error_msg, created_at,
+		       commission, commission_asset, fee_usdt
 		FROM transactions
 		WHERE grid_level_id = $1 AND side = $2 AND status = $3
 		ORDER BY created_at DESC
@@ -274,6 +366,7 @@ func (r *TransactionRepository) GetLastBuyForLevel(gridLevelID int) (*models.Tra
 		&tx.AmountCoin, &tx.AmountUSDT,
 		&tx.RelatedBuyID, &tx.ProfitUSDT, &tx.ProfitPct,
 		&tx.ErrorCode, &tx.ErrorMsg, &createdAtStr,
+		&tx.Commission, &tx.CommissionAsset, &tx.FeeUSDT,
 	)
 
 	if err == sql.ErrNoRows {
@@ -287,44 +380,60 @@ func (r *TransactionRepository) GetLastBuyForLevel(gridLevelID int) (*models.Tra
 	return tx, nil
 }
 
-func (r *TransactionRepository) GetDailyStats() (buys, sells, errors int, profit decimal.Decimal, err error) {
+// GetSymbolStats totals today's fills and errors and realized profit per symbol in one read,
+// so the per-symbol figures always add up to the totals built from them
+func (r *TransactionRepository) GetSymbolStats(periods models.ReportPeriods) ([]*models.SymbolStats, error) {
 	query := `
 		SELECT
-			COUNT(CASE WHEN side = 'BUY' AND status = 'FILLED' THEN 1 END) as buys_today,
-			COUNT(CASE WHEN side = 'SELL' AND status = 'FILLED' THEN 1 END) as sells_today,
-			COUNT(CASE WHEN status = 'ERROR' THEN 1 END) as errors_today,
-			COALESCE(SUM(CASE WHEN side = 'SELL' AND status = 'FILLED' THEN profit_usdt ELSE 0 END), 0) as profit_today
+			symbol,
+			COUNT(CASE WHEN side = 'BUY' AND status = 'FILLED' AND created_at >= $1 THEN 1 END) as buys_today,
+			COUNT(CASE WHEN side = 'SELL' AND status = 'FILLED' AND created_at >= $1 THEN 1 END) as sells_today,
+			COUNT(CASE WHEN status = 'ERROR' AND created_at >= $1 THEN 1 END) as errors_today,
+			COALESCE(SUM(CASE WHEN side = 'SELL' AND status = 'FILLED' AND created_at >= $1 THEN profit_usdt ELSE 0 END), 0) as profit_today,
+			COALESCE(SUM(CASE WHEN side = 'SELL' AND status = 'FILLED' AND created_at >= $2 THEN profit_usdt ELSE 0 END), 0) as profit_week,
+			COALESCE(SUM(CASE WHEN side = 'SELL' AND status = 'FILLED' AND created_at >= $3 THEN profit_usdt ELSE 0 END), 0) as profit_month,
+			COALESCE(SUM(CASE WHEN side = 'SELL' AND status = 'FILLED' THEN profit_usdt ELSE 0 END), 0) as profit_all_time
 		FROM transactions
-		WHERE date(created_at) = date('now')
+		GROUP BY symbol
+		ORDER BY symbol
 	`
 
-	var profitStr string
-	err = r.db.QueryRow(query).Scan(&buys, &sells, &errors, &profitStr)
+	rows, err := r.db.Query(query, periodArgs(periods)...)
 	if err != nil {
-		return 0, 0, 0, decimal.Zero, err
+		return nil, err
 	}
-
-	profit, err = decimal.NewFromString(profitStr)
-	if err != nil {
-		profit = decimal.Zero
+	defer rows.Close()
+
+	var stats []*models.SymbolStats
+	for rows.Next() {
+		st := &models.SymbolStats{}
+		var todayStr, weekStr, monthStr, allTimeStr string
+		if err := rows.Scan(&st.Symbol, &st.BuysToday, &st.SellsToday, &st.ErrorsToday, &todayStr, &weekStr, &monthStr, &allTimeStr); err != nil {
+			return nil, err
+		}
+		st.ProfitToday, _ = decimal.NewFromString(todayStr)
+		st.ProfitThisWeek, _ = decimal.NewFromString(weekStr)
+		st.ProfitThisMonth, _ = decimal.NewFromString(monthStr)
+		st.ProfitAllTime, _ = decimal.NewFromString(allTimeStr)
+		stats = append(stats, st)
 	}
 
-	return buys, sells, errors, profit, nil
+	return stats, rows.Err()
 }
 
-func (r *TransactionRepository) GetProfitStats() (today, week, month, allTime decimal.Decimal, err error) {
+func (r *TransactionRepository) GetProfitStats(periods models.ReportPeriods) (today, week, month, allTime decimal.Decimal, err error) {
 	query := `
 		SELECT
-			COALESCE(SUM(CASE WHEN date(created_at) = date('now') THEN profit_usdt ELSE 0 END), 0) as profit_today,
-			COALESCE(SUM(CASE WHEN created_at >= date('now', 'weekday 0', '-6 days') THEN profit_usdt ELSE 0 END), 0) as profit_week,
-			COALESCE(SUM(CASE WHEN strftime('%Y-%m', created_at) = strftime('%Y-%m', 'now') THEN profit_usdt ELSE 0 END), 0) as profit_month,
+			COALESCE(SUM(CASE WHEN created_at >= $1 THEN profit_usdt ELSE 0 END), 0) as profit_today,
+			COALESCE(SUM(CASE WHEN created_at >= $2 THEN profit_usdt ELSE 0 END), 0) as profit_week,
+			COALESCE(SUM(CASE WHEN created_at >= $3 THEN profit_usdt ELSE 0 END), 0) as profit_month,
 			COALESCE(SUM(profit_usdt), 0) as profit_all_time
 		FROM transactions
 		WHERE side = 'SELL' AND status = 'FILLED'
 	`
 
 	var todayStr, weekStr, monthStr, allTimeStr string
-	err = r.db.QueryRow(query).Scan(&todayStr, &weekStr, &monthStr, &allTimeStr)
+	err = r.db.QueryRow(query, periodArgs(periods)...).Scan(&todayStr, &weekStr, &monthStr, &allTimeStr)
 	if err != nil {
 		return decimal.Zero, decimal.Zero, decimal.Zero, decimal.Zero, err
 	}
@@ -337,13 +446,240 @@ func (r *TransactionRepository) GetProfitStats() (today, week, month, allTime de
 	return today, week, month, allTime, nil
 }
 
+// GetRealizedProfitByAccount totals realized sell profit per account of the grid level
+func (r *TransactionRepository) GetRealizedProfitByAccount() (map[string]decimal.Decimal, error) {
+	query := `
+		SELECT g.account, t.profit_usdt
+		FROM transactions t
+		JOIN all_grid_levels g ON g.id = t.grid_level_id
+		WHERE t.side = 'SELL' AND t.status = 'FILLED' AND t.profit_usdt IS NOT NULL
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profits := make(map[string]decimal.Decimal)
+	for rows.Next() {
+		var account string
+		var profit decimal.Decimal
+		if err := rows.Scan(&account, &profit); err != nil {
+			return nil, err
+		}
+		profits[account] = profits[account].Add(profit)
+	}
+
+	return profits, rows.Err()
+}
+
+// GetRealizedProfitSince totals realized sell profit of symbol from since on
+func (r *TransactionRepository) GetRealizedProfitSince(symbol string, since time.Time) (decimal.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(profit_usdt), 0)
+		FROM transactions
+		WHERE symbol = $1 AND side = 'SELL' AND status = 'FILLED' AND created_at >= $2
+	`
+
+	var profitStr string
+	if err := r.db.QueryRow(query, symbol, since.UTC().Format("2006-01-02 15:04:05")).Scan(&profitStr); err != nil {
+		return decimal.Zero, err
+	}
+
+	profit, _ := decimal.NewFromString(profitStr)
+	return profit, nil
+}
+
+// GetDayStats totals the fills and errors in [from, to) - one reporting day
+func (r *TransactionRepository) GetDayStats(from, to time.Time) (*models.DayStats, error) {
+	query := `
+		SELECT side, status, amount_usdt, profit_usdt, commission_asset, fee_usdt
+		FROM transactions
+		WHERE created_at >= $1 AND created_at < $2 AND status IN ('FILLED', 'ERROR')
+	`
+
+	rows, err := r.db.Query(query, from.UTC().Format("2006-01-02 15:04:05"), to.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &models.DayStats{}
+	for rows.Next() {
+		var side, status string
+		var amountUSDT, profitUSDT, feeUSDT decimal.NullDecimal
+		var commissionAsset sql.NullString
+		if err := rows.Scan(&side, &status, &amountUSDT, &profitUSDT, &commissionAsset, &feeUSDT); err != nil {
+			return nil, err
+		}
+
+		if status == "ERROR" {
+			stats.Errors++
+			continue
+		}
+		if side == "BUY" {
+			stats.Buys++
+			stats.BoughtUSDT = stats.BoughtUSDT.Add(amountUSDT.Decimal)
+		} else {
+			stats.Sells++
+			stats.SoldUSDT = stats.SoldUSDT.Add(amountUSDT.Decimal)
+			stats.ProfitUSDT = stats.ProfitUSDT.Add(profitUSDT.Decimal)
+		}
+		if feeUSDT.Valid {
+			stats.FeesUSDT = stats.FeesUSDT.Add(feeUSDT.Decimal)
+		} else if commissionAsset.Valid {
+			stats.UnpricedFills++
+		}
+	}
+
+	return stats, rows.Err()
+}
+
+// GetLevelCycles totals filled sells per grid level in [from, to)
+func (r *TransactionRepository) GetLevelCycles(from, to time.Time) ([]*models.LevelCycles, error) {
+	query := `
+		SELECT grid_level_id, symbol, profit_usdt
+		FROM transactions
+		WHERE side = 'SELL' AND status = 'FILLED' AND created_at >= $1 AND created_at < $2
+		ORDER BY grid_level_id
+	`
+
+	rows, err := r.db.Query(query, from.UTC().Format("2006-01-02 15:04:05"), to.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.LevelCycles
+	for rows.Next() {
+		var levelID int
+		var symbol string
+		var profitUSDT decimal.NullDecimal
+		if err := rows.Scan(&levelID, &symbol, &profitUSDT); err != nil {
+			return nil, err
+		}
+
+		if n := len(result); n == 0 || result[n-1].GridLevelID != levelID {
+			result = append(result, &models.LevelCycles{GridLevelID: levelID, Symbol: symbol})
+		}
+		cycles := result[len(result)-1]
+		cycles.Cycles++
+		cycles.ProfitUSDT = cycles.ProfitUSDT.Add(profitUSDT.Decimal)
+	}
+
+	return result, rows.Err()
+}
+
+// GetTransactionsAfter returns up to limit transactions with an ID above afterID, in ID order
+func (r *TransactionRepository) GetTransactionsAfter(afterID, limit int) ([]*models.LevelTransaction, error) {
+	query := `
+		SELECT t.id, t.grid_level_id, t.symbol, t.side, t.status,
+		       t.order_id, t.target_price, t.executed_price,
+		       t.amount_coin, t.amount_usdt,
+		       t.related_buy_id, t.profit_usdt, t.profit_pct,
+		       t.error_code, t.error_msg, t.created_at,
+		       t.commission, t.commission_asset, t.fee_usdt,
+		       g.account, g.buy_price, g.sell_price
+		FROM transactions t
+		JOIN all_grid_levels g ON g.id = t.grid_level_id
+		WHERE t.id > $1
+		ORDER BY t.id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.LevelTransaction
+	for rows.Next() {
+		tx := &models.LevelTransaction{}
+		var createdAtStr string
+		if err := rows.Scan(
+			&tx.ID, &tx.GridLevelID, &tx.Symbol, &tx.Side, &tx.Status,
+			&tx.OrderID, &tx.TargetPrice, &tx.ExecutedPrice,
+			&tx.AmountCoin, &tx.AmountUSDT,
+			&tx.RelatedBuyID, &tx.ProfitUSDT, &tx.ProfitPct,
+			&tx.ErrorCode, &tx.ErrorMsg, &createdAtStr,
+			&tx.Commission, &tx.CommissionAsset, &tx.FeeUSDT,
+			&tx.Account, &tx.LevelBuyPrice, &tx.LevelSellPrice,
+		); err != nil {
+			return nil, err
+		}
+		tx.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+		result = append(result, tx)
+	}
+
+	return result, rows.Err()
+}
+
+// GetFeeStats totals commission paid on fills, in USDT per period and natively per asset
+func (r *TransactionRepository) GetFeeStats(periods models.ReportPeriods) (*models.FeeStats, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(CASE WHEN created_at >= $1 THEN fee_usdt ELSE 0 END), 0) as fees_today,
+			COALESCE(SUM(CASE WHEN created_at >= $2 THEN fee_usdt ELSE 0 END), 0) as fees_week,
+			COALESCE(SUM(CASE WHEN created_at >= $3 THEN fee_usdt ELSE 0 END), 0) as fees_month,
+			COALESCE(SUM(fee_usdt), 0) as fees_all_time,
+			COUNT(CASE WHEN commission_asset IS NOT NULL AND fee_usdt IS NULL THEN 1 END) as unpriced
+		FROM transactions
+		WHERE status = 'FILLED'
+	`
+
+	stats := &models.FeeStats{ByAsset: []models.AssetFees{}}
+	var todayStr, weekStr, monthStr, allTimeStr string
+	err := r.db.QueryRow(query, periodArgs(periods)...).Scan(&todayStr, &weekStr, &monthStr, &allTimeStr, &stats.UnpricedFills)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.TodayUSDT, _ = decimal.NewFromString(todayStr)
+	stats.WeekUSDT, _ = decimal.NewFromString(weekStr)
+	stats.MonthUSDT, _ = decimal.NewFromString(monthStr)
+	stats.AllTimeUSDT, _ = decimal.NewFromString(allTimeStr)
+
+	assetQuery := `
+		SELECT commission_asset,
+		       COALESCE(SUM(commission), 0),
+		       COALESCE(SUM(fee_usdt), 0),
+		       COUNT(*)
+		FROM transactions
+		WHERE status = 'FILLED' AND commission_asset IS NOT NULL
+		GROUP BY commission_asset
+		ORDER BY commission_asset
+	`
+
+	rows, err := r.db.Query(assetQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var assetFees models.AssetFees
+		var commissionStr, feeUSDTStr string
+		if err := rows.Scan(&assetFees.Asset, &commissionStr, &feeUSDTStr, &assetFees.Fills); err != nil {
+			return nil, err
+		}
+		assetFees.Commission, _ = decimal.NewFromString(commissionStr)
+		assetFees.FeeUSDT, _ = decimal.NewFromString(feeUSDTStr)
+		stats.ByAsset = append(stats.ByAsset, assetFees)
+	}
+
+	return stats, rows.Err()
+}
+
 func (r *TransactionRepository) GetLastBuy() (*models.Transaction, error) {
 	query := `
 		SELECT id, grid_level_id, symbol, side, status,
 		       order_id, target_price, executed_price,
 		       amount_coin, amount_usdt,
 		       related_buy_id, profit_usdt, profit_pct,
-		       error_code, error_msg, created_at
+		       error_code, error_msg, created_at,
+		       commission, commission_asset, fee_usdt
 		FROM transactions
 		WHERE side = 'BUY' AND status = 'FILLED'
 		ORDER BY created_at DESC
@@ -358,6 +694,7 @@ func (r *TransactionRepository) GetLastBuy() (*models.Transaction, error) {
 		&tx.AmountCoin, &tx.AmountUSDT,
 		&tx.RelatedBuyID, &tx.ProfitUSDT, &tx.ProfitPct,
 		&tx.ErrorCode, &tx.ErrorMsg, &createdAtStr,
+		&tx.Commission, &tx.CommissionAsset, &tx.FeeUSDT,
 	)
 
 	if err == sql.ErrNoRows {
@@ -377,7 +714,8 @@ func (r *TransactionRepository) GetLastSell() (*models.Transaction, error) {
 		       order_id, target_price, executed_price,
 		       amount_coin, amount_usdt,
 		       related_buy_id, profit_usdt, profit_pct,
-		       error_code, error_msg, created_at
+		       error_code, error_msg, created_at,
+		       commission, commission_asset, fee_usdt
 		FROM transactions
 		WHERE side = 'SELL' AND status = 'FILLED'
 		ORDER BY created_at DESC
@@ -392,6 +730,7 @@ func (r *TransactionRepository) GetLastSell() (*models.Transaction, error) {
 		&tx.AmountCoin, &tx.AmountUSDT,
 		&tx.RelatedBuyID, &tx.ProfitUSDT, &tx.ProfitPct,
 		&tx.ErrorCode, &tx.ErrorMsg, &createdAtStr,
+		&tx.Commission, &tx.CommissionAsset, &tx.FeeUSDT,
 	)
 
 	if err == sql.ErrNoRows {
@@ -403,4 +742,13 @@ func (r *TransactionRepository) GetLastSell() (*models.Transaction, error) {
 
 	tx.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
 	return tx, nil
-}
\ No newline at end of file
+}
+
+// periodArgs binds the day, week and month starts as $1, $2, $3
+func periodArgs(periods models.ReportPeriods) []interface{} {
+	return []interface{}{
+		periods.DayStart.UTC().Format("2006-01-02 15:04:05"),
+		periods.WeekStart.UTC().Format("2006-01-02 15:04:05"),
+		periods.MonthStart.UTC().Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) Create(webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (url, secret, symbol, event_types)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	var symbol, eventTypes sql.NullString
+	if webhook.Symbol != "" {
+		symbol = sql.NullString{String: webhook.Symbol, Valid: true}
+	}
+	if len(webhook.EventTypes) > 0 {
+		eventTypes = sql.NullString{String: strings.Join(webhook.EventTypes, ","), Valid: true}
+	}
+
+	var createdAtStr string
+	err := r.db.QueryRow(query, webhook.URL, webhook.Secret, symbol, eventTypes).Scan(&webhook.ID, &createdAtStr)
+	if err != nil {
+		return err
+	}
+
+	webhook.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	return nil
+}
+
+// GetAll returns all webhooks including their secrets
+func (r *WebhookRepository) GetAll() ([]*models.Webhook, error) {
+	query := `
+		SELECT id, url, secret, symbol, event_types, created_at
+		FROM webhooks
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*models.Webhook
+	for rows.Next() {
+		webhook := &models.Webhook{EventTypes: []string{}}
+		var symbol, eventTypes sql.NullString
+		var createdAtStr string
+		if err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &symbol, &eventTypes, &createdAtStr); err != nil {
+			return nil, err
+		}
+
+		webhook.Symbol = symbol.String
+		if eventTypes.Valid {
+			webhook.EventTypes = strings.Split(eventTypes.String, ",")
+		}
+		webhook.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// Delete removes a webhook and its undelivered events
+func (r *WebhookRepository) Delete(id int) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(`DELETE FROM webhook_outbox WHERE webhook_id = $1 AND status != $2`, id, models.WebhookDelivered); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// Enqueue queues an event for delivery to a webhook
+func (r *WebhookRepository) Enqueue(webhookID int, eventID, eventType, payload string) error {
+	query := `
+		INSERT INTO webhook_outbox (webhook_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.Exec(query, webhookID, eventID, eventType, payload)
+	return err
+}
+
+func (r *WebhookRepository) queryDeliveries(query string, args ...interface{}) ([]*models.WebhookDelivery, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		delivery := &models.WebhookDelivery{}
+		var url, secret, lastError sql.NullString
+		var nextAttemptAt, createdAt, updatedAt string
+		err := rows.Scan(
+			&delivery.ID, &delivery.WebhookID, &url, &secret,
+			&delivery.EventID, &delivery.EventType, &delivery.Payload, &delivery.Status,
+			&delivery.Attempts, &lastError, &nextAttemptAt, &createdAt, &updatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		delivery.URL = url.String
+		delivery.Secret = secret.String
+		delivery.LastError = lastError.String
+
+		// Parse timestamps from TEXT format
+		delivery.NextAttemptAt, _ = time.Parse("2006-01-02 15:04:05", nextAttemptAt)
+		delivery.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		delivery.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// GetDue returns pending deliveries whose next attempt time has passed, oldest first
+func (r *WebhookRepository) GetDue(limit int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT o.id, o.webhook_id, w.url, w.secret,
+		       o.event_id, o.event_type, o.payload, o.status,
+		       o.attempts, o.last_error, o.next_attempt_at, o.created_at, o.updated_at
+		FROM webhook_outbox o
+		JOIN webhooks w ON w.id = o.webhook_id
+		WHERE o.status = $1 AND o.next_attempt_at <= datetime('now')
+		ORDER BY o.id ASC
+		LIMIT $2
+	`
+
+	return r.queryDeliveries(query, models.WebhookPending, limit)
+}
+
+// GetByStatus returns the latest deliveries in the given status, newest first
+func (r *WebhookRepository) GetByStatus(status models.WebhookDeliveryStatus, limit int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT o.id, o.webhook_id, w.url, w.secret,
+		       o.event_id, o.event_type, o.payload, o.status,
+		       o.attempts, o.last_error, o.next_attempt_at, o.created_at, o.updated_at
+		FROM webhook_outbox o
+		LEFT JOIN webhooks w ON w.id = o.webhook_id
+		WHERE o.status = $1
+		ORDER BY o.id DESC
+		LIMIT $2
+	`
+
+	return r.queryDeliveries(query, status, limit)
+}
+
+func (r *WebhookRepository) MarkDelivered(id int) error {
+	query := `
+		UPDATE webhook_outbox
+		SET status = $1, attempts = attempts + 1, last_error = NULL, updated_at = datetime('now')
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(query, models.WebhookDelivered, id)
+	return err
+}
+
+// MarkFailed records a failed delivery, scheduling the next attempt or
+// moving the delivery to the dead-letter set once maxAttempts is reached
+func (r *WebhookRepository) MarkFailed(id int, lastError string, nextAttemptIn time.Duration, maxAttempts int) error {
+	query := `
+		UPDATE webhook_outbox
+		SET attempts = attempts + 1,
+		    last_error = $1,
+		    status = CASE WHEN attempts + 1 >= $2 THEN $3 ELSE status END,
+		    next_attempt_at = datetime('now', $4),
+		    updated_at = datetime('now')
+		WHERE id = $5
+	`
+
+	modifier := fmt.Sprintf("+%d seconds", int(nextAttemptIn.Seconds()))
+	_, err := r.db.Exec(query, lastError, maxAttempts, models.WebhookDead, modifier, id)
+	return err
+}
+
+// Requeue moves a dead-letter delivery back to pending for immediate delivery
+func (r *WebhookRepository) Requeue(id int) (bool, error) {
+	query := `
+		UPDATE webhook_outbox
+		SET status = $1, attempts = 0, next_attempt_at = datetime('now'), updated_at = datetime('now')
+		WHERE id = $2 AND status = $3 AND webhook_id IN (SELECT id FROM webhooks)
+	`
+
+	result, err := r.db.Exec(query, models.WebhookPending, id, models.WebhookDead)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeleteDeliveredBefore prunes delivered events older than before
+func (r *WebhookRepository) DeleteDeliveredBefore(before time.Time) (int64, error) {
+	query := `
+		DELETE FROM webhook_outbox
+		WHERE status = $1 AND updated_at < $2
+	`
+
+	result, err := r.db.Exec(query, models.WebhookDelivered, before.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
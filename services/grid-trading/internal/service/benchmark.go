@@ -0,0 +1,181 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// priceHistoryInterval is the minimum time between stored samples per symbol - triggers
+// can arrive every second, the benchmark only needs the price when a grid started
+const priceHistoryInterval = time.Minute
+
+// PriceHistoryInterface stores sampled prices
+type PriceHistoryInterface interface {
+	Record(symbol string, price decimal.Decimal, at time.Time) error
+	GetFirstSince(symbol string, since time.Time) (*models.PricePoint, error)
+}
+
+// SymbolBenchmark compares a symbol's grid with buying coins for its whole capital when
+// the grid was created and holding them. Both profits are in USDT on the same capital.
+type SymbolBenchmark struct {
+	Symbol             string          `json:"symbol"`
+	Since              string          `json:"since"` // First stored price after the grid was created
+	Days               decimal.Decimal `json:"days"`
+	CapitalUSDT        decimal.Decimal `json:"capital_usdt"` // Worst-case buy amounts of all levels (see CapitalEstimate)
+	StartPrice         decimal.Decimal `json:"start_price"`
+	Price              decimal.Decimal `json:"price"`
+	Stale              bool            `json:"stale"`
+	RealizedUSDT       decimal.Decimal `json:"realized_usdt"`
+	UnrealizedUSDT     decimal.Decimal `json:"unrealized_usdt"`
+	GridProfitUSDT     decimal.Decimal `json:"grid_profit_usdt"`
+	GridReturnPct      decimal.Decimal `json:"grid_return_pct"`
+	HoldProfitUSDT     decimal.Decimal `json:"hold_profit_usdt"` // After one buy fee (TRADING_FEE)
+	HoldReturnPct      decimal.Decimal `json:"hold_return_pct"`
+	OutperformanceUSDT decimal.Decimal `json:"outperformance_usdt"` // Grid minus hold
+}
+
+// Benchmark totals symbols with a fresh price. Symbols without stored prices since their
+// grid was created, or without any known price, can't be compared and are only listed.
+type Benchmark struct {
+	CapitalUSDT        decimal.Decimal   `json:"capital_usdt"`
+	GridProfitUSDT     decimal.Decimal   `json:"grid_profit_usdt"`
+	GridReturnPct      decimal.Decimal   `json:"grid_return_pct"`
+	HoldProfitUSDT     decimal.Decimal   `json:"hold_profit_usdt"`
+	HoldReturnPct      decimal.Decimal   `json:"hold_return_pct"`
+	OutperformanceUSDT decimal.Decimal   `json:"outperformance_usdt"`
+	OutperformancePct  decimal.Decimal   `json:"outperformance_pct"` // Grid return minus hold return, in points
+	MissingHistory     []string          `json:"missing_history,omitempty"`
+	StaleSymbols       []string          `json:"stale_symbols,omitempty"`
+	Symbols            []SymbolBenchmark `json:"symbols"`
+}
+
+// UsePriceHistory samples trigger prices into history for the buy-and-hold benchmark
+func (s *GridService) UsePriceHistory(history PriceHistoryInterface) {
+	s.history = history
+}
+
+// recordPrice stores at most one price per symbol per priceHistoryInterval
+func (s *GridService) recordPrice(symbol string, price decimal.Decimal, at time.Time) {
+	if s.history == nil {
+		return
+	}
+
+	s.lastPriceMu.Lock()
+	if at.Sub(s.historyRecordedAt[symbol]) < priceHistoryInterval {
+		s.lastPriceMu.Unlock()
+		return
+	}
+	s.historyRecordedAt[symbol] = at
+	s.lastPriceMu.Unlock()
+
+	if err := s.history.Record(symbol, price, at); err != nil {
+		log.Printf("WARNING: Failed to record %s price history: %v", symbol, err)
+	}
+}
+
+// GetBenchmark compares every grid with holding its capital in the coin since the grid was created
+func (s *GridService) GetBenchmark() (*Benchmark, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("price history is not recorded")
+	}
+
+	levels, err := s.repo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+
+	type grid struct {
+		createdAt time.Time
+		levels    []*models.GridLevel
+		capital   decimal.Decimal
+		heldCoin  decimal.Decimal
+		heldCost  decimal.Decimal
+	}
+	grids := make(map[string]*grid)
+	for _, level := range levels {
+		g, ok := grids[level.Symbol]
+		if !ok {
+			g = &grid{createdAt: level.CreatedAt}
+			grids[level.Symbol] = g
+		}
+		if level.CreatedAt.Before(g.createdAt) {
+			g.createdAt = level.CreatedAt
+		}
+		g.levels = append(g.levels, level)
+		if holdsCoin(level) {
+			g.heldCoin = g.heldCoin.Add(level.FilledAmount.Decimal)
+			g.heldCost = g.heldCost.Add(level.CycleAmount())
+		}
+	}
+
+	// Scaled grids can need more than their flat buy amounts, hold is compared on the worst case
+	for symbol, g := range grids {
+		g.capital = estimateCapital(symbol, g.levels).WorstCaseUSDT
+	}
+
+	hundred := decimal.NewFromInt(100)
+	feeFactor := decimal.NewFromInt(1).Sub(decimal.NewFromFloat(s.tradingFee).Div(hundred))
+
+	result := &Benchmark{Symbols: []SymbolBenchmark{}}
+	for symbol, g := range grids {
+		start, err := s.history.GetFirstSince(symbol, g.createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s price history: %w", symbol, err)
+		}
+		quote, _ := s.latestPrice(symbol)
+		if start == nil || quote == nil || !g.capital.IsPositive() {
+			result.MissingHistory = append(result.MissingHistory, symbol)
+			continue
+		}
+
+		// Grids older than the price history are compared from its first sample on
+		realized, err := s.txRepo.GetRealizedProfitSince(symbol, start.RecordedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s realized profit: %w", symbol, err)
+		}
+
+		b := SymbolBenchmark{
+			Symbol:         symbol,
+			Since:          start.RecordedAt.Format(time.RFC3339),
+			Days:           decimal.NewFromFloat(time.Since(start.RecordedAt).Hours() / 24).Round(1),
+			CapitalUSDT:    g.capital,
+			StartPrice:     start.Price,
+			Price:          quote.Price,
+			Stale:          s.isStale(quote),
+			RealizedUSDT:   realized,
+			UnrealizedUSDT: g.heldCoin.Mul(quote.Price).Sub(g.heldCost).Round(8),
+		}
+		b.GridProfitUSDT = b.RealizedUSDT.Add(b.UnrealizedUSDT)
+		b.HoldProfitUSDT = g.capital.Mul(feeFactor).Div(start.Price).Mul(quote.Price).Sub(g.capital).Round(8)
+		b.GridReturnPct = b.GridProfitUSDT.Div(g.capital).Mul(hundred).Round(2)
+		b.HoldReturnPct = b.HoldProfitUSDT.Div(g.capital).Mul(hundred).Round(2)
+		b.OutperformanceUSDT = b.GridProfitUSDT.Sub(b.HoldProfitUSDT)
+
+		if b.Stale {
+			result.StaleSymbols = append(result.StaleSymbols, symbol)
+		} else {
+			result.CapitalUSDT = result.CapitalUSDT.Add(b.CapitalUSDT)
+			result.GridProfitUSDT = result.GridProfitUSDT.Add(b.GridProfitUSDT)
+			result.HoldProfitUSDT = result.HoldProfitUSDT.Add(b.HoldProfitUSDT)
+		}
+		result.Symbols = append(result.Symbols, b)
+	}
+
+	sort.Slice(result.Symbols, func(i, j int) bool { return result.Symbols[i].Symbol < result.Symbols[j].Symbol })
+	sort.Strings(result.MissingHistory)
+	sort.Strings(result.StaleSymbols)
+
+	result.OutperformanceUSDT = result.GridProfitUSDT.Sub(result.HoldProfitUSDT)
+	if result.CapitalUSDT.IsPositive() {
+		result.GridReturnPct = result.GridProfitUSDT.Div(result.CapitalUSDT).Mul(hundred).Round(2)
+		result.HoldReturnPct = result.HoldProfitUSDT.Div(result.CapitalUSDT).Mul(hundred).Round(2)
+		result.OutperformancePct = result.GridReturnPct.Sub(result.HoldReturnPct)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// ErrBulkRejected wraps bulk level requests that can't be applied as asked (no symbol, unknown action)
+var ErrBulkRejected = errors.New("bulk operation rejected")
+
+// Actions of POST /levels/bulk
+const (
+	BulkEnable  = "enable"
+	BulkDisable = "disable"
+	BulkRecover = "recover" // ERROR levels back to READY, or HOLDING when they still hold coins
+)
+
+// BulkLevelsRequest applies one action to every level of a symbol matching the filter
+type BulkLevelsRequest struct {
+	Symbol   string              `json:"symbol"`
+	Account  string              `json:"account,omitempty"`   // Only levels of this sub-account (empty = all accounts)
+	MinPrice decimal.NullDecimal `json:"min_price,omitempty"` // Buy price at or above
+	MaxPrice decimal.NullDecimal `json:"max_price,omitempty"` // Buy price at or below
+	State    models.GridState    `json:"state,omitempty"`
+	Action   string              `json:"action"`
+	DryRun   bool                `json:"dry_run"`
+}
+
+// BulkLevelsResult lists the levels a bulk action changed (or would change on a dry run)
+type BulkLevelsResult struct {
+	Symbol   string `json:"symbol"`
+	Action   string `json:"action"`
+	DryRun   bool   `json:"dry_run"`
+	Matched  int    `json:"matched"` // Levels matching the filter
+	Changed  int    `json:"changed"` // Matched levels not already in the requested setting
+	LevelIDs []int  `json:"level_ids"`
+}
+
+// BulkUpdateLevels enables, disables or recovers the matching levels of a symbol in one call.
+// Levels already in the requested setting are left alone; recover only touches ERROR levels.
+// A cancelled run returns the levels changed so far.
+func (s *GridService) BulkUpdateLevels(ctx context.Context, req BulkLevelsRequest) (*BulkLevelsResult, error) {
+	if req.Symbol == "" {
+		return nil, fmt.Errorf("%w: symbol is required", ErrBulkRejected)
+	}
+	switch req.Action {
+	case BulkEnable, BulkDisable, BulkRecover:
+	default:
+		return nil, fmt.Errorf("%w: action must be %s, %s or %s", ErrBulkRejected, BulkEnable, BulkDisable, BulkRecover)
+	}
+	if req.State != "" && !knownState(req.State) {
+		return nil, fmt.Errorf("%w: unknown state %q", ErrBulkRejected, req.State)
+	}
+
+	levels, err := s.repo.GetBySymbol(req.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+
+	result := &BulkLevelsResult{Symbol: req.Symbol, Action: req.Action, DryRun: req.DryRun, LevelIDs: []int{}}
+	for i, level := range levels {
+		if err := JobStep(ctx, i, len(levels), fmt.Sprintf("level %d", level.ID)); err != nil {
+			return result, err
+		}
+		if !req.matches(level) {
+			continue
+		}
+		result.Matched++
+
+		var changed bool
+		switch req.Action {
+		case BulkEnable, BulkDisable:
+			enable := req.Action == BulkEnable
+			changed = level.Enabled != enable
+			if changed && !req.DryRun {
+				changed, err = s.repo.SetEnabled(level.ID, enable)
+			}
+		case BulkRecover:
+			changed = level.State == models.StateError
+			if changed && !req.DryRun {
+				changed, err = s.repo.Recover(level.ID, recoveredState(level))
+			}
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to %s level %d: %w", req.Action, level.ID, err)
+		}
+		if changed {
+			result.Changed++
+			result.LevelIDs = append(result.LevelIDs, level.ID)
+		}
+	}
+
+	if !req.DryRun {
+		log.Printf("INFO: Bulk %s of %s levels: %d matched, %d changed", req.Action, req.Symbol, result.Matched, result.Changed)
+	}
+	return result, nil
+}
+
+func (req BulkLevelsRequest) matches(level *models.GridLevel) bool {
+	if req.Account != "" && level.Account != req.Account {
+		return false
+	}
+	if req.MinPrice.Valid && level.BuyPrice.LessThan(req.MinPrice.Decimal) {
+		return false
+	}
+	if req.MaxPrice.Valid && level.BuyPrice.GreaterThan(req.MaxPrice.Decimal) {
+		return false
+	}
+	return req.State == "" || level.State == req.State
+}
+
+func knownState(state models.GridState) bool {
+	for _, known := range reportStateOrder {
+		if state == known {
+			return true
+		}
+	}
+	return false
+}
+
+// recoveredState is where an ERROR level resumes: a level that still holds bought coins
+// goes back to selling them, any other starts a new cycle
+func recoveredState(level *models.GridLevel) models.GridState {
+	if level.FilledAmount.Valid && level.FilledAmount.Decimal.IsPositive() {
+		return models.StateHolding
+	}
+	return models.StateReady
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// ErrCalendarEventRejected wraps calendar imports with an invalid event
+var ErrCalendarEventRejected = errors.New("calendar event rejected")
+
+// Where calendar events came from
+const (
+	CalendarSourceAPI  = "api"
+	CalendarSourceFile = "file"
+)
+
+// CalendarRepositoryInterface stores the trading calendar
+type CalendarRepositoryInterface interface {
+	Upsert(event *models.CalendarEvent) error
+	GetAll() ([]*models.CalendarEvent, error)
+	GetPending(endedAfter time.Time) ([]*models.CalendarEvent, error)
+	MarkPaused(id int, at time.Time) (bool, error)
+	MarkResumed(id int, at time.Time) (bool, error)
+	Delete(id int) (bool, error)
+}
+
+// CalendarSettings is which symbols calendar events pause and for how long around them
+type CalendarSettings struct {
+	Symbols     []string      // Paused by events without their own symbols (empty = every symbol)
+	PauseBefore time.Duration // Buys stop this long before an event starts
+	ResumeAfter time.Duration // and start again this long after it ends
+}
+
+// CalendarEventRequest is one event of a calendar import; ends_at defaults to starts_at
+type CalendarEventRequest struct {
+	Name     string     `json:"name"`
+	StartsAt time.Time  `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	Symbols  []string   `json:"symbols,omitempty"`
+}
+
+// CalendarImport is a batch of events (POST /calendar/events, CALENDAR_FILE)
+type CalendarImport struct {
+	Events []CalendarEventRequest `json:"events"`
+}
+
+// CalendarWindow is a calendar event with the buy pause around it
+type CalendarWindow struct {
+	*models.CalendarEvent
+	PauseFrom     time.Time `json:"pause_from"`
+	ResumeAt      time.Time `json:"resume_at"`
+	PausedSymbols []string  `json:"paused_symbols"` // Empty = every symbol
+	Active        bool      `json:"active"`         // Buys of its symbols are paused now
+}
+
+// UseCalendar pauses buys around the events of a trading calendar and loads the upcoming ones
+func (s *GridService) UseCalendar(calendar CalendarRepositoryInterface, settings CalendarSettings) error {
+	s.calendar = calendar
+	s.calendarSettings = settings
+	return s.loadCalendar(time.Now())
+}
+
+// loadCalendar keeps the events whose window isn't over yet, or whose pause isn't lifted yet,
+// in memory for the triggers
+func (s *GridService) loadCalendar(now time.Time) error {
+	events, err := s.calendar.GetPending(now.Add(-s.calendarSettings.ResumeAfter))
+	if err != nil {
+		return fmt.Errorf("failed to get calendar events: %w", err)
+	}
+
+	s.calendarMu.Lock()
+	s.calendarEvents = events
+	s.calendarMu.Unlock()
+	return nil
+}
+
+// calendarWindow is an event's buy pause as of now
+func (s *GridService) calendarWindow(event *models.CalendarEvent, now time.Time) CalendarWindow {
+	window := CalendarWindow{
+		CalendarEvent: event,
+		PauseFrom:     event.StartsAt.Add(-s.calendarSettings.PauseBefore),
+		ResumeAt:      event.EndsAt.Add(s.calendarSettings.ResumeAfter),
+		PausedSymbols: event.Symbols,
+	}
+	if len(window.PausedSymbols) == 0 {
+		window.PausedSymbols = s.calendarSettings.Symbols
+	}
+	if window.PausedSymbols == nil {
+		window.PausedSymbols = []string{}
+	}
+	window.Active = !now.Before(window.PauseFrom) && now.Before(window.ResumeAt)
+	return window
+}
+
+// pauses reports whether the window pauses a symbol's buys
+func (w CalendarWindow) pauses(symbol string) bool {
+	if len(w.PausedSymbols) == 0 {
+		return true
+	}
+	for _, paused := range w.PausedSymbols {
+		if paused == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// calendarPause returns why a symbol's buys are paused by the calendar now, empty if they aren't
+func (s *GridService) calendarPause(symbol string) string {
+	if s.calendar == nil {
+		return ""
+	}
+
+	now := time.Now()
+	s.calendarMu.RLock()
+	defer s.calendarMu.RUnlock()
+	for _, event := range s.calendarEvents {
+		if window := s.calendarWindow(event, now); window.Active && window.pauses(symbol) {
+			return fmt.Sprintf("the %s calendar window (until %s)", event.Name, window.ResumeAt.UTC().Format(time.RFC3339))
+		}
+	}
+	return ""
+}
+
+// ImportCalendar stores a batch of events, updating those imported before (same name and
+// start). The whole batch is rejected if one event is invalid.
+func (s *GridService) ImportCalendar(req CalendarImport, source string) ([]CalendarWindow, error) {
+	if s.calendar == nil {
+		return nil, fmt.Errorf("the trading calendar is not enabled")
+	}
+	if len(req.Events) == 0 {
+		return nil, fmt.Errorf("%w: no events", ErrCalendarEventRejected)
+	}
+
+	events := make([]*models.CalendarEvent, 0, len(req.Events))
+	for i, e := range req.Events {
+		event := &models.CalendarEvent{
+			Name:     strings.TrimSpace(e.Name),
+			StartsAt: e.StartsAt.UTC().Truncate(time.Second),
+			EndsAt:   e.StartsAt.UTC().Truncate(time.Second),
+			Symbols:  []string{},
+			Source:   source,
+		}
+		if e.EndsAt != nil {
+			event.EndsAt = e.EndsAt.UTC().Truncate(time.Second)
+		}
+		for _, symbol := range e.Symbols {
+			if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+				event.Symbols = append(event.Symbols, symbol)
+			}
+		}
+
+		switch {
+		case event.Name == "":
+			return nil, fmt.Errorf("%w: event %d has no name", ErrCalendarEventRejected, i+1)
+		case event.StartsAt.IsZero():
+			return nil, fmt.Errorf("%w: %s has no starts_at", ErrCalendarEventRejected, event.Name)
+		case event.EndsAt.Before(event.StartsAt):
+			return nil, fmt.Errorf("%w: %s ends before it starts", ErrCalendarEventRejected, event.Name)
+		}
+		events = append(events, event)
+	}
+
+	for _, event := range events {
+		if err := s.calendar.Upsert(event); err != nil {
+			return nil, fmt.Errorf("failed to store calendar event %s: %w", event.Name, err)
+		}
+	}
+	slog.Info("Imported calendar events", "events", len(events), "source", source)
+
+	// An event already under way pauses at once
+	if err := s.CheckCalendar(); err != nil {
+		return nil, err
+	}
+	return s.GetCalendar(false)
+}
+
+// ImportCalendarFile imports the events of a JSON file shaped like the POST /calendar/events body
+func (s *GridService) ImportCalendarFile(path string) ([]CalendarWindow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar file: %w", err)
+	}
+
+	var req CalendarImport
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("%w: invalid calendar file %s: %v", ErrCalendarEventRejected, path, err)
+	}
+	return s.ImportCalendar(req, CalendarSourceFile)
+}
+
+// GetCalendar returns the events whose window isn't over yet, or every event with all
+func (s *GridService) GetCalendar(all bool) ([]CalendarWindow, error) {
+	if s.calendar == nil {
+		return nil, fmt.Errorf("the trading calendar is not enabled")
+	}
+
+	now := time.Now()
+	var events []*models.CalendarEvent
+	var err error
+	if all {
+		events, err = s.calendar.GetAll()
+	} else {
+		events, err = s.calendar.GetPending(now.Add(-s.calendarSettings.ResumeAfter))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar events: %w", err)
+	}
+
+	windows := make([]CalendarWindow, 0, len(events))
+	for _, event := range events {
+		windows = append(windows, s.calendarWindow(event, now))
+	}
+	return windows, nil
+}
+
+// calendarPauses returns the calendar windows pausing buys now, for /status
+func (s *GridService) calendarPauses() []CalendarWindow {
+	if s.calendar == nil {
+		return nil
+	}
+
+	now := time.Now()
+	s.calendarMu.RLock()
+	defer s.calendarMu.RUnlock()
+	var windows []CalendarWindow
+	for _, event := range s.calendarEvents {
+		if window := s.calendarWindow(event, now); window.Active {
+			windows = append(windows, window)
+		}
+	}
+	return windows
+}
+
+// DeleteCalendarEvent removes an event; a pause it holds ends with it. Returns false if there
+// is no such event.
+func (s *GridService) DeleteCalendarEvent(id int) (bool, error) {
+	if s.calendar == nil {
+		return false, fmt.Errorf("the trading calendar is not enabled")
+	}
+
+	deleted, err := s.calendar.Delete(id)
+	if err != nil || !deleted {
+		return deleted, err
+	}
+
+	var removed *models.CalendarEvent
+	s.calendarMu.Lock()
+	for i, event := range s.calendarEvents {
+		if event.ID == id {
+			removed = event
+			s.calendarEvents = append(s.calendarEvents[:i:i], s.calendarEvents[i+1:]...)
+			break
+		}
+	}
+	s.calendarMu.Unlock()
+
+	if removed != nil && removed.PausedAt != nil && removed.ResumedAt == nil {
+		s.emitCalendarResume(s.calendarWindow(removed, time.Now()), "deleted")
+	}
+	return true, nil
+}
+
+// CheckCalendar reloads the upcoming events and records the windows that began or ended since
+// the last check: calendar_pause when one starts pausing buys, calendar_resume once it is over.
+// Each is recorded by one instance only; a window that passed entirely while grid-trading was
+// down is neither.
+func (s *GridService) CheckCalendar() error {
+	if s.calendar == nil {
+		return nil
+	}
+	s.calendarCheckMu.Lock()
+	defer s.calendarCheckMu.Unlock()
+
+	now := time.Now()
+	if err := s.loadCalendar(now); err != nil {
+		return err
+	}
+
+	s.calendarMu.RLock()
+	events := s.calendarEvents
+	s.calendarMu.RUnlock()
+
+	changed := false
+	for _, event := range events {
+		window := s.calendarWindow(event, now)
+		switch {
+		case window.Active && event.PausedAt == nil:
+			marked, err := s.calendar.MarkPaused(event.ID, now)
+			if err != nil {
+				return fmt.Errorf("failed to record pause of calendar event %d: %w", event.ID, err)
+			}
+			changed = true
+			if !marked {
+				continue
+			}
+			slog.Warn("Calendar event pauses buys", "event", event.Name, "symbols", calendarSymbols(window),
+				"resume_at", window.ResumeAt.UTC().Format(time.RFC3339))
+			s.emit(contracts.EventCalendarPause, "",
+				fmt.Sprintf("%s: buys of %s paused until %s", event.Name, calendarSymbols(window), window.ResumeAt.UTC().Format(time.RFC3339)),
+				calendarFields(window))
+
+		case !window.Active && !now.Before(window.ResumeAt) && event.PausedAt != nil && event.ResumedAt == nil:
+			marked, err := s.calendar.MarkResumed(event.ID, now)
+			if err != nil {
+				return fmt.Errorf("failed to record resume after calendar event %d: %w", event.ID, err)
+			}
+			changed = true
+			if marked {
+				s.emitCalendarResume(window, "window over")
+			}
+		}
+	}
+
+	if changed {
+		return s.loadCalendar(now)
+	}
+	return nil
+}
+
+func (s *GridService) emitCalendarResume(window CalendarWindow, why string) {
+	slog.Info("Calendar event over, resuming buys", "event", window.Name, "reason", why, "symbols", calendarSymbols(window))
+	fields := calendarFields(window)
+	fields["reason"] = why
+	s.emit(contracts.EventCalendarResume, "",
+		fmt.Sprintf("%s %s: buys of %s resumed", window.Name, why, calendarSymbols(window)), fields)
+}
+
+// calendarSymbols names the symbols a window pauses
+func calendarSymbols(window CalendarWindow) string {
+	if len(window.PausedSymbols) == 0 {
+		return "all symbols"
+	}
+	return strings.Join(window.PausedSymbols, ", ")
+}
+
+func calendarFields(window CalendarWindow) map[string]string {
+	return map[string]string{
+		"event_id":   fmt.Sprint(window.ID),
+		"event":      window.Name,
+		"symbols":    calendarSymbols(window),
+		"starts_at":  window.StartsAt.UTC().Format(time.RFC3339),
+		"ends_at":    window.EndsAt.UTC().Format(time.RFC3339),
+		"pause_from": window.PauseFrom.UTC().Format(time.RFC3339),
+		"resume_at":  window.ResumeAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// ErrCapitalFlowRejected wraps deposits and withdrawals with invalid values
+var ErrCapitalFlowRejected = errors.New("capital flow rejected")
+
+// CapitalFlowRepositoryInterface stores the USDT put into and taken out of trading
+type CapitalFlowRepositoryInterface interface {
+	Create(flow *models.CapitalFlow) error
+	GetAll() ([]*models.CapitalFlow, error)
+	Delete(id int) (bool, error)
+}
+
+// CapitalFlowRequest records a deposit or withdrawal; occurred_at defaults to now
+type CapitalFlowRequest struct {
+	Kind       models.CapitalFlowKind `json:"kind"`
+	AmountUSDT decimal.Decimal        `json:"amount_usdt"`
+	Source     string                 `json:"source"`
+	Note       string                 `json:"note"`
+	OccurredAt *time.Time             `json:"occurred_at"`
+}
+
+// CapitalReturn is the profit - realized all time plus unrealized - against the capital deposited.
+// ROIPct divides it by the net deposit; TimeWeightedROIPct by the capital weighted by how long each
+// flow was invested (Modified Dietz), so a deposit made yesterday doesn't dilute a month's return.
+// Both are null while the capital they divide by isn't positive.
+type CapitalReturn struct {
+	DepositedUSDT      decimal.Decimal     `json:"deposited_usdt"`
+	WithdrawnUSDT      decimal.Decimal     `json:"withdrawn_usdt"`
+	NetDepositedUSDT   decimal.Decimal     `json:"net_deposited_usdt"`
+	ProfitUSDT         decimal.Decimal     `json:"profit_usdt"`
+	ROIPct             decimal.NullDecimal `json:"roi_pct"`
+	TimeWeightedROIPct decimal.NullDecimal `json:"time_weighted_roi_pct"`
+	Since              time.Time           `json:"since"` // First flow
+	Sources            []CapitalSource     `json:"sources"`
+}
+
+// CapitalSource totals the flows of one source tag
+type CapitalSource struct {
+	Source        string          `json:"source"`
+	DepositedUSDT decimal.Decimal `json:"deposited_usdt"`
+	WithdrawnUSDT decimal.Decimal `json:"withdrawn_usdt"`
+	NetUSDT       decimal.Decimal `json:"net_usdt"`
+}
+
+// UseCapitalFlows tracks deposits and withdrawals, adding ROI to status and reports
+func (s *GridService) UseCapitalFlows(flows CapitalFlowRepositoryInterface) {
+	s.capital = flows
+}
+
+// RecordCapitalFlow stores a deposit or withdrawal
+func (s *GridService) RecordCapitalFlow(req CapitalFlowRequest) (*models.CapitalFlow, error) {
+	if s.capital == nil {
+		return nil, fmt.Errorf("capital tracking is not enabled")
+	}
+	if req.Kind != models.CapitalDeposit && req.Kind != models.CapitalWithdrawal {
+		return nil, fmt.Errorf("%w: kind must be deposit or withdrawal", ErrCapitalFlowRejected)
+	}
+	if !req.AmountUSDT.IsPositive() {
+		return nil, fmt.Errorf("%w: amount_usdt must be positive", ErrCapitalFlowRejected)
+	}
+
+	now := time.Now().UTC()
+	flow := &models.CapitalFlow{
+		Kind:       req.Kind,
+		AmountUSDT: req.AmountUSDT,
+		Source:     strings.TrimSpace(req.Source),
+		Note:       strings.TrimSpace(req.Note),
+		OccurredAt: now,
+		CreatedAt:  now,
+	}
+	if req.OccurredAt != nil {
+		if req.OccurredAt.After(now) {
+			return nil, fmt.Errorf("%w: occurred_at is in the future", ErrCapitalFlowRejected)
+		}
+		flow.OccurredAt = req.OccurredAt.UTC().Truncate(time.Second)
+	}
+
+	if err := s.capital.Create(flow); err != nil {
+		return nil, err
+	}
+	log.Printf("INFO: Capital %s %d recorded: %s USDT, source %q, at %s",
+		flow.Kind, flow.ID, flow.AmountUSDT, flow.Source, flow.OccurredAt.Format(time.RFC3339))
+	return flow, nil
+}
+
+// GetCapitalFlows returns the deposits and withdrawals, oldest first, optionally of one source
+func (s *GridService) GetCapitalFlows(source string) ([]*models.CapitalFlow, error) {
+	if s.capital == nil {
+		return nil, fmt.Errorf("capital tracking is not enabled")
+	}
+	flows, err := s.capital.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*models.CapitalFlow{}
+	for _, flow := range flows {
+		if source == "" || flow.Source == source {
+			result = append(result, flow)
+		}
+	}
+	return result, nil
+}
+
+// DeleteCapitalFlow removes a flow recorded by mistake. Returns false if there is no such flow.
+func (s *GridService) DeleteCapitalFlow(id int) (bool, error) {
+	if s.capital == nil {
+		return false, fmt.Errorf("capital tracking is not enabled")
+	}
+	deleted, err := s.capital.Delete(id)
+	if deleted {
+		log.Printf("INFO: Capital flow %d deleted", id)
+	}
+	return deleted, err
+}
+
+// GetCapitalReturn measures the profit so far against the capital deposited; nil while no flow is recorded
+func (s *GridService) GetCapitalReturn() (*CapitalReturn, error) {
+	if s.capital == nil {
+		return nil, nil
+	}
+
+	now := time.Now().UTC()
+	_, _, _, profitAllTime, err := s.txRepo.GetProfitStats(reportPeriods(s.reportLoc, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profit stats: %w", err)
+	}
+	unrealized, err := s.GetUnrealizedPnL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unrealized pnl: %w", err)
+	}
+	return s.capitalReturn(profitAllTime.Add(unrealized.UnrealizedUSDT), now)
+}
+
+// capitalReturn relates profit to the recorded flows; nil without a capital store or flows
+func (s *GridService) capitalReturn(profit decimal.Decimal, now time.Time) (*CapitalReturn, error) {
+	if s.capital == nil {
+		return nil, nil
+	}
+	flows, err := s.capital.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get capital flows: %w", err)
+	}
+	if len(flows) == 0 {
+		return nil, nil
+	}
+
+	result := &CapitalReturn{ProfitUSDT: profit, Since: flows[0].OccurredAt, Sources: []CapitalSource{}}
+	bySource := make(map[string]*CapitalSource)
+	for _, flow := range flows {
+		source, ok := bySource[flow.Source]
+		if !ok {
+			source = &CapitalSource{Source: flow.Source}
+			bySource[flow.Source] = source
+		}
+		if flow.Kind == models.CapitalWithdrawal {
+			result.WithdrawnUSDT = result.WithdrawnUSDT.Add(flow.AmountUSDT)
+			source.WithdrawnUSDT = source.WithdrawnUSDT.Add(flow.AmountUSDT)
+		} else {
+			result.DepositedUSDT = result.DepositedUSDT.Add(flow.AmountUSDT)
+			source.DepositedUSDT = source.DepositedUSDT.Add(flow.AmountUSDT)
+		}
+		source.NetUSDT = source.NetUSDT.Add(flow.Signed())
+	}
+	result.NetDepositedUSDT = result.DepositedUSDT.Sub(result.WithdrawnUSDT)
+	for _, source := range bySource {
+		result.Sources = append(result.Sources, *source)
+	}
+	sort.Slice(result.Sources, func(i, j int) bool { return result.Sources[i].Source < result.Sources[j].Source })
+
+	hundred := decimal.NewFromInt(100)
+	if result.NetDepositedUSDT.IsPositive() {
+		result.ROIPct = decimal.NewNullDecimal(profit.Div(result.NetDepositedUSDT).Mul(hundred).Round(2))
+	}
+
+	// Modified Dietz: each flow counts for the share of the period it was invested
+	period := now.Sub(result.Since)
+	if period > 0 {
+		var weighted decimal.Decimal
+		for _, flow := range flows {
+			share := decimal.NewFromFloat(now.Sub(flow.OccurredAt).Seconds() / period.Seconds())
+			weighted = weighted.Add(flow.Signed().Mul(share))
+		}
+		if weighted.IsPositive() {
+			result.TimeWeightedROIPct = decimal.NewNullDecimal(profit.Div(weighted).Mul(hundred).Round(2))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// Grid invariants the consistency check enforces. A level breaking one would make grid-trading
+// sell nothing, poll an order that doesn't exist, or apply one fill to two levels.
+const (
+	RuleMissingFilledAmount = "missing_filled_amount" // HOLDING, PLACING_SELL or SELL_ACTIVE without a bought amount
+	RuleMissingOrderID      = "missing_order_id"      // BUY_ACTIVE/SELL_ACTIVE without the order it waits on
+	RuleSharedOrderID       = "shared_order_id"       // Two levels hold the same exchange order
+)
+
+// maxAlertViolations is how many violations an alert lists; the rest are on GET /consistency
+const maxAlertViolations = 10
+
+// ConsistencyViolation is one level breaking a grid invariant
+type ConsistencyViolation struct {
+	Rule    string `json:"rule"`
+	LevelID int    `json:"level_id"`
+	Symbol  string `json:"symbol"`
+	Account string `json:"account,omitempty"`
+	State   string `json:"state"`
+	OrderID string `json:"order_id,omitempty"` // missing_order_id: empty; shared_order_id: the shared order
+
+	// shared_order_id: the other levels holding the order
+	SharedWith []int `json:"shared_with,omitempty"`
+
+	Detail string `json:"detail"`
+}
+
+// ConsistencyReport is the result of checking every level against the grid invariants
+// (GET /consistency). It runs on startup, as part of the self-check, and on POST /consistency/check.
+type ConsistencyReport struct {
+	Consistent    bool                   `json:"consistent"`
+	LevelsChecked int                    `json:"levels_checked"`
+	Violations    []ConsistencyViolation `json:"violations"`
+	CheckedAt     time.Time              `json:"checked_at"`
+}
+
+// LevelIDs returns the distinct levels with a violation, in ascending order
+func (r *ConsistencyReport) LevelIDs() []int {
+	seen := make(map[int]bool)
+	var ids []int
+	for _, v := range r.Violations {
+		if !seen[v.LevelID] {
+			seen[v.LevelID] = true
+			ids = append(ids, v.LevelID)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// checkConsistency checks levels against the grid invariants. Order IDs are only unique per
+// account and symbol on the exchange, so those are part of what two levels must share.
+func checkConsistency(levels []*models.GridLevel) *ConsistencyReport {
+	report := &ConsistencyReport{
+		LevelsChecked: len(levels),
+		Violations:    []ConsistencyViolation{},
+		CheckedAt:     time.Now().UTC(),
+	}
+	violation := func(rule string, level *models.GridLevel, orderID, detail string) ConsistencyViolation {
+		return ConsistencyViolation{Rule: rule, LevelID: level.ID, Symbol: level.Symbol, Account: level.Account,
+			State: string(level.State), OrderID: orderID, Detail: detail}
+	}
+
+	type orderKey struct{ account, symbol, orderID string }
+	holders := make(map[orderKey][]*models.GridLevel)
+	var keys []orderKey
+
+	for _, level := range levels {
+		holds := level.FilledAmount.Valid && level.FilledAmount.Decimal.IsPositive()
+		switch level.State {
+		case models.StateHolding, models.StatePlacingSell, models.StateSellActive:
+			if !holds {
+				report.Violations = append(report.Violations, violation(RuleMissingFilledAmount, level, "",
+					fmt.Sprintf("%s without a filled amount - nothing to sell", level.State)))
+			}
+		}
+		if level.State == models.StateBuyActive && !level.BuyOrderID.Valid {
+			report.Violations = append(report.Violations, violation(RuleMissingOrderID, level, "", "BUY_ACTIVE without a buy order ID"))
+		}
+		if level.State == models.StateSellActive && !level.SellOrderID.Valid {
+			report.Violations = append(report.Violations, violation(RuleMissingOrderID, level, "", "SELL_ACTIVE without a sell order ID"))
+		}
+
+		for _, orderID := range []string{level.BuyOrderID.String, level.SellOrderID.String} {
+			if orderID == "" {
+				continue
+			}
+			key := orderKey{level.Account, level.Symbol, orderID}
+			if len(holders[key]) > 0 && holders[key][len(holders[key])-1] == level {
+				continue // Same order as buy and sell of one level - not shared with another
+			}
+			if holders[key] == nil {
+				keys = append(keys, key)
+			}
+			holders[key] = append(holders[key], level)
+		}
+	}
+
+	for _, key := range keys {
+		shared := holders[key]
+		if len(shared) < 2 {
+			continue
+		}
+		for _, level := range shared {
+			var others []int
+			for _, other := range shared {
+				if other != level {
+					others = append(others, other.ID)
+				}
+			}
+			v := violation(RuleSharedOrderID, level, key.orderID, fmt.Sprintf("order %s is also held by level(s) %s", key.orderID, joinInts(others)))
+			v.SharedWith = others
+			report.Violations = append(report.Violations, v)
+		}
+	}
+
+	report.Consistent = len(report.Violations) == 0
+	return report
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CheckConsistency checks every level against the grid invariants now, keeps the report for
+// GET /consistency and alerts when something is broken
+func (s *GridService) CheckConsistency() (*ConsistencyReport, error) {
+	levels, err := s.repo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read levels: %w", err)
+	}
+	report := s.storeConsistency(levels)
+	s.AnnounceInconsistencies()
+	return report, nil
+}
+
+// storeConsistency checks levels and keeps the report, logging each violation
+func (s *GridService) storeConsistency(levels []*models.GridLevel) *ConsistencyReport {
+	report := checkConsistency(levels)
+	for _, v := range report.Violations {
+		log.Printf("ERROR: Level %d (%s, %s) breaks %s: %s", v.LevelID, v.Symbol, v.State, v.Rule, v.Detail)
+	}
+	if report.Consistent {
+		log.Printf("INFO: Grid consistency check passed for %d levels", report.LevelsChecked)
+	}
+
+	s.safeModeMu.Lock()
+	s.consistency = report
+	s.safeModeMu.Unlock()
+	return report
+}
+
+// LastConsistencyCheck returns the latest consistency report, or nil if none ran
+func (s *GridService) LastConsistencyCheck() *ConsistencyReport {
+	s.safeModeMu.RLock()
+	defer s.safeModeMu.RUnlock()
+	return s.consistency
+}
+
+// AnnounceInconsistencies reports the violations of the latest consistency check to the
+// notifier and webhooks; call once the event sinks are set
+func (s *GridService) AnnounceInconsistencies() {
+	report := s.LastConsistencyCheck()
+	if report == nil || report.Consistent {
+		return
+	}
+
+	lines := make([]string, 0, maxAlertViolations+1)
+	for i, v := range report.Violations {
+		if i == maxAlertViolations {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(report.Violations)-maxAlertViolations))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("level %d (%s %s): %s", v.LevelID, v.Symbol, v.State, v.Detail))
+	}
+	levelIDs := report.LevelIDs()
+
+	s.emit(contracts.EventGridInconsistent, "", fmt.Sprintf("%d grid invariant violations on levels %s", len(report.Violations), joinInts(levelIDs)),
+		map[string]string{
+			"violations":     strconv.Itoa(len(report.Violations)),
+			"levels_checked": strconv.Itoa(report.LevelsChecked),
+			"level_ids":      joinInts(levelIDs),
+			"details":        strings.Join(lines, "\n"),
+		})
+}
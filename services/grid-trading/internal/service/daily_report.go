@@ -0,0 +1,159 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// DailyReportRepositoryInterface stores end-of-day reports
+type DailyReportRepositoryInterface interface {
+	Save(report *models.DailyReport) error
+	GetByDate(date string) (*models.DailyReport, error)
+	GetLatestBefore(date string) (*models.DailyReport, error)
+}
+
+// reportStateOrder lists level states in state machine order for the report message
+var reportStateOrder = []models.GridState{
+	models.StateReady, models.StatePlacingBuy, models.StateBuyActive, models.StateHolding,
+	models.StatePlacingSell, models.StateSellActive, models.StateError,
+}
+
+// UseDailyReports stores daily reports so they can be read back by date
+func (s *GridService) UseDailyReports(reports DailyReportRepositoryInterface) {
+	s.reports = reports
+}
+
+// SendDailyReport composes today's report (in the reporting timezone), stores it and sends it
+// to the notifier. Running it again the same day replaces the stored report.
+func (s *GridService) SendDailyReport() (*models.DailyReport, error) {
+	if s.reports == nil {
+		return nil, fmt.Errorf("daily reports are not enabled")
+	}
+
+	now := time.Now().UTC()
+	periods := reportPeriods(s.reportLoc, now)
+	date := periods.DayStart.Format("2006-01-02")
+
+	stats, err := s.txRepo.GetDayStats(periods.DayStart, periods.DayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get day stats: %w", err)
+	}
+
+	levels, err := s.repo.GetStateCounts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get level states: %w", err)
+	}
+
+	_, _, _, profitAllTime, err := s.txRepo.GetProfitStats(periods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profit stats: %w", err)
+	}
+
+	unrealized, err := s.GetUnrealizedPnL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unrealized pnl: %w", err)
+	}
+
+	report := &models.DailyReport{
+		Date:           date,
+		DayStats:       *stats,
+		Levels:         levels,
+		UnrealizedUSDT: unrealized.UnrealizedUSDT,
+		ProfitAllTime:  profitAllTime,
+		EquityUSDT:     profitAllTime.Add(unrealized.UnrealizedUSDT),
+		CreatedAt:      now,
+	}
+
+	previous, err := s.reports.GetLatestBefore(date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous report: %w", err)
+	}
+	if previous != nil {
+		report.EquityChange.Decimal = report.EquityUSDT.Sub(previous.EquityUSDT)
+		report.EquityChange.Valid = true
+	}
+
+	capital, err := s.capitalReturn(report.EquityUSDT, now)
+	if err != nil {
+		return nil, err
+	}
+	if capital != nil {
+		report.NetDeposited = decimal.NewNullDecimal(capital.NetDepositedUSDT)
+		report.ROIPct = capital.ROIPct
+		report.TimeWeightedROIPct = capital.TimeWeightedROIPct
+	}
+	report.Converted = s.convertedReport(report)
+
+	if err := s.reports.Save(report); err != nil {
+		return nil, err
+	}
+
+	s.emitDailyReport(report)
+	return report, nil
+}
+
+// GetDailyReport returns the stored report of a date (YYYY-MM-DD), nil if there is none
+func (s *GridService) GetDailyReport(date string) (*models.DailyReport, error) {
+	if s.reports == nil {
+		return nil, fmt.Errorf("daily reports are not enabled")
+	}
+	return s.reports.GetByDate(date)
+}
+
+func (s *GridService) emitDailyReport(report *models.DailyReport) {
+	var levels []string
+	for _, state := range reportStateOrder {
+		if count := report.Levels[string(state)]; count > 0 {
+			levels = append(levels, fmt.Sprintf("%s %d", state, count))
+		}
+	}
+
+	equityChange := "n/a"
+	if report.EquityChange.Valid {
+		equityChange = report.EquityChange.Decimal.String()
+	}
+
+	fields := map[string]string{
+		"date":               report.Date,
+		"buys":               strconv.Itoa(report.Buys),
+		"sells":              strconv.Itoa(report.Sells),
+		"errors":             strconv.Itoa(report.Errors),
+		"bought_usdt":        report.BoughtUSDT.String(),
+		"sold_usdt":          report.SoldUSDT.String(),
+		"profit_usdt":        report.ProfitUSDT.String(),
+		"fees_usdt":          report.FeesUSDT.String(),
+		"levels":             strings.Join(levels, ", "),
+		"unrealized_usdt":    report.UnrealizedUSDT.String(),
+		"equity_usdt":        report.EquityUSDT.String(),
+		"equity_change_usdt": equityChange,
+	}
+	message := fmt.Sprintf("%d buys, %d sells, %d errors, profit %s USDT, fees %s USDT, equity %s USDT (change %s)",
+		report.Buys, report.Sells, report.Errors, report.ProfitUSDT, report.FeesUSDT, report.EquityUSDT, equityChange)
+
+	if report.NetDeposited.Valid {
+		fields["net_deposited_usdt"] = report.NetDeposited.Decimal.String()
+	}
+	if report.ROIPct.Valid {
+		fields["roi_pct"] = report.ROIPct.Decimal.String()
+		message += fmt.Sprintf(", ROI %s%%", report.ROIPct.Decimal)
+	}
+	if report.TimeWeightedROIPct.Valid {
+		fields["time_weighted_roi_pct"] = report.TimeWeightedROIPct.Decimal.String()
+	}
+	if converted := report.Converted; converted != nil {
+		fields["currency"] = converted.Currency
+		fields["fx_rate"] = converted.FXRate.String()
+		fields["profit_converted"] = converted.Profit.String()
+		fields["fees_converted"] = converted.Fees.String()
+		fields["equity_converted"] = converted.Equity.String()
+		message += fmt.Sprintf(", profit %s %s", converted.Profit, converted.Currency)
+	}
+
+	s.emit(contracts.EventDailyReport, "", message, fields)
+}
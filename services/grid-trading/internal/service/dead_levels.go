@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// DeadLevelPolicy decides which levels count as dead. Either age can be off (0); with both off
+// no level is dead.
+type DeadLevelPolicy struct {
+	DisabledDays int  `json:"disabled_days"` // Disabled levels unchanged this long
+	IdleDays     int  `json:"idle_days"`     // Enabled READY levels whose buy hasn't triggered this long
+	CancelOrders bool `json:"cancel_orders"` // Cancel the open buy of a dead level to archive it
+}
+
+// Why a level is dead
+const (
+	DeadLevelDisabled = "disabled"
+	DeadLevelIdle     = "idle"
+)
+
+// Why a dead level can't be archived
+const (
+	DeadLevelBuyOpen    = "buy_order_open" // Archived once its buy is cancelled (cancel_orders)
+	DeadLevelHoldsCoins = "holds_coins"    // Never archived by the cleanup: sell or tear down the grid
+	DeadLevelPlacing    = "placing"        // An order is being placed right now
+	DeadLevelErrorState = "error_state"    // Recover it (POST /levels/bulk) or tear down the grid
+)
+
+const deadLevelDay = 24 * time.Hour
+
+// DeadLevel is a level the cleanup would archive, or can't yet
+type DeadLevel struct {
+	LevelID   int              `json:"level_id"`
+	Symbol    string           `json:"symbol"`
+	Account   string           `json:"account,omitempty"`
+	State     models.GridState `json:"state"`
+	Reason    string           `json:"reason"` // disabled | idle
+	Since     time.Time        `json:"since"`  // Last change (disabled) or state change (idle)
+	AgeDays   int              `json:"age_days"`
+	BlockedBy string           `json:"blocked_by,omitempty"` // Empty = archivable now
+}
+
+// DeadLevelReport lists the dead levels and, after a cleanup, what it did
+type DeadLevelReport struct {
+	Policy          DeadLevelPolicy `json:"policy"`
+	Levels          []DeadLevel     `json:"levels"`
+	Archived        []int           `json:"archived"`
+	CancelledOrders []string        `json:"cancelled_orders,omitempty"`
+	FailedOrders    []string        `json:"failed_orders,omitempty"`
+}
+
+// SetDeadLevelPolicy sets which levels FindDeadLevels and CollectDeadLevels treat as dead
+func (s *GridService) SetDeadLevelPolicy(policy DeadLevelPolicy) {
+	s.deadLevels = policy
+}
+
+// DeadLevelPolicy returns the policy in effect
+func (s *GridService) DeadLevelPolicy() DeadLevelPolicy {
+	return s.deadLevels
+}
+
+// FindDeadLevels lists the dead levels without touching them
+func (s *GridService) FindDeadLevels() (*DeadLevelReport, error) {
+	levels, err := s.repo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+	return &DeadLevelReport{Policy: s.deadLevels, Levels: s.deadLevelsOf(levels, time.Now()), Archived: []int{}}, nil
+}
+
+// CollectDeadLevels archives the dead levels that are empty - READY without coins. With
+// cancelOrders it first cancels the open buy of a dead BUY_ACTIVE level; the cancel moves it
+// back to READY and it is archived right after, or on the next run if the cancel notification
+// is late. Levels holding coins are never archived, their coins stay with the level. A cancelled
+// cleanup returns what it archived so far.
+func (s *GridService) CollectDeadLevels(ctx context.Context, cancelOrders bool) (*DeadLevelReport, error) {
+	report, err := s.FindDeadLevels()
+	if err != nil {
+		return nil, err
+	}
+	report.Policy.CancelOrders = cancelOrders
+
+	for i, dead := range report.Levels {
+		if err := JobStep(ctx, i, len(report.Levels), fmt.Sprintf("level %d", dead.LevelID)); err != nil {
+			return report, err
+		}
+		if dead.BlockedBy == DeadLevelBuyOpen && cancelOrders {
+			level, err := s.repo.GetByID(dead.LevelID)
+			if err != nil || level == nil || !level.BuyOrderID.Valid {
+				continue
+			}
+			if err := s.assurance.CancelOrder(level.Account, level.Symbol, level.BuyOrderID.String); err != nil {
+				log.Printf("ERROR: Failed to cancel buy order %s of dead level %d: %v", level.BuyOrderID.String, level.ID, err)
+				report.FailedOrders = append(report.FailedOrders, level.BuyOrderID.String)
+				continue
+			}
+			report.CancelledOrders = append(report.CancelledOrders, level.BuyOrderID.String)
+		} else if dead.BlockedBy != "" {
+			continue
+		}
+
+		// ArchiveLevel only moves a level that is still READY without coins
+		archived, err := s.repo.ArchiveLevel(dead.LevelID)
+		if err != nil {
+			return report, fmt.Errorf("failed to archive level %d: %w", dead.LevelID, err)
+		}
+		if !archived {
+			continue
+		}
+		report.Archived = append(report.Archived, dead.LevelID)
+	}
+
+	log.Printf("INFO: Dead-level cleanup archived %d of %d dead levels, cancelled %d orders (%d failed)",
+		len(report.Archived), len(report.Levels), len(report.CancelledOrders), len(report.FailedOrders))
+	return report, nil
+}
+
+// deadLevelsOf picks the dead levels under the policy
+func (s *GridService) deadLevelsOf(levels []*models.GridLevel, now time.Time) []DeadLevel {
+	policy := s.deadLevels
+	dead := []DeadLevel{}
+	for _, level := range levels {
+		var reason string
+		var since time.Time
+		switch {
+		case !level.Enabled && policy.DisabledDays > 0 && now.Sub(level.UpdatedAt) >= time.Duration(policy.DisabledDays)*deadLevelDay:
+			reason, since = DeadLevelDisabled, level.UpdatedAt
+		case level.Enabled && level.State == models.StateReady && policy.IdleDays > 0 &&
+			now.Sub(level.StateChangedAt) >= time.Duration(policy.IdleDays)*deadLevelDay:
+			reason, since = DeadLevelIdle, level.StateChangedAt
+		default:
+			continue
+		}
+
+		dead = append(dead, DeadLevel{
+			LevelID:   level.ID,
+			Symbol:    level.Symbol,
+			Account:   level.Account,
+			State:     level.State,
+			Reason:    reason,
+			Since:     since,
+			AgeDays:   int(now.Sub(since) / deadLevelDay),
+			BlockedBy: deadLevelBlocker(level),
+		})
+	}
+	return dead
+}
+
+// deadLevelBlocker tells why a dead level can't be archived as it is, empty if it can
+func deadLevelBlocker(level *models.GridLevel) string {
+	switch {
+	case level.FilledAmount.Valid:
+		return DeadLevelHoldsCoins
+	case level.State == models.StateBuyActive && level.BuyOrderID.Valid:
+		return DeadLevelBuyOpen
+	case level.State == models.StateError:
+		return DeadLevelErrorState
+	case level.State != models.StateReady:
+		return DeadLevelPlacing
+	}
+	return ""
+}
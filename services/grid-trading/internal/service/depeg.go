@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/shopspring/decimal"
+)
+
+// PegStatus is the latest check of the quote asset's peg
+type PegStatus struct {
+	Symbol       string          `json:"symbol"`
+	Price        decimal.Decimal `json:"price"`
+	DeviationPct decimal.Decimal `json:"deviation_pct"`
+	ThresholdPct decimal.Decimal `json:"threshold_pct"`
+	Depegged     bool            `json:"depegged"`
+	UpdatedAt    string          `json:"updated_at"`
+}
+
+// UseDepegGuard pauses new buys while pegSymbol - a pair of the quote asset against another
+// dollar stablecoin, e.g. USDCUSDT - is more than thresholdPct away from 1. Grid math
+// assumes USDT is worth $1; off peg, buys spend a falling currency and profits are illusory.
+func (s *GridService) UseDepegGuard(pegSymbol string, thresholdPct decimal.Decimal) {
+	s.pegSymbol = pegSymbol
+	s.depegThresholdPct = thresholdPct
+}
+
+// pegStatus returns the peg check at the latest known price, nil if the guard is off or no price arrived yet.
+// The price isn't checked for staleness: price-monitor only triggers on change, so a depegged price
+// that stays put must keep buys paused.
+func (s *GridService) pegStatus() *PegStatus {
+	if s.pegSymbol == "" {
+		return nil
+	}
+
+	quote, _ := s.latestPrice(s.pegSymbol)
+	if quote == nil {
+		return nil
+	}
+
+	deviation := quote.Price.Sub(decimal.NewFromInt(1)).Abs().Mul(decimal.NewFromInt(100)).Round(4)
+	return &PegStatus{
+		Symbol:       s.pegSymbol,
+		Price:        quote.Price,
+		DeviationPct: deviation,
+		ThresholdPct: s.depegThresholdPct,
+		Depegged:     deviation.GreaterThan(s.depegThresholdPct),
+		UpdatedAt:    quote.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// quoteDepegged reports whether the quote asset is off its peg, alerting once per depeg.
+// Buys continue until the first peg price arrives.
+func (s *GridService) quoteDepegged() bool {
+	peg := s.pegStatus()
+	if peg == nil {
+		return false
+	}
+
+	if peg.Depegged {
+		log.Printf("WARNING: %s at %s is %s%% off peg (limit %s%%)", peg.Symbol, peg.Price, peg.DeviationPct, peg.ThresholdPct)
+
+		if s.depegTripped.CompareAndSwap(false, true) {
+			s.emit(contracts.EventQuoteDepegged, peg.Symbol,
+				fmt.Sprintf("%s is %s%% off its $1 peg (%s at %s), new buys paused", quoteAsset, peg.DeviationPct, peg.Symbol, peg.Price),
+				map[string]string{
+					"quote_asset":   quoteAsset,
+					"peg_symbol":    peg.Symbol,
+					"price":         peg.Price.String(),
+					"deviation_pct": peg.DeviationPct.String(),
+					"threshold_pct": peg.ThresholdPct.String(),
+				})
+		}
+		return true
+	}
+
+	if s.depegTripped.CompareAndSwap(true, false) {
+		log.Printf("INFO: %s back on peg (%s at %s), resuming buys", quoteAsset, peg.Symbol, peg.Price)
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+)
+
+// EventSinkInterface receives events for the notifier service. Publish must not block:
+// GridService only describes what happened, delivery to Telegram, email etc. is the notifier's job.
+type EventSinkInterface interface {
+	Publish(event client.Event)
+}
+
+// UseEventSink reports fills, failed orders and trading pauses to the notifier
+func (s *GridService) UseEventSink(events EventSinkInterface) {
+	s.events = events
+}
+
+func (s *GridService) emit(eventType, symbol, message string, fields map[string]string) {
+	if s.events == nil && s.webhooks == nil {
+		return
+	}
+
+	event := s.newEvent(eventType, symbol, message, fields)
+	if s.events != nil {
+		s.events.Publish(event)
+	}
+	if s.webhooks != nil {
+		s.webhooks.Publish(event)
+	}
+}
+
+func (s *GridService) newEvent(eventType, symbol, message string, fields map[string]string) client.Event {
+	return client.Event{
+		ID:         newEventID(),
+		Type:       eventType,
+		Service:    "grid-trading",
+		Symbol:     symbol,
+		Message:    message,
+		Fields:     fields,
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
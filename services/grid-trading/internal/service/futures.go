@@ -0,0 +1,209 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// positionDriftTolerance is the share of the grid-held amount a position may differ by
+// (step size rounding) before it's reported as drifted
+var positionDriftTolerance = decimal.NewFromFloat(0.001)
+
+// FundingFeeRepositoryInterface defines the funding fee ledger operations
+type FundingFeeRepositoryInterface interface {
+	Record(fee *models.FundingFee) (bool, error)
+	GetLatestFundedAt() (time.Time, error)
+	GetTotalsBySymbol(dayStart time.Time) (today, allTime map[string]decimal.Decimal, err error)
+}
+
+// FuturesClient reads positions and funding of the futures account through order-assurance
+type FuturesClient interface {
+	GetPositions(account string) ([]client.FuturesPosition, error)
+	GetFundingFees(account string, since time.Time) ([]client.FundingFee, error)
+}
+
+// FuturesSymbolStatus compares a symbol's futures grid with its exchange position
+type FuturesSymbolStatus struct {
+	Symbol           string          `json:"symbol"`
+	GridHeld         decimal.Decimal `json:"grid_held"`    // Coins of futures levels holding coin
+	PositionAmt      decimal.Decimal `json:"position_amt"` // Long position on the exchange
+	Drift            decimal.Decimal `json:"drift"`        // Position minus grid held
+	InSync           bool            `json:"in_sync"`
+	EntryPrice       decimal.Decimal `json:"entry_price"`
+	MarkPrice        decimal.Decimal `json:"mark_price"`
+	Leverage         int             `json:"leverage"`
+	FundingToday     decimal.Decimal `json:"funding_today"`
+	FundingAllTime   decimal.Decimal `json:"funding_all_time"`
+	UnrealizedProfit decimal.Decimal `json:"unrealized_profit"`
+}
+
+// FuturesStatus is the position and funding overview of the futures grids
+type FuturesStatus struct {
+	Symbols        []FuturesSymbolStatus `json:"symbols"`
+	FundingToday   decimal.Decimal       `json:"funding_today"`    // Negative when paid
+	FundingAllTime decimal.Decimal       `json:"funding_all_time"` // Deduct from realized profit for the net result
+	LastSyncAt     *time.Time            `json:"last_sync_at,omitempty"`
+}
+
+// FuturesMonitor keeps the funding fee ledger of the futures account and checks that
+// each futures grid's HOLDING levels add up to the exchange position. A grid only goes
+// long, so levels holding coin are its share of the long position; sells are reduce-only.
+type FuturesMonitor struct {
+	levels    GridLevelRepositoryInterface
+	fees      FundingFeeRepositoryInterface
+	assurance FuturesClient
+
+	mu         sync.Mutex // One sync at a time (cron and manual trigger)
+	lastSyncAt time.Time
+
+	reportLoc *time.Location // Timezone of funding_today
+}
+
+func NewFuturesMonitor(levels GridLevelRepositoryInterface, fees FundingFeeRepositoryInterface, assurance FuturesClient) *FuturesMonitor {
+	return &FuturesMonitor{
+		levels:    levels,
+		fees:      fees,
+		assurance: assurance,
+	}
+}
+
+// Sync records new funding payments and logs positions that drifted from the grid
+func (m *FuturesMonitor) Sync() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	since, err := m.fees.GetLatestFundedAt()
+	if err != nil {
+		return fmt.Errorf("failed to get latest funding fee: %w", err)
+	}
+
+	// The newest payment is fetched again - its income ID keeps it from being counted twice
+	fees, err := m.assurance.GetFundingFees(client.FuturesAccount, since)
+	if err != nil {
+		return fmt.Errorf("failed to get funding fees: %w", err)
+	}
+
+	recorded := 0
+	for _, fee := range fees {
+		ok, err := m.fees.Record(&models.FundingFee{
+			IncomeID: fee.ID,
+			Symbol:   fee.Symbol,
+			Asset:    fee.Asset,
+			Amount:   fee.Amount,
+			FundedAt: fee.FundedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if ok {
+			recorded++
+		}
+	}
+	if recorded > 0 {
+		log.Printf("INFO: Recorded %d new funding payments", recorded)
+	}
+
+	status, err := m.status()
+	if err != nil {
+		return err
+	}
+	for _, symbol := range status.Symbols {
+		if !symbol.InSync {
+			log.Printf("WARNING: %s futures position %s differs from grid held %s by %s - check for manual trades or missed fills",
+				symbol.Symbol, symbol.PositionAmt, symbol.GridHeld, symbol.Drift)
+		}
+	}
+
+	m.lastSyncAt = time.Now().UTC()
+	return nil
+}
+
+// GetStatus returns each futures symbol's position check and funding totals
+func (m *FuturesMonitor) GetStatus() (*FuturesStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status, err := m.status()
+	if err != nil {
+		return nil, err
+	}
+	if !m.lastSyncAt.IsZero() {
+		lastSyncAt := m.lastSyncAt
+		status.LastSyncAt = &lastSyncAt
+	}
+	return status, nil
+}
+
+func (m *FuturesMonitor) status() (*FuturesStatus, error) {
+	levels, err := m.levels.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+
+	positions, err := m.assurance.GetPositions(client.FuturesAccount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	fundingToday, fundingAllTime, err := m.fees.GetTotalsBySymbol(reportPeriods(m.reportLoc, time.Now()).DayStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding totals: %w", err)
+	}
+
+	bySymbol := make(map[string]*FuturesSymbolStatus)
+	symbolStatus := func(symbol string) *FuturesSymbolStatus {
+		if _, ok := bySymbol[symbol]; !ok {
+			bySymbol[symbol] = &FuturesSymbolStatus{Symbol: symbol}
+		}
+		return bySymbol[symbol]
+	}
+
+	for _, level := range levels {
+		if level.Account != client.FuturesAccount {
+			continue
+		}
+		s := symbolStatus(level.Symbol)
+		if holdsCoin(level) {
+			s.GridHeld = s.GridHeld.Add(level.FilledAmount.Decimal)
+		}
+	}
+
+	// Grids never short, so only long (or one-way) positions are theirs
+	for _, p := range positions {
+		if p.PositionSide == "SHORT" || !p.PositionAmt.IsPositive() {
+			continue
+		}
+		s := symbolStatus(p.Symbol)
+		s.PositionAmt = s.PositionAmt.Add(p.PositionAmt)
+		s.EntryPrice = p.EntryPrice
+		s.MarkPrice = p.MarkPrice
+		s.Leverage = p.Leverage
+		s.UnrealizedProfit = s.UnrealizedProfit.Add(p.UnrealizedProfit)
+	}
+
+	for symbol := range fundingAllTime {
+		symbolStatus(symbol)
+	}
+
+	status := &FuturesStatus{Symbols: []FuturesSymbolStatus{}}
+	for symbol, s := range bySymbol {
+		s.Drift = s.PositionAmt.Sub(s.GridHeld)
+		s.InSync = s.Drift.Abs().LessThanOrEqual(s.GridHeld.Mul(positionDriftTolerance))
+		s.FundingToday = fundingToday[symbol]
+		s.FundingAllTime = fundingAllTime[symbol]
+
+		status.FundingToday = status.FundingToday.Add(s.FundingToday)
+		status.FundingAllTime = status.FundingAllTime.Add(s.FundingAllTime)
+		status.Symbols = append(status.Symbols, *s)
+	}
+	sort.Slice(status.Symbols, func(i, j int) bool { return status.Symbols[i].Symbol < status.Symbols[j].Symbol })
+
+	return status, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// GridFingerprintRepositoryInterface stores the parameters of the grids created on each symbol
+type GridFingerprintRepositoryInterface interface {
+	Record(grid *models.GridFingerprint) (bool, error)
+	GetBySymbol(symbol string) ([]*models.GridFingerprint, error)
+	DeleteBySymbol(symbol string) error
+}
+
+// GridCheck compares a grid about to be created with the grids already on its symbol
+type GridCheck struct {
+	Fingerprint string
+	Duplicate   bool                      // The same grid was created before - creating it again adds no levels
+	Overlaps    []*models.GridFingerprint // Other grids on the symbol whose price range overlaps this one
+
+	grid *models.GridFingerprint
+}
+
+// UseGridFingerprints records the grids created, to spot repeated and overlapping ones
+func (s *GridService) UseGridFingerprints(grids GridFingerprintRepositoryInterface) {
+	s.grids = grids
+}
+
+// CheckGrid fingerprints a grid's parameters and finds an identical grid or grids whose
+// price range overlaps it on the same symbol (any account). Returns nil without a store.
+func (s *GridService) CheckGrid(symbol, account, gridType string, minPrice, maxPrice, gridStep, buyAmount decimal.Decimal, buyMultiplier, maxBuyAmount decimal.NullDecimal) (*GridCheck, error) {
+	if s.grids == nil {
+		return nil, nil
+	}
+
+	grid := &models.GridFingerprint{
+		Fingerprint: gridFingerprint(symbol, account, gridType, minPrice, maxPrice, gridStep, buyAmount, buyMultiplier, maxBuyAmount),
+		Symbol:      symbol,
+		Account:     account,
+		GridType:    gridType,
+		MinPrice:    minPrice,
+		MaxPrice:    maxPrice,
+		GridStep:    gridStep,
+		BuyAmount:   buyAmount,
+	}
+	check := &GridCheck{Fingerprint: grid.Fingerprint, grid: grid}
+
+	existing, err := s.grids.GetBySymbol(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s grids: %w", symbol, err)
+	}
+	for _, other := range existing {
+		if other.Fingerprint == grid.Fingerprint {
+			check.Duplicate = true
+			continue
+		}
+		if other.MinPrice.LessThan(maxPrice) && minPrice.LessThan(other.MaxPrice) {
+			check.Overlaps = append(check.Overlaps, other)
+		}
+	}
+
+	if check.Duplicate {
+		log.Printf("INFO: Grid %s on %s was created before, no new levels expected", grid.Fingerprint, symbol)
+	}
+	for _, other := range check.Overlaps {
+		log.Printf("WARNING: New %s grid %s-%s overlaps grid %s (%s-%s, account %q)",
+			symbol, minPrice, maxPrice, other.Fingerprint, other.MinPrice, other.MaxPrice, other.Account)
+	}
+	return check, nil
+}
+
+// RecordGrid stores a checked grid once its levels are created
+func (s *GridService) RecordGrid(check *GridCheck) error {
+	if s.grids == nil || check == nil || check.Duplicate {
+		return nil
+	}
+	if _, err := s.grids.Record(check.grid); err != nil {
+		return fmt.Errorf("failed to record grid %s: %w", check.Fingerprint, err)
+	}
+	return nil
+}
+
+// gridFingerprint hashes the parameters that decide a grid's levels; decimals are
+// normalized, so 3500 and 3500.0 give the same fingerprint
+func gridFingerprint(symbol, account, gridType string, minPrice, maxPrice, gridStep, buyAmount decimal.Decimal, buyMultiplier, maxBuyAmount decimal.NullDecimal) string {
+	fields := []string{
+		symbol, account, gridType,
+		minPrice.String(), maxPrice.String(), gridStep.String(), buyAmount.String(),
+		"", "", // Flat sizing
+	}
+	if buyMultiplier.Valid {
+		fields[7] = buyMultiplier.Decimal.String()
+	}
+	if maxBuyAmount.Valid {
+		fields[8] = maxBuyAmount.Decimal.String()
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "|")))
+	return hex.EncodeToString(sum[:8])
+}
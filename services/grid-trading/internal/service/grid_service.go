@@ -1,11 +1,21 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/pkg/tracing"
 	"github.com/grid-trading-bot/services/grid-trading/internal/client"
 	"github.com/grid-trading-bot/services/grid-trading/internal/models"
 	"github.com/shopspring/decimal"
@@ -21,14 +31,18 @@ type GridLevelRepositoryInterface interface {
 	GetByBuyOrderID(orderID string) (*models.GridLevel, error)
 	GetBySellOrderID(orderID string) (*models.GridLevel, error)
 	GetStuckInPlacingState(timeout time.Duration) ([]*models.GridLevel, error)
+	GetPlacing() ([]*models.GridLevel, error)
 	GetAllActive() ([]*models.GridLevel, error)
 	GetDistinctSymbols() ([]string, error)
-	GetLevelCounts() (holding, ready int, err error)
+	GetLevelCountsBySymbol() (map[string]models.LevelCounts, error)
+	GetStateCounts() (map[string]int, error)
 
 	// State management operations
-	TryStartBuyOrder(id int) (bool, error)
+	TryStartBuyOrder(id int, amount decimal.Decimal) (bool, error)
 	TryStartSellOrder(id int) (bool, error)
 	UpdateState(id int, state models.GridState) error
+	AddRecoveryFailure(id int) (int, error)
+	Quarantine(id int, from models.GridState, reason string) (bool, error)
 
 	// Order tracking operations
 	UpdateBuyOrderPlaced(id int, orderID string) error
@@ -37,6 +51,18 @@ type GridLevelRepositoryInterface interface {
 	// Fill processing operations
 	ProcessBuyFill(id int, filledAmount decimal.Decimal) error
 	ProcessSellFill(id int) error
+	KeepUnsold(id int, remaining decimal.Decimal) error
+	ReplaceSellOrder(id int, orderID string, remaining decimal.Decimal) error
+	SeedHolding(id int, filledAmount, costUSDT decimal.Decimal) (bool, error)
+
+	// Operator operations
+	SetEnabled(id int, enabled bool) (bool, error)
+	SetSymbolEnabled(symbol string, enabled bool) (int, error)
+	UpdateSettings(id int, buyPrice, sellPrice, buyAmount decimal.Decimal, enabled bool) (bool, error)
+	UpdateNotes(id int, tags []string, notes string) (bool, error)
+	Recover(id int, to models.GridState) (bool, error)
+	ArchiveSymbol(symbol string) (int, error)
+	ArchiveLevel(id int) (bool, error)
 
 	// Creation operations
 	Create(level *models.GridLevel) error
@@ -44,21 +70,35 @@ type GridLevelRepositoryInterface interface {
 
 // OrderAssuranceInterface defines the interface for order assurance client operations
 type OrderAssuranceInterface interface {
-	PlaceOrder(req client.OrderRequest) (*client.OrderResponse, error)
-	GetOrderStatus(symbol, orderID string) (*client.OrderStatus, error)
+	PlaceOrder(ctx context.Context, req client.OrderRequest) (*client.OrderResponse, error)
+	GetOrderStatus(account, symbol, orderID string) (*client.OrderStatus, error)
+	GetOrderStatuses(queries []client.OrderStatusQuery) ([]client.BatchOrderStatus, error)
+	GetFreeBalances(account string) (map[string]decimal.Decimal, error)
+	GetSymbolAssets(account, symbol string) (*client.SymbolAssets, error)
+	GetBookTicker(account, symbol string) (*client.BookTicker, error)
+	GetOpenOrders(account, symbol string) (*client.OpenOrders, error)
+	CancelOrder(account, symbol, orderID string) error
+	Ping() error
 }
 
 // TransactionRepositoryInterface defines the interface for transaction repository operations
 type TransactionRepositoryInterface interface {
 	RecordBuyPlaced(gridLevelID int, symbol string, orderID string, targetPrice, amountUSDT decimal.Decimal) error
 	RecordSellPlaced(gridLevelID int, symbol string, orderID string, targetPrice, amountCoin decimal.Decimal) error
-	RecordBuyFilled(gridLevelID int, symbol string, orderID string, targetPrice, executedPrice, amountCoin, amountUSDT decimal.Decimal) error
-	RecordSellFilled(gridLevelID int, symbol string, orderID string, targetPrice, executedPrice, amountCoin, amountUSDT decimal.Decimal, relatedBuyID int, profitUSDT, profitPct decimal.Decimal) error
+	RecordBuyFilled(gridLevelID int, symbol string, orderID string, targetPrice, executedPrice, amountCoin, amountUSDT decimal.Decimal, fee models.Fee) error
+	RecordSellFilled(gridLevelID int, symbol string, orderID string, targetPrice, executedPrice, amountCoin, amountUSDT decimal.Decimal, relatedBuyID int, profitUSDT, profitPct decimal.Decimal, fee models.Fee) error
+	RecordCancelled(gridLevelID int, symbol string, side models.TransactionSide, orderID string, targetPrice, executedPrice, amountCoin decimal.Decimal) error
 	RecordBuyError(gridLevelID int, symbol string, targetPrice decimal.Decimal, errorCode, errorMsg string) error
 	RecordSellError(gridLevelID int, symbol string, targetPrice decimal.Decimal, errorCode, errorMsg string) error
 	GetLastBuyForLevel(gridLevelID int) (*models.Transaction, error)
-	GetDailyStats() (buys, sells, errors int, profit decimal.Decimal, err error)
-	GetProfitStats() (today, week, month, allTime decimal.Decimal, err error)
+	GetCancelledSellProceeds(gridLevelID, buyTxID int) (decimal.Decimal, error)
+	GetSymbolStats(periods models.ReportPeriods) ([]*models.SymbolStats, error)
+	GetProfitStats(periods models.ReportPeriods) (today, week, month, allTime decimal.Decimal, err error)
+	GetRealizedProfitSince(symbol string, since time.Time) (decimal.Decimal, error)
+	GetFeeStats(periods models.ReportPeriods) (*models.FeeStats, error)
+	GetTransactionsAfter(afterID, limit int) ([]*models.LevelTransaction, error)
+	GetDayStats(from, to time.Time) (*models.DayStats, error)
+	GetLevelCycles(from, to time.Time) ([]*models.LevelCycles, error)
 	GetLastBuy() (*models.Transaction, error)
 	GetLastSell() (*models.Transaction, error)
 }
@@ -68,11 +108,141 @@ type GridService struct {
 	txRepo     TransactionRepositoryInterface
 	assurance  OrderAssuranceInterface
 	tradingFee float64
+	orderTTL   time.Duration // order-assurance cancels orders still open after this (0 = never)
+
+	feeAwareSell bool // Sell orders raised to net the level's spread after fees
 
 	lastPriceMu     sync.RWMutex
 	lastPriceSymbol string
 	lastPrice       decimal.Decimal
 	lastPriceTime   time.Time
+	triggerPrices   map[string]client.PriceQuote // Last trigger per symbol
+	triggerMarks    map[string]triggerMark       // Newest sequenced trigger applied per symbol
+
+	// Latest prices for unrealized PnL and the drawdown guard
+	prices          PriceSourceInterface
+	priceStaleAfter time.Duration
+	maxDrawdownPct  decimal.Decimal // 0 = no limit
+	drawdownTripped atomic.Bool     // Reported once per breach, not on every trigger
+
+	// Order book check before placements (zero maxSpreadPct = off)
+	maxSpreadPct  decimal.Decimal
+	spreadMu      sync.Mutex
+	spreadBooks   map[string]bookRead // account/symbol → last read
+	spreadSkipped map[int]bool        // Levels whose current skip is already recorded
+	spreadSkips   atomic.Int64
+
+	// Which triggers may place orders, see trigger_policy.go (zero = any)
+	triggerPolicy TriggerPolicy
+	sourceSkips   atomic.Int64
+
+	// Buys as quote-quantity MARKET orders once the price reaches the level (false = resting LIMIT buys)
+	quoteBuys bool
+
+	// Quote stablecoin peg guard (empty pegSymbol = off)
+	pegSymbol         string
+	depegThresholdPct decimal.Decimal
+	depegTripped      atomic.Bool
+
+	// Reporting currency (empty reportCurrency = USDT only); fxSymbol is empty with a fixed rate
+	reportCurrency string
+	fxSymbol       string
+	fxFixedRate    decimal.Decimal
+
+	// Sampled trigger prices for the buy-and-hold benchmark (nil = not recorded)
+	history           PriceHistoryInterface
+	historyRecordedAt map[string]time.Time // Last sample per symbol, guarded by lastPriceMu
+
+	// Candles for grid simulations (nil = POST /grids/{symbol}/simulate unavailable)
+	klines KlineSourceInterface
+
+	// Notifier events (nil = not reported)
+	events EventSinkInterface
+
+	// End-of-day reports (nil = not stored)
+	reports DailyReportRepositoryInterface
+
+	// Parameters of the grids created, to spot repeated and overlapping ones
+	grids GridFingerprintRepositoryInterface
+
+	// Per-symbol grid settings, and when each trailing grid's price left its band
+	configs      GridConfigRepositoryInterface
+	trailMu      sync.Mutex
+	trailWatches map[string]trailWatch
+
+	// Deposits and withdrawals, for ROI in status and reports
+	capital CapitalFlowRepositoryInterface
+
+	// Watch-only grids record the orders they would place here instead of placing them
+	watchOnlyAll bool
+	shadow       ShadowTransactionRepositoryInterface
+
+	// Alternative grids run in shadow on the same triggers, for comparison with the live grid
+	strategies ShadowStrategyRepositoryInterface
+
+	// Reconciliation reports (nil = reconciliation off)
+	reconciliations ReconciliationRepositoryInterface
+
+	// Timezone of "today", this week and this month in stats and reports
+	reportLoc *time.Location
+
+	// User-registered webhooks, sent every event (nil = WEBHOOKS_ENABLED off)
+	webhooks *WebhookDispatcher
+
+	// Sell quantity rounding (empty sellPolicy = sell the bought amount as-is)
+	sellPolicy string
+	dust       SellDustInterface
+	dustMu     sync.Mutex // Serializes sells that read and update a grid's dust
+
+	// Take-profit ladders of levels created with sell_tranches (nil = every level sells in one order)
+	ladders SellTrancheRepositoryInterface
+
+	// Stop-loss legs of OCO sells, for grids with stop_loss_pct (nil = plain sells only)
+	stopLosses StopLossRepositoryInterface
+
+	// Trading calendar pausing buys around high-impact events (nil = no calendar)
+	calendar         CalendarRepositoryInterface
+	calendarSettings CalendarSettings
+	calendarMu       sync.RWMutex
+	calendarEvents   []*models.CalendarEvent // Events not over yet, or whose pause isn't lifted yet
+	calendarCheckMu  sync.Mutex              // Serializes CheckCalendar, so a window is recorded once
+
+	// Failed SyncOrders recoveries before a level is quarantined in ERROR (0 = retry forever)
+	maxRecoveryAttempts int
+
+	// Placements without an order ID reported after this (0 = watchdog off)
+	placingWatchdogAfter time.Duration
+	placingMu            sync.Mutex
+	placingAlerted       map[int]time.Time // Level → state_changed_at of the placement already reported
+	placingAlerts        atomic.Int64
+
+	// Set when order-assurance reports an open circuit breaker or a degraded exchange
+	pauseMu     sync.RWMutex
+	pausedAt    time.Time
+	pausedUntil time.Time
+	pauseReason string
+
+	// Startup self-check, and safe mode: no order placement until an operator resumes
+	safeModeMu     sync.RWMutex
+	selfCheck      *SelfCheckReport
+	consistency    *ConsistencyReport // Latest grid invariant check
+	safeModeSince  time.Time          // Zero = not in safe mode
+	safeModeReason string
+
+	// Symbol leases when several instances share the database (nil = trade every symbol)
+	shards *ShardCoordinator
+
+	levelCache *levelCache // Wraps repo when levels are cached (nil = every query reads the database)
+
+	sync SyncSettings // What the sync job recovers and checks
+
+	deadLevels DeadLevelPolicy // Levels the dead-level cleanup archives
+
+	statusCache *orderStatusCache // Open orders triggers don't check again until the price reaches them (nil = check on every trigger)
+
+	// Tick and step precision per account/symbol for API responses and notifications, see precision.go
+	precisionMu sync.Mutex
+	precisions  map[string]symbolPrecision
 }
 
 // NewGridService creates a new GridService
@@ -83,6 +253,14 @@ func NewGridService(repo GridLevelRepositoryInterface, txRepo TransactionReposit
 		txRepo:     txRepo,
 		assurance:  assurance,
 		tradingFee: tradingFee,
+
+		triggerPrices:     make(map[string]client.PriceQuote),
+		triggerMarks:      make(map[string]triggerMark),
+		historyRecordedAt: make(map[string]time.Time),
+		precisions:        make(map[string]symbolPrecision),
+		priceStaleAfter:   time.Minute,
+		reportLoc:         time.UTC,
+		sync:              DefaultSyncSettings(),
 	}
 }
 
@@ -96,27 +274,103 @@ func (s *GridService) CheckHealth() error {
 	return nil
 }
 
-func (s *GridService) ProcessPriceTrigger(symbol string, price decimal.Decimal) error {
+// SetOrderTTL asks order-assurance to cancel grid orders that are still open after ttl (0 = never)
+func (s *GridService) SetOrderTTL(ttl time.Duration) {
+	s.orderTTL = ttl
+}
+
+// triggerMark orders the triggers of a symbol
+type triggerMark struct {
+	exchangeTime time.Time
+	sequence     uint64
+	source       string
+}
+
+// newerThan reports whether a trigger marked m comes after last: a later exchange time
+// wins, and a higher sequence breaks ties (e.g. a second poll within Binance's one-second
+// clock). Exchange times of different sources come from different clocks, so after a
+// failover only the sequence decides. Sides that lack a value don't decide.
+func (m triggerMark) newerThan(last triggerMark) bool {
+	if m.source == last.source && !m.exchangeTime.IsZero() && !last.exchangeTime.IsZero() && !m.exchangeTime.Equal(last.exchangeTime) {
+		return m.exchangeTime.After(last.exchangeTime)
+	}
+	if m.sequence != 0 && last.sequence != 0 {
+		return m.sequence > last.sequence
+	}
+	return true
+}
+
+// ProcessPriceTrigger applies a price trigger, unless a newer trigger of the symbol was
+// already applied - a delayed retry must not replace a newer price decision. Returns false
+// for such duplicate or out-of-order triggers. Triggers without a sequence and exchange time
+// (manual, older price-monitors) are always applied. ctx carries the trace of the price tick.
+func (s *GridService) ProcessPriceTrigger(ctx context.Context, trigger contracts.PriceTrigger) (bool, error) {
+	symbol, price := trigger.Symbol, trigger.Price
+	ctx, span := tracing.Start(ctx, "process price trigger")
+	defer span.End()
+	span.SetAttr("symbol", symbol)
+	span.SetAttr("price", price.String())
+	if s.shards != nil && s.shards.OwnedElsewhere(symbol) {
+		return false, fmt.Errorf("%w: %s", ErrNotOwner, symbol)
+	}
+	mark := triggerMark{sequence: trigger.Sequence, source: trigger.Source}
+	if trigger.ExchangeTime != nil {
+		mark.exchangeTime = *trigger.ExchangeTime
+	}
+	sequenced := mark.sequence != 0 || !mark.exchangeTime.IsZero()
+
 	// Store last price update
+	now := time.Now()
 	s.lastPriceMu.Lock()
+	last, hadLast := s.triggerMarks[symbol]
+	if hadLast && sequenced && !mark.newerThan(last) {
+		s.lastPriceMu.Unlock()
+		span.SetAttr("ignored", true)
+		slog.Warn("Ignoring out-of-order trigger", logging.KeySymbol, symbol, "price", price,
+			"sequence", mark.sequence, "exchange_time", mark.exchangeTime.Format(time.RFC3339), "source", mark.source,
+			"last_sequence", last.sequence, "last_exchange_time", last.exchangeTime.Format(time.RFC3339), "last_source", last.source)
+		return false, nil
+	}
+	if sequenced {
+		s.triggerMarks[symbol] = mark
+	}
 	s.lastPriceSymbol = symbol
 	s.lastPrice = price
-	s.lastPriceTime = time.Now()
+	s.lastPriceTime = now
+	s.triggerPrices[symbol] = client.PriceQuote{Symbol: symbol, Price: price, UpdatedAt: now}
 	s.lastPriceMu.Unlock()
 
+	s.recordPrice(symbol, price, now)
+
+	_, dbSpan := tracing.Start(ctx, "db get levels")
 	levels, err := s.repo.GetBySymbol(symbol)
+	dbSpan.RecordError(err)
+	dbSpan.End()
 	if err != nil {
-		return fmt.Errorf("failed to get levels for symbol %s: %w", symbol, err)
+		span.RecordError(err)
+		// Let a redelivery of this trigger through, unless a newer one came in meanwhile
+		s.lastPriceMu.Lock()
+		if sequenced && s.triggerMarks[symbol] == mark {
+			if hadLast {
+				s.triggerMarks[symbol] = last
+			} else {
+				delete(s.triggerMarks, symbol)
+			}
+		}
+		s.lastPriceMu.Unlock()
+		return false, fmt.Errorf("failed to get levels for symbol %s: %w", symbol, err)
 	}
 
 	// Check active orders first to process any fills
+	_, pollSpan := tracing.Start(ctx, "poll active orders")
 	for _, level := range levels {
 		if level.State == models.StateBuyActive && level.BuyOrderID.Valid {
-			s.checkAndUpdateOrderStatus(level, level.BuyOrderID.String, true)
+			s.pollOrderStatus(level, level.BuyOrderID.String, true, price)
 		} else if level.State == models.StateSellActive && level.SellOrderID.Valid {
-			s.checkAndUpdateOrderStatus(level, level.SellOrderID.String, false)
+			s.pollOrderStatus(level, level.SellOrderID.String, false, price)
 		}
 	}
+	pollSpan.End()
 
 	// Place new orders based on price triggers
 	activatedCount := 0
@@ -137,248 +391,527 @@ func (s *GridService) ProcessPriceTrigger(symbol string, price decimal.Decimal)
 		}
 	}
 
+	if inSafeMode, _ := s.SafeMode(); inSafeMode {
+		slog.Warn("Safe mode, skipping order placement", logging.KeySymbol, symbol, "price", price)
+		return true, nil
+	}
+
+	if pausedUntil, reason := s.tradingPausedUntil(); !pausedUntil.IsZero() {
+		slog.Warn("Trading paused, skipping order placement", "until", pausedUntil.Format(time.RFC3339), "reason", reason,
+			logging.KeySymbol, symbol, "price", price)
+		return true, nil
+	}
+
+	buysPaused, pauseReason := s.drawdownExceeded(), "the drawdown limit"
+	if s.quoteDepegged() {
+		buysPaused, pauseReason = true, "the "+quoteAsset+" depeg guard"
+	}
+	if reason := s.calendarPause(symbol); reason != "" {
+		buysPaused, pauseReason = true, reason
+	}
+
+	s.runShadowStrategies(symbol, price, buysPaused, pauseReason)
+
+	if s.watchOnly(symbol) {
+		s.shadowTrigger(symbol, levels, price, buysPaused, pauseReason)
+		return true, nil
+	}
+
+	evalCtx, evalSpan := tracing.Start(ctx, "evaluate levels")
+	defer evalSpan.End()
+	evalSpan.SetAttr("levels", len(levels))
+	market := s.marketOrders(symbol)
 	for _, level := range levels {
-		if level.CanPlaceBuy(price) {
-			log.Printf("INFO: Price %s triggered BUY level %d (target: %s)", price, level.ID, level.BuyPrice)
-			if err := s.tryPlaceBuyOrder(level); err != nil {
-				log.Printf("ERROR: Failed to place buy order for level %d: %v", level.ID, err)
+		canBuy := s.canBuy(level, price, market)
+		if canBuy && buysPaused {
+			levelLogger(level).Warn("Price triggered BUY level but buys are paused", "price", price, "reason", pauseReason)
+		} else if canBuy {
+			levelLogger(level).Info("Price triggered BUY level", "price", price, "target", level.BuyPrice)
+			if !s.triggerAllows(trigger, level, client.OrderSideBuy) {
+				continue
+			}
+			if err := s.tryPlaceBuyOrder(evalCtx, level); err != nil {
+				levelLogger(level).Error("Failed to place buy order", logging.Err(err))
 			} else {
 				activatedCount++
 			}
-		} else if level.CanPlaceSell(price) {
-			log.Printf("INFO: Price %s triggered SELL level %d (target: %s)", price, level.ID, level.SellPrice)
-			if err := s.tryPlaceSellOrder(level); err != nil {
-				log.Printf("ERROR: Failed to place sell order for level %d: %v", level.ID, err)
+		} else if s.canSell(level, price, market) {
+			levelLogger(level).Info("Price triggered SELL level", "price", price, "target", s.sellOrderPrice(level))
+			if !s.triggerAllows(trigger, level, client.OrderSideSell) {
+				continue
+			}
+			if err := s.tryPlaceSellOrder(evalCtx, level); err != nil {
+				levelLogger(level).Error("Failed to place sell order", logging.Err(err))
 			} else {
 				activatedCount++
 			}
 		}
 	}
 
+	evalSpan.SetAttr("activated", activatedCount)
 	if activatedCount > 0 {
-		log.Printf("INFO: Successfully activated %d/%d orders for %s at price %s", activatedCount, checkedLevels, symbol, price)
+		slog.Info("Activated orders", logging.KeySymbol, symbol, "price", price, "activated", activatedCount, "levels", checkedLevels)
 	} else if len(levels) > 0 {
-		log.Printf("DEBUG: No orders activated for %s at price %s (checked %d levels, range [%s - %s])", symbol, price, checkedLevels, minBuyPrice, maxSellPrice)
+		slog.Debug("No orders activated", logging.KeySymbol, symbol, "price", price, "levels", checkedLevels,
+			"min_buy_price", minBuyPrice, "max_sell_price", maxSellPrice)
 	} else {
-		log.Printf("DEBUG: No orders activated for %s at price %s (no levels configured)", symbol, price)
+		slog.Debug("No orders activated, no levels configured", logging.KeySymbol, symbol, "price", price)
 	}
 
-	return nil
+	return true, nil
 }
 
-func (s *GridService) tryPlaceBuyOrder(level *models.GridLevel) error {
-	started, err := s.repo.TryStartBuyOrder(level.ID)
+// tryPlaceBuyOrder places a ready level's buy order, traced as "place buy" under ctx
+func (s *GridService) tryPlaceBuyOrder(ctx context.Context, level *models.GridLevel) (err error) {
+	ctx, span := tracing.Start(ctx, "place buy")
+	span.SetAttr(logging.KeyLevelID, level.ID)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	if !s.spreadAllows(level, client.OrderSideBuy, level.BuyPrice) {
+		return nil
+	}
+
+	logger := levelLogger(level)
+	amount := s.buyAmountFor(level)
+	_, dbSpan := tracing.Start(ctx, "db start buy")
+	started, err := s.repo.TryStartBuyOrder(level.ID, amount)
+	dbSpan.RecordError(err)
+	dbSpan.End()
 	if err != nil {
-		log.Printf("ERROR: Failed to start buy order for level %d: %v", level.ID, err)
+		logger.Error("Failed to start buy order", logging.Err(err))
 		return fmt.Errorf("failed to start buy order: %w", err)
 	}
 
 	if !started {
-		log.Printf("DEBUG: Level %d buy order skipped (race condition or already in progress)", level.ID)
+		logger.Debug("Buy order skipped (race condition or already in progress)")
 		return nil
 	}
 
 	orderReq := client.OrderRequest{
-		Symbol: level.Symbol,
-		Price:  level.BuyPrice,
-		Side:   client.OrderSideBuy,
-		Amount: level.BuyAmount,
+		Symbol:     level.Symbol,
+		Price:      level.BuyPrice,
+		Side:       client.OrderSideBuy,
+		Amount:     amount,
+		Account:    level.Account,
+		TTLSeconds: int(s.orderTTL.Seconds()),
+		OrderType:  s.orderType(level.Symbol),
+
+		QuoteOrderQty: s.quoteBuys,
 	}
 
-	log.Printf("INFO: Placing buy order for level %d - Symbol: %s, Price: %s, Amount: %s",
-		level.ID, orderReq.Symbol, orderReq.Price, orderReq.Amount)
+	logger.Info("Placing buy order", "price", orderReq.Price, "amount", orderReq.Amount)
 
-	orderResp, err := s.assurance.PlaceOrder(orderReq)
+	orderResp, err := s.assurance.PlaceOrder(ctx, orderReq)
 	if err != nil {
-		log.Printf("ERROR: Buy order placement failed for level %d: %v", level.ID, err)
+		logger.Error("Buy order placement failed", logging.Err(err))
+		s.pauseOnCircuitOpen(err)
 		s.repo.UpdateState(level.ID, models.StateReady)
-		s.txRepo.RecordBuyError(level.ID, level.Symbol, level.BuyPrice, "order_placement_failed", err.Error())
+		s.txRepo.RecordBuyError(level.ID, level.Symbol, level.BuyPrice, placementErrorCode(err), err.Error())
 		return fmt.Errorf("failed to place buy order: %w", err)
 	}
 
+	span.SetAttr(logging.KeyOrderID, orderResp.OrderID)
+	_, dbSpan = tracing.Start(ctx, "db store buy order")
+	defer dbSpan.End()
 	if err := s.repo.UpdateBuyOrderPlaced(level.ID, orderResp.OrderID); err != nil {
-		log.Printf("ERROR: Failed to update database for buy order %s: %v", orderResp.OrderID, err)
+		dbSpan.RecordError(err)
+		logger.Error("Failed to store placed buy order", logging.KeyOrderID, orderResp.OrderID, logging.Err(err))
 		return fmt.Errorf("failed to update buy order placed: %w", err)
 	}
 
 	// Record PLACED transaction
-	if err := s.txRepo.RecordBuyPlaced(level.ID, level.Symbol, orderResp.OrderID, level.BuyPrice, level.BuyAmount); err != nil {
-		log.Printf("WARNING: Failed to record buy placed transaction: %v", err)
+	if err := s.txRepo.RecordBuyPlaced(level.ID, level.Symbol, orderResp.OrderID, level.BuyPrice, amount); err != nil {
+		logger.Warn("Failed to record buy placed transaction", logging.KeyOrderID, orderResp.OrderID, logging.Err(err))
 	}
 
-	log.Printf("SUCCESS: Placed buy order %s for level %d at price %s, amount %s", orderResp.OrderID, level.ID, level.BuyPrice, level.BuyAmount)
+	logger.Info("Placed buy order", logging.KeyOrderID, orderResp.OrderID, "price", level.BuyPrice, "amount", amount)
 	return nil
 }
 
-func (s *GridService) tryPlaceSellOrder(level *models.GridLevel) error {
+// tryPlaceSellOrder places a holding level's sell order, traced as "place sell" under ctx
+func (s *GridService) tryPlaceSellOrder(ctx context.Context, level *models.GridLevel) (err error) {
+	ctx, span := tracing.Start(ctx, "place sell")
+	span.SetAttr(logging.KeyLevelID, level.ID)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	if !s.spreadAllows(level, client.OrderSideSell, level.SellPrice) {
+		return nil
+	}
+
+	logger := levelLogger(level)
+	_, dbSpan := tracing.Start(ctx, "db start sell")
 	started, err := s.repo.TryStartSellOrder(level.ID)
+	dbSpan.RecordError(err)
+	dbSpan.End()
 	if err != nil {
-		log.Printf("ERROR: Failed to start sell order for level %d: %v", level.ID, err)
+		logger.Error("Failed to start sell order", logging.Err(err))
 		return fmt.Errorf("failed to start sell order: %w", err)
 	}
 
 	if !started {
-		log.Printf("DEBUG: Level %d sell order skipped (race condition or already in progress)", level.ID)
+		logger.Debug("Sell order skipped (race condition or already in progress)")
 		return nil
 	}
 
 	if !level.FilledAmount.Valid {
-		log.Printf("ERROR: Level %d has no filled amount, cannot place sell order", level.ID)
+		logger.Error("Level has no filled amount, cannot place sell order")
 		s.repo.UpdateState(level.ID, models.StateHolding)
 		return fmt.Errorf("no filled amount for level %d", level.ID)
 	}
 
+	tranches, err := s.sellTranches(level.ID)
+	if err != nil {
+		logger.Error("Failed to get sell tranches", logging.Err(err))
+		s.repo.UpdateState(level.ID, models.StateHolding)
+		return fmt.Errorf("failed to get sell tranches: %w", err)
+	}
+	if len(tranches) > 0 {
+		return s.placeTranches(ctx, level, tranches)
+	}
+
 	orderReq := client.OrderRequest{
-		Symbol: level.Symbol,
-		Price:  level.SellPrice,
-		Side:   client.OrderSideSell,
-		Amount: level.FilledAmount.Decimal,
+		Symbol:     level.Symbol,
+		Price:      s.sellOrderPrice(level),
+		Side:       client.OrderSideSell,
+		Amount:     level.FilledAmount.Decimal,
+		Account:    level.Account,
+		TTLSeconds: int(s.orderTTL.Seconds()),
+		OrderType:  s.orderType(level.Symbol),
 	}
 
-	log.Printf("INFO: Placing sell order for level %d - Symbol: %s, Price: %s, Amount: %s",
-		level.ID, orderReq.Symbol, orderReq.Price, orderReq.Amount)
+	logger.Info("Placing sell order", "price", orderReq.Price, "amount", orderReq.Amount)
 
-	orderResp, err := s.assurance.PlaceOrder(orderReq)
+	orderResp, amount, err := s.placeSell(ctx, level, orderReq)
 	if err != nil {
-		log.Printf("ERROR: Sell order placement failed for level %d: %v", level.ID, err)
+		logger.Error("Sell order placement failed", logging.Err(err))
+		s.pauseOnCircuitOpen(err)
 		s.repo.UpdateState(level.ID, models.StateHolding)
-		s.txRepo.RecordSellError(level.ID, level.Symbol, level.SellPrice, "order_placement_failed", err.Error())
+		s.txRepo.RecordSellError(level.ID, level.Symbol, level.SellPrice, placementErrorCode(err), err.Error())
 		return fmt.Errorf("failed to place sell order: %w", err)
 	}
 
+	span.SetAttr(logging.KeyOrderID, orderResp.OrderID)
+	_, dbSpan = tracing.Start(ctx, "db store sell order")
+	defer dbSpan.End()
 	if err := s.repo.UpdateSellOrderPlaced(level.ID, orderResp.OrderID); err != nil {
-		log.Printf("ERROR: Failed to update database for sell order %s: %v", orderResp.OrderID, err)
+		dbSpan.RecordError(err)
+		logger.Error("Failed to store placed sell order", logging.KeyOrderID, orderResp.OrderID, logging.Err(err))
 		return fmt.Errorf("failed to update sell order placed: %w", err)
 	}
 
 	// Record PLACED transaction
-	if err := s.txRepo.RecordSellPlaced(level.ID, level.Symbol, orderResp.OrderID, level.SellPrice, level.FilledAmount.Decimal); err != nil {
-		log.Printf("WARNING: Failed to record sell placed transaction: %v", err)
+	if err := s.txRepo.RecordSellPlaced(level.ID, level.Symbol, orderResp.OrderID, level.SellPrice, amount); err != nil {
+		logger.Warn("Failed to record sell placed transaction", logging.KeyOrderID, orderResp.OrderID, logging.Err(err))
 	}
 
-	log.Printf("SUCCESS: Placed sell order %s for level %d at price %s, amount %s", orderResp.OrderID, level.ID, level.SellPrice, level.FilledAmount.Decimal)
+	logger.Info("Placed sell order", logging.KeyOrderID, orderResp.OrderID, "price", orderReq.Price, "amount", amount)
 	return nil
 }
 
-func (s *GridService) ProcessBuyFillNotification(orderID string, filledAmount, fillPrice decimal.Decimal) error {
+// tradingPausedUntil returns the pause deadline and why, or zero time if trading is not paused
+func (s *GridService) tradingPausedUntil() (time.Time, string) {
+	s.pauseMu.RLock()
+	defer s.pauseMu.RUnlock()
+
+	if time.Now().Before(s.pausedUntil) {
+		return s.pausedUntil, s.pauseReason
+	}
+	return time.Time{}, ""
+}
+
+// pauseTrading pauses order placement until the given time, reporting whether it already was paused
+func (s *GridService) pauseTrading(until time.Time, reason string) bool {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	now := time.Now()
+	wasPaused := now.Before(s.pausedUntil)
+	if !wasPaused {
+		s.pausedAt = now
+	}
+	s.pausedUntil = until
+	s.pauseReason = reason
+	return wasPaused
+}
+
+// SetExchangeStatus pauses order placement while order-assurance reports the exchange as
+// degraded, and resumes it on recovery. A degraded status lapses unless it is re-sent.
+func (s *GridService) SetExchangeStatus(status contracts.ExchangeStatus) {
+	if status.Degraded {
+		until := status.CheckedAt.Add(time.Duration(status.ValidForSec) * time.Second)
+		if !until.After(time.Now()) {
+			return // Already lapsed (e.g. queued while grid-trading was down)
+		}
+
+		if !s.pauseTrading(until, "exchange degraded: "+status.Reason) {
+			slog.Warn("Exchange degraded, pausing order placement", "reason", status.Reason)
+			s.emit(contracts.EventExchangeDegraded, "", fmt.Sprintf("Exchange degraded (%s), order placement paused until it recovers", status.Reason),
+				map[string]string{"reason": status.Reason})
+		}
+		return
+	}
+
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	// A check from before the pause (e.g. a circuit opened since) says nothing about it
+	if !time.Now().Before(s.pausedUntil) || status.CheckedAt.Before(s.pausedAt) {
+		return
+	}
+	s.pausedUntil = time.Time{}
+	s.pauseReason = ""
+	slog.Info("Exchange recovered, resuming order placement")
+}
+
+// pauseOnCircuitOpen pauses order placement while order-assurance's circuit breaker is open
+func (s *GridService) pauseOnCircuitOpen(err error) {
+	var orderErr *client.OrderError
+	if !errors.As(err, &orderErr) || orderErr.Code != "circuit_open" {
+		return
+	}
+
+	retryAfter := time.Duration(orderErr.RetryAfterSec) * time.Second
+	if retryAfter <= 0 {
+		retryAfter = 30 * time.Second
+	}
+
+	wasPaused := s.pauseTrading(time.Now().Add(retryAfter), "exchange circuit open")
+
+	slog.Warn("Exchange circuit open, pausing order placement", "retry_after", retryAfter)
+
+	if !wasPaused {
+		s.emit(contracts.EventTradingPaused, "", fmt.Sprintf("Exchange circuit open, order placement paused for %s", retryAfter),
+			map[string]string{"retry_after": retryAfter.String(), "reason": orderErr.Message})
+	}
+}
+
+// levelLogger tags log lines with a level's ID, symbol and account
+func levelLogger(level *models.GridLevel) *slog.Logger {
+	return slog.With(logging.KeyLevelID, level.ID, logging.KeySymbol, level.Symbol, logging.KeyAccount, level.Account)
+}
+
+// placementErrorCode returns the order-assurance error classification, if any
+func placementErrorCode(err error) string {
+	var orderErr *client.OrderError
+	if errors.As(err, &orderErr) && orderErr.Code != "" {
+		return orderErr.Code
+	}
+	return "order_placement_failed"
+}
+
+// ProcessBuyFillNotification records a buy fill and moves the level to HOLDING. baseAsset is the
+// traded coin from the exchange's symbol rules (looked up when the sender didn't include it).
+func (s *GridService) ProcessBuyFillNotification(orderID string, filledAmount, fillPrice, commission decimal.Decimal, commissionAsset, baseAsset string) error {
 	level, err := s.repo.GetByBuyOrderID(orderID)
 	if err != nil {
-		log.Printf("ERROR: Failed to get level by buy order ID %s: %v", orderID, err)
+		slog.Error("Failed to get level by buy order ID", logging.KeyOrderID, orderID, logging.Err(err))
 		return fmt.Errorf("failed to get level by buy order ID: %w", err)
 	}
 
 	if level == nil {
-		log.Printf("WARNING: No level found for buy order %s (possibly old/deleted)", orderID)
+		slog.Warn("No level found for buy order (possibly old/deleted)", logging.KeyOrderID, orderID)
 		return nil
 	}
 
+	logger := levelLogger(level).With(logging.KeyOrderID, orderID)
 	if level.State != models.StateBuyActive {
-		log.Printf("WARNING: Level %d not in BUY_ACTIVE state (current: %s) for buy order %s, skipping fill", level.ID, level.State, orderID)
+		logger.Warn("Level not in BUY_ACTIVE state for buy order, skipping fill", "state", level.State)
 		return nil
 	}
 
 	// Record transaction FIRST (audit trail before state change)
 	amountUSDT := filledAmount.Mul(fillPrice)
-	if err := s.txRepo.RecordBuyFilled(level.ID, level.Symbol, orderID, level.BuyPrice, fillPrice, filledAmount, amountUSDT); err != nil {
-		log.Printf("ERROR: CRITICAL - Failed to record buy transaction for level %d: %v - NOT updating state!", level.ID, err)
+	baseAsset = s.resolveBaseAsset(level, baseAsset)
+	fee := newFee(baseAsset, commission, commissionAsset, fillPrice)
+	if err := s.txRepo.RecordBuyFilled(level.ID, level.Symbol, orderID, level.BuyPrice, fillPrice, filledAmount, amountUSDT, fee); err != nil {
+		logger.Error("CRITICAL - Failed to record buy transaction, NOT updating state!", logging.Err(err))
 		return fmt.Errorf("failed to record buy fill transaction: %w", err)
 	}
 
+	// Commission charged in the bought coin reduces what we can sell
+	heldAmount := filledAmount
+	if fee.Asset != "" && fee.Asset == baseAsset {
+		heldAmount = filledAmount.Sub(fee.Commission)
+		logger.Info("Level holds the bought amount less the commission", "held", heldAmount, "commission", fee.Commission, "asset", fee.Asset)
+	}
+
 	// Now update state
-	if err := s.repo.ProcessBuyFill(level.ID, filledAmount); err != nil {
-		log.Printf("ERROR: CRITICAL - Recorded buy TX but failed state update for level %d: %v", level.ID, err)
+	if err := s.repo.ProcessBuyFill(level.ID, heldAmount); err != nil {
+		logger.Error("CRITICAL - Recorded buy TX but failed state update", logging.Err(err))
 		return fmt.Errorf("failed to process buy fill: %w", err)
 	}
-
-	log.Printf("INFO: Processed buy fill for level %d - Order: %s, Amount: %s coins, Fill Price: %s, Total: %s USDT",
-		level.ID, orderID, filledAmount, fillPrice, amountUSDT)
+	s.resetTranches(level.ID)
+
+	logger.Info("Processed buy fill", "amount", filledAmount, "price", fillPrice, "amount_usdt", amountUSDT)
+
+	precision := s.precisionFor(level.Account, level.Symbol)
+	shownPrice, shownAmount := precision.Price(fillPrice), precision.Quantity(filledAmount)
+	s.emit(contracts.EventBuyFilled, level.Symbol,
+		fmt.Sprintf("Bought %s %s at %s (%s USDT), level %d", shownAmount, level.Symbol, shownPrice, amountUSDT, level.ID),
+		map[string]string{
+			"level_id":    strconv.Itoa(level.ID),
+			"order_id":    orderID,
+			"price":       shownPrice.String(),
+			"amount":      shownAmount.String(),
+			"amount_usdt": amountUSDT.String(),
+			"sell_price":  precision.Price(level.SellPrice).String(),
+		})
 
 	// Immediately place sell order now that we're in HOLDING state
 	updatedLevel, err := s.repo.GetByID(level.ID)
 	if err != nil {
-		log.Printf("ERROR: Failed to fetch updated level %d for sell order: %v", level.ID, err)
+		logger.Error("Failed to fetch updated level for sell order", logging.Err(err))
 		return nil
 	}
 
 	if updatedLevel.State == models.StateHolding {
-		if err := s.tryPlaceSellOrder(updatedLevel); err != nil {
-			log.Printf("ERROR: Failed to place sell order for level %d: %v", level.ID, err)
+		if err := s.tryPlaceSellOrder(context.Background(), updatedLevel); err != nil {
+			logger.Error("Failed to place sell order", logging.Err(err))
 		}
 	}
 
 	return nil
 }
 
-func (s *GridService) ProcessSellFillNotification(orderID string, filledAmount, fillPrice decimal.Decimal) error {
+// ProcessSellFillNotification records a sell fill with the cycle's profit and frees the level.
+// The fill of a take-profit ladder's tranche only frees the level once every tranche is sold.
+func (s *GridService) ProcessSellFillNotification(orderID string, filledAmount, fillPrice, commission decimal.Decimal, commissionAsset, baseAsset string) error {
+	tranche, err := s.trancheOf(orderID)
+	if err != nil {
+		return err
+	}
+	if tranche != nil {
+		return s.processTrancheFill(tranche, orderID, filledAmount, fillPrice, commission, commissionAsset, baseAsset)
+	}
+
 	level, err := s.repo.GetBySellOrderID(orderID)
 	if err != nil {
-		log.Printf("ERROR: Failed to get level by sell order ID %s: %v", orderID, err)
+		slog.Error("Failed to get level by sell order ID", logging.KeyOrderID, orderID, logging.Err(err))
 		return fmt.Errorf("failed to get level by sell order ID: %w", err)
 	}
 
+	// The stop-loss leg of the OCO a level sells with
+	var stop *models.StopLossOrder
+	if level == nil {
+		if stop, level, err = s.stopLossOf(orderID); err != nil {
+			return fmt.Errorf("failed to get level by stop-loss order ID: %w", err)
+		}
+	}
+
 	if level == nil {
-		log.Printf("WARNING: No level found for sell order %s (possibly old/deleted)", orderID)
+		slog.Warn("No level found for sell order (possibly old/deleted)", logging.KeyOrderID, orderID)
 		return nil
 	}
 
+	logger := levelLogger(level).With(logging.KeyOrderID, orderID)
 	if level.State != models.StateSellActive {
-		log.Printf("WARNING: Level %d not in SELL_ACTIVE state (current: %s) for sell order %s, skipping fill", level.ID, level.State, orderID)
+		logger.Warn("Level not in SELL_ACTIVE state for sell order, skipping fill", "state", level.State)
 		return nil
 	}
 
+	fee := newFee(s.resolveBaseAsset(level, baseAsset), commission, commissionAsset, fillPrice)
+	return s.completeSell(level, orderID, filledAmount, fillPrice, fee, stop)
+}
+
+// completeSell records the sell completing a level's cycle with the cycle's profit and frees the
+// level. stop is set when the stop-loss leg of the level's OCO sold instead of its limit sell.
+func (s *GridService) completeSell(level *models.GridLevel, orderID string, filledAmount, fillPrice decimal.Decimal, fee models.Fee, stop *models.StopLossOrder) error {
+	logger := levelLogger(level).With(logging.KeyOrderID, orderID)
+	targetPrice := level.SellPrice
+	if stop != nil {
+		targetPrice = stop.StopPrice
+	}
+
 	// Get the last buy transaction to calculate profit
 	buyTx, err := s.txRepo.GetLastBuyForLevel(level.ID)
 	if err != nil {
-		log.Printf("ERROR: Failed to get last buy transaction for level %d: %v", level.ID, err)
+		logger.Error("Failed to get last buy transaction", logging.Err(err))
 	}
 	if buyTx == nil {
-		log.Printf("WARNING: No buy transaction found for level %d - cannot calculate profit", level.ID)
+		logger.Warn("No buy transaction found - cannot calculate profit")
 	}
 
 	// Calculate profit BEFORE recording
 	sellAmountUSDT := filledAmount.Mul(fillPrice)
 	var relatedBuyID int
-	var profitUSDT, profitPct decimal.Decimal
+	var profitUSDT, profitPct, totalFees decimal.Decimal
 
 	if buyTx != nil && buyTx.AmountUSDT.Valid && buyTx.AmountUSDT.Decimal.GreaterThan(decimal.Zero) {
 		relatedBuyID = buyTx.ID
-		buyFee := buyTx.AmountUSDT.Decimal.Mul(decimal.NewFromFloat(s.tradingFee / 100))
-		sellFee := sellAmountUSDT.Mul(decimal.NewFromFloat(s.tradingFee / 100))
-		totalFees := buyFee.Add(sellFee)
-		profitUSDT = sellAmountUSDT.Sub(buyTx.AmountUSDT.Decimal).Sub(totalFees)
+		// A buy commission taken in the coin already shrank the amount sold, so it is in sellAmountUSDT
+		buyFee := decimal.Zero
+		if !chargedInCoin(buyTx) {
+			buyFee = s.feeUSDT(buyTx.FeeUSDT, buyTx.AmountUSDT.Decimal)
+		}
+		sellFee := s.feeUSDT(fee.USDT, sellAmountUSDT)
+		totalFees = buyFee.Add(sellFee)
+
+		// Coins sold by earlier sells that were cancelled part-filled belong to this cycle too
+		proceeds := sellAmountUSDT
+		partial, err := s.txRepo.GetCancelledSellProceeds(level.ID, buyTx.ID)
+		if err != nil {
+			logger.Warn("Failed to get part-filled sells, profit excludes them", logging.Err(err))
+		} else if partial.IsPositive() {
+			proceeds = proceeds.Add(partial)
+			totalFees = totalFees.Add(s.feeUSDT(decimal.NullDecimal{}, partial))
+		}
+		profitUSDT = proceeds.Sub(buyTx.AmountUSDT.Decimal).Sub(totalFees)
 		profitPct = profitUSDT.Div(buyTx.AmountUSDT.Decimal).Mul(decimal.NewFromInt(100))
 	}
 
 	// Record transaction FIRST (audit trail before state change)
-	if err := s.txRepo.RecordSellFilled(level.ID, level.Symbol, orderID, level.SellPrice, fillPrice, filledAmount, sellAmountUSDT, relatedBuyID, profitUSDT, profitPct); err != nil {
-		log.Printf("ERROR: CRITICAL - Failed to record sell transaction for level %d: %v - NOT updating state!", level.ID, err)
+	if err := s.txRepo.RecordSellFilled(level.ID, level.Symbol, orderID, targetPrice, fillPrice, filledAmount, sellAmountUSDT, relatedBuyID, profitUSDT, profitPct, fee); err != nil {
+		logger.Error("CRITICAL - Failed to record sell transaction, NOT updating state!", logging.Err(err))
 		return fmt.Errorf("failed to record sell fill transaction: %w", err)
 	}
 
 	// Now update state
 	if err := s.repo.ProcessSellFill(level.ID); err != nil {
-		log.Printf("ERROR: CRITICAL - Recorded sell TX but failed state update for level %d: %v", level.ID, err)
+		logger.Error("CRITICAL - Recorded sell TX but failed state update", logging.Err(err))
 		return fmt.Errorf("failed to process sell fill: %w", err)
 	}
+	s.clearStopLoss(level.ID)
 
+	logger.Info("Processed sell fill", "amount", filledAmount, "price", fillPrice, "amount_usdt", sellAmountUSDT)
 	if buyTx != nil && buyTx.AmountUSDT.Valid && buyTx.AmountUSDT.Decimal.GreaterThan(decimal.Zero) {
-		buyFee := buyTx.AmountUSDT.Decimal.Mul(decimal.NewFromFloat(s.tradingFee / 100))
-		sellFee := sellAmountUSDT.Mul(decimal.NewFromFloat(s.tradingFee / 100))
-		totalFees := buyFee.Add(sellFee)
-		log.Printf("INFO: Processed sell fill for level %d - Order: %s, Amount: %s coins @ %s, Total: %s USDT",
-			level.ID, orderID, filledAmount, fillPrice, sellAmountUSDT)
-		log.Printf("SUCCESS: Cycle complete for level %d - Buy: %s USDT, Sell: %s USDT, Fees: %s USDT, Profit: %s USDT (%s%%)",
-			level.ID, buyTx.AmountUSDT.Decimal, sellAmountUSDT, totalFees, profitUSDT, profitPct)
+		logger.Info("Cycle complete", "buy_usdt", buyTx.AmountUSDT.Decimal, "sell_usdt", sellAmountUSDT,
+			"fees_usdt", totalFees, "profit_usdt", profitUSDT, "profit_pct", profitPct)
 	} else {
-		log.Printf("INFO: Processed sell fill for level %d - Order: %s, Amount: %s coins @ %s, Total: %s USDT",
-			level.ID, orderID, filledAmount, fillPrice, sellAmountUSDT)
-		log.Printf("WARNING: Cycle complete for level %d but profit N/A (no buy transaction found)", level.ID)
+		logger.Warn("Cycle complete but profit N/A (no buy transaction found)")
 	}
 
+	precision := s.precisionFor(level.Account, level.Symbol)
+	shownPrice, shownAmount := precision.Price(fillPrice), precision.Quantity(filledAmount)
+	fields := map[string]string{
+		"level_id":    strconv.Itoa(level.ID),
+		"order_id":    orderID,
+		"price":       shownPrice.String(),
+		"amount":      shownAmount.String(),
+		"amount_usdt": sellAmountUSDT.String(),
+	}
+	message := fmt.Sprintf("Sold %s %s at %s (%s USDT), level %d", shownAmount, level.Symbol, shownPrice, sellAmountUSDT, level.ID)
+	if stop != nil {
+		fields["stop_loss"] = "true"
+		message = fmt.Sprintf("Sold %s %s at %s (%s USDT) on its stop-loss, level %d", shownAmount, level.Symbol, shownPrice, sellAmountUSDT, level.ID)
+	}
+	if relatedBuyID != 0 {
+		fields["profit_usdt"] = profitUSDT.Round(8).String()
+		fields["profit_pct"] = profitPct.Round(2).String()
+		fields["fees_usdt"] = totalFees.Round(8).String()
+		message += fmt.Sprintf(", profit %s USDT (%s%%)", fields["profit_usdt"], fields["profit_pct"])
+	}
+	s.emit(contracts.EventSellFilled, level.Symbol, message, fields)
+
 	return nil
 }
 
-func (s *GridService) ProcessErrorNotification(orderID string, side string, errorMsg string) error {
+func (s *GridService) ProcessErrorNotification(orderID string, side string, errorCode string, errorMsg string) error {
 	var level *models.GridLevel
 	var err error
 
@@ -387,53 +920,305 @@ func (s *GridService) ProcessErrorNotification(orderID string, side string, erro
 	} else if side == "sell" {
 		level, err = s.repo.GetBySellOrderID(orderID)
 	} else {
-		log.Printf("ERROR: Invalid side '%s' for order %s in error notification", side, orderID)
+		slog.Error("Invalid side in error notification", "side", side, logging.KeyOrderID, orderID)
 		return fmt.Errorf("invalid side: %s", side)
 	}
 
 	if err != nil {
-		log.Printf("ERROR: Failed to get level by %s order ID %s: %v", side, orderID, err)
+		slog.Error("Failed to get level by order ID", "side", side, logging.KeyOrderID, orderID, logging.Err(err))
 		return fmt.Errorf("failed to get level by order ID: %w", err)
 	}
 
 	if level == nil {
-		log.Printf("WARNING: No level found for %s order %s (possibly old/deleted)", side, orderID)
+		slog.Warn("No level found for order (possibly old/deleted)", "side", side, logging.KeyOrderID, orderID)
 		return nil
 	}
 
-	log.Printf("ERROR: Order %s (%s) failed for level %d: %s", orderID, side, level.ID, errorMsg)
+	logger := levelLogger(level).With(logging.KeyOrderID, orderID, "side", side)
+	logger.Error("Order failed", "error_code", errorCode, logging.KeyError, errorMsg)
+
+	if errorCode == "" {
+		errorCode = "order_error"
+	}
 
 	if err := s.repo.UpdateState(level.ID, models.StateError); err != nil {
-		log.Printf("ERROR: Failed to update level %d to ERROR state: %v", level.ID, err)
+		logger.Error("Failed to update level to ERROR state", logging.Err(err))
 		return fmt.Errorf("failed to update state to ERROR: %w", err)
 	}
 
 	// Record error transaction
 	if side == "buy" {
-		if err := s.txRepo.RecordBuyError(level.ID, level.Symbol, level.BuyPrice, "order_error", errorMsg); err != nil {
-			log.Printf("WARNING: Failed to record buy error transaction for level %d: %v", level.ID, err)
+		if err := s.txRepo.RecordBuyError(level.ID, level.Symbol, level.BuyPrice, errorCode, errorMsg); err != nil {
+			logger.Warn("Failed to record buy error transaction", logging.Err(err))
+		}
+	} else {
+		if err := s.txRepo.RecordSellError(level.ID, level.Symbol, level.SellPrice, errorCode, errorMsg); err != nil {
+			logger.Warn("Failed to record sell error transaction", logging.Err(err))
+		}
+	}
+
+	logger.Info("Level set to ERROR state")
+
+	s.emit(contracts.EventOrderFailed, level.Symbol,
+		fmt.Sprintf("%s order %s failed for level %d, level set to ERROR: %s", strings.ToUpper(side), orderID, level.ID, errorMsg),
+		map[string]string{
+			"level_id":   strconv.Itoa(level.ID),
+			"order_id":   orderID,
+			"side":       side,
+			"error_code": errorCode,
+			"error":      errorMsg,
+		})
+	return nil
+}
+
+// ProcessRejectionNotification records an order that order-assurance rejected before placement.
+// The placement call already returned the level to READY/HOLDING, and recorded the rejection
+// unless the call itself failed (e.g. timed out); the error message matches the one that call
+// records, so the repository's duplicate check keeps a single ERROR transaction either way.
+func (s *GridService) ProcessRejectionNotification(account, symbol, side string, price decimal.Decimal, errorCode, errorMsg string) error {
+	levels, err := s.repo.GetBySymbol(symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get levels for symbol %s: %w", symbol, err)
+	}
+
+	var level *models.GridLevel
+	for _, candidate := range levels {
+		if candidate.Account != account {
+			continue
+		}
+		if (side == "buy" && candidate.BuyPrice.Equal(price)) || (side == "sell" && s.isSellOrderPrice(candidate, price)) {
+			level = candidate
+			break
+		}
+	}
+	if level == nil {
+		slog.Warn("No level found for rejected order", "side", side, logging.KeySymbol, symbol, "price", price, logging.KeyAccount, account)
+		return nil
+	}
+
+	if errorCode == "" {
+		errorCode = "order_error"
+	}
+	message := (&client.OrderError{Code: errorCode, Message: errorMsg}).Error()
+
+	if side == "buy" {
+		err = s.txRepo.RecordBuyError(level.ID, level.Symbol, level.BuyPrice, errorCode, message)
+	} else {
+		err = s.txRepo.RecordSellError(level.ID, level.Symbol, level.SellPrice, errorCode, message)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record rejection for level %d: %w", level.ID, err)
+	}
+	return nil
+}
+
+// ProcessCancelNotification handles an active order cancelled on the exchange (e.g. expired by
+// order-assurance TTL). The cancel is recorded, then the level is reset so it can be triggered
+// again: a buy that partly filled is applied as a fill of that part (HOLDING), a sell that
+// partly filled keeps only the unsold remainder (HOLDING). A cancelled tranche of a take-profit
+// ladder is placed again once the level's other tranches are done.
+func (s *GridService) ProcessCancelNotification(orderID, side string, filledAmount, fillPrice, commission decimal.Decimal, commissionAsset, baseAsset string) error {
+	var level *models.GridLevel
+	var err error
+	expectedState := models.StateSellActive
+	targetState := models.StateHolding
+	txSide := models.SideSell
+
+	if side == "buy" {
+		level, err = s.repo.GetByBuyOrderID(orderID)
+		expectedState = models.StateBuyActive
+		targetState = models.StateReady
+		txSide = models.SideBuy
+	} else {
+		var tranche *models.SellTranche
+		if tranche, err = s.trancheOf(orderID); err != nil {
+			return err
+		}
+		if tranche != nil {
+			return s.processTrancheCancel(tranche, orderID, filledAmount, fillPrice, commission, commissionAsset, baseAsset)
+		}
+		level, err = s.repo.GetBySellOrderID(orderID)
+	}
+
+	if err != nil {
+		slog.Error("Failed to get level by order ID", "side", side, logging.KeyOrderID, orderID, logging.Err(err))
+		return fmt.Errorf("failed to get level by order ID: %w", err)
+	}
+
+	if level == nil {
+		slog.Warn("No level found for order (possibly old/deleted)", "side", side, logging.KeyOrderID, orderID)
+		return nil
+	}
+
+	logger := levelLogger(level).With(logging.KeyOrderID, orderID, "side", side)
+	if level.State != expectedState {
+		logger.Warn("Level not in expected state for cancelled order, skipping", "expected", expectedState, "state", level.State)
+		return nil
+	}
+
+	// Binance expires the limit leg of an OCO when its stop-loss fills
+	if side == "sell" && !filledAmount.IsPositive() && s.applyStopLegFill(level, orderID) {
+		return nil
+	}
+
+	targetPrice := level.BuyPrice
+	remaining := decimal.Zero
+	if side == "sell" {
+		targetPrice = level.SellPrice
+		if level.FilledAmount.Valid {
+			remaining = level.FilledAmount.Decimal.Sub(filledAmount)
+		}
+		// Everything sold before the cancel landed - it is a complete fill
+		if filledAmount.IsPositive() && !remaining.IsPositive() {
+			return s.ProcessSellFillNotification(orderID, filledAmount, fillPrice, commission, commissionAsset, baseAsset)
 		}
+	}
+
+	// Record transaction FIRST (audit trail before state change)
+	if err := s.txRepo.RecordCancelled(level.ID, level.Symbol, txSide, orderID, targetPrice, fillPrice, filledAmount); err != nil {
+		return fmt.Errorf("failed to record cancel of order %s: %w", orderID, err)
+	}
+
+	if filledAmount.IsPositive() {
+		if side == "buy" {
+			logger.Warn("Buy order cancelled after filling, applying the filled part", "filled", filledAmount)
+			return s.ProcessBuyFillNotification(orderID, filledAmount, fillPrice, commission, commissionAsset, baseAsset)
+		}
+
+		logger.Warn("Sell order cancelled after selling, level keeps the unsold amount", "sold", filledAmount, "unsold", remaining)
+		if err := s.repo.KeepUnsold(level.ID, remaining); err != nil {
+			return fmt.Errorf("failed to keep unsold amount of level %d after cancel: %w", level.ID, err)
+		}
+		return nil
+	}
+
+	logger.Warn("Order cancelled on exchange, resetting level", "state", targetState)
+	if err := s.repo.UpdateState(level.ID, targetState); err != nil {
+		return fmt.Errorf("failed to reset level %d after cancel: %w", level.ID, err)
+	}
+
+	return nil
+}
+
+// ProcessPlacedNotification attaches an order placed on the exchange whose placement response
+// never arrived (order-assurance restarted mid-request) to the level it was placed for
+func (s *GridService) ProcessPlacedNotification(orderID, symbol, side, account string, price decimal.Decimal) error {
+	var tracked *models.GridLevel
+	var err error
+	if side == "buy" {
+		tracked, err = s.repo.GetByBuyOrderID(orderID)
 	} else {
-		if err := s.txRepo.RecordSellError(level.ID, level.Symbol, level.SellPrice, "order_error", errorMsg); err != nil {
-			log.Printf("WARNING: Failed to record sell error transaction for level %d: %v", level.ID, err)
+		tracked, err = s.repo.GetBySellOrderID(orderID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get level by order ID: %w", err)
+	}
+	if tracked != nil {
+		levelLogger(tracked).Info("Order already tracked", logging.KeyOrderID, orderID)
+		return nil
+	}
+
+	levels, err := s.repo.GetBySymbol(symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get levels for %s: %w", symbol, err)
+	}
+
+	for _, level := range levels {
+		if level.Account != account {
+			continue
+		}
+
+		if side == "buy" && level.BuyPrice.Equal(price) {
+			return s.adoptBuyOrder(level, orderID)
+		}
+		if side == "sell" && s.isSellOrderPrice(level, price) {
+			return s.adoptSellOrder(level, orderID)
+		}
+	}
+
+	slog.Warn("No level for recovered order - check the exchange manually", logging.KeySymbol, symbol, "price", price,
+		"side", side, logging.KeyOrderID, orderID, logging.KeyAccount, account)
+	return nil
+}
+
+func (s *GridService) adoptBuyOrder(level *models.GridLevel, orderID string) error {
+	if level.State == models.StateReady {
+		amount := s.buyAmountFor(level)
+		started, err := s.repo.TryStartBuyOrder(level.ID, amount)
+		if err != nil {
+			return fmt.Errorf("failed to start buy order: %w", err)
 		}
+		if started {
+			level.State = models.StatePlacingBuy
+			level.OrderAmount = decimal.NewNullDecimal(amount)
+		}
+	}
+
+	logger := levelLogger(level).With(logging.KeyOrderID, orderID)
+	if level.State != models.StatePlacingBuy {
+		logger.Warn("Recovered buy order may be a duplicate - check the exchange manually", "state", level.State)
+		return nil
+	}
+
+	if err := s.repo.UpdateBuyOrderPlaced(level.ID, orderID); err != nil {
+		return fmt.Errorf("failed to update buy order placed: %w", err)
+	}
+
+	if err := s.txRepo.RecordBuyPlaced(level.ID, level.Symbol, orderID, level.BuyPrice, level.CycleAmount()); err != nil {
+		logger.Warn("Failed to record buy placed transaction", logging.Err(err))
+	}
+
+	logger.Info("Recovered buy order")
+	return nil
+}
+
+func (s *GridService) adoptSellOrder(level *models.GridLevel, orderID string) error {
+	if level.State == models.StateHolding {
+		started, err := s.repo.TryStartSellOrder(level.ID)
+		if err != nil {
+			return fmt.Errorf("failed to start sell order: %w", err)
+		}
+		if started {
+			level.State = models.StatePlacingSell
+		}
+	}
+
+	logger := levelLogger(level).With(logging.KeyOrderID, orderID)
+	if level.State != models.StatePlacingSell {
+		logger.Warn("Recovered sell order may be a duplicate - check the exchange manually", "state", level.State)
+		return nil
+	}
+
+	if err := s.repo.UpdateSellOrderPlaced(level.ID, orderID); err != nil {
+		return fmt.Errorf("failed to update sell order placed: %w", err)
+	}
+
+	if err := s.txRepo.RecordSellPlaced(level.ID, level.Symbol, orderID, level.SellPrice, level.FilledAmount.Decimal); err != nil {
+		logger.Warn("Failed to record sell placed transaction", logging.Err(err))
 	}
 
-	log.Printf("INFO: Level %d set to ERROR state: %s", level.ID, errorMsg)
+	logger.Info("Recovered sell order")
 	return nil
 }
 
 func (s *GridService) SyncOrders() error {
-	stuckLevels, err := s.repo.GetStuckInPlacingState(5 * time.Minute)
+	// Recovery retries placements - not before an operator has resumed
+	if inSafeMode, _ := s.SafeMode(); inSafeMode {
+		slog.Info("Safe mode, skipping sync job")
+		return nil
+	}
+
+	stuckLevels, err := s.syncStuckLevels()
 	if err != nil {
-		log.Printf("ERROR: Failed to get stuck levels in sync job: %v", err)
+		slog.Error("Failed to get stuck levels in sync job", logging.Err(err))
 		return fmt.Errorf("failed to get stuck levels: %w", err)
 	}
 
-	log.Printf("INFO: Sync job checking %d stuck levels", len(stuckLevels))
+	slog.Info("Sync job checking stuck levels", "levels", len(stuckLevels))
 
 	for _, level := range stuckLevels {
-		log.Printf("INFO: Recovering stuck level %d in state %s", level.ID, level.State)
+		logger := levelLogger(level)
+		logger.Info("Recovering stuck level", "state", level.State)
 
 		if level.State == models.StatePlacingBuy {
 			if level.BuyOrderID.Valid {
@@ -441,17 +1226,21 @@ func (s *GridService) SyncOrders() error {
 			} else {
 				// Retry order placement (idempotent)
 				orderReq := client.OrderRequest{
-					Symbol: level.Symbol,
-					Price:  level.BuyPrice,
-					Side:   client.OrderSideBuy,
-					Amount: level.BuyAmount,
+					Symbol:    level.Symbol,
+					Price:     level.BuyPrice,
+					Side:      client.OrderSideBuy,
+					Amount:    level.CycleAmount(),
+					Account:   level.Account,
+					OrderType: s.orderType(level.Symbol),
+
+					QuoteOrderQty: s.quoteBuys,
 				}
-				if orderResp, err := s.assurance.PlaceOrder(orderReq); err == nil {
+				if orderResp, err := s.assurance.PlaceOrder(context.Background(), orderReq); err == nil {
 					s.repo.UpdateBuyOrderPlaced(level.ID, orderResp.OrderID)
-					log.Printf("SUCCESS: Recovered buy order %s for level %d", orderResp.OrderID, level.ID)
+					logger.Info("Recovered buy order", logging.KeyOrderID, orderResp.OrderID)
 				} else {
-					s.repo.UpdateState(level.ID, models.StateReady)
-					log.Printf("ERROR: Failed to recover buy order for level %d: %v", level.ID, err)
+					logger.Error("Failed to recover buy order", logging.Err(err))
+					s.failRecovery(level, models.StateReady, err)
 				}
 			}
 		} else if level.State == models.StatePlacingSell {
@@ -460,60 +1249,118 @@ func (s *GridService) SyncOrders() error {
 			} else if level.FilledAmount.Valid {
 				// Retry order placement (idempotent)
 				orderReq := client.OrderRequest{
-					Symbol: level.Symbol,
-					Price:  level.SellPrice,
-					Side:   client.OrderSideSell,
-					Amount: level.FilledAmount.Decimal,
+					Symbol:    level.Symbol,
+					Price:     s.sellOrderPrice(level),
+					Side:      client.OrderSideSell,
+					Amount:    level.FilledAmount.Decimal,
+					Account:   level.Account,
+					OrderType: s.orderType(level.Symbol),
 				}
-				if orderResp, err := s.assurance.PlaceOrder(orderReq); err == nil {
+				if orderResp, _, err := s.placeSell(context.Background(), level, orderReq); err == nil {
 					s.repo.UpdateSellOrderPlaced(level.ID, orderResp.OrderID)
-					log.Printf("SUCCESS: Recovered sell order %s for level %d", orderResp.OrderID, level.ID)
+					logger.Info("Recovered sell order", logging.KeyOrderID, orderResp.OrderID)
 				} else {
-					s.repo.UpdateState(level.ID, models.StateHolding)
-					log.Printf("ERROR: Failed to recover sell order for level %d: %v", level.ID, err)
+					logger.Error("Failed to recover sell order", logging.Err(err))
+					s.failRecovery(level, models.StateHolding, err)
 				}
 			} else {
-				log.Printf("WARNING: Level %d stuck in PLACING_SELL but no filled amount, resetting to HOLDING", level.ID)
+				logger.Warn("Level stuck in PLACING_SELL but no filled amount, resetting to HOLDING")
 				s.repo.UpdateState(level.ID, models.StateHolding)
 			}
 		}
 	}
 
-	activeLevels, err := s.repo.GetAllActive()
+	activeLevels, err := s.syncActiveLevels()
 	if err != nil {
-		log.Printf("ERROR: Failed to get active levels in sync job: %v", err)
+		slog.Error("Failed to get active levels in sync job", logging.Err(err))
 		return fmt.Errorf("failed to get active levels: %w", err)
 	}
 
-	log.Printf("INFO: Sync job checking %d active levels", len(activeLevels))
+	slog.Info("Sync job checking active levels", "levels", len(activeLevels))
+
+	s.syncActiveOrders(activeLevels)
+
+	slog.Info("Sync job completed", "stuck", len(stuckLevels), "active", len(activeLevels))
+	return nil
+}
 
-	for _, level := range activeLevels {
+// orderCheck is an active level's order awaiting a status check
+type orderCheck struct {
+	level   *models.GridLevel
+	orderID string
+	isBuy   bool
+}
+
+// syncActiveOrders checks all active orders with one batch request, falling back
+// to per-order checks if order-assurance can't serve the batch
+func (s *GridService) syncActiveOrders(levels []*models.GridLevel) {
+	var checks []orderCheck
+	for _, level := range levels {
 		if level.State == models.StateBuyActive && level.BuyOrderID.Valid {
-			log.Printf("DEBUG: Checking buy order %s status for level %d", level.BuyOrderID.String, level.ID)
-			s.checkAndUpdateOrderStatus(level, level.BuyOrderID.String, true)
+			checks = append(checks, orderCheck{level: level, orderID: level.BuyOrderID.String, isBuy: true})
 		} else if level.State == models.StateSellActive && level.SellOrderID.Valid {
-			log.Printf("DEBUG: Checking sell order %s status for level %d", level.SellOrderID.String, level.ID)
-			s.checkAndUpdateOrderStatus(level, level.SellOrderID.String, false)
+			checks = append(checks, orderCheck{level: level, orderID: level.SellOrderID.String, isBuy: false})
 		}
 	}
+	checks = append(checks, s.trancheChecks(levels)...)
 
-	log.Printf("INFO: Sync job completed - checked %d stuck + %d active levels", len(stuckLevels), len(activeLevels))
-	return nil
+	if len(checks) == 0 {
+		return
+	}
+
+	queries := make([]client.OrderStatusQuery, len(checks))
+	for i, check := range checks {
+		queries[i] = client.OrderStatusQuery{Symbol: check.level.Symbol, OrderID: check.orderID, Account: check.level.Account}
+	}
+
+	statuses, err := s.assurance.GetOrderStatuses(queries)
+	if err != nil {
+		slog.Warn("Batch order status failed, checking orders one by one", "orders", len(checks), logging.Err(err))
+		for _, check := range checks {
+			s.checkAndUpdateOrderStatus(check.level, check.orderID, check.isBuy)
+		}
+		return
+	}
+
+	for i, check := range checks {
+		status := statuses[i]
+		switch status.Status {
+		case client.OrderStatusError:
+			levelLogger(check.level).Error("Failed to get order status", logging.KeyOrderID, check.orderID, logging.KeyError, status.Error)
+		case client.OrderStatusNotFound:
+			s.applyOrderStatus(check.level, check.orderID, check.isBuy, nil)
+		default:
+			s.applyOrderStatus(check.level, check.orderID, check.isBuy, &status.OrderStatus)
+		}
+	}
 }
 
 func (s *GridService) checkAndUpdateOrderStatus(level *models.GridLevel, orderID string, isBuy bool) {
-	status, err := s.assurance.GetOrderStatus(level.Symbol, orderID)
+	status, err := s.assurance.GetOrderStatus(level.Account, level.Symbol, orderID)
 	if err != nil {
-		log.Printf("ERROR: Failed to get order status for %s (level %d): %v", orderID, level.ID, err)
+		levelLogger(level).Error("Failed to get order status", logging.KeyOrderID, orderID, logging.Err(err))
+		return
+	}
+
+	s.applyOrderStatus(level, orderID, isBuy, status)
+}
+
+// applyOrderStatus moves a level according to its order's exchange status (nil = not found)
+func (s *GridService) applyOrderStatus(level *models.GridLevel, orderID string, isBuy bool, status *client.OrderStatus) {
+	if !isBuy && (status == nil || status.Status == "cancelled") && s.applyTrancheGone(orderID, status) {
+		return
+	}
+	if !isBuy && status == nil && s.applyStopLegFill(level, orderID) {
 		return
 	}
 
+	logger := levelLogger(level).With(logging.KeyOrderID, orderID)
 	if status == nil {
 		targetState := models.StateHolding
 		if isBuy {
 			targetState = models.StateReady
 		}
-		log.Printf("WARNING: Order %s not found on exchange, resetting level %d to %s", orderID, level.ID, targetState)
+		logger.Warn("Order not found on exchange, resetting level", "state", targetState)
 		s.repo.UpdateState(level.ID, targetState)
 		return
 	}
@@ -521,23 +1368,27 @@ func (s *GridService) checkAndUpdateOrderStatus(level *models.GridLevel, orderID
 	switch status.Status {
 	case "filled":
 		if status.FilledAmount == nil || status.FillPrice == nil {
-			log.Printf("WARNING: Order %s marked as filled but missing fill details (level %d)", orderID, level.ID)
+			logger.Warn("Order marked as filled but missing fill details")
 			return
 		}
 
-		log.Printf("INFO: Order %s filled - Amount: %s @ %s (level %d)", orderID, *status.FilledAmount, *status.FillPrice, level.ID)
+		logger.Info("Order filled", "amount", *status.FilledAmount, "price", *status.FillPrice)
 		if isBuy {
-			s.ProcessBuyFillNotification(orderID, *status.FilledAmount, *status.FillPrice)
+			s.ProcessBuyFillNotification(orderID, *status.FilledAmount, *status.FillPrice, status.CommissionAmount(), status.CommissionAsset, status.BaseAsset)
 		} else {
-			s.ProcessSellFillNotification(orderID, *status.FilledAmount, *status.FillPrice)
+			s.ProcessSellFillNotification(orderID, *status.FilledAmount, *status.FillPrice, status.CommissionAmount(), status.CommissionAsset, status.BaseAsset)
 		}
 	case "cancelled":
-		targetState := models.StateHolding
+		// Applied like the cancel notification, so a part filled before the cancel isn't lost
+		// when the poll gets there first and the notification later finds the level moved
+		side := "sell"
 		if isBuy {
-			targetState = models.StateReady
+			side = "buy"
+		}
+		filled, price := partialFill(status)
+		if err := s.ProcessCancelNotification(orderID, side, filled, price, status.CommissionAmount(), status.CommissionAsset, status.BaseAsset); err != nil {
+			logger.Error("Failed to apply cancel of order", logging.Err(err))
 		}
-		log.Printf("WARNING: Order %s cancelled on exchange, resetting level %d to %s", orderID, level.ID, targetState)
-		s.repo.UpdateState(level.ID, targetState)
 	case "open":
 		side := "SELL"
 		targetPrice := level.SellPrice
@@ -545,19 +1396,80 @@ func (s *GridService) checkAndUpdateOrderStatus(level *models.GridLevel, orderID
 			side = "BUY"
 			targetPrice = level.BuyPrice
 		}
-		log.Printf("DEBUG: Order %s (%s) still open on exchange - Level: %d, Symbol: %s, Target: %s", orderID, side, level.ID, level.Symbol, targetPrice)
+		logger.Debug("Order still open on exchange", "side", side, "target", targetPrice)
 	default:
-		log.Printf("WARNING: Order %s has unknown status '%s' (level %d)", orderID, status.Status, level.ID)
+		logger.Warn("Order has unknown status", "status", status.Status)
+	}
+}
+
+// partialFill returns the amount and price a cancelled order filled before the cancel, zero if none
+func partialFill(status *client.OrderStatus) (filled, price decimal.Decimal) {
+	if status.FilledAmount != nil {
+		filled = *status.FilledAmount
+	}
+	if status.FillPrice != nil {
+		price = *status.FillPrice
+	}
+	return filled, price
+}
+
+// newFee values a fill's commission in USDT when it was charged in the quote or base asset.
+// Other assets (e.g. BNB) stay unpriced and profit falls back to the configured fee rate.
+func newFee(baseAsset string, commission decimal.Decimal, asset string, fillPrice decimal.Decimal) models.Fee {
+	fee := models.Fee{Commission: commission, Asset: strings.ToUpper(asset)}
+	switch {
+	case fee.Asset == "":
+	case fee.Asset == quoteAsset:
+		fee.USDT = decimal.NullDecimal{Decimal: commission, Valid: true}
+	case baseAsset != "" && fee.Asset == strings.ToUpper(baseAsset):
+		fee.USDT = decimal.NullDecimal{Decimal: commission.Mul(fillPrice), Valid: true}
+	}
+	return fee
+}
+
+// feeUSDT returns the actual fee when known, otherwise an estimate from the configured fee rate
+func (s *GridService) feeUSDT(actual decimal.NullDecimal, amountUSDT decimal.Decimal) decimal.Decimal {
+	if actual.Valid {
+		return actual.Decimal
+	}
+	return amountUSDT.Mul(decimal.NewFromFloat(s.tradingFee / 100))
+}
+
+// quoteAsset is the asset all grids are priced in
+const quoteAsset = "USDT"
+
+// chargedInCoin reports whether a buy's commission was taken in the bought coin: priced,
+// but not in the quote asset (newFee only prices quote and base commissions)
+func chargedInCoin(buyTx *models.Transaction) bool {
+	return buyTx.FeeUSDT.Valid && buyTx.CommissionAsset.Valid && buyTx.CommissionAsset.String != quoteAsset
+}
+
+// resolveBaseAsset returns the traded coin reported with a fill, asking order-assurance for the
+// symbol's rules when the sender left it out. Empty when unknown - the commission then stays unpriced.
+func (s *GridService) resolveBaseAsset(level *models.GridLevel, reported string) string {
+	if reported != "" {
+		return strings.ToUpper(reported)
+	}
+
+	assets, err := s.assurance.GetSymbolAssets(level.Account, level.Symbol)
+	if err != nil {
+		levelLogger(level).Warn("Failed to get base asset", logging.Err(err))
+		return ""
 	}
+	return strings.ToUpper(assets.BaseAsset)
 }
 
-// CreateGrid creates new grid levels for a symbol, only adding missing levels (idempotent)
-func (s *GridService) CreateGrid(symbol string, minPrice, maxPrice, gridStep, buyAmount decimal.Decimal) ([]*models.GridLevel, error) {
-	// Calculate the number of levels
-	priceRange := maxPrice.Sub(minPrice)
-	numLevels := priceRange.Div(gridStep).IntPart()
+// CreateGrid creates new grid levels for a symbol, only adding missing levels (idempotent).
+// gridType spaces the levels by gridStep in quote currency (arithmetic) or by gridStep percent (geometric).
+// buyMultiplier (with its maxBuyAmount cap) scales buys after consecutive fills, NULL keeps them flat.
+// tranches gives each new level a take-profit ladder instead of one sell at its sell price.
+func (s *GridService) CreateGrid(symbol, account, gridType string, minPrice, maxPrice, gridStep, buyAmount decimal.Decimal, buyMultiplier, maxBuyAmount decimal.NullDecimal, tranches []SellTrancheSpec) ([]*models.GridLevel, error) {
+	if len(tranches) > 0 && s.ladders == nil {
+		return nil, fmt.Errorf("take-profit ladders are not available")
+	}
 
-	if numLevels <= 0 {
+	prices := levelPrices(gridType, minPrice, maxPrice, gridStep)
+	if len(prices) == 0 {
 		return nil, fmt.Errorf("invalid grid parameters: no levels can be created")
 	}
 
@@ -567,26 +1479,23 @@ func (s *GridService) CreateGrid(symbol string, minPrice, maxPrice, gridStep, bu
 		log.Printf("Warning: failed to get existing levels for %s: %v", symbol, err)
 	}
 
-	// Create a map for quick lookup of existing levels
+	// Create a map for quick lookup of existing levels - the same prices on another account are a separate grid
 	existingMap := make(map[string]bool)
 	for _, level := range existingLevels {
+		if level.Account != account {
+			continue
+		}
 		key := fmt.Sprintf("%s-%s", level.BuyPrice.String(), level.SellPrice.String())
 		existingMap[key] = true
 	}
 
 	// Create new levels
-	levels := make([]*models.GridLevel, 0, int(numLevels))
+	levels := make([]*models.GridLevel, 0, len(prices))
 	skippedCount := 0
 	createdCount := 0
 
-	for i := int64(0); i < numLevels; i++ {
-		buyPrice := minPrice.Add(gridStep.Mul(decimal.NewFromInt(i)))
-		sellPrice := buyPrice.Add(gridStep)
-
-		// Skip if sell price exceeds max price
-		if sellPrice.GreaterThan(maxPrice) {
-			break
-		}
+	for _, price := range prices {
+		buyPrice, sellPrice := price[0], price[1]
 
 		// Check if this level already exists
 		key := fmt.Sprintf("%s-%s", buyPrice.String(), sellPrice.String())
@@ -596,14 +1505,17 @@ func (s *GridService) CreateGrid(symbol string, minPrice, maxPrice, gridStep, bu
 		}
 
 		level := &models.GridLevel{
-			Symbol:    symbol,
-			BuyPrice:  buyPrice,
-			SellPrice: sellPrice,
-			BuyAmount: buyAmount,
-			State:     models.StateReady,
-			Enabled:   true,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			Symbol:        symbol,
+			Account:       account,
+			BuyPrice:      buyPrice,
+			SellPrice:     sellPrice,
+			BuyAmount:     buyAmount,
+			BuyMultiplier: buyMultiplier,
+			MaxBuyAmount:  maxBuyAmount,
+			State:         models.StateReady,
+			Enabled:       true,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
 		}
 
 		// Insert the level
@@ -612,6 +1524,9 @@ func (s *GridService) CreateGrid(symbol string, minPrice, maxPrice, gridStep, bu
 			log.Printf("Failed to create level at buy=%s sell=%s: %v", buyPrice, sellPrice, err)
 			continue
 		}
+		if err := s.createLadder(level.ID, tranches); err != nil {
+			return levels, fmt.Errorf("failed to create sell tranches of level %d: %w", level.ID, err)
+		}
 
 		createdCount++
 		levels = append(levels, level)
@@ -621,35 +1536,85 @@ func (s *GridService) CreateGrid(symbol string, minPrice, maxPrice, gridStep, bu
 	return levels, nil
 }
 
-// GetGridLevels retrieves all grid levels for a specific symbol
-func (s *GridService) GetGridLevels(symbol string) ([]*models.GridLevel, error) {
-	return s.repo.GetBySymbol(symbol)
-}
+// GetGridSymbols retrieves all distinct symbols used in grid levels, plus the depeg
+// guard's peg symbol and the reporting currency's FX pair so price-monitor triggers them too
+func (s *GridService) GetGridSymbols() ([]string, error) {
+	symbols, err := s.repo.GetDistinctSymbols()
+	if err != nil {
+		return nil, err
+	}
 
-// GetAllGridLevels retrieves all grid levels
-func (s *GridService) GetAllGridLevels() ([]*models.GridLevel, error) {
-	return s.repo.GetAll()
+	for _, watched := range []string{s.pegSymbol, s.fxSymbol} {
+		if watched != "" && !containsSymbol(symbols, watched) {
+			symbols = append(symbols, watched)
+		}
+	}
+	return symbols, nil
 }
 
-// GetGridSymbols retrieves all distinct symbols used in grid levels
-func (s *GridService) GetGridSymbols() ([]string, error) {
-	return s.repo.GetDistinctSymbols()
+func containsSymbol(symbols []string, symbol string) bool {
+	for _, s := range symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
 }
 
 type StatusResponse struct {
-	Date              string             `json:"date"`
-	BuysToday         int                `json:"buys_today"`
-	SellsToday        int                `json:"sells_today"`
-	ProfitToday       decimal.Decimal    `json:"profit_today"`
-	ProfitThisWeek    decimal.Decimal    `json:"profit_this_week"`
-	ProfitThisMonth   decimal.Decimal    `json:"profit_this_month"`
-	ProfitAllTime     decimal.Decimal    `json:"profit_all_time"`
-	LastBuy           *TransactionInfo   `json:"last_buy,omitempty"`
-	LastSell          *TransactionInfo   `json:"last_sell,omitempty"`
-	LastPriceUpdate   *PriceUpdateInfo   `json:"last_price_update,omitempty"`
-	WaitingForBuy     int                `json:"waiting_for_buy"`
-	WaitingForSell    int                `json:"waiting_for_sell"`
-	ErrorsToday       int                `json:"errors_today"`
+	Date               string                 `json:"date"`
+	BuysToday          int                    `json:"buys_today"`
+	SellsToday         int                    `json:"sells_today"`
+	ProfitToday        decimal.Decimal        `json:"profit_today"`
+	ProfitThisWeek     decimal.Decimal        `json:"profit_this_week"`
+	ProfitThisMonth    decimal.Decimal        `json:"profit_this_month"`
+	ProfitAllTime      decimal.Decimal        `json:"profit_all_time"`
+	LastBuy            *TransactionInfo       `json:"last_buy,omitempty"`
+	LastSell           *TransactionInfo       `json:"last_sell,omitempty"`
+	LastPriceUpdate    *PriceUpdateInfo       `json:"last_price_update,omitempty"`
+	WaitingForBuy      int                    `json:"waiting_for_buy"`
+	WaitingForSell     int                    `json:"waiting_for_sell"`
+	ErrorsToday        int                    `json:"errors_today"`
+	TradingPausedUntil string                 `json:"trading_paused_until,omitempty"`
+	TradingPauseReason string                 `json:"trading_pause_reason,omitempty"`
+	SafeMode           bool                   `json:"safe_mode,omitempty"` // Booted into safe mode, waiting for POST /safe-mode/resume
+	SafeModeReason     string                 `json:"safe_mode_reason,omitempty"`
+	WatchOnly          bool                   `json:"watch_only,omitempty"` // WATCH_ONLY: no grid places real orders
+	UnrealizedPnL      decimal.Decimal        `json:"unrealized_pnl_usdt"`
+	DrawdownPct        decimal.Decimal        `json:"drawdown_pct"`
+	StalePrices        []string               `json:"stale_prices,omitempty"`       // Holding symbols without a fresh price
+	QuotePeg           *PegStatus             `json:"quote_peg,omitempty"`          // Depeg guard (DEPEG_THRESHOLD_PCT)
+	ReportingCurrency  *ReportingValues       `json:"reporting_currency,omitempty"` // Profit in REPORT_CURRENCY
+	CalendarPauses     []CalendarWindow       `json:"calendar_pauses,omitempty"`    // Trading calendar windows pausing buys now
+	LevelCache         *LevelCacheStats       `json:"level_cache,omitempty"`        // LEVEL_CACHE_TTL_SEC
+	OrderStatusCache   *OrderStatusCacheStats `json:"order_status_cache,omitempty"` // ORDER_STATUS_CACHE_TTL_SEC
+	VsBuyAndHold       *BenchmarkTotals       `json:"vs_buy_and_hold,omitempty"`
+	Capital            *CapitalReturn         `json:"capital,omitempty"` // ROI once deposits are recorded (POST /capital/flows)
+	Symbols            []SymbolStatus         `json:"symbols"`           // Per-symbol breakdown; the totals above are their sums
+}
+
+// SymbolStatus is one symbol's share of /status
+type SymbolStatus struct {
+	Symbol          string          `json:"symbol"`
+	BuysToday       int             `json:"buys_today"`
+	SellsToday      int             `json:"sells_today"`
+	ErrorsToday     int             `json:"errors_today"`
+	ProfitToday     decimal.Decimal `json:"profit_today"`
+	ProfitThisWeek  decimal.Decimal `json:"profit_this_week"`
+	ProfitThisMonth decimal.Decimal `json:"profit_this_month"`
+	ProfitAllTime   decimal.Decimal `json:"profit_all_time"`
+	WaitingForBuy   int             `json:"waiting_for_buy"`
+	WaitingForSell  int             `json:"waiting_for_sell"`
+	UnrealizedPnL   decimal.Decimal `json:"unrealized_pnl_usdt"` // 0 while the price is stale
+	StalePrice      bool            `json:"stale_price,omitempty"`
+}
+
+// BenchmarkTotals is the headline of GetBenchmark shown in /status
+type BenchmarkTotals struct {
+	GridReturnPct      decimal.Decimal `json:"grid_return_pct"`
+	HoldReturnPct      decimal.Decimal `json:"hold_return_pct"`
+	OutperformancePct  decimal.Decimal `json:"outperformance_pct"`
+	OutperformanceUSDT decimal.Decimal `json:"outperformance_usdt"`
 }
 
 type TransactionInfo struct {
@@ -667,19 +1632,109 @@ type PriceUpdateInfo struct {
 	UpdatedAt string          `json:"updated_at"`
 }
 
-func (s *GridService) GetStatus() (*StatusResponse, error) {
-	// Get daily stats
-	buys, sells, errors, profitToday, err := s.txRepo.GetDailyStats()
+// GetFeeStats reports trading fees paid
+func (s *GridService) GetFeeStats() (*models.FeeStats, error) {
+	stats, err := s.txRepo.GetFeeStats(reportPeriods(s.reportLoc, time.Now()))
 	if err != nil {
-		log.Printf("ERROR: GetStatus - GetDailyStats failed: %v", err)
-		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+		return nil, fmt.Errorf("failed to get fee stats: %w", err)
 	}
+	return stats, nil
+}
 
-	// Get profit stats
-	_, profitWeek, profitMonth, profitAllTime, err := s.txRepo.GetProfitStats()
+// GetTransactionsAfter pages through the transaction log for the analytics service
+func (s *GridService) GetTransactionsAfter(afterID, limit int) (*contracts.TransactionPage, error) {
+	txs, err := s.txRepo.GetTransactionsAfter(afterID, limit)
 	if err != nil {
-		log.Printf("ERROR: GetStatus - GetProfitStats failed: %v", err)
-		return nil, fmt.Errorf("failed to get profit stats: %w", err)
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	page := &contracts.TransactionPage{Transactions: make([]contracts.TransactionRecord, 0, len(txs)), NextAfterID: afterID}
+	for _, tx := range txs {
+		page.Transactions = append(page.Transactions, contracts.TransactionRecord{
+			ID:              tx.ID,
+			GridLevelID:     tx.GridLevelID,
+			Account:         tx.Account,
+			Symbol:          tx.Symbol,
+			LevelBuyPrice:   tx.LevelBuyPrice,
+			LevelSellPrice:  tx.LevelSellPrice,
+			Side:            string(tx.Side),
+			Status:          string(tx.Status),
+			OrderID:         tx.OrderID.String,
+			TargetPrice:     tx.TargetPrice,
+			ExecutedPrice:   nullDecimalPtr(tx.ExecutedPrice),
+			AmountCoin:      nullDecimalPtr(tx.AmountCoin),
+			AmountUSDT:      nullDecimalPtr(tx.AmountUSDT),
+			ProfitUSDT:      nullDecimalPtr(tx.ProfitUSDT),
+			Commission:      nullDecimalPtr(tx.Commission),
+			CommissionAsset: tx.CommissionAsset.String,
+			FeeUSDT:         nullDecimalPtr(tx.FeeUSDT),
+			ErrorCode:       tx.ErrorCode.String,
+			CreatedAt:       tx.CreatedAt,
+		})
+		page.NextAfterID = tx.ID
+	}
+	return page, nil
+}
+
+func nullDecimalPtr(d decimal.NullDecimal) *decimal.Decimal {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Decimal
+}
+
+// symbolStatuses merges the per-symbol stats, level counts and unrealized PnL, sorted by symbol.
+// A symbol appears once it has levels, transactions or held coins.
+func symbolStatuses(stats []*models.SymbolStats, counts map[string]models.LevelCounts, unrealized *UnrealizedPnL) []SymbolStatus {
+	bySymbol := make(map[string]*SymbolStatus)
+	get := func(symbol string) *SymbolStatus {
+		sym, ok := bySymbol[symbol]
+		if !ok {
+			sym = &SymbolStatus{Symbol: symbol}
+			bySymbol[symbol] = sym
+		}
+		return sym
+	}
+
+	for _, st := range stats {
+		sym := get(st.Symbol)
+		sym.BuysToday = st.BuysToday
+		sym.SellsToday = st.SellsToday
+		sym.ErrorsToday = st.ErrorsToday
+		sym.ProfitToday = st.ProfitToday
+		sym.ProfitThisWeek = st.ProfitThisWeek
+		sym.ProfitThisMonth = st.ProfitThisMonth
+		sym.ProfitAllTime = st.ProfitAllTime
+	}
+	for symbol, c := range counts {
+		sym := get(symbol)
+		sym.WaitingForBuy = c.Ready
+		sym.WaitingForSell = c.Holding
+	}
+	for _, pnl := range unrealized.Symbols {
+		sym := get(pnl.Symbol)
+		sym.StalePrice = pnl.Stale
+		if !pnl.Stale {
+			sym.UnrealizedPnL = pnl.UnrealizedUSDT
+		}
+	}
+
+	symbols := make([]SymbolStatus, 0, len(bySymbol))
+	for _, sym := range bySymbol {
+		symbols = append(symbols, *sym)
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Symbol < symbols[j].Symbol })
+	return symbols
+}
+
+func (s *GridService) GetStatus() (*StatusResponse, error) {
+	now := time.Now()
+
+	// Get fills, errors and profit per symbol
+	symbolStats, err := s.txRepo.GetSymbolStats(reportPeriods(s.reportLoc, now))
+	if err != nil {
+		log.Printf("ERROR: GetStatus - GetSymbolStats failed: %v", err)
+		return nil, fmt.Errorf("failed to get symbol stats: %w", err)
 	}
 
 	// Get last buy
@@ -696,13 +1751,19 @@ func (s *GridService) GetStatus() (*StatusResponse, error) {
 		return nil, fmt.Errorf("failed to get last sell: %w", err)
 	}
 
-	// Get level counts
-	holding, ready, err := s.repo.GetLevelCounts()
+	// Get level counts per symbol
+	levelCounts, err := s.repo.GetLevelCountsBySymbol()
 	if err != nil {
-		log.Printf("ERROR: GetStatus - GetLevelCounts failed: %v", err)
+		log.Printf("ERROR: GetStatus - GetLevelCountsBySymbol failed: %v", err)
 		return nil, fmt.Errorf("failed to get level counts: %w", err)
 	}
 
+	unrealized, err := s.GetUnrealizedPnL()
+	if err != nil {
+		log.Printf("ERROR: GetStatus - GetUnrealizedPnL failed: %v", err)
+		return nil, fmt.Errorf("failed to get unrealized pnl: %w", err)
+	}
+
 	// Get last price update
 	s.lastPriceMu.RLock()
 	var lastPriceUpdate *PriceUpdateInfo
@@ -717,17 +1778,56 @@ func (s *GridService) GetStatus() (*StatusResponse, error) {
 
 	// Build response
 	response := &StatusResponse{
-		Date:            time.Now().Format("2006-01-02"),
-		BuysToday:       buys,
-		SellsToday:      sells,
-		ProfitToday:     profitToday,
-		ProfitThisWeek:  profitWeek,
-		ProfitThisMonth: profitMonth,
-		ProfitAllTime:   profitAllTime,
-		LastPriceUpdate: lastPriceUpdate,
-		WaitingForBuy:   ready,
-		WaitingForSell:  holding,
-		ErrorsToday:     errors,
+		Date:             now.In(s.reportLoc).Format("2006-01-02"),
+		LastPriceUpdate:  lastPriceUpdate,
+		UnrealizedPnL:    unrealized.UnrealizedUSDT,
+		DrawdownPct:      unrealized.DrawdownPct,
+		StalePrices:      unrealized.StaleSymbols,
+		QuotePeg:         s.pegStatus(),
+		CalendarPauses:   s.calendarPauses(),
+		LevelCache:       s.LevelCacheStats(),
+		OrderStatusCache: s.OrderStatusCacheStats(),
+		Symbols:          symbolStatuses(symbolStats, levelCounts, unrealized),
+	}
+	for _, sym := range response.Symbols {
+		response.BuysToday += sym.BuysToday
+		response.SellsToday += sym.SellsToday
+		response.ErrorsToday += sym.ErrorsToday
+		response.ProfitToday = response.ProfitToday.Add(sym.ProfitToday)
+		response.ProfitThisWeek = response.ProfitThisWeek.Add(sym.ProfitThisWeek)
+		response.ProfitThisMonth = response.ProfitThisMonth.Add(sym.ProfitThisMonth)
+		response.ProfitAllTime = response.ProfitAllTime.Add(sym.ProfitAllTime)
+		response.WaitingForBuy += sym.WaitingForBuy
+		response.WaitingForSell += sym.WaitingForSell
+	}
+
+	if pausedUntil, reason := s.tradingPausedUntil(); !pausedUntil.IsZero() {
+		response.TradingPausedUntil = pausedUntil.Format(time.RFC3339)
+		response.TradingPauseReason = reason
+	}
+	response.SafeMode, response.SafeModeReason = s.SafeMode()
+	response.WatchOnly = s.watchOnlyAll
+	response.ReportingCurrency = s.reportingValues(response)
+
+	// The benchmark is informational - status works without it
+	if s.history != nil {
+		if benchmark, err := s.GetBenchmark(); err != nil {
+			log.Printf("WARNING: GetStatus - GetBenchmark failed: %v", err)
+		} else if benchmark.CapitalUSDT.IsPositive() {
+			response.VsBuyAndHold = &BenchmarkTotals{
+				GridReturnPct:      benchmark.GridReturnPct,
+				HoldReturnPct:      benchmark.HoldReturnPct,
+				OutperformancePct:  benchmark.OutperformancePct,
+				OutperformanceUSDT: benchmark.OutperformanceUSDT,
+			}
+		}
+	}
+
+	// ROI is informational as well
+	if capital, err := s.capitalReturn(response.ProfitAllTime.Add(response.UnrealizedPnL), now); err != nil {
+		log.Printf("WARNING: GetStatus - capital return failed: %v", err)
+	} else {
+		response.Capital = capital
 	}
 
 	// Add last buy info
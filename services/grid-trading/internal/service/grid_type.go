@@ -0,0 +1,55 @@
+package service
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Grid types: how CreateGrid spaces the levels between min and max price
+const (
+	GridTypeArithmetic = "arithmetic" // A fixed step in quote currency (default)
+	GridTypeGeometric  = "geometric"  // A fixed percentage of the level's buy price
+)
+
+// Geometric level prices are rounded to this many significant digits, keeping them
+// readable and the same on every CreateGrid call; order-assurance rounds to the tick size
+const geometricPriceDigits = 8
+
+// levelPrices returns the buy and sell price of each level from minPrice up, stopping
+// before a sell price would exceed maxPrice. Each level sells where the next one buys.
+// gridStep is the absolute step of an arithmetic grid and the percentage of a geometric one.
+func levelPrices(gridType string, minPrice, maxPrice, gridStep decimal.Decimal) [][2]decimal.Decimal {
+	var prices [][2]decimal.Decimal
+
+	if gridType != GridTypeGeometric {
+		numLevels := maxPrice.Sub(minPrice).Div(gridStep).IntPart()
+		for i := int64(0); i < numLevels; i++ {
+			buyPrice := minPrice.Add(gridStep.Mul(decimal.NewFromInt(i)))
+			sellPrice := buyPrice.Add(gridStep)
+			if sellPrice.GreaterThan(maxPrice) {
+				break
+			}
+			prices = append(prices, [2]decimal.Decimal{buyPrice, sellPrice})
+		}
+		return prices
+	}
+
+	// Prices are compounded unrounded, so rounding never adds up along the grid
+	factor := decimal.NewFromInt(1).Add(gridStep.Div(decimal.NewFromInt(100)))
+	price := minPrice
+	buyPrice := minPrice
+	for {
+		price = price.Mul(factor).Round(2 * geometricPriceDigits)
+		sellPrice := roundSignificant(price, geometricPriceDigits)
+		if sellPrice.GreaterThan(maxPrice) || !sellPrice.GreaterThan(buyPrice) {
+			return prices
+		}
+		prices = append(prices, [2]decimal.Decimal{buyPrice, sellPrice})
+		buyPrice = sellPrice
+	}
+}
+
+// roundSignificant rounds a positive price to the given number of significant digits
+func roundSignificant(d decimal.Decimal, digits int32) decimal.Decimal {
+	intDigits := int32(d.NumDigits()) + d.Exponent() // Digits left of the point, negative below 0.1
+	return d.Round(digits - intDigits)
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// jobProgressEvery limits how often a job's progress is written to the database
+const jobProgressEvery = time.Second
+
+var (
+	// ErrJobQueueFull is returned when JOB_QUEUE_SIZE jobs are already waiting for a worker
+	ErrJobQueueFull = errors.New("too many jobs queued")
+
+	// ErrJobCancelled is returned by Run for a job cancelled before it finished
+	ErrJobCancelled = errors.New("job cancelled")
+
+	// ErrJobFinished is returned when cancelling a job that already finished
+	ErrJobFinished = errors.New("job already finished")
+
+	// ErrJobElsewhere is returned when cancelling a job another instance runs
+	ErrJobElsewhere = errors.New("job runs on another instance")
+)
+
+// JobRepositoryInterface stores jobs and their progress
+type JobRepositoryInterface interface {
+	Create(job *models.Job) error
+	Start(id int) error
+	UpdateProgress(id, done, total int, message string) error
+	Finish(id int, status models.JobStatus, result []byte, errMsg string) error
+	Get(id int) (*models.Job, error)
+	List(kind string, status models.JobStatus, limit int) ([]*models.Job, error)
+	FailUnfinished(instanceID, reason string) (int64, error)
+	DeleteFinishedBefore(before time.Time) (int64, error)
+}
+
+// JobFunc is the work of a job. Its result is stored as JSON; long jobs report progress and
+// stop early through JobStep.
+type JobFunc func(ctx context.Context) (interface{}, error)
+
+// JobProgress reports how far a job got: done of total steps and what it is doing
+type JobProgress func(done, total int, message string)
+
+type jobProgressKey struct{}
+
+// JobStep reports progress to the job running ctx, if any, and returns ctx's error once the job
+// is cancelled - the caller stops there and returns what it has. Outside a job it only checks ctx.
+func JobStep(ctx context.Context, done, total int, message string) error {
+	if progress, ok := ctx.Value(jobProgressKey{}).(JobProgress); ok {
+		progress(done, total, message)
+	}
+	return ctx.Err()
+}
+
+// JobRunner runs long operations - simulations, reconciliations, kline downloads, bulk changes -
+// on a fixed pool of workers, so a burst of them can't starve triggers and fills. Every job is
+// stored with its progress and result for GET /jobs/{id}; jobs a restart interrupted are failed
+// on start, finished ones pruned after the retention.
+type JobRunner struct {
+	repo      JobRepositoryInterface
+	workers   int
+	retention time.Duration
+	queue     chan *queuedJob
+
+	// Stamped on its jobs, so instances sharing the database only fail and cancel their own
+	instanceID string
+
+	mu     sync.Mutex
+	active map[int]*queuedJob // Queued and running jobs
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type queuedJob struct {
+	id     int
+	kind   string
+	fn     JobFunc
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{} // Closed once the job finished; result and err are set then
+	result interface{}
+	err    error
+
+	savedAt time.Time // Last progress write
+}
+
+func NewJobRunner(repo JobRepositoryInterface, workers, queueSize int, retention time.Duration) *JobRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobRunner{
+		repo:      repo,
+		workers:   workers,
+		retention: retention,
+		queue:     make(chan *queuedJob, queueSize),
+		active:    make(map[int]*queuedJob),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// UseInstance names the instance the runner's jobs belong to, when instances share the database
+// (sharding). Call before Start.
+func (r *JobRunner) UseInstance(instanceID string) {
+	r.instanceID = instanceID
+}
+
+func (r *JobRunner) Start() {
+	if failed, err := r.repo.FailUnfinished(r.instanceID, "interrupted by a restart"); err != nil {
+		log.Printf("ERROR: Failed to fail interrupted jobs: %v", err)
+	} else if failed > 0 {
+		log.Printf("WARNING: %d jobs were interrupted by a restart and marked FAILED", failed)
+	}
+
+	log.Printf("Starting %d job workers", r.workers)
+	for i := 0; i < r.workers; i++ {
+		r.wg.Add(1)
+		go r.work()
+	}
+	r.wg.Add(1)
+	go r.prune()
+}
+
+// Stop cancels the running jobs and waits for the workers
+func (r *JobRunner) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// Submit queues a job and returns it right away
+func (r *JobRunner) Submit(kind string, params interface{}, actor string, fn JobFunc) (*models.Job, error) {
+	job, _, err := r.submit(kind, params, actor, fn)
+	return job, err
+}
+
+// Run queues a job and waits for it, returning what fn returned. A caller that stops waiting
+// (ctx done) leaves the job running; it can still be followed on GET /jobs/{id}.
+func (r *JobRunner) Run(ctx context.Context, kind string, params interface{}, actor string, fn JobFunc) (interface{}, error) {
+	_, queued, err := r.submit(kind, params, actor, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-queued.done:
+		return queued.result, queued.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("stopped waiting for job %d: %w", queued.id, ctx.Err())
+	}
+}
+
+func (r *JobRunner) submit(kind string, params interface{}, actor string, fn JobFunc) (*models.Job, *queuedJob, error) {
+	job := &models.Job{Kind: kind, Status: models.JobQueued, Actor: actor, InstanceID: r.instanceID}
+	if params != nil {
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode job params: %w", err)
+		}
+		job.Params = encoded
+	}
+	if err := r.repo.Create(job); err != nil {
+		return nil, nil, fmt.Errorf("failed to store job: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	queued := &queuedJob{id: job.ID, kind: kind, fn: fn, ctx: ctx, cancel: cancel, done: make(chan struct{})}
+
+	r.mu.Lock()
+	r.active[job.ID] = queued
+	r.mu.Unlock()
+
+	select {
+	case r.queue <- queued:
+	default:
+		r.forget(queued)
+		if err := r.repo.Finish(job.ID, models.JobFailed, nil, ErrJobQueueFull.Error()); err != nil {
+			log.Printf("ERROR: Failed to fail job %d: %v", job.ID, err)
+		}
+		return nil, nil, fmt.Errorf("%w (%d)", ErrJobQueueFull, cap(r.queue))
+	}
+
+	log.Printf("INFO: Queued %s job %d", kind, job.ID)
+	return job, queued, nil
+}
+
+// Get returns a job, nil if there is none with the ID
+func (r *JobRunner) Get(id int) (*models.Job, error) {
+	return r.repo.Get(id)
+}
+
+// List returns the newest jobs, optionally of one kind and status
+func (r *JobRunner) List(kind string, status models.JobStatus, limit int) ([]*models.Job, error) {
+	return r.repo.List(kind, status, limit)
+}
+
+// Cancel stops a queued or running job. A running job stops at its next step and keeps the
+// partial result. Only the instance running a job can cancel it: ErrJobElsewhere names the
+// one to ask, ErrJobFinished means there is nothing left to cancel.
+func (r *JobRunner) Cancel(job *models.Job) error {
+	r.mu.Lock()
+	queued, ok := r.active[job.ID]
+	r.mu.Unlock()
+	if !ok {
+		if job.InstanceID != r.instanceID && (job.Status == models.JobQueued || job.Status == models.JobRunning) {
+			return fmt.Errorf("%w: job %d runs on %s - cancel it there", ErrJobElsewhere, job.ID, job.InstanceID)
+		}
+		return fmt.Errorf("%w: job %d is %s", ErrJobFinished, job.ID, job.Status)
+	}
+
+	log.Printf("INFO: Cancelling %s job %d", queued.kind, job.ID)
+	queued.cancel()
+	return nil
+}
+
+func (r *JobRunner) work() {
+	defer r.wg.Done()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case queued := <-r.queue:
+			r.run(queued)
+		}
+	}
+}
+
+func (r *JobRunner) run(queued *queuedJob) {
+	defer r.forget(queued)
+
+	if queued.ctx.Err() != nil {
+		queued.err = ErrJobCancelled
+		r.finish(queued, models.JobCancelled, nil, "cancelled before it started")
+		return
+	}
+
+	if err := r.repo.Start(queued.id); err != nil {
+		log.Printf("ERROR: Failed to mark job %d running: %v", queued.id, err)
+	}
+	started := time.Now()
+	log.Printf("INFO: Running %s job %d", queued.kind, queued.id)
+
+	ctx := context.WithValue(queued.ctx, jobProgressKey{}, JobProgress(func(done, total int, message string) {
+		r.progress(queued, done, total, message)
+	}))
+	result, err := r.call(ctx, queued)
+	queued.result, queued.err = result, err
+
+	// A failed step returns a typed nil result, stored as none
+	var encoded []byte
+	if result != nil {
+		if encoded, err = json.Marshal(result); err != nil {
+			log.Printf("ERROR: Failed to encode the result of job %d: %v", queued.id, err)
+		}
+		if string(encoded) == "null" {
+			encoded = nil
+		}
+	}
+
+	switch {
+	case queued.ctx.Err() != nil:
+		queued.err = ErrJobCancelled
+		r.finish(queued, models.JobCancelled, encoded, "cancelled")
+	case queued.err != nil:
+		r.finish(queued, models.JobFailed, encoded, queued.err.Error())
+	default:
+		r.finish(queued, models.JobSucceeded, encoded, "")
+	}
+	log.Printf("INFO: %s job %d finished in %s", queued.kind, queued.id, time.Since(started).Round(time.Millisecond))
+}
+
+// call runs a job's work, turning a panic into a failure instead of taking the worker down
+func (r *JobRunner) call(ctx context.Context, queued *queuedJob) (result interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("ERROR: %s job %d panicked: %v", queued.kind, queued.id, p)
+			result, err = nil, fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+	return queued.fn(ctx)
+}
+
+func (r *JobRunner) progress(queued *queuedJob, done, total int, message string) {
+	if done < total && time.Since(queued.savedAt) < jobProgressEvery {
+		return
+	}
+	queued.savedAt = time.Now()
+	if err := r.repo.UpdateProgress(queued.id, done, total, message); err != nil {
+		log.Printf("WARNING: Failed to record progress of job %d: %v", queued.id, err)
+	}
+}
+
+func (r *JobRunner) finish(queued *queuedJob, status models.JobStatus, result []byte, errMsg string) {
+	if err := r.repo.Finish(queued.id, status, result, errMsg); err != nil {
+		log.Printf("ERROR: Failed to record the outcome of job %d: %v", queued.id, err)
+	}
+}
+
+func (r *JobRunner) forget(queued *queuedJob) {
+	r.mu.Lock()
+	delete(r.active, queued.id)
+	r.mu.Unlock()
+	queued.cancel()
+	close(queued.done)
+}
+
+// prune deletes finished jobs past the retention, hourly
+func (r *JobRunner) prune() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		if deleted, err := r.repo.DeleteFinishedBefore(time.Now().Add(-r.retention)); err != nil {
+			log.Printf("ERROR: Failed to prune jobs: %v", err)
+		} else if deleted > 0 {
+			log.Printf("INFO: Pruned %d finished jobs", deleted)
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
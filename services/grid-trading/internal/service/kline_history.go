@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/klines"
+)
+
+// klineDownloadLimit caps the candles one download asks Binance for - 200 requests of 1000
+const klineDownloadLimit = 200000
+
+// ErrKlineRequestRejected wraps kline requests with an invalid interval or range
+var ErrKlineRequestRejected = errors.New("kline request rejected")
+
+// KlineDownload reports klines downloaded with POST /klines/{symbol}/download
+type KlineDownload struct {
+	Symbol   string    `json:"symbol"`
+	Interval string    `json:"interval"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Added    int       `json:"added"`  // Downloaded now; the rest of the range was stored already
+	Stored   int       `json:"stored"` // Klines stored in [from, to) - the candle still open never is
+}
+
+// DownloadKlines stores a symbol's closed klines of [from, to), downloading only the ones missing
+func (s *GridService) DownloadKlines(symbol, interval string, from, to time.Time) (*KlineDownload, error) {
+	symbol = strings.ToUpper(symbol)
+	if err := s.checkKlineRequest(interval, from, to); err != nil {
+		return nil, err
+	}
+	if candles := to.Sub(from) / klines.IntervalDuration(interval); candles > klineDownloadLimit {
+		return nil, fmt.Errorf("%w: %d %s klines requested, at most %d per download - split the range",
+			ErrKlineRequestRejected, candles, interval, klineDownloadLimit)
+	}
+
+	added, err := s.klines.Sync(symbol, interval, from, to)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.klines.Range(symbol, interval, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("INFO: %s %s klines from %s to %s stored: %d (%d downloaded)",
+		symbol, interval, from.Format(time.RFC3339), to.Format(time.RFC3339), len(stored), added)
+	return &KlineDownload{Symbol: symbol, Interval: interval, From: from, To: to, Added: added, Stored: len(stored)}, nil
+}
+
+// GetKlines returns a symbol's stored klines of [from, to), oldest first, without downloading
+func (s *GridService) GetKlines(symbol, interval string, from, to time.Time) ([]klines.Kline, error) {
+	if err := s.checkKlineRequest(interval, from, to); err != nil {
+		return nil, err
+	}
+	stored, err := s.klines.Range(symbol, interval, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		stored = []klines.Kline{}
+	}
+	return stored, nil
+}
+
+func (s *GridService) checkKlineRequest(interval string, from, to time.Time) error {
+	if s.klines == nil {
+		return fmt.Errorf("klines are not stored")
+	}
+	if !klines.ValidInterval(interval) {
+		return fmt.Errorf("%w: interval must be one of %s", ErrKlineRequestRejected, strings.Join(klines.Intervals, ", "))
+	}
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		return fmt.Errorf("%w: from must be before to", ErrKlineRequestRejected)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// LevelCacheStats reports the level cache in /status
+type LevelCacheStats struct {
+	Symbols int   `json:"symbols"` // Symbols cached right now
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	TTLSec  int   `json:"ttl_sec"`
+}
+
+// levelCache keeps GetBySymbol results in memory, so a trigger doesn't read every level of
+// its symbol from SQLite. Every write through it drops the symbol it touches (all symbols if
+// the level isn't cached), so the service reads its own writes; writes made elsewhere - another
+// instance, a manual edit - show up once the entry is older than the TTL.
+type levelCache struct {
+	GridLevelRepositoryInterface
+	ttl time.Duration
+
+	mu       sync.Mutex
+	entries  map[string]*levelCacheEntry
+	symbolOf map[int]string // Symbol of each cached level, to drop it on a write by level ID
+	gen      uint64         // Bumped on every drop; a read that raced one is not cached
+	hits     int64
+	misses   int64
+}
+
+type levelCacheEntry struct {
+	levels   []*models.GridLevel
+	cachedAt time.Time
+}
+
+// UseLevelCache serves the levels of a symbol from memory for up to ttl
+func (s *GridService) UseLevelCache(ttl time.Duration) {
+	cache := &levelCache{
+		GridLevelRepositoryInterface: s.repo,
+		ttl:                          ttl,
+		entries:                      make(map[string]*levelCacheEntry),
+		symbolOf:                     make(map[int]string),
+	}
+	s.levelCache = cache
+	s.repo = cache
+}
+
+// LevelCacheStats returns nil without a level cache
+func (s *GridService) LevelCacheStats() *LevelCacheStats {
+	if s.levelCache == nil {
+		return nil
+	}
+	return s.levelCache.stats()
+}
+
+func (c *levelCache) stats() *LevelCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &LevelCacheStats{Symbols: len(c.entries), Hits: c.hits, Misses: c.misses, TTLSec: int(c.ttl / time.Second)}
+}
+
+func (c *levelCache) GetBySymbol(symbol string) ([]*models.GridLevel, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[symbol]; ok && time.Since(entry.cachedAt) < c.ttl {
+		c.hits++
+		c.mu.Unlock()
+		return copyLevels(entry.levels), nil
+	}
+	c.misses++
+	gen := c.gen
+	c.mu.Unlock()
+
+	levels, err := c.GridLevelRepositoryInterface.GetBySymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.gen == gen {
+		c.entries[symbol] = &levelCacheEntry{levels: copyLevels(levels), cachedAt: time.Now()}
+		for _, level := range levels {
+			c.symbolOf[level.ID] = symbol
+		}
+	}
+	c.mu.Unlock()
+	return levels, nil
+}
+
+// copyLevels keeps callers from changing the cached levels
+func copyLevels(levels []*models.GridLevel) []*models.GridLevel {
+	copied := make([]*models.GridLevel, len(levels))
+	for i, level := range levels {
+		level := *level
+		copied[i] = &level
+	}
+	return copied
+}
+
+func (c *levelCache) dropSymbol(symbol string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	if entry, ok := c.entries[symbol]; ok {
+		for _, level := range entry.levels {
+			delete(c.symbolOf, level.ID)
+		}
+		delete(c.entries, symbol)
+	}
+}
+
+func (c *levelCache) dropLevel(id int) {
+	c.mu.Lock()
+	symbol, ok := c.symbolOf[id]
+	if !ok {
+		c.gen++
+		c.entries = make(map[string]*levelCacheEntry)
+		c.symbolOf = make(map[int]string)
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+	c.dropSymbol(symbol)
+}
+
+func (c *levelCache) TryStartBuyOrder(id int, amount decimal.Decimal) (bool, error) {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.TryStartBuyOrder(id, amount)
+}
+
+func (c *levelCache) TryStartSellOrder(id int) (bool, error) {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.TryStartSellOrder(id)
+}
+
+func (c *levelCache) UpdateState(id int, state models.GridState) error {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.UpdateState(id, state)
+}
+
+func (c *levelCache) AddRecoveryFailure(id int) (int, error) {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.AddRecoveryFailure(id)
+}
+
+func (c *levelCache) Quarantine(id int, from models.GridState, reason string) (bool, error) {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.Quarantine(id, from, reason)
+}
+
+func (c *levelCache) UpdateBuyOrderPlaced(id int, orderID string) error {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.UpdateBuyOrderPlaced(id, orderID)
+}
+
+func (c *levelCache) UpdateSellOrderPlaced(id int, orderID string) error {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.UpdateSellOrderPlaced(id, orderID)
+}
+
+func (c *levelCache) ProcessBuyFill(id int, filledAmount decimal.Decimal) error {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.ProcessBuyFill(id, filledAmount)
+}
+
+func (c *levelCache) ProcessSellFill(id int) error {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.ProcessSellFill(id)
+}
+
+func (c *levelCache) KeepUnsold(id int, remaining decimal.Decimal) error {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.KeepUnsold(id, remaining)
+}
+
+func (c *levelCache) ReplaceSellOrder(id int, orderID string, remaining decimal.Decimal) error {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.ReplaceSellOrder(id, orderID, remaining)
+}
+
+func (c *levelCache) SeedHolding(id int, filledAmount, costUSDT decimal.Decimal) (bool, error) {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.SeedHolding(id, filledAmount, costUSDT)
+}
+
+func (c *levelCache) SetEnabled(id int, enabled bool) (bool, error) {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.SetEnabled(id, enabled)
+}
+
+func (c *levelCache) SetSymbolEnabled(symbol string, enabled bool) (int, error) {
+	defer c.dropSymbol(symbol)
+	return c.GridLevelRepositoryInterface.SetSymbolEnabled(symbol, enabled)
+}
+
+func (c *levelCache) UpdateSettings(id int, buyPrice, sellPrice, buyAmount decimal.Decimal, enabled bool) (bool, error) {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.UpdateSettings(id, buyPrice, sellPrice, buyAmount, enabled)
+}
+
+func (c *levelCache) UpdateNotes(id int, tags []string, notes string) (bool, error) {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.UpdateNotes(id, tags, notes)
+}
+
+func (c *levelCache) Recover(id int, to models.GridState) (bool, error) {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.Recover(id, to)
+}
+
+func (c *levelCache) ArchiveSymbol(symbol string) (int, error) {
+	defer c.dropSymbol(symbol)
+	return c.GridLevelRepositoryInterface.ArchiveSymbol(symbol)
+}
+
+func (c *levelCache) ArchiveLevel(id int) (bool, error) {
+	defer c.dropLevel(id)
+	return c.GridLevelRepositoryInterface.ArchiveLevel(id)
+}
+
+func (c *levelCache) Create(level *models.GridLevel) error {
+	defer c.dropSymbol(level.Symbol)
+	return c.GridLevelRepositoryInterface.Create(level)
+}
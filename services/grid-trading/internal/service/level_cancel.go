@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// ErrNoOpenOrder means a level has no order open on the exchange to cancel
+var ErrNoOpenOrder = errors.New("level has no open order")
+
+// LevelCancelResult reports the order of a level cancelled on an operator's request
+type LevelCancelResult struct {
+	LevelID     int    `json:"level_id"`
+	OrderID     string `json:"order_id"`
+	Side        string `json:"side"`         // buy | sell
+	OrderStatus string `json:"order_status"` // cancelled, filled when the fill beat the cancel, empty if unknown
+	Pending     bool   `json:"pending"`      // The exchange status wasn't read back; the cancel notification moves the level
+
+	Level *models.GridLevel `json:"level"` // The level after the cancel
+}
+
+// CancelLevelOrder cancels the open order of one level through order-assurance and moves the
+// level as the cancel notification would: a buy back to READY, a sell back to HOLDING, a partly
+// filled order as its filled part, and an order that filled first as a fill. The notification
+// arriving later finds the level moved and changes nothing. Returns nil if the level does not exist.
+func (s *GridService) CancelLevelOrder(id int) (*LevelCancelResult, error) {
+	level, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get level %d: %w", id, err)
+	}
+	if level == nil {
+		return nil, nil
+	}
+
+	orderID := openOrderID(level)
+	if orderID == "" {
+		return nil, fmt.Errorf("%w: level %d is %s", ErrNoOpenOrder, id, level.State)
+	}
+	isBuy := level.State == models.StateBuyActive
+	result := &LevelCancelResult{LevelID: id, OrderID: orderID, Side: "sell"}
+	if isBuy {
+		result.Side = "buy"
+	}
+
+	logger := levelLogger(level).With(logging.KeyOrderID, orderID, "side", result.Side)
+	logger.Info("Cancelling order on request")
+	if err := s.assurance.CancelOrder(level.Account, level.Symbol, orderID); err != nil {
+		return nil, fmt.Errorf("failed to cancel order %s of level %d: %w", orderID, id, err)
+	}
+
+	status, err := s.assurance.GetOrderStatus(level.Account, level.Symbol, orderID)
+	switch {
+	case err != nil:
+		logger.Warn("Cancelled order but failed to read its status, level moves on the cancel notification", logging.Err(err))
+		result.Pending = true
+	case status == nil || (status.Status != "cancelled" && status.Status != "filled"):
+		logger.Warn("Cancelled order not reported as cancelled yet, level moves on the cancel notification")
+		result.Pending = true
+		if status != nil {
+			result.OrderStatus = status.Status
+		}
+	case status.Status == "filled":
+		result.OrderStatus = status.Status
+		s.applyOrderStatus(level, orderID, isBuy, status)
+	default:
+		// order-assurance reports the part filled before the cancel, so the level keeps it even
+		// when this runs before the cancel notification
+		result.OrderStatus = status.Status
+		filled, price := partialFill(status)
+		if err := s.ProcessCancelNotification(orderID, result.Side, filled, price, status.CommissionAmount(), status.CommissionAsset, status.BaseAsset); err != nil {
+			return nil, fmt.Errorf("failed to apply cancel of order %s to level %d: %w", orderID, id, err)
+		}
+	}
+
+	if result.Level, err = s.repo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("failed to get level %d: %w", id, err)
+	}
+	return result, nil
+}
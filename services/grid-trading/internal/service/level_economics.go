@@ -0,0 +1,77 @@
+package service
+
+import (
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// LevelDetails is a level as the levels API returns it: the stored level plus what one
+// cycle is expected to earn under the current TRADING_FEE
+type LevelDetails struct {
+	*models.GridLevel
+	SellOrderPrice     decimal.Decimal `json:"sell_order_price"`      // Price the sell is placed at (above sell_price with FEE_AWARE_SELL)
+	RoundTripFeeUSDT   decimal.Decimal `json:"round_trip_fee_usdt"`   // TRADING_FEE on the buy and on the sell
+	NetProfitUSDT      decimal.Decimal `json:"net_profit_usdt"`       // Sell proceeds less the buy and both fees
+	BreakEvenSellPrice decimal.Decimal `json:"break_even_sell_price"` // Lowest sell price that doesn't lose money
+	Marginal           bool            `json:"marginal"`              // The cycle doesn't beat its fees
+}
+
+// GetGridLevels retrieves all grid levels for a specific symbol
+func (s *GridService) GetGridLevels(symbol string) ([]*LevelDetails, error) {
+	levels, err := s.repo.GetBySymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return s.levelDetails(levels), nil
+}
+
+// GetAllGridLevels retrieves all grid levels
+func (s *GridService) GetAllGridLevels() ([]*LevelDetails, error) {
+	levels, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return s.levelDetails(levels), nil
+}
+
+func (s *GridService) levelDetails(levels []*models.GridLevel) []*LevelDetails {
+	details := make([]*LevelDetails, len(levels))
+	for i, level := range levels {
+		details[i] = s.describeLevel(level)
+	}
+	return details
+}
+
+// describeLevel estimates one cycle of a level from the USDT its buy spends (order_amount of
+// the current cycle, otherwise buy_amount). The break-even price B solves
+//
+//	B × (1 − fee) = buy_price × (1 + fee)
+//
+// rounded up to the 8 decimals prices are stored with.
+func (s *GridService) describeLevel(level *models.GridLevel) *LevelDetails {
+	details := &LevelDetails{
+		GridLevel:          level,
+		SellOrderPrice:     s.sellOrderPrice(level),
+		BreakEvenSellPrice: level.BuyPrice,
+	}
+
+	amount := level.BuyAmount
+	if level.OrderAmount.Valid {
+		amount = level.OrderAmount.Decimal
+	}
+	if !level.BuyPrice.IsPositive() {
+		return details
+	}
+
+	one := decimal.NewFromInt(1)
+	fee := decimal.NewFromFloat(s.tradingFee).Div(decimal.NewFromInt(100))
+	if fee.LessThan(one) {
+		details.BreakEvenSellPrice = level.BuyPrice.Mul(one.Add(fee)).Div(one.Sub(fee)).RoundCeil(8)
+	}
+
+	proceeds := amount.Div(level.BuyPrice).Mul(details.SellOrderPrice)
+	details.RoundTripFeeUSDT = amount.Add(proceeds).Mul(fee).Round(8)
+	details.NetProfitUSDT = proceeds.Sub(amount).Sub(details.RoundTripFeeUSDT).Round(8)
+	details.Marginal = !details.NetProfitUSDT.IsPositive()
+	return details
+}
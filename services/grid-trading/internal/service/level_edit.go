@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrLevelEditRejected wraps level edits with invalid values
+	ErrLevelEditRejected = errors.New("level edit rejected")
+
+	// ErrLevelEditConflict wraps level edits the level's state or another level conflicts with
+	ErrLevelEditConflict = errors.New("level edit conflicts")
+)
+
+// LevelEditRequest changes some settings of one level; fields left out keep their value
+type LevelEditRequest struct {
+	BuyPrice  decimal.NullDecimal `json:"buy_price,omitempty"`
+	SellPrice decimal.NullDecimal `json:"sell_price,omitempty"`
+	BuyAmount decimal.NullDecimal `json:"buy_amount,omitempty"` // USDT per buy
+	Enabled   *bool               `json:"enabled,omitempty"`
+}
+
+// EditLevel applies a LevelEditRequest to a level without an order in flight. A HOLDING level
+// sells at its new sell price; buy price and amount take effect from its next buy.
+// Returns nil if the level does not exist.
+func (s *GridService) EditLevel(id int, req LevelEditRequest) (*models.GridLevel, error) {
+	level, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get level %d: %w", id, err)
+	}
+	if level == nil {
+		return nil, nil
+	}
+	if !editableState(level.State) {
+		return nil, fmt.Errorf("%w: level %d is %s - wait until its order fills or is cancelled", ErrLevelEditConflict, id, level.State)
+	}
+
+	buyPrice, sellPrice, buyAmount, enabled := level.BuyPrice, level.SellPrice, level.BuyAmount, level.Enabled
+	if req.BuyPrice.Valid {
+		buyPrice = req.BuyPrice.Decimal
+	}
+	if req.SellPrice.Valid {
+		sellPrice = req.SellPrice.Decimal
+	}
+	if req.BuyAmount.Valid {
+		buyAmount = req.BuyAmount.Decimal
+	}
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	if !buyPrice.IsPositive() || !buyAmount.IsPositive() {
+		return nil, fmt.Errorf("%w: buy_price and buy_amount must be positive", ErrLevelEditRejected)
+	}
+	if !sellPrice.GreaterThan(buyPrice) {
+		return nil, fmt.Errorf("%w: sell_price must be above buy_price", ErrLevelEditRejected)
+	}
+
+	// (account, symbol, buy_price, sell_price) is unique
+	if !buyPrice.Equal(level.BuyPrice) || !sellPrice.Equal(level.SellPrice) {
+		levels, err := s.repo.GetBySymbol(level.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get levels: %w", err)
+		}
+		for _, other := range levels {
+			if other.ID != id && other.Account == level.Account && other.BuyPrice.Equal(buyPrice) && other.SellPrice.Equal(sellPrice) {
+				return nil, fmt.Errorf("%w: level %d already trades %s → %s", ErrLevelEditConflict, other.ID, buyPrice, sellPrice)
+			}
+		}
+	}
+
+	updated, err := s.repo.UpdateSettings(id, buyPrice, sellPrice, buyAmount, enabled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update level %d: %w", id, err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: level %d started placing an order - try again once it is filled", ErrLevelEditConflict, id)
+	}
+
+	log.Printf("INFO: Level %d edited: buy %s → %s, sell %s → %s, amount %s → %s, enabled %v → %v",
+		id, level.BuyPrice, buyPrice, level.SellPrice, sellPrice, level.BuyAmount, buyAmount, level.Enabled, enabled)
+	return s.repo.GetByID(id)
+}
+
+// editableState reports whether a level has no order placing or open
+func editableState(state models.GridState) bool {
+	return state == models.StateReady || state == models.StateHolding || state == models.StateError
+}
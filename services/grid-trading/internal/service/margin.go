@@ -0,0 +1,169 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// MarginInterestRepositoryInterface defines the margin interest ledger operations
+type MarginInterestRepositoryInterface interface {
+	Record(charge *models.MarginInterest) (bool, error)
+	GetLatestChargedAt() (time.Time, error)
+	GetTotalsUSDT(dayStart time.Time) (today, allTime decimal.Decimal, unvalued int, err error)
+}
+
+// MarginClient reads debts and interest of the margin account through order-assurance
+type MarginClient interface {
+	GetMarginDebts(account string) ([]client.MarginDebt, error)
+	GetMarginInterest(account string, since time.Time) ([]client.MarginInterest, error)
+}
+
+// LatestPriceSource values non-USDT assets
+type LatestPriceSource interface {
+	LatestPrice(symbol string) (decimal.Decimal, bool)
+}
+
+// MarginDebtStatus is a borrowed asset of the margin account
+type MarginDebtStatus struct {
+	client.MarginDebt
+	ValueUSDT decimal.NullDecimal `json:"value_usdt"` // Borrowed plus interest at the latest price (NULL = no price)
+}
+
+// MarginStatus is the debt and interest overview of the margin grids
+type MarginStatus struct {
+	Debts            []MarginDebtStatus `json:"debts"`
+	DebtUSDT         decimal.Decimal    `json:"debt_usdt"` // Valued debts only
+	InterestToday    decimal.Decimal    `json:"interest_today_usdt"`
+	InterestAllTime  decimal.Decimal    `json:"interest_all_time_usdt"`
+	UnvaluedInterest int                `json:"unvalued_interest"` // Charges recorded without a price, left out of the totals
+	RealizedProfit   decimal.Decimal    `json:"realized_profit_usdt"`
+	NetProfit        decimal.Decimal    `json:"net_profit_usdt"` // Realized profit minus interest paid
+	LastSyncAt       *time.Time         `json:"last_sync_at,omitempty"`
+}
+
+// MarginMonitor keeps the interest ledger of the margin account, so what borrowing
+// costs is deducted from what the margin grids earn
+type MarginMonitor struct {
+	interest  MarginInterestRepositoryInterface
+	profits   RealizedProfitSource
+	assurance MarginClient
+	prices    LatestPriceSource
+
+	mu         sync.Mutex // One sync at a time (cron and manual trigger)
+	lastSyncAt time.Time
+
+	reportLoc *time.Location // Timezone of interest_today_usdt
+}
+
+func NewMarginMonitor(interest MarginInterestRepositoryInterface, profits RealizedProfitSource, assurance MarginClient, prices LatestPriceSource) *MarginMonitor {
+	return &MarginMonitor{
+		interest:  interest,
+		profits:   profits,
+		assurance: assurance,
+		prices:    prices,
+	}
+}
+
+// Sync records new interest charges, valued in USDT at the latest price
+func (m *MarginMonitor) Sync() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	since, err := m.interest.GetLatestChargedAt()
+	if err != nil {
+		return fmt.Errorf("failed to get latest interest charge: %w", err)
+	}
+
+	// The newest charge is fetched again - its income ID keeps it from being counted twice
+	charges, err := m.assurance.GetMarginInterest(client.MarginAccount, since)
+	if err != nil {
+		return fmt.Errorf("failed to get margin interest: %w", err)
+	}
+
+	recorded := 0
+	for _, charge := range charges {
+		record := &models.MarginInterest{
+			IncomeID:  charge.ID,
+			Symbol:    charge.Symbol,
+			Asset:     charge.Asset,
+			Amount:    charge.Amount,
+			ChargedAt: charge.ChargedAt,
+		}
+		if value, ok := m.valueUSDT(charge.Asset, charge.Amount); ok {
+			record.AmountUSDT = decimal.NewNullDecimal(value)
+		} else {
+			log.Printf("WARNING: No recent %s price, interest charge %s recorded without a USDT value", charge.Asset, charge.ID)
+		}
+
+		ok, err := m.interest.Record(record)
+		if err != nil {
+			return err
+		}
+		if ok {
+			recorded++
+		}
+	}
+	if recorded > 0 {
+		log.Printf("INFO: Recorded %d new margin interest charges", recorded)
+	}
+
+	m.lastSyncAt = time.Now().UTC()
+	return nil
+}
+
+// GetStatus returns the margin account's debts and the grids' profit net of interest
+func (m *MarginMonitor) GetStatus() (*MarginStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	debts, err := m.assurance.GetMarginDebts(client.MarginAccount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get margin debts: %w", err)
+	}
+
+	status := &MarginStatus{Debts: []MarginDebtStatus{}}
+	for _, debt := range debts {
+		entry := MarginDebtStatus{MarginDebt: debt}
+		if value, ok := m.valueUSDT(debt.Asset, debt.Borrowed.Add(debt.Interest)); ok {
+			entry.ValueUSDT = decimal.NewNullDecimal(value)
+			status.DebtUSDT = status.DebtUSDT.Add(value)
+		}
+		status.Debts = append(status.Debts, entry)
+	}
+
+	status.InterestToday, status.InterestAllTime, status.UnvaluedInterest, err = m.interest.GetTotalsUSDT(reportPeriods(m.reportLoc, time.Now()).DayStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get interest totals: %w", err)
+	}
+
+	profits, err := m.profits.GetRealizedProfitByAccount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get realized profit: %w", err)
+	}
+	status.RealizedProfit = profits[client.MarginAccount]
+	status.NetProfit = status.RealizedProfit.Sub(status.InterestAllTime)
+
+	if !m.lastSyncAt.IsZero() {
+		lastSyncAt := m.lastSyncAt
+		status.LastSyncAt = &lastSyncAt
+	}
+	return status, nil
+}
+
+// valueUSDT converts an amount of asset to USDT at the latest price of its USDT pair
+func (m *MarginMonitor) valueUSDT(asset string, amount decimal.Decimal) (decimal.Decimal, bool) {
+	if asset == quoteAsset {
+		return amount, true
+	}
+	price, ok := m.prices.LatestPrice(asset + quoteAsset)
+	if !ok {
+		return decimal.Zero, false
+	}
+	return amount.Mul(price).Round(8), true
+}
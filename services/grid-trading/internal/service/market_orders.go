@@ -0,0 +1,43 @@
+package service
+
+import (
+	"log/slog"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// orderType returns how a symbol's levels place their orders: order_type in its grid config,
+// limit while none is saved or it can't be read
+func (s *GridService) orderType(symbol string) client.OrderType {
+	if s.configs == nil {
+		return client.OrderTypeLimit
+	}
+	config, err := s.configs.Get(symbol)
+	if err != nil {
+		slog.Warn("Failed to read grid config, placing limit orders", logging.KeySymbol, symbol, logging.Err(err))
+		return client.OrderTypeLimit
+	}
+	if config == nil || config.OrderType == "" {
+		return client.OrderTypeLimit
+	}
+	return config.OrderType
+}
+
+// marketOrders reports whether a symbol's grid takes liquidity with MARKET orders instead of
+// resting LIMIT orders at its levels' prices
+func (s *GridService) marketOrders(symbol string) bool {
+	return s.orderType(symbol) == client.OrderTypeMarket
+}
+
+// canSell reports whether a price triggers a level's sell. A market sell fills at once, so a
+// level of a market grid waits for the price to reach its sell order price instead of resting
+// an order there.
+func (s *GridService) canSell(level *models.GridLevel, price decimal.Decimal, market bool) bool {
+	if market && price.LessThan(s.sellOrderPrice(level)) {
+		return false
+	}
+	return level.CanPlaceSell(price)
+}
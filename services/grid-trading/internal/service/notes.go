@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// ErrNotesRejected wraps tags or notes that can't be stored
+var ErrNotesRejected = errors.New("notes rejected")
+
+// Limits on the tags and notes of a grid or level
+const (
+	maxTags        = 10
+	maxTagLength   = 32
+	maxNotesLength = 1000
+)
+
+// NotesRequest changes the operator tags and notes of a level (PUT /levels/{id}/notes);
+// fields left out keep their value, an empty list or string clears them
+type NotesRequest struct {
+	Tags  *[]string `json:"tags,omitempty"`
+	Notes *string   `json:"notes,omitempty"`
+}
+
+// normalizeTags trims and lowercases tags, dropping duplicates. A tag can't be empty or hold a
+// comma (they are stored comma-separated).
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) > maxTags {
+		return nil, fmt.Errorf("at most %d tags", maxTags)
+	}
+	result := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		switch {
+		case tag == "":
+			return nil, fmt.Errorf("tags can't be empty")
+		case len(tag) > maxTagLength:
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+		case strings.Contains(tag, ","):
+			return nil, fmt.Errorf("tag %q can't contain a comma", tag)
+		}
+		if !hasTag(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// normalizeNotes trims notes, rejecting ones over the length limit
+func normalizeNotes(notes string) (string, error) {
+	notes = strings.TrimSpace(notes)
+	if len(notes) > maxNotesLength {
+		return "", fmt.Errorf("notes are longer than %d characters", maxNotesLength)
+	}
+	return notes, nil
+}
+
+// hasTag reports whether tags holds tag, compared case-insensitively
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLevelNotes applies a NotesRequest to a level in any state, nil if the level does not exist
+func (s *GridService) SetLevelNotes(id int, req NotesRequest) (*models.GridLevel, error) {
+	level, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get level %d: %w", id, err)
+	}
+	if level == nil {
+		return nil, nil
+	}
+
+	tags, notes := level.Tags, level.Notes.String
+	if req.Tags != nil {
+		if tags, err = normalizeTags(*req.Tags); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotesRejected, err)
+		}
+	}
+	if req.Notes != nil {
+		if notes, err = normalizeNotes(*req.Notes); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotesRejected, err)
+		}
+	}
+
+	updated, err := s.repo.UpdateNotes(id, tags, notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update notes of level %d: %w", id, err)
+	}
+	if !updated {
+		return nil, nil
+	}
+
+	log.Printf("INFO: Level %d notes updated: tags=%v, notes=%q", id, tags, notes)
+	return s.repo.GetByID(id)
+}
+
+// FilterLevelsByTag keeps the levels tagged with tag, all of them when tag is empty
+func FilterLevelsByTag(levels []*LevelDetails, tag string) []*LevelDetails {
+	if tag == "" {
+		return levels
+	}
+	filtered := []*LevelDetails{}
+	for _, level := range levels {
+		if hasTag(level.Tags, tag) {
+			filtered = append(filtered, level)
+		}
+	}
+	return filtered
+}
+
+// GetTaggedGridSymbols returns the symbols whose grid config is tagged with tag
+func (s *GridService) GetTaggedGridSymbols(tag string) ([]string, error) {
+	configs, err := s.configs.GetTagged(strings.ToLower(strings.TrimSpace(tag)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grids tagged %q: %w", tag, err)
+	}
+	symbols := []string{}
+	for _, config := range configs {
+		symbols = append(symbols, config.Symbol)
+	}
+	return symbols, nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// OrderStatusCacheStats reports the order status cache in /status
+type OrderStatusCacheStats struct {
+	Orders  int   `json:"orders"`  // Open orders cached right now
+	Checks  int64 `json:"checks"`  // Trigger status checks sent to order-assurance
+	Skipped int64 `json:"skipped"` // Trigger status checks answered from the cache
+	TTLSec  int   `json:"ttl_sec"`
+}
+
+// orderStatusCache remembers the orders a trigger found still open, so the next triggers don't
+// ask order-assurance about them again. An open order is checked again once the entry is older
+// than the TTL or the trigger price reaches the order's price while it hadn't at the last check -
+// the moment a limit order can fill. Fills still arrive as notifications, and the sync job checks
+// every active order regardless of the cache.
+type orderStatusCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*orderStatusEntry // Order ID → last open status
+	checks  int64
+	skipped int64
+}
+
+type orderStatusEntry struct {
+	reached   bool // The trigger price was at or through the order's price at the check
+	checkedAt time.Time
+}
+
+// UseOrderStatusCache answers trigger status checks of open orders from memory for up to ttl,
+// unless the price crosses the order's price
+func (s *GridService) UseOrderStatusCache(ttl time.Duration) {
+	s.statusCache = &orderStatusCache{ttl: ttl, entries: make(map[string]*orderStatusEntry)}
+}
+
+// OrderStatusCacheStats returns nil without an order status cache
+func (s *GridService) OrderStatusCacheStats() *OrderStatusCacheStats {
+	if s.statusCache == nil {
+		return nil
+	}
+	return s.statusCache.stats()
+}
+
+func (c *orderStatusCache) stats() *OrderStatusCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &OrderStatusCacheStats{Orders: len(c.entries), Checks: c.checks, Skipped: c.skipped, TTLSec: int(c.ttl / time.Second)}
+}
+
+// due reports whether an order has to be checked: not cached, expired, or the price reached it
+// since the last check
+func (c *orderStatusCache) due(orderID string, reached bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[orderID]
+	if ok && time.Since(entry.checkedAt) < c.ttl && (entry.reached || !reached) {
+		c.skipped++
+		return false
+	}
+	c.checks++
+	return true
+}
+
+// remember caches an open order and forgets one that isn't open anymore. Expired entries - of
+// orders filled by notification or handled by the sync job - are dropped on the way.
+func (c *orderStatusCache) remember(orderID string, reached bool, status *client.OrderStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, entry := range c.entries {
+		if time.Since(entry.checkedAt) >= c.ttl {
+			delete(c.entries, id)
+		}
+	}
+	if status == nil || status.Status != "open" {
+		delete(c.entries, orderID)
+		return
+	}
+	c.entries[orderID] = &orderStatusEntry{reached: reached, checkedAt: time.Now()}
+}
+
+// pollOrderStatus checks an active order on a trigger, skipping orders that are cached as open
+// and that the price hasn't crossed since
+func (s *GridService) pollOrderStatus(level *models.GridLevel, orderID string, isBuy bool, price decimal.Decimal) {
+	if s.statusCache == nil {
+		s.checkAndUpdateOrderStatus(level, orderID, isBuy)
+		return
+	}
+
+	reached := price.GreaterThanOrEqual(s.sellOrderPrice(level))
+	if isBuy {
+		reached = price.LessThanOrEqual(level.BuyPrice)
+	}
+	if !s.statusCache.due(orderID, reached) {
+		return
+	}
+
+	status, err := s.assurance.GetOrderStatus(level.Account, level.Symbol, orderID)
+	if err != nil {
+		log.Printf("ERROR: Failed to get order status for %s (level %d): %v", orderID, level.ID, err)
+		return
+	}
+	s.statusCache.remember(orderID, reached, status)
+	s.applyOrderStatus(level, orderID, isBuy, status)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// ErrNoLevels means a symbol has no grid levels to pause or resume
+var ErrNoLevels = errors.New("symbol has no grid levels")
+
+// PauseResult reports a symbol paused or resumed
+type PauseResult struct {
+	Symbol  string `json:"symbol"`
+	Enabled bool   `json:"enabled"`
+	Changed int    `json:"changed"` // Levels not already in that setting
+
+	// Open orders cancelled on pause; their levels go back to READY (buys) or HOLDING (sells)
+	// once the cancel notification arrives
+	CancelledOrders []string `json:"cancelled_orders,omitempty"`
+	FailedOrders    []string `json:"failed_orders,omitempty"`
+}
+
+// PauseGrid disables every level of a symbol so it places no new orders. Open orders are
+// left to fill unless cancelOrders is set.
+func (s *GridService) PauseGrid(symbol string, cancelOrders bool) (*PauseResult, error) {
+	levels, err := s.repo.GetBySymbol(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoLevels, symbol)
+	}
+
+	changed, err := s.repo.SetSymbolEnabled(symbol, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to disable %s levels: %w", symbol, err)
+	}
+	result := &PauseResult{Symbol: symbol, Enabled: false, Changed: changed}
+
+	if cancelOrders {
+		for _, level := range levels {
+			orderID := openOrderID(level)
+			if orderID == "" {
+				continue
+			}
+			if err := s.assurance.CancelOrder(level.Account, level.Symbol, orderID); err != nil {
+				log.Printf("ERROR: Failed to cancel order %s of level %d: %v", orderID, level.ID, err)
+				result.FailedOrders = append(result.FailedOrders, orderID)
+				continue
+			}
+			result.CancelledOrders = append(result.CancelledOrders, orderID)
+		}
+	}
+
+	log.Printf("INFO: Paused %s: %d levels disabled, %d orders cancelled, %d failed to cancel",
+		symbol, changed, len(result.CancelledOrders), len(result.FailedOrders))
+	return result, nil
+}
+
+// ResumeGrid enables every level of a symbol again; they trade from the next price trigger
+func (s *GridService) ResumeGrid(symbol string) (*PauseResult, error) {
+	levels, err := s.repo.GetBySymbol(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoLevels, symbol)
+	}
+
+	changed, err := s.repo.SetSymbolEnabled(symbol, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable %s levels: %w", symbol, err)
+	}
+
+	log.Printf("INFO: Resumed %s: %d levels enabled", symbol, changed)
+	return &PauseResult{Symbol: symbol, Enabled: true, Changed: changed}, nil
+}
+
+// openOrderID returns the order a level has open on the exchange, if any
+func openOrderID(level *models.GridLevel) string {
+	switch {
+	case level.State == models.StateBuyActive && level.BuyOrderID.Valid:
+		return level.BuyOrderID.String
+	case level.State == models.StateSellActive && level.SellOrderID.Valid:
+		return level.SellOrderID.String
+	}
+	return ""
+}
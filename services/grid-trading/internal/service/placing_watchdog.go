@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// Levels stay locked in PLACING_BUY/PLACING_SELL only for the length of one order-assurance call.
+// The sync job recovers them after SYNC_STUCK_AFTER_SEC (5 minutes); the watchdog reports them much sooner.
+
+// PlacingMetrics counts the levels locked in a PLACING state (GET /metrics/placing)
+type PlacingMetrics struct {
+	PlacingBuy       PlacingStateMetrics `json:"placing_buy"`
+	PlacingSell      PlacingStateMetrics `json:"placing_sell"`
+	WatchdogAfterSec int                 `json:"watchdog_after_sec"` // 0 = watchdog off
+	Stuck            []StuckPlacement    `json:"stuck"`              // Past watchdog_after_sec without an order ID
+	AlertsSent       int64               `json:"alerts_sent"`        // Since start
+	SpreadSkips      int64               `json:"spread_skips"`       // Placements skipped over MAX_SPREAD_PCT since start
+	SourceSkips      int64               `json:"source_skips"`       // Placements held back by the trigger policy since start
+}
+
+// PlacingStateMetrics counts the levels in one PLACING state
+type PlacingStateMetrics struct {
+	Count          int `json:"count"`
+	WithoutOrderID int `json:"without_order_id"`
+	OldestAgeSec   int `json:"oldest_age_sec"`
+}
+
+// StuckPlacement is a level whose placement never got an order ID
+type StuckPlacement struct {
+	LevelID        int              `json:"level_id"`
+	Account        string           `json:"account,omitempty"`
+	Symbol         string           `json:"symbol"`
+	State          models.GridState `json:"state"`
+	StateChangedAt time.Time        `json:"state_changed_at"`
+	AgeSec         int              `json:"age_sec"`
+}
+
+// EnablePlacingWatchdog reports placements still without an order ID after the given age
+func (s *GridService) EnablePlacingWatchdog(after time.Duration) {
+	s.placingWatchdogAfter = after
+}
+
+// GetPlacingMetrics counts the levels locked in PLACING_BUY/PLACING_SELL and their ages
+func (s *GridService) GetPlacingMetrics() (*PlacingMetrics, error) {
+	levels, err := s.repo.GetPlacing()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get placing levels: %w", err)
+	}
+
+	now := time.Now()
+	metrics := &PlacingMetrics{
+		WatchdogAfterSec: int(s.placingWatchdogAfter.Seconds()),
+		Stuck:            []StuckPlacement{},
+		AlertsSent:       s.placingAlerts.Load(),
+		SpreadSkips:      s.spreadSkips.Load(),
+		SourceSkips:      s.sourceSkips.Load(),
+	}
+	for _, level := range levels {
+		state := &metrics.PlacingSell
+		if level.State == models.StatePlacingBuy {
+			state = &metrics.PlacingBuy
+		}
+
+		age := int(now.Sub(level.StateChangedAt).Seconds())
+		state.Count++
+		if age > state.OldestAgeSec {
+			state.OldestAgeSec = age
+		}
+		if hasOrderID(level) {
+			continue
+		}
+		state.WithoutOrderID++
+
+		if s.placingWatchdogAfter > 0 && age >= metrics.WatchdogAfterSec {
+			metrics.Stuck = append(metrics.Stuck, stuckPlacement(level, age))
+		}
+	}
+	return metrics, nil
+}
+
+// CheckStuckPlacements logs and alerts once per placement on levels locked in a PLACING state for
+// longer than the watchdog age without an order ID. It changes nothing - the sync job recovers them.
+func (s *GridService) CheckStuckPlacements() error {
+	if s.placingWatchdogAfter <= 0 {
+		return nil
+	}
+
+	levels, err := s.repo.GetPlacing()
+	if err != nil {
+		return fmt.Errorf("failed to get placing levels: %w", err)
+	}
+
+	s.placingMu.Lock()
+	defer s.placingMu.Unlock()
+
+	now := time.Now()
+	stuck := make(map[int]time.Time)
+	for _, level := range levels {
+		age := now.Sub(level.StateChangedAt)
+		if hasOrderID(level) || age < s.placingWatchdogAfter {
+			continue
+		}
+		stuck[level.ID] = level.StateChangedAt
+
+		// One alert per placement attempt: a new attempt changes state_changed_at
+		if alerted, ok := s.placingAlerted[level.ID]; ok && alerted.Equal(level.StateChangedAt) {
+			continue
+		}
+
+		fields := map[string]string{
+			"level_id": strconv.Itoa(level.ID),
+			"account":  level.Account,
+			"state":    string(level.State),
+			"age_sec":  strconv.Itoa(int(age.Seconds())),
+			"since":    level.StateChangedAt.UTC().Format(time.RFC3339),
+		}
+		recovery := "stuck recovery is off"
+		if s.sync.Enabled && s.sync.RecoverStuck {
+			recovery = fmt.Sprintf("the sync job recovers it after %ds", s.sync.StuckAfterSec)
+			fields["recover_after_sec"] = strconv.Itoa(s.sync.StuckAfterSec)
+		}
+		log.Printf("WARNING: Level %d (%s) locked in %s for %s without an order ID - %s",
+			level.ID, level.Symbol, level.State, age.Round(time.Second), recovery)
+		s.placingAlerts.Add(1)
+		s.emit(contracts.EventPlacementStuck, level.Symbol,
+			fmt.Sprintf("Level %d locked in %s for %s without an order ID", level.ID, level.State, age.Round(time.Second)),
+			fields)
+	}
+	s.placingAlerted = stuck // Levels no longer stuck are forgotten
+	return nil
+}
+
+// hasOrderID reports whether a placing level already has the order ID of its side
+func hasOrderID(level *models.GridLevel) bool {
+	if level.State == models.StatePlacingBuy {
+		return level.BuyOrderID.Valid && level.BuyOrderID.String != ""
+	}
+	return level.SellOrderID.Valid && level.SellOrderID.String != ""
+}
+
+func stuckPlacement(level *models.GridLevel, ageSec int) StuckPlacement {
+	return StuckPlacement{
+		LevelID:        level.ID,
+		Account:        level.Account,
+		Symbol:         level.Symbol,
+		State:          level.State,
+		StateChangedAt: level.StateChangedAt,
+		AgeSec:         ageSec,
+	}
+}
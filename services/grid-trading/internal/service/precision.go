@@ -0,0 +1,135 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// precisionTTL is how long a symbol's tick and step size are reused; exchanges rarely change them
+	precisionTTL = time.Hour
+
+	// precisionRetryAfter is how long values pass through unrounded after a failed rules lookup
+	precisionRetryAfter = time.Minute
+)
+
+// symbolPrecision is how many decimals a symbol's prices and quantities have on the exchange,
+// from its tick and step size
+type symbolPrecision struct {
+	price      int32
+	quantity   int32
+	priceOK    bool // Tick size known
+	quantityOK bool // Step size known
+	fetchedAt  time.Time
+}
+
+// Price rounds a price to the symbol's tick precision, unchanged when it is unknown
+func (p symbolPrecision) Price(price decimal.Decimal) decimal.Decimal {
+	if !p.priceOK {
+		return price
+	}
+	return price.Round(p.price)
+}
+
+// Quantity rounds a coin amount to the symbol's step precision, unchanged when it is unknown
+func (p symbolPrecision) Quantity(amount decimal.Decimal) decimal.Decimal {
+	if !p.quantityOK {
+		return amount
+	}
+	return amount.Round(p.quantity)
+}
+
+// precisionFor returns the precision of an account's symbol from order-assurance's trading rules
+// (GET /symbols/{symbol}), cached for precisionTTL. A failed lookup leaves values unrounded and
+// is retried after precisionRetryAfter.
+func (s *GridService) precisionFor(account, symbol string) symbolPrecision {
+	key := account + "/" + symbol
+	s.precisionMu.Lock()
+	cached, ok := s.precisions[key]
+	s.precisionMu.Unlock()
+	if ok {
+		ttl := precisionTTL
+		if !cached.priceOK && !cached.quantityOK {
+			ttl = precisionRetryAfter
+		}
+		if time.Since(cached.fetchedAt) < ttl {
+			return cached
+		}
+	}
+
+	precision := symbolPrecision{fetchedAt: time.Now()}
+	assets, err := s.assurance.GetSymbolAssets(account, symbol)
+	if err != nil {
+		log.Printf("WARNING: No trading rules for %s, showing its prices unrounded: %v", symbol, err)
+	} else {
+		precision.price, precision.priceOK = decimalsOf(assets.TickSize)
+		precision.quantity, precision.quantityOK = decimalsOf(assets.StepSize)
+	}
+
+	s.precisionMu.Lock()
+	s.precisions[key] = precision
+	s.precisionMu.Unlock()
+	return precision
+}
+
+// decimalsOf counts the decimals of a tick or step size: 0.01000000 → 2, 1 → 0.
+// false for an unknown (zero) size.
+func decimalsOf(step decimal.Decimal) (int32, bool) {
+	if !step.IsPositive() {
+		return 0, false
+	}
+	places := int32(0)
+	for places < 18 && !step.Equal(step.Truncate(places)) {
+		places++
+	}
+	return places, true
+}
+
+// FormatLevel returns a copy of a level with its prices at the symbol's tick precision and its
+// filled amount at the step precision
+func (s *GridService) FormatLevel(level *models.GridLevel) *models.GridLevel {
+	precision := s.precisionFor(level.Account, level.Symbol)
+	formatted := *level
+	formatted.BuyPrice = precision.Price(level.BuyPrice)
+	formatted.SellPrice = precision.Price(level.SellPrice)
+	if level.FilledAmount.Valid {
+		formatted.FilledAmount.Decimal = precision.Quantity(level.FilledAmount.Decimal)
+	}
+	return &formatted
+}
+
+// FormatLevelDetails rounds the levels API's prices and amounts to each symbol's precision. The
+// break-even price rounds up, so selling at it still doesn't lose money.
+func (s *GridService) FormatLevelDetails(details []*LevelDetails) {
+	for _, d := range details {
+		precision := s.precisionFor(d.Account, d.Symbol)
+		d.GridLevel = s.FormatLevel(d.GridLevel)
+		d.SellOrderPrice = precision.Price(d.SellOrderPrice)
+		if precision.priceOK {
+			d.BreakEvenSellPrice = d.BreakEvenSellPrice.RoundCeil(precision.price)
+		}
+	}
+}
+
+// FormatTransactionPage rounds the prices and coin amounts of transactions to each symbol's precision
+func (s *GridService) FormatTransactionPage(page *contracts.TransactionPage) {
+	for i := range page.Transactions {
+		tx := &page.Transactions[i]
+		precision := s.precisionFor(tx.Account, tx.Symbol)
+		tx.LevelBuyPrice = precision.Price(tx.LevelBuyPrice)
+		tx.LevelSellPrice = precision.Price(tx.LevelSellPrice)
+		tx.TargetPrice = precision.Price(tx.TargetPrice)
+		if tx.ExecutedPrice != nil {
+			price := precision.Price(*tx.ExecutedPrice)
+			tx.ExecutedPrice = &price
+		}
+		if tx.AmountCoin != nil {
+			amount := precision.Quantity(*tx.AmountCoin)
+			tx.AmountCoin = &amount
+		}
+	}
+}
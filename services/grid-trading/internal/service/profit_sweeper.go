@@ -0,0 +1,138 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// ProfitSweepRepositoryInterface defines the profit sweep audit operations
+type ProfitSweepRepositoryInterface interface {
+	Create(sweep *models.ProfitSweep) error
+	GetRecent(limit int) ([]*models.ProfitSweep, error)
+	GetSweptByAccount() (map[string]decimal.Decimal, error)
+}
+
+// RealizedProfitSource reports realized profit per account
+type RealizedProfitSource interface {
+	GetRealizedProfitByAccount() (map[string]decimal.Decimal, error)
+}
+
+// ProfitSweepClient moves funds through order-assurance
+type ProfitSweepClient interface {
+	SweepProfit(req client.SweepRequest) (*client.SweepResult, error)
+}
+
+// ProfitSweepConfig controls when and where profit is swept
+type ProfitSweepConfig struct {
+	Threshold   decimal.Decimal // Minimum unswept USDT profit before sweeping
+	Destination string          // earn | withdraw
+	DryRun      bool            // Record what would be swept without moving funds
+}
+
+// ProfitSweeper moves realized profit above a threshold off the spot wallet,
+// recording every attempt (including dry runs and failures) for audit
+type ProfitSweeper struct {
+	profits   RealizedProfitSource
+	sweeps    ProfitSweepRepositoryInterface
+	assurance ProfitSweepClient
+	cfg       ProfitSweepConfig
+
+	mu sync.Mutex // One sweep run at a time (cron and manual trigger)
+}
+
+func NewProfitSweeper(profits RealizedProfitSource, sweeps ProfitSweepRepositoryInterface, assurance ProfitSweepClient, cfg ProfitSweepConfig) *ProfitSweeper {
+	return &ProfitSweeper{
+		profits:   profits,
+		sweeps:    sweeps,
+		assurance: assurance,
+		cfg:       cfg,
+	}
+}
+
+// Run sweeps every account whose unswept profit reached the threshold
+func (p *ProfitSweeper) Run() ([]*models.ProfitSweep, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	profits, err := p.profits.GetRealizedProfitByAccount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get realized profit: %w", err)
+	}
+
+	swept, err := p.sweeps.GetSweptByAccount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swept totals: %w", err)
+	}
+
+	accounts := make([]string, 0, len(profits))
+	for account := range profits {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+
+	var results []*models.ProfitSweep
+	for _, account := range accounts {
+		unswept := profits[account].Sub(swept[account])
+		amount := unswept.RoundDown(2) // Cent precision is accepted by Earn and withdrawals alike
+		if amount.LessThan(p.cfg.Threshold) {
+			log.Printf("DEBUG: Account %q unswept profit %s USDT below sweep threshold %s", account, unswept, p.cfg.Threshold)
+			continue
+		}
+
+		results = append(results, p.sweep(account, amount, unswept))
+	}
+
+	return results, nil
+}
+
+// GetRecent returns the latest sweep audit records
+func (p *ProfitSweeper) GetRecent(limit int) ([]*models.ProfitSweep, error) {
+	return p.sweeps.GetRecent(limit)
+}
+
+func (p *ProfitSweeper) sweep(account string, amount, unswept decimal.Decimal) *models.ProfitSweep {
+	record := &models.ProfitSweep{
+		Account:       account,
+		Asset:         "USDT",
+		Amount:        amount,
+		Destination:   p.cfg.Destination,
+		UnsweptProfit: unswept,
+		CreatedAt:     time.Now().UTC().Truncate(time.Second),
+	}
+
+	result, err := p.assurance.SweepProfit(client.SweepRequest{
+		Account:     account,
+		Asset:       record.Asset,
+		Amount:      amount,
+		Destination: p.cfg.Destination,
+		DryRun:      p.cfg.DryRun,
+	})
+
+	switch {
+	case err != nil:
+		record.Status = models.SweepFailed
+		record.Error = err.Error()
+		log.Printf("ERROR: Profit sweep of %s USDT to %s failed (account %q): %v", amount, p.cfg.Destination, account, err)
+	case result.DryRun:
+		record.Status = models.SweepDryRun
+		log.Printf("INFO: [DRY RUN] Would sweep %s USDT to %s (account %q, free balance %s)", amount, p.cfg.Destination, account, result.FreeBalance)
+	default:
+		record.Status = models.SweepCompleted
+		record.Reference = result.Reference
+		log.Printf("SUCCESS: Swept %s USDT to %s (account %q, reference %s)", amount, p.cfg.Destination, account, result.Reference)
+	}
+
+	if err := p.sweeps.Create(record); err != nil {
+		// A completed sweep we can't record would be swept again next run
+		log.Printf("ERROR: CRITICAL - %v (account %q, %s USDT, status %s, reference %s)", err, account, amount, record.Status, record.Reference)
+	}
+
+	return record
+}
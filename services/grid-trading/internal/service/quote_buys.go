@@ -0,0 +1,23 @@
+package service
+
+import (
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// UseQuoteQuantityBuys buys with MARKET orders that spend exactly the level's buy amount in
+// USDT (Binance quoteOrderQty) instead of resting LIMIT orders for amount/price coins, so no
+// quantity rounding is lost. A level buys once the price is at or below its buy price; the
+// fill carries the executed quantity and cost, which the sell and profit use as for any fill.
+func (s *GridService) UseQuoteQuantityBuys() {
+	s.quoteBuys = true
+}
+
+// canBuy reports whether a price triggers a level's buy in the configured buy mode; market
+// reports a grid placing MARKET orders, whose buys wait for the price like quote buys
+func (s *GridService) canBuy(level *models.GridLevel, price decimal.Decimal, market bool) bool {
+	if s.quoteBuys || market {
+		return level.CanPlaceQuoteBuy(price)
+	}
+	return level.CanPlaceBuy(price)
+}
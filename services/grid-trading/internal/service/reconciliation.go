@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// reconcileGrace leaves out levels and orders that changed this recently - a fill
+	// notification or a placement response may still be on its way
+	reconcileGrace = 2 * time.Minute
+
+	// reconcileBalanceTolerancePct absorbs commission and rounding differences in coin balances
+	reconcileBalanceTolerancePct = 1
+)
+
+// ReconciliationRepositoryInterface stores reconciliation reports
+type ReconciliationRepositoryInterface interface {
+	Save(report *models.ReconciliationReport) error
+	GetLatest() (*models.ReconciliationReport, error)
+}
+
+// reconcileGroup is one symbol of one account
+type reconcileGroup struct {
+	account string
+	symbol  string
+}
+
+// accountAsset is one coin balance of one account
+type accountAsset struct {
+	account string
+	asset   string
+}
+
+// UseReconciliation stores reconciliation reports so the latest one can be read back
+func (s *GridService) UseReconciliation(reports ReconciliationRepositoryInterface) {
+	s.reconciliations = reports
+}
+
+// Reconcile compares every level with the exchange and stores the result: active levels whose
+// order is no longer open, open orders no level tracks, and coin balances short of what the
+// levels hold. It only reports - the sync job repairs level states. A report with findings,
+// or with checks that could not run, is sent to the notifier. A cancelled run stores nothing.
+func (s *GridService) Reconcile(ctx context.Context) (*models.ReconciliationReport, error) {
+	if s.reconciliations == nil {
+		return nil, fmt.Errorf("reconciliation is not enabled")
+	}
+
+	started := time.Now()
+	levels, err := s.repo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+
+	report := &models.ReconciliationReport{
+		CheckedLevels: len(levels),
+		Mismatches:    []models.OrderMismatch{},
+		OrphanOrders:  []models.OrphanOrder{},
+		Balances:      []models.BalanceCheck{},
+		Errors:        []string{},
+		CreatedAt:     started.UTC(),
+	}
+	cutoff := started.Add(-reconcileGrace)
+
+	groups := make(map[reconcileGroup][]*models.GridLevel)
+	var order []reconcileGroup
+	for _, level := range levels {
+		key := reconcileGroup{account: level.Account, symbol: level.Symbol}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], level)
+	}
+
+	var checks []orderCheck
+	locked := make(map[accountAsset]decimal.Decimal) // Coins in open sell orders
+	holders := make(map[accountAsset][]*models.GridLevel)
+	for i, key := range order {
+		if err := JobStep(ctx, i, len(order), "checking open orders of "+key.symbol+accountSuffix(key.account)); err != nil {
+			return nil, err
+		}
+		open, err := s.assurance.GetOpenOrders(key.account, key.symbol)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("open orders of %s%s: %v", key.symbol, accountSuffix(key.account), err))
+			continue
+		}
+		report.CheckedOrders += len(open.Orders)
+
+		openIDs := make(map[string]bool, len(open.Orders))
+		for _, o := range open.Orders {
+			openIDs[o.OrderID] = true
+		}
+
+		tracked := make(map[string]bool)
+		for _, level := range groups[key] {
+			if level.BuyOrderID.Valid {
+				tracked[level.BuyOrderID.String] = true
+			}
+			if level.SellOrderID.Valid {
+				tracked[level.SellOrderID.String] = true
+			}
+
+			check, ok := activeOrder(level)
+			if ok && !openIDs[check.orderID] && level.StateChangedAt.Before(cutoff) {
+				checks = append(checks, check)
+			}
+		}
+
+		for _, o := range open.Orders {
+			if !tracked[o.OrderID] && o.CreatedAt.Before(cutoff) {
+				report.OrphanOrders = append(report.OrphanOrders, models.OrphanOrder{
+					Account:  key.account,
+					Symbol:   key.symbol,
+					OrderID:  o.OrderID,
+					Side:     string(o.Side),
+					Price:    o.Price,
+					Quantity: o.Quantity,
+				})
+			}
+		}
+
+		// Futures accounts hold positions, not coins
+		if key.account == client.FuturesAccount {
+			continue
+		}
+		var holding []*models.GridLevel
+		for _, level := range groups[key] {
+			if holdsCoins(level) {
+				holding = append(holding, level)
+			}
+		}
+		if len(holding) == 0 {
+			continue
+		}
+
+		assets, err := s.assurance.GetSymbolAssets(key.account, key.symbol)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("assets of %s%s: %v", key.symbol, accountSuffix(key.account), err))
+			continue
+		}
+		coin := accountAsset{account: key.account, asset: assets.BaseAsset}
+		holders[coin] = append(holders[coin], holding...)
+		for _, o := range open.Orders {
+			if o.Side == client.OrderSideSell {
+				locked[coin] = locked[coin].Add(o.Quantity.Sub(o.ExecutedQty))
+			}
+		}
+	}
+
+	mismatched := s.reconcileOrders(checks, report)
+	s.reconcileBalances(holders, locked, mismatched, report)
+
+	report.Status = models.ReconciliationClean
+	if len(report.Mismatches) > 0 || len(report.OrphanOrders) > 0 || len(report.Errors) > 0 || !balancesOK(report.Balances) {
+		report.Status = models.ReconciliationDiscrepancies
+	}
+	report.Duration = time.Since(started).Round(time.Millisecond).String()
+
+	if err := s.reconciliations.Save(report); err != nil {
+		return nil, err
+	}
+
+	log.Printf("INFO: Reconciliation %s - %d levels, %d open orders, %d mismatches, %d orphan orders, %d errors",
+		report.Status, report.CheckedLevels, report.CheckedOrders, len(report.Mismatches), len(report.OrphanOrders), len(report.Errors))
+	if report.Status == models.ReconciliationDiscrepancies {
+		s.emitReconciliation(report)
+	}
+	return report, nil
+}
+
+// GetLatestReconciliation returns the newest stored report, nil if none has run yet
+func (s *GridService) GetLatestReconciliation() (*models.ReconciliationReport, error) {
+	if s.reconciliations == nil {
+		return nil, fmt.Errorf("reconciliation is not enabled")
+	}
+	return s.reconciliations.GetLatest()
+}
+
+// reconcileOrders looks up the active orders missing from the open orders and reports the ones
+// the exchange shows as filled, cancelled or unknown. Returns the levels reported.
+func (s *GridService) reconcileOrders(checks []orderCheck, report *models.ReconciliationReport) map[int]bool {
+	mismatched := make(map[int]bool)
+	if len(checks) == 0 {
+		return mismatched
+	}
+
+	queries := make([]client.OrderStatusQuery, len(checks))
+	for i, check := range checks {
+		queries[i] = client.OrderStatusQuery{Symbol: check.level.Symbol, OrderID: check.orderID, Account: check.level.Account}
+	}
+
+	statuses, err := s.assurance.GetOrderStatuses(queries)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("status of %d orders missing from the open orders: %v", len(checks), err))
+		return mismatched
+	}
+
+	for i, check := range checks {
+		status := statuses[i]
+		switch status.Status {
+		case contracts.StatusOpen:
+			// Placed or reopened since the open orders were read
+		case client.OrderStatusError:
+			report.Errors = append(report.Errors, fmt.Sprintf("status of order %s (level %d): %s", check.orderID, check.level.ID, status.Error))
+		default:
+			mismatched[check.level.ID] = true
+			report.Mismatches = append(report.Mismatches, models.OrderMismatch{
+				LevelID:        check.level.ID,
+				Account:        check.level.Account,
+				Symbol:         check.level.Symbol,
+				State:          check.level.State,
+				OrderID:        check.orderID,
+				ExchangeStatus: status.Status,
+			})
+		}
+	}
+	return mismatched
+}
+
+// reconcileBalances checks that each account's coins cover what its levels hold. Levels already
+// reported as mismatched are left out - their coins are on the move.
+func (s *GridService) reconcileBalances(holders map[accountAsset][]*models.GridLevel, locked map[accountAsset]decimal.Decimal, mismatched map[int]bool, report *models.ReconciliationReport) {
+	free := make(map[string]map[string]decimal.Decimal)
+	tolerance := decimal.NewFromInt(reconcileBalanceTolerancePct).Div(decimal.NewFromInt(100))
+
+	for coin, levels := range holders {
+		var expected decimal.Decimal
+		for _, level := range levels {
+			if !mismatched[level.ID] {
+				expected = expected.Add(level.FilledAmount.Decimal)
+			}
+		}
+		if !expected.IsPositive() {
+			continue
+		}
+
+		balances, ok := free[coin.account]
+		if !ok {
+			var err error
+			if balances, err = s.assurance.GetFreeBalances(coin.account); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("balances%s: %v", accountSuffix(coin.account), err))
+				continue
+			}
+			free[coin.account] = balances
+		}
+
+		check := models.BalanceCheck{
+			Account:  coin.account,
+			Asset:    coin.asset,
+			Expected: expected,
+			Actual:   balances[coin.asset].Add(locked[coin]),
+		}
+		if check.Actual.LessThan(expected) {
+			check.Shortfall = expected.Sub(check.Actual)
+		}
+		check.OK = check.Shortfall.LessThanOrEqual(expected.Mul(tolerance))
+		report.Balances = append(report.Balances, check)
+	}
+
+	sort.Slice(report.Balances, func(i, j int) bool {
+		a, b := report.Balances[i], report.Balances[j]
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		return a.Asset < b.Asset
+	})
+}
+
+func (s *GridService) emitReconciliation(report *models.ReconciliationReport) {
+	var findings []string
+	for _, m := range report.Mismatches {
+		findings = append(findings, fmt.Sprintf("level %d %s: %s order %s is %s", m.LevelID, m.Symbol, m.State, m.OrderID, m.ExchangeStatus))
+	}
+	for _, o := range report.OrphanOrders {
+		findings = append(findings, fmt.Sprintf("untracked %s %s order %s: %s @ %s%s", o.Symbol, o.Side, o.OrderID, o.Quantity, o.Price, accountSuffix(o.Account)))
+	}
+	for _, b := range report.Balances {
+		if !b.OK {
+			findings = append(findings, fmt.Sprintf("%s%s short by %s (levels hold %s, exchange has %s)", b.Asset, accountSuffix(b.Account), b.Shortfall, b.Expected, b.Actual))
+		}
+	}
+	findings = append(findings, report.Errors...)
+
+	fields := map[string]string{
+		"report_id":     strconv.Itoa(report.ID),
+		"mismatches":    strconv.Itoa(len(report.Mismatches)),
+		"orphan_orders": strconv.Itoa(len(report.OrphanOrders)),
+		"errors":        strconv.Itoa(len(report.Errors)),
+		"findings":      strings.Join(findings, "\n"),
+	}
+	message := fmt.Sprintf("Reconciliation found %d mismatches, %d orphan orders, %d balance shortfalls and %d errors",
+		len(report.Mismatches), len(report.OrphanOrders), len(report.Balances)-countOK(report.Balances), len(report.Errors))
+
+	s.emit(contracts.EventReconciliation, "", message, fields)
+}
+
+// activeOrder returns the order an active level waits on
+func activeOrder(level *models.GridLevel) (orderCheck, bool) {
+	if level.State == models.StateBuyActive && level.BuyOrderID.Valid {
+		return orderCheck{level: level, orderID: level.BuyOrderID.String, isBuy: true}, true
+	}
+	if level.State == models.StateSellActive && level.SellOrderID.Valid {
+		return orderCheck{level: level, orderID: level.SellOrderID.String, isBuy: false}, true
+	}
+	return orderCheck{}, false
+}
+
+// holdsCoins reports whether a level owns bought coins, free or in its sell order
+func holdsCoins(level *models.GridLevel) bool {
+	switch level.State {
+	case models.StateHolding, models.StatePlacingSell, models.StateSellActive:
+		return level.FilledAmount.Valid && level.FilledAmount.Decimal.IsPositive()
+	}
+	return false
+}
+
+func balancesOK(checks []models.BalanceCheck) bool {
+	return countOK(checks) == len(checks)
+}
+
+func countOK(checks []models.BalanceCheck) int {
+	ok := 0
+	for _, check := range checks {
+		if check.OK {
+			ok++
+		}
+	}
+	return ok
+}
+
+func accountSuffix(account string) string {
+	if account == "" {
+		return ""
+	}
+	return " (account " + account + ")"
+}
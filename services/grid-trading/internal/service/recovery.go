@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// recoveryExhaustedCode is the error code of a level quarantined after failed recoveries
+const recoveryExhaustedCode = "recovery_exhausted"
+
+// SetRecoveryBudget quarantines a level in ERROR after maxAttempts failed SyncOrders recoveries
+// in a row (0 = retry forever). A placed order resets the count.
+func (s *GridService) SetRecoveryBudget(maxAttempts int) {
+	s.maxRecoveryAttempts = maxAttempts
+}
+
+// failRecovery counts a failed recovery of a stuck level and returns it to revertTo, or to ERROR
+// once the budget is spent - retrying the same failing placement every sync would loop forever
+func (s *GridService) failRecovery(level *models.GridLevel, revertTo models.GridState, cause error) {
+	attempts, err := s.repo.AddRecoveryFailure(level.ID)
+	if err != nil {
+		log.Printf("ERROR: Failed to count recovery attempt of level %d: %v", level.ID, err)
+	}
+	if err != nil || s.maxRecoveryAttempts <= 0 || attempts < s.maxRecoveryAttempts {
+		s.repo.UpdateState(level.ID, revertTo)
+		return
+	}
+
+	if err := s.quarantine(level, attempts, cause); err != nil {
+		log.Printf("ERROR: Failed to quarantine level %d, returning it to %s: %v", level.ID, revertTo, err)
+		s.repo.UpdateState(level.ID, revertTo)
+	}
+}
+
+// quarantine moves a stuck level to ERROR with the reason, recording and reporting it first
+func (s *GridService) quarantine(level *models.GridLevel, attempts int, cause error) error {
+	reason := fmt.Sprintf("quarantined after %d failed recoveries: %v", attempts, cause)
+	side := "buy"
+
+	// Record transaction FIRST (audit trail before state change)
+	var err error
+	if level.State == models.StatePlacingBuy {
+		err = s.txRepo.RecordBuyError(level.ID, level.Symbol, level.BuyPrice, recoveryExhaustedCode, reason)
+	} else {
+		side = "sell"
+		err = s.txRepo.RecordSellError(level.ID, level.Symbol, level.SellPrice, recoveryExhaustedCode, reason)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record quarantine: %w", err)
+	}
+
+	moved, err := s.repo.Quarantine(level.ID, level.State, reason)
+	if err != nil {
+		return err
+	}
+	if !moved {
+		log.Printf("WARNING: Level %d left %s before it could be quarantined", level.ID, level.State)
+		return nil
+	}
+
+	log.Printf("ERROR: Level %d %s", level.ID, reason)
+	s.emit(contracts.EventOrderFailed, level.Symbol,
+		fmt.Sprintf("Level %d %s, level set to ERROR", level.ID, reason),
+		map[string]string{
+			"level_id":   strconv.Itoa(level.ID),
+			"order_id":   "none",
+			"side":       side,
+			"error_code": recoveryExhaustedCode,
+			"error":      reason,
+		})
+	return nil
+}
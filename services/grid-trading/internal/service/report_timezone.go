@@ -0,0 +1,46 @@
+package service
+
+import (
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// Timestamps are stored in UTC; the reporting timezone (REPORT_TIMEZONE) only moves the
+// boundaries of "today", this week and this month, and the date of the daily report.
+
+// SetReportTimezone sets the timezone of daily, weekly and monthly stats and reports (UTC by default)
+func (s *GridService) SetReportTimezone(loc *time.Location) {
+	s.reportLoc = loc
+}
+
+// ReportTimezone returns the timezone of daily, weekly and monthly stats and reports
+func (s *GridService) ReportTimezone() *time.Location {
+	return s.reportLoc
+}
+
+// SetReportTimezone sets the timezone of funding_today (UTC by default)
+func (m *FuturesMonitor) SetReportTimezone(loc *time.Location) {
+	m.reportLoc = loc
+}
+
+// SetReportTimezone sets the timezone of interest_today_usdt (UTC by default)
+func (m *MarginMonitor) SetReportTimezone(loc *time.Location) {
+	m.reportLoc = loc
+}
+
+// reportPeriods returns the starts of the day, week (from Monday) and month containing now in loc.
+// A nil loc means UTC.
+func reportPeriods(loc *time.Location, now time.Time) models.ReportPeriods {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	local := now.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	return models.ReportPeriods{
+		DayStart:   day,
+		WeekStart:  day.AddDate(0, 0, -(int(day.Weekday())+6)%7),
+		MonthStart: time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc),
+	}
+}
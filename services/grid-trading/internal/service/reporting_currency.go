@@ -0,0 +1,120 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// FXSourceFixed is the source of a rate configured as FX_RATE
+const FXSourceFixed = "fixed"
+
+// fxDecimals rounds rates; values converted with them keep fxValueDecimals
+const (
+	fxDecimals      = 8
+	fxValueDecimals = 4
+)
+
+// FXRate converts USDT values into the reporting currency
+type FXRate struct {
+	Currency  string          `json:"currency"`
+	Rate      decimal.Decimal `json:"rate"`                 // Units of Currency per USDT
+	Source    string          `json:"source"`               // fixed, or the pair the rate was read from
+	UpdatedAt string          `json:"updated_at,omitempty"` // When the pair's price arrived
+}
+
+// Convert values a USDT amount in the reporting currency
+func (r *FXRate) Convert(usdt decimal.Decimal) decimal.Decimal {
+	return usdt.Mul(r.Rate).Round(fxValueDecimals)
+}
+
+// ReportingValues are the profit totals of /status in the reporting currency
+type ReportingValues struct {
+	FXRate
+	ProfitToday     decimal.Decimal `json:"profit_today"`
+	ProfitThisWeek  decimal.Decimal `json:"profit_this_week"`
+	ProfitThisMonth decimal.Decimal `json:"profit_this_month"`
+	ProfitAllTime   decimal.Decimal `json:"profit_all_time"`
+	UnrealizedPnL   decimal.Decimal `json:"unrealized_pnl"`
+}
+
+// UseReportingCurrency reports profit in currency (EUR, GBP...) next to USDT, converted at report
+// time. The rate is fixedRate when positive, otherwise the latest price of fxSymbol - a pair of the
+// currency and USDT either way round (EURUSDT, USDTBRL) - which price-monitor triggers like a grid symbol.
+func (s *GridService) UseReportingCurrency(currency, fxSymbol string, fixedRate decimal.Decimal) {
+	s.reportCurrency = currency
+	s.fxSymbol = fxSymbol
+	s.fxFixedRate = fixedRate
+	if fixedRate.IsPositive() {
+		s.fxSymbol = ""
+	}
+}
+
+// ReportCurrency returns the reporting currency, empty if profit is reported in USDT only
+func (s *GridService) ReportCurrency() string {
+	return s.reportCurrency
+}
+
+// ReportingRate returns the rate to convert USDT values at now, nil if no reporting currency is set
+// or the FX pair has no price yet. Like the peg check, the price isn't checked for staleness:
+// triggers only fire on change, so a quiet pair keeps its last price.
+func (s *GridService) ReportingRate() *FXRate {
+	if s.reportCurrency == "" {
+		return nil
+	}
+	if s.fxSymbol == "" {
+		return &FXRate{Currency: s.reportCurrency, Rate: s.fxFixedRate, Source: FXSourceFixed}
+	}
+
+	quote, _ := s.latestPrice(s.fxSymbol)
+	if quote == nil || !quote.Price.IsPositive() {
+		return nil
+	}
+
+	// EURUSDT prices a euro in USDT; USDTBRL prices a USDT in reais
+	rate := decimal.NewFromInt(1).DivRound(quote.Price, fxDecimals)
+	if strings.HasPrefix(s.fxSymbol, quoteAsset) {
+		rate = quote.Price
+	}
+	return &FXRate{
+		Currency:  s.reportCurrency,
+		Rate:      rate,
+		Source:    s.fxSymbol,
+		UpdatedAt: quote.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// reportingValues converts the profit totals of /status, nil if there is no rate
+func (s *GridService) reportingValues(status *StatusResponse) *ReportingValues {
+	rate := s.ReportingRate()
+	if rate == nil {
+		return nil
+	}
+	return &ReportingValues{
+		FXRate:          *rate,
+		ProfitToday:     rate.Convert(status.ProfitToday),
+		ProfitThisWeek:  rate.Convert(status.ProfitThisWeek),
+		ProfitThisMonth: rate.Convert(status.ProfitThisMonth),
+		ProfitAllTime:   rate.Convert(status.ProfitAllTime),
+		UnrealizedPnL:   rate.Convert(status.UnrealizedPnL),
+	}
+}
+
+// convertedReport converts the totals of a daily report, nil if there is no rate
+func (s *GridService) convertedReport(report *models.DailyReport) *models.ConvertedTotals {
+	rate := s.ReportingRate()
+	if rate == nil {
+		return nil
+	}
+	return &models.ConvertedTotals{
+		Currency:      rate.Currency,
+		FXRate:        rate.Rate,
+		Profit:        rate.Convert(report.ProfitUSDT),
+		Fees:          rate.Convert(report.FeesUSDT),
+		Unrealized:    rate.Convert(report.UnrealizedUSDT),
+		ProfitAllTime: rate.Convert(report.ProfitAllTime),
+		Equity:        rate.Convert(report.EquityUSDT),
+	}
+}
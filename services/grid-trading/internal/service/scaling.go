@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// CapitalEstimate is the USDT a symbol's grid can lock up. Flat is every level buying its
+// buy_amount; worst case is price falling through the whole grid, so each level buys
+// with the longest possible streak of filled levels above it.
+type CapitalEstimate struct {
+	Symbol          string          `json:"symbol"`
+	Levels          int             `json:"levels"`
+	FlatUSDT        decimal.Decimal `json:"flat_usdt"`
+	WorstCaseUSDT   decimal.Decimal `json:"worst_case_usdt"`
+	ScalingRiskUSDT decimal.Decimal `json:"scaling_risk_usdt"` // Worst case minus flat
+	LargestBuyUSDT  decimal.Decimal `json:"largest_buy_usdt"`
+}
+
+// buyAmountFor sizes the next buy of a level. Flat levels buy buy_amount; scaled levels
+// multiply it once per consecutive level directly above that holds coin.
+func (s *GridService) buyAmountFor(level *models.GridLevel) decimal.Decimal {
+	if !level.BuyMultiplier.Valid {
+		return level.BuyAmount
+	}
+
+	levels, err := s.repo.GetBySymbol(level.Symbol)
+	if err != nil {
+		log.Printf("WARNING: Failed to get %s levels for buy scaling, using base amount for level %d: %v", level.Symbol, level.ID, err)
+		return level.BuyAmount
+	}
+
+	// Levels are sorted by buy price ascending, so the ones above follow this one
+	streak := 0
+	for i, other := range levels {
+		if other.ID != level.ID {
+			continue
+		}
+		for _, above := range levels[i+1:] {
+			if !holdsCoin(above) {
+				break
+			}
+			streak++
+		}
+		break
+	}
+
+	amount := level.ScaledBuyAmount(streak)
+	if streak > 0 {
+		log.Printf("INFO: Level %d buy scaled to %s USDT (%d filled levels above, base %s)", level.ID, amount, streak, level.BuyAmount)
+	}
+	return amount
+}
+
+// EstimateCapital returns the flat and worst-case capital of a symbol's grid
+func (s *GridService) EstimateCapital(symbol string) (*CapitalEstimate, error) {
+	levels, err := s.repo.GetBySymbol(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+
+	estimate := estimateCapital(symbol, levels)
+	return &estimate, nil
+}
+
+// estimateCapital walks the levels from the top down, each buying with the streak of
+// every level above it filled
+func estimateCapital(symbol string, levels []*models.GridLevel) CapitalEstimate {
+	sorted := make([]*models.GridLevel, len(levels))
+	copy(sorted, levels)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].BuyPrice.GreaterThan(sorted[j].BuyPrice) })
+
+	estimate := CapitalEstimate{Symbol: symbol, Levels: len(sorted)}
+	for streak, level := range sorted {
+		amount := level.ScaledBuyAmount(streak)
+		estimate.FlatUSDT = estimate.FlatUSDT.Add(level.BuyAmount)
+		estimate.WorstCaseUSDT = estimate.WorstCaseUSDT.Add(amount)
+		if amount.GreaterThan(estimate.LargestBuyUSDT) {
+			estimate.LargestBuyUSDT = amount
+		}
+	}
+	estimate.ScalingRiskUSDT = estimate.WorstCaseUSDT.Sub(estimate.FlatUSDT)
+
+	return estimate
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// ErrSeedRejected wraps seed requests that can't be applied as asked (no price, too little inventory)
+var ErrSeedRejected = errors.New("seed rejected")
+
+// SeedRequest pre-funds levels with coins already on the exchange
+type SeedRequest struct {
+	Account string              `json:"account,omitempty"` // Levels (and balance) of this sub-account (empty = master)
+	Amount  decimal.NullDecimal `json:"amount,omitempty"`  // Coins to allocate (default: all free coins the grid doesn't hold)
+	Price   decimal.NullDecimal `json:"price,omitempty"`   // Cost basis per coin (default: latest price)
+	DryRun  bool                `json:"dry_run"`
+}
+
+// SeededLevel is a level moved to HOLDING with seeded coins
+type SeededLevel struct {
+	LevelID    int             `json:"level_id"`
+	BuyPrice   decimal.Decimal `json:"buy_price"`
+	SellPrice  decimal.Decimal `json:"sell_price"`
+	AmountCoin decimal.Decimal `json:"amount_coin"`
+	CostUSDT   decimal.Decimal `json:"cost_usdt"`
+}
+
+// SeedResult describes a completed (or simulated) seed
+type SeedResult struct {
+	Symbol        string          `json:"symbol"`
+	Account       string          `json:"account,omitempty"`
+	Price         decimal.Decimal `json:"price"`
+	FreeBalance   decimal.Decimal `json:"free_balance"`   // Free coins in the balance snapshot
+	GridHeld      decimal.Decimal `json:"grid_held"`      // Free coins already owned by HOLDING levels
+	AvailableCoin decimal.Decimal `json:"available_coin"` // Free balance minus grid held
+	SeededCoin    decimal.Decimal `json:"seeded_coin"`
+	DryRun        bool            `json:"dry_run"`
+	Levels        []SeededLevel   `json:"levels"`
+}
+
+// SeedInventory starts a grid sell-first with coins the user already owns. Every READY
+// level above the price would be holding had the grid bought on the way down, so those
+// levels - closest to the price first - get buy_amount worth of coins at the cost basis
+// price and move to HOLDING, until the inventory runs out. A BUY FILLED transaction with
+// order ID seed-<level id> records the cost basis so the sell's profit is calculated.
+func (s *GridService) SeedInventory(symbol string, req SeedRequest) (*SeedResult, error) {
+	price := req.Price.Decimal
+	if !req.Price.Valid {
+		quote, _ := s.latestPrice(symbol)
+		if quote == nil || s.isStale(quote) {
+			return nil, fmt.Errorf("%w: no recent %s price, pass one as the cost basis", ErrSeedRejected, symbol)
+		}
+		price = quote.Price
+	}
+
+	levels, err := s.repo.GetBySymbol(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+
+	assets, err := s.assurance.GetSymbolAssets(req.Account, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assets of %s: %w", symbol, err)
+	}
+
+	balances, err := s.assurance.GetFreeBalances(req.Account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance snapshot: %w", err)
+	}
+
+	result := &SeedResult{
+		Symbol:      symbol,
+		Account:     req.Account,
+		Price:       price,
+		FreeBalance: balances[assets.BaseAsset],
+		DryRun:      req.DryRun,
+		Levels:      []SeededLevel{},
+	}
+
+	// Coins in open sell orders are locked, but HOLDING levels' coins are still free
+	for _, level := range levels {
+		if level.Account == req.Account && holdsCoin(level) && level.State != models.StateSellActive {
+			result.GridHeld = result.GridHeld.Add(level.FilledAmount.Decimal)
+		}
+	}
+	result.AvailableCoin = decimal.Max(result.FreeBalance.Sub(result.GridHeld), decimal.Zero)
+
+	remaining := result.AvailableCoin
+	if req.Amount.Valid {
+		if req.Amount.Decimal.GreaterThan(result.AvailableCoin) {
+			return nil, fmt.Errorf("%w: %s %s requested but only %s free outside the grid",
+				ErrSeedRejected, req.Amount.Decimal, assets.BaseAsset, result.AvailableCoin)
+		}
+		remaining = req.Amount.Decimal
+	}
+
+	// Levels are sorted by buy price ascending, so the first ones above the price are closest
+	for _, level := range levels {
+		if level.Account != req.Account || level.State != models.StateReady || !level.Enabled || !level.BuyPrice.GreaterThan(price) {
+			continue
+		}
+
+		coins := level.BuyAmount.Div(price).Truncate(8)
+		if coins.GreaterThan(remaining) {
+			break
+		}
+
+		seeded := SeededLevel{
+			LevelID:    level.ID,
+			BuyPrice:   level.BuyPrice,
+			SellPrice:  level.SellPrice,
+			AmountCoin: coins,
+			CostUSDT:   coins.Mul(price).Round(8),
+		}
+
+		if !req.DryRun {
+			ok, err := s.seedLevel(level, seeded, price)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		remaining = remaining.Sub(coins)
+		result.SeededCoin = result.SeededCoin.Add(coins)
+		result.Levels = append(result.Levels, seeded)
+	}
+
+	if req.DryRun {
+		log.Printf("INFO: [DRY RUN] Would seed %d %s levels with %s coins at %s", len(result.Levels), symbol, result.SeededCoin, price)
+	} else if len(result.Levels) > 0 {
+		log.Printf("SUCCESS: Seeded %d %s levels with %s coins at %s", len(result.Levels), symbol, result.SeededCoin, price)
+	}
+
+	return result, nil
+}
+
+// seedLevel moves one level to HOLDING. Returns false if it left READY in the meantime.
+func (s *GridService) seedLevel(level *models.GridLevel, seeded SeededLevel, price decimal.Decimal) (bool, error) {
+	ok, err := s.repo.SeedHolding(level.ID, seeded.AmountCoin, seeded.CostUSDT)
+	if err != nil {
+		return false, fmt.Errorf("failed to seed level %d: %w", level.ID, err)
+	}
+	if !ok {
+		log.Printf("WARNING: Level %d left READY while seeding, skipped", level.ID)
+		return false, nil
+	}
+
+	// Seeded coins were bought outside the grid, so no fee is charged to the cycle
+	noFee := models.Fee{Asset: quoteAsset, USDT: decimal.NewNullDecimal(decimal.Zero)}
+	orderID := "seed-" + strconv.Itoa(level.ID)
+	if err := s.txRepo.RecordBuyFilled(level.ID, level.Symbol, orderID, level.BuyPrice, price, seeded.AmountCoin, seeded.CostUSDT, noFee); err != nil {
+		log.Printf("WARNING: Failed to record seed transaction for level %d, its sell profit will be N/A: %v", level.ID, err)
+	}
+	return true, nil
+}
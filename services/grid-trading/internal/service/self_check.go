@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// Self-check results: failed checks can send grid-trading into safe mode, warnings never do
+const (
+	CheckOK      = "ok"
+	CheckWarning = "warning"
+	CheckFailed  = "failed"
+)
+
+// SelfCheck is one startup check
+type SelfCheck struct {
+	Name   string `json:"name"` // schema | order_assurance | levels
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// SelfCheckReport is the result of the startup self-check (GET /self-check)
+type SelfCheckReport struct {
+	Passed    bool        `json:"passed"` // No check failed
+	Checks    []SelfCheck `json:"checks"`
+	CheckedAt time.Time   `json:"checked_at"`
+
+	// Safe mode: read APIs only and no order placement until POST /safe-mode/resume
+	SafeMode       bool       `json:"safe_mode"`
+	SafeModeSince  *time.Time `json:"safe_mode_since,omitempty"`
+	SafeModeReason string     `json:"safe_mode_reason,omitempty"`
+}
+
+// RunSelfCheck checks the state grid-trading is about to trade on: the database schema
+// against the migrations this build knows, order-assurance reachability and levels whose
+// state contradicts their orders or holdings. schemaVersion is what the database recorded
+// before this start's migrations ran.
+func (s *GridService) RunSelfCheck(schemaVersion, knownVersion int) *SelfCheckReport {
+	report := &SelfCheckReport{Passed: true, CheckedAt: time.Now().UTC()}
+
+	add := func(name, status, detail string) {
+		report.Checks = append(report.Checks, SelfCheck{Name: name, Status: status, Detail: detail})
+		if status == CheckFailed {
+			report.Passed = false
+		}
+		if status == CheckOK {
+			log.Printf("INFO: Self-check %s: %s", name, detail)
+		} else {
+			log.Printf("WARNING: Self-check %s %s: %s", name, status, detail)
+		}
+	}
+
+	if schemaVersion > knownVersion {
+		add("schema", CheckFailed, fmt.Sprintf("database is at schema version %d, this build knows %d - it was migrated by a newer grid-trading", schemaVersion, knownVersion))
+	} else {
+		add("schema", CheckOK, fmt.Sprintf("schema version %d", knownVersion))
+	}
+
+	if err := s.assurance.Ping(); err != nil {
+		add("order_assurance", CheckFailed, fmt.Sprintf("order-assurance unreachable: %v", err))
+	} else {
+		add("order_assurance", CheckOK, "order-assurance reachable")
+	}
+
+	levels, err := s.repo.GetAll()
+	if err != nil {
+		add("levels", CheckFailed, fmt.Sprintf("failed to read levels: %v", err))
+	} else {
+		status, detail := checkLevels(levels, s.storeConsistency(levels))
+		add("levels", status, detail)
+	}
+
+	s.safeModeMu.Lock()
+	s.selfCheck = report
+	s.safeModeMu.Unlock()
+	return report
+}
+
+// checkLevels fails on levels breaking a grid invariant (see the consistency report). Levels
+// mid-placement or in ERROR are only warnings - the sync job and operators handle those.
+func checkLevels(levels []*models.GridLevel, consistency *ConsistencyReport) (string, string) {
+	var broken, pending []string
+	for _, v := range consistency.Violations {
+		broken = append(broken, fmt.Sprintf("%d (%s)", v.LevelID, v.Detail))
+	}
+	for _, level := range levels {
+		if level.State == models.StatePlacingBuy || level.State == models.StatePlacingSell || level.State == models.StateError {
+			pending = append(pending, fmt.Sprintf("%d (%s)", level.ID, level.State))
+		}
+	}
+
+	switch {
+	case len(broken) > 0:
+		return CheckFailed, fmt.Sprintf("%d of %d levels inconsistent, see GET /consistency: %s", len(consistency.LevelIDs()), len(levels), strings.Join(broken, ", "))
+	case len(pending) > 0:
+		return CheckWarning, fmt.Sprintf("%d of %d levels placing or in ERROR: %s", len(pending), len(levels), strings.Join(pending, ", "))
+	}
+	return CheckOK, fmt.Sprintf("%d levels consistent", len(levels))
+}
+
+// EnterSafeMode stops order placement and the sync job until ResumeFromSafeMode
+func (s *GridService) EnterSafeMode(reason string) {
+	s.safeModeMu.Lock()
+	defer s.safeModeMu.Unlock()
+
+	s.safeModeSince = time.Now().UTC()
+	s.safeModeReason = reason
+	log.Printf("WARNING: Safe mode (%s) - serving read APIs only until POST /safe-mode/resume", reason)
+}
+
+// AnnounceSafeMode reports safe mode to the notifier and webhooks; call once the event sinks are set
+func (s *GridService) AnnounceSafeMode() {
+	inSafeMode, reason := s.SafeMode()
+	if !inSafeMode {
+		return
+	}
+	s.emit(contracts.EventSafeMode, "", fmt.Sprintf("grid-trading started in safe mode (%s), trading waits for POST /safe-mode/resume", reason),
+		map[string]string{"reason": reason})
+}
+
+// ResumeFromSafeMode lets grid-trading trade again. Returns false if it was not in safe mode.
+func (s *GridService) ResumeFromSafeMode() bool {
+	s.safeModeMu.Lock()
+	defer s.safeModeMu.Unlock()
+
+	if s.safeModeSince.IsZero() {
+		return false
+	}
+	log.Printf("INFO: Leaving safe mode after %s, trading resumes", time.Since(s.safeModeSince).Round(time.Second))
+	s.safeModeSince = time.Time{}
+	s.safeModeReason = ""
+	return true
+}
+
+// SafeMode reports whether grid-trading is in safe mode and why
+func (s *GridService) SafeMode() (bool, string) {
+	s.safeModeMu.RLock()
+	defer s.safeModeMu.RUnlock()
+	return !s.safeModeSince.IsZero(), s.safeModeReason
+}
+
+// LastSelfCheck returns the startup self-check with the current safe mode, or nil if none ran
+func (s *GridService) LastSelfCheck() *SelfCheckReport {
+	s.safeModeMu.RLock()
+	defer s.safeModeMu.RUnlock()
+
+	if s.selfCheck == nil {
+		return nil
+	}
+	report := *s.selfCheck
+	report.SafeMode = !s.safeModeSince.IsZero()
+	report.SafeModeReason = s.safeModeReason
+	if report.SafeMode {
+		since := s.safeModeSince
+		report.SafeModeSince = &since
+	}
+	return &report
+}
@@ -0,0 +1,456 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// SellTrancheRepositoryInterface stores the take-profit ladders of levels
+type SellTrancheRepositoryInterface interface {
+	Create(levelID int, tranches []*models.SellTranche) error
+	GetByLevel(levelID int) ([]*models.SellTranche, error)
+	GetByOrderID(orderID string) (*models.SellTranche, error)
+	GetOpen() ([]*models.SellTranche, error)
+	SetAmount(id int, amount decimal.Decimal) error
+	MarkOpen(id int, orderID string, price decimal.Decimal) error
+	MarkSold(id int, orderID string, soldAmount, proceedsUSDT decimal.Decimal, fee models.Fee) (bool, error)
+	Release(id int, orderID string, remaining decimal.Decimal) (bool, error)
+	ReleaseOpen(levelID int) error
+	Reset(levelID int) error
+}
+
+// ErrInvalidSellTranches means a grid's take-profit ladder can't be used
+var ErrInvalidSellTranches = errors.New("invalid sell tranches")
+
+// maxSellTranches is the most tranches a ladder has, each an open order of its own
+const maxSellTranches = 10
+
+// SellTrancheSpec is one step of the take-profit ladder a grid is created with
+type SellTrancheSpec struct {
+	SharePct  decimal.Decimal `json:"share_pct"`  // Percent of the bought amount sold by this tranche
+	ProfitPct decimal.Decimal `json:"profit_pct"` // Sell price as percent above the level's buy price
+}
+
+// SellLadder is the take-profit ladder of a level (GET /levels/{id}/tranches)
+type SellLadder struct {
+	LevelID  int                   `json:"level_id"`
+	Tranches []*models.SellTranche `json:"tranches"` // Empty when the level sells in one order at its sell price
+}
+
+// UseSellLadders lets grids be created with a take-profit ladder (sell_tranches)
+func (s *GridService) UseSellLadders(ladders SellTrancheRepositoryInterface) {
+	s.ladders = ladders
+}
+
+// ValidateSellTranches checks a ladder before a grid is created with it: positive shares that
+// add up to 100% and positive profits
+func ValidateSellTranches(tranches []SellTrancheSpec) error {
+	if len(tranches) > maxSellTranches {
+		return fmt.Errorf("%w: at most %d tranches", ErrInvalidSellTranches, maxSellTranches)
+	}
+
+	total := decimal.Zero
+	for i, tranche := range tranches {
+		if !tranche.SharePct.IsPositive() || !tranche.ProfitPct.IsPositive() {
+			return fmt.Errorf("%w: tranche %d needs a positive share_pct and profit_pct", ErrInvalidSellTranches, i+1)
+		}
+		total = total.Add(tranche.SharePct)
+	}
+	if len(tranches) > 0 && !total.Equal(decimal.NewFromInt(100)) {
+		return fmt.Errorf("%w: shares add up to %s%%, not 100%%", ErrInvalidSellTranches, total)
+	}
+	return nil
+}
+
+// GetSellLadder returns the take-profit ladder of a level, nil if the level does not exist
+func (s *GridService) GetSellLadder(levelID int) (*SellLadder, error) {
+	level, err := s.repo.GetByID(levelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get level %d: %w", levelID, err)
+	}
+	if level == nil {
+		return nil, nil
+	}
+
+	tranches, err := s.sellTranches(levelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sell tranches of level %d: %w", levelID, err)
+	}
+	if tranches == nil {
+		tranches = []*models.SellTranche{}
+	}
+	return &SellLadder{LevelID: levelID, Tranches: tranches}, nil
+}
+
+// createLadder stores the ladder of a new level
+func (s *GridService) createLadder(levelID int, specs []SellTrancheSpec) error {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	tranches := make([]*models.SellTranche, len(specs))
+	for i, spec := range specs {
+		tranches[i] = &models.SellTranche{SharePct: spec.SharePct, ProfitPct: spec.ProfitPct}
+	}
+	return s.ladders.Create(levelID, tranches)
+}
+
+// sellTranches returns the ladder of a level, none without ladders
+func (s *GridService) sellTranches(levelID int) ([]*models.SellTranche, error) {
+	if s.ladders == nil {
+		return nil, nil
+	}
+	return s.ladders.GetByLevel(levelID)
+}
+
+// trancheOf returns the tranche waiting on a sell order, nil if the order is a plain level sell
+func (s *GridService) trancheOf(orderID string) (*models.SellTranche, error) {
+	if s.ladders == nil {
+		return nil, nil
+	}
+	tranche, err := s.ladders.GetByOrderID(orderID)
+	if err != nil {
+		slog.Error("Failed to get sell tranche by order ID", logging.KeyOrderID, orderID, logging.Err(err))
+		return nil, fmt.Errorf("failed to get sell tranche by order ID: %w", err)
+	}
+	return tranche, nil
+}
+
+// resetTranches clears a level's ladder for its next cycle
+func (s *GridService) resetTranches(levelID int) {
+	if s.ladders == nil {
+		return
+	}
+	if err := s.ladders.Reset(levelID); err != nil {
+		slog.Error("Failed to reset sell tranches", logging.KeyLevelID, levelID, logging.Err(err))
+	}
+}
+
+// tranchePrice is profit_pct above the level's buy price, raised like a level's sell price when
+// sells net their spread after fees
+func (s *GridService) tranchePrice(level *models.GridLevel, tranche *models.SellTranche) decimal.Decimal {
+	hundred := decimal.NewFromInt(100)
+	target := level.BuyPrice.Mul(hundred.Add(tranche.ProfitPct)).Div(hundred).RoundCeil(8)
+	if !s.feeAwareSell || s.tradingFee <= 0 || s.tradingFee >= 100 {
+		return target
+	}
+	return feeAwareSellPrice(level.BuyPrice, target, s.tradingFee)
+}
+
+// planTranches splits a level's bought amount between its tranches on the first sell of a
+// cycle, the last tranche taking what the others' rounded-down shares leave
+func (s *GridService) planTranches(level *models.GridLevel, tranches []*models.SellTranche) error {
+	for _, tranche := range tranches {
+		if tranche.Amount.Valid {
+			return nil // Planned earlier in the cycle
+		}
+	}
+
+	filled := level.FilledAmount.Decimal
+	planned := decimal.Zero
+	for i, tranche := range tranches {
+		amount := filled.Sub(planned)
+		if i < len(tranches)-1 {
+			amount = filled.Mul(tranche.SharePct).Div(decimal.NewFromInt(100)).Truncate(8)
+		}
+		if err := s.ladders.SetAmount(tranche.ID, amount); err != nil {
+			return err
+		}
+		tranche.Amount = decimal.NullDecimal{Decimal: amount, Valid: true}
+		planned = planned.Add(amount)
+	}
+	return nil
+}
+
+// placeTranches places a sell order for every unsold tranche of a PLACING_SELL level; the level
+// waits on the first one placed. A tranche that fails to place is placed again once the
+// others are done. Tranches are sold as planned, without the sell quantity policy.
+func (s *GridService) placeTranches(ctx context.Context, level *models.GridLevel, tranches []*models.SellTranche) error {
+	logger := levelLogger(level)
+
+	// The level is placing its sells, so no tranche order is still open
+	if err := s.ladders.ReleaseOpen(level.ID); err != nil {
+		logger.Error("Failed to release sell tranches", logging.Err(err))
+		s.repo.UpdateState(level.ID, models.StateHolding)
+		return fmt.Errorf("failed to release sell tranches: %w", err)
+	}
+	if err := s.planTranches(level, tranches); err != nil {
+		logger.Error("Failed to plan sell tranches", logging.Err(err))
+		s.repo.UpdateState(level.ID, models.StateHolding)
+		return fmt.Errorf("failed to plan sell tranches: %w", err)
+	}
+
+	var lead string
+	var placeErr error
+	for _, tranche := range tranches {
+		if tranche.Done() {
+			continue
+		}
+
+		price := s.tranchePrice(level, tranche)
+		orderReq := client.OrderRequest{
+			Symbol:     level.Symbol,
+			Price:      price,
+			Side:       client.OrderSideSell,
+			Amount:     tranche.Amount.Decimal,
+			Account:    level.Account,
+			TTLSeconds: int(s.orderTTL.Seconds()),
+		}
+
+		logger.Info("Placing sell tranche", "tranche", tranche.Position, "price", price, "amount", orderReq.Amount)
+		orderResp, err := s.assurance.PlaceOrder(ctx, orderReq)
+		if err != nil {
+			logger.Error("Sell tranche placement failed", "tranche", tranche.Position, logging.Err(err))
+			s.pauseOnCircuitOpen(err)
+			s.txRepo.RecordSellError(level.ID, level.Symbol, price, placementErrorCode(err), err.Error())
+			placeErr = err
+			continue
+		}
+
+		if err := s.ladders.MarkOpen(tranche.ID, orderResp.OrderID, price); err != nil {
+			logger.Error("CRITICAL - Placed sell tranche but failed to store its order", "tranche", tranche.Position,
+				logging.KeyOrderID, orderResp.OrderID, logging.Err(err))
+			continue
+		}
+		if err := s.txRepo.RecordSellPlaced(level.ID, level.Symbol, orderResp.OrderID, price, orderReq.Amount); err != nil {
+			logger.Warn("Failed to record sell placed transaction", logging.KeyOrderID, orderResp.OrderID, logging.Err(err))
+		}
+		if lead == "" {
+			lead = orderResp.OrderID
+		}
+		logger.Info("Placed sell tranche", "tranche", tranche.Position, logging.KeyOrderID, orderResp.OrderID, "price", price, "amount", orderReq.Amount)
+	}
+
+	if lead == "" {
+		s.repo.UpdateState(level.ID, models.StateHolding)
+		if placeErr == nil {
+			placeErr = errors.New("no tranche left to sell")
+		}
+		return fmt.Errorf("failed to place sell tranches: %w", placeErr)
+	}
+
+	if err := s.repo.UpdateSellOrderPlaced(level.ID, lead); err != nil {
+		logger.Error("Failed to store placed sell order", logging.KeyOrderID, lead, logging.Err(err))
+		return fmt.Errorf("failed to update sell order placed: %w", err)
+	}
+	if placeErr != nil {
+		logger.Warn("Some sell tranches were not placed, they are placed once the others are done", logging.Err(placeErr))
+	}
+	return nil
+}
+
+// processTrancheFill records the fill of one tranche and moves its level on
+func (s *GridService) processTrancheFill(tranche *models.SellTranche, orderID string, filledAmount, fillPrice, commission decimal.Decimal, commissionAsset, baseAsset string) error {
+	level, err := s.trancheLevel(tranche, orderID)
+	if err != nil || level == nil {
+		return err
+	}
+	logger := levelLogger(level).With(logging.KeyOrderID, orderID, "tranche", tranche.Position)
+
+	fee := newFee(s.resolveBaseAsset(level, baseAsset), commission, commissionAsset, fillPrice)
+	applied, err := s.ladders.MarkSold(tranche.ID, orderID, filledAmount, filledAmount.Mul(fillPrice), fee)
+	if err != nil {
+		logger.Error("Failed to record sell tranche fill", logging.Err(err))
+		return fmt.Errorf("failed to record sell tranche fill: %w", err)
+	}
+	if !applied {
+		logger.Warn("Sell tranche no longer waits on the order, skipping fill")
+		return nil
+	}
+
+	logger.Info("Sell tranche filled", "amount", filledAmount, "price", fillPrice)
+	return s.settleLadder(level, orderID)
+}
+
+// processTrancheCancel records the cancel of one tranche's order, keeping what it didn't sell for
+// its next placement. A cancel after the whole tranche sold is its fill.
+func (s *GridService) processTrancheCancel(tranche *models.SellTranche, orderID string, filledAmount, fillPrice, commission decimal.Decimal, commissionAsset, baseAsset string) error {
+	level, err := s.trancheLevel(tranche, orderID)
+	if err != nil || level == nil {
+		return err
+	}
+
+	remaining := tranche.Amount.Decimal.Sub(filledAmount)
+	if filledAmount.IsPositive() && !remaining.IsPositive() {
+		return s.processTrancheFill(tranche, orderID, filledAmount, fillPrice, commission, commissionAsset, baseAsset)
+	}
+
+	// Record transaction FIRST (audit trail before state change)
+	if err := s.txRepo.RecordCancelled(level.ID, level.Symbol, models.SideSell, orderID, tranche.Price.Decimal, fillPrice, filledAmount); err != nil {
+		return fmt.Errorf("failed to record cancel of order %s: %w", orderID, err)
+	}
+
+	released, err := s.ladders.Release(tranche.ID, orderID, remaining)
+	if err != nil {
+		return fmt.Errorf("failed to release sell tranche %d of level %d: %w", tranche.Position, level.ID, err)
+	}
+	if !released {
+		return nil
+	}
+
+	levelLogger(level).Warn("Sell tranche cancelled", "tranche", tranche.Position, logging.KeyOrderID, orderID,
+		"sold", filledAmount, "unsold", remaining)
+	return s.settleLadder(level, orderID)
+}
+
+// trancheLevel returns the SELL_ACTIVE level of a tranche, nil if the level is gone or moved on
+func (s *GridService) trancheLevel(tranche *models.SellTranche, orderID string) (*models.GridLevel, error) {
+	level, err := s.repo.GetByID(tranche.LevelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get level %d: %w", tranche.LevelID, err)
+	}
+	if level == nil {
+		slog.Warn("No level found for sell tranche (possibly old/deleted)", logging.KeyLevelID, tranche.LevelID, logging.KeyOrderID, orderID)
+		return nil, nil
+	}
+	if level.State != models.StateSellActive {
+		levelLogger(level).Warn("Level not in SELL_ACTIVE state for sell tranche, skipping", logging.KeyOrderID, orderID, "state", level.State)
+		return nil, nil
+	}
+	return level, nil
+}
+
+// settleLadder moves a SELL_ACTIVE level after one of its tranches sold or was cancelled: the
+// cycle completes once every tranche is sold; until then the level waits on another open
+// tranche, or holds the unsold coin for its next trigger to place the rest
+func (s *GridService) settleLadder(level *models.GridLevel, orderID string) error {
+	tranches, err := s.ladders.GetByLevel(level.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get sell tranches of level %d: %w", level.ID, err)
+	}
+
+	var lead string
+	unsold := decimal.Zero
+	done := true
+	for _, tranche := range tranches {
+		if tranche.Done() {
+			continue
+		}
+		done = false
+		unsold = unsold.Add(tranche.Amount.Decimal)
+		if tranche.Status == models.TrancheOpen && lead == "" {
+			lead = tranche.OrderID
+		}
+	}
+
+	logger := levelLogger(level)
+	switch {
+	case done:
+		return s.completeLadder(level, orderID, tranches)
+	case lead != "":
+		logger.Info("Level keeps selling its other tranches", logging.KeyOrderID, lead, "unsold", unsold)
+		if err := s.repo.ReplaceSellOrder(level.ID, lead, unsold); err != nil {
+			return fmt.Errorf("failed to move level %d to sell order %s: %w", level.ID, lead, err)
+		}
+	default:
+		logger.Info("No sell tranche open, level holds the unsold amount for its next trigger", "unsold", unsold)
+		if err := s.repo.KeepUnsold(level.ID, unsold); err != nil {
+			return fmt.Errorf("failed to keep unsold amount of level %d: %w", level.ID, err)
+		}
+	}
+	return nil
+}
+
+// completeLadder records the tranches of a cycle as one sell at their average price and frees the level
+func (s *GridService) completeLadder(level *models.GridLevel, orderID string, tranches []*models.SellTranche) error {
+	sold, proceeds := decimal.Zero, decimal.Zero
+	fee := models.Fee{USDT: decimal.NullDecimal{Valid: true}}
+	mixedAssets := false
+	for _, tranche := range tranches {
+		if !tranche.SoldAmount.Valid {
+			continue
+		}
+		sold = sold.Add(tranche.SoldAmount.Decimal)
+		proceeds = proceeds.Add(tranche.ProceedsUSDT.Decimal)
+
+		if tranche.Fee.USDT.Valid {
+			fee.USDT.Decimal = fee.USDT.Decimal.Add(tranche.Fee.USDT.Decimal)
+		} else {
+			fee.USDT.Valid = false
+		}
+		switch {
+		case tranche.Fee.Asset == "" || (fee.Asset != "" && fee.Asset != tranche.Fee.Asset):
+			mixedAssets = true
+		case fee.Asset == "":
+			fee.Asset = tranche.Fee.Asset
+		}
+		fee.Commission = fee.Commission.Add(tranche.Fee.Commission)
+	}
+	if mixedAssets {
+		fee.Commission, fee.Asset = decimal.Zero, "" // Not one commission to record, only its USDT value if known
+	}
+
+	price := decimal.Zero
+	if sold.IsPositive() {
+		price = proceeds.Div(sold).Round(8)
+	}
+	if err := s.completeSell(level, orderID, sold, price, fee, nil); err != nil {
+		return err
+	}
+	s.resetTranches(level.ID)
+	return nil
+}
+
+// applyTrancheGone applies a tranche order the exchange reports cancelled, or doesn't know
+// (nil status), as the tranche's cancel. Returns false if the order is not a tranche's.
+func (s *GridService) applyTrancheGone(orderID string, status *client.OrderStatus) bool {
+	tranche, err := s.trancheOf(orderID)
+	if err != nil {
+		return true // Not known to be a plain sell - left for the next sync
+	}
+	if tranche == nil {
+		return false
+	}
+
+	filled, price, commission := decimal.Zero, decimal.Zero, decimal.Zero
+	var commissionAsset, baseAsset string
+	if status != nil {
+		filled, price = partialFill(status)
+		commission, commissionAsset, baseAsset = status.CommissionAmount(), status.CommissionAsset, status.BaseAsset
+	}
+	if err := s.processTrancheCancel(tranche, orderID, filled, price, commission, commissionAsset, baseAsset); err != nil {
+		slog.Error("Failed to apply cancel of sell tranche order", logging.KeyOrderID, orderID, logging.Err(err))
+	}
+	return true
+}
+
+// trancheChecks returns the open tranche orders of SELL_ACTIVE levels other than the one each
+// level waits on, for the sync to check with the rest
+func (s *GridService) trancheChecks(levels []*models.GridLevel) []orderCheck {
+	if s.ladders == nil {
+		return nil
+	}
+
+	selling := make(map[int]*models.GridLevel)
+	for _, level := range levels {
+		if level.State == models.StateSellActive {
+			selling[level.ID] = level
+		}
+	}
+	if len(selling) == 0 {
+		return nil
+	}
+
+	open, err := s.ladders.GetOpen()
+	if err != nil {
+		slog.Warn("Failed to get open sell tranches, checking only the orders levels wait on", logging.Err(err))
+		return nil
+	}
+
+	var checks []orderCheck
+	for _, tranche := range open {
+		level := selling[tranche.LevelID]
+		if level == nil || tranche.OrderID == level.SellOrderID.String {
+			continue
+		}
+		checks = append(checks, orderCheck{level: level, orderID: tranche.OrderID, isBuy: false})
+	}
+	return checks
+}
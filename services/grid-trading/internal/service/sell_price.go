@@ -0,0 +1,40 @@
+package service
+
+import (
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// EnableFeeAwareSellPrice raises each sell order above the level's sell_price so the cycle still
+// nets the level's spread after TRADING_FEE is paid on the buy and on the sell
+func (s *GridService) EnableFeeAwareSellPrice() {
+	s.feeAwareSell = true
+}
+
+// sellOrderPrice is the limit price of a level's sell order. Fee-aware, it is the price P where
+//
+//	P × (1 − fee) − buy_price × (1 + fee) = sell_price − buy_price
+//
+// i.e. P = (sell_price + buy_price × fee) / (1 − fee), rounded up to the 8 decimals prices are stored with.
+// Computed from the current TRADING_FEE on every placement, so a fee change applies to the next sells.
+func (s *GridService) sellOrderPrice(level *models.GridLevel) decimal.Decimal {
+	if !s.feeAwareSell || s.tradingFee <= 0 || s.tradingFee >= 100 {
+		return level.SellPrice
+	}
+
+	return feeAwareSellPrice(level.BuyPrice, level.SellPrice, s.tradingFee)
+}
+
+// feeAwareSellPrice is (sell_price + buy_price × fee) / (1 − fee) for a fee in percent below 100
+func feeAwareSellPrice(buyPrice, sellPrice decimal.Decimal, feePct float64) decimal.Decimal {
+	fee := decimal.NewFromFloat(feePct).Div(decimal.NewFromInt(100))
+	return sellPrice.Add(buyPrice.Mul(fee)).
+		Div(decimal.NewFromInt(1).Sub(fee)).
+		RoundCeil(8)
+}
+
+// isSellOrderPrice reports whether an order price reported back by order-assurance is the level's sell:
+// its current order price, or sell_price for orders placed before the adjustment was enabled
+func (s *GridService) isSellOrderPrice(level *models.GridLevel, price decimal.Decimal) bool {
+	return level.SellPrice.Equal(price) || s.sellOrderPrice(level).Equal(price)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Sell quantity policies (SELL_QUANTITY_POLICY) for a bought amount that is not a whole
+// multiple of the exchange's quantity step
+const (
+	SellQuantityNearest   = "nearest"    // Sell the bought amount, order-assurance rounds it to the nearest step
+	SellQuantityRoundDown = "round_down" // Round down, the remainder is recorded as dust and left unsold
+	SellQuantityKeepDust  = "keep_dust"  // Round down, adding the grid's dust once it covers whole steps
+	SellQuantityTopUp     = "top_up"     // Round up, from dust or the free balance when it covers it
+)
+
+// SellDustInterface stores the unsold coin left by rounded sells, per grid
+type SellDustInterface interface {
+	Get(account, symbol string) (decimal.Decimal, error)
+	Set(account, symbol string, dust decimal.Decimal) error
+	GetAll() ([]*models.SellDust, error)
+}
+
+// UseSellQuantityPolicy rounds sell quantities to the exchange's step with policy, tracking the
+// leftover coin in dust. Without it sells use the bought amount as-is (SellQuantityNearest).
+func (s *GridService) UseSellQuantityPolicy(policy string, dust SellDustInterface) {
+	s.sellPolicy = policy
+	s.dust = dust
+}
+
+// placeSell places a level's sell with the policy's quantity, storing the grid's new dust once
+// the exchange accepts it. A grid with stop_loss_pct sells with an OCO, or a plain sell if the
+// exchange won't take one. Returns the amount actually ordered.
+func (s *GridService) placeSell(ctx context.Context, level *models.GridLevel, orderReq client.OrderRequest) (*client.OrderResponse, decimal.Decimal, error) {
+	if s.dust != nil {
+		s.dustMu.Lock()
+		defer s.dustMu.Unlock()
+	}
+
+	plan := s.planSell(level)
+	orderReq.Amount = plan.amount
+
+	if orderReq.OrderType != client.OrderTypeMarket {
+		orderReq.StopPrice = s.stopLossPrice(level)
+	}
+
+	orderResp, err := s.assurance.PlaceOrder(ctx, orderReq)
+	if err != nil && orderReq.StopPrice != nil && ocoRejected(err) {
+		levelLogger(level).Warn("OCO sell rejected, placing it without a stop-loss", logging.Err(err))
+		orderReq.StopPrice = nil
+		orderResp, err = s.assurance.PlaceOrder(ctx, orderReq)
+	}
+	if err != nil {
+		return nil, plan.amount, err
+	}
+
+	s.recordSellDust(level, plan)
+	if orderReq.StopPrice != nil && orderResp.StopOrderID != "" {
+		s.recordStopLoss(level, orderResp, *orderReq.StopPrice)
+	}
+	return orderResp, plan.amount, nil
+}
+
+// sellPlan is the quantity a level's sell uses and the grid's dust once it is placed
+type sellPlan struct {
+	amount    decimal.Decimal
+	dustAfter decimal.Decimal
+	tracked   bool // dustAfter must be stored after the sell is placed
+}
+
+// planSell applies the sell quantity policy to a holding level. Anything it can't look up
+// (step size, dust, balances) falls back to selling the bought amount.
+func (s *GridService) planSell(level *models.GridLevel) sellPlan {
+	filled := level.FilledAmount.Decimal
+	plan := sellPlan{amount: filled}
+	if s.dust == nil || s.sellPolicy == "" || s.sellPolicy == SellQuantityNearest {
+		return plan
+	}
+
+	assets, err := s.assurance.GetSymbolAssets(level.Account, level.Symbol)
+	if err != nil || !assets.StepSize.IsPositive() {
+		levelLogger(level).Warn("No quantity step, selling the full amount", "amount", filled, logging.Err(err))
+		return plan
+	}
+	step := assets.StepSize
+
+	dust, err := s.dust.Get(level.Account, level.Symbol)
+	if err != nil {
+		levelLogger(level).Warn("Failed to read sell dust, selling the full amount", "amount", filled, logging.Err(err))
+		return plan
+	}
+
+	roundDown := func(amount decimal.Decimal) decimal.Decimal {
+		return amount.Div(step).Floor().Mul(step)
+	}
+
+	switch s.sellPolicy {
+	case SellQuantityRoundDown:
+		plan.amount = roundDown(filled)
+		plan.dustAfter = dust.Add(filled.Sub(plan.amount))
+
+	case SellQuantityKeepDust:
+		total := filled.Add(dust)
+		plan.amount = roundDown(total)
+		plan.dustAfter = total.Sub(plan.amount)
+
+	case SellQuantityTopUp:
+		up := filled.Div(step).Ceil().Mul(step)
+		shortfall := up.Sub(filled)
+		switch {
+		case shortfall.IsZero():
+			return plan
+		case dust.GreaterThanOrEqual(shortfall):
+			plan.amount = up
+			plan.dustAfter = dust.Sub(shortfall)
+		case s.freeCoinCovers(level, assets.BaseAsset, up):
+			plan.amount = up
+			plan.dustAfter = decimal.Zero // Dust is part of the free balance the top-up draws on
+		default:
+			plan.amount = roundDown(filled)
+			plan.dustAfter = dust.Add(filled.Sub(plan.amount))
+		}
+	}
+
+	// Less than one step can't be sold either way - leave it to order-assurance's minimum adjustments
+	if !plan.amount.IsPositive() {
+		return sellPlan{amount: filled}
+	}
+
+	plan.tracked = !plan.dustAfter.Equal(dust)
+	if !plan.amount.Equal(filled) {
+		levelLogger(level).Info("Level sells a rounded amount", "amount", plan.amount, "bought", filled,
+			"policy", s.sellPolicy, "step", step, "dust", dust, "dust_after", plan.dustAfter)
+	}
+	return plan
+}
+
+// freeCoinCovers reports whether the account's free balance of the base asset covers amount
+func (s *GridService) freeCoinCovers(level *models.GridLevel, baseAsset string, amount decimal.Decimal) bool {
+	balances, err := s.assurance.GetFreeBalances(level.Account)
+	if err != nil {
+		levelLogger(level).Warn("Failed to get balances to top up the sell", logging.Err(err))
+		return false
+	}
+	return balances[strings.ToUpper(baseAsset)].GreaterThanOrEqual(amount)
+}
+
+// recordSellDust stores the grid's dust once the planned sell is on the exchange
+func (s *GridService) recordSellDust(level *models.GridLevel, plan sellPlan) {
+	if !plan.tracked {
+		return
+	}
+	if err := s.dust.Set(level.Account, level.Symbol, plan.dustAfter); err != nil {
+		levelLogger(level).Error("Failed to record sell dust", "dust", plan.dustAfter, logging.Err(err))
+	}
+}
+
+// GetSellDust reports the unsold coin per grid (empty unless a rounding policy is set)
+func (s *GridService) GetSellDust() ([]*models.SellDust, error) {
+	if s.dust == nil {
+		return []*models.SellDust{}, nil
+	}
+	dust, err := s.dust.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if dust == nil {
+		dust = []*models.SellDust{}
+	}
+	return dust, nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// ErrShadowStrategyRejected wraps shadow strategies with invalid parameters
+var ErrShadowStrategyRejected = errors.New("shadow strategy rejected")
+
+// ShadowStrategyRepositoryInterface stores the alternative grids run in shadow next to live ones
+type ShadowStrategyRepositoryInterface interface {
+	Create(strategy *models.ShadowStrategy) error
+	Get(id int) (*models.ShadowStrategy, error)
+	GetBySymbol(symbol string) ([]*models.ShadowStrategy, error)
+	Delete(id int) (bool, error)
+}
+
+// ShadowStrategyRequest describes a grid to run in shadow: the parameters of POST /grids plus the
+// order settings that are otherwise global, so a changed BUY_ORDER_TYPE or FEE_AWARE_SELL can be
+// tried on one symbol before switching
+type ShadowStrategyRequest struct {
+	Name         string          `json:"name"`
+	GridType     string          `json:"grid_type,omitempty"` // arithmetic (default) | geometric
+	MinPrice     decimal.Decimal `json:"min_price"`
+	MaxPrice     decimal.Decimal `json:"max_price"`
+	GridStep     decimal.Decimal `json:"grid_step"`
+	GridStepPct  decimal.Decimal `json:"grid_step_pct,omitempty"`
+	BuyAmount    decimal.Decimal `json:"buy_amount"`
+	BuyOrderType string          `json:"buy_order_type,omitempty"` // limit (default) | quote_market
+	FeeAwareSell bool            `json:"fee_aware_sell"`
+}
+
+// CycleResult counts completed cycles (filled sells) and their profit
+type CycleResult struct {
+	Cycles     int             `json:"cycles"`
+	ProfitUSDT decimal.Decimal `json:"profit_usdt"`
+}
+
+// StrategyComparison sets a shadow strategy against the live grid of its symbol since the strategy
+// was created, when both started seeing the same triggers
+type StrategyComparison struct {
+	Strategy       *models.ShadowStrategy `json:"strategy"`
+	Levels         int                    `json:"levels"`
+	Since          time.Time              `json:"since"`
+	Live           CycleResult            `json:"live"`
+	Shadow         CycleResult            `json:"shadow"`
+	ShadowOpen     int                    `json:"shadow_open_orders"`
+	ProfitDiffUSDT decimal.Decimal        `json:"profit_diff_usdt"` // Shadow less live; positive when the strategy did better
+}
+
+// StrategyComparisonReport compares every shadow strategy of a symbol with its live grid
+type StrategyComparisonReport struct {
+	Symbol        string                `json:"symbol"`
+	LiveWatchOnly bool                  `json:"live_watch_only"` // Live results are the watch-only grid's shadow fills
+	Strategies    []*StrategyComparison `json:"strategies"`
+}
+
+// UseShadowStrategies runs the symbols' shadow strategies on every price trigger
+func (s *GridService) UseShadowStrategies(strategies ShadowStrategyRepositoryInterface) {
+	s.strategies = strategies
+}
+
+// CreateShadowStrategy starts running a grid in shadow next to the symbol's live grid
+func (s *GridService) CreateShadowStrategy(symbol string, req ShadowStrategyRequest) (*models.ShadowStrategy, error) {
+	if s.strategies == nil || s.shadow == nil {
+		return nil, fmt.Errorf("shadow strategies are not enabled")
+	}
+
+	strategy := &models.ShadowStrategy{
+		Symbol:       symbol,
+		Name:         strings.TrimSpace(req.Name),
+		GridType:     req.GridType,
+		MinPrice:     req.MinPrice,
+		MaxPrice:     req.MaxPrice,
+		GridStep:     req.GridStep,
+		BuyAmount:    req.BuyAmount,
+		BuyOrderType: req.BuyOrderType,
+		FeeAwareSell: req.FeeAwareSell,
+	}
+	if !req.MinPrice.IsPositive() || !req.MaxPrice.IsPositive() {
+		return nil, fmt.Errorf("%w: min and max prices must be positive", ErrShadowStrategyRejected)
+	}
+	if req.MinPrice.GreaterThanOrEqual(req.MaxPrice) {
+		return nil, fmt.Errorf("%w: min price must be less than max price", ErrShadowStrategyRejected)
+	}
+	switch req.GridType {
+	case "", GridTypeArithmetic:
+		strategy.GridType = GridTypeArithmetic
+		if !req.GridStep.IsPositive() {
+			return nil, fmt.Errorf("%w: grid step must be positive", ErrShadowStrategyRejected)
+		}
+	case GridTypeGeometric:
+		if !req.GridStep.IsZero() {
+			return nil, fmt.Errorf("%w: a geometric grid takes grid_step_pct instead of grid_step", ErrShadowStrategyRejected)
+		}
+		if !req.GridStepPct.IsPositive() {
+			return nil, fmt.Errorf("%w: grid step percentage must be positive", ErrShadowStrategyRejected)
+		}
+		strategy.GridStep = req.GridStepPct
+	default:
+		return nil, fmt.Errorf("%w: grid type must be arithmetic or geometric", ErrShadowStrategyRejected)
+	}
+	if !req.BuyAmount.IsPositive() {
+		return nil, fmt.Errorf("%w: buy amount must be positive", ErrShadowStrategyRejected)
+	}
+	switch req.BuyOrderType {
+	case "":
+		strategy.BuyOrderType = "limit"
+	case "limit", "quote_market":
+	default:
+		return nil, fmt.Errorf("%w: buy order type must be limit or quote_market", ErrShadowStrategyRejected)
+	}
+	if len(levelPrices(strategy.GridType, strategy.MinPrice, strategy.MaxPrice, strategy.GridStep)) == 0 {
+		return nil, fmt.Errorf("%w: the grid has no level between min and max price", ErrShadowStrategyRejected)
+	}
+
+	if err := s.strategies.Create(strategy); err != nil {
+		return nil, err
+	}
+	strategy.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	log.Printf("INFO: Shadow strategy %d %q created for %s: %s %s-%s step %s, %s USDT %s buys, fee-aware sell %t",
+		strategy.ID, strategy.Name, symbol, strategy.GridType, strategy.MinPrice, strategy.MaxPrice,
+		strategy.GridStep, strategy.BuyAmount, strategy.BuyOrderType, strategy.FeeAwareSell)
+	return strategy, nil
+}
+
+// GetShadowStrategies returns the shadow strategies of a symbol, oldest first
+func (s *GridService) GetShadowStrategies(symbol string) ([]*models.ShadowStrategy, error) {
+	if s.strategies == nil {
+		return nil, fmt.Errorf("shadow strategies are not enabled")
+	}
+	strategies, err := s.strategies.GetBySymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if strategies == nil {
+		strategies = []*models.ShadowStrategy{}
+	}
+	return strategies, nil
+}
+
+// DeleteShadowStrategy stops a shadow strategy of a symbol and forgets its shadow transactions.
+// Returns false if the symbol has no such strategy.
+func (s *GridService) DeleteShadowStrategy(symbol string, id int) (bool, error) {
+	if s.strategies == nil || s.shadow == nil {
+		return false, fmt.Errorf("shadow strategies are not enabled")
+	}
+	strategy, err := s.strategies.Get(id)
+	if err != nil || strategy == nil || strategy.Symbol != symbol {
+		return false, err
+	}
+
+	deleted, err := s.strategies.Delete(id)
+	if err != nil || !deleted {
+		return false, err
+	}
+	txs, err := s.shadow.DeleteBySymbol(symbol, id)
+	if err != nil {
+		return true, fmt.Errorf("failed to delete shadow transactions of strategy %d: %w", id, err)
+	}
+	log.Printf("INFO: Shadow strategy %d of %s deleted with %d shadow transactions", id, symbol, txs)
+	return true, nil
+}
+
+// runShadowStrategies steps the levels of each shadow strategy of a symbol on a trigger, under the
+// same pauses as the live grid
+func (s *GridService) runShadowStrategies(symbol string, price decimal.Decimal, buysPaused bool, pauseReason string) {
+	if s.strategies == nil || s.shadow == nil {
+		return
+	}
+	strategies, err := s.strategies.GetBySymbol(symbol)
+	if err != nil {
+		log.Printf("ERROR: Failed to get %s shadow strategies: %v", symbol, err)
+		return
+	}
+
+	for _, strategy := range strategies {
+		latest, err := s.shadow.GetLatestByLevel(symbol, strategy.ID)
+		if err != nil {
+			log.Printf("ERROR: Failed to get shadow transactions of strategy %d: %v", strategy.ID, err)
+			continue
+		}
+		for _, level := range s.strategyLevels(strategy, price) {
+			if err := s.stepShadow(strategy.ID, level, latest[level.ID], price, buysPaused, pauseReason); err != nil {
+				log.Printf("ERROR: Failed to record shadow order of strategy %d level %d: %v", strategy.ID, level.ID, err)
+			}
+		}
+	}
+}
+
+// strategyLevels lays out a shadow strategy's grid as levels numbered from 1 at the lowest buy price
+func (s *GridService) strategyLevels(strategy *models.ShadowStrategy, price decimal.Decimal) []shadowLevel {
+	prices := levelPrices(strategy.GridType, strategy.MinPrice, strategy.MaxPrice, strategy.GridStep)
+	marketBuy := strategy.BuyOrderType == "quote_market"
+	amount := strategy.BuyAmount
+
+	levels := make([]shadowLevel, len(prices))
+	for i, p := range prices {
+		buyPrice, sellPrice := p[0], p[1]
+		level := shadowLevel{
+			ID:             i + 1,
+			Symbol:         strategy.Symbol,
+			BuyPrice:       buyPrice,
+			SellOrderPrice: sellPrice,
+			Enabled:        true,
+			CanBuy:         price.GreaterThanOrEqual(buyPrice) && price.LessThan(sellPrice),
+			MarketBuy:      marketBuy,
+			BuyAmount:      func() decimal.Decimal { return amount },
+		}
+		if marketBuy {
+			level.CanBuy = price.LessThanOrEqual(buyPrice)
+		}
+		if strategy.FeeAwareSell && s.tradingFee > 0 && s.tradingFee < 100 {
+			level.SellOrderPrice = feeAwareSellPrice(buyPrice, sellPrice, s.tradingFee)
+		}
+		levels[i] = level
+	}
+	return levels
+}
+
+// CompareShadowStrategies sets each shadow strategy of a symbol against its live grid over the
+// time since the strategy was created. Live results are the filled sells of the symbol's levels,
+// or the watch-only grid's shadow sells while the symbol is watch-only.
+func (s *GridService) CompareShadowStrategies(symbol string) (*StrategyComparisonReport, error) {
+	if s.strategies == nil || s.shadow == nil {
+		return nil, fmt.Errorf("shadow strategies are not enabled")
+	}
+	strategies, err := s.strategies.GetBySymbol(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s shadow strategies: %w", symbol, err)
+	}
+
+	report := &StrategyComparisonReport{
+		Symbol:        symbol,
+		LiveWatchOnly: s.watchOnly(symbol),
+		Strategies:    []*StrategyComparison{},
+	}
+	var watchOnlyTxs []*models.ShadowTransaction
+	if report.LiveWatchOnly {
+		if watchOnlyTxs, err = s.shadow.GetBySymbol(symbol, 0); err != nil {
+			return nil, fmt.Errorf("failed to get %s shadow transactions: %w", symbol, err)
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, strategy := range strategies {
+		txs, err := s.shadow.GetBySymbol(symbol, strategy.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get shadow transactions of strategy %d: %w", strategy.ID, err)
+		}
+		shadow := summarizeShadow(txs, time.Time{})
+
+		comparison := &StrategyComparison{
+			Strategy:   strategy,
+			Levels:     len(levelPrices(strategy.GridType, strategy.MinPrice, strategy.MaxPrice, strategy.GridStep)),
+			Since:      strategy.CreatedAt,
+			Shadow:     CycleResult{Cycles: shadow.SellsFilled, ProfitUSDT: shadow.ProfitUSDT},
+			ShadowOpen: shadow.OpenOrders,
+		}
+
+		if report.LiveWatchOnly {
+			live := summarizeShadow(watchOnlyTxs, strategy.CreatedAt)
+			comparison.Live = CycleResult{Cycles: live.SellsFilled, ProfitUSDT: live.ProfitUSDT}
+		} else {
+			cycles, err := s.txRepo.GetLevelCycles(strategy.CreatedAt, now)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get level cycles: %w", err)
+			}
+			for _, level := range cycles {
+				if level.Symbol == symbol {
+					comparison.Live.Cycles += level.Cycles
+					comparison.Live.ProfitUSDT = comparison.Live.ProfitUSDT.Add(level.ProfitUSDT)
+				}
+			}
+		}
+		comparison.ProfitDiffUSDT = comparison.Shadow.ProfitUSDT.Sub(comparison.Live.ProfitUSDT)
+		report.Strategies = append(report.Strategies, comparison)
+	}
+	return report, nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// ErrNotOwner means a trigger reached an instance while another one holds the symbol's lease
+var ErrNotOwner = errors.New("symbol is traded by another instance")
+
+// ShardStore is what the shard coordinator needs from the shared shard tables
+type ShardStore interface {
+	Heartbeat(instanceID, url string, ttl time.Duration) error
+	Deregister(instanceID string) error
+	Instances() ([]*models.ShardInstance, error)
+	Leases() ([]*models.SymbolLease, error)
+	RenewLeases(instanceID string, ttl time.Duration) (int, error)
+	Claim(symbol, instanceID, url string, ttl time.Duration) (bool, error)
+	Release(symbol, instanceID string) error
+}
+
+// ShardCoordinator splits the grid symbols between grid-trading instances sharing one database
+// (SHARDING_ENABLED). Every instance heartbeats, renews its symbol leases and, a third of a lease
+// later, claims free or expired symbols up to its fair share - ceil(symbols / live instances) -
+// releasing any above it, so a joining instance takes symbols over and a stopped or crashed
+// instance's symbols move once its leases lapse. The instance with the lowest ID is the leader.
+type ShardCoordinator struct {
+	store      ShardStore
+	symbols    interface{ GetDistinctSymbols() ([]string, error) }
+	instanceID string
+	url        string
+	ttl        time.Duration
+
+	mu        sync.RWMutex
+	instances []*models.ShardInstance
+	leases    map[string]*models.SymbolLease // Live leases of all instances, by symbol
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewShardCoordinator(store ShardStore, symbols interface{ GetDistinctSymbols() ([]string, error) }, instanceID, url string, ttl time.Duration) *ShardCoordinator {
+	return &ShardCoordinator{
+		store:      store,
+		symbols:    symbols,
+		instanceID: instanceID,
+		url:        url,
+		ttl:        ttl,
+		leases:     make(map[string]*models.SymbolLease),
+	}
+}
+
+// Start claims this instance's share of the symbols and keeps rebalancing until Stop
+func (c *ShardCoordinator) Start() {
+	c.rebalance()
+
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(c.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.rebalance()
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop deregisters the instance, freeing its symbols for the others straight away
+func (c *ShardCoordinator) Stop() {
+	close(c.stop)
+	<-c.done
+	logger := slog.With("instance_id", c.instanceID)
+	if err := c.store.Deregister(c.instanceID); err != nil {
+		logger.Error("Failed to release the shard leases", logging.Err(err))
+		return
+	}
+	logger.Info("Shard leases released")
+}
+
+func (c *ShardCoordinator) rebalance() {
+	logger := slog.With("instance_id", c.instanceID)
+	if err := c.store.Heartbeat(c.instanceID, c.url, c.ttl); err != nil {
+		logger.Error("Shard heartbeat failed", logging.Err(err))
+		return
+	}
+	if _, err := c.store.RenewLeases(c.instanceID, c.ttl); err != nil {
+		logger.Error("Failed to renew shard leases", logging.Err(err))
+		return
+	}
+
+	instances, err := c.store.Instances()
+	if err != nil {
+		logger.Error("Failed to read shard instances", logging.Err(err))
+		return
+	}
+	leases, err := c.store.Leases()
+	if err != nil {
+		logger.Error("Failed to read shard leases", logging.Err(err))
+		return
+	}
+	symbols, err := c.symbols.GetDistinctSymbols()
+	if err != nil {
+		logger.Error("Failed to get grid symbols for sharding", logging.Err(err))
+		return
+	}
+	sort.Strings(symbols)
+
+	share := len(symbols)
+	if len(instances) > 1 {
+		share = (len(symbols) + len(instances) - 1) / len(instances)
+	}
+
+	leased := make(map[string]*models.SymbolLease, len(leases))
+	for _, lease := range leases {
+		leased[lease.Symbol] = lease
+	}
+	isGrid := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		isGrid[symbol] = true
+	}
+
+	var mine, claimed, released []string
+	for _, lease := range leases {
+		if lease.InstanceID != c.instanceID {
+			continue
+		}
+		// Grids deleted meanwhile, and anything above the fair share once another instance joined
+		if !isGrid[lease.Symbol] || len(mine) >= share {
+			if err := c.store.Release(lease.Symbol, c.instanceID); err != nil {
+				logger.Error("Failed to release shard lease", logging.KeySymbol, lease.Symbol, logging.Err(err))
+				mine = append(mine, lease.Symbol)
+				continue
+			}
+			released = append(released, lease.Symbol)
+			continue
+		}
+		mine = append(mine, lease.Symbol)
+	}
+
+	for _, symbol := range symbols {
+		if len(mine) >= share {
+			break
+		}
+		if _, taken := leased[symbol]; taken {
+			continue
+		}
+		ok, err := c.store.Claim(symbol, c.instanceID, c.url, c.ttl)
+		if err != nil {
+			logger.Error("Failed to claim shard lease", logging.KeySymbol, symbol, logging.Err(err))
+			continue
+		}
+		if ok {
+			mine = append(mine, symbol)
+			claimed = append(claimed, symbol)
+		}
+	}
+
+	if len(claimed) > 0 || len(released) > 0 {
+		if leases, err = c.store.Leases(); err != nil {
+			logger.Error("Failed to read shard leases", logging.Err(err))
+			return
+		}
+		logger.Info("Shard leases rebalanced", "claimed", claimed, "released", released,
+			"trading", len(mine), "symbols", len(symbols), "instances", len(instances))
+	}
+
+	c.mu.Lock()
+	c.instances = instances
+	c.leases = make(map[string]*models.SymbolLease, len(leases))
+	for _, lease := range leases {
+		c.leases[lease.Symbol] = lease
+	}
+	c.mu.Unlock()
+}
+
+// OwnedElsewhere reports whether another instance holds a live lease of a symbol. Symbols
+// nobody holds (not yet claimed, or the peg symbol) are traded by whichever instance gets
+// the trigger - the level state transitions keep two instances from placing the same order.
+func (c *ShardCoordinator) OwnedElsewhere(symbol string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	lease, ok := c.leases[symbol]
+	return ok && lease.InstanceID != c.instanceID && time.Now().Before(lease.ExpiresAt)
+}
+
+// IsLeader reports whether this instance runs the cluster-wide jobs (lowest live instance ID)
+func (c *ShardCoordinator) IsLeader() bool {
+	return c.leader() == c.instanceID
+}
+
+func (c *ShardCoordinator) leader() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.instances) == 0 {
+		return ""
+	}
+	return c.instances[0].InstanceID
+}
+
+// ShardMap returns the instances and symbol leases as of the last rebalance (GET /shards)
+func (c *ShardCoordinator) ShardMap() *contracts.ShardMap {
+	result := &contracts.ShardMap{InstanceID: c.instanceID, Leader: c.leader()}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, instance := range c.instances {
+		result.Instances = append(result.Instances, *instance)
+	}
+	for _, lease := range c.leases {
+		result.Leases = append(result.Leases, *lease)
+	}
+	sort.Slice(result.Leases, func(i, j int) bool { return result.Leases[i].Symbol < result.Leases[j].Symbol })
+	return result
+}
+
+// UseSharding trades only the symbols no other instance holds a lease of
+func (s *GridService) UseSharding(shards *ShardCoordinator) {
+	s.shards = shards
+}
+
+// ownedLevels drops the levels of symbols another instance trades
+func (s *GridService) ownedLevels(levels []*models.GridLevel) []*models.GridLevel {
+	if s.shards == nil {
+		return levels
+	}
+	owned := levels[:0:0]
+	for _, level := range levels {
+		if !s.shards.OwnedElsewhere(level.Symbol) {
+			owned = append(owned, level)
+		}
+	}
+	return owned
+}
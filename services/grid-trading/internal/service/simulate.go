@@ -0,0 +1,194 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/klines"
+	"github.com/shopspring/decimal"
+)
+
+// ErrSimulationRejected wraps simulations that can't run (no levels, no price, too little history)
+var ErrSimulationRejected = errors.New("simulation rejected")
+
+const (
+	// Volatility is measured on hourly closes over simulationDefaultDays unless asked otherwise
+	simulationInterval    = "1h"
+	simulationDefaultDays = 7
+	simulationMaxDays     = 90
+	simulationMinKlines   = 24
+)
+
+// KlineSourceInterface loads historical candles, downloading the ones not stored yet
+type KlineSourceInterface interface {
+	Load(symbol, interval string, from, to time.Time) ([]klines.Kline, error)
+	Sync(symbol, interval string, from, to time.Time) (int, error)
+	Range(symbol, interval string, from, to time.Time) ([]klines.Kline, error)
+}
+
+// SimulationRequest tunes a grid simulation; every field is optional
+type SimulationRequest struct {
+	Price        decimal.NullDecimal `json:"price,omitempty"`         // Default: latest price
+	LookbackDays int                 `json:"lookback_days,omitempty"` // Volatility window (default 7, max 90)
+}
+
+// SimulationResult estimates how an existing grid trades at the current price and volatility
+type SimulationResult struct {
+	Symbol             string          `json:"symbol"`
+	Price              decimal.Decimal `json:"price"`
+	LookbackDays       int             `json:"lookback_days"`
+	Klines             int             `json:"klines"`               // Hourly candles the volatility was measured on
+	DailyVolatilityPct decimal.Decimal `json:"daily_volatility_pct"` // Standard deviation of daily log returns
+	Levels             int             `json:"levels"`               // Enabled levels
+	LowestBuyPrice     decimal.Decimal `json:"lowest_buy_price"`
+	HighestSellPrice   decimal.Decimal `json:"highest_sell_price"`
+	InRange            bool            `json:"in_range"` // Price between the lowest buy and the highest sell
+	AvgStepPct         decimal.Decimal `json:"avg_step_pct"`
+	CapitalUSDT        decimal.Decimal `json:"capital_usdt"`          // Worst-case buy amounts of all levels (see CapitalEstimate)
+	ProfitPerCycleUSDT decimal.Decimal `json:"profit_per_cycle_usdt"` // Average over levels, after TRADING_FEE on both legs
+
+	ExpectedFillsPerDay        decimal.Decimal `json:"expected_fills_per_day"`
+	ExpectedCyclesPerDay       decimal.Decimal `json:"expected_cycles_per_day"` // Buy and sell pairs
+	ProjectedMonthlyProfitUSDT decimal.Decimal `json:"projected_monthly_profit_usdt"`
+	ProjectedMonthlyReturnPct  decimal.Decimal `json:"projected_monthly_return_pct"` // On CapitalUSDT
+}
+
+// UseKlines reads price history for grid simulations and the klines API
+func (s *GridService) UseKlines(source KlineSourceInterface) {
+	s.klines = source
+}
+
+// SimulateGrid estimates the fills and monthly profit of a symbol's enabled levels. The price is
+// modelled as a random walk with the volatility of recent hourly klines: a walk with daily
+// deviation σ crosses grid lines a step g apart about σ²/g² times a day, each crossing filling
+// one order. A grid the price is outside of fills nothing until the price comes back.
+func (s *GridService) SimulateGrid(symbol string, req SimulationRequest) (*SimulationResult, error) {
+	if s.klines == nil {
+		return nil, fmt.Errorf("%w: price history is not available", ErrSimulationRejected)
+	}
+
+	days := req.LookbackDays
+	if days == 0 {
+		days = simulationDefaultDays
+	}
+	if days < 1 || days > simulationMaxDays {
+		return nil, fmt.Errorf("%w: lookback_days must be between 1 and %d", ErrSimulationRejected, simulationMaxDays)
+	}
+
+	price := req.Price.Decimal
+	if !req.Price.Valid {
+		quote, _ := s.latestPrice(symbol)
+		if quote == nil || s.isStale(quote) {
+			return nil, fmt.Errorf("%w: no recent %s price, pass one to simulate at", ErrSimulationRejected, symbol)
+		}
+		price = quote.Price
+	}
+
+	levels, err := s.repo.GetBySymbol(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+
+	result := &SimulationResult{Symbol: symbol, Price: price, LookbackDays: days}
+	var buyPrices []float64
+	var profitSum decimal.Decimal
+	hundred := decimal.NewFromInt(100)
+	fee := decimal.NewFromFloat(s.tradingFee).Div(hundred)
+	for _, level := range levels {
+		if !level.Enabled {
+			continue
+		}
+		if result.Levels == 0 || level.BuyPrice.LessThan(result.LowestBuyPrice) {
+			result.LowestBuyPrice = level.BuyPrice
+		}
+		if level.SellPrice.GreaterThan(result.HighestSellPrice) {
+			result.HighestSellPrice = level.SellPrice
+		}
+		result.Levels++
+		buyPrices = append(buyPrices, level.BuyPrice.InexactFloat64())
+
+		// Buy amount worth of coins sold at the sell price, the fee paid on both legs
+		ratio := level.SellPrice.Div(level.BuyPrice)
+		gross := level.BuyAmount.Mul(ratio.Sub(decimal.NewFromInt(1)))
+		fees := level.BuyAmount.Mul(fee).Mul(ratio.Add(decimal.NewFromInt(1)))
+		profitSum = profitSum.Add(gross.Sub(fees))
+	}
+	if result.Levels == 0 {
+		return nil, fmt.Errorf("%w: %s has no enabled levels", ErrSimulationRejected, symbol)
+	}
+
+	result.CapitalUSDT = estimateCapital(symbol, levels).WorstCaseUSDT
+	result.ProfitPerCycleUSDT = profitSum.Div(decimal.NewFromInt(int64(result.Levels))).Round(8)
+	result.InRange = price.GreaterThanOrEqual(result.LowestBuyPrice) && price.LessThanOrEqual(result.HighestSellPrice)
+
+	// Step between adjacent buy prices, in log terms; a single level steps to its own sell price
+	sort.Float64s(buyPrices)
+	var stepSum float64
+	steps := 0
+	for i := 1; i < len(buyPrices); i++ {
+		if buyPrices[i] > buyPrices[i-1] {
+			stepSum += math.Log(buyPrices[i] / buyPrices[i-1])
+			steps++
+		}
+	}
+	if steps == 0 {
+		stepSum, steps = math.Log(result.HighestSellPrice.Div(result.LowestBuyPrice).InexactFloat64()), 1
+	}
+	step := stepSum / float64(steps)
+	result.AvgStepPct = decimal.NewFromFloat((math.Exp(step) - 1) * 100).Round(4)
+
+	to := time.Now().UTC().Truncate(time.Hour)
+	history, err := s.klines.Load(symbol, simulationInterval, to.AddDate(0, 0, -days), to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s klines: %w", symbol, err)
+	}
+	result.Klines = len(history)
+	if len(history) < simulationMinKlines {
+		return nil, fmt.Errorf("%w: only %d hourly klines of %s, at least %d are needed",
+			ErrSimulationRejected, len(history), symbol, simulationMinKlines)
+	}
+
+	dailyVol := hourlyVolatility(history) * math.Sqrt(24)
+	result.DailyVolatilityPct = decimal.NewFromFloat(dailyVol * 100).Round(4)
+
+	if result.InRange && step > 0 {
+		fills := dailyVol * dailyVol / (step * step)
+		result.ExpectedFillsPerDay = decimal.NewFromFloat(fills).Round(2)
+		result.ExpectedCyclesPerDay = decimal.NewFromFloat(fills / 2).Round(2)
+		result.ProjectedMonthlyProfitUSDT = result.ProfitPerCycleUSDT.Mul(decimal.NewFromFloat(fills / 2 * 30)).Round(2)
+	}
+	if result.CapitalUSDT.IsPositive() {
+		result.ProjectedMonthlyReturnPct = result.ProjectedMonthlyProfitUSDT.Div(result.CapitalUSDT).Mul(hundred).Round(2)
+	}
+
+	return result, nil
+}
+
+// hourlyVolatility is the sample standard deviation of log returns between consecutive closes
+func hourlyVolatility(history []klines.Kline) float64 {
+	var returns []float64
+	for i := 1; i < len(history); i++ {
+		prev, curr := history[i-1].Close.InexactFloat64(), history[i].Close.InexactFloat64()
+		if prev > 0 && curr > 0 {
+			returns = append(returns, math.Log(curr/prev))
+		}
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(variance / float64(len(returns)-1))
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// spreadTooWideCode is the error code recorded for a placement skipped over a wide spread
+	spreadTooWideCode = "spread_too_wide"
+
+	// spreadBookTTL reuses a book read for the other levels of the same trigger
+	spreadBookTTL = 2 * time.Second
+)
+
+// bookRead is the last order book read of an account's symbol
+type bookRead struct {
+	book   *client.BookTicker
+	readAt time.Time
+}
+
+// UseSpreadGuard reads the best bid and ask from order-assurance before every placement and skips
+// it while the spread is wider than maxSpreadPct of the mid price - an illiquid moment where the
+// trigger price says little about where an order would fill. The level keeps its state and is
+// placed on a later trigger once the book is back to normal. Orders keep the level's prices:
+// rejections, adoption and sync retries match orders to levels by price.
+func (s *GridService) UseSpreadGuard(maxSpreadPct decimal.Decimal) {
+	s.maxSpreadPct = maxSpreadPct
+	s.spreadBooks = make(map[string]bookRead)
+	s.spreadSkipped = make(map[int]bool)
+}
+
+// spreadAllows reports whether a level's order may be placed now. The first skip of a level
+// is recorded as an ERROR transaction with the reason; repeats until it places are only logged.
+// A failed book read lets the placement through - the guard must not stop a grid on its own.
+func (s *GridService) spreadAllows(level *models.GridLevel, side client.OrderSide, price decimal.Decimal) bool {
+	if !s.maxSpreadPct.IsPositive() {
+		return true
+	}
+
+	book, err := s.orderBook(level.Account, level.Symbol)
+	if err != nil {
+		log.Printf("WARNING: Failed to read %s order book, placing level %d without the spread check: %v", level.Symbol, level.ID, err)
+		return true
+	}
+
+	spread := book.Spread().Round(4)
+	s.spreadMu.Lock()
+	recorded := s.spreadSkipped[level.ID]
+	if spread.LessThanOrEqual(s.maxSpreadPct) {
+		delete(s.spreadSkipped, level.ID)
+		s.spreadMu.Unlock()
+		return true
+	}
+	s.spreadSkipped[level.ID] = true
+	s.spreadMu.Unlock()
+
+	s.spreadSkips.Add(1)
+	reason := fmt.Sprintf("spread %s%% above MAX_SPREAD_PCT %s%% (bid %s, ask %s)", spread, s.maxSpreadPct, book.BidPrice, book.AskPrice)
+	if recorded {
+		log.Printf("DEBUG: Level %d %s still skipped: %s", level.ID, side, reason)
+		return false
+	}
+
+	log.Printf("WARNING: Skipping %s of level %d: %s", side, level.ID, reason)
+	if side == client.OrderSideBuy {
+		err = s.txRepo.RecordBuyError(level.ID, level.Symbol, price, spreadTooWideCode, reason)
+	} else {
+		err = s.txRepo.RecordSellError(level.ID, level.Symbol, price, spreadTooWideCode, reason)
+	}
+	if err != nil {
+		log.Printf("WARNING: Failed to record spread skip of level %d: %v", level.ID, err)
+	}
+	return false
+}
+
+// orderBook returns the book of an account's symbol, read at most once per spreadBookTTL
+func (s *GridService) orderBook(account, symbol string) (*client.BookTicker, error) {
+	key := account + "/" + symbol
+
+	s.spreadMu.Lock()
+	cached, ok := s.spreadBooks[key]
+	s.spreadMu.Unlock()
+	if ok && time.Since(cached.readAt) < spreadBookTTL {
+		return cached.book, nil
+	}
+
+	book, err := s.assurance.GetBookTicker(account, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	s.spreadMu.Lock()
+	s.spreadBooks[key] = bookRead{book: book, readAt: time.Now()}
+	s.spreadMu.Unlock()
+	return book, nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"log/slog"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// StopLossRepositoryInterface stores the stop-loss legs of levels' OCO sells
+type StopLossRepositoryInterface interface {
+	Save(order *models.StopLossOrder) error
+	GetByLevel(levelID int) (*models.StopLossOrder, error)
+	GetByStopOrderID(orderID string) (*models.StopLossOrder, error)
+	Delete(levelID int) error
+}
+
+// UseStopLossOrders lets grids with stop_loss_pct in their config sell with an OCO: the usual
+// limit sell plus a stop-loss below the buy price, placed in one call
+func (s *GridService) UseStopLossOrders(stopLosses StopLossRepositoryInterface) {
+	s.stopLosses = stopLosses
+}
+
+// stopLossPrice returns the stop price of a level's OCO sell, nil if it sells with a plain order
+func (s *GridService) stopLossPrice(level *models.GridLevel) *decimal.Decimal {
+	if s.stopLosses == nil || s.configs == nil {
+		return nil
+	}
+	config, err := s.configs.Get(level.Symbol)
+	if err != nil {
+		levelLogger(level).Warn("Failed to read grid config, level sells without a stop-loss", logging.Err(err))
+		return nil
+	}
+	if config == nil || !config.StopLossPct.Valid || config.OrderType == client.OrderTypeMarket {
+		return nil
+	}
+	hundred := decimal.NewFromInt(100)
+	stop := level.BuyPrice.Mul(hundred.Sub(config.StopLossPct.Decimal)).Div(hundred)
+	return &stop
+}
+
+// ocoRejected reports whether order-assurance turned down an OCO sell for its stop-loss - the
+// venue doesn't support it, or the stop price fails the symbol's filters - so a plain sell may go through
+func ocoRejected(err error) bool {
+	switch placementErrorCode(err) {
+	case "unsupported_order", "unsupported_venue", "filter_failure", "exchange_rejected":
+		return true
+	}
+	return false
+}
+
+// recordStopLoss stores the stop-loss leg of the OCO sell just placed for a level
+func (s *GridService) recordStopLoss(level *models.GridLevel, orderResp *client.OrderResponse, stopPrice decimal.Decimal) {
+	order := &models.StopLossOrder{
+		LevelID:     level.ID,
+		SellOrderID: orderResp.OrderID,
+		StopOrderID: orderResp.StopOrderID,
+		StopPrice:   stopPrice,
+	}
+	logger := levelLogger(level).With(logging.KeyOrderID, orderResp.OrderID, "stop_order_id", orderResp.StopOrderID)
+	if err := s.stopLosses.Save(order); err != nil {
+		logger.Error("Failed to record stop-loss order", logging.Err(err))
+		return
+	}
+	logger.Info("Level sells with an OCO", "price", level.SellPrice, "stop_price", stopPrice)
+}
+
+// stopLossOf returns the stop-loss leg with an order ID and the level selling with it, nils if
+// the order isn't the stop-loss of the sell a level waits on
+func (s *GridService) stopLossOf(orderID string) (*models.StopLossOrder, *models.GridLevel, error) {
+	if s.stopLosses == nil {
+		return nil, nil, nil
+	}
+	stop, err := s.stopLosses.GetByStopOrderID(orderID)
+	if err != nil || stop == nil {
+		return nil, nil, err
+	}
+	level, err := s.repo.GetByID(stop.LevelID)
+	if err != nil || level == nil || level.SellOrderID.String != stop.SellOrderID {
+		return nil, nil, err
+	}
+	return stop, level, nil
+}
+
+// clearStopLoss forgets a level's stop-loss leg once its OCO is over
+func (s *GridService) clearStopLoss(levelID int) {
+	if s.stopLosses == nil {
+		return
+	}
+	if err := s.stopLosses.Delete(levelID); err != nil {
+		slog.Warn("Failed to clear stop-loss order", logging.KeyLevelID, levelID, logging.Err(err))
+	}
+}
+
+// applyStopLegFill handles the limit leg of a level's OCO gone from the exchange: if its
+// stop-loss filled, the level's cycle completes at the stop. Returns false when the level sold
+// with a plain order or neither leg filled, for the caller to handle as a cancelled sell.
+func (s *GridService) applyStopLegFill(level *models.GridLevel, orderID string) bool {
+	if s.stopLosses == nil {
+		return false
+	}
+	logger := levelLogger(level)
+	stop, err := s.stopLosses.GetByLevel(level.ID)
+	if err != nil {
+		logger.Error("Failed to get stop-loss order", logging.Err(err))
+		return true // Not known to be a plain sell - left for the next sync
+	}
+	if stop == nil || stop.SellOrderID != orderID {
+		return false
+	}
+
+	logger = logger.With(logging.KeyOrderID, stop.StopOrderID)
+	status, err := s.assurance.GetOrderStatus(level.Account, level.Symbol, stop.StopOrderID)
+	if err != nil {
+		logger.Warn("Failed to check stop-loss order", logging.Err(err))
+		return true
+	}
+	if status == nil || status.Status == "cancelled" {
+		s.clearStopLoss(level.ID)
+		return false
+	}
+	if status.Status != "filled" || status.FilledAmount == nil || status.FillPrice == nil {
+		return true // Triggered, still filling
+	}
+
+	logger.Info("Stop-loss order filled", "amount", *status.FilledAmount, "price", *status.FillPrice)
+	if err := s.ProcessSellFillNotification(stop.StopOrderID, *status.FilledAmount, *status.FillPrice,
+		status.CommissionAmount(), status.CommissionAsset, status.BaseAsset); err != nil {
+		logger.Error("Failed to apply stop-loss fill", logging.Err(err))
+	}
+	return true
+}
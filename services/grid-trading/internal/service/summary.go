@@ -0,0 +1,54 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+)
+
+// SendSummary reports the day's trading and the buy-and-hold comparison to the notifier
+func (s *GridService) SendSummary() error {
+	if s.events == nil {
+		return fmt.Errorf("no event sink configured")
+	}
+
+	status, err := s.GetStatus()
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]string{
+		"date":            status.Date,
+		"buys_today":      strconv.Itoa(status.BuysToday),
+		"sells_today":     strconv.Itoa(status.SellsToday),
+		"errors_today":    strconv.Itoa(status.ErrorsToday),
+		"profit_today":    status.ProfitToday.String(),
+		"profit_all_time": status.ProfitAllTime.String(),
+		"unrealized_usdt": status.UnrealizedPnL.String(),
+	}
+	message := fmt.Sprintf("%d buys, %d sells, profit today %s USDT, all time %s USDT",
+		status.BuysToday, status.SellsToday, status.ProfitToday, status.ProfitAllTime)
+
+	if b := status.VsBuyAndHold; b != nil {
+		fields["grid_return_pct"] = b.GridReturnPct.String()
+		fields["hold_return_pct"] = b.HoldReturnPct.String()
+		fields["outperformance_pct"] = b.OutperformancePct.String()
+		fields["outperformance_usdt"] = b.OutperformanceUSDT.String()
+		message += fmt.Sprintf(" - grid %s%% vs buy-and-hold %s%%", b.GridReturnPct, b.HoldReturnPct)
+	}
+
+	if c := status.Capital; c != nil {
+		fields["net_deposited_usdt"] = c.NetDepositedUSDT.String()
+		if c.ROIPct.Valid {
+			fields["roi_pct"] = c.ROIPct.Decimal.String()
+			message += fmt.Sprintf(", ROI %s%% on %s USDT deposited", c.ROIPct.Decimal, c.NetDepositedUSDT)
+		}
+		if c.TimeWeightedROIPct.Valid {
+			fields["time_weighted_roi_pct"] = c.TimeWeightedROIPct.Decimal.String()
+		}
+	}
+
+	s.emit(contracts.EventSummary, "", message, fields)
+	return nil
+}
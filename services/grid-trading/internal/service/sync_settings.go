@@ -0,0 +1,69 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// SyncSettings are the sync job's effective settings (GET /sync/config)
+type SyncSettings struct {
+	Enabled             bool   `json:"enabled"`               // SYNC_JOB_ENABLED
+	Cron                string `json:"cron"`                  // SYNC_JOB_CRON
+	StuckAfterSec       int    `json:"stuck_after_sec"`       // PLACING_* levels older than this are recovered
+	RecoveryBatch       int    `json:"recovery_batch"`        // Stuck levels recovered per run, oldest first (0 = all)
+	RecoverStuck        bool   `json:"recover_stuck"`         // Retry or revert stuck PLACING_* levels
+	CheckActive         bool   `json:"check_active"`          // Check the orders of BUY_ACTIVE/SELL_ACTIVE levels
+	MaxRecoveryAttempts int    `json:"max_recovery_attempts"` // RECOVERY_MAX_ATTEMPTS, 0 = retry forever
+}
+
+// DefaultSyncSettings recovers placements stuck for 5 minutes and checks every active order
+func DefaultSyncSettings() SyncSettings {
+	return SyncSettings{Cron: "0 * * * *", StuckAfterSec: 300, RecoverStuck: true, CheckActive: true}
+}
+
+// SetSyncSettings tunes what SyncOrders does; Enabled and Cron are only reported, main schedules the job
+func (s *GridService) SetSyncSettings(settings SyncSettings) {
+	s.sync = settings
+}
+
+// SyncSettings returns the sync job's effective settings
+func (s *GridService) SyncSettings() SyncSettings {
+	settings := s.sync
+	settings.MaxRecoveryAttempts = s.maxRecoveryAttempts
+	return settings
+}
+
+// recoveryBatch keeps the levels stuck longest when a run recovers only so many
+func (s *GridService) recoveryBatch(levels []*models.GridLevel) []*models.GridLevel {
+	if s.sync.RecoveryBatch <= 0 || len(levels) <= s.sync.RecoveryBatch {
+		return levels
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].StateChangedAt.Before(levels[j].StateChangedAt) })
+	return levels[:s.sync.RecoveryBatch]
+}
+
+// syncStuckLevels returns this instance's levels the sync job recovers, none with RecoverStuck off
+func (s *GridService) syncStuckLevels() ([]*models.GridLevel, error) {
+	if !s.sync.RecoverStuck {
+		return nil, nil
+	}
+	levels, err := s.repo.GetStuckInPlacingState(time.Duration(s.sync.StuckAfterSec) * time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return s.recoveryBatch(s.ownedLevels(levels)), nil
+}
+
+// syncActiveLevels returns this instance's levels whose orders the sync job checks, none with CheckActive off
+func (s *GridService) syncActiveLevels() ([]*models.GridLevel, error) {
+	if !s.sync.CheckActive {
+		return nil, nil
+	}
+	levels, err := s.repo.GetAllActive()
+	if err != nil {
+		return nil, err
+	}
+	return s.ownedLevels(levels), nil
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+)
+
+// ErrTeardownIncomplete means a grid could not be archived yet because a level still has an
+// order in flight - its levels stay disabled, and calling the teardown again finishes it
+var ErrTeardownIncomplete = errors.New("grid teardown incomplete")
+
+// TeardownResult reports a grid torn down with DELETE /grids/{symbol}
+type TeardownResult struct {
+	Symbol   string `json:"symbol"`
+	Archived int    `json:"archived"` // Levels moved to grid_levels_archive, 0 while incomplete
+
+	CancelledOrders []string `json:"cancelled_orders,omitempty"`
+	FailedOrders    []string `json:"failed_orders,omitempty"`
+
+	// market_sell: HOLDING levels closed with a MARKET sell, and the ones that failed
+	SoldLevels   []int `json:"sold_levels,omitempty"`
+	UnsoldLevels []int `json:"unsold_levels,omitempty"`
+
+	// Levels archived still holding coins, which stay on the exchange
+	HeldLevels []int `json:"held_levels,omitempty"`
+
+	// Levels whose order is still placing or open, keeping the grid from being archived
+	PendingLevels []int `json:"pending_levels,omitempty"`
+}
+
+// TeardownGrid removes a symbol's grid: it disables the levels, cancels their open orders,
+// sells HOLDING levels at market if marketSell is set and moves the levels to the archive.
+// Fills and cancels are recorded as transactions as usual; the transactions are kept.
+// Returns ErrTeardownIncomplete (with the result so far) while a level still has an order in
+// flight, e.g. a cancel notification that hasn't arrived yet.
+func (s *GridService) TeardownGrid(symbol string, marketSell bool) (*TeardownResult, error) {
+	levels, err := s.repo.GetBySymbol(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoLevels, symbol)
+	}
+
+	// No new orders while the grid is torn down, and none if it stops halfway
+	if _, err := s.repo.SetSymbolEnabled(symbol, false); err != nil {
+		return nil, fmt.Errorf("failed to disable %s levels: %w", symbol, err)
+	}
+	result := &TeardownResult{Symbol: symbol}
+
+	for _, level := range levels {
+		orderID := openOrderID(level)
+		if orderID == "" {
+			continue
+		}
+		if err := s.assurance.CancelOrder(level.Account, level.Symbol, orderID); err != nil {
+			log.Printf("ERROR: Failed to cancel order %s of level %d: %v", orderID, level.ID, err)
+			result.FailedOrders = append(result.FailedOrders, orderID)
+			continue
+		}
+		result.CancelledOrders = append(result.CancelledOrders, orderID)
+	}
+
+	// Cancel notifications have moved the levels back to READY or HOLDING by now (HTTP transport)
+	if levels, err = s.repo.GetBySymbol(symbol); err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+
+	if marketSell {
+		for _, level := range levels {
+			if level.State != models.StateHolding || !level.FilledAmount.Valid || !level.FilledAmount.Decimal.IsPositive() {
+				continue
+			}
+			if err := s.closeHolding(level); err != nil {
+				log.Printf("ERROR: Failed to sell holdings of level %d at market: %v", level.ID, err)
+				result.UnsoldLevels = append(result.UnsoldLevels, level.ID)
+				continue
+			}
+			result.SoldLevels = append(result.SoldLevels, level.ID)
+		}
+		if levels, err = s.repo.GetBySymbol(symbol); err != nil {
+			return nil, fmt.Errorf("failed to get levels: %w", err)
+		}
+	}
+
+	for _, level := range levels {
+		if !editableState(level.State) {
+			result.PendingLevels = append(result.PendingLevels, level.ID)
+		} else if level.FilledAmount.Valid && level.FilledAmount.Decimal.IsPositive() {
+			result.HeldLevels = append(result.HeldLevels, level.ID)
+		}
+	}
+	if len(result.PendingLevels) > 0 {
+		result.HeldLevels = nil
+		return result, fmt.Errorf("%w: levels %v of %s still have an order placing or open - try again once they settle",
+			ErrTeardownIncomplete, result.PendingLevels, symbol)
+	}
+
+	archived, err := s.repo.ArchiveSymbol(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive %s levels: %w", symbol, err)
+	}
+	if archived == 0 {
+		result.HeldLevels = nil
+		return result, fmt.Errorf("%w: a level of %s started an order - try again once it settles", ErrTeardownIncomplete, symbol)
+	}
+	result.Archived = archived
+	if s.grids != nil {
+		if err := s.grids.DeleteBySymbol(symbol); err != nil {
+			log.Printf("WARNING: Failed to forget the grid fingerprints of %s: %v", symbol, err)
+		}
+	}
+	if s.shadow != nil {
+		if _, err := s.shadow.DeleteBySymbol(symbol, 0); err != nil {
+			log.Printf("WARNING: Failed to forget the shadow transactions of %s: %v", symbol, err)
+		}
+	}
+
+	log.Printf("INFO: Tore down %s: %d levels archived, %d orders cancelled, %d levels sold at market, %d still holding",
+		symbol, archived, len(result.CancelledOrders), len(result.SoldLevels), len(result.HeldLevels))
+	return result, nil
+}
+
+// closeHolding sells a HOLDING level's coins with a MARKET order and applies the fill right
+// away, recording the closing sell and its profit (or loss) like any sell fill
+func (s *GridService) closeHolding(level *models.GridLevel) error {
+	started, err := s.repo.TryStartSellOrder(level.ID)
+	if err != nil {
+		return fmt.Errorf("failed to start sell order: %w", err)
+	}
+	if !started {
+		return fmt.Errorf("level %d is no longer HOLDING", level.ID)
+	}
+
+	// Kept as the target price; the fill reports what the market paid
+	price, ok := s.LatestPrice(level.Symbol)
+	if !ok {
+		price = level.BuyPrice
+	}
+
+	orderReq := client.OrderRequest{
+		Symbol:  level.Symbol,
+		Price:   price,
+		Side:    client.OrderSideSell,
+		Amount:  level.FilledAmount.Decimal,
+		Account: level.Account,
+		Market:  true,
+	}
+	log.Printf("INFO: Selling level %d at market - Symbol: %s, Amount: %s", level.ID, level.Symbol, orderReq.Amount)
+
+	orderResp, err := s.assurance.PlaceOrder(context.Background(), orderReq)
+	if err != nil {
+		s.repo.UpdateState(level.ID, models.StateHolding)
+		s.txRepo.RecordSellError(level.ID, level.Symbol, price, placementErrorCode(err), err.Error())
+		return fmt.Errorf("failed to place market sell: %w", err)
+	}
+
+	if err := s.repo.UpdateSellOrderPlaced(level.ID, orderResp.OrderID); err != nil {
+		return fmt.Errorf("failed to update sell order placed: %w", err)
+	}
+	if err := s.txRepo.RecordSellPlaced(level.ID, level.Symbol, orderResp.OrderID, price, orderReq.Amount); err != nil {
+		log.Printf("WARNING: Failed to record sell placed transaction: %v", err)
+	}
+
+	// A MARKET order is filled by the time it is placed; a later fill notification finds the level READY
+	s.checkAndUpdateOrderStatus(level, orderResp.OrderID, false)
+	return nil
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Minutes the price must stay outside a trailing grid's band before it shifts, unless configured
+const defaultTrailAfterMin = 30
+
+// ErrGridConfigRejected wraps grid config changes with invalid values
+var ErrGridConfigRejected = errors.New("grid config rejected")
+
+// GridConfigRepositoryInterface stores per-symbol grid settings
+type GridConfigRepositoryInterface interface {
+	Get(symbol string) (*models.GridConfig, error)
+	GetTrailing() ([]*models.GridConfig, error)
+	GetTagged(tag string) ([]*models.GridConfig, error)
+	Save(config *models.GridConfig) error
+}
+
+// GridConfigRequest changes a symbol's grid settings; fields left out keep their value
+type GridConfigRequest struct {
+	TrailingEnabled *bool                `json:"trailing_enabled,omitempty"`
+	TrailAfterMin   *int                 `json:"trail_after_min,omitempty"`
+	WatchOnly       *bool                `json:"watch_only,omitempty"`
+	OrderType       *contracts.OrderType `json:"order_type,omitempty"`
+	StopLossPct     *decimal.Decimal     `json:"stop_loss_pct,omitempty"` // 0 turns OCO sells off
+	Tags            *[]string            `json:"tags,omitempty"`          // Replaces the grid's tags, [] clears them
+	Notes           *string              `json:"notes,omitempty"`
+}
+
+// TrailShift is one level moved from an edge of a trailing grid to the other
+type TrailShift struct {
+	Symbol    string          `json:"symbol"`
+	Account   string          `json:"account,omitempty"`
+	Direction string          `json:"direction"` // up | down
+	Price     decimal.Decimal `json:"price"`
+	Retired   int             `json:"retired_level_id"`
+	Added     int             `json:"level_id"`
+}
+
+// trailWatch is when the price of a grid (symbol and account) left its band, and to which side
+type trailWatch struct {
+	since time.Time
+	up    bool
+}
+
+// UseGridConfigs reads per-symbol grid settings, such as trailing
+func (s *GridService) UseGridConfigs(configs GridConfigRepositoryInterface) {
+	s.configs = configs
+	s.trailWatches = make(map[string]trailWatch)
+}
+
+// GetGridConfig returns a symbol's grid settings, the defaults if none were saved
+func (s *GridService) GetGridConfig(symbol string) (*models.GridConfig, error) {
+	config, err := s.configs.Get(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s grid config: %w", symbol, err)
+	}
+	if config == nil {
+		config = &models.GridConfig{Symbol: symbol, TrailAfterMin: defaultTrailAfterMin, OrderType: contracts.OrderTypeLimit, Tags: []string{}}
+	}
+	return config, nil
+}
+
+// UpdateGridConfig applies a GridConfigRequest to a symbol's grid settings
+func (s *GridService) UpdateGridConfig(symbol string, req GridConfigRequest) (*models.GridConfig, error) {
+	config, err := s.GetGridConfig(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if req.TrailingEnabled != nil {
+		config.TrailingEnabled = *req.TrailingEnabled
+	}
+	if req.TrailAfterMin != nil {
+		config.TrailAfterMin = *req.TrailAfterMin
+	}
+	if req.WatchOnly != nil {
+		config.WatchOnly = *req.WatchOnly
+	}
+	if req.OrderType != nil {
+		config.OrderType = *req.OrderType
+	}
+	if req.StopLossPct != nil {
+		config.StopLossPct = decimal.NullDecimal{Decimal: *req.StopLossPct, Valid: !req.StopLossPct.IsZero()}
+	}
+	if req.Tags != nil {
+		if config.Tags, err = normalizeTags(*req.Tags); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrGridConfigRejected, err)
+		}
+	}
+	if req.Notes != nil {
+		if config.Notes, err = normalizeNotes(*req.Notes); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrGridConfigRejected, err)
+		}
+	}
+	if config.TrailAfterMin < 1 {
+		return nil, fmt.Errorf("%w: trail_after_min must be at least 1", ErrGridConfigRejected)
+	}
+	if config.OrderType != contracts.OrderTypeLimit && config.OrderType != contracts.OrderTypeMarket {
+		return nil, fmt.Errorf("%w: order_type must be limit or market", ErrGridConfigRejected)
+	}
+	if config.StopLossPct.Valid {
+		if !config.StopLossPct.Decimal.IsPositive() || config.StopLossPct.Decimal.GreaterThanOrEqual(decimal.NewFromInt(100)) {
+			return nil, fmt.Errorf("%w: stop_loss_pct must be between 0 and 100", ErrGridConfigRejected)
+		}
+		if config.OrderType == contracts.OrderTypeMarket {
+			return nil, fmt.Errorf("%w: stop_loss_pct needs order_type limit - OCO sells rest at the sell price", ErrGridConfigRejected)
+		}
+	}
+
+	if err := s.configs.Save(config); err != nil {
+		return nil, fmt.Errorf("failed to save %s grid config: %w", symbol, err)
+	}
+	slog.Info("Grid config saved", logging.KeySymbol, symbol, "trailing_enabled", config.TrailingEnabled, "trail_after_min", config.TrailAfterMin,
+		"watch_only", config.WatchOnly, "order_type", config.OrderType, "stop_loss_pct", config.StopLossPct.Decimal, "tags", config.Tags)
+	return s.GetGridConfig(symbol)
+}
+
+// TrailGrids shifts the grids with trailing enabled whose price has stayed above or below
+// their band - lowest buy price to highest sell price - for trail_after_min. Each run moves
+// at most one level per grid: the empty level (READY, no coins) at the far edge is archived
+// and a level spaced like its neighbours is added past the near edge. A grid whose far edge
+// level holds coins or has an order doesn't move. Grids of each account trail separately.
+func (s *GridService) TrailGrids() ([]TrailShift, error) {
+	configs, err := s.configs.GetTrailing()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trailing grids: %w", err)
+	}
+
+	var shifts []TrailShift
+	now := time.Now()
+	for _, config := range configs {
+		price, ok := s.LatestPrice(config.Symbol)
+		if !ok {
+			slog.Warn("No fresh price, not trailing the grid", logging.KeySymbol, config.Symbol)
+			continue
+		}
+
+		levels, err := s.repo.GetBySymbol(config.Symbol)
+		if err != nil {
+			slog.Error("Failed to get levels for trailing", logging.KeySymbol, config.Symbol, logging.Err(err))
+			continue
+		}
+
+		byAccount := make(map[string][]*models.GridLevel)
+		for _, level := range levels {
+			byAccount[level.Account] = append(byAccount[level.Account], level)
+		}
+		for account, grid := range byAccount {
+			shift, err := s.trailGrid(config, account, grid, price, now)
+			if err != nil {
+				slog.Error("Failed to trail grid", logging.KeySymbol, config.Symbol, logging.KeyAccount, account, logging.Err(err))
+				continue
+			}
+			if shift != nil {
+				shifts = append(shifts, *shift)
+			}
+		}
+	}
+	return shifts, nil
+}
+
+// trailGrid shifts one grid by a level once its price has been outside the band long enough
+func (s *GridService) trailGrid(config *models.GridConfig, account string, levels []*models.GridLevel, price decimal.Decimal, now time.Time) (*TrailShift, error) {
+	sort.Slice(levels, func(i, j int) bool { return levels[i].BuyPrice.LessThan(levels[j].BuyPrice) })
+	bottom, top := levels[0], levels[len(levels)-1]
+	key := account + "/" + config.Symbol
+
+	// A paused grid stays where it is
+	enabled := false
+	for _, level := range levels {
+		enabled = enabled || level.Enabled
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	s.trailMu.Lock()
+	if price.GreaterThanOrEqual(bottom.BuyPrice) && price.LessThanOrEqual(top.SellPrice) {
+		delete(s.trailWatches, key)
+		s.trailMu.Unlock()
+		return nil, nil
+	}
+	up := price.GreaterThan(top.SellPrice)
+	watch, watching := s.trailWatches[key]
+	if !watching || watch.up != up {
+		s.trailWatches[key] = trailWatch{since: now, up: up}
+		s.trailMu.Unlock()
+		return nil, nil
+	}
+	s.trailMu.Unlock()
+	if now.Sub(watch.since) < time.Duration(config.TrailAfterMin)*time.Minute {
+		return nil, nil
+	}
+
+	// Up: the bottom level goes, a level is added above the top one; down the other way round
+	retire := bottom
+	buyPrice, sellPrice := top.SellPrice, nextLevelPrice(levels, top.SellPrice, true)
+	direction := "up"
+	if !up {
+		retire = top
+		buyPrice, sellPrice = nextLevelPrice(levels, bottom.BuyPrice, false), bottom.BuyPrice
+		direction = "down"
+	}
+	if !buyPrice.IsPositive() || !sellPrice.GreaterThan(buyPrice) {
+		return nil, fmt.Errorf("no room for a level below %s", bottom.BuyPrice)
+	}
+	if retire.State != models.StateReady || (retire.FilledAmount.Valid && retire.FilledAmount.Decimal.IsPositive()) {
+		levelLogger(retire).Info("Grid can't trail, the level at the far edge is in use", "direction", direction, "state", retire.State)
+		return nil, nil
+	}
+
+	archived, err := s.repo.ArchiveLevel(retire.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retire level %d: %w", retire.ID, err)
+	}
+	if !archived {
+		levelLogger(retire).Info("Grid can't trail, the level at the far edge started an order", "direction", direction)
+		return nil, nil
+	}
+
+	edge := top
+	if !up {
+		edge = bottom
+	}
+	level := &models.GridLevel{
+		Symbol:        config.Symbol,
+		Account:       account,
+		BuyPrice:      buyPrice,
+		SellPrice:     sellPrice,
+		BuyAmount:     edge.BuyAmount,
+		BuyMultiplier: edge.BuyMultiplier,
+		MaxBuyAmount:  edge.MaxBuyAmount,
+		State:         models.StateReady,
+		Enabled:       edge.Enabled,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.repo.Create(level); err != nil {
+		return nil, fmt.Errorf("retired level %d but failed to add one at %s → %s: %w", retire.ID, buyPrice, sellPrice, err)
+	}
+
+	shift := &TrailShift{Symbol: config.Symbol, Account: account, Direction: direction, Price: price, Retired: retire.ID, Added: level.ID}
+	slog.Info("Grid trailed", logging.KeySymbol, config.Symbol, logging.KeyAccount, account, "direction", direction, "price", price,
+		"retired_level_id", retire.ID, "added_level_id", level.ID, "buy_price", buyPrice, "sell_price", sellPrice)
+	s.emit(contracts.EventGridTrailed, config.Symbol,
+		fmt.Sprintf("%s grid trailed %s: level %d retired, level %d added at %s → %s", config.Symbol, direction, retire.ID, level.ID, buyPrice, sellPrice),
+		map[string]string{
+			"direction":          direction,
+			"price":              price.String(),
+			"retired_level_id":   fmt.Sprint(retire.ID),
+			"retired_buy_price":  retire.BuyPrice.String(),
+			"retired_sell_price": retire.SellPrice.String(),
+			"level_id":           fmt.Sprint(level.ID),
+			"buy_price":          buyPrice.String(),
+			"sell_price":         sellPrice.String(),
+		})
+	return shift, nil
+}
+
+// nextLevelPrice continues a grid's spacing one level past from, upwards or downwards.
+// Levels spanning the same amount at both edges are arithmetic; otherwise the grid is
+// geometric and the edge level's ratio is kept.
+func nextLevelPrice(levels []*models.GridLevel, from decimal.Decimal, up bool) decimal.Decimal {
+	bottom, top := levels[0], levels[len(levels)-1]
+	edge := top
+	if !up {
+		edge = bottom
+	}
+
+	step := edge.SellPrice.Sub(edge.BuyPrice)
+	if step.Equal(top.SellPrice.Sub(top.BuyPrice)) && step.Equal(bottom.SellPrice.Sub(bottom.BuyPrice)) {
+		if up {
+			return from.Add(step)
+		}
+		return from.Sub(step)
+	}
+
+	ratio := edge.SellPrice.DivRound(edge.BuyPrice, 2*geometricPriceDigits)
+	if up {
+		return roundSignificant(from.Mul(ratio), geometricPriceDigits)
+	}
+	return roundSignificant(from.DivRound(ratio, 2*geometricPriceDigits), geometricPriceDigits)
+}
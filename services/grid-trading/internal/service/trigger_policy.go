@@ -0,0 +1,50 @@
+package service
+
+import (
+	"log"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// TriggerPolicy decides which triggers may place real orders, by what their price is based on
+// (contracts.PriceTrigger Feed and Confidence). A held back level keeps its state and is placed
+// by a later trigger that passes.
+type TriggerPolicy struct {
+	MinConfidence   float64         // Triggers below this confidence place no orders (0 = off)
+	AggregatedAbove decimal.Decimal // Orders above this notional (USDT) need an aggregated trigger (0 = off)
+}
+
+// SetTriggerPolicy holds back placements of triggers the policy doesn't trust
+func (s *GridService) SetTriggerPolicy(policy TriggerPolicy) {
+	s.triggerPolicy = policy
+}
+
+// triggerAllows reports whether a trigger may place a level's order. Triggers from older
+// price-monitors carry no feed or confidence and only pass an empty policy.
+func (s *GridService) triggerAllows(trigger contracts.PriceTrigger, level *models.GridLevel, side client.OrderSide) bool {
+	policy := s.triggerPolicy
+	if policy.MinConfidence > 0 && trigger.Confidence < policy.MinConfidence {
+		s.sourceSkips.Add(1)
+		log.Printf("WARNING: Holding back %s of level %d: %s trigger from %q has confidence %.2f, below TRIGGER_MIN_CONFIDENCE %.2f",
+			side, level.ID, trigger.Feed, trigger.Exchange, trigger.Confidence, policy.MinConfidence)
+		return false
+	}
+	if !policy.AggregatedAbove.IsPositive() || trigger.Feed == contracts.FeedAggregated {
+		return true
+	}
+
+	notional := s.buyAmountFor(level)
+	if side == client.OrderSideSell {
+		notional = level.FilledAmount.Decimal.Mul(s.sellOrderPrice(level))
+	}
+	if notional.LessThanOrEqual(policy.AggregatedAbove) {
+		return true
+	}
+	s.sourceSkips.Add(1)
+	log.Printf("WARNING: Holding back %s of level %d: %s USDT is above TRIGGER_AGGREGATED_ABOVE %s and needs an aggregated price, trigger was %s from %q",
+		side, level.ID, notional.Round(2), policy.AggregatedAbove, trigger.Feed, trigger.Exchange)
+	return false
+}
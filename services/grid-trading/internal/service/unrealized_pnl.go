@@ -0,0 +1,201 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// PriceSourceInterface provides the latest market price of any symbol (the shared price cache)
+type PriceSourceInterface interface {
+	GetLatestPrice(symbol string) (*client.PriceQuote, error)
+}
+
+// Where a price used for unrealized PnL came from
+const (
+	PriceSourceCache   = "cache"
+	PriceSourceTrigger = "trigger"
+)
+
+// SymbolPnL values the coins held by a symbol's levels at the latest price
+type SymbolPnL struct {
+	Symbol         string          `json:"symbol"`
+	HoldingLevels  int             `json:"holding_levels"`
+	AmountCoin     decimal.Decimal `json:"amount_coin"`
+	CostUSDT       decimal.Decimal `json:"cost_usdt"` // Buy amounts of the holding levels
+	Price          decimal.Decimal `json:"price,omitempty"`
+	PriceSource    string          `json:"price_source,omitempty"` // cache | trigger, empty if no price is known
+	PriceUpdatedAt string          `json:"price_updated_at,omitempty"`
+	Stale          bool            `json:"stale"`
+	ValueUSDT      decimal.Decimal `json:"value_usdt"`
+	UnrealizedUSDT decimal.Decimal `json:"unrealized_usdt"`
+	UnrealizedPct  decimal.Decimal `json:"unrealized_pct"`
+}
+
+// UnrealizedPnL totals only symbols with a fresh price - stale or unknown ones
+// are listed but left out, so a dead price feed can't fake a loss or a profit
+type UnrealizedPnL struct {
+	CostUSDT       decimal.Decimal `json:"cost_usdt"`
+	ValueUSDT      decimal.Decimal `json:"value_usdt"`
+	UnrealizedUSDT decimal.Decimal `json:"unrealized_usdt"`
+	DrawdownPct    decimal.Decimal `json:"drawdown_pct"` // Unrealized loss as % of cost (0 when in profit)
+	StaleSymbols   []string        `json:"stale_symbols,omitempty"`
+	Symbols        []SymbolPnL     `json:"symbols"`
+}
+
+// UsePriceSource reads prices from the shared cache instead of relying on the last trigger per symbol
+func (s *GridService) UsePriceSource(prices PriceSourceInterface) {
+	s.prices = prices
+}
+
+// SetRiskLimits sets when a price counts as stale and the unrealized drawdown
+// (in % of the cost of held coins) above which new buys are paused (0 = no limit)
+func (s *GridService) SetRiskLimits(priceStaleAfter time.Duration, maxDrawdownPct decimal.Decimal) {
+	s.priceStaleAfter = priceStaleAfter
+	s.maxDrawdownPct = maxDrawdownPct
+}
+
+// latestPrice returns the most recent of the cached price and the last trigger for symbol
+func (s *GridService) latestPrice(symbol string) (quote *client.PriceQuote, source string) {
+	if s.prices != nil {
+		cached, err := s.prices.GetLatestPrice(symbol)
+		if err != nil {
+			log.Printf("WARNING: %v", err)
+		} else if cached != nil {
+			quote, source = cached, PriceSourceCache
+		}
+	}
+
+	s.lastPriceMu.RLock()
+	triggered, ok := s.triggerPrices[symbol]
+	s.lastPriceMu.RUnlock()
+	if ok && (quote == nil || triggered.UpdatedAt.After(quote.UpdatedAt)) {
+		quote, source = &triggered, PriceSourceTrigger
+	}
+
+	return quote, source
+}
+
+// LatestPrice returns the latest price of a symbol, false if none is known or it's stale
+func (s *GridService) LatestPrice(symbol string) (decimal.Decimal, bool) {
+	quote, _ := s.latestPrice(symbol)
+	if s.isStale(quote) {
+		return decimal.Zero, false
+	}
+	return quote.Price, true
+}
+
+func (s *GridService) isStale(quote *client.PriceQuote) bool {
+	return quote == nil || time.Since(quote.UpdatedAt) > s.priceStaleAfter
+}
+
+// GetUnrealizedPnL values the coins currently held (bought, not yet sold) at the latest prices
+func (s *GridService) GetUnrealizedPnL() (*UnrealizedPnL, error) {
+	levels, err := s.repo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+
+	bySymbol := make(map[string]*SymbolPnL)
+	for _, level := range levels {
+		if !holdsCoin(level) {
+			continue
+		}
+
+		pnl, ok := bySymbol[level.Symbol]
+		if !ok {
+			pnl = &SymbolPnL{Symbol: level.Symbol}
+			bySymbol[level.Symbol] = pnl
+		}
+		pnl.HoldingLevels++
+		pnl.AmountCoin = pnl.AmountCoin.Add(level.FilledAmount.Decimal)
+		pnl.CostUSDT = pnl.CostUSDT.Add(level.CycleAmount())
+	}
+
+	result := &UnrealizedPnL{Symbols: []SymbolPnL{}}
+	for _, pnl := range bySymbol {
+		quote, source := s.latestPrice(pnl.Symbol)
+		pnl.Stale = s.isStale(quote)
+		if quote != nil {
+			pnl.Price = quote.Price
+			pnl.PriceSource = source
+			pnl.PriceUpdatedAt = quote.UpdatedAt.Format(time.RFC3339)
+			pnl.ValueUSDT = pnl.AmountCoin.Mul(quote.Price).Round(8)
+			pnl.UnrealizedUSDT = pnl.ValueUSDT.Sub(pnl.CostUSDT)
+			if pnl.CostUSDT.IsPositive() {
+				pnl.UnrealizedPct = pnl.UnrealizedUSDT.Div(pnl.CostUSDT).Mul(decimal.NewFromInt(100)).Round(2)
+			}
+		}
+
+		if pnl.Stale {
+			result.StaleSymbols = append(result.StaleSymbols, pnl.Symbol)
+		} else {
+			result.CostUSDT = result.CostUSDT.Add(pnl.CostUSDT)
+			result.ValueUSDT = result.ValueUSDT.Add(pnl.ValueUSDT)
+		}
+		result.Symbols = append(result.Symbols, *pnl)
+	}
+
+	sort.Slice(result.Symbols, func(i, j int) bool { return result.Symbols[i].Symbol < result.Symbols[j].Symbol })
+	sort.Strings(result.StaleSymbols)
+
+	result.UnrealizedUSDT = result.ValueUSDT.Sub(result.CostUSDT)
+	if result.UnrealizedUSDT.IsNegative() && result.CostUSDT.IsPositive() {
+		result.DrawdownPct = result.UnrealizedUSDT.Neg().Div(result.CostUSDT).Mul(decimal.NewFromInt(100)).Round(2)
+	}
+
+	return result, nil
+}
+
+// drawdownExceeded reports whether unrealized losses across all symbols passed MAX_DRAWDOWN_PCT.
+// Buys continue if the check itself fails - sells and the existing grid are unaffected either way.
+func (s *GridService) drawdownExceeded() bool {
+	if !s.maxDrawdownPct.IsPositive() {
+		return false
+	}
+
+	pnl, err := s.GetUnrealizedPnL()
+	if err != nil {
+		log.Printf("ERROR: Drawdown check failed: %v", err)
+		return false
+	}
+	if len(pnl.StaleSymbols) > 0 {
+		log.Printf("WARNING: No fresh price for %v, drawdown check covers the remaining symbols only", pnl.StaleSymbols)
+	}
+
+	if pnl.DrawdownPct.GreaterThan(s.maxDrawdownPct) {
+		log.Printf("WARNING: Unrealized drawdown %s%% (%s USDT) exceeds limit %s%%",
+			pnl.DrawdownPct, pnl.UnrealizedUSDT, s.maxDrawdownPct)
+
+		if s.drawdownTripped.CompareAndSwap(false, true) {
+			s.emit(contracts.EventDrawdownExceeded, "",
+				fmt.Sprintf("Unrealized drawdown %s%% (%s USDT) exceeds %s%%, new buys paused", pnl.DrawdownPct, pnl.UnrealizedUSDT, s.maxDrawdownPct),
+				map[string]string{
+					"drawdown_pct":    pnl.DrawdownPct.String(),
+					"unrealized_usdt": pnl.UnrealizedUSDT.String(),
+					"limit_pct":       s.maxDrawdownPct.String(),
+				})
+		}
+		return true
+	}
+
+	if s.drawdownTripped.CompareAndSwap(true, false) {
+		log.Printf("INFO: Unrealized drawdown %s%% back within limit, resuming buys", pnl.DrawdownPct)
+	}
+	return false
+}
+
+// holdsCoin reports whether a level bought coins it hasn't sold yet
+func holdsCoin(level *models.GridLevel) bool {
+	switch level.State {
+	case models.StateHolding, models.StatePlacingSell, models.StateSellActive:
+		return level.FilledAmount.Valid
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// shadowListLimit is how many of a symbol's newest shadow transactions GetShadowSummary lists
+const shadowListLimit = 100
+
+// ShadowTransactionRepositoryInterface stores the orders watch-only grids would have placed
+type ShadowTransactionRepositoryInterface interface {
+	Record(tx *models.ShadowTransaction) error
+	GetLatestByLevel(symbol string, strategyID int) (map[int]*models.ShadowTransaction, error)
+	GetBySymbol(symbol string, strategyID int) ([]*models.ShadowTransaction, error)
+	DeleteBySymbol(symbol string, strategyID int) (int, error)
+}
+
+// ShadowSummary is how a watch-only grid would have traded
+type ShadowSummary struct {
+	Symbol       string                      `json:"symbol"`
+	WatchOnly    bool                        `json:"watch_only"`
+	BuysFilled   int                         `json:"buys_filled"`
+	SellsFilled  int                         `json:"sells_filled"`
+	ProfitUSDT   decimal.Decimal             `json:"profit_usdt"` // Of the filled sells, after TRADING_FEE
+	OpenOrders   int                         `json:"open_orders"` // Shadow orders waiting for the price
+	Transactions []*models.ShadowTransaction `json:"transactions"`
+}
+
+// EnableWatchOnly makes every grid watch-only: triggers record shadow orders, nothing is placed
+func (s *GridService) EnableWatchOnly() {
+	s.watchOnlyAll = true
+}
+
+// UseShadowTransactions records the orders of watch-only grids
+func (s *GridService) UseShadowTransactions(shadow ShadowTransactionRepositoryInterface) {
+	s.shadow = shadow
+}
+
+// watchOnly reports whether triggers of a symbol only record shadow orders. When its grid
+// config can't be read, nothing is placed for the trigger.
+func (s *GridService) watchOnly(symbol string) bool {
+	if s.watchOnlyAll {
+		return true
+	}
+	if s.configs == nil {
+		return false
+	}
+	config, err := s.configs.Get(symbol)
+	if err != nil {
+		log.Printf("WARNING: Failed to read %s grid config, not placing orders: %v", symbol, err)
+		return true
+	}
+	return config != nil && config.WatchOnly
+}
+
+// shadowLevel is a level as the shadow state machine sees it: a real level of a watch-only
+// grid or a level of a shadow strategy
+type shadowLevel struct {
+	ID             int
+	Symbol         string
+	Account        string
+	BuyPrice       decimal.Decimal
+	SellOrderPrice decimal.Decimal
+	Enabled        bool
+	CanBuy         bool                   // The trigger price starts a buy
+	MarketBuy      bool                   // Buys fill at once at the trigger price
+	BuyAmount      func() decimal.Decimal // USDT of a buy, asked for only when one starts
+}
+
+// shadowTrigger runs a trigger through the state machine of each level of a watch-only grid
+// without touching the level
+func (s *GridService) shadowTrigger(symbol string, levels []*models.GridLevel, price decimal.Decimal, buysPaused bool, pauseReason string) {
+	if s.shadow == nil {
+		log.Printf("WARNING: %s is watch-only but shadow transactions are not recorded", symbol)
+		return
+	}
+	latest, err := s.shadow.GetLatestByLevel(symbol, 0)
+	if err != nil {
+		log.Printf("ERROR: Failed to get %s shadow transactions: %v", symbol, err)
+		return
+	}
+
+	market := s.marketOrders(symbol)
+	for _, level := range levels {
+		level := level
+		shadow := shadowLevel{
+			ID:             level.ID,
+			Symbol:         level.Symbol,
+			Account:        level.Account,
+			BuyPrice:       level.BuyPrice,
+			SellOrderPrice: s.sellOrderPrice(level),
+			Enabled:        level.Enabled,
+			CanBuy:         s.canBuy(level, price, market),
+			MarketBuy:      s.quoteBuys || market,
+			BuyAmount:      func() decimal.Decimal { return s.buyAmountFor(level) },
+		}
+		if err := s.stepShadow(0, shadow, latest[level.ID], price, buysPaused, pauseReason); err != nil {
+			log.Printf("ERROR: Failed to record shadow order of level %d: %v", level.ID, err)
+		}
+	}
+}
+
+// stepShadow moves one shadow level on a trigger. Shadow orders are limit orders at the level's
+// prices (or market buys) that fill once the trigger price reaches them; the level's shadow state
+// is its newest shadow transaction. Shadow orders don't expire and ignore the spread guard.
+func (s *GridService) stepShadow(strategyID int, level shadowLevel, last *models.ShadowTransaction, price decimal.Decimal, buysPaused bool, pauseReason string) error {
+	switch {
+	case last == nil || (last.Side == models.SideSell && last.Status == models.StatusFilled):
+		if !level.CanBuy || !level.Enabled {
+			return nil
+		}
+		if buysPaused {
+			log.Printf("WARNING: Price %s triggered shadow BUY level %d but buys are paused by %s", price, level.ID, pauseReason)
+			return nil
+		}
+		return s.shadowBuy(strategyID, level, price)
+	case last.Side == models.SideBuy && last.Status == models.StatusPlaced:
+		if price.GreaterThan(last.TargetPrice) {
+			return nil
+		}
+		fill := *last
+		fill.Status = models.StatusFilled
+		fill.TriggerPrice = price
+		if err := s.recordShadow(&fill); err != nil {
+			return err
+		}
+		return s.shadowSell(level, &fill, price)
+	case last.Side == models.SideBuy:
+		// Bought while the level was disabled; sells once it is enabled again
+		return s.shadowSell(level, last, price)
+	case last.Status == models.StatusPlaced:
+		if price.LessThan(last.TargetPrice) {
+			return nil
+		}
+		fill := *last
+		fill.Status = models.StatusFilled
+		fill.TriggerPrice = price
+		return s.recordShadow(&fill)
+	}
+	return nil
+}
+
+// shadowBuy records the buy a level would place
+func (s *GridService) shadowBuy(strategyID int, level shadowLevel, price decimal.Decimal) error {
+	amount := level.BuyAmount()
+	buy := &models.ShadowTransaction{
+		StrategyID:   strategyID,
+		GridLevelID:  level.ID,
+		Symbol:       level.Symbol,
+		Account:      level.Account,
+		Side:         models.SideBuy,
+		Status:       models.StatusPlaced,
+		TargetPrice:  level.BuyPrice,
+		TriggerPrice: price,
+		AmountUSDT:   amount,
+		AmountCoin:   amount.DivRound(level.BuyPrice, 8),
+	}
+	if !level.MarketBuy {
+		return s.recordShadow(buy)
+	}
+
+	// Market buys fill at once
+	buy.Status = models.StatusFilled
+	buy.TargetPrice = price
+	buy.AmountCoin = amount.DivRound(price, 8)
+	if err := s.recordShadow(buy); err != nil {
+		return err
+	}
+	return s.shadowSell(level, buy, price)
+}
+
+// shadowSell records the sell a level would place for a filled shadow buy, with the profit it
+// makes once filled
+func (s *GridService) shadowSell(level shadowLevel, buy *models.ShadowTransaction, price decimal.Decimal) error {
+	if !level.Enabled {
+		return nil
+	}
+
+	proceeds := buy.AmountCoin.Mul(level.SellOrderPrice)
+	fee := decimal.NewFromFloat(s.tradingFee).Div(decimal.NewFromInt(100))
+	profit := proceeds.Sub(buy.AmountUSDT).Sub(proceeds.Add(buy.AmountUSDT).Mul(fee))
+
+	return s.recordShadow(&models.ShadowTransaction{
+		StrategyID:   buy.StrategyID,
+		GridLevelID:  level.ID,
+		Symbol:       level.Symbol,
+		Account:      level.Account,
+		Side:         models.SideSell,
+		Status:       models.StatusPlaced,
+		TargetPrice:  level.SellOrderPrice,
+		TriggerPrice: price,
+		AmountCoin:   buy.AmountCoin,
+		AmountUSDT:   proceeds.Round(8),
+		ProfitUSDT:   decimal.NewNullDecimal(profit.Round(8)),
+	})
+}
+
+func (s *GridService) recordShadow(tx *models.ShadowTransaction) error {
+	tx.ID = 0
+	if err := s.shadow.Record(tx); err != nil {
+		return err
+	}
+
+	verb := "would place"
+	if tx.Status == models.StatusFilled {
+		verb = "would fill"
+	}
+	who := "WATCH-ONLY"
+	if tx.StrategyID != 0 {
+		who = fmt.Sprintf("SHADOW STRATEGY %d", tx.StrategyID)
+	}
+	log.Printf("%s: %s %s %s for level %d at %s (price %s), %s coin / %s USDT",
+		who, tx.Symbol, verb, tx.Side, tx.GridLevelID, tx.TargetPrice, tx.TriggerPrice, tx.AmountCoin, tx.AmountUSDT)
+	return nil
+}
+
+// GetShadowSummary sums up a symbol's shadow trading, listing the newest shadow transactions first
+func (s *GridService) GetShadowSummary(symbol string) (*ShadowSummary, error) {
+	if s.shadow == nil {
+		return nil, fmt.Errorf("shadow transactions are not recorded")
+	}
+	txs, err := s.shadow.GetBySymbol(symbol, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s shadow transactions: %w", symbol, err)
+	}
+
+	summary := summarizeShadow(txs, time.Time{})
+	summary.Symbol = symbol
+	summary.WatchOnly = s.watchOnly(symbol)
+	for i := len(txs) - 1; i >= 0 && len(summary.Transactions) < shadowListLimit; i-- {
+		summary.Transactions = append(summary.Transactions, txs[i])
+	}
+	return summary, nil
+}
+
+// summarizeShadow totals the shadow fills since a time (all with zero) and counts the open shadow orders
+func summarizeShadow(txs []*models.ShadowTransaction, since time.Time) *ShadowSummary {
+	summary := &ShadowSummary{Transactions: []*models.ShadowTransaction{}}
+	latest := make(map[int]*models.ShadowTransaction)
+	for _, tx := range txs {
+		latest[tx.GridLevelID] = tx
+		if tx.Status != models.StatusFilled || tx.CreatedAt.Before(since) {
+			continue
+		}
+		if tx.Side == models.SideBuy {
+			summary.BuysFilled++
+		} else {
+			summary.SellsFilled++
+			summary.ProfitUSDT = summary.ProfitUSDT.Add(tx.ProfitUSDT.Decimal)
+		}
+	}
+	for _, tx := range latest {
+		if tx.Status == models.StatusPlaced {
+			summary.OpenOrders++
+		}
+	}
+	return summary
+}
+
+// ResetShadow forgets a symbol's shadow transactions, e.g. before watching a changed grid
+func (s *GridService) ResetShadow(symbol string) (int, error) {
+	if s.shadow == nil {
+		return 0, fmt.Errorf("shadow transactions are not recorded")
+	}
+	deleted, err := s.shadow.DeleteBySymbol(symbol, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete %s shadow transactions: %w", symbol, err)
+	}
+	log.Printf("INFO: %s shadow transactions reset (%d)", symbol, deleted)
+	return deleted, nil
+}
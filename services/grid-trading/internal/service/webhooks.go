@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/client"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	webhookBatchSize   = 50
+	webhookMaxAttempts = 12
+	webhookMaxBackoff  = 1 * time.Hour
+	webhookRetention   = 7 * 24 * time.Hour // Delivered events are pruned after this
+)
+
+// ErrWebhookRejected wraps webhook registrations with an invalid URL, symbol or event type
+var ErrWebhookRejected = errors.New("webhook rejected")
+
+// WebhookEventTypes are the events webhooks can subscribe to
+var WebhookEventTypes = []string{
+	contracts.EventBuyFilled, contracts.EventSellFilled, contracts.EventOrderFailed, contracts.EventLevelState,
+	contracts.EventTradingPaused, contracts.EventDrawdownExceeded, contracts.EventQuoteDepegged, contracts.EventExchangeDegraded,
+	contracts.EventPlacementStuck, contracts.EventReconciliation, contracts.EventSafeMode, contracts.EventGridInconsistent, contracts.EventGridTrailed,
+	contracts.EventCalendarPause, contracts.EventCalendarResume,
+	contracts.EventSummary, contracts.EventDailyReport, contracts.EventWeeklyDigest,
+}
+
+// WebhookRepositoryInterface stores webhooks and their outbox of pending deliveries
+type WebhookRepositoryInterface interface {
+	Create(webhook *models.Webhook) error
+	GetAll() ([]*models.Webhook, error)
+	Delete(id int) (bool, error)
+	Enqueue(webhookID int, eventID, eventType, payload string) error
+	GetDue(limit int) ([]*models.WebhookDelivery, error)
+	GetByStatus(status models.WebhookDeliveryStatus, limit int) ([]*models.WebhookDelivery, error)
+	MarkDelivered(id int) error
+	MarkFailed(id int, lastError string, nextAttemptIn time.Duration, maxAttempts int) error
+	Requeue(id int) (bool, error)
+	DeleteDeliveredBefore(before time.Time) (int64, error)
+}
+
+// WebhookSender posts a signed event to a webhook URL
+type WebhookSender interface {
+	Post(url, secret, eventType string, deliveryID int, payload []byte) error
+}
+
+// WebhookDispatcher delivers events to user-registered webhooks. Publish writes one outbox
+// row per subscribed webhook, so events survive restarts and unreachable receivers; the
+// dispatcher sends them right away and retries failures with exponential backoff, moving
+// them to the dead-letter set after webhookMaxAttempts.
+type WebhookDispatcher struct {
+	repo     WebhookRepositoryInterface
+	sender   WebhookSender
+	interval time.Duration
+
+	mu       sync.RWMutex
+	webhooks []*models.Webhook // Cached so matching events doesn't hit the database
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWebhookDispatcher(repo WebhookRepositoryInterface, sender WebhookSender, interval time.Duration) (*WebhookDispatcher, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &WebhookDispatcher{
+		repo:     repo,
+		sender:   sender,
+		interval: interval,
+		wake:     make(chan struct{}, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	if err := d.reload(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to load webhooks: %w", err)
+	}
+	return d, nil
+}
+
+func (d *WebhookDispatcher) Start() {
+	log.Printf("Starting webhook dispatcher with retry interval: %s", d.interval)
+	d.wg.Add(1)
+	go d.loop()
+}
+
+func (d *WebhookDispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *WebhookDispatcher) reload() error {
+	webhooks, err := d.repo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.webhooks = webhooks
+	d.mu.Unlock()
+	return nil
+}
+
+// Register validates and stores a webhook. A secret is generated when none is given -
+// the returned webhook is the only place it is shown.
+func (d *WebhookDispatcher) Register(webhook *models.Webhook) (*models.Webhook, error) {
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []string{}
+	}
+
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http(s) URL", ErrWebhookRejected)
+	}
+
+	webhook.Symbol = strings.ToUpper(strings.TrimSpace(webhook.Symbol))
+	for _, eventType := range webhook.EventTypes {
+		if !isWebhookEventType(eventType) {
+			return nil, fmt.Errorf("%w: unknown event type %q (one of %s)", ErrWebhookRejected, eventType, strings.Join(WebhookEventTypes, ", "))
+		}
+	}
+
+	if webhook.Secret == "" {
+		b := make([]byte, 32)
+		rand.Read(b)
+		webhook.Secret = hex.EncodeToString(b)
+	}
+
+	if err := d.repo.Create(webhook); err != nil {
+		return nil, fmt.Errorf("failed to store webhook: %w", err)
+	}
+	if err := d.reload(); err != nil {
+		log.Printf("ERROR: Failed to reload webhooks: %v", err)
+	}
+
+	log.Printf("INFO: Registered webhook %d → %s (symbol %q, events %v)", webhook.ID, webhook.URL, webhook.Symbol, webhook.EventTypes)
+	return webhook, nil
+}
+
+// List returns the registered webhooks without their secrets
+func (d *WebhookDispatcher) List() []models.Webhook {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	webhooks := make([]models.Webhook, 0, len(d.webhooks))
+	for _, webhook := range d.webhooks {
+		w := *webhook
+		w.Secret = ""
+		webhooks = append(webhooks, w)
+	}
+	return webhooks
+}
+
+// Delete removes a webhook along with its undelivered events
+func (d *WebhookDispatcher) Delete(id int) (bool, error) {
+	deleted, err := d.repo.Delete(id)
+	if err != nil || !deleted {
+		return deleted, err
+	}
+	if err := d.reload(); err != nil {
+		log.Printf("ERROR: Failed to reload webhooks: %v", err)
+	}
+
+	log.Printf("INFO: Deleted webhook %d", id)
+	return true, nil
+}
+
+// GetDeliveries lists the latest deliveries in status
+func (d *WebhookDispatcher) GetDeliveries(status models.WebhookDeliveryStatus) ([]*models.WebhookDelivery, error) {
+	return d.repo.GetByStatus(status, 100)
+}
+
+// Requeue moves a dead-letter delivery back to pending and sends it right away
+func (d *WebhookDispatcher) Requeue(id int) (bool, error) {
+	requeued, err := d.repo.Requeue(id)
+	if requeued {
+		log.Printf("INFO: Requeued dead-letter webhook delivery %d", id)
+		d.nudge()
+	}
+	return requeued, err
+}
+
+// Subscribed reports whether any webhook wants eventType, for events that cost a lookup to build
+func (d *WebhookDispatcher) Subscribed(eventType string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, webhook := range d.webhooks {
+		if webhook.Wants(eventType, "") {
+			return true
+		}
+	}
+	return false
+}
+
+// Publish queues the event for every webhook subscribed to it
+func (d *WebhookDispatcher) Publish(event client.Event) {
+	d.mu.RLock()
+	var targets []*models.Webhook
+	for _, webhook := range d.webhooks {
+		if webhook.Wants(event.Type, event.Symbol) {
+			targets = append(targets, webhook)
+		}
+	}
+	d.mu.RUnlock()
+
+	if len(targets) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("ERROR: Failed to marshal %s event for webhooks: %v", event.Type, err)
+		return
+	}
+
+	for _, webhook := range targets {
+		if err := d.repo.Enqueue(webhook.ID, event.ID, event.Type, string(payload)); err != nil {
+			log.Printf("ERROR: Failed to queue %s event for webhook %d: %v", event.Type, webhook.ID, err)
+		}
+	}
+	d.nudge()
+}
+
+func (d *WebhookDispatcher) nudge() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *WebhookDispatcher) loop() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	d.deliverDue()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-d.wake:
+			d.deliverDue()
+		case <-ticker.C:
+			d.deliverDue()
+			if _, err := d.repo.DeleteDeliveredBefore(time.Now().Add(-webhookRetention)); err != nil {
+				log.Printf("WARNING: Failed to prune delivered webhook events: %v", err)
+			}
+		}
+	}
+}
+
+func (d *WebhookDispatcher) deliverDue() {
+	for {
+		deliveries, err := d.repo.GetDue(webhookBatchSize)
+		if err != nil {
+			log.Printf("ERROR: Failed to load due webhook deliveries: %v", err)
+			return
+		}
+
+		for _, delivery := range deliveries {
+			if d.ctx.Err() != nil {
+				return
+			}
+			d.deliver(delivery)
+		}
+
+		// A full batch may have more behind it; failed ones are rescheduled, so this ends
+		if len(deliveries) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+func (d *WebhookDispatcher) deliver(delivery *models.WebhookDelivery) {
+	err := d.sender.Post(delivery.URL, delivery.Secret, delivery.EventType, delivery.ID, []byte(delivery.Payload))
+	if err != nil {
+		backoff := d.interval * time.Duration(1<<uint(min(delivery.Attempts, 10)))
+		if backoff > webhookMaxBackoff {
+			backoff = webhookMaxBackoff
+		}
+
+		if err := d.repo.MarkFailed(delivery.ID, err.Error(), backoff, webhookMaxAttempts); err != nil {
+			log.Printf("ERROR: Failed to update webhook delivery %d: %v", delivery.ID, err)
+		}
+
+		if delivery.Attempts+1 >= webhookMaxAttempts {
+			log.Printf("ERROR: Webhook delivery %d (%s to webhook %d) moved to dead-letter after %d attempts: %v",
+				delivery.ID, delivery.EventType, delivery.WebhookID, delivery.Attempts+1, err)
+		} else {
+			log.Printf("WARNING: Webhook delivery %d (%s to webhook %d) failed, next attempt in %s: %v",
+				delivery.ID, delivery.EventType, delivery.WebhookID, backoff, err)
+		}
+		return
+	}
+
+	if err := d.repo.MarkDelivered(delivery.ID); err != nil {
+		log.Printf("ERROR: Failed to mark webhook delivery %d delivered: %v", delivery.ID, err)
+	}
+}
+
+func isWebhookEventType(eventType string) bool {
+	for _, t := range WebhookEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// UseWebhooks sends events to registered webhooks as well, plus a level_state event on
+// every level state transition (webhooks only - too chatty for the notifier)
+func (s *GridService) UseWebhooks(webhooks *WebhookDispatcher) {
+	s.webhooks = webhooks
+	s.repo = &stateEventRepository{GridLevelRepositoryInterface: s.repo, service: s}
+}
+
+// stateEventRepository publishes level_state events for the state changes made through it
+type stateEventRepository struct {
+	GridLevelRepositoryInterface
+	service *GridService
+}
+
+func (r *stateEventRepository) changed(id int, err error) {
+	if err != nil || !r.service.webhooks.Subscribed(contracts.EventLevelState) {
+		return
+	}
+
+	level, err := r.GridLevelRepositoryInterface.GetByID(id)
+	if err != nil || level == nil {
+		log.Printf("WARNING: Failed to read level %d for its level_state webhook event: %v", id, err)
+		return
+	}
+
+	fields := map[string]string{
+		"level_id":   fmt.Sprint(level.ID),
+		"state":      string(level.State),
+		"buy_price":  level.BuyPrice.String(),
+		"sell_price": level.SellPrice.String(),
+	}
+	if level.FilledAmount.Valid {
+		fields["filled_amount"] = level.FilledAmount.Decimal.String()
+	}
+
+	r.service.webhooks.Publish(r.service.newEvent(contracts.EventLevelState, level.Symbol,
+		fmt.Sprintf("Level %d (%s-%s) → %s", level.ID, level.BuyPrice, level.SellPrice, level.State), fields))
+}
+
+func (r *stateEventRepository) TryStartBuyOrder(id int, amount decimal.Decimal) (bool, error) {
+	ok, err := r.GridLevelRepositoryInterface.TryStartBuyOrder(id, amount)
+	if ok {
+		r.changed(id, err)
+	}
+	return ok, err
+}
+
+func (r *stateEventRepository) TryStartSellOrder(id int) (bool, error) {
+	ok, err := r.GridLevelRepositoryInterface.TryStartSellOrder(id)
+	if ok {
+		r.changed(id, err)
+	}
+	return ok, err
+}
+
+func (r *stateEventRepository) UpdateState(id int, state models.GridState) error {
+	err := r.GridLevelRepositoryInterface.UpdateState(id, state)
+	r.changed(id, err)
+	return err
+}
+
+func (r *stateEventRepository) UpdateBuyOrderPlaced(id int, orderID string) error {
+	err := r.GridLevelRepositoryInterface.UpdateBuyOrderPlaced(id, orderID)
+	r.changed(id, err)
+	return err
+}
+
+func (r *stateEventRepository) UpdateSellOrderPlaced(id int, orderID string) error {
+	err := r.GridLevelRepositoryInterface.UpdateSellOrderPlaced(id, orderID)
+	r.changed(id, err)
+	return err
+}
+
+func (r *stateEventRepository) ProcessBuyFill(id int, filledAmount decimal.Decimal) error {
+	err := r.GridLevelRepositoryInterface.ProcessBuyFill(id, filledAmount)
+	r.changed(id, err)
+	return err
+}
+
+func (r *stateEventRepository) ProcessSellFill(id int) error {
+	err := r.GridLevelRepositoryInterface.ProcessSellFill(id)
+	r.changed(id, err)
+	return err
+}
+
+func (r *stateEventRepository) SeedHolding(id int, filledAmount, costUSDT decimal.Decimal) (bool, error) {
+	ok, err := r.GridLevelRepositoryInterface.SeedHolding(id, filledAmount, costUSDT)
+	if ok {
+		r.changed(id, err)
+	}
+	return ok, err
+}
+
+func (r *stateEventRepository) Recover(id int, to models.GridState) (bool, error) {
+	ok, err := r.GridLevelRepositoryInterface.Recover(id, to)
+	if ok {
+		r.changed(id, err)
+	}
+	return ok, err
+}
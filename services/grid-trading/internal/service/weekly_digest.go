@@ -0,0 +1,204 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/grid-trading/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// digestLevels is how many best and worst levels the weekly digest lists
+const digestLevels = 3
+
+// SymbolDigest is one symbol's week. Capital utilization is a snapshot at digest time:
+// the cost of coins held over the worst-case capital of the grid (see CapitalEstimate).
+type SymbolDigest struct {
+	Symbol         string          `json:"symbol"`
+	Cycles         int             `json:"cycles"`
+	ProfitUSDT     decimal.Decimal `json:"profit_usdt"`
+	CapitalUSDT    decimal.Decimal `json:"capital_usdt"`
+	DeployedUSDT   decimal.Decimal `json:"deployed_usdt"`
+	UtilizationPct decimal.Decimal `json:"utilization_pct"`
+	ReturnPct      decimal.Decimal `json:"return_pct"` // Week's profit over capital
+}
+
+// LevelDigest is one grid level's week
+type LevelDigest struct {
+	GridLevelID int             `json:"grid_level_id"`
+	Symbol      string          `json:"symbol"`
+	BuyPrice    decimal.Decimal `json:"buy_price"`
+	SellPrice   decimal.Decimal `json:"sell_price"`
+	Cycles      int             `json:"cycles"`
+	ProfitUSDT  decimal.Decimal `json:"profit_usdt"`
+}
+
+// WeeklyDigest summarizes the seven days up to To. Worst levels include enabled levels
+// that didn't complete a cycle, so idle parts of a grid show up.
+type WeeklyDigest struct {
+	From           string          `json:"from"`
+	To             string          `json:"to"`
+	Cycles         int             `json:"cycles"`
+	ProfitUSDT     decimal.Decimal `json:"profit_usdt"`
+	CapitalUSDT    decimal.Decimal `json:"capital_usdt"`
+	DeployedUSDT   decimal.Decimal `json:"deployed_usdt"`
+	UtilizationPct decimal.Decimal `json:"utilization_pct"`
+	Symbols        []SymbolDigest  `json:"symbols"`
+	BestLevels     []LevelDigest   `json:"best_levels"`
+	WorstLevels    []LevelDigest   `json:"worst_levels"`
+}
+
+// GetWeeklyDigest aggregates the seven days before to; the days are those of the reporting timezone
+func (s *GridService) GetWeeklyDigest(to time.Time) (*WeeklyDigest, error) {
+	to = to.In(s.reportLoc)
+	from := to.AddDate(0, 0, -7)
+
+	cycles, err := s.txRepo.GetLevelCycles(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get level cycles: %w", err)
+	}
+
+	levels, err := s.repo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+
+	digest := &WeeklyDigest{
+		From:        from.Format(time.RFC3339),
+		To:          to.Format(time.RFC3339),
+		Symbols:     []SymbolDigest{},
+		BestLevels:  []LevelDigest{},
+		WorstLevels: []LevelDigest{},
+	}
+
+	bySymbol := make(map[string]*SymbolDigest)
+	symbolDigest := func(symbol string) *SymbolDigest {
+		sd, ok := bySymbol[symbol]
+		if !ok {
+			sd = &SymbolDigest{Symbol: symbol}
+			bySymbol[symbol] = sd
+		}
+		return sd
+	}
+
+	byLevel := make(map[int]*LevelDigest)
+	enabled := make(map[string][]*models.GridLevel)
+	for _, level := range levels {
+		if !level.Enabled {
+			continue
+		}
+		enabled[level.Symbol] = append(enabled[level.Symbol], level)
+		byLevel[level.ID] = &LevelDigest{
+			GridLevelID: level.ID,
+			Symbol:      level.Symbol,
+			BuyPrice:    level.BuyPrice,
+			SellPrice:   level.SellPrice,
+		}
+		if holdsCoin(level) {
+			sd := symbolDigest(level.Symbol)
+			sd.DeployedUSDT = sd.DeployedUSDT.Add(level.CycleAmount())
+		}
+	}
+	for symbol, symbolLevels := range enabled {
+		symbolDigest(symbol).CapitalUSDT = estimateCapital(symbol, symbolLevels).WorstCaseUSDT
+	}
+
+	// Cycles of levels deleted or disabled since still count for their symbol
+	for _, c := range cycles {
+		sd := symbolDigest(c.Symbol)
+		sd.Cycles += c.Cycles
+		sd.ProfitUSDT = sd.ProfitUSDT.Add(c.ProfitUSDT)
+		if ld, ok := byLevel[c.GridLevelID]; ok {
+			ld.Cycles = c.Cycles
+			ld.ProfitUSDT = c.ProfitUSDT
+		}
+	}
+
+	hundred := decimal.NewFromInt(100)
+	for _, sd := range bySymbol {
+		if sd.CapitalUSDT.IsPositive() {
+			sd.UtilizationPct = sd.DeployedUSDT.Div(sd.CapitalUSDT).Mul(hundred).Round(2)
+			sd.ReturnPct = sd.ProfitUSDT.Div(sd.CapitalUSDT).Mul(hundred).Round(2)
+		}
+		digest.Cycles += sd.Cycles
+		digest.ProfitUSDT = digest.ProfitUSDT.Add(sd.ProfitUSDT)
+		digest.CapitalUSDT = digest.CapitalUSDT.Add(sd.CapitalUSDT)
+		digest.DeployedUSDT = digest.DeployedUSDT.Add(sd.DeployedUSDT)
+		digest.Symbols = append(digest.Symbols, *sd)
+	}
+	sort.Slice(digest.Symbols, func(i, j int) bool { return digest.Symbols[i].Symbol < digest.Symbols[j].Symbol })
+	if digest.CapitalUSDT.IsPositive() {
+		digest.UtilizationPct = digest.DeployedUSDT.Div(digest.CapitalUSDT).Mul(hundred).Round(2)
+	}
+
+	ranked := make([]LevelDigest, 0, len(byLevel))
+	for _, ld := range byLevel {
+		ranked = append(ranked, *ld)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if !ranked[i].ProfitUSDT.Equal(ranked[j].ProfitUSDT) {
+			return ranked[i].ProfitUSDT.GreaterThan(ranked[j].ProfitUSDT)
+		}
+		return ranked[i].GridLevelID < ranked[j].GridLevelID
+	})
+
+	// Only levels that made money are "best", and a level is never listed as both
+	for _, ld := range ranked {
+		if len(digest.BestLevels) == digestLevels || !ld.ProfitUSDT.IsPositive() {
+			break
+		}
+		digest.BestLevels = append(digest.BestLevels, ld)
+	}
+	for i := len(ranked) - 1; i >= len(digest.BestLevels) && len(digest.WorstLevels) < digestLevels; i-- {
+		digest.WorstLevels = append(digest.WorstLevels, ranked[i])
+	}
+
+	return digest, nil
+}
+
+// SendWeeklyDigest sends the digest of the last seven days to the notifier
+func (s *GridService) SendWeeklyDigest() (*WeeklyDigest, error) {
+	if s.events == nil {
+		return nil, fmt.Errorf("no event sink configured")
+	}
+
+	digest, err := s.GetWeeklyDigest(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []string
+	for _, sd := range digest.Symbols {
+		symbols = append(symbols, fmt.Sprintf("%s: %d cycles, %s USDT (%s%%), utilization %s%%",
+			sd.Symbol, sd.Cycles, sd.ProfitUSDT, sd.ReturnPct, sd.UtilizationPct))
+	}
+
+	fields := map[string]string{
+		"from":            digest.From[:10],
+		"to":              digest.To[:10],
+		"cycles":          strconv.Itoa(digest.Cycles),
+		"profit_usdt":     digest.ProfitUSDT.String(),
+		"utilization_pct": digest.UtilizationPct.String(),
+		"symbols":         strings.Join(symbols, "\n"),
+		"best_levels":     formatDigestLevels(digest.BestLevels),
+		"worst_levels":    formatDigestLevels(digest.WorstLevels),
+	}
+	message := fmt.Sprintf("%d cycles, profit %s USDT, capital utilization %s%%",
+		digest.Cycles, digest.ProfitUSDT, digest.UtilizationPct)
+
+	s.emit(contracts.EventWeeklyDigest, "", message, fields)
+	return digest, nil
+}
+
+func formatDigestLevels(levels []LevelDigest) string {
+	var lines []string
+	for _, ld := range levels {
+		lines = append(lines, fmt.Sprintf("%s %s-%s: %d cycles, %s USDT",
+			ld.Symbol, ld.BuyPrice, ld.SellPrice, ld.Cycles, ld.ProfitUSDT))
+	}
+	return strings.Join(lines, "\n")
+}
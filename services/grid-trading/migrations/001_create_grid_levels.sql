@@ -2,20 +2,28 @@
 CREATE TABLE IF NOT EXISTS grid_levels (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     symbol TEXT NOT NULL,
+    account TEXT NOT NULL DEFAULT '',  -- Sub-account the level trades on (empty = master)
     buy_price TEXT NOT NULL,
     sell_price TEXT NOT NULL,
     buy_amount TEXT NOT NULL,
+    buy_multiplier TEXT,               -- Scaled re-entry (NULL = flat buys)
+    max_buy_amount TEXT,               -- Cap on a scaled buy
+    order_amount TEXT,                 -- USDT of the current cycle's buy
     filled_amount TEXT,
     state TEXT NOT NULL DEFAULT 'READY',
     buy_order_id TEXT,
     sell_order_id TEXT,
     enabled INTEGER DEFAULT 1,
+    recovery_attempts INTEGER NOT NULL DEFAULT 0, -- Failed SyncOrders recoveries since the last placed order
+    error_msg TEXT,                    -- Why the level is in ERROR (e.g. quarantined after failed recoveries)
+    tags TEXT,                         -- Comma-separated operator tags, lowercase (NULL = none)
+    notes TEXT,                        -- Free-form operator notes, e.g. why the level was disabled
     state_changed_at TEXT NOT NULL DEFAULT (datetime('now')),
     created_at TEXT NOT NULL DEFAULT (datetime('now')),
     updated_at TEXT NOT NULL DEFAULT (datetime('now')),
 
     -- Constraints
-    CONSTRAINT unique_level UNIQUE (symbol, buy_price, sell_price),
+    CONSTRAINT unique_level UNIQUE (account, symbol, buy_price, sell_price),
     CONSTRAINT check_prices CHECK (CAST(sell_price AS REAL) > CAST(buy_price AS REAL)),
     CONSTRAINT check_state CHECK (state IN ('READY', 'PLACING_BUY', 'BUY_ACTIVE', 'HOLDING', 'PLACING_SELL', 'SELL_ACTIVE', 'ERROR'))
 );
@@ -7,7 +7,7 @@ CREATE TABLE IF NOT EXISTS transactions (
 
     -- What happened (separate columns for clarity)
     side TEXT NOT NULL,           -- BUY | SELL
-    status TEXT NOT NULL,         -- PLACED | FILLED | ERROR
+    status TEXT NOT NULL,         -- PLACED | FILLED | CANCELLED | ERROR
 
     -- Order details (NULL for errors)
     order_id TEXT,               -- Exchange order ID
@@ -15,7 +15,7 @@ CREATE TABLE IF NOT EXISTS transactions (
     executed_price TEXT,        -- Actual fill price (NULL if error)
 
     -- Amounts (NULL for errors)
-    amount_coin TEXT,           -- ETH bought/sold
+    amount_coin TEXT,           -- ETH bought/sold (CANCELLED: filled before the cancel)
     amount_usdt TEXT,           -- USDT spent/received
 
     -- Profit tracking (only for SELL with status=FILLED)
@@ -23,6 +23,11 @@ CREATE TABLE IF NOT EXISTS transactions (
     profit_usdt TEXT,           -- Sell USDT - Buy USDT
     profit_pct TEXT,             -- (profit / buy cost) * 100
 
+    -- Fees (only for status=FILLED)
+    commission TEXT,             -- Fee charged by Binance
+    commission_asset TEXT,       -- Asset the fee was charged in
+    fee_usdt TEXT,               -- Fee valued in USDT (NULL if unpriced, e.g. BNB)
+
     -- Error details (only when status=ERROR)
     error_code TEXT,              -- insufficient_funds, api_error, etc
     error_msg TEXT,                      -- Full error details
@@ -32,7 +37,7 @@ CREATE TABLE IF NOT EXISTS transactions (
 
     -- Constraints
     CONSTRAINT check_side CHECK (side IN ('BUY', 'SELL')),
-    CONSTRAINT check_status CHECK (status IN ('PLACED', 'FILLED', 'ERROR')),
+    CONSTRAINT check_status CHECK (status IN ('PLACED', 'FILLED', 'CANCELLED', 'ERROR')),
     CONSTRAINT check_placed_has_order CHECK (status = 'ERROR' OR order_id IS NOT NULL),
     CONSTRAINT check_filled_has_executed_price CHECK (status != 'FILLED' OR executed_price IS NOT NULL),
     CONSTRAINT check_error_has_code CHECK (status != 'ERROR' OR error_code IS NOT NULL)
@@ -0,0 +1,19 @@
+-- Create profit_sweeps table: audit record of every profit sweep attempt (including dry runs)
+CREATE TABLE IF NOT EXISTS profit_sweeps (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account TEXT NOT NULL DEFAULT '',  -- Sub-account swept from (empty = master)
+    asset TEXT NOT NULL,
+    amount TEXT NOT NULL,
+    destination TEXT NOT NULL,         -- earn | withdraw
+    unswept_profit TEXT NOT NULL,      -- Realized profit not yet swept when the job ran
+    status TEXT NOT NULL,              -- DRY_RUN | COMPLETED | FAILED
+    reference TEXT,                    -- Earn purchase ID or withdrawal ID
+    error_msg TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+
+    -- Constraints
+    CONSTRAINT check_sweep_status CHECK (status IN ('DRY_RUN', 'COMPLETED', 'FAILED'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_sweeps_account_status ON profit_sweeps(account, status);
@@ -0,0 +1,11 @@
+-- Create price_history table: sampled trigger prices, the reference for benchmarking the grid
+-- against buying and holding the same capital
+CREATE TABLE IF NOT EXISTS price_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    symbol TEXT NOT NULL,
+    price TEXT NOT NULL,
+    recorded_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_price_history_symbol_time ON price_history(symbol, recorded_at);
@@ -0,0 +1,13 @@
+-- Create funding_fees table: funding payments of the USDT-M futures account, deducted from grid profit
+CREATE TABLE IF NOT EXISTS funding_fees (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    income_id TEXT NOT NULL UNIQUE,   -- Binance tranId, so re-syncing a window never double counts
+    symbol TEXT NOT NULL,
+    asset TEXT NOT NULL,
+    amount TEXT NOT NULL,             -- Negative when paid, positive when received
+    funded_at TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_funding_fees_symbol_time ON funding_fees(symbol, funded_at);
@@ -0,0 +1,14 @@
+-- Create margin_interest table: interest charged on the margin account's borrowed assets, deducted from grid profit
+CREATE TABLE IF NOT EXISTS margin_interest (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    income_id TEXT NOT NULL UNIQUE,   -- Binance txId, so re-syncing a window never double counts
+    symbol TEXT NOT NULL DEFAULT '',  -- Isolated pair (empty = cross margin)
+    asset TEXT NOT NULL,
+    amount TEXT NOT NULL,
+    amount_usdt TEXT,                 -- Valued at the latest price when recorded (NULL = no price)
+    charged_at TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_margin_interest_time ON margin_interest(charged_at);
@@ -0,0 +1,7 @@
+-- Create daily_reports table: one end-of-day report per UTC date, stored as JSON so
+-- past reports read back exactly as they were sent
+CREATE TABLE IF NOT EXISTS daily_reports (
+    date TEXT PRIMARY KEY,          -- YYYY-MM-DD (UTC)
+    report TEXT NOT NULL,           -- models.DailyReport as JSON
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
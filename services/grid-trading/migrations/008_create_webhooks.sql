@@ -0,0 +1,32 @@
+-- Create webhooks table: outbound subscriptions for external automation
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,            -- HMAC-SHA256 key for the X-Webhook-Signature header
+    symbol TEXT,                     -- Only events of this grid, plus events without a symbol (NULL = all grids)
+    event_types TEXT,                -- Comma-separated event types (NULL = all)
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- Create webhook_outbox table: one row per event per subscribed webhook, delivered
+-- by the dispatcher with retries
+CREATE TABLE IF NOT EXISTS webhook_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,           -- Event JSON as posted
+    status TEXT NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TEXT NOT NULL DEFAULT (datetime('now')),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+
+    -- Constraints
+    CONSTRAINT check_status CHECK (status IN ('PENDING', 'DELIVERED', 'DEAD'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_webhook_outbox_status_next ON webhook_outbox(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_outbox_webhook_id ON webhook_outbox(webhook_id);
@@ -0,0 +1,10 @@
+-- Create sell_dust table: coin left over per grid when sell quantities are rounded down to the
+-- exchange's quantity step (SELL_QUANTITY_POLICY), so leftovers are reported and can be sold later
+CREATE TABLE IF NOT EXISTS sell_dust (
+    account TEXT NOT NULL DEFAULT '',  -- Sub-account (empty = master)
+    symbol TEXT NOT NULL,
+    dust_coin TEXT NOT NULL,           -- Unsold coin not yet added to a sell
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+
+    PRIMARY KEY (account, symbol)
+);
@@ -0,0 +1,10 @@
+-- Create reconciliation_reports table: periodic checks of the levels against the exchange
+-- (order states, untracked open orders, coin balances), stored as JSON like daily reports
+CREATE TABLE IF NOT EXISTS reconciliation_reports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    status TEXT NOT NULL,           -- clean | discrepancies
+    report TEXT NOT NULL,           -- models.ReconciliationReport as JSON
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_reconciliation_reports_created_at ON reconciliation_reports(created_at);
@@ -0,0 +1,22 @@
+-- Create grid_levels_archive table: levels of grids torn down with DELETE /grids/{symbol}. They keep
+-- their grid_levels ID, so their transactions (the audit log is never deleted) still resolve.
+CREATE TABLE IF NOT EXISTS grid_levels_archive (
+    id INTEGER PRIMARY KEY,            -- ID the level had in grid_levels (AUTOINCREMENT never reuses it)
+    symbol TEXT NOT NULL,
+    account TEXT NOT NULL DEFAULT '',
+    buy_price TEXT NOT NULL,
+    sell_price TEXT NOT NULL,
+    buy_amount TEXT NOT NULL,
+    filled_amount TEXT,                -- Coin still held when archived without selling
+    state TEXT NOT NULL,               -- READY, HOLDING or ERROR at teardown
+    created_at TEXT NOT NULL,
+    archived_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_grid_levels_archive_symbol ON grid_levels_archive(symbol);
+
+-- Live and archived levels, for transaction queries that need the level's account or prices
+CREATE VIEW IF NOT EXISTS all_grid_levels AS
+    SELECT id, symbol, account, buy_price, sell_price FROM grid_levels
+    UNION ALL
+    SELECT id, symbol, account, buy_price, sell_price FROM grid_levels_archive;
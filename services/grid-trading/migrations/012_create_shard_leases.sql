@@ -0,0 +1,17 @@
+-- Create shard tables: grid-trading instances sharing this database (SHARDING_ENABLED) register
+-- here and lease disjoint sets of symbols, each trading only the symbols it holds
+CREATE TABLE IF NOT EXISTS shard_instances (
+    instance_id TEXT PRIMARY KEY,
+    url TEXT NOT NULL,                 -- Where price-monitor reaches the instance (INSTANCE_URL)
+    heartbeat_at TEXT NOT NULL,        -- Instances without a heartbeat for SHARD_LEASE_SEC are gone
+    expires_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS symbol_leases (
+    symbol TEXT PRIMARY KEY,
+    instance_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    expires_at TEXT NOT NULL           -- Renewed by the owner; anyone may claim it once expired
+);
+
+CREATE INDEX IF NOT EXISTS idx_symbol_leases_instance_id ON symbol_leases(instance_id);
@@ -0,0 +1,15 @@
+-- Create grid_fingerprints table: the parameters of every grid created with POST /levels/init,
+-- keyed by their fingerprint, to spot repeated requests and grids overlapping on a symbol
+CREATE TABLE IF NOT EXISTS grid_fingerprints (
+    fingerprint TEXT PRIMARY KEY,      -- Hash of the grid parameters
+    symbol TEXT NOT NULL,
+    account TEXT NOT NULL DEFAULT '',
+    grid_type TEXT NOT NULL,           -- arithmetic | geometric
+    min_price TEXT NOT NULL,
+    max_price TEXT NOT NULL,
+    grid_step TEXT NOT NULL,           -- Quote currency (arithmetic) or percent (geometric)
+    buy_amount TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_grid_fingerprints_symbol ON grid_fingerprints(symbol);
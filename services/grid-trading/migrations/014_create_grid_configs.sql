@@ -0,0 +1,12 @@
+-- Create grid_configs table: per-symbol grid settings changed with PUT /grids/{symbol}/config
+CREATE TABLE IF NOT EXISTS grid_configs (
+    symbol TEXT PRIMARY KEY,
+    trailing_enabled BOOLEAN NOT NULL DEFAULT false, -- Shift the grid band after the price
+    trail_after_min INTEGER NOT NULL DEFAULT 30,     -- Minutes the price stays outside the band before a shift
+    watch_only BOOLEAN NOT NULL DEFAULT false,       -- Record shadow orders instead of placing real ones
+    order_type TEXT NOT NULL DEFAULT 'limit',        -- limit | market: how triggered levels place their orders
+    stop_loss_pct TEXT,                              -- Sell with an OCO, stop-loss this far below the buy price (NULL = plain sells)
+    tags TEXT,                                       -- Comma-separated operator tags, lowercase (NULL = none)
+    notes TEXT,                                      -- Free-form operator notes
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
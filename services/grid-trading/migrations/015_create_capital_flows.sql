@@ -0,0 +1,13 @@
+-- Create capital_flows table: USDT deposited into or withdrawn from trading, so ROI is measured
+-- against the capital actually put in rather than as absolute profit
+CREATE TABLE IF NOT EXISTS capital_flows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,               -- deposit | withdrawal
+    amount_usdt TEXT NOT NULL,        -- Always positive, kind gives the direction
+    source TEXT NOT NULL DEFAULT '',  -- Free-form tag of where the money came from or went (salary, savings, ...)
+    note TEXT NOT NULL DEFAULT '',
+    occurred_at TEXT NOT NULL,        -- When the money moved; ROI weights each flow by the time it was invested
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_capital_flows_occurred_at ON capital_flows(occurred_at);
@@ -0,0 +1,19 @@
+-- Create shadow_transactions table: orders watch-only grids and shadow strategies would have placed
+-- and filled, kept apart from transactions so they never count as real trades or profit
+CREATE TABLE IF NOT EXISTS shadow_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    strategy_id INTEGER NOT NULL DEFAULT 0,  -- shadow_strategies.id, 0 for a watch-only grid
+    grid_level_id INTEGER NOT NULL,          -- Level ID, or the level's number within a shadow strategy
+    symbol TEXT NOT NULL,
+    account TEXT NOT NULL DEFAULT '',
+    side TEXT NOT NULL,               -- BUY | SELL
+    status TEXT NOT NULL,             -- PLACED | FILLED
+    target_price TEXT NOT NULL,       -- Order price the level would have used
+    trigger_price TEXT NOT NULL,      -- Price that made the decision
+    amount_coin TEXT NOT NULL,
+    amount_usdt TEXT NOT NULL,
+    profit_usdt TEXT,                 -- SELL FILLED only, after TRADING_FEE on both legs
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_shadow_transactions_symbol ON shadow_transactions(symbol, strategy_id, grid_level_id);
@@ -0,0 +1,17 @@
+-- Create shadow_strategies table: alternative grids run in shadow next to a symbol's live grid,
+-- their orders recorded in shadow_transactions under the strategy's id
+CREATE TABLE IF NOT EXISTS shadow_strategies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    symbol TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    grid_type TEXT NOT NULL DEFAULT 'arithmetic',  -- arithmetic | geometric
+    min_price TEXT NOT NULL,
+    max_price TEXT NOT NULL,
+    grid_step TEXT NOT NULL,                       -- USDT step, or percent for a geometric grid
+    buy_amount TEXT NOT NULL,
+    buy_order_type TEXT NOT NULL DEFAULT 'limit',  -- limit | quote_market, as BUY_ORDER_TYPE
+    fee_aware_sell BOOLEAN NOT NULL DEFAULT false, -- Sells placed above sell_price to cover TRADING_FEE
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_shadow_strategies_symbol ON shadow_strategies(symbol);
@@ -0,0 +1,7 @@
+-- Create notification_cursor table: the order-assurance notification seq up to which every
+-- notification was applied, so missed ones can be replayed (GET /notifications/replay there)
+CREATE TABLE IF NOT EXISTS notification_cursor (
+    id INTEGER PRIMARY KEY CHECK (id = 1), -- Single row, shared by all instances
+    last_seq INTEGER NOT NULL,
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
@@ -0,0 +1,25 @@
+-- Create jobs table: long-running operations (simulations, reconciliations, kline downloads,
+-- bulk changes) run by the job workers, with their progress and result (GET /jobs/{id})
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,              -- simulate, reconciliation, klines_download, levels_bulk, dead_levels_archive
+    params TEXT,                     -- Request JSON
+    status TEXT NOT NULL DEFAULT 'QUEUED',
+    done INTEGER NOT NULL DEFAULT 0, -- Steps done of total
+    total INTEGER NOT NULL DEFAULT 0,
+    message TEXT,                    -- What the job is doing
+    result TEXT,                     -- Response JSON, partial for a cancelled job
+    error TEXT,
+    actor TEXT,
+    instance_id TEXT NOT NULL DEFAULT '', -- INSTANCE_ID of the instance running it (empty without sharding)
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    started_at TEXT,
+    finished_at TEXT,
+
+    -- Constraints
+    CONSTRAINT check_status CHECK (status IN ('QUEUED', 'RUNNING', 'SUCCEEDED', 'FAILED', 'CANCELLED'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
+CREATE INDEX IF NOT EXISTS idx_jobs_created_at ON jobs(created_at);
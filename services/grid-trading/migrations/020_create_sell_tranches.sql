@@ -0,0 +1,31 @@
+-- Create sell_tranches table: the take-profit ladder of a level created with sell_tranches -
+-- its bought amount is sold in parts, each at its own profit over the buy price, and the cycle
+-- completes once every tranche is sold
+CREATE TABLE IF NOT EXISTS sell_tranches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    grid_level_id INTEGER NOT NULL REFERENCES grid_levels(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,         -- Order of the tranche in the ladder, from 1
+    share_pct TEXT NOT NULL,           -- Percent of the bought amount this tranche sells
+    profit_pct TEXT NOT NULL,          -- Sell price as percent above the level's buy price
+    status TEXT NOT NULL DEFAULT 'PENDING',
+
+    -- Current cycle (NULL between cycles)
+    amount TEXT,                       -- Coin left for this tranche to sell
+    order_id TEXT,                     -- Open sell order
+    price TEXT,                        -- Price of the open or filled sell
+    sold_amount TEXT,
+    proceeds_usdt TEXT,
+    commission TEXT,
+    commission_asset TEXT,
+    fee_usdt TEXT,                     -- Commission valued in USDT, when it could be priced
+
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+
+    -- Constraints
+    CONSTRAINT check_status CHECK (status IN ('PENDING', 'OPEN', 'SOLD')),
+    UNIQUE (grid_level_id, position)
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_sell_tranches_order_id ON sell_tranches(order_id);
+CREATE INDEX IF NOT EXISTS idx_sell_tranches_status ON sell_tranches(status);
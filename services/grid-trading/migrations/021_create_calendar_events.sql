@@ -0,0 +1,15 @@
+-- Create calendar_events table: high-impact events (FOMC, CPI, ...) around which buys pause
+CREATE TABLE IF NOT EXISTS calendar_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    starts_at TEXT NOT NULL,            -- UTC; buys pause CALENDAR_PAUSE_BEFORE_MIN earlier
+    ends_at TEXT NOT NULL,              -- UTC, starts_at for a point in time; buys resume CALENDAR_RESUME_AFTER_MIN later
+    symbols TEXT NOT NULL DEFAULT '',   -- Comma-separated symbols it pauses (empty = CALENDAR_SYMBOLS)
+    source TEXT NOT NULL DEFAULT 'api', -- api | file (CALENDAR_FILE)
+    paused_at TEXT,                     -- When its window began pausing buys
+    resumed_at TEXT,                    -- When buys resumed after it
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE(name, starts_at)             -- Importing the same event again updates it
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_events_ends_at ON calendar_events(ends_at);
@@ -0,0 +1,13 @@
+-- Create stop_loss_orders table: the stop-loss leg of a level's open OCO sell (grid config
+-- stop_loss_pct). The level keeps the limit leg as its sell_order_id; a row whose sell order
+-- the level no longer waits on is stale and replaced by the level's next OCO.
+CREATE TABLE IF NOT EXISTS stop_loss_orders (
+    grid_level_id INTEGER PRIMARY KEY REFERENCES grid_levels(id) ON DELETE CASCADE,
+    sell_order_id TEXT NOT NULL,       -- Limit leg, at the level's sell price
+    stop_order_id TEXT NOT NULL,       -- Stop-loss leg, a market sell once the price falls to stop_price
+    stop_price TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_stop_loss_orders_stop_order_id ON stop_loss_orders(stop_order_id);
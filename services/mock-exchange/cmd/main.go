@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/mock-exchange/internal/api"
+	"github.com/grid-trading-bot/services/mock-exchange/internal/config"
+	"github.com/grid-trading-bot/services/mock-exchange/internal/engine"
+)
+
+func main() {
+	logging.Setup("mock-exchange")
+	cfg := config.LoadConfig()
+
+	exchange := engine.NewExchange(engine.Config{
+		PricePaths:     cfg.PricePaths,
+		Balances:       cfg.Balances,
+		CommissionRate: cfg.CommissionRate,
+		Filters: engine.SymbolFilters{
+			TickSize:    cfg.TickSize,
+			StepSize:    cfg.StepSize,
+			MinQty:      cfg.StepSize,
+			MinNotional: cfg.MinNotional,
+		},
+		Loop: cfg.LoopPrices,
+	})
+
+	for symbol, path := range cfg.PricePaths {
+		log.Printf("Mock market %s: %d-step price path starting at %s", symbol, len(path), path[0])
+	}
+
+	priceDriver := engine.NewPriceDriver(exchange, time.Duration(cfg.TickMs)*time.Millisecond)
+	priceDriver.Start()
+
+	handlers := api.NewHandlers(exchange)
+
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+
+	srv := &http.Server{
+		Addr:    ":" + cfg.ServerPort,
+		Handler: router,
+	}
+
+	go func() {
+		log.Printf("Mock Exchange starting on port %s", cfg.ServerPort)
+		log.Printf("Point BINANCE_API_URL at http://localhost:%s, BINANCE_WS_API_URL at ws://localhost:%s/ws-api/v3 and BINANCE_USER_STREAM_URL at ws://localhost:%s/ws",
+			cfg.ServerPort, cfg.ServerPort, cfg.ServerPort)
+
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Server failed:", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+
+	if err := srv.Close(); err != nil {
+		log.Printf("Server close error: %v", err)
+	}
+	priceDriver.Stop()
+
+	fmt.Println("Server stopped")
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/services/mock-exchange/internal/engine"
+	"github.com/shopspring/decimal"
+)
+
+// futuresLeverage remembers the leverage set per symbol; the mock doesn't apply margin
+type futuresLeverage struct {
+	mu      sync.Mutex
+	symbols map[string]int
+}
+
+// registerFuturesRoutes serves the USDT-M futures (fapi) subset used by the bot. Orders
+// trade against the same spot books and wallet, so a long position is simply the base
+// asset balance and USDT stays the margin balance.
+func (h *Handlers) registerFuturesRoutes(r *mux.Router) {
+	r.HandleFunc("/fapi/v1/ping", h.handlePing).Methods("GET")
+	r.HandleFunc("/fapi/v1/time", h.handleTime).Methods("GET")
+	r.HandleFunc("/fapi/v1/exchangeInfo", h.handleExchangeInfo).Methods("GET")
+	r.HandleFunc("/fapi/v1/ticker/bookTicker", h.handleBookTicker).Methods("GET")
+
+	r.HandleFunc("/fapi/v1/order", h.handlePlaceOrder).Methods("POST")
+	r.HandleFunc("/fapi/v1/order", h.handleGetOrder).Methods("GET")
+	r.HandleFunc("/fapi/v1/order", h.handleCancelOrder).Methods("DELETE")
+	r.HandleFunc("/fapi/v1/openOrders", h.handleOpenOrders).Methods("GET")
+	r.HandleFunc("/fapi/v1/allOrders", h.handleAllOrders).Methods("GET")
+	r.HandleFunc("/fapi/v1/userTrades", h.handleMyTrades).Methods("GET")
+
+	r.HandleFunc("/fapi/v1/positionSide/dual", h.handleFuturesPositionMode).Methods("POST")
+	r.HandleFunc("/fapi/v1/leverage", h.handleFuturesLeverage).Methods("POST")
+	r.HandleFunc("/fapi/v2/balance", h.handleFuturesBalance).Methods("GET")
+	r.HandleFunc("/fapi/v2/positionRisk", h.handleFuturesPositions).Methods("GET")
+	r.HandleFunc("/fapi/v1/income", h.handleFuturesIncome).Methods("GET")
+}
+
+func (h *Handlers) handleFuturesPositionMode(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{"code": 200, "msg": "success"})
+}
+
+func (h *Handlers) handleFuturesLeverage(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	symbol := r.Form.Get("symbol")
+	leverage, err := strconv.Atoi(r.Form.Get("leverage"))
+	if err != nil || leverage < 1 || leverage > 125 {
+		writeError(w, &engine.APIError{HTTPStatus: http.StatusBadRequest, Code: -4028, Msg: "Leverage is not valid"})
+		return
+	}
+	if _, ok := h.exchange.BaseAsset(symbol); !ok {
+		writeError(w, &engine.APIError{HTTPStatus: http.StatusBadRequest, Code: -1121, Msg: "Invalid symbol."})
+		return
+	}
+
+	h.leverage.mu.Lock()
+	h.leverage.symbols[symbol] = leverage
+	h.leverage.mu.Unlock()
+
+	writeJSON(w, map[string]interface{}{"symbol": symbol, "leverage": leverage, "maxNotionalValue": "1000000"})
+}
+
+func (h *Handlers) handleFuturesBalance(w http.ResponseWriter, r *http.Request) {
+	balances := make([]map[string]string, 0, 1)
+	for _, bal := range h.exchange.Balances() {
+		if bal.Asset != "USDT" {
+			continue
+		}
+		free, _ := decimal.NewFromString(bal.Free)
+		locked, _ := decimal.NewFromString(bal.Locked)
+		balances = append(balances, map[string]string{
+			"asset":            bal.Asset,
+			"balance":          free.Add(locked).String(),
+			"availableBalance": bal.Free,
+		})
+	}
+	writeJSON(w, balances)
+}
+
+func (h *Handlers) handleFuturesPositions(w http.ResponseWriter, r *http.Request) {
+	held := make(map[string]decimal.Decimal)
+	for _, bal := range h.exchange.Balances() {
+		free, _ := decimal.NewFromString(bal.Free)
+		locked, _ := decimal.NewFromString(bal.Locked)
+		held[bal.Asset] = free.Add(locked)
+	}
+
+	h.leverage.mu.Lock()
+	defer h.leverage.mu.Unlock()
+
+	positions := make([]map[string]string, 0)
+	for _, symbol := range h.exchange.Symbols() {
+		baseAsset, _ := h.exchange.BaseAsset(symbol)
+		amount := held[baseAsset]
+		if !amount.IsPositive() {
+			continue
+		}
+
+		price, _ := h.exchange.Price(symbol)
+		leverage := h.leverage.symbols[symbol]
+		if leverage == 0 {
+			leverage = 1
+		}
+		positions = append(positions, map[string]string{
+			"symbol":           symbol,
+			"positionSide":     "BOTH",
+			"positionAmt":      amount.String(),
+			"entryPrice":       price.String(),
+			"markPrice":        price.String(),
+			"unRealizedProfit": "0",
+			"leverage":         strconv.Itoa(leverage),
+		})
+	}
+	writeJSON(w, positions)
+}
+
+// handleFuturesIncome has no funding to report - the mock never charges funding
+func (h *Handlers) handleFuturesIncome(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, []map[string]interface{}{})
+}
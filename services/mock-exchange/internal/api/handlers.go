@@ -0,0 +1,346 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/services/mock-exchange/internal/engine"
+	"github.com/shopspring/decimal"
+)
+
+// Handlers serve the subset of the Binance spot, margin and USDT-M futures APIs used by the bot.
+// Signatures and API keys are accepted without verification.
+type Handlers struct {
+	exchange    *engine.Exchange
+	leverage    futuresLeverage
+	maintenance atomic.Bool // Reported by /sapi/v1/system/status (POST /mock/maintenance)
+}
+
+func NewHandlers(exchange *engine.Exchange) *Handlers {
+	return &Handlers{exchange: exchange, leverage: futuresLeverage{symbols: make(map[string]int)}}
+}
+
+func (h *Handlers) RegisterRoutes(r *mux.Router) {
+	// Market data
+	r.HandleFunc("/api/v3/ping", h.handlePing).Methods("GET")
+	r.HandleFunc("/api/v3/time", h.handleTime).Methods("GET")
+	r.HandleFunc("/api/v3/exchangeInfo", h.handleExchangeInfo).Methods("GET")
+	r.HandleFunc("/api/v3/ticker/price", h.handleTickerPrice).Methods("GET")
+	r.HandleFunc("/api/v3/ticker/bookTicker", h.handleBookTicker).Methods("GET")
+	r.HandleFunc("/sapi/v1/system/status", h.handleSystemStatus).Methods("GET")
+
+	// Trading
+	r.HandleFunc("/api/v3/order", h.handlePlaceOrder).Methods("POST")
+	r.HandleFunc("/api/v3/order", h.handleGetOrder).Methods("GET")
+	r.HandleFunc("/api/v3/order", h.handleCancelOrder).Methods("DELETE")
+	r.HandleFunc("/api/v3/openOrders", h.handleOpenOrders).Methods("GET")
+	r.HandleFunc("/api/v3/allOrders", h.handleAllOrders).Methods("GET")
+	r.HandleFunc("/api/v3/myTrades", h.handleMyTrades).Methods("GET")
+	r.HandleFunc("/api/v3/account", h.handleAccount).Methods("GET")
+
+	// User-data stream and WebSocket API
+	r.HandleFunc("/api/v3/userDataStream", h.handleCreateListenKey).Methods("POST")
+	r.HandleFunc("/api/v3/userDataStream", h.handleListenKeyNoop).Methods("PUT", "DELETE")
+	r.HandleFunc("/ws/{listen_key}", h.handleUserDataStream).Methods("GET")
+	r.HandleFunc("/ws-api/v3", h.handleWSAPI).Methods("GET")
+
+	h.registerFuturesRoutes(r)
+	h.registerMarginRoutes(r)
+
+	// Test controls
+	r.HandleFunc("/mock/price", h.handleSetPrice).Methods("POST")
+	r.HandleFunc("/mock/maintenance", h.handleSetMaintenance).Methods("POST")
+	r.HandleFunc("/health", h.handleHealth).Methods("GET")
+}
+
+func (h *Handlers) handlePing(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, struct{}{})
+}
+
+func (h *Handlers) handleTime(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]int64{"serverTime": time.Now().UnixMilli()})
+}
+
+func (h *Handlers) handleSystemStatus(w http.ResponseWriter, r *http.Request) {
+	if h.maintenance.Load() {
+		writeJSON(w, map[string]interface{}{"status": 1, "msg": "system maintenance"})
+		return
+	}
+	writeJSON(w, map[string]interface{}{"status": 0, "msg": "normal"})
+}
+
+func (h *Handlers) handleExchangeInfo(w http.ResponseWriter, r *http.Request) {
+	symbols, err := requestedSymbols(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(symbols) == 0 {
+		symbols = h.exchange.Symbols()
+	}
+
+	filters := h.exchange.Filters()
+	infos := make([]map[string]interface{}, 0, len(symbols))
+	for _, symbol := range symbols {
+		baseAsset, ok := h.exchange.BaseAsset(symbol)
+		if !ok {
+			writeError(w, &engine.APIError{HTTPStatus: http.StatusBadRequest, Code: -1121, Msg: "Invalid symbol."})
+			return
+		}
+
+		infos = append(infos, map[string]interface{}{
+			"symbol":     symbol,
+			"status":     "TRADING",
+			"baseAsset":  baseAsset,
+			"quoteAsset": "USDT",
+			"orderTypes": []string{"LIMIT"},
+			"filters": []map[string]string{
+				{"filterType": "PRICE_FILTER", "minPrice": filters.TickSize.String(), "maxPrice": "1000000", "tickSize": filters.TickSize.String()},
+				{"filterType": "LOT_SIZE", "minQty": filters.MinQty.String(), "maxQty": "9000000", "stepSize": filters.StepSize.String()},
+				{"filterType": "NOTIONAL", "minNotional": filters.MinNotional.String()},
+			},
+		})
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"timezone":   "UTC",
+		"serverTime": time.Now().UnixMilli(),
+		"symbols":    infos,
+	})
+}
+
+func (h *Handlers) handleTickerPrice(w http.ResponseWriter, r *http.Request) {
+	symbols, err := requestedSymbols(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	single := r.URL.Query().Get("symbol") != ""
+	if len(symbols) == 0 {
+		symbols = h.exchange.Symbols()
+	}
+
+	prices := make([]map[string]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		price, err := h.exchange.Price(symbol)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		prices = append(prices, map[string]string{"symbol": symbol, "price": price.String()})
+	}
+
+	if single {
+		writeJSON(w, prices[0])
+		return
+	}
+	writeJSON(w, prices)
+}
+
+// handleBookTicker quotes one tick either side of the symbol's price
+func (h *Handlers) handleBookTicker(w http.ResponseWriter, r *http.Request) {
+	book, err := bookTicker(h.exchange, r.URL.Query().Get("symbol"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, book)
+}
+
+func bookTicker(exchange *engine.Exchange, symbol string) (map[string]string, error) {
+	price, err := exchange.Price(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	tick := exchange.Filters().TickSize
+	return map[string]string{
+		"symbol":   symbol,
+		"bidPrice": price.Sub(tick).String(),
+		"bidQty":   "1",
+		"askPrice": price.Add(tick).String(),
+		"askQty":   "1",
+	}, nil
+}
+
+func (h *Handlers) handlePlaceOrder(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	order, err := placeOrder(h.exchange, r.Form.Get)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, order)
+}
+
+func (h *Handlers) handleGetOrder(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	orderID, _ := strconv.ParseInt(q.Get("orderId"), 10, 64)
+
+	order, err := h.exchange.GetOrder(q.Get("symbol"), orderID, q.Get("origClientOrderId"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, order)
+}
+
+func (h *Handlers) handleCancelOrder(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	order, err := cancelOrder(h.exchange, r.Form.Get)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, order)
+}
+
+func (h *Handlers) handleOpenOrders(w http.ResponseWriter, r *http.Request) {
+	orders, err := h.exchange.OpenOrders(r.URL.Query().Get("symbol"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, orders)
+}
+
+func (h *Handlers) handleAllOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	fromOrderID, _ := strconv.ParseInt(q.Get("orderId"), 10, 64)
+
+	limit := 500
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil && v > 0 && v <= 1000 {
+		limit = v
+	}
+
+	orders, err := h.exchange.AllOrders(q.Get("symbol"), fromOrderID, limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, orders)
+}
+
+func (h *Handlers) handleMyTrades(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	orderID, _ := strconv.ParseInt(q.Get("orderId"), 10, 64)
+
+	trades, err := h.exchange.Trades(q.Get("symbol"), orderID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, trades)
+}
+
+func (h *Handlers) handleAccount(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"canTrade":    true,
+		"accountType": "SPOT",
+		"updateTime":  time.Now().UnixMilli(),
+		"balances":    h.exchange.Balances(),
+	})
+}
+
+func (h *Handlers) handleSetPrice(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Symbol string          `json:"symbol"`
+		Price  decimal.Decimal `json:"price"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.Price.IsPositive() {
+		http.Error(w, "Invalid request body - expected {symbol, price}", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.exchange.SetPrice(strings.ToUpper(req.Symbol), req.Price); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	log.Printf("INFO: Price of %s set to %s", req.Symbol, req.Price)
+	writeJSON(w, map[string]string{"symbol": req.Symbol, "price": req.Price.String()})
+}
+
+// handleSetMaintenance announces (or ends) system maintenance; trading endpoints keep working
+func (h *Handlers) handleSetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body - expected {enabled}", http.StatusBadRequest)
+		return
+	}
+
+	h.maintenance.Store(req.Enabled)
+	log.Printf("INFO: System maintenance %v", req.Enabled)
+	writeJSON(w, map[string]bool{"enabled": req.Enabled})
+}
+
+func (h *Handlers) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{"status": "healthy"})
+}
+
+// placeOrder validates Binance order parameters; get reads a REST form or WebSocket API params
+func placeOrder(exchange *engine.Exchange, get func(string) string) (*engine.Order, error) {
+	if t := get("type"); t != "LIMIT" {
+		return nil, &engine.APIError{HTTPStatus: http.StatusBadRequest, Code: -1116, Msg: "Invalid orderType - mock exchange supports LIMIT only."}
+	}
+
+	price, err := decimal.NewFromString(get("price"))
+	if err != nil {
+		return nil, &engine.APIError{HTTPStatus: http.StatusBadRequest, Code: -1100, Msg: "Illegal characters found in parameter 'price'."}
+	}
+	quantity, err := decimal.NewFromString(get("quantity"))
+	if err != nil {
+		return nil, &engine.APIError{HTTPStatus: http.StatusBadRequest, Code: -1100, Msg: "Illegal characters found in parameter 'quantity'."}
+	}
+
+	return exchange.PlaceOrder(get("symbol"), get("side"), price, quantity, get("newClientOrderId"))
+}
+
+func cancelOrder(exchange *engine.Exchange, get func(string) string) (*engine.Order, error) {
+	orderID, _ := strconv.ParseInt(get("orderId"), 10, 64)
+	return exchange.CancelOrder(get("symbol"), orderID, get("origClientOrderId"))
+}
+
+// requestedSymbols reads ?symbol=X or ?symbols=["X","Y"]
+func requestedSymbols(r *http.Request) ([]string, error) {
+	q := r.URL.Query()
+	if symbol := q.Get("symbol"); symbol != "" {
+		return []string{symbol}, nil
+	}
+	if raw := q.Get("symbols"); raw != "" {
+		var symbols []string
+		if err := json.Unmarshal([]byte(raw), &symbols); err != nil {
+			return nil, &engine.APIError{HTTPStatus: http.StatusBadRequest, Code: -1100, Msg: "Illegal characters found in parameter 'symbols'."}
+		}
+		return symbols, nil
+	}
+	return nil, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var apiErr *engine.APIError
+	if !errors.As(err, &apiErr) {
+		apiErr = &engine.APIError{HTTPStatus: http.StatusInternalServerError, Code: -1000, Msg: err.Error()}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.HTTPStatus)
+	json.NewEncoder(w).Encode(apiErr)
+}
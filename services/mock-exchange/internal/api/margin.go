@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/services/mock-exchange/internal/engine"
+)
+
+// registerMarginRoutes serves the margin (sapi) subset used by the bot. Orders trade
+// against the spot books and wallet; the mock never lends, so nothing is ever borrowed
+// and sideEffectType is ignored.
+func (h *Handlers) registerMarginRoutes(r *mux.Router) {
+	r.HandleFunc("/sapi/v1/margin/order", h.handlePlaceOrder).Methods("POST")
+	r.HandleFunc("/sapi/v1/margin/order", h.handleGetOrder).Methods("GET")
+	r.HandleFunc("/sapi/v1/margin/order", h.handleCancelOrder).Methods("DELETE")
+	r.HandleFunc("/sapi/v1/margin/openOrders", h.handleOpenOrders).Methods("GET")
+	r.HandleFunc("/sapi/v1/margin/allOrders", h.handleAllOrders).Methods("GET")
+	r.HandleFunc("/sapi/v1/margin/myTrades", h.handleMyTrades).Methods("GET")
+
+	r.HandleFunc("/sapi/v1/margin/account", h.handleMarginAccount).Methods("GET")
+	r.HandleFunc("/sapi/v1/margin/isolated/account", h.handleIsolatedMarginAccount).Methods("GET")
+	r.HandleFunc("/sapi/v1/margin/interestHistory", h.handleMarginInterest).Methods("GET")
+}
+
+func marginAsset(bal engine.Balance) map[string]string {
+	return map[string]string{
+		"asset":    bal.Asset,
+		"free":     bal.Free,
+		"locked":   bal.Locked,
+		"borrowed": "0",
+		"interest": "0",
+		"netAsset": bal.Free,
+	}
+}
+
+func (h *Handlers) handleMarginAccount(w http.ResponseWriter, r *http.Request) {
+	balances := h.exchange.Balances()
+	assets := make([]map[string]string, 0, len(balances))
+	for _, bal := range balances {
+		assets = append(assets, marginAsset(bal))
+	}
+	writeJSON(w, map[string]interface{}{"tradeEnabled": true, "marginLevel": "999", "userAssets": assets})
+}
+
+// handleIsolatedMarginAccount reports every symbol as an isolated pair holding the whole wallet
+func (h *Handlers) handleIsolatedMarginAccount(w http.ResponseWriter, r *http.Request) {
+	byAsset := make(map[string]engine.Balance)
+	for _, bal := range h.exchange.Balances() {
+		byAsset[bal.Asset] = bal
+	}
+
+	pairs := make([]map[string]interface{}, 0)
+	for _, symbol := range h.exchange.Symbols() {
+		baseAsset, _ := h.exchange.BaseAsset(symbol)
+		base, quote := byAsset[baseAsset], byAsset["USDT"]
+		base.Asset, quote.Asset = baseAsset, "USDT"
+		if base.Free == "" {
+			base.Free, base.Locked = "0", "0"
+		}
+		if quote.Free == "" {
+			quote.Free, quote.Locked = "0", "0"
+		}
+		pairs = append(pairs, map[string]interface{}{
+			"symbol":     symbol,
+			"baseAsset":  marginAsset(base),
+			"quoteAsset": marginAsset(quote),
+		})
+	}
+	writeJSON(w, map[string]interface{}{"assets": pairs})
+}
+
+// handleMarginInterest has no interest to report - the mock never lends
+func (h *Handlers) handleMarginInterest(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{"rows": []interface{}{}, "total": 0})
+}
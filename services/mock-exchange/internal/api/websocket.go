@@ -0,0 +1,125 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/grid-trading-bot/services/mock-exchange/internal/engine"
+)
+
+var upgrader = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+
+func (h *Handlers) handleCreateListenKey(w http.ResponseWriter, r *http.Request) {
+	b := make([]byte, 16)
+	rand.Read(b)
+	writeJSON(w, map[string]string{"listenKey": hex.EncodeToString(b)})
+}
+
+// handleListenKeyNoop accepts keepalives and closes; listen keys never expire here
+func (h *Handlers) handleListenKeyNoop(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, struct{}{})
+}
+
+// handleUserDataStream pushes an executionReport for every order update
+func (h *Handlers) handleUserDataStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("ERROR: User-data stream upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	reports := h.exchange.Subscribe()
+	defer h.exchange.Unsubscribe(reports)
+
+	// Detect client disconnects
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	log.Printf("INFO: User-data stream client connected")
+	for {
+		select {
+		case <-closed:
+			log.Printf("INFO: User-data stream client disconnected")
+			return
+		case report := <-reports:
+			if err := conn.WriteJSON(report); err != nil {
+				return
+			}
+		}
+	}
+}
+
+type wsRequest struct {
+	ID     string                 `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params"`
+}
+
+type wsResponse struct {
+	ID     string      `json:"id"`
+	Status int         `json:"status"`
+	Result interface{} `json:"result,omitempty"`
+	Error  interface{} `json:"error,omitempty"`
+}
+
+// handleWSAPI serves order.place, order.cancel and ticker.book over the WebSocket API
+func (h *Handlers) handleWSAPI(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("ERROR: WebSocket API upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	for {
+		var req wsRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		get := func(key string) string {
+			if v, ok := req.Params[key]; ok {
+				return fmt.Sprint(v)
+			}
+			return ""
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "order.place":
+			result, err = placeOrder(h.exchange, get)
+		case "order.cancel":
+			result, err = cancelOrder(h.exchange, get)
+		case "ticker.book":
+			result, err = bookTicker(h.exchange, get("symbol"))
+		default:
+			err = &engine.APIError{HTTPStatus: http.StatusBadRequest, Code: -1020, Msg: "Unsupported method " + req.Method}
+		}
+
+		resp := wsResponse{ID: req.ID, Status: http.StatusOK, Result: result}
+		if err != nil {
+			var apiErr *engine.APIError
+			if !errors.As(err, &apiErr) {
+				apiErr = &engine.APIError{HTTPStatus: http.StatusInternalServerError, Code: -1000, Msg: err.Error()}
+			}
+			resp = wsResponse{ID: req.ID, Status: apiErr.HTTPStatus, Error: apiErr}
+		}
+
+		if err := conn.WriteJSON(resp); err != nil {
+			return
+		}
+	}
+}
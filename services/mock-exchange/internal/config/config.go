@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+type Config struct {
+	ServerPort     string
+	PricePaths     map[string][]decimal.Decimal
+	TickMs         int
+	LoopPrices     bool
+	Balances       map[string]decimal.Decimal
+	CommissionRate decimal.Decimal
+	TickSize       decimal.Decimal
+	StepSize       decimal.Decimal
+	MinNotional    decimal.Decimal
+}
+
+func LoadConfig() *Config {
+	serverPort := os.Getenv("SERVER_PORT")
+	if serverPort == "" {
+		serverPort = "6060"
+	}
+
+	// SYMBOL:p1,p2,...;SYMBOL:... - one price per tick
+	pricePath := os.Getenv("MOCK_PRICE_PATH")
+	if pricePath == "" {
+		pricePath = "ETHUSDT:3000,2990,2980,2970,2960,2970,2980,2990,3000,3010,3020,3030,3040,3030,3020,3010"
+	}
+	pricePaths, err := parsePricePaths(pricePath)
+	if err != nil {
+		log.Fatalf("Invalid MOCK_PRICE_PATH: %v", err)
+	}
+
+	tickMs := 5000
+	if v := os.Getenv("MOCK_TICK_MS"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			tickMs = parsed
+		}
+	}
+
+	loopPrices := true
+	if v := os.Getenv("MOCK_PRICE_LOOP"); v != "" {
+		loopPrices, _ = strconv.ParseBool(v)
+	}
+
+	// ASSET:amount,ASSET:amount
+	balanceSpec := os.Getenv("MOCK_BALANCES")
+	if balanceSpec == "" {
+		balanceSpec = "USDT:10000"
+	}
+	balances, err := parseBalances(balanceSpec)
+	if err != nil {
+		log.Fatalf("Invalid MOCK_BALANCES: %v", err)
+	}
+
+	return &Config{
+		ServerPort:     serverPort,
+		PricePaths:     pricePaths,
+		TickMs:         tickMs,
+		LoopPrices:     loopPrices,
+		Balances:       balances,
+		CommissionRate: decimalEnv("MOCK_COMMISSION_RATE", "0.001"),
+		TickSize:       decimalEnv("MOCK_TICK_SIZE", "0.01"),
+		StepSize:       decimalEnv("MOCK_STEP_SIZE", "0.0001"),
+		MinNotional:    decimalEnv("MOCK_MIN_NOTIONAL", "5"),
+	}
+}
+
+func parsePricePaths(spec string) (map[string][]decimal.Decimal, error) {
+	paths := make(map[string][]decimal.Decimal)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		symbol, prices, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("missing ':' in %q", entry)
+		}
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if !strings.HasSuffix(symbol, "USDT") {
+			return nil, fmt.Errorf("%s is not a USDT pair", symbol)
+		}
+
+		for _, p := range strings.Split(prices, ",") {
+			price, err := decimal.NewFromString(strings.TrimSpace(p))
+			if err != nil || !price.IsPositive() {
+				return nil, fmt.Errorf("invalid price %q for %s", p, symbol)
+			}
+			paths[symbol] = append(paths[symbol], price)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no symbols")
+	}
+	return paths, nil
+}
+
+func parseBalances(spec string) (map[string]decimal.Decimal, error) {
+	balances := make(map[string]decimal.Decimal)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		asset, amount, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("missing ':' in %q", entry)
+		}
+		value, err := decimal.NewFromString(strings.TrimSpace(amount))
+		if err != nil || value.IsNegative() {
+			return nil, fmt.Errorf("invalid amount %q for %s", amount, asset)
+		}
+		balances[strings.ToUpper(strings.TrimSpace(asset))] = value
+	}
+	return balances, nil
+}
+
+func decimalEnv(name, fallback string) decimal.Decimal {
+	if v := os.Getenv(name); v != "" {
+		if parsed, err := decimal.NewFromString(v); err == nil && parsed.IsPositive() {
+			return parsed
+		}
+		log.Printf("WARNING: Invalid %s=%q, using %s", name, v, fallback)
+	}
+	return decimal.RequireFromString(fallback)
+}
@@ -0,0 +1,548 @@
+package engine
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const quoteAsset = "USDT"
+
+type market struct {
+	symbol    string
+	baseAsset string
+	path      []decimal.Decimal // Scripted prices, one per tick
+	step      int
+	price     decimal.Decimal
+}
+
+type balance struct {
+	free   decimal.Decimal
+	locked decimal.Decimal
+}
+
+// Exchange is an in-memory spot exchange for USDT pairs. LIMIT orders rest
+// until the scripted price crosses them and then fill completely.
+type Exchange struct {
+	mu             sync.Mutex
+	markets        map[string]*market
+	orders         map[string][]*Order // By symbol, in placement order
+	trades         map[string][]*Trade // By symbol
+	balances       map[string]*balance
+	filters        SymbolFilters
+	commissionRate decimal.Decimal
+	loop           bool
+	nextOrderID    int64
+	nextTradeID    int64
+
+	subMu       sync.Mutex
+	subscribers map[chan ExecutionReport]struct{}
+}
+
+// Config seeds the exchange
+type Config struct {
+	PricePaths     map[string][]decimal.Decimal // Symbol → scripted prices (first is the opening price)
+	Balances       map[string]decimal.Decimal   // Asset → starting free balance
+	CommissionRate decimal.Decimal              // Charged in the received asset, like Binance without BNB
+	Filters        SymbolFilters
+	Loop           bool // Restart price paths from the beginning when they end
+}
+
+func NewExchange(cfg Config) *Exchange {
+	ex := &Exchange{
+		markets:        make(map[string]*market),
+		orders:         make(map[string][]*Order),
+		trades:         make(map[string][]*Trade),
+		balances:       make(map[string]*balance),
+		filters:        cfg.Filters,
+		commissionRate: cfg.CommissionRate,
+		loop:           cfg.Loop,
+		nextOrderID:    1,
+		nextTradeID:    1,
+		subscribers:    make(map[chan ExecutionReport]struct{}),
+	}
+
+	for symbol, path := range cfg.PricePaths {
+		ex.markets[symbol] = &market{
+			symbol:    symbol,
+			baseAsset: strings.TrimSuffix(symbol, quoteAsset),
+			path:      path,
+			price:     path[0],
+		}
+	}
+	for asset, amount := range cfg.Balances {
+		ex.balances[asset] = &balance{free: amount}
+	}
+
+	return ex
+}
+
+// Symbols returns the listed symbols, sorted
+func (ex *Exchange) Symbols() []string {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	symbols := make([]string, 0, len(ex.markets))
+	for symbol := range ex.markets {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// BaseAsset returns the base asset of a listed symbol
+func (ex *Exchange) BaseAsset(symbol string) (string, bool) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	m, ok := ex.markets[symbol]
+	if !ok {
+		return "", false
+	}
+	return m.baseAsset, true
+}
+
+// Filters returns the trading rules shared by all symbols
+func (ex *Exchange) Filters() SymbolFilters {
+	return ex.filters
+}
+
+// Price returns the current price of a symbol
+func (ex *Exchange) Price(symbol string) (decimal.Decimal, error) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	m, ok := ex.markets[symbol]
+	if !ok {
+		return decimal.Zero, errInvalidSymbol
+	}
+	return m.price, nil
+}
+
+// SetPrice moves a symbol to an arbitrary price, filling any crossed orders
+func (ex *Exchange) SetPrice(symbol string, price decimal.Decimal) error {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	m, ok := ex.markets[symbol]
+	if !ok {
+		return errInvalidSymbol
+	}
+	m.price = price
+	ex.match(m)
+	return nil
+}
+
+// Tick advances every symbol one step along its price path
+func (ex *Exchange) Tick() {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	for _, m := range ex.markets {
+		next := m.step + 1
+		if next >= len(m.path) {
+			if !ex.loop {
+				continue
+			}
+			next = 0
+		}
+		m.step = next
+		m.price = m.path[next]
+		ex.match(m)
+	}
+}
+
+// PlaceOrder accepts a GTC LIMIT order, filling it at once if it crosses the current price
+func (ex *Exchange) PlaceOrder(symbol, side string, price, quantity decimal.Decimal, clientOrderID string) (*Order, error) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	m, ok := ex.markets[symbol]
+	if !ok {
+		return nil, errInvalidSymbol
+	}
+	if side != "BUY" && side != "SELL" {
+		return nil, badParam("Invalid side.")
+	}
+	if err := ex.checkFilters(price, quantity); err != nil {
+		return nil, err
+	}
+
+	if clientOrderID == "" {
+		clientOrderID = "mock-" + strconv.FormatInt(ex.nextOrderID, 10)
+	} else if ex.findByClientID(symbol, clientOrderID) != nil {
+		return nil, errDuplicateID
+	}
+
+	// Lock what the order can spend
+	lockAsset, lockAmount := quoteAsset, price.Mul(quantity)
+	if side == "SELL" {
+		lockAsset, lockAmount = m.baseAsset, quantity
+	}
+	bal := ex.balance(lockAsset)
+	if bal.free.LessThan(lockAmount) {
+		return nil, errInsufficient
+	}
+	bal.free = bal.free.Sub(lockAmount)
+	bal.locked = bal.locked.Add(lockAmount)
+
+	now := time.Now().UnixMilli()
+	order := &Order{
+		Symbol:              symbol,
+		OrderID:             ex.nextOrderID,
+		ClientOrderID:       clientOrderID,
+		Price:               price.String(),
+		OrigQty:             quantity.String(),
+		ExecutedQty:         "0",
+		CummulativeQuoteQty: "0",
+		Status:              "NEW",
+		TimeInForce:         "GTC",
+		Type:                "LIMIT",
+		Side:                side,
+		Time:                now,
+		UpdateTime:          now,
+		IsWorking:           true,
+		price:               price,
+		quantity:            quantity,
+	}
+	ex.nextOrderID++
+	ex.orders[symbol] = append(ex.orders[symbol], order)
+
+	log.Printf("INFO: Order %d placed - %s %s %s @ %s", order.OrderID, side, quantity, symbol, price)
+	ex.publish(report(order, "NEW", nil, now))
+
+	// Marketable orders take liquidity at the current price
+	if crosses(order, m.price) {
+		ex.fill(m, order, m.price, false)
+	}
+
+	placed := *order
+	placed.TransactTime = now
+	return &placed, nil
+}
+
+// CancelOrder cancels an open order by ID or client order ID
+func (ex *Exchange) CancelOrder(symbol string, orderID int64, clientOrderID string) (*Order, error) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	m, ok := ex.markets[symbol]
+	if !ok {
+		return nil, errInvalidSymbol
+	}
+
+	order := ex.find(symbol, orderID, clientOrderID)
+	if order == nil || order.Status != "NEW" {
+		return nil, errUnknownOrder
+	}
+
+	now := time.Now().UnixMilli()
+	order.Status = "CANCELED"
+	order.IsWorking = false
+	order.UpdateTime = now
+
+	lockAsset, lockAmount := quoteAsset, order.price.Mul(order.quantity)
+	if order.Side == "SELL" {
+		lockAsset, lockAmount = m.baseAsset, order.quantity
+	}
+	bal := ex.balance(lockAsset)
+	bal.locked = bal.locked.Sub(lockAmount)
+	bal.free = bal.free.Add(lockAmount)
+
+	log.Printf("INFO: Order %d cancelled", order.OrderID)
+	ex.publish(report(order, "CANCELED", nil, now))
+
+	cancelled := *order
+	cancelled.TransactTime = now
+	return &cancelled, nil
+}
+
+// GetOrder looks an order up by ID or client order ID
+func (ex *Exchange) GetOrder(symbol string, orderID int64, clientOrderID string) (*Order, error) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	if _, ok := ex.markets[symbol]; !ok {
+		return nil, errInvalidSymbol
+	}
+
+	order := ex.find(symbol, orderID, clientOrderID)
+	if order == nil {
+		return nil, errNoSuchOrder
+	}
+	copied := *order
+	return &copied, nil
+}
+
+// OpenOrders returns NEW orders, for one symbol or all when symbol is empty
+func (ex *Exchange) OpenOrders(symbol string) ([]*Order, error) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	if symbol != "" {
+		if _, ok := ex.markets[symbol]; !ok {
+			return nil, errInvalidSymbol
+		}
+	}
+
+	open := []*Order{}
+	for sym, orders := range ex.orders {
+		if symbol != "" && sym != symbol {
+			continue
+		}
+		for _, order := range orders {
+			if order.Status == "NEW" {
+				copied := *order
+				open = append(open, &copied)
+			}
+		}
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].OrderID < open[j].OrderID })
+	return open, nil
+}
+
+// AllOrders returns up to limit orders with ID >= fromOrderID (0 = the most recent orders)
+func (ex *Exchange) AllOrders(symbol string, fromOrderID int64, limit int) ([]*Order, error) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	if _, ok := ex.markets[symbol]; !ok {
+		return nil, errInvalidSymbol
+	}
+
+	var matched []*Order
+	for _, order := range ex.orders[symbol] {
+		if order.OrderID >= fromOrderID {
+			copied := *order
+			matched = append(matched, &copied)
+		}
+	}
+
+	if len(matched) > limit {
+		if fromOrderID > 0 {
+			matched = matched[:limit]
+		} else {
+			matched = matched[len(matched)-limit:]
+		}
+	}
+	if matched == nil {
+		matched = []*Order{}
+	}
+	return matched, nil
+}
+
+// Trades returns executions for a symbol, optionally only one order's
+func (ex *Exchange) Trades(symbol string, orderID int64) ([]*Trade, error) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	if _, ok := ex.markets[symbol]; !ok {
+		return nil, errInvalidSymbol
+	}
+
+	trades := []*Trade{}
+	for _, trade := range ex.trades[symbol] {
+		if orderID == 0 || trade.OrderID == orderID {
+			copied := *trade
+			trades = append(trades, &copied)
+		}
+	}
+	return trades, nil
+}
+
+// Balances returns every wallet balance, sorted by asset
+func (ex *Exchange) Balances() []Balance {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	balances := make([]Balance, 0, len(ex.balances))
+	for asset, bal := range ex.balances {
+		balances = append(balances, Balance{Asset: asset, Free: bal.free.String(), Locked: bal.locked.String()})
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Asset < balances[j].Asset })
+	return balances
+}
+
+// Subscribe returns a channel receiving every execution report until Unsubscribe
+func (ex *Exchange) Subscribe() chan ExecutionReport {
+	ch := make(chan ExecutionReport, 100)
+	ex.subMu.Lock()
+	ex.subscribers[ch] = struct{}{}
+	ex.subMu.Unlock()
+	return ch
+}
+
+func (ex *Exchange) Unsubscribe(ch chan ExecutionReport) {
+	ex.subMu.Lock()
+	delete(ex.subscribers, ch)
+	ex.subMu.Unlock()
+}
+
+// publish hands a report to subscribers, dropping it for any that fall behind
+func (ex *Exchange) publish(r ExecutionReport) {
+	ex.subMu.Lock()
+	defer ex.subMu.Unlock()
+
+	for ch := range ex.subscribers {
+		select {
+		case ch <- r:
+		default:
+			log.Printf("WARNING: User-data subscriber lagging, dropped report for order %d", r.OrderID)
+		}
+	}
+}
+
+// match fills every resting order the current price crosses. Caller holds ex.mu.
+func (ex *Exchange) match(m *market) {
+	for _, order := range ex.orders[m.symbol] {
+		if order.Status == "NEW" && crosses(order, m.price) {
+			ex.fill(m, order, order.price, true)
+		}
+	}
+}
+
+// fill executes an order completely at price and settles balances. Caller holds ex.mu.
+func (ex *Exchange) fill(m *market, order *Order, price decimal.Decimal, isMaker bool) {
+	now := time.Now().UnixMilli()
+	quote := price.Mul(order.quantity)
+
+	// Commission is charged in the asset received
+	var commission decimal.Decimal
+	commissionAsset := quoteAsset
+	if order.Side == "BUY" {
+		commissionAsset = m.baseAsset
+		commission = order.quantity.Mul(ex.commissionRate)
+
+		usdt := ex.balance(quoteAsset)
+		reserved := order.price.Mul(order.quantity)
+		usdt.locked = usdt.locked.Sub(reserved)
+		usdt.free = usdt.free.Add(reserved.Sub(quote)) // Refund price improvement
+		base := ex.balance(m.baseAsset)
+		base.free = base.free.Add(order.quantity.Sub(commission))
+	} else {
+		commission = quote.Mul(ex.commissionRate)
+
+		base := ex.balance(m.baseAsset)
+		base.locked = base.locked.Sub(order.quantity)
+		usdt := ex.balance(quoteAsset)
+		usdt.free = usdt.free.Add(quote.Sub(commission))
+	}
+
+	order.Status = "FILLED"
+	order.IsWorking = false
+	order.ExecutedQty = order.quantity.String()
+	order.CummulativeQuoteQty = quote.String()
+	order.UpdateTime = now
+
+	trade := &Trade{
+		Symbol:          order.Symbol,
+		ID:              ex.nextTradeID,
+		OrderID:         order.OrderID,
+		Price:           price.String(),
+		Qty:             order.quantity.String(),
+		QuoteQty:        quote.String(),
+		Commission:      commission.String(),
+		CommissionAsset: commissionAsset,
+		Time:            now,
+		IsBuyer:         order.Side == "BUY",
+		IsMaker:         isMaker,
+	}
+	ex.nextTradeID++
+	ex.trades[order.Symbol] = append(ex.trades[order.Symbol], trade)
+
+	log.Printf("INFO: Order %d filled - %s %s %s @ %s", order.OrderID, order.Side, order.quantity, order.Symbol, price)
+	ex.publish(report(order, "TRADE", trade, now))
+}
+
+func (ex *Exchange) balance(asset string) *balance {
+	bal, ok := ex.balances[asset]
+	if !ok {
+		bal = &balance{}
+		ex.balances[asset] = bal
+	}
+	return bal
+}
+
+func (ex *Exchange) checkFilters(price, quantity decimal.Decimal) error {
+	if !price.IsPositive() || !price.Mod(ex.filters.TickSize).IsZero() {
+		return filterFailure("PRICE_FILTER")
+	}
+	if quantity.LessThan(ex.filters.MinQty) || !quantity.Mod(ex.filters.StepSize).IsZero() {
+		return filterFailure("LOT_SIZE")
+	}
+	if price.Mul(quantity).LessThan(ex.filters.MinNotional) {
+		return filterFailure("NOTIONAL")
+	}
+	return nil
+}
+
+func (ex *Exchange) find(symbol string, orderID int64, clientOrderID string) *Order {
+	if orderID == 0 {
+		return ex.findByClientID(symbol, clientOrderID)
+	}
+	for _, order := range ex.orders[symbol] {
+		if order.OrderID == orderID {
+			return order
+		}
+	}
+	return nil
+}
+
+func (ex *Exchange) findByClientID(symbol, clientOrderID string) *Order {
+	for _, order := range ex.orders[symbol] {
+		if order.ClientOrderID == clientOrderID {
+			return order
+		}
+	}
+	return nil
+}
+
+// crosses reports whether a resting order would trade at price
+func crosses(order *Order, price decimal.Decimal) bool {
+	if order.Side == "BUY" {
+		return price.LessThanOrEqual(order.price)
+	}
+	return price.GreaterThanOrEqual(order.price)
+}
+
+func report(order *Order, executionType string, trade *Trade, now int64) ExecutionReport {
+	r := ExecutionReport{
+		EventType:       "executionReport",
+		EventTime:       now,
+		Symbol:          order.Symbol,
+		ClientOrderID:   order.ClientOrderID,
+		Side:            order.Side,
+		OrderType:       order.Type,
+		TimeInForce:     order.TimeInForce,
+		Quantity:        order.OrigQty,
+		Price:           order.Price,
+		ExecutionType:   executionType,
+		OrderStatus:     order.Status,
+		OrderID:         order.OrderID,
+		LastQty:         "0",
+		CumulativeQty:   order.ExecutedQty,
+		LastPrice:       "0",
+		Commission:      "0",
+		TradeTime:       now,
+		TradeID:         -1,
+		OrderCreatedAt:  order.Time,
+		CumulativeQuote: order.CummulativeQuoteQty,
+		LastQuoteQty:    "0",
+	}
+
+	if trade != nil {
+		r.LastQty = trade.Qty
+		r.LastPrice = trade.Price
+		r.LastQuoteQty = trade.QuoteQty
+		r.Commission = trade.Commission
+		r.CommissionAsset = &trade.CommissionAsset
+		r.TradeID = trade.ID
+		r.IsMaker = trade.IsMaker
+	}
+	return r
+}
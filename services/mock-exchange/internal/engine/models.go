@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// APIError is a Binance-style error ({"code": -2010, "msg": "..."})
+type APIError struct {
+	HTTPStatus int    `json:"-"`
+	Code       int    `json:"code"`
+	Msg        string `json:"msg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Msg)
+}
+
+var (
+	errInvalidSymbol = &APIError{HTTPStatus: 400, Code: -1121, Msg: "Invalid symbol."}
+	errNoSuchOrder   = &APIError{HTTPStatus: 400, Code: -2013, Msg: "Order does not exist."}
+	errUnknownOrder  = &APIError{HTTPStatus: 400, Code: -2011, Msg: "Unknown order sent."}
+	errInsufficient  = &APIError{HTTPStatus: 400, Code: -2010, Msg: "Account has insufficient balance for requested action."}
+	errDuplicateID   = &APIError{HTTPStatus: 400, Code: -2010, Msg: "Duplicate order sent."}
+)
+
+func filterFailure(filter string) *APIError {
+	return &APIError{HTTPStatus: 400, Code: -1013, Msg: "Filter failure: " + filter}
+}
+
+func badParam(msg string) *APIError {
+	return &APIError{HTTPStatus: 400, Code: -1102, Msg: msg}
+}
+
+// Order is a LIMIT order in Binance's REST representation
+type Order struct {
+	Symbol              string `json:"symbol"`
+	OrderID             int64  `json:"orderId"`
+	ClientOrderID       string `json:"clientOrderId"`
+	Price               string `json:"price"`
+	OrigQty             string `json:"origQty"`
+	ExecutedQty         string `json:"executedQty"`
+	CummulativeQuoteQty string `json:"cummulativeQuoteQty"`
+	Status              string `json:"status"` // NEW, FILLED, CANCELED
+	TimeInForce         string `json:"timeInForce"`
+	Type                string `json:"type"`
+	Side                string `json:"side"` // BUY, SELL
+	Time                int64  `json:"time"`
+	UpdateTime          int64  `json:"updateTime"`
+	IsWorking           bool   `json:"isWorking"`
+	TransactTime        int64  `json:"transactTime,omitempty"` // Set on placement and cancel responses
+
+	price    decimal.Decimal
+	quantity decimal.Decimal
+}
+
+// Trade is one execution in GET /api/v3/myTrades format
+type Trade struct {
+	Symbol          string `json:"symbol"`
+	ID              int64  `json:"id"`
+	OrderID         int64  `json:"orderId"`
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	QuoteQty        string `json:"quoteQty"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            int64  `json:"time"`
+	IsBuyer         bool   `json:"isBuyer"`
+	IsMaker         bool   `json:"isMaker"`
+}
+
+// ExecutionReport is the user-data stream event sent for every order update
+type ExecutionReport struct {
+	EventType       string  `json:"e"`
+	EventTime       int64   `json:"E"`
+	Symbol          string  `json:"s"`
+	ClientOrderID   string  `json:"c"`
+	Side            string  `json:"S"`
+	OrderType       string  `json:"o"`
+	TimeInForce     string  `json:"f"`
+	Quantity        string  `json:"q"`
+	Price           string  `json:"p"`
+	ExecutionType   string  `json:"x"` // NEW, TRADE, CANCELED
+	OrderStatus     string  `json:"X"`
+	OrderID         int64   `json:"i"`
+	LastQty         string  `json:"l"`
+	CumulativeQty   string  `json:"z"`
+	LastPrice       string  `json:"L"`
+	Commission      string  `json:"n"`
+	CommissionAsset *string `json:"N"`
+	TradeTime       int64   `json:"T"`
+	TradeID         int64   `json:"t"`
+	IsMaker         bool    `json:"m"`
+	OrderCreatedAt  int64   `json:"O"`
+	CumulativeQuote string  `json:"Z"`
+	LastQuoteQty    string  `json:"Y"`
+}
+
+// Balance is a spot wallet balance
+type Balance struct {
+	Asset  string `json:"asset"`
+	Free   string `json:"free"`
+	Locked string `json:"locked"`
+}
+
+// SymbolFilters are the trading rules published in exchangeInfo
+type SymbolFilters struct {
+	TickSize    decimal.Decimal
+	StepSize    decimal.Decimal
+	MinQty      decimal.Decimal
+	MinNotional decimal.Decimal
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// PriceDriver advances the scripted price paths at a fixed interval
+type PriceDriver struct {
+	exchange *Exchange
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewPriceDriver(exchange *Exchange, interval time.Duration) *PriceDriver {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &PriceDriver{
+		exchange: exchange,
+		interval: interval,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+func (d *PriceDriver) Start() {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		log.Printf("INFO: Price driver started, stepping every %s", d.interval)
+		for {
+			select {
+			case <-d.ctx.Done():
+				return
+			case <-ticker.C:
+				d.exchange.Tick()
+			}
+		}
+	}()
+}
+
+func (d *PriceDriver) Stop() {
+	d.cancel()
+	d.wg.Wait()
+	log.Println("INFO: Price driver stopped")
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/pkg/natsjs"
+	"github.com/grid-trading-bot/services/notifier/internal/api"
+	"github.com/grid-trading-bot/services/notifier/internal/channels"
+	"github.com/grid-trading-bot/services/notifier/internal/config"
+	"github.com/grid-trading-bot/services/notifier/internal/database"
+	"github.com/grid-trading-bot/services/notifier/internal/repository"
+	"github.com/grid-trading-bot/services/notifier/internal/service"
+	"github.com/grid-trading-bot/services/notifier/internal/templates"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("No .env file found, using params from environment only.")
+	}
+	logging.Setup("notifier")
+
+	cfg := config.LoadConfig()
+
+	db, err := database.NewConnection(database.Config{Path: cfg.DBPath})
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	migrations := []string{
+		"services/notifier/migrations/001_create_deliveries.sql",
+	}
+
+	for _, migrationFile := range migrations {
+		migrationSQL, err := os.ReadFile(migrationFile)
+		if err != nil {
+			log.Fatalf("Failed to read migration file %s: %v", migrationFile, err)
+		}
+
+		if err := database.RunMigrations(db, string(migrationSQL)); err != nil {
+			log.Fatalf("Failed to run migration %s: %v", migrationFile, err)
+		}
+	}
+
+	renderer, err := templates.New(cfg.TemplatesDir)
+	if err != nil {
+		log.Fatal("Failed to load templates:", err)
+	}
+
+	chans := buildChannels(cfg)
+	if len(chans) == 0 {
+		log.Println("WARNING: No channels configured - events are accepted but not sent anywhere")
+	}
+	for _, ch := range chans {
+		log.Printf("Channel enabled: %s", ch.Name())
+	}
+
+	dispatcher := service.NewDispatcher(repository.NewDeliveryRepository(db), chans, renderer, service.DispatcherConfig{
+		EventTypes:    cfg.EventTypes,
+		MaxAttempts:   cfg.MaxAttempts,
+		RetryInterval: time.Duration(cfg.RetryInterval) * time.Second,
+	})
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	handlers := api.NewHandlers(dispatcher)
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+
+	// POST /events stays available either way, e.g. for other services or manual tests
+	if cfg.Transport == "nats" {
+		conn, err := natsjs.Connect(cfg.NATSURL, "notifier")
+		if err != nil {
+			log.Fatal("Failed to connect to NATS:", err)
+		}
+		defer conn.Close()
+
+		consumer := api.NewQueueConsumer(dispatcher, natsjs.New(conn))
+		if err := consumer.Start(); err != nil {
+			log.Fatal("Failed to start event consumer:", err)
+		}
+		defer consumer.Stop()
+		log.Printf("Consuming events from NATS JetStream at %s", cfg.NATSURL)
+	}
+
+	srv := &http.Server{
+		Addr:    ":" + cfg.ServerPort,
+		Handler: router,
+	}
+
+	go func() {
+		log.Printf("Notifier starting on port %s", cfg.ServerPort)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Server failed:", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+	fmt.Println("Server stopped")
+}
+
+// buildChannels enables every channel whose settings are present
+func buildChannels(cfg *config.Config) []channels.Channel {
+	var chans []channels.Channel
+
+	if cfg.TelegramBotToken != "" && cfg.TelegramChatID != "" {
+		chans = append(chans, channels.NewTelegram(cfg.TelegramAPIURL, cfg.TelegramBotToken, cfg.TelegramChatID))
+	}
+
+	if cfg.DiscordWebhookURL != "" {
+		chans = append(chans, channels.NewDiscord(cfg.DiscordWebhookURL))
+	}
+
+	if cfg.SMTPHost != "" {
+		if cfg.EmailFrom == "" || len(cfg.EmailTo) == 0 {
+			log.Fatal("SMTP_HOST is set but EMAIL_FROM or EMAIL_TO is missing")
+		}
+		chans = append(chans, channels.NewEmail(channels.EmailConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.EmailFrom,
+			To:       cfg.EmailTo,
+		}))
+	}
+
+	// Named by position in NOTIFY_WEBHOOK_URLS - reordering the list mixes up per-URL delivery history
+	for i, url := range cfg.WebhookURLs {
+		chans = append(chans, channels.NewWebhook("webhook-"+strconv.Itoa(i+1), url))
+	}
+
+	return chans
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/notifier/internal/models"
+	"github.com/grid-trading-bot/services/notifier/internal/service"
+)
+
+type Handlers struct {
+	dispatcher *service.Dispatcher
+}
+
+func NewHandlers(dispatcher *service.Dispatcher) *Handlers {
+	return &Handlers{dispatcher: dispatcher}
+}
+
+func (h *Handlers) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/health", h.handleHealth).Methods("GET")
+	r.HandleFunc("/events", h.handleEvent).Methods("POST")
+	r.HandleFunc("/channels", h.handleChannels).Methods("GET")
+	r.HandleFunc("/deliveries", h.handleGetDeliveries).Methods("GET")
+	r.HandleFunc("/deliveries/{id}/retry", h.handleRetryDelivery).Methods("POST")
+}
+
+func (h *Handlers) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":   "healthy",
+		"channels": h.dispatcher.Channels(),
+	})
+}
+
+// handleEvent queues an event for every channel
+func (h *Handlers) handleEvent(w http.ResponseWriter, r *http.Request) {
+	var event contracts.Event
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		log.Printf("ERROR: Invalid event request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateEvent(&event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	queued, err := h.dispatcher.Dispatch(event)
+	if err != nil {
+		log.Printf("ERROR: Failed to queue %s event %s: %v", event.Type, event.ID, err)
+		http.Error(w, "Failed to queue event", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]int{"deliveries": queued})
+}
+
+// handleChannels reports delivery counts and the latest error per channel
+func (h *Handlers) handleChannels(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.dispatcher.GetChannelStatus()
+	if err != nil {
+		log.Printf("ERROR: Failed to get channel status: %v", err)
+		http.Error(w, "Failed to get channel status", http.StatusInternalServerError)
+		return
+	}
+
+	if stats == nil {
+		stats = []*models.ChannelStatus{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"channels": stats})
+}
+
+// handleGetDeliveries lists the latest deliveries (?status=PENDING|SENT|FAILED&channel=&limit=)
+func (h *Handlers) handleGetDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	status := models.DeliveryStatus(query.Get("status"))
+	if status != "" && status != models.DeliveryPending && status != models.DeliverySent && status != models.DeliveryFailed {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	limit := 100
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.dispatcher.GetDeliveries(status, query.Get("channel"), limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch deliveries: %v", err)
+		http.Error(w, "Failed to fetch deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []*models.Delivery{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"deliveries": deliveries})
+}
+
+// handleRetryDelivery moves a failed delivery back to pending
+func (h *Handlers) handleRetryDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid delivery ID", http.StatusBadRequest)
+		return
+	}
+
+	requeued, err := h.dispatcher.Retry(id)
+	if err != nil {
+		log.Printf("ERROR: Failed to requeue delivery %d: %v", id, err)
+		http.Error(w, "Failed to requeue delivery", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !requeued {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed delivery not found"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"status": "requeued"})
+}
+
+// validateEvent checks the required fields, defaulting the time of events sent without one
+func validateEvent(event *contracts.Event) error {
+	if event.ID == "" {
+		return errors.New("id is required")
+	}
+	if event.Type == "" {
+		return errors.New("type is required")
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/natsjs"
+	"github.com/grid-trading-bot/services/notifier/internal/service"
+)
+
+const (
+	queueFetchWait  = 5 * time.Second
+	queueAckWait    = 60 * time.Second
+	queueRetryDelay = 5 * time.Second
+	queueDurable    = "notifier"
+)
+
+// QueueConsumer reads events from the GRID_EVENTS stream (TRANSPORT=nats). An event
+// is acked once its deliveries are stored; sending them is up to the dispatcher.
+type QueueConsumer struct {
+	dispatcher *service.Dispatcher
+	js         *natsjs.JetStream
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewQueueConsumer(dispatcher *service.Dispatcher, js *natsjs.JetStream) *QueueConsumer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &QueueConsumer{
+		dispatcher: dispatcher,
+		js:         js,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start creates the stream and durable consumer if needed and begins consuming
+func (q *QueueConsumer) Start() error {
+	if err := q.js.EnsureStream(contracts.EventStream); err != nil {
+		return err
+	}
+
+	consumer, err := q.js.EnsureConsumer(contracts.EventStream.Name, natsjs.ConsumerConfig{
+		Durable:       queueDurable,
+		DeliverPolicy: "all",
+		AckPolicy:     "explicit",
+		ReplayPolicy:  "instant",
+		AckWait:       queueAckWait,
+	})
+	if err != nil {
+		return err
+	}
+
+	q.wg.Add(1)
+	go q.consume(consumer)
+	return nil
+}
+
+func (q *QueueConsumer) Stop() {
+	q.cancel()
+	q.wg.Wait()
+}
+
+func (q *QueueConsumer) consume(consumer *natsjs.Consumer) {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		default:
+		}
+
+		msgs, err := consumer.Fetch(1, queueFetchWait)
+		if err != nil {
+			log.Printf("ERROR: Failed to fetch events: %v", err)
+			select {
+			case <-q.ctx.Done():
+				return
+			case <-time.After(queueRetryDelay):
+			}
+		}
+
+		for _, msg := range msgs {
+			q.handle(msg)
+		}
+	}
+}
+
+func (q *QueueConsumer) handle(msg *natsjs.Msg) {
+	var err error
+	var event contracts.Event
+	if decodeErr := json.Unmarshal(msg.Data, &event); decodeErr != nil || validateEvent(&event) != nil {
+		// Retrying can't fix a malformed event
+		log.Printf("ERROR: Dropping malformed event on %s: %s", msg.Subject, msg.Data)
+		err = msg.Ack()
+	} else if _, dispatchErr := q.dispatcher.Dispatch(event); dispatchErr != nil {
+		log.Printf("WARNING: Failed to queue %s event %s, redelivering in %s: %v", event.Type, event.ID, queueRetryDelay, dispatchErr)
+		err = msg.Nak(queueRetryDelay)
+	} else {
+		err = msg.Ack()
+	}
+
+	if err != nil {
+		// Unsettled messages are redelivered once the ack wait expires
+		log.Printf("ERROR: Failed to settle event on %s: %v", msg.Subject, err)
+	}
+}
@@ -0,0 +1,75 @@
+// Package channels delivers rendered notifications to Telegram, Discord, email and
+// plain webhooks using only their HTTP/SMTP APIs - no third-party SDKs.
+package channels
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Message is an event rendered for delivery. Payload is the original event JSON.
+type Message struct {
+	Title   string
+	Body    string
+	Payload json.RawMessage
+}
+
+// Channel sends messages to one destination
+type Channel interface {
+	Name() string
+	Send(msg Message) error
+}
+
+// PermanentError marks a failure retrying can't fix (e.g. a rejected chat ID or webhook URL)
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// IsPermanent reports whether err should not be retried
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// postJSON posts body and classifies the response: 4xx (except 408 and 429) is permanent
+func postJSON(endpoint string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("failed to marshal request: %w", err)}
+	}
+
+	resp, err := httpClient.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		// Webhook and bot URLs embed credentials - keep them out of logs and delivery records
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("request failed: %w", urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return &PermanentError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,26 @@
+package channels
+
+// Discord posts to a channel webhook
+type Discord struct {
+	webhookURL string
+}
+
+func NewDiscord(webhookURL string) *Discord {
+	return &Discord{webhookURL: webhookURL}
+}
+
+func (d *Discord) Name() string { return "discord" }
+
+func (d *Discord) Send(msg Message) error {
+	content := joinTitle(msg)
+	if msg.Title != "" {
+		content = "**" + msg.Title + "**\n" + msg.Body
+	}
+
+	// Discord rejects messages over 2000 characters
+	if runes := []rune(content); len(runes) > 2000 {
+		content = string(runes[:1997]) + "..."
+	}
+
+	return postJSON(d.webhookURL, map[string]string{"content": content})
+}
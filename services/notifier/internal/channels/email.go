@@ -0,0 +1,106 @@
+package channels
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// EmailConfig describes the SMTP relay and recipients
+type EmailConfig struct {
+	Host     string
+	Port     string // 465 = implicit TLS, anything else upgrades with STARTTLS when offered
+	Username string // Empty = no authentication
+	Password string
+	From     string
+	To       []string
+}
+
+// Email sends plain-text mail over SMTP
+type Email struct {
+	cfg EmailConfig
+}
+
+func NewEmail(cfg EmailConfig) *Email {
+	return &Email{cfg: cfg}
+}
+
+func (e *Email) Name() string { return "email" }
+
+func (e *Email) Send(msg Message) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+
+	err := e.send([]byte(b.String()))
+
+	// 5xx replies (unknown recipient, relay denied, bad credentials) won't succeed on retry
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return &PermanentError{Err: err}
+	}
+	return err
+}
+
+func (e *Email) send(message []byte) error {
+	addr := net.JoinHostPort(e.cfg.Host, e.cfg.Port)
+
+	var auth smtp.Auth
+	if e.cfg.Username != "" {
+		auth = smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+	}
+
+	if e.cfg.Port != "465" {
+		return smtp.SendMail(addr, auth, e.cfg.From, e.cfg.To, message)
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", addr, &tls.Config{ServerName: e.cfg.Host})
+	if err != nil {
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(e.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range e.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,35 @@
+package channels
+
+import "strings"
+
+// Telegram sends messages through a bot (Bot API sendMessage)
+type Telegram struct {
+	apiURL string
+	token  string
+	chatID string
+}
+
+func NewTelegram(apiURL, token, chatID string) *Telegram {
+	return &Telegram{apiURL: strings.TrimRight(apiURL, "/"), token: token, chatID: chatID}
+}
+
+func (t *Telegram) Name() string { return "telegram" }
+
+func (t *Telegram) Send(msg Message) error {
+	return postJSON(t.apiURL+"/bot"+t.token+"/sendMessage", map[string]interface{}{
+		"chat_id":                  t.chatID,
+		"text":                     joinTitle(msg),
+		"disable_web_page_preview": true,
+	})
+}
+
+// joinTitle puts the title on its own line above the body, for channels without a subject
+func joinTitle(msg Message) string {
+	if msg.Title == "" {
+		return msg.Body
+	}
+	if msg.Body == "" {
+		return msg.Title
+	}
+	return msg.Title + "\n" + msg.Body
+}
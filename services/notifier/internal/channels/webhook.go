@@ -0,0 +1,21 @@
+package channels
+
+// Webhook posts the rendered message together with the original event
+type Webhook struct {
+	name string
+	url  string
+}
+
+func NewWebhook(name, url string) *Webhook {
+	return &Webhook{name: name, url: url}
+}
+
+func (w *Webhook) Name() string { return w.name }
+
+func (w *Webhook) Send(msg Message) error {
+	return postJSON(w.url, map[string]interface{}{
+		"title": msg.Title,
+		"text":  msg.Body,
+		"event": msg.Payload,
+	})
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type Config struct {
+	ServerPort string
+	DBPath     string
+
+	Transport string // Events via http (POST /events only) or nats (also consumes GRID_EVENTS)
+	NATSURL   string
+
+	TelegramAPIURL   string
+	TelegramBotToken string
+	TelegramChatID   string
+
+	DiscordWebhookURL string
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	EmailFrom    string
+	EmailTo      []string
+
+	WebhookURLs []string
+
+	EventTypes    []string // Only send these event types (empty = all)
+	TemplatesDir  string   // <event_type>.tmpl overrides (empty = built-in templates)
+	MaxAttempts   int
+	RetryInterval int // Seconds before the first retry, doubled after every failure
+}
+
+func LoadConfig() *Config {
+	serverPort := os.Getenv("SERVER_PORT")
+	if serverPort == "" {
+		serverPort = "5050"
+	}
+
+	dbPath := os.Getenv("DB_PATH")
+	if dbPath == "" {
+		dbPath = "./notifier.db"
+	}
+
+	transport := os.Getenv("TRANSPORT")
+	if transport == "" {
+		transport = "http"
+	}
+	if transport != "http" && transport != "nats" {
+		log.Fatal("TRANSPORT must be http or nats")
+	}
+
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://localhost:4222"
+	}
+
+	telegramAPIURL := os.Getenv("TELEGRAM_API_URL")
+	if telegramAPIURL == "" {
+		telegramAPIURL = "https://api.telegram.org"
+	}
+
+	smtpPort := os.Getenv("SMTP_PORT")
+	if smtpPort == "" {
+		smtpPort = "587"
+	}
+
+	maxAttempts := 8
+	if v := os.Getenv("NOTIFIER_MAX_ATTEMPTS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			log.Fatal("NOTIFIER_MAX_ATTEMPTS must be a positive integer")
+		}
+		maxAttempts = parsed
+	}
+
+	retryInterval := 15
+	if v := os.Getenv("NOTIFIER_RETRY_INTERVAL_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			log.Fatal("NOTIFIER_RETRY_INTERVAL_SEC must be a positive integer")
+		}
+		retryInterval = parsed
+	}
+
+	return &Config{
+		ServerPort: serverPort,
+		DBPath:     dbPath,
+
+		Transport: transport,
+		NATSURL:   natsURL,
+
+		TelegramAPIURL:   telegramAPIURL,
+		TelegramBotToken: os.Getenv("TELEGRAM_BOT_TOKEN"),
+		TelegramChatID:   os.Getenv("TELEGRAM_CHAT_ID"),
+
+		DiscordWebhookURL: os.Getenv("DISCORD_WEBHOOK_URL"),
+
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     smtpPort,
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		EmailFrom:    os.Getenv("EMAIL_FROM"),
+		EmailTo:      splitList(os.Getenv("EMAIL_TO")),
+
+		WebhookURLs: splitList(os.Getenv("NOTIFY_WEBHOOK_URLS")),
+
+		EventTypes:    splitList(os.Getenv("NOTIFY_EVENTS")),
+		TemplatesDir:  os.Getenv("NOTIFIER_TEMPLATES_DIR"),
+		MaxAttempts:   maxAttempts,
+		RetryInterval: retryInterval,
+	}
+}
+
+// splitList parses a comma-separated list, dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
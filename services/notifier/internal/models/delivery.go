@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "PENDING"
+	DeliverySent    DeliveryStatus = "SENT"
+	DeliveryFailed  DeliveryStatus = "FAILED" // Gave up - retried too often or rejected by the channel
+)
+
+// Delivery is one event rendered for one channel
+type Delivery struct {
+	ID            int            `json:"id"`
+	EventID       string         `json:"event_id"`
+	EventType     string         `json:"event_type"`
+	Channel       string         `json:"channel"`
+	Title         string         `json:"title"`
+	Body          string         `json:"body"`
+	Payload       string         `json:"-"`
+	Status        DeliveryStatus `json:"status"`
+	Attempts      int            `json:"attempts"`
+	LastError     string         `json:"last_error,omitempty"`
+	NextAttemptAt time.Time      `json:"next_attempt_at"`
+	SentAt        *time.Time     `json:"sent_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// ChannelStatus summarizes deliveries on one channel
+type ChannelStatus struct {
+	Channel     string     `json:"channel"`
+	Pending     int        `json:"pending"`
+	Sent        int        `json:"sent"`
+	Failed      int        `json:"failed"`
+	LastSentAt  *time.Time `json:"last_sent_at,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/grid-trading-bot/services/notifier/internal/models"
+)
+
+const sqliteTimeFormat = "2006-01-02 15:04:05"
+
+type DeliveryRepository struct {
+	db *sql.DB
+}
+
+func NewDeliveryRepository(db *sql.DB) *DeliveryRepository {
+	return &DeliveryRepository{db: db}
+}
+
+const deliveryColumns = `id, event_id, event_type, channel, title, body, payload, status, attempts,
+	last_error, next_attempt_at, sent_at, created_at, updated_at`
+
+func (r *DeliveryRepository) scanDelivery(scanner interface{ Scan(...interface{}) error }) (*models.Delivery, error) {
+	d := &models.Delivery{}
+	var lastError, sentAt sql.NullString
+	var nextAttemptAt, createdAt, updatedAt string
+	err := scanner.Scan(
+		&d.ID, &d.EventID, &d.EventType, &d.Channel, &d.Title, &d.Body, &d.Payload, &d.Status, &d.Attempts,
+		&lastError, &nextAttemptAt, &sentAt, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	d.LastError = lastError.String
+	d.SentAt = parseNullTime(sentAt)
+
+	// Parse timestamps from TEXT format
+	d.NextAttemptAt, _ = time.Parse(sqliteTimeFormat, nextAttemptAt)
+	d.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	d.UpdatedAt, _ = time.Parse(sqliteTimeFormat, updatedAt)
+
+	return d, nil
+}
+
+func parseNullTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, err := time.Parse(sqliteTimeFormat, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func (r *DeliveryRepository) queryDeliveries(query string, args ...interface{}) ([]*models.Delivery, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.Delivery
+	for rows.Next() {
+		d, err := r.scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// Create stores a pending delivery. Returns false if the event was already
+// recorded for this channel (a redelivered event).
+func (r *DeliveryRepository) Create(d *models.Delivery) (bool, error) {
+	query := `
+		INSERT OR IGNORE INTO deliveries (event_id, event_type, channel, title, body, payload)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	result, err := r.db.Exec(query, d.EventID, d.EventType, d.Channel, d.Title, d.Body, d.Payload)
+	if err != nil {
+		return false, fmt.Errorf("failed to create delivery: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetDue returns pending deliveries whose next attempt time has passed, oldest first
+func (r *DeliveryRepository) GetDue(limit int) ([]*models.Delivery, error) {
+	query := `SELECT ` + deliveryColumns + `
+		FROM deliveries
+		WHERE status = $1 AND next_attempt_at <= datetime('now')
+		ORDER BY id ASC
+		LIMIT $2
+	`
+
+	return r.queryDeliveries(query, models.DeliveryPending, limit)
+}
+
+// List returns the latest deliveries, optionally filtered by status and channel
+func (r *DeliveryRepository) List(status models.DeliveryStatus, channel string, limit int) ([]*models.Delivery, error) {
+	query := `SELECT ` + deliveryColumns + `
+		FROM deliveries
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR channel = $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`
+
+	return r.queryDeliveries(query, status, channel, limit)
+}
+
+func (r *DeliveryRepository) MarkSent(id int) error {
+	query := `
+		UPDATE deliveries
+		SET status = $1, attempts = attempts + 1, sent_at = datetime('now'), updated_at = datetime('now')
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(query, models.DeliverySent, id)
+	return err
+}
+
+// MarkFailed records a failed attempt, scheduling the next one or giving up once
+// maxAttempts is reached (or straight away when the failure is permanent)
+func (r *DeliveryRepository) MarkFailed(id int, lastError string, nextAttemptIn time.Duration, maxAttempts int, permanent bool) error {
+	query := `
+		UPDATE deliveries
+		SET attempts = attempts + 1,
+		    last_error = $1,
+		    status = CASE WHEN $2 OR attempts + 1 >= $3 THEN $4 ELSE status END,
+		    next_attempt_at = datetime('now', $5),
+		    updated_at = datetime('now')
+		WHERE id = $6
+	`
+
+	modifier := fmt.Sprintf("+%d seconds", int(nextAttemptIn.Seconds()))
+	_, err := r.db.Exec(query, lastError, permanent, maxAttempts, models.DeliveryFailed, modifier, id)
+	return err
+}
+
+// Requeue moves a failed delivery back to pending for immediate redelivery
+func (r *DeliveryRepository) Requeue(id int) (bool, error) {
+	query := `
+		UPDATE deliveries
+		SET status = $1, attempts = 0, next_attempt_at = datetime('now'), updated_at = datetime('now')
+		WHERE id = $2 AND status = $3
+	`
+
+	result, err := r.db.Exec(query, models.DeliveryPending, id, models.DeliveryFailed)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetChannelStats counts deliveries per channel with the latest success and error
+func (r *DeliveryRepository) GetChannelStats() ([]*models.ChannelStatus, error) {
+	query := `
+		SELECT d.channel,
+		       SUM(CASE WHEN d.status = 'PENDING' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN d.status = 'SENT' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN d.status = 'FAILED' THEN 1 ELSE 0 END),
+		       MAX(d.sent_at),
+		       (SELECT e.last_error FROM deliveries e
+		        WHERE e.channel = d.channel AND e.last_error IS NOT NULL
+		        ORDER BY e.updated_at DESC, e.id DESC LIMIT 1),
+		       (SELECT e.updated_at FROM deliveries e
+		        WHERE e.channel = d.channel AND e.last_error IS NOT NULL
+		        ORDER BY e.updated_at DESC, e.id DESC LIMIT 1)
+		FROM deliveries d
+		GROUP BY d.channel
+		ORDER BY d.channel
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*models.ChannelStatus
+	for rows.Next() {
+		s := &models.ChannelStatus{}
+		var lastSentAt, lastError, lastErrorAt sql.NullString
+		if err := rows.Scan(&s.Channel, &s.Pending, &s.Sent, &s.Failed, &lastSentAt, &lastError, &lastErrorAt); err != nil {
+			return nil, err
+		}
+		s.LastSentAt = parseNullTime(lastSentAt)
+		s.LastError = lastError.String
+		s.LastErrorAt = parseNullTime(lastErrorAt)
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
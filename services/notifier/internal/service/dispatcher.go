@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/notifier/internal/channels"
+	"github.com/grid-trading-bot/services/notifier/internal/models"
+	"github.com/grid-trading-bot/services/notifier/internal/repository"
+	"github.com/grid-trading-bot/services/notifier/internal/templates"
+)
+
+const (
+	deliveryBatchSize  = 50
+	deliveryMaxBackoff = 1 * time.Hour
+)
+
+// DispatcherConfig controls which events are sent and how failures are retried
+type DispatcherConfig struct {
+	EventTypes    []string      // Event types to send (empty = all)
+	MaxAttempts   int           // Attempts per delivery before giving up
+	RetryInterval time.Duration // First retry delay, doubled on every failure
+}
+
+// Dispatcher fans events out to every configured channel. Each event is stored as
+// one delivery per channel before anything is sent, so deliveries survive restarts
+// and a failing channel is retried on its own without holding up the others.
+type Dispatcher struct {
+	repo      *repository.DeliveryRepository
+	channels  map[string]channels.Channel
+	templates *templates.Renderer
+	cfg       DispatcherConfig
+	filter    map[string]bool
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewDispatcher(repo *repository.DeliveryRepository, chans []channels.Channel, renderer *templates.Renderer, cfg DispatcherConfig) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d := &Dispatcher{
+		repo:      repo,
+		channels:  make(map[string]channels.Channel),
+		templates: renderer,
+		cfg:       cfg,
+		wake:      make(chan struct{}, 1),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	for _, ch := range chans {
+		d.channels[ch.Name()] = ch
+	}
+	if len(cfg.EventTypes) > 0 {
+		d.filter = make(map[string]bool)
+		for _, t := range cfg.EventTypes {
+			d.filter[t] = true
+		}
+	}
+	return d
+}
+
+// Channels returns the names of the configured channels
+func (d *Dispatcher) Channels() []string {
+	names := make([]string, 0, len(d.channels))
+	for name := range d.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dispatch renders event and queues it for every channel. Returns the number of
+// deliveries created - 0 for filtered or already seen events.
+func (d *Dispatcher) Dispatch(event contracts.Event) (int, error) {
+	if d.filter != nil && !d.filter[event.Type] {
+		log.Printf("DEBUG: Ignoring %s event %s (not in NOTIFY_EVENTS)", event.Type, event.ID)
+		return 0, nil
+	}
+
+	title, body, err := d.templates.Render(event)
+	if err != nil {
+		log.Printf("WARNING: %v - sending the plain message instead", err)
+		title, body = event.Type, event.Message
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	created := 0
+	for name := range d.channels {
+		ok, err := d.repo.Create(&models.Delivery{
+			EventID:   event.ID,
+			EventType: event.Type,
+			Channel:   name,
+			Title:     title,
+			Body:      body,
+			Payload:   string(payload),
+		})
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created++
+		}
+	}
+
+	if created > 0 {
+		log.Printf("INFO: Queued %s event %s for %d channel(s)", event.Type, event.ID, created)
+		d.Wake()
+	} else if len(d.channels) > 0 {
+		log.Printf("DEBUG: Event %s already queued, ignoring duplicate", event.ID)
+	}
+	return created, nil
+}
+
+// Retry moves a failed delivery back to pending
+func (d *Dispatcher) Retry(id int) (bool, error) {
+	requeued, err := d.repo.Requeue(id)
+	if requeued {
+		d.Wake()
+	}
+	return requeued, err
+}
+
+func (d *Dispatcher) GetDeliveries(status models.DeliveryStatus, channel string, limit int) ([]*models.Delivery, error) {
+	return d.repo.List(status, channel, limit)
+}
+
+// GetChannelStatus reports delivery counts for every configured channel, including ones with no deliveries yet
+func (d *Dispatcher) GetChannelStatus() ([]*models.ChannelStatus, error) {
+	stats, err := d.repo.GetChannelStats()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range stats {
+		seen[s.Channel] = true
+	}
+	for name := range d.channels {
+		if !seen[name] {
+			stats = append(stats, &models.ChannelStatus{Channel: name})
+		}
+	}
+	return stats, nil
+}
+
+// Wake sends due deliveries now instead of at the next tick
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) Start() {
+	log.Printf("Starting delivery worker (retry interval %s, max %d attempts)", d.cfg.RetryInterval, d.cfg.MaxAttempts)
+	d.wg.Add(1)
+	go d.loop()
+}
+
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) loop() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.cfg.RetryInterval)
+	defer ticker.Stop()
+
+	d.deliverDue()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			d.deliverDue()
+		case <-d.wake:
+			d.deliverDue()
+		}
+	}
+}
+
+func (d *Dispatcher) deliverDue() {
+	deliveries, err := d.repo.GetDue(deliveryBatchSize)
+	if err != nil {
+		log.Printf("ERROR: Failed to load due deliveries: %v", err)
+		return
+	}
+
+	for _, delivery := range deliveries {
+		if d.ctx.Err() != nil {
+			return
+		}
+		d.deliver(delivery)
+	}
+}
+
+func (d *Dispatcher) deliver(delivery *models.Delivery) {
+	ch, ok := d.channels[delivery.Channel]
+	if !ok {
+		// Channel removed from the config since the delivery was queued
+		d.markFailed(delivery, &channels.PermanentError{Err: fmt.Errorf("channel %s is not configured", delivery.Channel)})
+		return
+	}
+
+	err := ch.Send(channels.Message{
+		Title:   delivery.Title,
+		Body:    delivery.Body,
+		Payload: json.RawMessage(delivery.Payload),
+	})
+	if err != nil {
+		d.markFailed(delivery, err)
+		return
+	}
+
+	if err := d.repo.MarkSent(delivery.ID); err != nil {
+		log.Printf("ERROR: Failed to mark delivery %d sent: %v", delivery.ID, err)
+		return
+	}
+	log.Printf("SUCCESS: Sent %s event %s to %s", delivery.EventType, delivery.EventID, delivery.Channel)
+}
+
+func (d *Dispatcher) markFailed(delivery *models.Delivery, err error) {
+	backoff := d.cfg.RetryInterval * time.Duration(1<<uint(min(delivery.Attempts, 10)))
+	if backoff > deliveryMaxBackoff {
+		backoff = deliveryMaxBackoff
+	}
+	permanent := channels.IsPermanent(err)
+
+	if dbErr := d.repo.MarkFailed(delivery.ID, err.Error(), backoff, d.cfg.MaxAttempts, permanent); dbErr != nil {
+		log.Printf("ERROR: Failed to update delivery %d: %v", delivery.ID, dbErr)
+	}
+
+	if permanent || delivery.Attempts+1 >= d.cfg.MaxAttempts {
+		log.Printf("ERROR: Giving up on delivery %d (%s event %s to %s) after %d attempt(s): %v",
+			delivery.ID, delivery.EventType, delivery.EventID, delivery.Channel, delivery.Attempts+1, err)
+	} else {
+		log.Printf("WARNING: Delivery %d (%s event to %s) failed, next attempt in %s: %v",
+			delivery.ID, delivery.EventType, delivery.Channel, backoff, err)
+	}
+}
@@ -0,0 +1,166 @@
+// Package templates renders events into notification text. Each event type has a
+// text/template whose first line becomes the title (email subject) and the rest the body.
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+)
+
+// Built-in templates, overridden by <type>.tmpl files in NOTIFIER_TEMPLATES_DIR
+var defaults = map[string]string{
+	contracts.EventBuyFilled: `🟢 Bought {{.Symbol}} at {{.Fields.price}}
+Amount: {{.Fields.amount}} ({{.Fields.amount_usdt}} USDT)
+Level {{.Fields.level_id}} - selling at {{.Fields.sell_price}}`,
+
+	contracts.EventSellFilled: `🔴 Sold {{.Symbol}}{{with .Fields.stop_loss}} on its stop-loss{{end}} at {{.Fields.price}}{{with .Fields.profit_usdt}} - profit {{.}} USDT{{end}}
+Amount: {{.Fields.amount}} ({{.Fields.amount_usdt}} USDT)
+{{- with .Fields.profit_pct}}
+Profit: {{.}}% after {{$.Fields.fees_usdt}} USDT fees{{end}}
+Level {{.Fields.level_id}}`,
+
+	contracts.EventOrderFailed: `⚠️ {{.Symbol}} order failed: {{.Fields.error_code}}
+{{.Fields.error}}
+Level {{.Fields.level_id}} is in ERROR state (order {{.Fields.order_id}}, {{.Fields.side}})`,
+
+	contracts.EventTradingPaused: `⏸ Trading paused for {{.Fields.retry_after}}
+{{.Message}}`,
+
+	contracts.EventDrawdownExceeded: `📉 Drawdown {{.Fields.drawdown_pct}}% - new buys paused
+Unrealized: {{.Fields.unrealized_usdt}} USDT (limit {{.Fields.limit_pct}}%)`,
+
+	contracts.EventQuoteDepegged: `🚨 {{.Fields.quote_asset}} off peg by {{.Fields.deviation_pct}}% - new buys paused
+{{.Fields.peg_symbol}} at {{.Fields.price}} (limit {{.Fields.threshold_pct}}%)`,
+
+	contracts.EventExchangeDegraded: `🛠 Exchange degraded - trading paused
+{{.Fields.reason}}. Resumes automatically when the exchange recovers.`,
+
+	contracts.EventPlacementStuck: `⏳ {{.Symbol}} level {{.Fields.level_id}} stuck in {{.Fields.state}}
+No order ID after {{.Fields.age_sec}}s (since {{.Fields.since}}). {{if .Fields.recover_after_sec}}The sync job recovers it after {{.Fields.recover_after_sec}}s{{else}}Stuck recovery is off{{end}} - check order-assurance.`,
+
+	contracts.EventReconciliation: `🔍 Reconciliation found discrepancies (report {{.Fields.report_id}})
+{{.Fields.findings}}`,
+
+	contracts.EventSafeMode: `🛑 grid-trading started in safe mode - no trading
+{{.Fields.reason}}. Check GET /self-check, then POST /safe-mode/resume.`,
+
+	contracts.EventGridInconsistent: `🧩 {{.Fields.violations}} grid inconsistencies in {{.Fields.levels_checked}} levels
+{{.Fields.details}}
+Fix the levels before trading on them - GET /consistency lists all.`,
+
+	contracts.EventGridTrailed: `↕ {{.Symbol}} grid trailed {{.Fields.direction}}
+Level {{.Fields.retired_level_id}} ({{.Fields.retired_buy_price}} → {{.Fields.retired_sell_price}}) retired, level {{.Fields.level_id}} added at {{.Fields.buy_price}} → {{.Fields.sell_price}}. Price {{.Fields.price}}.`,
+
+	contracts.EventCalendarPause: `📅 {{.Fields.event}} - buys paused
+{{.Fields.symbols}} from {{.Fields.pause_from}} until {{.Fields.resume_at}}. Sells continue.`,
+
+	contracts.EventCalendarResume: `📅 {{.Fields.event}} {{.Fields.reason}} - buys resumed
+{{.Fields.symbols}}`,
+
+	contracts.EventSummary: `📊 Grid summary {{.Fields.date}}
+Today: {{.Fields.buys_today}} buys, {{.Fields.sells_today}} sells, profit {{.Fields.profit_today}} USDT
+All time: {{.Fields.profit_all_time}} USDT realized, {{.Fields.unrealized_usdt}} USDT unrealized
+{{- with .Fields.grid_return_pct}}
+Grid {{.}}% vs buy-and-hold {{$.Fields.hold_return_pct}}% ({{$.Fields.outperformance_usdt}} USDT){{end}}
+{{- with .Fields.roi_pct}}
+ROI {{.}}% on {{$.Fields.net_deposited_usdt}} USDT deposited{{with $.Fields.time_weighted_roi_pct}} (time-weighted {{.}}%){{end}}{{end}}`,
+
+	contracts.EventDailyReport: `🗓 Daily report {{.Fields.date}}
+Fills: {{.Fields.buys}} buys ({{.Fields.bought_usdt}} USDT), {{.Fields.sells}} sells ({{.Fields.sold_usdt}} USDT), {{.Fields.errors}} errors
+Profit {{.Fields.profit_usdt}} USDT, fees {{.Fields.fees_usdt}} USDT
+Equity {{.Fields.equity_usdt}} USDT (change {{.Fields.equity_change_usdt}}), unrealized {{.Fields.unrealized_usdt}} USDT
+{{- with .Fields.roi_pct}}
+ROI {{.}}% on {{$.Fields.net_deposited_usdt}} USDT deposited{{with $.Fields.time_weighted_roi_pct}} (time-weighted {{.}}%){{end}}{{end}}
+{{- with .Fields.currency}}
+In {{.}} (1 USDT = {{$.Fields.fx_rate}}): profit {{$.Fields.profit_converted}}, fees {{$.Fields.fees_converted}}, equity {{$.Fields.equity_converted}}{{end}}
+Levels: {{.Fields.levels}}`,
+
+	contracts.EventWeeklyDigest: `📅 Weekly digest {{.Fields.from}} - {{.Fields.to}}
+{{.Fields.cycles}} cycles, profit {{.Fields.profit_usdt}} USDT, capital utilization {{.Fields.utilization_pct}}%
+{{- with .Fields.symbols}}
+{{.}}{{end}}
+{{- with .Fields.best_levels}}
+Best levels:
+{{.}}{{end}}
+{{- with .Fields.worst_levels}}
+Worst levels:
+{{.}}{{end}}`,
+}
+
+// Used for event types without a template
+const fallback = `{{.Type}}{{with .Symbol}} {{.}}{{end}}
+{{.Message}}`
+
+type Renderer struct {
+	templates map[string]*template.Template
+	fallback  *template.Template
+}
+
+// New loads the built-in templates and any overrides from dir (empty = built-in only)
+func New(dir string) (*Renderer, error) {
+	r := &Renderer{
+		templates: make(map[string]*template.Template),
+		fallback:  template.Must(parse("fallback", fallback)),
+	}
+
+	for eventType, text := range defaults {
+		r.templates[eventType] = template.Must(parse(eventType, text))
+	}
+
+	if dir == "" {
+		return r, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		text, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", file, err)
+		}
+
+		eventType := strings.TrimSuffix(filepath.Base(file), ".tmpl")
+		tmpl, err := parse(eventType, string(text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", file, err)
+		}
+		r.templates[eventType] = tmpl
+		log.Printf("Loaded template for %s events from %s", eventType, file)
+	}
+
+	return r, nil
+}
+
+func parse(name, text string) (*template.Template, error) {
+	// Missing fields render as empty rather than "<no value>"
+	return template.New(name).Option("missingkey=zero").Parse(text)
+}
+
+// Render returns the title and body for event
+func (r *Renderer) Render(event contracts.Event) (title, body string, err error) {
+	tmpl, ok := r.templates[event.Type]
+	if !ok {
+		tmpl = r.fallback
+	}
+
+	if event.Fields == nil {
+		event.Fields = map[string]string{}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return "", "", fmt.Errorf("failed to render %s event: %w", event.Type, err)
+	}
+
+	title, body, _ = strings.Cut(strings.TrimSpace(buf.String()), "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(body), nil
+}
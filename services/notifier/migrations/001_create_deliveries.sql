@@ -0,0 +1,25 @@
+-- Create deliveries table: one row per event and channel
+CREATE TABLE IF NOT EXISTS deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    channel TEXT NOT NULL,           -- telegram | discord | email | webhook-N
+    title TEXT NOT NULL,             -- Rendered once, so retries send the same text
+    body TEXT NOT NULL,
+    payload TEXT NOT NULL,           -- Event JSON as received (sent as-is to webhooks)
+    status TEXT NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TEXT NOT NULL DEFAULT (datetime('now')),
+    sent_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+
+    -- Constraints
+    CONSTRAINT check_status CHECK (status IN ('PENDING', 'SENT', 'FAILED')),
+    CONSTRAINT unique_event_channel UNIQUE (event_id, channel)
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_deliveries_status_next ON deliveries(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_deliveries_channel ON deliveries(channel, status);
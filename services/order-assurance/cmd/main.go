@@ -3,18 +3,31 @@ package main
 import (
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/pkg/natsjs"
+	"github.com/grid-trading-bot/pkg/oplog"
+	"github.com/grid-trading-bot/pkg/tracing"
 	"github.com/grid-trading-bot/services/order-assurance/internal/api"
+	"github.com/grid-trading-bot/services/order-assurance/internal/chaos"
+	"github.com/grid-trading-bot/services/order-assurance/internal/client"
 	"github.com/grid-trading-bot/services/order-assurance/internal/config"
+	"github.com/grid-trading-bot/services/order-assurance/internal/database"
 	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+	"github.com/grid-trading-bot/services/order-assurance/internal/paper"
+	"github.com/grid-trading-bot/services/order-assurance/internal/repository"
 	"github.com/grid-trading-bot/services/order-assurance/internal/service"
-	"github.com/grid-trading-bot/services/order-assurance/internal/client"
+	pb "github.com/grid-trading-bot/services/order-assurance/proto/orderassurancepb"
 	"github.com/joho/godotenv"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -22,35 +35,259 @@ func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Printf("No .env file found: %v", err)
 	}
+	logging.Setup("order-assurance")
+	tracer := tracing.Setup("order-assurance")
 
 	// Load configuration
 	cfg := config.LoadConfig()
 
 	// Log whether we have credentials
-	if cfg.BinanceAPIKey == "" || cfg.BinanceSecret == "" {
+	if cfg.Exchange == exchange.VenueKraken {
+		if cfg.Kraken.APIKey == "" || cfg.Kraken.APISecret == "" {
+			log.Println("WARNING: Kraken API credentials not configured - order placement will fail")
+		}
+	} else if cfg.BinanceAPIKey == "" || cfg.BinanceSecret == "" {
 		log.Println("WARNING: Binance API credentials not configured - order placement will fail")
 	} else {
-		log.Println("Binance API credentials configured")
+		log.Printf("Binance API credentials configured (%d backup keys)", len(cfg.BackupKeys))
 	}
 
 	// Create Binance client (works with or without credentials)
 	binanceClient := exchange.NewBinanceClient(
 		cfg.BinanceAPIKey,
 		cfg.BinanceSecret,
+		backupKeyPairs(cfg.BackupKeys)...,
 	)
 
-	// Create grid-trading client notifier
-	gridClient := client.NewNotifier(cfg.GridTradingURL)
+	// EXCHANGE=kraken places the master account's orders on Kraken instead
+	var master exchange.Exchange = binanceClient
+	if cfg.Exchange == exchange.VenueKraken {
+		kraken := exchange.NewKrakenClient(cfg.Kraken.APIKey, cfg.Kraken.APISecret)
+		kraken.UseAPIURL(cfg.Kraken.APIURL)
+		master = kraken
+		log.Printf("Trading on Kraken at %s - only limit orders, order status, cancels and balances", cfg.Kraken.APIURL)
+	}
+
+	// Sub-accounts get their own clients so balances and fills stay segregated
+	accounts := exchange.NewAccounts(master)
+	for _, sub := range cfg.SubAccounts {
+		accounts.Add(sub.Name, exchange.NewBinanceClient(sub.APIKey, sub.APISecret, backupKeyPairs(sub.BackupKeys)...))
+		log.Printf("Binance sub-account configured: %s (%d backup keys)", sub.Name, len(sub.BackupKeys))
+	}
+
+	if cfg.BinanceAPIURL != exchange.BinanceAPIURL {
+		for _, binance := range accounts.All() {
+			binance.UseAPIURL(cfg.BinanceAPIURL)
+		}
+	}
+
+	// Optionally place and cancel orders over the WebSocket API for lower latency
+	if cfg.WSAPIEnabled {
+		for _, binance := range accounts.All() {
+			binance.EnableWebSocketAPI(cfg.WSAPIURL)
+		}
+	}
+
+	// USDT-M perpetuals trade through their own account, with the master credentials
+	if cfg.Futures.Enabled {
+		futures := exchange.NewBinanceClient(cfg.BinanceAPIKey, cfg.BinanceSecret, backupKeyPairs(cfg.BackupKeys)...)
+		futures.EnableFutures(exchange.FuturesConfig{APIURL: cfg.Futures.APIURL, Leverage: cfg.Futures.Leverage, HedgeMode: cfg.Futures.HedgeMode})
+		futures.UseAPIURL(cfg.BinanceAPIURL)
+		accounts.Add(contracts.FuturesAccount, futures)
+	}
+
+	// Margin orders go through the master account's margin wallet
+	if cfg.Margin.Enabled {
+		margin := exchange.NewBinanceClient(cfg.BinanceAPIKey, cfg.BinanceSecret, backupKeyPairs(cfg.BackupKeys)...)
+		margin.EnableMargin(exchange.MarginConfig{Isolated: cfg.Margin.Isolated, AutoBorrow: cfg.Margin.AutoBorrow})
+		margin.UseAPIURL(cfg.BinanceAPIURL)
+		accounts.Add(contracts.MarginAccount, margin)
+	}
+
+	// Hold requests back near Binance's rate limits, retrying 429s after their Retry-After
+	for _, binance := range accounts.All() {
+		binance.UseRateLimits(exchange.RateLimitConfig{
+			MaxWait: time.Duration(cfg.RateLimitMaxWaitSec) * time.Second,
+			Retries: cfg.RateLimitRetries,
+		})
+	}
+
+	// Testing only: inject latency and Binance failures to exercise recovery paths
+	var chaosInjector *chaos.Injector
+	if cfg.Chaos.Enabled {
+		latencyMax := cfg.Chaos.LatencyMaxMs
+		if latencyMax < cfg.Chaos.LatencyMinMs {
+			latencyMax = cfg.Chaos.LatencyMinMs
+		}
+		chaosInjector = chaos.NewInjector(chaos.Config{
+			LatencyMin:      time.Duration(cfg.Chaos.LatencyMinMs) * time.Millisecond,
+			LatencyMax:      time.Duration(latencyMax) * time.Millisecond,
+			RateLimitRate:   cfg.Chaos.RateLimitRate,
+			ServerErrorRate: cfg.Chaos.ServerErrorRate,
+			NotifyFailRate:  cfg.Chaos.NotifyFailRate,
+			NotifyDropRate:  cfg.Chaos.NotifyDropRate,
+		})
+		for _, binance := range accounts.All() {
+			binance.EnableChaos(chaosInjector)
+		}
+	}
+
+	// Keep symbol trading rules fresh off the order placement path
+	symbolRefresher := service.NewSymbolInfoRefresher(accounts, time.Duration(cfg.SymbolRefreshMin)*time.Minute)
+	symbolRefresher.Start()
+
+	db, err := database.NewConnection(database.Config{Path: cfg.DBPath})
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	// Run migrations
+	migrations := []string{
+		"services/order-assurance/migrations/001_create_notification_outbox.sql",
+		"services/order-assurance/migrations/002_create_order_fills.sql",
+		"services/order-assurance/migrations/003_create_orders.sql",
+		"services/order-assurance/migrations/004_create_trade_journal.sql",
+		"services/order-assurance/migrations/005_create_pending_placements.sql",
+		"services/order-assurance/migrations/006_create_api_tokens.sql",
+		"services/order-assurance/migrations/007_create_placement_audit.sql",
+		"services/order-assurance/migrations/008_create_paper_trading.sql",
+		"services/order-assurance/migrations/009_create_notification_log.sql",
+	}
+
+	for _, migrationFile := range migrations {
+		migrationSQL, err := os.ReadFile(migrationFile)
+		if err != nil {
+			log.Fatalf("Failed to read migration file %s: %v", migrationFile, err)
+		}
+
+		if err := database.RunMigrations(db, string(migrationSQL)); err != nil {
+			log.Fatalf("Failed to run migration %s: %v", migrationFile, err)
+		}
+	}
+
+	// Paper trading: orders and balances are simulated against live prices, market data stays real
+	var paperExchange *paper.Exchange
+	if cfg.Paper.Enabled {
+		paperExchange = paper.NewExchange(db, paper.Config{
+			MarketURL:      cfg.BinanceAPIURL,
+			Balances:       cfg.Paper.Balances,
+			CommissionRate: cfg.Paper.CommissionRate,
+			FillInterval:   time.Duration(cfg.Paper.FillIntervalSec) * time.Second,
+			Assets: func(symbol string) (string, string, error) {
+				base, quote, _, err := exchange.SymbolAssets(accounts.Master(), symbol)
+				return base, quote, err
+			},
+		})
+		for _, name := range append([]string{""}, accounts.Names()...) {
+			binance, _ := accounts.Binance(name)
+			binance.EnablePaperTrading(paperExchange.Account(name))
+		}
+		paperExchange.Start()
+		log.Printf("WARNING: Paper trading enabled - orders are simulated, nothing is sent to Binance (fills checked every %ds)", cfg.Paper.FillIntervalSec)
+	}
+
+	outboxRepo := repository.NewOutboxRepository(db)
+	fillRepo := repository.NewFillRepository(db)
+	orderRepo := repository.NewOrderRepository(db)
+	journalRepo := repository.NewJournalRepository(db)
+	placementRepo := repository.NewPlacementRepository(db)
+	tokenService := service.NewTokenService(repository.NewTokenRepository(db))
+
+	// Create grid-trading client notifier (undelivered notifications go to the outbox)
+	gridClient := client.NewNotifier(cfg.GridTradingURL, cfg.GridTradingAPIKey, outboxRepo)
+	if chaosInjector != nil {
+		gridClient.EnableChaos(chaosInjector)
+	}
+
+	// Fill and error notifications are numbered and kept for GET /notifications/replay
+	notificationLog := repository.NewNotificationLogRepository(db)
+	gridClient.UseNotificationLog(notificationLog)
+
+	// gRPC subscribers receive copies of every fill and error notification
+	fillStream := client.NewFillStream()
+	gridClient.UseFillStream(fillStream)
+
+	if cfg.Transport == "nats" {
+		conn, err := natsjs.Connect(cfg.NATSURL, "order-assurance")
+		if err != nil {
+			log.Fatal("Failed to connect to NATS:", err)
+		}
+		defer conn.Close()
+
+		if err := gridClient.UseJetStream(natsjs.New(conn)); err != nil {
+			log.Fatal("Failed to set up notification stream:", err)
+		}
+		log.Printf("Publishing notifications to NATS JetStream at %s", cfg.NATSURL)
+	}
+
+	outboxWorker := service.NewOutboxWorker(outboxRepo, gridClient, time.Duration(cfg.OutboxRetrySec)*time.Second)
+	outboxWorker.PruneNotificationLog(notificationLog, time.Duration(cfg.NotificationLogDays)*24*time.Hour)
+	outboxWorker.Start()
+
+	// Serialize order operations per symbol
+	orderQueue := service.NewSymbolQueue(100)
+
+	// Create TTL worker for expiring orders
+	ttlWorker := service.NewTTLWorker(accounts, orderQueue, orderRepo, time.Duration(cfg.TTLCheckIntervalSec)*time.Second)
 
 	// Create order service
-	orderService := service.NewOrderService(binanceClient, gridClient)
+	orderService := service.NewOrderService(accounts, gridClient, ttlWorker, orderQueue, outboxRepo, fillRepo, orderRepo, journalRepo, placementRepo)
+	ttlWorker.UseOrderService(orderService)
+	ttlWorker.Start()
+
+	if cfg.WithdrawAddress != "" {
+		orderService.EnableWithdrawals(cfg.WithdrawAddress, cfg.WithdrawNetwork)
+	}
+	orderService.UsePlacementAudit(repository.NewPlacementAuditRepository(db))
+	orderService.UseNotificationLog(notificationLog)
+
+	// Reconcile orders placed just before the last shutdown, before accepting new ones
+	orderService.RecoverPendingPlacements()
+
+	// Journal executions from the user-data stream and push fills to grid-trading as they happen
+	var tradeCapture *service.TradeCaptureWorker
+	if cfg.TradeCaptureEnabled {
+		tradeCapture = service.NewTradeCaptureWorker(accounts, journalRepo, cfg.UserStreamURL)
+		tradeCapture.EnableFillPush(orderService)
+		tradeCapture.Start()
+	}
 
 	// Create API handlers
-	handlers := api.NewHandlers(orderService)
+	handlers := api.NewHandlers(orderService, tokenService)
+
+	operatorLog, err := oplog.NewStore(db)
+	if err != nil {
+		log.Fatal("Failed to set up the operator log:", err)
+	}
+	handlers.UseOperatorLog(operatorLog)
+
+	// Pause grid-trading during Binance maintenance and outages
+	var exchangeStatus *service.ExchangeStatusMonitor
+	if cfg.ExchangeStatusSec > 0 && cfg.Exchange == exchange.VenueBinance {
+		exchangeStatus = service.NewExchangeStatusMonitor(accounts, gridClient, time.Duration(cfg.ExchangeStatusSec)*time.Second)
+		if cfg.Testnet {
+			exchangeStatus.SkipSystemStatus()
+		}
+		exchangeStatus.Start()
+		handlers.UseExchangeStatusMonitor(exchangeStatus)
+	}
 
 	// Setup routes
 	router := mux.NewRouter()
 	handlers.RegisterRoutes(router)
+	router.Use(tracing.Middleware)
+
+	if cfg.APIKey != "" {
+		router.Use(api.APIKeyMiddleware(cfg.APIKey, tokenService))
+		log.Println("API key authentication enabled (shared key or API tokens from /tokens)")
+	} else {
+		log.Println("WARNING: ORDER_ASSURANCE_AUTH_DISABLED=true - order endpoints are unauthenticated")
+	}
+
+	// Log manual actions by actor. Placements, status lookups, token checks and sweeps
+	// are what grid-trading and the gateway call on their own.
+	router.Use(oplog.Middleware(operatorLog, "/order-assurance", "/order-status/batch", "/tokens/verify", "/profit-sweep"))
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -58,11 +295,39 @@ func main() {
 		Handler: router,
 	}
 
+	// Optionally serve the typed gRPC contract from the same order service
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != "" {
+		var opts []grpc.ServerOption
+		if cfg.APIKey != "" {
+			unary, stream := api.GRPCAuth(cfg.APIKey, tokenService)
+			opts = append(opts, grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream))
+		}
+		grpcServer = grpc.NewServer(opts...)
+		pb.RegisterOrderAssuranceServer(grpcServer, api.NewGRPCServer(orderService, fillStream))
+
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			log.Fatal("Failed to listen for gRPC:", err)
+		}
+		go func() {
+			log.Printf("gRPC API starting on port %s", cfg.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal("gRPC server failed:", err)
+			}
+		}()
+	}
+
 
 	// Start server
 	go func() {
 		log.Printf("Order Assurance Service starting on port %s", cfg.ServerPort)
-		log.Println("Using Binance Production API")
+		if cfg.Exchange == exchange.VenueBinance {
+			log.Printf("Using Binance API at %s", cfg.BinanceAPIURL)
+		}
+		if cfg.Testnet {
+			log.Printf("WARNING: Binance testnet mode - orders and balances are not real")
+		}
 
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatal("Server failed:", err)
@@ -80,6 +345,41 @@ func main() {
 	if err := srv.Close(); err != nil {
 		log.Printf("Server close error: %v", err)
 	}
+	if grpcServer != nil {
+		grpcServer.Stop()
+	}
+
+	// Stop background workers, then drain queued order operations
+	ttlWorker.Stop()
+	if tradeCapture != nil {
+		tradeCapture.Stop()
+	}
+	if exchangeStatus != nil {
+		exchangeStatus.Stop()
+	}
+	symbolRefresher.Stop()
+	outboxWorker.Stop()
+	orderQueue.Stop()
+	if paperExchange != nil {
+		paperExchange.Stop()
+	}
+	for _, binance := range accounts.All() {
+		binance.Close()
+	}
+	tracer.Shutdown()
 
 	fmt.Println("Server stopped")
+}
+
+// backupKeyPairs labels configured backup keys for failover
+func backupKeyPairs(keys []config.KeyPair) []exchange.APIKeyPair {
+	pairs := make([]exchange.APIKeyPair, 0, len(keys))
+	for i, key := range keys {
+		pairs = append(pairs, exchange.APIKeyPair{
+			Label:     fmt.Sprintf("backup-%d", i+1),
+			APIKey:    key.APIKey,
+			APISecret: key.APISecret,
+		})
+	}
+	return pairs
 }
\ No newline at end of file
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"log/slog"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/client"
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/grid-trading-bot/services/order-assurance/internal/service"
+	pb "github.com/grid-trading-bot/services/order-assurance/proto/orderassurancepb"
+)
+
+// GRPCServer serves the typed contract in proto/order_assurance.proto from the same
+// OrderService as the HTTP handlers
+type GRPCServer struct {
+	pb.UnimplementedOrderAssuranceServer
+	orderService *service.OrderService
+	fills        *client.FillStream
+}
+
+func NewGRPCServer(orderService *service.OrderService, fills *client.FillStream) *GRPCServer {
+	return &GRPCServer{orderService: orderService, fills: fills}
+}
+
+// PlaceOrder handles idempotent order placement, as POST /order-assurance does
+func (s *GRPCServer) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.PlaceOrderResponse, error) {
+	side, err := sideFromProto(req.GetSide())
+	if err != nil {
+		return nil, err
+	}
+	price, err := parseDecimalField("price", req.GetPrice())
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseDecimalField("amount", req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Received gRPC order request", logging.KeySymbol, req.GetSymbol(), "side", side, "price", price,
+		"amount", amount, logging.KeyAccount, req.GetAccount())
+
+	if req.GetSymbol() == "" || price.IsZero() || amount.IsZero() {
+		return nil, status.Error(codes.InvalidArgument, "invalid order parameters")
+	}
+
+	resp, err := s.orderService.PlaceOrder(ctx, models.OrderRequest{
+		Symbol:     req.GetSymbol(),
+		Price:      price,
+		Side:       side,
+		Amount:     amount,
+		TTLSeconds: int(req.GetTtlSeconds()),
+		Account:    req.GetAccount(),
+	})
+	if err != nil {
+		return nil, orderErrorStatus(err)
+	}
+
+	return &pb.PlaceOrderResponse{OrderId: resp.OrderID, Status: resp.Status}, nil
+}
+
+// GetOrderStatus retrieves order status from Binance, as GET /order-status/{symbol}/{order_id} does
+func (s *GRPCServer) GetOrderStatus(ctx context.Context, req *pb.GetOrderStatusRequest) (*pb.OrderStatus, error) {
+	if req.GetSymbol() == "" || req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol and order_id are required")
+	}
+
+	orderStatus, err := s.orderService.GetOrderStatus(req.GetAccount(), req.GetSymbol(), req.GetOrderId())
+	var orderErr *exchange.OrderError
+	if errors.As(err, &orderErr) && orderErr.Code == exchange.ErrUnknownAccount {
+		return nil, orderErrorStatus(err)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get order status")
+	}
+	if orderStatus == nil {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+
+	return orderStatusToProto(*orderStatus), nil
+}
+
+// GetOrderStatuses resolves many orders at once, as POST /order-status/batch does
+func (s *GRPCServer) GetOrderStatuses(ctx context.Context, req *pb.GetOrderStatusesRequest) (*pb.GetOrderStatusesResponse, error) {
+	if len(req.GetOrders()) > maxBatchOrders {
+		return nil, status.Error(codes.InvalidArgument, "too many orders in batch (max 1000)")
+	}
+
+	queries := make([]models.OrderStatusQuery, 0, len(req.GetOrders()))
+	for _, q := range req.GetOrders() {
+		if q.GetSymbol() == "" || q.GetOrderId() == "" {
+			return nil, status.Error(codes.InvalidArgument, "each order requires symbol and order_id")
+		}
+		queries = append(queries, models.OrderStatusQuery{Symbol: q.GetSymbol(), OrderID: q.GetOrderId(), Account: q.GetAccount()})
+	}
+
+	statuses := s.orderService.GetOrderStatuses(queries)
+
+	resp := &pb.GetOrderStatusesResponse{Orders: make([]*pb.BatchOrderStatus, 0, len(statuses))}
+	for _, st := range statuses {
+		resp.Orders = append(resp.Orders, &pb.BatchOrderStatus{
+			Symbol:  st.Symbol,
+			Account: st.Account,
+			Status:  orderStatusToProto(st.OrderStatus),
+			Error:   st.Error,
+		})
+	}
+	return resp, nil
+}
+
+// SubscribeFills streams fill and error notifications until the client disconnects
+func (s *GRPCServer) SubscribeFills(req *pb.SubscribeFillsRequest, stream grpc.ServerStreamingServer[pb.OrderEvent]) error {
+	events, cancel := s.fills.Subscribe(req.GetAccount())
+	defer cancel()
+
+	log.Printf("gRPC fill subscriber connected (account: %q)", req.GetAccount())
+	defer log.Printf("gRPC fill subscriber disconnected (account: %q)", req.GetAccount())
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "subscriber fell too far behind")
+			}
+			if err := stream.Send(orderEventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// orderErrorStatus maps classified exchange errors to gRPC codes, matching writeOrderError
+func orderErrorStatus(err error) error {
+	var orderErr *exchange.OrderError
+	if !errors.As(err, &orderErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	code := codes.Internal
+	switch orderErr.Code {
+	case exchange.ErrInsufficientFunds, exchange.ErrOrderTooSmall, exchange.ErrFilterFailure, exchange.ErrInvalidSymbol, exchange.ErrUnknownAccount, exchange.ErrSweepRejected, exchange.ErrNotFutures, exchange.ErrNotMargin, exchange.ErrUnsupportedOrder, exchange.ErrUnsupportedVenue:
+		code = codes.InvalidArgument
+	case exchange.ErrRateLimited:
+		code = codes.ResourceExhausted
+	case exchange.ErrExchangeFailure, exchange.ErrCircuitOpen:
+		code = codes.Unavailable
+	}
+	return status.Error(code, string(orderErr.Code)+": "+orderErr.Message)
+}
+
+func parseDecimalField(name, value string) (decimal.Decimal, error) {
+	parsed, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, status.Errorf(codes.InvalidArgument, "invalid %s %q", name, value)
+	}
+	return parsed, nil
+}
+
+func sideFromProto(side pb.OrderSide) (models.OrderSide, error) {
+	switch side {
+	case pb.OrderSide_ORDER_SIDE_BUY:
+		return models.SideBuy, nil
+	case pb.OrderSide_ORDER_SIDE_SELL:
+		return models.SideSell, nil
+	}
+	return "", status.Error(codes.InvalidArgument, "side must be buy or sell")
+}
+
+func sideToProto(side string) pb.OrderSide {
+	switch strings.ToLower(side) {
+	case string(models.SideBuy):
+		return pb.OrderSide_ORDER_SIDE_BUY
+	case string(models.SideSell):
+		return pb.OrderSide_ORDER_SIDE_SELL
+	}
+	return pb.OrderSide_ORDER_SIDE_UNSPECIFIED
+}
+
+func decimalString(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
+
+func orderStatusToProto(s models.OrderStatus) *pb.OrderStatus {
+	return &pb.OrderStatus{
+		OrderId:         s.OrderID,
+		Status:          s.Status,
+		FilledAmount:    decimalString(s.FilledAmount),
+		FillPrice:       decimalString(s.FillPrice),
+		Commission:      decimalString(s.Commission),
+		CommissionAsset: s.CommissionAsset,
+		BaseAsset:       s.BaseAsset,
+	}
+}
+
+func orderEventToProto(event client.OrderEvent) *pb.OrderEvent {
+	if event.Fill != nil {
+		n := event.Fill
+		return &pb.OrderEvent{Event: &pb.OrderEvent_Fill{Fill: &pb.FillNotification{
+			OrderId:         n.OrderID,
+			Symbol:          n.Symbol,
+			Price:           n.Price.String(),
+			Side:            sideToProto(n.Side),
+			Status:          n.Status,
+			FilledAmount:    n.FilledAmount.String(),
+			FillPrice:       n.FillPrice.String(),
+			Account:         n.Account,
+			Commission:      n.Commission.String(),
+			CommissionAsset: n.CommissionAsset,
+			BaseAsset:       n.BaseAsset,
+		}}}
+	}
+
+	n := event.Error
+	return &pb.OrderEvent{Event: &pb.OrderEvent_Error{Error: &pb.ErrorNotification{
+		OrderId:   n.OrderID,
+		Symbol:    n.Symbol,
+		Side:      sideToProto(n.Side),
+		Price:     n.Price.String(),
+		ErrorCode: n.ErrorCode,
+		Error:     n.Error,
+		Account:   n.Account,
+	}}}
+}
+
+// GRPCAuth returns interceptors enforcing the same credentials as APIKeyMiddleware, read from
+// the x-api-key metadata. PlaceOrder needs the write scope, everything else read.
+func GRPCAuth(apiKey string, tokens *service.TokenService) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := authorizeGRPC(ctx, info.FullMethod, apiKey, tokens); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorizeGRPC(ss.Context(), info.FullMethod, apiKey, tokens); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+	return unary, stream
+}
+
+func authorizeGRPC(ctx context.Context, method, apiKey string, tokens *service.TokenService) error {
+	var provided string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(strings.ToLower(APIKeyHeader)); len(values) > 0 {
+			provided = values[0]
+		}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) == 1 {
+		return nil
+	}
+
+	token, err := tokens.Verify(provided)
+	if err != nil {
+		log.Printf("ERROR: Failed to verify API token: %v", err)
+		return status.Error(codes.Internal, "failed to verify token")
+	}
+	if token == nil {
+		log.Printf("WARNING: Rejected unauthenticated gRPC call %s", method)
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	required := contracts.ScopeRead
+	if method == pb.OrderAssurance_PlaceOrder_FullMethodName {
+		required = contracts.ScopeWrite
+	}
+	if !contracts.HasScope(token.Scopes, required) {
+		log.Printf("WARNING: Token %q lacks %s scope for gRPC call %s", token.Name, required, method)
+		return status.Error(codes.PermissionDenied, "token lacks the "+required+" scope")
+	}
+	return nil
+}
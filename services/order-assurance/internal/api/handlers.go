@@ -1,29 +1,80 @@
 package api
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/pkg/oplog"
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
 	"github.com/grid-trading-bot/services/order-assurance/internal/models"
 	"github.com/grid-trading-bot/services/order-assurance/internal/service"
 )
 
 type Handlers struct {
-	orderService *service.OrderService
+	orderService   *service.OrderService
+	tokenService   *service.TokenService
+	exchangeStatus *service.ExchangeStatusMonitor // nil = not monitored
+	operatorLog    *oplog.Store                   // nil = not logged
 }
 
-func NewHandlers(orderService *service.OrderService) *Handlers {
+func NewHandlers(orderService *service.OrderService, tokenService *service.TokenService) *Handlers {
 	return &Handlers{
 		orderService: orderService,
+		tokenService: tokenService,
 	}
 }
 
+// UseOperatorLog serves the logged operator actions on GET /operator-actions
+func (h *Handlers) UseOperatorLog(store *oplog.Store) {
+	h.operatorLog = store
+}
+
+// UseExchangeStatusMonitor serves the last exchange status check on GET /exchange-status
+func (h *Handlers) UseExchangeStatusMonitor(monitor *service.ExchangeStatusMonitor) {
+	h.exchangeStatus = monitor
+}
+
 func (h *Handlers) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/order-assurance", h.handlePlaceOrder).Methods("POST")
-	r.HandleFunc("/order-status/{order_id}", h.handleGetOrderStatus).Methods("GET")
+	r.HandleFunc("/order-status/batch", h.handleBatchOrderStatus).Methods("POST")
+	r.HandleFunc("/order-status/{symbol}/{order_id}", h.handleGetOrderStatus).Methods("GET")
+	r.HandleFunc("/order-status/{order_id}", h.handleGetOrderStatus).Methods("GET") // Legacy: symbol from ?symbol= or the order store
+	r.HandleFunc("/orders", h.handleListOrders).Methods("GET")
+	r.HandleFunc("/orders/{symbol}/{order_id}", h.handleCancelOrder).Methods("DELETE")
+	r.HandleFunc("/placements/audit", h.handlePlacementAudit).Methods("GET")
+	r.HandleFunc("/trades/journal", h.handleTradeJournal).Methods("GET")
+	r.HandleFunc("/profit-sweep", h.handleSweepProfit).Methods("POST")
+	r.HandleFunc("/balances", h.handleGetBalances).Methods("GET")
+	r.HandleFunc("/symbols/{symbol}", h.handleGetSymbolAssets).Methods("GET")
+	r.HandleFunc("/book/{symbol}", h.handleGetBookTicker).Methods("GET")
+	r.HandleFunc("/open-orders/{symbol}", h.handleGetOpenOrders).Methods("GET")
+	r.HandleFunc("/positions", h.handleGetPositions).Methods("GET")
+	r.HandleFunc("/funding-fees", h.handleGetFundingFees).Methods("GET")
+	r.HandleFunc("/margin/debts", h.handleGetMarginDebts).Methods("GET")
+	r.HandleFunc("/margin/interest", h.handleGetMarginInterest).Methods("GET")
+	r.HandleFunc("/circuit-breakers", h.handleCircuitBreakers).Methods("GET")
+	r.HandleFunc("/exchange-status", h.handleExchangeStatus).Methods("GET")
+	r.HandleFunc("/status", h.handleStatus).Methods("GET")
+	r.HandleFunc("/api-keys", h.handleGetAPIKeys).Methods("GET")
+	r.HandleFunc("/api-keys/rotate", h.handleRotateAPIKey).Methods("POST")
+	r.HandleFunc("/tokens", h.handleCreateToken).Methods("POST")
+	r.HandleFunc("/tokens", h.handleListTokens).Methods("GET")
+	r.HandleFunc("/tokens/verify", h.handleVerifyToken).Methods("POST")
+	r.HandleFunc("/tokens/{id}", h.handleRevokeToken).Methods("DELETE")
+	r.HandleFunc("/notifications/outbox", h.handleGetOutbox).Methods("GET")
+	r.HandleFunc("/notifications/outbox/{id}/requeue", h.handleRequeueNotification).Methods("POST")
+	r.HandleFunc("/notifications/replay", h.handleReplayNotifications).Methods("GET")
+	r.HandleFunc("/operator-actions", h.handleOperatorActions).Methods("GET")
 	r.HandleFunc("/health", h.handleHealth).Methods("GET")
 }
 
@@ -35,71 +86,113 @@ func (h *Handlers) handlePlaceOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Received order request: %s %s at %s, amount: %s",
-		req.Side, req.Symbol, req.Price, req.Amount)
+	slog.Info("Received order request", logging.KeySymbol, req.Symbol, "side", req.Side, "price", req.Price,
+		"amount", req.Amount, logging.KeyAccount, req.Account)
 
 	// Validate request
 	if req.Symbol == "" || req.Price.IsZero() || req.Amount.IsZero() {
 		http.Error(w, "Invalid order parameters", http.StatusBadRequest)
 		return
 	}
+	if req.QuoteOrderQty && req.Side != models.SideBuy {
+		http.Error(w, "quote_order_qty is only supported for buy orders", http.StatusBadRequest)
+		return
+	}
+	if req.Market && req.Side != models.SideSell {
+		http.Error(w, "market is only supported for sell orders", http.StatusBadRequest)
+		return
+	}
+	if req.OrderType != "" && req.OrderType != models.OrderTypeLimit && req.OrderType != models.OrderTypeMarket {
+		http.Error(w, "order_type must be limit or market", http.StatusBadRequest)
+		return
+	}
+	if req.StopPrice != nil && (req.Side != models.SideSell || req.Market || req.OrderType == models.OrderTypeMarket) {
+		http.Error(w, "stop_price is only supported for limit sell orders", http.StatusBadRequest)
+		return
+	}
+	if req.StopPrice != nil && (!req.StopPrice.IsPositive() || !req.StopPrice.LessThan(req.Price)) {
+		http.Error(w, "stop_price must be positive and below price", http.StatusBadRequest)
+		return
+	}
 
 	// Place order (idempotent)
-	resp, err := h.orderService.PlaceOrder(req)
-	if err != nil {
-
-		// Parse Binance error codes
-		errorMsg := err.Error()
-		if strings.Contains(errorMsg, "insufficient") || strings.Contains(errorMsg, "balance") {
-			errorResp := map[string]string{
-				"error": "insufficient_funds",
-				"message": errorMsg,
-			}
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(errorResp)
-			return
-		} else if strings.Contains(errorMsg, "MIN_NOTIONAL") {
-			errorResp := map[string]string{
-				"error": "order_too_small",
-				"message": "Order value below minimum",
-			}
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(errorResp)
-			return
+	resp, err := h.orderService.PlaceOrder(r.Context(), req)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// writeOrderError maps classified exchange errors to HTTP status codes
+func writeOrderError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	errorResp := map[string]string{
+		"error":   "order_failed",
+		"message": err.Error(),
+	}
+
+	var orderErr *exchange.OrderError
+	if errors.As(err, &orderErr) {
+		errorResp["error"] = string(orderErr.Code)
+		errorResp["message"] = orderErr.Message
+		for k, v := range orderErr.Details {
+			errorResp[k] = v
 		}
 
-		// Return detailed error as JSON
-		errorResp := map[string]string{
-			"error": "order_failed",
-			"message": errorMsg,
+		switch orderErr.Code {
+		case exchange.ErrInsufficientFunds, exchange.ErrOrderTooSmall, exchange.ErrFilterFailure, exchange.ErrInvalidSymbol, exchange.ErrUnknownAccount, exchange.ErrSweepRejected, exchange.ErrNotFutures, exchange.ErrNotMargin, exchange.ErrUnsupportedOrder, exchange.ErrUnsupportedVenue:
+			status = http.StatusBadRequest
+		case exchange.ErrRateLimited:
+			status = http.StatusTooManyRequests
+		case exchange.ErrExchangeFailure:
+			status = http.StatusBadGateway
+		case exchange.ErrCircuitOpen:
+			status = http.StatusServiceUnavailable
 		}
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(errorResp)
-		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResp)
 }
 
 // handleGetOrderStatus retrieves order status from Binance
 func (h *Handlers) handleGetOrderStatus(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	orderID := vars["order_id"]
-	symbol := r.URL.Query().Get("symbol")
+	symbol := vars["symbol"]
+	if symbol == "" {
+		symbol = r.URL.Query().Get("symbol")
+	}
+	account := r.URL.Query().Get("account")
 
 	if orderID == "" {
 		http.Error(w, "Order ID is required", http.StatusBadRequest)
 		return
 	}
 
-	if symbol == "" {
-		http.Error(w, "Symbol is required", http.StatusBadRequest)
-		return
+	var status *models.OrderStatus
+	var err error
+	if symbol != "" {
+		status, err = h.orderService.GetOrderStatus(account, symbol, orderID)
+	} else {
+		status, err = h.orderService.GetOrderStatusByID(account, orderID)
 	}
 
-	status, err := h.orderService.GetOrderStatus(symbol, orderID)
+	if errors.Is(err, service.ErrAmbiguousOrder) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	var orderErr *exchange.OrderError
+	if errors.As(err, &orderErr) && orderErr.Code == exchange.ErrUnknownAccount {
+		writeOrderError(w, err)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to get order status", http.StatusInternalServerError)
 		return
@@ -115,8 +208,682 @@ func (h *Handlers) handleGetOrderStatus(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(status)
 }
 
+// maxBatchOrders caps a batch status request
+const maxBatchOrders = 1000
+
+// handleBatchOrderStatus resolves many orders at once, grouping Binance calls per symbol
+func (h *Handlers) handleBatchOrderStatus(w http.ResponseWriter, r *http.Request) {
+	var req models.BatchOrderStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Orders) > maxBatchOrders {
+		http.Error(w, "Too many orders in batch (max 1000)", http.StatusBadRequest)
+		return
+	}
+
+	for _, q := range req.Orders {
+		if q.Symbol == "" || q.OrderID == "" {
+			http.Error(w, "Each order requires symbol and order_id", http.StatusBadRequest)
+			return
+		}
+	}
+
+	statuses := h.orderService.GetOrderStatuses(req.Orders)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.BatchOrderStatusResponse{Orders: statuses})
+}
+
+// Order history page size bounds
+const (
+	defaultOrdersLimit = 100
+	maxOrdersLimit     = 1000
+)
+
+// handleListOrders pages through the local order store for auditing what was sent to the exchange
+func (h *Handlers) handleListOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.OrderFilter{
+		Symbol:  strings.ToUpper(q.Get("symbol")),
+		Account: q.Get("account"),
+		Status:  q.Get("status"),
+		Limit:   defaultOrdersLimit,
+	}
+
+	if filter.Status != "" && filter.Status != "open" && filter.Status != "filled" && filter.Status != "cancelled" {
+		http.Error(w, "Invalid status (open, filled, cancelled)", http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(q.Get("from")); err != nil {
+		http.Error(w, "Invalid from (RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = parseTimeParam(q.Get("to")); err != nil {
+		http.Error(w, "Invalid to (RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxOrdersLimit {
+			http.Error(w, "Invalid limit (1-1000)", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			http.Error(w, "Invalid offset", http.StatusBadRequest)
+			return
+		}
+		filter.Offset = offset
+	}
+
+	orders, total, err := h.orderService.ListOrders(filter)
+	if err != nil {
+		log.Printf("ERROR: Failed to list orders: %v", err)
+		http.Error(w, "Failed to list orders", http.StatusInternalServerError)
+		return
+	}
+
+	if orders == nil {
+		orders = []*models.PlacedOrder{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"orders": orders,
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// handlePlacementAudit returns order submissions as sent to the exchange and its replies
+func (h *Handlers) handlePlacementAudit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.PlacementAuditFilter{
+		Symbol:        strings.ToUpper(q.Get("symbol")),
+		Account:       q.Get("account"),
+		ClientOrderID: q.Get("client_order_id"),
+		OrderID:       q.Get("order_id"),
+		Limit:         defaultOrdersLimit,
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(q.Get("from")); err != nil {
+		http.Error(w, "Invalid from (RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = parseTimeParam(q.Get("to")); err != nil {
+		http.Error(w, "Invalid to (RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxOrdersLimit {
+			http.Error(w, "Invalid limit (1-1000)", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	placements, err := h.orderService.PlacementAudit(filter)
+	if err != nil {
+		log.Printf("ERROR: Failed to read placement audit: %v", err)
+		http.Error(w, "Failed to read placement audit", http.StatusInternalServerError)
+		return
+	}
+
+	if placements == nil {
+		placements = []*models.PlacementAudit{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"placements": placements})
+}
+
+// handleTradeJournal exports journaled executions as JSON or CSV (?format=csv)
+func (h *Handlers) handleTradeJournal(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.JournalFilter{
+		Symbol:  strings.ToUpper(q.Get("symbol")),
+		Account: q.Get("account"),
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(q.Get("from")); err != nil {
+		http.Error(w, "Invalid from (RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = parseTimeParam(q.Get("to")); err != nil {
+		http.Error(w, "Invalid to (RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+
+	format := q.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, "Invalid format (json, csv)", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.orderService.TradeJournal(filter)
+	if err != nil {
+		log.Printf("ERROR: Failed to read trade journal: %v", err)
+		http.Error(w, "Failed to read trade journal", http.StatusInternalServerError)
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="trade_journal.csv"`)
+		writer := csv.NewWriter(w)
+		writer.Write([]string{
+			"traded_at", "account", "symbol", "order_id", "client_order_id", "trade_id", "side",
+			"price", "quantity", "quote_quantity", "commission", "commission_asset", "is_maker", "order_status",
+		})
+		for _, e := range entries {
+			writer.Write([]string{
+				e.TradedAt.Format(time.RFC3339), e.Account, e.Symbol, e.OrderID, e.ClientOrderID,
+				strconv.FormatInt(e.TradeID, 10), e.Side, e.Price.String(), e.Quantity.String(),
+				e.QuoteQuantity.String(), e.Commission.String(), e.CommissionAsset,
+				strconv.FormatBool(e.IsMaker), e.OrderStatus,
+			})
+		}
+		writer.Flush()
+		return
+	}
+
+	if entries == nil {
+		entries = []*models.JournalEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"trades": entries})
+}
+
+// parseTimeParam accepts RFC3339 or a bare date (midnight UTC); empty means unset
+func parseTimeParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+// handleSweepProfit moves realized profit off the spot wallet (or simulates it with dry_run)
+func (h *Handlers) handleSweepProfit(w http.ResponseWriter, r *http.Request) {
+	var req models.SweepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Asset == "" || !req.Amount.IsPositive() {
+		http.Error(w, "Invalid sweep parameters", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Received profit sweep request: %s %s to %s, account: %q, dry run: %v",
+		req.Amount, req.Asset, req.Destination, req.Account, req.DryRun)
+
+	result, err := h.orderService.SweepProfit(req)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleGetSymbolAssets names a symbol's base and quote asset (?account= picks whose exchange rules to read)
+func (h *Handlers) handleGetSymbolAssets(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	assets, err := h.orderService.GetSymbolAssets(r.URL.Query().Get("account"), symbol)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assets)
+}
+
+// handleGetBookTicker returns a symbol's best bid and ask (?account= picks whose market to read)
+func (h *Handlers) handleGetBookTicker(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	book, err := h.orderService.GetBookTicker(r.URL.Query().Get("account"), symbol)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(book)
+}
+
+// handleGetOpenOrders lists every order open on the exchange for a symbol (?account= picks the account)
+func (h *Handlers) handleGetOpenOrders(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	orders, err := h.orderService.GetOpenOrders(r.URL.Query().Get("account"), symbol)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// handleCancelOrder cancels an open order of an account (?account=)
+func (h *Handlers) handleCancelOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	status, err := h.orderService.CancelOrder(r.URL.Query().Get("account"), vars["symbol"], vars["order_id"])
+	if err != nil {
+		slog.Error("Failed to cancel order", logging.KeySymbol, vars["symbol"], logging.KeyOrderID, vars["order_id"], logging.Err(err))
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// handleGetBalances returns an account's free spot balances, e.g. to seed grid levels from existing holdings
+func (h *Handlers) handleGetBalances(w http.ResponseWriter, r *http.Request) {
+	account := r.URL.Query().Get("account")
+
+	balances, err := h.orderService.GetFreeBalances(account)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contracts.BalancesResponse{Account: account, Balances: balances})
+}
+
+// handleGetPositions returns open USDT-M positions of a futures account
+func (h *Handlers) handleGetPositions(w http.ResponseWriter, r *http.Request) {
+	account := r.URL.Query().Get("account")
+
+	positions, err := h.orderService.GetPositions(account)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contracts.PositionsResponse{Positions: positions})
+}
+
+// handleGetFundingFees returns funding payments of a futures account since ?since= (RFC3339)
+func (h *Handlers) handleGetFundingFees(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var since time.Time
+	if v := query.Get("since"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "Invalid since, expected RFC3339", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	fees, err := h.orderService.GetFundingFees(query.Get("account"), query.Get("symbol"), since)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contracts.FundingFeesResponse{FundingFees: fees})
+}
+
+// handleGetMarginDebts returns borrowed assets and outstanding interest of a margin account
+func (h *Handlers) handleGetMarginDebts(w http.ResponseWriter, r *http.Request) {
+	debts, err := h.orderService.GetMarginDebts(r.URL.Query().Get("account"))
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contracts.MarginDebtsResponse{Debts: debts})
+}
+
+// handleGetMarginInterest returns interest charged to a margin account since ?since= (RFC3339)
+func (h *Handlers) handleGetMarginInterest(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var since time.Time
+	if v := query.Get("since"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "Invalid since, expected RFC3339", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	interest, err := h.orderService.GetMarginInterest(query.Get("account"), since)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contracts.MarginInterestResponse{Interest: interest})
+}
+
+// handleCircuitBreakers publishes exchange circuit breaker states so callers can pause trading
+func (h *Handlers) handleCircuitBreakers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"circuit_breakers": h.orderService.CircuitStatuses(),
+	})
+}
+
+// handleStatus reports the Binance rate limit usage of each account
+func (h *Handlers) handleStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"rate_limits": h.orderService.RateLimitStatuses(),
+	})
+}
+
+// handleOperatorActions lists the logged operator actions, newest first
+func (h *Handlers) handleOperatorActions(w http.ResponseWriter, r *http.Request) {
+	if h.operatorLog == nil {
+		http.Error(w, "Operator log not enabled", http.StatusNotFound)
+		return
+	}
+	h.operatorLog.ServeHTTP(w, r)
+}
+
+// handleExchangeStatus reports whether the exchange is degraded, as last sent to grid-trading
+func (h *Handlers) handleExchangeStatus(w http.ResponseWriter, r *http.Request) {
+	if h.exchangeStatus == nil {
+		http.Error(w, "Exchange status monitor is disabled (EXCHANGE_STATUS_INTERVAL_SEC=0)", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.exchangeStatus.Status())
+}
+
+// handleGetAPIKeys reports the health of an account's API keys (masked)
+func (h *Handlers) handleGetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	account := r.URL.Query().Get("account")
+
+	statuses, err := h.orderService.APIKeyStatuses(account)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"api_keys": statuses})
+}
+
+// handleRotateAPIKey switches an account to its next API key without restarting
+func (h *Handlers) handleRotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	account := r.URL.Query().Get("account")
+
+	active, err := h.orderService.RotateAPIKey(account)
+	var orderErr *exchange.OrderError
+	if errors.As(err, &orderErr) {
+		writeOrderError(w, err)
+		return
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	log.Printf("INFO: API key rotated via admin endpoint - account: %q, active: %s", account, active.Label)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "rotated", "active": active})
+}
+
+type createTokenRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days"` // 0 = never expires
+}
+
+// handleCreateToken issues an API token. The token is only ever shown in this response.
+func (h *Handlers) handleCreateToken(w http.ResponseWriter, r *http.Request) {
+	var req createTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Scopes) == 0 {
+		http.Error(w, "at least one scope is required", http.StatusBadRequest)
+		return
+	}
+	for _, scope := range req.Scopes {
+		if !contracts.ValidScope(scope) {
+			http.Error(w, "Invalid scope "+strconv.Quote(scope)+" (read, write or admin)", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.ExpiresInDays < 0 {
+		http.Error(w, "expires_in_days must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	token, created, err := h.tokenService.Create(req.Name, req.Scopes, time.Duration(req.ExpiresInDays)*24*time.Hour)
+	if err != nil {
+		log.Printf("ERROR: Failed to create API token: %v", err)
+		http.Error(w, "Failed to create token", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(struct {
+		Token string `json:"token"`
+		*models.APIToken
+	}{token, created})
+}
+
+// handleListTokens lists tokens without their secrets
+func (h *Handlers) handleListTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := h.tokenService.List()
+	if err != nil {
+		log.Printf("ERROR: Failed to list API tokens: %v", err)
+		http.Error(w, "Failed to list tokens", http.StatusInternalServerError)
+		return
+	}
+
+	if tokens == nil {
+		tokens = []*models.APIToken{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"tokens": tokens})
+}
+
+// handleRevokeToken disables a token immediately
+func (h *Handlers) handleRevokeToken(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid token ID", http.StatusBadRequest)
+		return
+	}
+
+	revoked, err := h.tokenService.Revoke(id)
+	if err != nil {
+		log.Printf("ERROR: Failed to revoke API token %d: %v", id, err)
+		http.Error(w, "Failed to revoke token", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !revoked {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Active token not found"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"status": "revoked"})
+}
+
+// handleVerifyToken lets the gateway check client tokens against this service's store
+func (h *Handlers) handleVerifyToken(w http.ResponseWriter, r *http.Request) {
+	var req contracts.TokenVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.tokenService.Verify(req.Token)
+	if err != nil {
+		log.Printf("ERROR: Failed to verify API token: %v", err)
+		http.Error(w, "Failed to verify token", http.StatusInternalServerError)
+		return
+	}
+
+	response := contracts.TokenVerifyResponse{}
+	if token != nil {
+		response = contracts.TokenVerifyResponse{Valid: true, Name: token.Name, Scopes: token.Scopes, ExpiresAt: token.ExpiresAt}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleGetOutbox lists persisted notifications, the dead-letter set by default
+func (h *Handlers) handleGetOutbox(w http.ResponseWriter, r *http.Request) {
+	status := models.OutboxStatus(r.URL.Query().Get("status"))
+	if status == "" {
+		status = models.OutboxDead
+	}
+
+	if status != models.OutboxPending && status != models.OutboxDead && status != models.OutboxDelivered {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.orderService.GetOutboxEntries(status)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch outbox entries: %v", err)
+		http.Error(w, "Failed to fetch outbox entries", http.StatusInternalServerError)
+		return
+	}
+
+	if entries == nil {
+		entries = []*models.OutboxEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"notifications": entries})
+}
+
+// handleRequeueNotification moves a dead-letter notification back to pending
+func (h *Handlers) handleRequeueNotification(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	requeued, err := h.orderService.RequeueNotification(id)
+	if err != nil {
+		log.Printf("ERROR: Failed to requeue notification %d: %v", id, err)
+		http.Error(w, "Failed to requeue notification", http.StatusInternalServerError)
+		return
+	}
+
+	if !requeued {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Dead-letter notification not found"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "requeued"})
+}
+
+// handleReplayNotifications returns the numbered fill and error notifications after ?after_seq=
+// (default 0), oldest first, up to ?limit= (default 100)
+func (h *Handlers) handleReplayNotifications(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var afterSeq int64
+	if v := q.Get("after_seq"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid after_seq", http.StatusBadRequest)
+			return
+		}
+		afterSeq = parsed
+	}
+
+	limit := defaultOrdersLimit
+	if v := q.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 || parsed > maxOrdersLimit {
+			http.Error(w, "Invalid limit (1-1000)", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	replay, err := h.orderService.ReplayNotifications(afterSeq, limit)
+	if errors.Is(err, service.ErrNotificationLogOff) {
+		http.Error(w, "Notification log is not enabled", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to replay notifications after seq %d: %v", afterSeq, err)
+		http.Error(w, "Failed to replay notifications", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(replay)
+}
+
 // handleHealth returns service health status
+// With ?deep=true it also verifies the service is able to trade on Binance
 func (h *Handlers) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
-}
\ No newline at end of file
+
+	if r.URL.Query().Get("deep") != "true" {
+		json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
+		return
+	}
+
+	report := h.orderService.DeepHealthCheck()
+	if report.Status != "healthy" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/oplog"
+	"github.com/grid-trading-bot/services/order-assurance/internal/service"
+)
+
+// APIKeyHeader carries the shared key grid-trading uses to call order-assurance
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyMiddleware rejects requests without the shared API key or an active API token
+// with the scope the request needs. The shared key has full access, so it can create
+// the first tokens. Plain health checks stay open so orchestrators can probe the service;
+// ?deep=true makes signed Binance calls, so it needs a key like any other endpoint.
+// Requests with the shared key act as the contracts.ActorHeader the gateway sends, else
+// as api-key; token requests as token:<name>.
+func APIKeyMiddleware(apiKey string, tokens *service.TokenService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" && r.URL.Query().Get("deep") != "true" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			provided := r.Header.Get(APIKeyHeader)
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) == 1 {
+				actor := r.Header.Get(contracts.ActorHeader)
+				if actor == "" {
+					actor = "api-key"
+				}
+				next.ServeHTTP(w, r.WithContext(oplog.WithActor(r.Context(), actor)))
+				return
+			}
+
+			token, err := tokens.Verify(provided)
+			if err != nil {
+				log.Printf("ERROR: Failed to verify API token: %v", err)
+				writeAuthError(w, http.StatusInternalServerError, "failed to verify token")
+				return
+			}
+			if token == nil {
+				log.Printf("WARNING: Rejected unauthenticated request %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+				writeAuthError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if required := contracts.RequiredScope(r.Method, r.URL.Path); !contracts.HasScope(token.Scopes, required) {
+				log.Printf("WARNING: Token %q lacks %s scope for %s %s", token.Name, required, r.Method, r.URL.Path)
+				writeAuthError(w, http.StatusForbidden, "token lacks the "+required+" scope")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(oplog.WithActor(r.Context(), "token:"+token.Name)))
+		})
+	}
+}
+
+func writeAuthError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
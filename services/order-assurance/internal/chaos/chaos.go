@@ -0,0 +1,107 @@
+package chaos
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config sets fault rates (0-1) and latency for resilience testing. Never enable in production.
+type Config struct {
+	LatencyMin      time.Duration
+	LatencyMax      time.Duration
+	RateLimitRate   float64 // Fake Binance 429s
+	ServerErrorRate float64 // Fake Binance 5xx
+	NotifyFailRate  float64 // Failed delivery attempts (exercises the outbox)
+	NotifyDropRate  float64 // Silently lost notifications (exercises SyncOrders)
+}
+
+// Injector decides when to inject faults. A nil *Injector injects nothing,
+// so callers can hold one unconditionally.
+type Injector struct {
+	cfg Config
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func NewInjector(cfg Config) *Injector {
+	log.Printf("WARNING: CHAOS MODE ENABLED - latency %s-%s, 429 rate %.2f, 5xx rate %.2f, notification fail rate %.2f, drop rate %.2f",
+		cfg.LatencyMin, cfg.LatencyMax, cfg.RateLimitRate, cfg.ServerErrorRate, cfg.NotifyFailRate, cfg.NotifyDropRate)
+	return &Injector{
+		cfg: cfg,
+		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Delay sleeps for a random duration within the configured latency range
+func (i *Injector) Delay() {
+	if i == nil || i.cfg.LatencyMax <= 0 {
+		return
+	}
+
+	delay := i.cfg.LatencyMin
+	if spread := i.cfg.LatencyMax - i.cfg.LatencyMin; spread > 0 {
+		delay += time.Duration(i.float() * float64(spread))
+	}
+	time.Sleep(delay)
+}
+
+// ExchangeResponse returns a fake Binance error response to use instead of sending req,
+// or nil to let the request through
+func (i *Injector) ExchangeResponse(req *http.Request) *http.Response {
+	if i == nil {
+		return nil
+	}
+
+	roll := i.float()
+	switch {
+	case roll < i.cfg.RateLimitRate:
+		log.Printf("WARNING: [CHAOS] Injected 429 for %s %s", req.Method, req.URL.Path)
+		resp := fakeResponse(req, http.StatusTooManyRequests, `{"code":-1003,"msg":"Too many requests (chaos)."}`)
+		resp.Header.Set("Retry-After", "1")
+		return resp
+	case roll < i.cfg.RateLimitRate+i.cfg.ServerErrorRate:
+		status := []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable}[i.intn(3)]
+		log.Printf("WARNING: [CHAOS] Injected %d for %s %s", status, req.Method, req.URL.Path)
+		return fakeResponse(req, status, fmt.Sprintf(`{"code":-1001,"msg":"Internal error (chaos %d)."}`, status))
+	}
+	return nil
+}
+
+// FailNotification reports whether a notification delivery attempt should fail
+func (i *Injector) FailNotification() bool {
+	return i != nil && i.float() < i.cfg.NotifyFailRate
+}
+
+// DropNotification reports whether a notification should be silently lost
+func (i *Injector) DropNotification() bool {
+	return i != nil && i.float() < i.cfg.NotifyDropRate
+}
+
+func (i *Injector) float() float64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rng.Float64()
+}
+
+func (i *Injector) intn(n int) int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rng.Intn(n)
+}
+
+func fakeResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+// OrderEvent is one fill or error notification, as sent to grid-trading
+type OrderEvent struct {
+	Fill  *models.FillNotification
+	Error *models.ErrorNotification
+}
+
+func (e OrderEvent) account() string {
+	if e.Fill != nil {
+		return e.Fill.Account
+	}
+	return e.Error.Account
+}
+
+// subscriberBuffer is how many events a slow subscriber may fall behind before it is dropped
+const subscriberBuffer = 256
+
+type fillSubscriber struct {
+	account string // Only this sub-account's events (empty = all)
+	events  chan OrderEvent
+}
+
+// FillStream fans out the notifications the Notifier sends to live subscribers (gRPC
+// SubscribeFills). Subscribers get copies - grid-trading's webhooks or NATS queue still
+// receive every notification, so a dropped subscriber loses nothing that isn't delivered there.
+type FillStream struct {
+	mu          sync.Mutex
+	subscribers map[*fillSubscriber]struct{}
+}
+
+func NewFillStream() *FillStream {
+	return &FillStream{subscribers: make(map[*fillSubscriber]struct{})}
+}
+
+// Subscribe returns a channel of events for account (empty = all accounts), and a function
+// that ends the subscription. The channel is closed when the subscription ends, including
+// when the subscriber falls too far behind.
+func (s *FillStream) Subscribe(account string) (<-chan OrderEvent, func()) {
+	sub := &fillSubscriber{account: account, events: make(chan OrderEvent, subscriberBuffer)}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	return sub.events, func() { s.remove(sub) }
+}
+
+func (s *FillStream) remove(sub *fillSubscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// publish hands event to every matching subscriber without blocking the notifier
+func (s *FillStream) publish(event OrderEvent) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		if sub.account != "" && sub.account != event.account() {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			slog.Warn("Fill stream subscriber fell behind, dropping it", "buffer", subscriberBuffer)
+			delete(s.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
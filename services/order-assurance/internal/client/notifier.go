@@ -4,115 +4,233 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/pkg/natsjs"
+	"github.com/grid-trading-bot/services/order-assurance/internal/chaos"
 	"github.com/grid-trading-bot/services/order-assurance/internal/models"
 )
 
+// Outbox persists notifications that could not be delivered
+type Outbox interface {
+	Enqueue(kind, orderID, payload, lastError string) error
+}
+
+// NotificationLog numbers fill and error notifications and keeps them for replay
+type NotificationLog interface {
+	Append(kind, orderID, payload string) (int64, error)
+}
+
 type Notifier struct {
 	gridTradingURL string
+	apiKey         string // Sent as X-API-Key
 	client         *http.Client
 	maxRetries     int
 	retryDelay     time.Duration
+	outbox         Outbox
+	chaos          *chaos.Injector   // Fault injection for resilience testing (nil = off)
+	js             *natsjs.JetStream // Publish to NATS JetStream instead of webhooks (nil = HTTP)
+	stream         *FillStream       // Live copies for gRPC subscribers (nil = off)
+	log            NotificationLog   // Sequence numbers for replay (nil = notifications are not numbered)
 }
 
-func NewNotifier(gridTradingURL string) *Notifier {
+func NewNotifier(gridTradingURL, apiKey string, outbox Outbox) *Notifier {
 	return &Notifier{
 		gridTradingURL: gridTradingURL,
+		apiKey:         apiKey,
 		client:         &http.Client{Timeout: 10 * time.Second},
 		maxRetries:     3,
 		retryDelay:     1 * time.Second,
+		outbox:         outbox,
+	}
+}
+
+// EnableChaos makes deliveries fail or vanish at the injector's configured rates
+func (n *Notifier) EnableChaos(injector *chaos.Injector) {
+	n.chaos = injector
+}
+
+// UseJetStream publishes notifications to NATS JetStream, where they wait until
+// grid-trading acknowledges them, instead of calling its webhooks
+func (n *Notifier) UseJetStream(js *natsjs.JetStream) error {
+	if err := js.EnsureStream(contracts.NotificationStream); err != nil {
+		return err
 	}
+	n.js = js
+	return nil
+}
+
+// UseFillStream also hands every fill and error notification to the stream's subscribers
+func (n *Notifier) UseFillStream(stream *FillStream) {
+	n.stream = stream
+}
+
+// UseNotificationLog numbers every fill and error notification, so grid-trading can spot
+// the ones it missed and pull them from GET /notifications/replay
+func (n *Notifier) UseNotificationLog(log NotificationLog) {
+	n.log = log
 }
 
 // SendFillNotification sends fill notification to grid-trading service
 func (n *Notifier) SendFillNotification(notification models.FillNotification) error {
-	url := fmt.Sprintf("%s/order-fill-notification", n.gridTradingURL)
+	notification.Seq = n.sequence(models.NotificationKindFill, notification.OrderID, notification)
+	n.stream.publish(OrderEvent{Fill: &notification})
 
 	jsonData, err := json.Marshal(notification)
 	if err != nil {
 		return fmt.Errorf("failed to marshal notification: %w", err)
 	}
 
-	for attempt := 1; attempt <= n.maxRetries; attempt++ {
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
-		}
-
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := n.client.Do(req)
-		if err != nil {
-			if attempt < n.maxRetries {
-				log.Printf("Failed to send fill notification (attempt %d/%d): %v", attempt, n.maxRetries, err)
-				time.Sleep(n.retryDelay * time.Duration(attempt))
-				continue
-			}
-			return fmt.Errorf("failed to send notification after %d attempts: %w", n.maxRetries, err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			log.Printf("Successfully sent fill notification for order %s", notification.OrderID)
-			return nil
-		}
-
-		if attempt < n.maxRetries {
-			log.Printf("Received status %d for fill notification (attempt %d/%d)", resp.StatusCode, attempt, n.maxRetries)
-			time.Sleep(n.retryDelay * time.Duration(attempt))
-			continue
-		}
+	if n.chaos.DropNotification() {
+		slog.Warn("[CHAOS] Dropped fill notification", logging.KeyOrderID, notification.OrderID)
+		return nil
+	}
 
-		return fmt.Errorf("failed with status %d after %d attempts", resp.StatusCode, n.maxRetries)
+	if err := n.sendWithRetries(models.NotificationKindFill, jsonData); err != nil {
+		n.persist(models.NotificationKindFill, notification.OrderID, jsonData, err)
+		return err
 	}
 
+	slog.Info("Sent fill notification to grid-trading", logging.KeyOrderID, notification.OrderID, "status", notification.Status)
 	return nil
 }
 
 // SendErrorNotification sends error notification to grid-trading service
 func (n *Notifier) SendErrorNotification(notification models.ErrorNotification) error {
-	url := fmt.Sprintf("%s/order-fill-error-notification", n.gridTradingURL)
+	notification.Seq = n.sequence(models.NotificationKindError, notification.OrderID, notification)
+	n.stream.publish(OrderEvent{Error: &notification})
 
 	jsonData, err := json.Marshal(notification)
 	if err != nil {
 		return fmt.Errorf("failed to marshal notification: %w", err)
 	}
 
-	for attempt := 1; attempt <= n.maxRetries; attempt++ {
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
-		}
+	if n.chaos.DropNotification() {
+		slog.Warn("[CHAOS] Dropped error notification", logging.KeyOrderID, notification.OrderID)
+		return nil
+	}
 
-		req.Header.Set("Content-Type", "application/json")
+	if err := n.sendWithRetries(models.NotificationKindError, jsonData); err != nil {
+		n.persist(models.NotificationKindError, notification.OrderID, jsonData, err)
+		return err
+	}
 
-		resp, err := n.client.Do(req)
-		if err != nil {
-			if attempt < n.maxRetries {
-				log.Printf("Failed to send error notification (attempt %d/%d): %v", attempt, n.maxRetries, err)
-				time.Sleep(n.retryDelay * time.Duration(attempt))
-				continue
-			}
-			return fmt.Errorf("failed to send notification after %d attempts: %w", n.maxRetries, err)
-		}
-		defer resp.Body.Close()
+	slog.Info("Sent error notification to grid-trading", logging.KeyOrderID, notification.OrderID,
+		logging.KeySymbol, notification.Symbol, "side", notification.Side, "code", notification.ErrorCode)
+	return nil
+}
 
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			log.Printf("Successfully sent error notification for order %s", notification.OrderID)
+// SendExchangeStatus tells grid-trading whether the exchange is degraded. A failed
+// delivery is not outboxed - the monitor sends the current status again on its next check.
+func (n *Notifier) SendExchangeStatus(status contracts.ExchangeStatus) error {
+	jsonData, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal exchange status: %w", err)
+	}
+	return n.send(models.NotificationKindExchangeStatus, jsonData)
+}
+
+// sequence logs a notification before its first delivery attempt - even one that is dropped or
+// ends up in the outbox can be replayed - and returns its number, 0 if it could not be logged
+func (n *Notifier) sequence(kind, orderID string, notification interface{}) int64 {
+	if n.log == nil {
+		return 0
+	}
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return 0
+	}
+	seq, err := n.log.Append(kind, orderID, string(payload))
+	if err != nil {
+		slog.Error("Failed to log notification, sending it without a sequence number", "kind", kind,
+			logging.KeyOrderID, orderID, logging.Err(err))
+		return 0
+	}
+	return seq
+}
+
+// Redeliver makes a single delivery attempt for a persisted outbox entry
+func (n *Notifier) Redeliver(entry *models.OutboxEntry) error {
+	return n.send(entry.Kind, []byte(entry.Payload))
+}
+
+func (n *Notifier) sendWithRetries(kind string, jsonData []byte) error {
+	// An acknowledged publish is already durable - a failed one goes straight to the outbox
+	if n.js != nil {
+		return n.send(kind, jsonData)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= n.maxRetries; attempt++ {
+		lastErr = n.send(kind, jsonData)
+		if lastErr == nil {
 			return nil
 		}
 
 		if attempt < n.maxRetries {
-			log.Printf("Received status %d for error notification (attempt %d/%d)", resp.StatusCode, attempt, n.maxRetries)
+			slog.Warn("Failed to send notification", "kind", kind, "attempt", attempt, "max_attempts", n.maxRetries, logging.Err(lastErr))
 			time.Sleep(n.retryDelay * time.Duration(attempt))
-			continue
 		}
+	}
+
+	return fmt.Errorf("failed to send notification after %d attempts: %w", n.maxRetries, lastErr)
+}
+
+func (n *Notifier) send(kind string, jsonData []byte) error {
+	path := "/order-fill-notification"
+	subject := contracts.FillSubject
+	switch kind {
+	case models.NotificationKindError:
+		path = "/order-fill-error-notification"
+		subject = contracts.ErrorSubject
+	case models.NotificationKindExchangeStatus:
+		path = "/exchange-status"
+		subject = contracts.ExchangeStatusSubject
+	}
+
+	if n.chaos.FailNotification() {
+		return fmt.Errorf("chaos: injected delivery failure")
+	}
+
+	if n.js != nil {
+		if _, err := n.js.Publish(subject, jsonData); err != nil {
+			return fmt.Errorf("failed to publish to %s: %w", subject, err)
+		}
+		return nil
+	}
+
+	req, err := http.NewRequest("POST", n.gridTradingURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", n.apiKey)
 
-		return fmt.Errorf("failed with status %d after %d attempts", resp.StatusCode, n.maxRetries)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("received status %d", resp.StatusCode)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// persist hands an undelivered notification to the outbox for scheduled redelivery
+func (n *Notifier) persist(kind, orderID string, jsonData []byte, sendErr error) {
+	if n.outbox == nil {
+		return
+	}
+
+	if err := n.outbox.Enqueue(kind, orderID, string(jsonData), sendErr.Error()); err != nil {
+		slog.Error("Notification lost - failed to persist to outbox", "kind", kind, logging.KeyOrderID, orderID, logging.Err(err))
+	}
+}
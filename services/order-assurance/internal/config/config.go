@@ -1,14 +1,84 @@
 package config
 
 import (
+	"log"
 	"os"
+	"strconv"
+	"strings"
 )
 
+// KeyPair is a backup Binance API key used when the primary one fails
+type KeyPair struct {
+	APIKey    string
+	APISecret string
+}
+
+// SubAccount holds credentials for a Binance sub-account orders can be routed to
+type SubAccount struct {
+	Name       string
+	APIKey     string
+	APISecret  string
+	BackupKeys []KeyPair
+}
+
 type Config struct {
-	ServerPort     string
-	BinanceAPIKey  string
-	BinanceSecret  string
-	GridTradingURL string
+	ServerPort          string
+	GRPCPort            string // Serve the gRPC API on this port too (empty = off)
+	DBPath              string
+	Exchange            string // binance | kraken (EXCHANGE)
+	Kraken              KrakenConfig
+	BinanceAPIKey       string
+	BinanceSecret       string
+	BinanceAPIURL       string
+	Testnet             bool // Default every Binance URL to the testnet (BINANCE_TESTNET)
+	BackupKeys          []KeyPair
+	SubAccounts         []SubAccount
+	WSAPIEnabled        bool
+	WSAPIURL            string
+	TradeCaptureEnabled bool
+	UserStreamURL       string
+	GridTradingURL      string
+	GridTradingAPIKey   string // Shared key grid-trading requires on notifications (GRID_TRADING_API_KEY)
+	Transport           string // Notifications over http (webhooks) or nats (JetStream)
+	NATSURL             string
+	APIKey              string
+	AuthDisabled        bool // Explicit opt-out of API key auth (ORDER_ASSURANCE_AUTH_DISABLED)
+	TTLCheckIntervalSec int
+	SymbolRefreshMin    int
+	OutboxRetrySec      int
+	NotificationLogDays int // Days numbered notifications are kept for replay (0 = forever)
+	ExchangeStatusSec   int // Binance system status check interval (0 = off)
+	RateLimitMaxWaitSec int // Longest a Binance request waits for the rate limits before failing
+	RateLimitRetries    int // Binance 429s retried after their Retry-After (0 = none)
+	WithdrawAddress     string
+	WithdrawNetwork     string
+	Futures             FuturesConfig
+	Margin              MarginConfig
+	Paper               PaperConfig
+	Chaos               ChaosConfig
+}
+
+// maxFuturesLeverage keeps futures grids at low leverage - a grid holds through drawdowns
+// that would liquidate a highly leveraged position
+const maxFuturesLeverage = 5
+
+// FuturesConfig enables the USDT-M perpetuals account
+type FuturesConfig struct {
+	Enabled   bool
+	APIURL    string
+	Leverage  int
+	HedgeMode bool
+}
+
+// ChaosConfig enables fault injection for exercising grid-trading's recovery paths
+type ChaosConfig struct {
+	Enabled         bool
+	LatencyMinMs    int
+	LatencyMaxMs    int
+	RateLimitRate   float64
+	ServerErrorRate float64
+	NotifyFailRate  float64
+	NotifyDropRate  float64
 }
 
 func LoadConfig() *Config {
@@ -17,18 +87,332 @@ func LoadConfig() *Config {
 		serverPort = "9090" // Only default kept for local dev
 	}
 
+	dbPath := os.Getenv("DB_PATH")
+	if dbPath == "" {
+		dbPath = "./order_assurance.db" // Only default kept for local dev
+	}
+
 	apiKey := os.Getenv("BINANCE_API_KEY")
 	apiSecret := os.Getenv("BINANCE_API_SECRET")
 
+	// Testnet switches the URL defaults - an explicit URL setting still wins
+	testnet, _ := strconv.ParseBool(os.Getenv("BINANCE_TESTNET"))
+
+	binanceAPIURL := os.Getenv("BINANCE_API_URL")
+	if binanceAPIURL == "" {
+		binanceAPIURL = "https://api.binance.com"
+		if testnet {
+			binanceAPIURL = "https://testnet.binance.vision"
+		}
+	}
+
+	wsAPIEnabled, _ := strconv.ParseBool(os.Getenv("BINANCE_WS_API_ENABLED"))
+
+	wsAPIURL := os.Getenv("BINANCE_WS_API_URL")
+	if wsAPIURL == "" {
+		wsAPIURL = "wss://ws-api.binance.com:443/ws-api/v3"
+		if testnet {
+			wsAPIURL = "wss://ws-api.testnet.binance.vision/ws-api/v3"
+		}
+	}
+
+	tradeCaptureEnabled, _ := strconv.ParseBool(os.Getenv("BINANCE_USER_STREAM_ENABLED"))
+
+	userStreamURL := os.Getenv("BINANCE_USER_STREAM_URL")
+	if userStreamURL == "" {
+		userStreamURL = "wss://stream.binance.com:9443/ws"
+		if testnet {
+			userStreamURL = "wss://stream.testnet.binance.vision/ws"
+		}
+	}
+
 	gridTradingURL := os.Getenv("GRID_TRADING_URL")
 	if gridTradingURL == "" {
 		gridTradingURL = "http://localhost:8080" // Only default kept for local dev
 	}
 
+	ttlCheckInterval := 10
+	if v := os.Getenv("TTL_CHECK_INTERVAL_SEC"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			ttlCheckInterval = parsed
+		}
+	}
+
+	symbolRefresh := 60
+	if v := os.Getenv("SYMBOL_INFO_REFRESH_MIN"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			symbolRefresh = parsed
+		}
+	}
+
+	outboxRetry := 30
+	if v := os.Getenv("OUTBOX_RETRY_INTERVAL_SEC"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			outboxRetry = parsed
+		}
+	}
+
+	notificationLogDays := 7
+	if v := os.Getenv("NOTIFICATION_LOG_RETENTION_DAYS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("NOTIFICATION_LOG_RETENTION_DAYS must be a non-negative integer")
+		}
+		notificationLogDays = parsed
+	}
+
+	rateLimitMaxWait := 10
+	if v := os.Getenv("RATE_LIMIT_MAX_WAIT_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("RATE_LIMIT_MAX_WAIT_SEC must be a non-negative number of seconds")
+		}
+		rateLimitMaxWait = parsed
+	}
+
+	rateLimitRetries := 2
+	if v := os.Getenv("RATE_LIMIT_RETRIES"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("RATE_LIMIT_RETRIES must be a non-negative integer")
+		}
+		rateLimitRetries = parsed
+	}
+
+	exchangeStatus := 30
+	if v := os.Getenv("EXCHANGE_STATUS_INTERVAL_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("EXCHANGE_STATUS_INTERVAL_SEC must be a non-negative number of seconds")
+		}
+		exchangeStatus = parsed
+	}
+
+	transport := os.Getenv("TRANSPORT")
+	if transport == "" {
+		transport = "http"
+	}
+	if transport != "http" && transport != "nats" {
+		log.Fatal("TRANSPORT must be http or nats")
+	}
+
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://localhost:4222"
+	}
+
+	// Shared key required from callers of order endpoints. Running without one must be
+	// asked for explicitly, so a missing key can't silently expose order placement
+	assuranceAPIKey := os.Getenv("ORDER_ASSURANCE_API_KEY")
+	authDisabled, _ := strconv.ParseBool(os.Getenv("ORDER_ASSURANCE_AUTH_DISABLED"))
+	if assuranceAPIKey == "" && !authDisabled {
+		log.Fatal("ORDER_ASSURANCE_API_KEY is required (set ORDER_ASSURANCE_AUTH_DISABLED=true to run unauthenticated)")
+	}
+
+	futures := loadFuturesConfig(testnet)
+	margin := loadMarginConfig(testnet)
+
+	// Paper trading simulates spot orders sent over REST; nothing signed reaches Binance
+	paper := loadPaperConfig()
+	if paper.Enabled {
+		if futures.Enabled || margin.Enabled || wsAPIEnabled || tradeCaptureEnabled {
+			log.Fatal("TRADING_MODE=paper is spot only - turn off FUTURES_ENABLED, MARGIN_ENABLED, BINANCE_WS_API_ENABLED and BINANCE_USER_STREAM_ENABLED")
+		}
+		if apiKey == "" || apiSecret == "" {
+			apiKey, apiSecret = "paper", "paper"
+		}
+	}
+
+	// Kraken trades the master spot account through the common order flow only
+	venue, kraken := loadExchange()
+	subAccounts := loadSubAccounts()
+	if venue == "kraken" && (futures.Enabled || margin.Enabled || paper.Enabled || wsAPIEnabled || tradeCaptureEnabled || len(subAccounts) > 0) {
+		log.Fatal("EXCHANGE=kraken is spot master account only - turn off FUTURES_ENABLED, MARGIN_ENABLED, TRADING_MODE=paper, BINANCE_WS_API_ENABLED, BINANCE_USER_STREAM_ENABLED and BINANCE_SUB_ACCOUNTS")
+	}
+
 	return &Config{
-		ServerPort:     serverPort,
-		BinanceAPIKey:  apiKey,
-		BinanceSecret:  apiSecret,
-		GridTradingURL: gridTradingURL,
+		ServerPort:          serverPort,
+		GRPCPort:            os.Getenv("GRPC_PORT"),
+		DBPath:              dbPath,
+		Exchange:            venue,
+		Kraken:              kraken,
+		BinanceAPIKey:       apiKey,
+		BinanceSecret:       apiSecret,
+		BinanceAPIURL:       binanceAPIURL,
+		Testnet:             testnet,
+		BackupKeys:          parseKeyPairs("BINANCE_BACKUP_API_KEYS"),
+		SubAccounts:         subAccounts,
+		WSAPIEnabled:        wsAPIEnabled,
+		WSAPIURL:            wsAPIURL,
+		TradeCaptureEnabled: tradeCaptureEnabled,
+		UserStreamURL:       userStreamURL,
+		GridTradingURL:      gridTradingURL,
+		GridTradingAPIKey:   os.Getenv("GRID_TRADING_API_KEY"),
+		Transport:           transport,
+		NATSURL:             natsURL,
+		APIKey:              assuranceAPIKey,
+		AuthDisabled:        authDisabled,
+		TTLCheckIntervalSec: ttlCheckInterval,
+		SymbolRefreshMin:    symbolRefresh,
+		OutboxRetrySec:      outboxRetry,
+		NotificationLogDays: notificationLogDays,
+		ExchangeStatusSec:   exchangeStatus,
+		RateLimitMaxWaitSec: rateLimitMaxWait,
+		RateLimitRetries:    rateLimitRetries,
+		WithdrawAddress:     os.Getenv("PROFIT_SWEEP_WITHDRAW_ADDRESS"),
+		WithdrawNetwork:     os.Getenv("PROFIT_SWEEP_WITHDRAW_NETWORK"),
+		Futures:             futures,
+		Margin:              margin,
+		Paper:               paper,
+		Chaos:               loadChaosConfig(),
+	}
+}
+
+// MarginConfig enables the cross or isolated margin account
+type MarginConfig struct {
+	Enabled    bool
+	Isolated   bool
+	AutoBorrow bool
+}
+
+// loadMarginConfig reads the MARGIN_* settings. The spot testnet has no margin endpoints.
+func loadMarginConfig(testnet bool) MarginConfig {
+	enabled, _ := strconv.ParseBool(os.Getenv("MARGIN_ENABLED"))
+	if enabled && testnet {
+		log.Fatal("MARGIN_ENABLED is not supported with BINANCE_TESTNET")
+	}
+	autoBorrow, _ := strconv.ParseBool(os.Getenv("MARGIN_AUTO_BORROW"))
+
+	mode := os.Getenv("MARGIN_MODE")
+	if mode == "" {
+		mode = "cross"
+	}
+	if mode != "cross" && mode != "isolated" {
+		log.Fatal("MARGIN_MODE must be cross or isolated")
+	}
+
+	return MarginConfig{
+		Enabled:    enabled,
+		Isolated:   mode == "isolated",
+		AutoBorrow: autoBorrow,
+	}
+}
+
+// loadFuturesConfig reads the FUTURES_* settings, failing on leverage above maxFuturesLeverage
+func loadFuturesConfig(testnet bool) FuturesConfig {
+	enabled, _ := strconv.ParseBool(os.Getenv("FUTURES_ENABLED"))
+
+	apiURL := os.Getenv("BINANCE_FUTURES_API_URL")
+	if apiURL == "" {
+		apiURL = "https://fapi.binance.com"
+		if testnet {
+			apiURL = "https://testnet.binancefuture.com"
+		}
+	}
+
+	leverage := 2
+	if v := os.Getenv("FUTURES_LEVERAGE"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxFuturesLeverage {
+			log.Fatalf("FUTURES_LEVERAGE must be between 1 and %d", maxFuturesLeverage)
+		}
+		leverage = parsed
+	}
+
+	mode := os.Getenv("FUTURES_POSITION_MODE")
+	if mode == "" {
+		mode = "one-way"
+	}
+	if mode != "one-way" && mode != "hedge" {
+		log.Fatal("FUTURES_POSITION_MODE must be one-way or hedge")
+	}
+
+	return FuturesConfig{
+		Enabled:   enabled,
+		APIURL:    apiURL,
+		Leverage:  leverage,
+		HedgeMode: mode == "hedge",
+	}
+}
+
+// loadChaosConfig reads the CHAOS_* testing settings
+func loadChaosConfig() ChaosConfig {
+	enabled, _ := strconv.ParseBool(os.Getenv("CHAOS_ENABLED"))
+	return ChaosConfig{
+		Enabled:         enabled,
+		LatencyMinMs:    parseNonNegativeInt("CHAOS_LATENCY_MIN_MS"),
+		LatencyMaxMs:    parseNonNegativeInt("CHAOS_LATENCY_MAX_MS"),
+		RateLimitRate:   parseRate("CHAOS_RATE_LIMIT_RATE"),
+		ServerErrorRate: parseRate("CHAOS_SERVER_ERROR_RATE"),
+		NotifyFailRate:  parseRate("CHAOS_NOTIFICATION_FAIL_RATE"),
+		NotifyDropRate:  parseRate("CHAOS_NOTIFICATION_DROP_RATE"),
+	}
+}
+
+func parseNonNegativeInt(name string) int {
+	if v := os.Getenv(name); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed >= 0 {
+			return parsed
+		}
+		log.Printf("WARNING: Invalid %s=%q ignored", name, v)
+	}
+	return 0
+}
+
+// parseRate reads a probability between 0 and 1
+func parseRate(name string) float64 {
+	if v := os.Getenv(name); v != "" {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil && parsed >= 0 && parsed <= 1 {
+			return parsed
+		}
+		log.Printf("WARNING: Invalid %s=%q ignored (expected 0-1)", name, v)
+	}
+	return 0
+}
+
+// loadSubAccounts reads BINANCE_SUB_ACCOUNTS (comma-separated names) and the
+// BINANCE_API_KEY_<NAME> / BINANCE_API_SECRET_<NAME> pair for each
+func loadSubAccounts() []SubAccount {
+	var subAccounts []SubAccount
+	for _, name := range strings.Split(os.Getenv("BINANCE_SUB_ACCOUNTS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		suffix := strings.ToUpper(name)
+		apiKey := os.Getenv("BINANCE_API_KEY_" + suffix)
+		apiSecret := os.Getenv("BINANCE_API_SECRET_" + suffix)
+		if apiKey == "" || apiSecret == "" {
+			log.Printf("WARNING: Sub-account %s skipped - BINANCE_API_KEY_%s / BINANCE_API_SECRET_%s not set", name, suffix, suffix)
+			continue
+		}
+
+		subAccounts = append(subAccounts, SubAccount{
+			Name:       name,
+			APIKey:     apiKey,
+			APISecret:  apiSecret,
+			BackupKeys: parseKeyPairs("BINANCE_BACKUP_API_KEYS_" + suffix),
+		})
+	}
+	return subAccounts
+}
+
+// parseKeyPairs reads comma-separated key:secret pairs from env
+func parseKeyPairs(env string) []KeyPair {
+	var pairs []KeyPair
+	for _, entry := range strings.Split(os.Getenv(env), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		apiKey, apiSecret, ok := strings.Cut(entry, ":")
+		if !ok || apiKey == "" || apiSecret == "" {
+			log.Printf("WARNING: Ignoring malformed entry in %s - expected key:secret", env)
+			continue
+		}
+		pairs = append(pairs, KeyPair{APIKey: apiKey, APISecret: apiSecret})
 	}
+	return pairs
 }
\ No newline at end of file
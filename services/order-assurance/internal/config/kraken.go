@@ -0,0 +1,34 @@
+package config
+
+import (
+	"log"
+	"os"
+)
+
+// KrakenConfig holds the Kraken spot account orders go to with EXCHANGE=kraken
+type KrakenConfig struct {
+	APIKey    string
+	APISecret string // Base64, as Kraken issues it
+	APIURL    string
+}
+
+// loadExchange reads EXCHANGE and, for Kraken, the KRAKEN_* settings
+func loadExchange() (string, KrakenConfig) {
+	venue := os.Getenv("EXCHANGE")
+	if venue == "" {
+		venue = "binance"
+	}
+	if venue != "binance" && venue != "kraken" {
+		log.Fatal("EXCHANGE must be binance or kraken")
+	}
+
+	kraken := KrakenConfig{
+		APIKey:    os.Getenv("KRAKEN_API_KEY"),
+		APISecret: os.Getenv("KRAKEN_API_SECRET"),
+		APIURL:    os.Getenv("KRAKEN_API_URL"),
+	}
+	if kraken.APIURL == "" {
+		kraken.APIURL = "https://api.kraken.com"
+	}
+	return venue, kraken
+}
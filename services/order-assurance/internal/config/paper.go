@@ -0,0 +1,72 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaperConfig enables paper trading: orders and balances are simulated against live prices
+type PaperConfig struct {
+	Enabled         bool
+	Balances        map[string]decimal.Decimal // Starting balances of every account
+	CommissionRate  decimal.Decimal
+	FillIntervalSec int
+}
+
+// loadPaperConfig reads TRADING_MODE and the PAPER_* settings
+func loadPaperConfig() PaperConfig {
+	mode := os.Getenv("TRADING_MODE")
+	if mode == "" {
+		mode = "live"
+	}
+	if mode != "live" && mode != "paper" {
+		log.Fatal("TRADING_MODE must be live or paper")
+	}
+
+	// ASSET:amount,ASSET:amount
+	spec := os.Getenv("PAPER_BALANCES")
+	if spec == "" {
+		spec = "USDT:10000"
+	}
+	balances := make(map[string]decimal.Decimal)
+	for _, entry := range strings.Split(spec, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		asset, amount, ok := strings.Cut(entry, ":")
+		value, err := decimal.NewFromString(strings.TrimSpace(amount))
+		if !ok || err != nil || value.IsNegative() {
+			log.Fatal("PAPER_BALANCES must be ASSET:amount pairs, e.g. USDT:10000,ETH:1")
+		}
+		balances[strings.ToUpper(strings.TrimSpace(asset))] = value
+	}
+
+	commission := decimal.NewFromFloat(0.001)
+	if v := os.Getenv("PAPER_COMMISSION_RATE"); v != "" {
+		parsed, err := decimal.NewFromString(v)
+		if err != nil || parsed.IsNegative() || parsed.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+			log.Fatal("PAPER_COMMISSION_RATE must be a fraction between 0 and 1 (0.001 = 0.1%)")
+		}
+		commission = parsed
+	}
+
+	fillInterval := 5
+	if v := os.Getenv("PAPER_FILL_INTERVAL_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			log.Fatal("PAPER_FILL_INTERVAL_SEC must be a positive number of seconds")
+		}
+		fillInterval = parsed
+	}
+
+	return PaperConfig{
+		Enabled:         mode == "paper",
+		Balances:        balances,
+		CommissionRate:  commission,
+		FillIntervalSec: fillInterval,
+	}
+}
@@ -0,0 +1,175 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// GetFreeBalances retrieves free (unlocked) balances for all assets
+func (bc *BinanceClient) GetFreeBalances() (map[string]decimal.Decimal, error) {
+	// Check if we have credentials
+	if !bc.hasCredentials() {
+		return nil, fmt.Errorf("Binance API credentials not configured - cannot get account balances")
+	}
+
+	if bc.futures != nil {
+		return bc.getFuturesBalances()
+	}
+	if bc.margin != nil {
+		return bc.getMarginBalances()
+	}
+
+	params := url.Values{}
+	params.Set("omitZeroBalances", "true")
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	params.Set("recvWindow", "5000")
+
+	apiKey := bc.signParams(params)
+
+	req, err := http.NewRequest("GET", bc.baseURL+"/api/v3/account?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-MBX-APIKEY", apiKey)
+
+	resp, err := bc.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseBinanceError(resp.StatusCode, body)
+	}
+
+	var account struct {
+		Balances []struct {
+			Asset string `json:"asset"`
+			Free  string `json:"free"`
+		} `json:"balances"`
+	}
+	if err := json.Unmarshal(body, &account); err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]decimal.Decimal, len(account.Balances))
+	for _, b := range account.Balances {
+		free, err := decimal.NewFromString(b.Free)
+		if err != nil {
+			continue
+		}
+		balances[b.Asset] = free
+	}
+
+	return balances, nil
+}
+
+// getCachedBalances returns free balances, refreshing the cache when stale
+func (bc *BinanceClient) getCachedBalances() (map[string]decimal.Decimal, error) {
+	bc.balancesMutex.RLock()
+	if time.Since(bc.balancesTime) < bc.balancesTTL {
+		balances := bc.balances
+		bc.balancesMutex.RUnlock()
+		return balances, nil
+	}
+	bc.balancesMutex.RUnlock()
+
+	balances, err := bc.GetFreeBalances()
+	if err != nil {
+		return nil, err
+	}
+
+	bc.balancesMutex.Lock()
+	bc.balances = balances
+	bc.balancesTime = time.Now()
+	bc.balancesMutex.Unlock()
+
+	return balances, nil
+}
+
+// invalidateBalances forces the next balance check to refetch from Binance
+func (bc *BinanceClient) invalidateBalances() {
+	bc.balancesMutex.Lock()
+	bc.balancesTime = time.Time{}
+	bc.balancesMutex.Unlock()
+}
+
+// checkBalance verifies the free balance covers the order, returning an
+// insufficient_funds OrderError with the shortfall if it does not.
+// Balance lookup failures are logged and do not block the order.
+func (bc *BinanceClient) checkBalance(info *SymbolInfo, side models.OrderSide, price, quantity decimal.Decimal) error {
+	asset := info.QuoteAsset
+	required := price.Mul(quantity)
+	if side == models.SideSell {
+		asset = info.BaseAsset
+		required = quantity
+	} else if bc.futures != nil {
+		// Futures buys only lock the initial margin
+		required = required.Div(decimal.NewFromInt(int64(bc.futures.Leverage)))
+	} else if bc.margin != nil && bc.margin.AutoBorrow {
+		// Margin buys borrow whatever the free balance doesn't cover - Binance checks the borrow limit
+		return nil
+	}
+
+	if asset == "" {
+		return nil
+	}
+
+	balances, err := bc.getCachedBalances()
+	if err != nil {
+		slog.Warn("Balance pre-check skipped, failed to fetch balances", logging.Err(err))
+		return nil
+	}
+
+	free := balances[asset]
+	if free.GreaterThanOrEqual(required) {
+		return nil
+	}
+
+	// Cached balance may predate a recent fill - confirm with a fresh read before rejecting
+	bc.invalidateBalances()
+	if balances, err = bc.getCachedBalances(); err != nil {
+		slog.Warn("Balance pre-check skipped, failed to refresh balances", logging.Err(err))
+		return nil
+	}
+
+	free = balances[asset]
+	if free.GreaterThanOrEqual(required) {
+		return nil
+	}
+
+	return insufficientFunds(asset, required, free)
+}
+
+// insufficientFunds is the insufficient_funds OrderError of a balance pre-check, with the shortfall
+func insufficientFunds(asset string, required, free decimal.Decimal) *OrderError {
+	shortfall := required.Sub(free)
+	slog.Warn("Insufficient balance", "asset", asset, "required", required, "free", free, "shortfall", shortfall)
+
+	return &OrderError{
+		Code:    ErrInsufficientFunds,
+		Message: fmt.Sprintf("insufficient %s balance: required %s, free %s, shortfall %s", asset, required, free, shortfall),
+		Details: map[string]string{
+			"asset":     asset,
+			"required":  required.String(),
+			"free":      free.String(),
+			"shortfall": shortfall.String(),
+		},
+	}
+}
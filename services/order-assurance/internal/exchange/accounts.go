@@ -0,0 +1,86 @@
+package exchange
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Accounts routes requests to the master account or a named Binance sub-account.
+// Each account has its own client, so balances, idempotency caches and breakers stay segregated.
+type Accounts struct {
+	master Exchange
+	subs   map[string]Exchange
+}
+
+func NewAccounts(master Exchange) *Accounts {
+	return &Accounts{
+		master: master,
+		subs:   make(map[string]Exchange),
+	}
+}
+
+// Add registers a sub-account client under name
+func (a *Accounts) Add(name string, client Exchange) {
+	a.subs[name] = client
+}
+
+// Master returns the default account client
+func (a *Accounts) Master() Exchange {
+	return a.master
+}
+
+// Get returns the client for account, where an empty name means the master account
+func (a *Accounts) Get(account string) (Exchange, error) {
+	if account == "" {
+		return a.master, nil
+	}
+
+	client, ok := a.subs[account]
+	if !ok {
+		return nil, &OrderError{
+			Code:    ErrUnknownAccount,
+			Message: fmt.Sprintf("sub-account %q is not configured", account),
+			Details: map[string]string{"account": account},
+		}
+	}
+	return client, nil
+}
+
+// Binance returns the account's client for the Binance-only features, failing on other venues
+func (a *Accounts) Binance(account string) (*BinanceClient, error) {
+	client, err := a.Get(account)
+	if err != nil {
+		return nil, err
+	}
+
+	binance, ok := client.(*BinanceClient)
+	if !ok {
+		return nil, &OrderError{
+			Code:    ErrUnsupportedVenue,
+			Message: fmt.Sprintf("account %q is not on Binance - only limit orders, order status, cancels and balances are supported", account),
+			Details: map[string]string{"account": account},
+		}
+	}
+	return binance, nil
+}
+
+// Names returns configured sub-account names in sorted order
+func (a *Accounts) Names() []string {
+	names := make([]string, 0, len(a.subs))
+	for name := range a.subs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// All returns every Binance client, master first
+func (a *Accounts) All() []*BinanceClient {
+	var clients []*BinanceClient
+	for _, name := range append([]string{""}, a.Names()...) {
+		if binance, err := a.Binance(name); err == nil {
+			clients = append(clients, binance)
+		}
+	}
+	return clients
+}
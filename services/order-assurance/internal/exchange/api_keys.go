@@ -0,0 +1,256 @@
+package exchange
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// keyCooldown keeps a failed key out of automatic rotation for a while
+const keyCooldown = 10 * time.Minute
+
+// APIKeyPair is one set of Binance credentials for an account
+type APIKeyPair struct {
+	Label     string
+	APIKey    string
+	APISecret string
+}
+
+// KeyStatus is a snapshot of a key's health for the admin API (secrets never leave the ring)
+type KeyStatus struct {
+	Label         string `json:"label"`
+	APIKey        string `json:"api_key"` // Masked
+	Active        bool   `json:"active"`
+	Healthy       bool   `json:"healthy"`
+	Failures      int    `json:"consecutive_failures"`
+	LastError     string `json:"last_error,omitempty"`
+	LastFailureAt string `json:"last_failure_at,omitempty"`
+	CooldownUntil string `json:"cooldown_until,omitempty"`
+}
+
+type keyState struct {
+	pair          APIKeyPair
+	failures      int
+	lastError     string
+	lastFailureAt time.Time
+	cooldownUntil time.Time
+}
+
+// keyRing holds the primary and backup keys of one account and fails over between them
+type keyRing struct {
+	mu     sync.Mutex
+	keys   []*keyState
+	active int
+}
+
+func newKeyRing(pairs []APIKeyPair) *keyRing {
+	ring := &keyRing{}
+	for _, pair := range pairs {
+		ring.keys = append(ring.keys, &keyState{pair: pair})
+	}
+	return ring
+}
+
+// current returns the key pair new requests should be signed with
+func (k *keyRing) current() APIKeyPair {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.keys[k.active].pair
+}
+
+// secretOf returns the secret paired with apiKey
+func (k *keyRing) secretOf(apiKey string) (string, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if state := k.find(apiKey); state != nil {
+		return state.pair.APISecret, true
+	}
+	return "", false
+}
+
+// recordSuccess clears the failure count of a key
+func (k *keyRing) recordSuccess(apiKey string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if state := k.find(apiKey); state != nil {
+		state.failures = 0
+	}
+}
+
+// recordFailure marks a key unhealthy and fails over if it is the active one
+func (k *keyRing) recordFailure(apiKey, reason string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	state := k.find(apiKey)
+	if state == nil {
+		return
+	}
+
+	state.failures++
+	state.lastError = reason
+	state.lastFailureAt = time.Now()
+	state.cooldownUntil = time.Now().Add(keyCooldown)
+
+	// Requests signed before a rotation may still fail with the old key
+	if k.keys[k.active] != state {
+		return
+	}
+
+	next := k.nextHealthy()
+	if next < 0 {
+		slog.Error("API key failed and no healthy backup key is available", "key", state.pair.Label, "reason", reason)
+		return
+	}
+
+	slog.Warn("API key failed, rotating", "key", state.pair.Label, "reason", reason, "next_key", k.keys[next].pair.Label)
+	k.active = next
+}
+
+// rotate switches to the next key on demand, preferring healthy ones
+func (k *keyRing) rotate() (APIKeyPair, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if len(k.keys) < 2 {
+		return APIKeyPair{}, fmt.Errorf("no backup API key configured")
+	}
+
+	next := k.nextHealthy()
+	if next < 0 {
+		// Everything is cooling down - still honour an explicit operator request
+		next = (k.active + 1) % len(k.keys)
+	}
+
+	slog.Info("Rotating API key on request", "key", k.keys[k.active].pair.Label, "next_key", k.keys[next].pair.Label)
+	k.active = next
+	return k.keys[next].pair, nil
+}
+
+func (k *keyRing) statuses() []KeyStatus {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]KeyStatus, 0, len(k.keys))
+	for i, state := range k.keys {
+		status := KeyStatus{
+			Label:     state.pair.Label,
+			APIKey:    maskKey(state.pair.APIKey),
+			Active:    i == k.active,
+			Healthy:   now.After(state.cooldownUntil),
+			Failures:  state.failures,
+			LastError: state.lastError,
+		}
+		if !state.lastFailureAt.IsZero() {
+			status.LastFailureAt = state.lastFailureAt.Format(time.RFC3339)
+		}
+		if !status.Healthy {
+			status.CooldownUntil = state.cooldownUntil.Format(time.RFC3339)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// nextHealthy returns the index of the next key after the active one that is out of cooldown, or -1
+func (k *keyRing) nextHealthy() int {
+	now := time.Now()
+	for offset := 1; offset < len(k.keys); offset++ {
+		i := (k.active + offset) % len(k.keys)
+		if now.After(k.keys[i].cooldownUntil) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (k *keyRing) find(apiKey string) *keyState {
+	for _, state := range k.keys {
+		if state.pair.APIKey == apiKey {
+			return state
+		}
+	}
+	return nil
+}
+
+func maskKey(apiKey string) string {
+	if len(apiKey) <= 8 {
+		return "****"
+	}
+	return apiKey[:4] + "****" + apiKey[len(apiKey)-4:]
+}
+
+// hasCredentials reports whether the active key pair is configured
+func (bc *BinanceClient) hasCredentials() bool {
+	pair := bc.keys.current()
+	return pair.APIKey != "" && pair.APISecret != ""
+}
+
+// trackKeyHealth fails over to a backup key on auth failures and IP bans
+func (bc *BinanceClient) trackKeyHealth(apiKey string, resp *http.Response) {
+	switch {
+	case resp.StatusCode < 300:
+		bc.keys.recordSuccess(apiKey)
+	case resp.StatusCode == http.StatusTeapot:
+		bc.keys.recordFailure(apiKey, "IP banned (HTTP 418)")
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		bc.keys.recordFailure(apiKey, fmt.Sprintf("HTTP %d", resp.StatusCode))
+	case resp.StatusCode == http.StatusBadRequest:
+		// Peek at the error code and restore the body for the caller
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+
+		var errResp struct {
+			Code int    `json:"code"`
+			Msg  string `json:"msg"`
+		}
+		if json.Unmarshal(body, &errResp) == nil && classifyBinanceError(resp.StatusCode, errResp.Code, errResp.Msg) == ErrAuthFailed {
+			bc.keys.recordFailure(apiKey, fmt.Sprintf("code %d: %s", errResp.Code, errResp.Msg))
+		}
+	}
+}
+
+// trackWSKeyHealth applies the same failover to WebSocket API results
+func (bc *BinanceClient) trackWSKeyHealth(apiKey string, err error) {
+	var orderErr *OrderError
+	switch {
+	case err == nil:
+		bc.keys.recordSuccess(apiKey)
+	case errors.As(err, &orderErr) && (orderErr.Code == ErrAuthFailed || orderErr.HTTPStatus == http.StatusTeapot):
+		bc.keys.recordFailure(apiKey, orderErr.Message)
+	}
+}
+
+// RotateAPIKey switches to the next configured key without interrupting in-flight requests
+func (bc *BinanceClient) RotateAPIKey() (KeyStatus, error) {
+	pair, err := bc.keys.rotate()
+	if err != nil {
+		return KeyStatus{}, err
+	}
+	bc.invalidateBalances()
+
+	for _, status := range bc.keys.statuses() {
+		if status.Label == pair.Label {
+			return status, nil
+		}
+	}
+	return KeyStatus{}, fmt.Errorf("rotated key %s not found", pair.Label)
+}
+
+// APIKeyStatuses returns the health of every configured key
+func (bc *BinanceClient) APIKeyStatuses() []KeyStatus {
+	return bc.keys.statuses()
+}
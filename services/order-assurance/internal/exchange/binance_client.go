@@ -5,9 +5,10 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -15,30 +16,37 @@ import (
 	"sync"
 	"time"
 
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/chaos"
 	"github.com/grid-trading-bot/services/order-assurance/internal/models"
 	"github.com/shopspring/decimal"
 )
 
 const (
 	BinanceAPIURL = "https://api.binance.com"
+
+	// symbolInfoMaxAge forces a synchronous refetch if the background refresher falls behind
+	symbolInfoMaxAge = 24 * time.Hour
 )
 
 // SymbolInfo contains trading rules for a symbol
 type SymbolInfo struct {
-	MinQty   decimal.Decimal // Minimum order quantity
-	MaxQty   decimal.Decimal // Maximum order quantity
-	StepSize decimal.Decimal // Quantity step size
-	MinPrice decimal.Decimal // Minimum price
-	MaxPrice decimal.Decimal // Maximum price
-	TickSize decimal.Decimal // Price tick size
+	BaseAsset   string          // Asset being traded (e.g. ETH)
+	QuoteAsset  string          // Asset used for pricing (e.g. USDT)
+	MinQty      decimal.Decimal // Minimum order quantity
+	MaxQty      decimal.Decimal // Maximum order quantity (zero = no bound)
+	StepSize    decimal.Decimal // Quantity step size
+	MinPrice    decimal.Decimal // Minimum price (zero = no bound)
+	MaxPrice    decimal.Decimal // Maximum price (zero = no bound)
+	TickSize    decimal.Decimal // Price tick size
 	MinNotional decimal.Decimal // Minimum notional value (price * quantity)
+	FetchedAt   time.Time       // When these rules were fetched from Binance
 }
 
 type BinanceClient struct {
-	apiKey    string
-	apiSecret string
-	baseURL   string
-	client    *http.Client
+	keys    *keyRing // Primary and backup credentials
+	baseURL string
+	client  *http.Client
 
 	// Cache for open orders to implement idempotency
 	orderCache      map[string]*models.BinanceOrder
@@ -49,32 +57,107 @@ type BinanceClient struct {
 	// Symbol restrictions cache
 	symbolInfo      map[string]*SymbolInfo
 	symbolInfoMutex sync.RWMutex
-	symbolInfoTime  time.Time
+
+	// Free balances cache for pre-submission checks
+	balances      map[string]decimal.Decimal
+	balancesMutex sync.RWMutex
+	balancesTime  time.Time
+	balancesTTL   time.Duration
+
+	// Circuit breakers per endpoint group
+	breakers map[string]*CircuitBreaker
+
+	// Schedules REST requests under Binance's weight and order limits
+	limiter *rateLimiter
+
+	// Optional low-latency transport for placing and cancelling orders
+	ws *WSAPIClient
+
+	// Fault injection for resilience testing (nil = off; REST only)
+	chaos *chaos.Injector
+
+	// Simulated order handling for paper trading (nil = live)
+	paper PaperExchange
+
+	// USDT-M futures mode (nil = spot)
+	futures *futuresSettings
+
+	// Cross/isolated margin mode (nil = spot)
+	margin *MarginConfig
+
+	// Receives every order submission and its reply (nil = not audited)
+	placementAudit func(PlacementAttempt)
 }
 
-func NewBinanceClient(apiKey, apiSecret string) *BinanceClient {
+// NewBinanceClient creates a client signing with apiKey/apiSecret, failing over to backups in order
+func NewBinanceClient(apiKey, apiSecret string, backups ...APIKeyPair) *BinanceClient {
+	keys := append([]APIKeyPair{{Label: "primary", APIKey: apiKey, APISecret: apiSecret}}, backups...)
 	return &BinanceClient{
-		apiKey:    apiKey,
-		apiSecret: apiSecret,
-		baseURL:   BinanceAPIURL,
-		client:    &http.Client{Timeout: 10 * time.Second},
-		orderCache: make(map[string]*models.BinanceOrder),
+		keys:        newKeyRing(keys),
+		baseURL:     BinanceAPIURL,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		orderCache:  make(map[string]*models.BinanceOrder),
 		cacheExpiry: 5 * time.Second, // Short cache for idempotency
-		symbolInfo: make(map[string]*SymbolInfo),
+		symbolInfo:  make(map[string]*SymbolInfo),
+		balances:    make(map[string]decimal.Decimal),
+		balancesTTL: 10 * time.Second,
+		breakers: map[string]*CircuitBreaker{
+			GroupOrders:  NewCircuitBreaker(GroupOrders, 5, 30*time.Second),
+			GroupAccount: NewCircuitBreaker(GroupAccount, 5, 30*time.Second),
+			GroupMarket:  NewCircuitBreaker(GroupMarket, 5, 30*time.Second),
+		},
+		limiter: newRateLimiter(spotWeightLimit, spotOrderLimit),
+	}
+}
+
+// EnableWebSocketAPI routes order placement and cancellation through Binance's WebSocket API
+func (bc *BinanceClient) EnableWebSocketAPI(wsURL string) {
+	bc.ws = NewWSAPIClient(wsURL)
+	slog.Info("Binance WebSocket API order transport enabled, REST fallback active", "url", wsURL)
+}
+
+// UseAPIURL points REST calls at another Binance-compatible endpoint (e.g. the mock exchange)
+func (bc *BinanceClient) UseAPIURL(baseURL string) {
+	bc.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// EnableChaos injects latency and fake error responses into REST calls
+func (bc *BinanceClient) EnableChaos(injector *chaos.Injector) {
+	bc.chaos = injector
+}
+
+// Close releases the WebSocket API connection, if any
+func (bc *BinanceClient) Close() {
+	if bc.ws != nil {
+		bc.ws.Close()
 	}
 }
 
-// PlaceOrder places a LIMIT order on Binance
-func (bc *BinanceClient) PlaceOrder(symbol string, side models.OrderSide, price, quantity decimal.Decimal) (*models.BinanceOrder, error) {
+// PlaceOrder places a LIMIT or MARKET order on Binance. clientOrderID (optional) is sent as
+// newClientOrderId so the order can be found again if the response is lost. A MARKET order
+// fills against the book at once: price is only the estimate its notional and balance are
+// checked with, and it is never reused from the idempotency cache.
+func (bc *BinanceClient) PlaceOrder(symbol string, side models.OrderSide, orderType models.OrderType, price, quantity decimal.Decimal, clientOrderID string) (*models.BinanceOrder, error) {
 	// Ensure we have symbol info
 	info, err := bc.getSymbolInfo(symbol)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get symbol info: %w", err)
 	}
 
+	requestedQuantity := quantity
+	market := orderType == models.OrderTypeMarket
+
 	// Apply symbol restrictions
-	price = bc.roundToTickSize(price, info.TickSize)
 	quantity = bc.roundToStepSize(quantity, info.StepSize)
+	if !market {
+		price = bc.roundToTickSize(price, info.TickSize)
+
+		// Price can't be adjusted without changing the grid level
+		if (info.MinPrice.IsPositive() && price.LessThan(info.MinPrice)) || (info.MaxPrice.IsPositive() && price.GreaterThan(info.MaxPrice)) {
+			return nil, newFilterError(ErrFilterFailure, "PRICE_FILTER", info, price, requestedQuantity, quantity,
+				fmt.Sprintf("price %s outside allowed range [%s - %s]", price, info.MinPrice, info.MaxPrice))
+		}
+	}
 
 	originalQuantity := quantity
 
@@ -87,107 +170,165 @@ func (bc *BinanceClient) PlaceOrder(symbol string, side models.OrderSide, price,
 		// Round up to step size
 		quantity = bc.roundUpToStepSize(minQuantityNeeded, info.StepSize)
 		notional = price.Mul(quantity)
-		log.Printf("INFO: Adjusted quantity %s → %s to meet min notional %s (notional was %s, now %s)",
-			originalQuantity, quantity, info.MinNotional, price.Mul(originalQuantity), notional)
+		slog.Info("Adjusted quantity to meet min notional", logging.KeySymbol, symbol, "requested", originalQuantity,
+			"quantity", quantity, "min_notional", info.MinNotional, "notional", notional)
 	}
 
 	// Adjust for minimum quantity restriction
 	if quantity.LessThan(info.MinQty) {
-		log.Printf("INFO: Adjusted quantity %s → %s to meet min quantity requirement", originalQuantity, info.MinQty)
+		slog.Info("Adjusted quantity to meet min quantity", logging.KeySymbol, symbol, "requested", originalQuantity,
+			"quantity", info.MinQty)
 		quantity = info.MinQty
 	}
 
 	// Check maximum quantity restriction (this one we can't adjust)
-	if quantity.GreaterThan(info.MaxQty) {
-		return nil, fmt.Errorf("required quantity %s exceeds maximum allowed %s", quantity, info.MaxQty)
+	if info.MaxQty.IsPositive() && quantity.GreaterThan(info.MaxQty) {
+		return nil, newFilterError(ErrFilterFailure, "LOT_SIZE", info, price, requestedQuantity, quantity,
+			fmt.Sprintf("required quantity %s exceeds maximum allowed %s", quantity, info.MaxQty))
 	}
 
-	// Check cache for idempotency
+	// Verify adjustments actually satisfied the filters
+	if quantity.LessThan(info.MinQty) {
+		return nil, newFilterError(ErrOrderTooSmall, "LOT_SIZE", info, price, requestedQuantity, quantity,
+			fmt.Sprintf("adjusted quantity %s still below minimum %s", quantity, info.MinQty))
+	}
+	if notional = price.Mul(quantity); notional.LessThan(info.MinNotional) {
+		return nil, newFilterError(ErrOrderTooSmall, "MIN_NOTIONAL", info, price, requestedQuantity, quantity,
+			fmt.Sprintf("adjusted notional %s still below minimum %s", notional, info.MinNotional))
+	}
+
+	// Check cache for idempotency - a market order has filled before it could be reused
 	cacheKey := bc.createCacheKey(symbol, side, price, quantity)
-	if existingOrder := bc.getFromCache(cacheKey); existingOrder != nil {
-		log.Printf("INFO: Cache hit for order - Symbol: %s, Side: %s, Price: %s, Qty: %s, Existing Order: %d",
-			symbol, side, price, quantity, existingOrder.OrderID)
+	if existingOrder := bc.getFromCache(cacheKey); !market && existingOrder != nil {
+		logger := slog.With(logging.KeySymbol, symbol, logging.KeyOrderID, existingOrder.OrderID)
+		logger.Info("Cache hit for order", "side", side, "price", price, "quantity", quantity)
 		currentOrder, err := bc.GetOrder(symbol, strconv.FormatInt(existingOrder.OrderID, 10))
 		if err == nil && currentOrder != nil && (currentOrder.Status == "NEW" || currentOrder.Status == "PARTIALLY_FILLED") {
-			log.Printf("INFO: Reusing existing order %d (status: %s) - idempotent placement", existingOrder.OrderID, currentOrder.Status)
+			logger.Info("Reusing existing order - idempotent placement", "status", currentOrder.Status)
 			return currentOrder, nil
 		}
-		log.Printf("WARNING: Cached order %d no longer valid, placing new order", existingOrder.OrderID)
+		logger.Warn("Cached order no longer valid, placing new order")
 	}
 
 	params := url.Values{}
 	params.Set("symbol", symbol)
 	params.Set("side", strings.ToUpper(string(side)))
-	params.Set("type", "LIMIT")
-	params.Set("timeInForce", "GTC")
-	params.Set("price", price.String())
+	if market {
+		params.Set("type", "MARKET")
+	} else {
+		params.Set("type", "LIMIT")
+		params.Set("timeInForce", "GTC")
+		params.Set("price", price.String())
+	}
 	params.Set("quantity", quantity.String())
 	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
 	params.Set("recvWindow", "5000") // 5 seconds - Binance recommended value
+	if clientOrderID != "" {
+		params.Set("newClientOrderId", clientOrderID)
+	}
 
 	// Check if we have credentials
-	if bc.apiKey == "" || bc.apiSecret == "" {
+	if !bc.hasCredentials() {
 		return nil, fmt.Errorf("Binance API credentials not configured - cannot place orders")
 	}
 
+	if bc.futures != nil {
+		bc.futuresOrderParams(params, params.Get("side"))
+		if err := bc.prepareFutures(symbol); err != nil {
+			return nil, err
+		}
+	}
+	if bc.margin != nil {
+		bc.marginOrderParams(params, params.Get("side"))
+	}
+
+	// Fail fast on insufficient balance instead of burning request weight
+	if err := bc.checkBalance(info, side, price, quantity); err != nil {
+		return nil, err
+	}
+
+	order, err := bc.submitOrder(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store in cache
+	if !market {
+		bc.storeInCache(cacheKey, order)
+	}
+	bc.invalidateBalances()
+	slog.Info("Placed order on Binance", "type", params.Get("type"), logging.KeyOrderID, order.OrderID,
+		logging.KeySymbol, symbol, "side", side, "price", price, "quantity", quantity)
+
+	return order, nil
+}
+
+// submitOrder sends a new order over the WebSocket API when enabled, falling back to REST
+// only if the request never reached Binance
+func (bc *BinanceClient) submitOrder(params url.Values) (*models.BinanceOrder, error) {
+	started := time.Now()
+	if bc.ws != nil && bc.IsSpot() {
+		pair := bc.keys.current()
+		order, reply, err := bc.ws.PlaceOrder(params, pair)
+		bc.trackWSKeyHealth(pair.APIKey, err)
+		if !errors.Is(err, errWSUnavailable) {
+			bc.auditWSPlacement(params, reply, order, err, started)
+			return order, err
+		}
+		slog.Warn("WebSocket API unavailable, placing order via REST", logging.KeySymbol, params.Get("symbol"), logging.Err(err))
+	}
+
 	// Add signature
-	signature := bc.sign(params.Encode())
-	params.Set("signature", signature)
+	bc.marginParams(params)
+	apiKey := bc.signParams(params)
 
-	req, err := http.NewRequest("POST", bc.baseURL+"/api/v3/order", strings.NewReader(params.Encode()))
+	req, err := http.NewRequest("POST", bc.endpoint("/api/v3/order"), strings.NewReader(params.Encode()))
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("X-MBX-APIKEY", bc.apiKey)
+	req.Header.Set("X-MBX-APIKEY", apiKey)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := bc.client.Do(req)
+	resp, err := bc.do(req)
 	if err != nil {
+		bc.auditPlacement(placementRest, params, 0, nil, nil, err, started)
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// Log rate limit headers for monitoring
-	if weight := resp.Header.Get("X-MBX-USED-WEIGHT-1M"); weight != "" {
-		log.Printf("Binance API weight used: %s/6000", weight)
-	}
-
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		bc.auditPlacement(placementRest, params, resp.StatusCode, nil, nil, err, started)
 		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		var errResp map[string]interface{}
-		json.Unmarshal(body, &errResp)
+		orderErr := parseBinanceError(resp.StatusCode, body)
 
 		// Special handling for rate limit errors
-		if resp.StatusCode == 429 {
-			retryAfter := resp.Header.Get("Retry-After")
-			return nil, fmt.Errorf("binance rate limit exceeded (429), retry after: %s, error: %v", retryAfter, errResp)
+		if orderErr.Code == ErrRateLimited {
+			orderErr.Message = fmt.Sprintf("%s (retry after: %s)", orderErr.Message, resp.Header.Get("Retry-After"))
 		}
 
-		return nil, fmt.Errorf("binance error %d: %v", resp.StatusCode, errResp)
+		bc.auditPlacement(placementRest, params, resp.StatusCode, body, nil, orderErr, started)
+		return nil, orderErr
 	}
 
 	var order models.BinanceOrder
 	if err := json.Unmarshal(body, &order); err != nil {
+		bc.auditPlacement(placementRest, params, resp.StatusCode, body, nil, err, started)
 		return nil, err
 	}
 
-	// Store in cache
-	bc.storeInCache(cacheKey, &order)
-	log.Printf("SUCCESS: Placed order on Binance - Order ID: %d, Symbol: %s, Side: %s, Price: %s, Qty: %s",
-		order.OrderID, symbol, side, price, quantity)
-
+	bc.auditPlacement(placementRest, params, resp.StatusCode, body, &order, nil, started)
 	return &order, nil
 }
 
 // GetOrder retrieves order status from Binance
 func (bc *BinanceClient) GetOrder(symbol, orderID string) (*models.BinanceOrder, error) {
 	// Check if we have credentials
-	if bc.apiKey == "" || bc.apiSecret == "" {
+	if !bc.hasCredentials() {
 		return nil, fmt.Errorf("Binance API credentials not configured - cannot get order status")
 	}
 
@@ -198,17 +339,17 @@ func (bc *BinanceClient) GetOrder(symbol, orderID string) (*models.BinanceOrder,
 	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
 	params.Set("recvWindow", "5000")
 
-	signature := bc.sign(params.Encode())
-	params.Set("signature", signature)
+	bc.marginParams(params)
+	apiKey := bc.signParams(params)
 
-	req, err := http.NewRequest("GET", bc.baseURL+"/api/v3/order?"+params.Encode(), nil)
+	req, err := http.NewRequest("GET", bc.endpoint("/api/v3/order")+"?"+params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("X-MBX-APIKEY", bc.apiKey)
+	req.Header.Set("X-MBX-APIKEY", apiKey)
 
-	resp, err := bc.client.Do(req)
+	resp, err := bc.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -230,7 +371,7 @@ func (bc *BinanceClient) GetOrder(symbol, orderID string) (*models.BinanceOrder,
 
 	// If not found, fallback to allOrders (searches recent 7 days)
 	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
-		log.Printf("INFO: Order %s not found in /api/v3/order, falling back to /api/v3/allOrders", orderID)
+		slog.Info("Order not found in /api/v3/order, falling back to /api/v3/allOrders", logging.KeySymbol, symbol, logging.KeyOrderID, orderID)
 		return bc.getOrderFromAllOrders(symbol, orderID)
 	}
 
@@ -240,6 +381,118 @@ func (bc *BinanceClient) GetOrder(symbol, orderID string) (*models.BinanceOrder,
 	return nil, fmt.Errorf("binance error %d: %v", resp.StatusCode, errResp)
 }
 
+// GetOrderByClientID looks an order up by the clientOrderId it was placed with.
+// Returns nil without error if Binance has no such order.
+func (bc *BinanceClient) GetOrderByClientID(symbol, clientOrderID string) (*models.BinanceOrder, error) {
+	if !bc.hasCredentials() {
+		return nil, fmt.Errorf("Binance API credentials not configured - cannot get order status")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("origClientOrderId", clientOrderID)
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	params.Set("recvWindow", "5000")
+
+	bc.marginParams(params)
+	apiKey := bc.signParams(params)
+
+	req, err := http.NewRequest("GET", bc.endpoint("/api/v3/order")+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-MBX-APIKEY", apiKey)
+
+	resp, err := bc.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		orderErr := parseBinanceError(resp.StatusCode, body)
+		if orderErr.BinanceCode == -2013 { // Order does not exist
+			return nil, nil
+		}
+		return nil, orderErr
+	}
+
+	var order models.BinanceOrder
+	if err := json.Unmarshal(body, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// CancelOrder cancels an open order on Binance
+func (bc *BinanceClient) CancelOrder(symbol, orderID string) (*models.BinanceOrder, error) {
+	// Check if we have credentials
+	if !bc.hasCredentials() {
+		return nil, fmt.Errorf("Binance API credentials not configured - cannot cancel orders")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("orderId", orderID)
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	params.Set("recvWindow", "5000")
+
+	if bc.ws != nil {
+		pair := bc.keys.current()
+		order, err := bc.ws.CancelOrder(params, pair)
+		bc.trackWSKeyHealth(pair.APIKey, err)
+		if err == nil {
+			slog.Info("Cancelled order on Binance", logging.KeyOrderID, order.OrderID, logging.KeySymbol, symbol, "status", order.Status)
+			return order, nil
+		}
+		if !errors.Is(err, errWSUnavailable) {
+			return nil, err
+		}
+		slog.Warn("WebSocket API unavailable, cancelling order via REST", logging.KeySymbol, symbol, logging.KeyOrderID, orderID, logging.Err(err))
+	}
+
+	bc.marginParams(params)
+	apiKey := bc.signParams(params)
+
+	req, err := http.NewRequest("DELETE", bc.endpoint("/api/v3/order")+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-MBX-APIKEY", apiKey)
+
+	resp, err := bc.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp map[string]interface{}
+		json.Unmarshal(body, &errResp)
+		return nil, fmt.Errorf("binance error %d: %v", resp.StatusCode, errResp)
+	}
+
+	var order models.BinanceOrder
+	if err := json.Unmarshal(body, &order); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Cancelled order on Binance", logging.KeyOrderID, order.OrderID, logging.KeySymbol, symbol, "status", order.Status)
+	return &order, nil
+}
+
 func (bc *BinanceClient) getOrderFromAllOrders(symbol, orderID string) (*models.BinanceOrder, error) {
 	// Parse orderID to int64
 	targetOrderID, err := strconv.ParseInt(orderID, 10, 64)
@@ -248,23 +501,52 @@ func (bc *BinanceClient) getOrderFromAllOrders(symbol, orderID string) (*models.
 	}
 
 	// Query recent orders (last 7 days)
+	orders, err := bc.GetAllOrders(symbol, 0, 500) // Max 500 orders
+	if err != nil {
+		return nil, err
+	}
+
+	// Find order with matching ID
+	for _, order := range orders {
+		if order.OrderID == targetOrderID {
+			slog.Info("Found order in allOrders", logging.KeySymbol, symbol, logging.KeyOrderID, orderID, "status", order.Status)
+			return order, nil
+		}
+	}
+
+	slog.Warn("Order not found in recent 500 orders", logging.KeySymbol, symbol, logging.KeyOrderID, orderID)
+	return nil, nil
+}
+
+// GetAllOrders retrieves up to limit orders of any status for a symbol.
+// With fromOrderID > 0 it returns orders with ID >= fromOrderID in ascending order,
+// otherwise the most recent ones.
+func (bc *BinanceClient) GetAllOrders(symbol string, fromOrderID int64, limit int) ([]*models.BinanceOrder, error) {
+	// Check if we have credentials
+	if !bc.hasCredentials() {
+		return nil, fmt.Errorf("Binance API credentials not configured - cannot get orders")
+	}
+
 	params := url.Values{}
 	params.Set("symbol", symbol)
-	params.Set("limit", "500") // Max 500 orders
+	if fromOrderID > 0 {
+		params.Set("orderId", strconv.FormatInt(fromOrderID, 10))
+	}
+	params.Set("limit", strconv.Itoa(limit))
 	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
 	params.Set("recvWindow", "5000")
 
-	signature := bc.sign(params.Encode())
-	params.Set("signature", signature)
+	bc.marginParams(params)
+	apiKey := bc.signParams(params)
 
-	req, err := http.NewRequest("GET", bc.baseURL+"/api/v3/allOrders?"+params.Encode(), nil)
+	req, err := http.NewRequest("GET", bc.endpoint("/api/v3/allOrders")+"?"+params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("X-MBX-APIKEY", bc.apiKey)
+	req.Header.Set("X-MBX-APIKEY", apiKey)
 
-	resp, err := bc.client.Do(req)
+	resp, err := bc.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -281,27 +563,18 @@ func (bc *BinanceClient) getOrderFromAllOrders(symbol, orderID string) (*models.
 		return nil, fmt.Errorf("binance error %d: %v", resp.StatusCode, errResp)
 	}
 
-	var orders []models.BinanceOrder
+	var orders []*models.BinanceOrder
 	if err := json.Unmarshal(body, &orders); err != nil {
 		return nil, err
 	}
 
-	// Find order with matching ID
-	for i := range orders {
-		if orders[i].OrderID == targetOrderID {
-			log.Printf("INFO: Found order %s in allOrders - Status: %s", orderID, orders[i].Status)
-			return &orders[i], nil
-		}
-	}
-
-	log.Printf("WARNING: Order %s not found in recent 500 orders for %s", orderID, symbol)
-	return nil, nil
+	return orders, nil
 }
 
 // GetOpenOrders retrieves all open orders for a symbol
 func (bc *BinanceClient) GetOpenOrders(symbol string) ([]*models.BinanceOrder, error) {
 	// Check if we have credentials
-	if bc.apiKey == "" || bc.apiSecret == "" {
+	if !bc.hasCredentials() {
 		return nil, fmt.Errorf("Binance API credentials not configured - cannot get open orders")
 	}
 
@@ -312,17 +585,17 @@ func (bc *BinanceClient) GetOpenOrders(symbol string) ([]*models.BinanceOrder, e
 	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
 	params.Set("recvWindow", "5000")
 
-	signature := bc.sign(params.Encode())
-	params.Set("signature", signature)
+	bc.marginParams(params)
+	apiKey := bc.signParams(params)
 
-	req, err := http.NewRequest("GET", bc.baseURL+"/api/v3/openOrders?"+params.Encode(), nil)
+	req, err := http.NewRequest("GET", bc.endpoint("/api/v3/openOrders")+"?"+params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("X-MBX-APIKEY", bc.apiKey)
+	req.Header.Set("X-MBX-APIKEY", apiKey)
 
-	resp, err := bc.client.Do(req)
+	resp, err := bc.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -350,14 +623,68 @@ func (bc *BinanceClient) GetOpenOrders(symbol string) ([]*models.BinanceOrder, e
 	return orders, nil
 }
 
+// GetOrderTrades retrieves the executions of an order, including commission per trade
+func (bc *BinanceClient) GetOrderTrades(symbol, orderID string) ([]models.BinanceTrade, error) {
+	// Check if we have credentials
+	if !bc.hasCredentials() {
+		return nil, fmt.Errorf("Binance API credentials not configured - cannot get trades")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("orderId", orderID)
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	params.Set("recvWindow", "5000")
+
+	bc.marginParams(params)
+	apiKey := bc.signParams(params)
+
+	req, err := http.NewRequest("GET", bc.endpoint("/api/v3/myTrades")+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-MBX-APIKEY", apiKey)
+
+	resp, err := bc.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseBinanceError(resp.StatusCode, body)
+	}
+
+	var trades []models.BinanceTrade
+	if err := json.Unmarshal(body, &trades); err != nil {
+		return nil, err
+	}
+
+	return trades, nil
+}
+
 // Helper functions
 
-func (bc *BinanceClient) sign(payload string) string {
-	h := hmac.New(sha256.New, []byte(bc.apiSecret))
+func signPayload(secret, payload string) string {
+	h := hmac.New(sha256.New, []byte(secret))
 	h.Write([]byte(payload))
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// signParams signs params with the active key and returns the API key for the X-MBX-APIKEY header,
+// so signature and header always come from the same pair even while rotating
+func (bc *BinanceClient) signParams(params url.Values) string {
+	pair := bc.keys.current()
+	params.Set("signature", signPayload(pair.APISecret, params.Encode()))
+	return pair.APIKey
+}
+
 
 // Cache management for idempotency
 
@@ -432,25 +759,81 @@ func ConvertBinanceStatus(status string) string {
 	}
 }
 
-// getSymbolInfo fetches and caches symbol trading rules
+// getSymbolInfo returns cached symbol trading rules, fetching them on first use or when stale
 func (bc *BinanceClient) getSymbolInfo(symbol string) (*SymbolInfo, error) {
 	bc.symbolInfoMutex.RLock()
-	if info, ok := bc.symbolInfo[symbol]; ok && time.Since(bc.symbolInfoTime) < 24*time.Hour {
+	if info, ok := bc.symbolInfo[symbol]; ok && time.Since(info.FetchedAt) < symbolInfoMaxAge {
 		bc.symbolInfoMutex.RUnlock()
-		log.Printf("DEBUG: Symbol info cache hit for %s (age: %v)", symbol, time.Since(bc.symbolInfoTime))
+		slog.Debug("Symbol info cache hit", logging.KeySymbol, symbol, "age", time.Since(info.FetchedAt))
 		return info, nil
 	}
 	bc.symbolInfoMutex.RUnlock()
 
-	log.Printf("INFO: Fetching symbol info from Binance for %s", symbol)
+	slog.Info("Fetching symbol info from Binance", logging.KeySymbol, symbol)
+
+	infos, err := bc.fetchSymbolInfo([]string{symbol})
+	if err != nil {
+		return nil, err
+	}
+
+	info, ok := infos[symbol]
+	if !ok {
+		return nil, fmt.Errorf("symbol %s not found", symbol)
+	}
+
+	return info, nil
+}
+
+// SymbolAssets returns the base and quote asset and the quantity step of a symbol from its trading rules
+func (bc *BinanceClient) SymbolAssets(symbol string) (base, quote string, stepSize decimal.Decimal, err error) {
+	info, err := bc.getSymbolInfo(symbol)
+	if err != nil {
+		return "", "", decimal.Zero, err
+	}
+	return info.BaseAsset, info.QuoteAsset, info.StepSize, nil
+}
+
+// RefreshSymbolInfo re-fetches trading rules for every cached symbol in a single request,
+// so filter changes are picked up off the order placement hot path
+func (bc *BinanceClient) RefreshSymbolInfo() error {
+	bc.symbolInfoMutex.RLock()
+	symbols := make([]string, 0, len(bc.symbolInfo))
+	for symbol := range bc.symbolInfo {
+		symbols = append(symbols, symbol)
+	}
+	bc.symbolInfoMutex.RUnlock()
+
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	infos, err := bc.fetchSymbolInfo(symbols)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Refreshed symbol info", "refreshed", len(infos), "symbols", len(symbols))
+	return nil
+}
+
+// fetchSymbolInfo fetches trading rules for the given symbols and updates the cache
+func (bc *BinanceClient) fetchSymbolInfo(symbols []string) (map[string]*SymbolInfo, error) {
+	reqURL := bc.endpoint("/api/v3/exchangeInfo") + "?symbol=" + url.QueryEscape(symbols[0])
+	if len(symbols) > 1 {
+		symbolsJSON, err := json.Marshal(symbols)
+		if err != nil {
+			return nil, err
+		}
+		reqURL = bc.endpoint("/api/v3/exchangeInfo") + "?symbols=" + url.QueryEscape(string(symbolsJSON))
+	}
 
 	// Fetch exchange info
-	req, err := http.NewRequest("GET", bc.baseURL+"/api/v3/exchangeInfo?symbol="+symbol, nil)
+	req, err := http.NewRequest("GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := bc.client.Do(req)
+	resp, err := bc.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -467,8 +850,10 @@ func (bc *BinanceClient) getSymbolInfo(symbol string) (*SymbolInfo, error) {
 
 	var exchangeInfo struct {
 		Symbols []struct {
-			Symbol  string `json:"symbol"`
-			Filters []struct {
+			Symbol     string `json:"symbol"`
+			BaseAsset  string `json:"baseAsset"`
+			QuoteAsset string `json:"quoteAsset"`
+			Filters    []struct {
 				FilterType  string `json:"filterType"`
 				MinQty      string `json:"minQty,omitempty"`
 				MaxQty      string `json:"maxQty,omitempty"`
@@ -477,6 +862,7 @@ func (bc *BinanceClient) getSymbolInfo(symbol string) (*SymbolInfo, error) {
 				MaxPrice    string `json:"maxPrice,omitempty"`
 				TickSize    string `json:"tickSize,omitempty"`
 				MinNotional string `json:"minNotional,omitempty"`
+				Notional    string `json:"notional,omitempty"` // Futures MIN_NOTIONAL
 			} `json:"filters"`
 		} `json:"symbols"`
 	}
@@ -485,60 +871,81 @@ func (bc *BinanceClient) getSymbolInfo(symbol string) (*SymbolInfo, error) {
 		return nil, err
 	}
 
-	if len(exchangeInfo.Symbols) == 0 {
-		return nil, fmt.Errorf("symbol %s not found", symbol)
+	// Futures exchangeInfo ignores the symbol filter and lists every contract
+	requested := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		requested[symbol] = true
 	}
 
-	info := &SymbolInfo{
-		MinQty:      decimal.NewFromFloat(0.00001),
-		MaxQty:      decimal.NewFromFloat(10000000),
-		StepSize:    decimal.NewFromFloat(0.00001),
-		MinPrice:    decimal.NewFromFloat(0.01),
-		MaxPrice:    decimal.NewFromFloat(1000000),
-		TickSize:    decimal.NewFromFloat(0.01),
-		MinNotional: decimal.NewFromFloat(10),
-	}
+	infos := make(map[string]*SymbolInfo, len(exchangeInfo.Symbols))
+	for _, sym := range exchangeInfo.Symbols {
+		if !requested[sym.Symbol] {
+			continue
+		}
+		info := &SymbolInfo{
+			BaseAsset:   sym.BaseAsset,
+			QuoteAsset:  sym.QuoteAsset,
+			MinQty:      decimal.NewFromFloat(0.00001),
+			MaxQty:      decimal.NewFromFloat(10000000),
+			StepSize:    decimal.NewFromFloat(0.00001),
+			MinPrice:    decimal.NewFromFloat(0.01),
+			MaxPrice:    decimal.NewFromFloat(1000000),
+			TickSize:    decimal.NewFromFloat(0.01),
+			MinNotional: decimal.NewFromFloat(10),
+			FetchedAt:   time.Now(),
+		}
 
-	// Parse filters
-	for _, filter := range exchangeInfo.Symbols[0].Filters {
-		switch filter.FilterType {
-		case "LOT_SIZE":
-			if v, err := decimal.NewFromString(filter.MinQty); err == nil {
-				info.MinQty = v
-			}
-			if v, err := decimal.NewFromString(filter.MaxQty); err == nil {
-				info.MaxQty = v
-			}
-			if v, err := decimal.NewFromString(filter.StepSize); err == nil {
-				info.StepSize = v
-			}
-		case "PRICE_FILTER":
-			if v, err := decimal.NewFromString(filter.MinPrice); err == nil {
-				info.MinPrice = v
-			}
-			if v, err := decimal.NewFromString(filter.MaxPrice); err == nil {
-				info.MaxPrice = v
-			}
-			if v, err := decimal.NewFromString(filter.TickSize); err == nil {
-				info.TickSize = v
-			}
-		case "MIN_NOTIONAL":
-			if v, err := decimal.NewFromString(filter.MinNotional); err == nil {
-				info.MinNotional = v
+		// Parse filters
+		for _, filter := range sym.Filters {
+			switch filter.FilterType {
+			case "LOT_SIZE":
+				if v, err := decimal.NewFromString(filter.MinQty); err == nil {
+					info.MinQty = v
+				}
+				if v, err := decimal.NewFromString(filter.MaxQty); err == nil {
+					info.MaxQty = v
+				}
+				if v, err := decimal.NewFromString(filter.StepSize); err == nil {
+					info.StepSize = v
+				}
+			case "PRICE_FILTER":
+				if v, err := decimal.NewFromString(filter.MinPrice); err == nil {
+					info.MinPrice = v
+				}
+				if v, err := decimal.NewFromString(filter.MaxPrice); err == nil {
+					info.MaxPrice = v
+				}
+				if v, err := decimal.NewFromString(filter.TickSize); err == nil {
+					info.TickSize = v
+				}
+			case "MIN_NOTIONAL", "NOTIONAL":
+				if v, err := decimal.NewFromString(filter.MinNotional); err == nil {
+					info.MinNotional = v
+				} else if v, err := decimal.NewFromString(filter.Notional); err == nil {
+					info.MinNotional = v
+				}
 			}
 		}
+
+		infos[sym.Symbol] = info
 	}
 
 	// Cache the info
 	bc.symbolInfoMutex.Lock()
-	bc.symbolInfo[symbol] = info
-	bc.symbolInfoTime = time.Now()
+	for symbol, info := range infos {
+		if prev, ok := bc.symbolInfo[symbol]; ok && !prev.MinNotional.Equal(info.MinNotional) {
+			slog.Warn("MinNotional changed", logging.KeySymbol, symbol, "previous", prev.MinNotional, "min_notional", info.MinNotional)
+		}
+		bc.symbolInfo[symbol] = info
+	}
 	bc.symbolInfoMutex.Unlock()
 
-	log.Printf("INFO: Cached symbol info for %s - MinQty: %s, MinNotional: %s, StepSize: %s",
-		symbol, info.MinQty, info.MinNotional, info.StepSize)
+	for symbol, info := range infos {
+		slog.Info("Cached symbol info", logging.KeySymbol, symbol, "min_qty", info.MinQty, "min_notional", info.MinNotional,
+			"step_size", info.StepSize)
+	}
 
-	return info, nil
+	return infos, nil
 }
 
 // roundToStepSize rounds a quantity to the nearest valid step size
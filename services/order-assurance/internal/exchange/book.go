@@ -0,0 +1,82 @@
+package exchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/shopspring/decimal"
+)
+
+// bookTicker is the reply of ticker.book and GET /api/v3/ticker/bookTicker
+type bookTicker struct {
+	Symbol   string          `json:"symbol"`
+	BidPrice decimal.Decimal `json:"bidPrice"`
+	BidQty   decimal.Decimal `json:"bidQty"`
+	AskPrice decimal.Decimal `json:"askPrice"`
+	AskQty   decimal.Decimal `json:"askQty"`
+}
+
+// GetBookTicker returns the best bid and ask of a symbol, over the WebSocket API when it is
+// enabled (spot and margin books) and via REST otherwise or when the socket is unavailable
+func (bc *BinanceClient) GetBookTicker(symbol string) (*contracts.BookTicker, error) {
+	if bc.ws != nil && !bc.IsFutures() {
+		resp, err := bc.ws.BookTicker(symbol)
+		if err == nil {
+			if resp.Status != 200 {
+				return nil, parseBinanceError(resp.Status, resp.Error)
+			}
+			return decodeBookTicker(resp.Result, "websocket")
+		}
+		if !errors.Is(err, errWSUnavailable) {
+			return nil, err
+		}
+		slog.Warn("WebSocket API unavailable, reading book via REST", logging.KeySymbol, symbol, logging.Err(err))
+	}
+
+	req, err := http.NewRequest("GET", bc.endpoint("/api/v3/ticker/bookTicker")+"?symbol="+url.QueryEscape(symbol), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := bc.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseBinanceError(resp.StatusCode, body)
+	}
+
+	return decodeBookTicker(body, "rest")
+}
+
+func decodeBookTicker(body []byte, source string) (*contracts.BookTicker, error) {
+	var book bookTicker
+	if err := json.Unmarshal(body, &book); err != nil {
+		return nil, fmt.Errorf("failed to parse book ticker: %w", err)
+	}
+
+	ticker := &contracts.BookTicker{
+		Symbol:   book.Symbol,
+		BidPrice: book.BidPrice,
+		BidQty:   book.BidQty,
+		AskPrice: book.AskPrice,
+		AskQty:   book.AskQty,
+		Source:   source,
+	}
+	ticker.SpreadPct = ticker.Spread().Round(4)
+	return ticker, nil
+}
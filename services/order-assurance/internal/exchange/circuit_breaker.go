@@ -0,0 +1,228 @@
+package exchange
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// Endpoint groups with independent circuit breakers
+const (
+	GroupOrders  = "orders"  // Order placement and cancellation
+	GroupAccount = "account" // Signed queries (order status, balances)
+	GroupMarket  = "market"  // Public market data (exchange info, ping, time)
+)
+
+// CircuitBreaker stops calling an endpoint group after sustained failures,
+// letting a single probe through once the cooldown has elapsed
+type CircuitBreaker struct {
+	name             string
+	failureThreshold int
+	cooldown         time.Duration
+
+	mu                  sync.Mutex
+	state               CircuitState
+	consecutiveFailures int
+	openedAt            time.Time
+	lastError           string
+}
+
+// CircuitStatus is a snapshot of a breaker for publishing to callers
+type CircuitStatus struct {
+	Name                string       `json:"name"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            string       `json:"opened_at,omitempty"`
+	RetryAfterSec       int          `json:"retry_after_sec,omitempty"`
+	LastError           string       `json:"last_error,omitempty"`
+}
+
+func NewCircuitBreaker(name string, failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		state:            CircuitClosed,
+	}
+}
+
+// Allow returns an ErrCircuitOpen OrderError while the breaker is open
+func (cb *CircuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		remaining := cb.cooldown - time.Since(cb.openedAt)
+		if remaining > 0 {
+			retryAfter := int(remaining.Seconds()) + 1
+			return &OrderError{
+				Code:    ErrCircuitOpen,
+				Message: fmt.Sprintf("circuit breaker %s open after %d consecutive failures (last: %s)", cb.name, cb.consecutiveFailures, cb.lastError),
+				Details: map[string]string{
+					"circuit":         cb.name,
+					"retry_after_sec": strconv.Itoa(retryAfter),
+				},
+			}
+		}
+		// Cooldown elapsed - let one probe request through
+		cb.state = CircuitHalfOpen
+		slog.Info("Circuit breaker half-open, probing exchange", "circuit", cb.name)
+		return nil
+	case CircuitHalfOpen:
+		return &OrderError{
+			Code:    ErrCircuitOpen,
+			Message: fmt.Sprintf("circuit breaker %s half-open, probe in progress", cb.name),
+			Details: map[string]string{"circuit": cb.name, "retry_after_sec": "1"},
+		}
+	default:
+		return nil
+	}
+}
+
+// Record updates the breaker with the outcome of a request.
+// Only transport errors and 5xx responses count as failures - 4xx are caller errors.
+func (cb *CircuitBreaker) Record(resp *http.Response, err error) {
+	failed := err != nil || (resp != nil && resp.StatusCode >= 500)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !failed {
+		if cb.state != CircuitClosed {
+			slog.Info("Circuit breaker closed, exchange recovered", "circuit", cb.name)
+		}
+		cb.state = CircuitClosed
+		cb.consecutiveFailures = 0
+		cb.lastError = ""
+		return
+	}
+
+	cb.consecutiveFailures++
+	if err != nil {
+		cb.lastError = err.Error()
+	} else {
+		cb.lastError = fmt.Sprintf("HTTP %d", resp.StatusCode)
+	}
+
+	if cb.state == CircuitHalfOpen || cb.consecutiveFailures >= cb.failureThreshold {
+		if cb.state != CircuitOpen {
+			slog.Error("Circuit breaker opened", "circuit", cb.name, "failures", cb.consecutiveFailures,
+				"last_error", cb.lastError, "cooldown", cb.cooldown)
+		}
+		cb.state = CircuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+func (cb *CircuitBreaker) Status() CircuitStatus {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	status := CircuitStatus{
+		Name:                cb.name,
+		State:               cb.state,
+		ConsecutiveFailures: cb.consecutiveFailures,
+		LastError:           cb.lastError,
+	}
+	if cb.state == CircuitOpen {
+		status.OpenedAt = cb.openedAt.Format(time.RFC3339)
+		if remaining := cb.cooldown - time.Since(cb.openedAt); remaining > 0 {
+			status.RetryAfterSec = int(remaining.Seconds()) + 1
+		}
+	}
+	return status
+}
+
+// endpointGroup maps a Binance request to its circuit breaker group
+func endpointGroup(req *http.Request) string {
+	switch req.URL.Path {
+	case "/api/v3/order", "/fapi/v1/order", "/sapi/v1/margin/order":
+		if req.Method == "GET" {
+			return GroupAccount
+		}
+		return GroupOrders
+	case "/api/v3/allOrders", "/api/v3/openOrders", "/api/v3/account", "/api/v3/myTrades", "/api/v3/userDataStream",
+		"/sapi/v1/simple-earn/flexible/list", "/sapi/v1/simple-earn/flexible/subscribe", "/sapi/v1/capital/withdraw/apply",
+		"/fapi/v1/allOrders", "/fapi/v1/openOrders", "/fapi/v1/userTrades", "/fapi/v2/balance", "/fapi/v2/positionRisk",
+		"/fapi/v1/income", "/fapi/v1/leverage", "/fapi/v1/positionSide/dual",
+		"/sapi/v1/margin/allOrders", "/sapi/v1/margin/openOrders", "/sapi/v1/margin/myTrades", "/sapi/v1/margin/account",
+		"/sapi/v1/margin/isolated/account", "/sapi/v1/margin/interestHistory":
+		return GroupAccount
+	default:
+		return GroupMarket
+	}
+}
+
+// do executes a request through the circuit breaker of its endpoint group, once the rate
+// limiter lets it go. A 429 is retried after its Retry-After (RateLimitConfig.Retries times).
+func (bc *BinanceClient) do(req *http.Request) (*http.Response, error) {
+	breaker := bc.breakers[endpointGroup(req)]
+	for attempt := 0; ; attempt++ {
+		if err := breaker.Allow(); err != nil {
+			return nil, err
+		}
+
+		weight, orders := requestCost(req)
+		wait, err := bc.limiter.reserve(weight, orders)
+		if err != nil {
+			return nil, err
+		}
+		if wait > 0 {
+			slog.Info("Delaying request to stay under the Binance rate limits", "method", req.Method, "path", req.URL.Path,
+				"wait", wait.Round(time.Millisecond))
+			time.Sleep(wait)
+		}
+		if attempt > 0 {
+			if req, err = bc.retryRequest(req); err != nil {
+				return nil, fmt.Errorf("failed to retry rate-limited request: %w", err)
+			}
+		}
+
+		var resp *http.Response
+		bc.chaos.Delay()
+		if bc.paper != nil {
+			resp = bc.paper.Response(req)
+		}
+		if resp == nil {
+			if resp = bc.chaos.ExchangeResponse(req); resp == nil {
+				resp, err = bc.client.Do(req)
+			}
+		}
+		breaker.Record(resp, err)
+		if err != nil {
+			return resp, err
+		}
+		bc.limiter.observe(resp)
+		if apiKey := req.Header.Get("X-MBX-APIKEY"); apiKey != "" {
+			bc.trackKeyHealth(apiKey, resp)
+		}
+
+		retryWait, retry := bc.limiter.retryWait(resp, attempt)
+		if !retry {
+			return resp, nil
+		}
+		slog.Warn("Binance rate-limited request, retrying", "method", req.Method, "path", req.URL.Path, "retry_after", retryWait)
+		resp.Body.Close()
+	}
+}
+
+// CircuitStatuses returns the state of every circuit breaker
+func (bc *BinanceClient) CircuitStatuses() []CircuitStatus {
+	statuses := make([]CircuitStatus, 0, len(bc.breakers))
+	for _, group := range []string{GroupOrders, GroupAccount, GroupMarket} {
+		statuses = append(statuses, bc.breakers[group].Status())
+	}
+	return statuses
+}
@@ -0,0 +1,136 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrorCode classifies exchange rejections into a stable taxonomy shared with grid-trading
+type ErrorCode string
+
+const (
+	ErrInsufficientFunds ErrorCode = "insufficient_funds"
+	ErrOrderTooSmall     ErrorCode = "order_too_small"
+	ErrFilterFailure     ErrorCode = "filter_failure"
+	ErrRateLimited       ErrorCode = "rate_limited"
+	ErrInvalidSymbol     ErrorCode = "invalid_symbol"
+	ErrClockSkew         ErrorCode = "clock_skew"
+	ErrAuthFailed        ErrorCode = "auth_failed"
+	ErrExchangeRejected  ErrorCode = "exchange_rejected"
+	ErrExchangeFailure   ErrorCode = "exchange_failure"
+	ErrCircuitOpen       ErrorCode = "circuit_open"
+	ErrUnknownAccount    ErrorCode = "unknown_account"
+	ErrSweepRejected     ErrorCode = "sweep_rejected"
+	ErrNotFutures        ErrorCode = "not_futures"
+	ErrNotMargin         ErrorCode = "not_margin"
+	ErrUnsupportedOrder  ErrorCode = "unsupported_order"
+	ErrUnsupportedVenue  ErrorCode = "unsupported_venue"
+)
+
+// OrderError is a classified Binance error response
+type OrderError struct {
+	Code        ErrorCode
+	HTTPStatus  int
+	BinanceCode int
+	Message     string
+	Details     map[string]string // Extra context for callers, e.g. shortfall amounts
+}
+
+func (e *OrderError) Error() string {
+	if e.HTTPStatus == 0 {
+		// Rejected locally before reaching Binance
+		return fmt.Sprintf("%s: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("binance error %d (code %d, %s): %s", e.HTTPStatus, e.BinanceCode, e.Code, e.Message)
+}
+
+// Terminal reports whether retrying the same request cannot succeed without intervention
+func (e *OrderError) Terminal() bool {
+	switch e.Code {
+	case ErrRateLimited, ErrClockSkew, ErrExchangeFailure, ErrCircuitOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// newFilterError reports a symbol filter that the order cannot satisfy, even after adjustment
+func newFilterError(code ErrorCode, filter string, info *SymbolInfo, price, requestedQty, adjustedQty decimal.Decimal, msg string) *OrderError {
+	return &OrderError{
+		Code:    code,
+		Message: fmt.Sprintf("%s filter failed: %s", filter, msg),
+		Details: map[string]string{
+			"filter":             filter,
+			"price":              price.String(),
+			"requested_quantity": requestedQty.String(),
+			"adjusted_quantity":  adjustedQty.String(),
+			"notional":           price.Mul(adjustedQty).String(),
+			"min_qty":            info.MinQty.String(),
+			"max_qty":            info.MaxQty.String(),
+			"step_size":          info.StepSize.String(),
+			"min_price":          info.MinPrice.String(),
+			"max_price":          info.MaxPrice.String(),
+			"min_notional":       info.MinNotional.String(),
+		},
+	}
+}
+
+// parseBinanceError builds a classified OrderError from a non-200 Binance response
+func parseBinanceError(statusCode int, body []byte) *OrderError {
+	var errResp struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Msg == "" {
+		errResp.Msg = string(body)
+	}
+
+	return &OrderError{
+		Code:        classifyBinanceError(statusCode, errResp.Code, errResp.Msg),
+		HTTPStatus:  statusCode,
+		BinanceCode: errResp.Code,
+		Message:     errResp.Msg,
+	}
+}
+
+// classifyBinanceError maps Binance HTTP status, error code and message to an ErrorCode
+// See https://developers.binance.com/docs/binance-spot-api-docs/errors
+func classifyBinanceError(statusCode, code int, msg string) ErrorCode {
+	if statusCode == http.StatusTooManyRequests || statusCode == http.StatusTeapot || code == -1003 || code == -1015 {
+		return ErrRateLimited
+	}
+	if statusCode >= 500 {
+		return ErrExchangeFailure
+	}
+
+	switch code {
+	case -1021:
+		return ErrClockSkew
+	case -1121:
+		return ErrInvalidSymbol
+	case -2014, -2015, -1022:
+		return ErrAuthFailed
+	case -1013:
+		if strings.Contains(msg, "NOTIONAL") {
+			return ErrOrderTooSmall
+		}
+		return ErrFilterFailure
+	case -2010:
+		if strings.Contains(strings.ToLower(msg), "insufficient balance") {
+			return ErrInsufficientFunds
+		}
+		return ErrExchangeRejected
+	case -2019, -2022: // Futures: margin insufficient, reduce-only sell larger than the position
+		return ErrInsufficientFunds
+	case -4164: // Futures: notional below the minimum
+		return ErrOrderTooSmall
+	case -3006, -3041, -3045: // Margin: borrow limit exceeded, balance not enough, no asset left to lend
+		return ErrInsufficientFunds
+	}
+
+	return ErrExchangeRejected
+}
@@ -0,0 +1,49 @@
+package exchange
+
+import (
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Exchange is the order flow of one account on a trading venue: limit and market orders, their
+// status and cancellation, trading rules and free balances (EXCHANGE selects the venue). Orders
+// come back in Binance's shape and status names whatever the venue. Everything beyond this -
+// quote quantity buys, market sells of whole holdings, trade history, open order listings,
+// futures, margin, sweeps - is Binance only and is reached through Accounts.Binance.
+type Exchange interface {
+	PlaceOrder(symbol string, side models.OrderSide, orderType models.OrderType, price, quantity decimal.Decimal, clientOrderID string) (*models.BinanceOrder, error)
+	GetOrder(symbol, orderID string) (*models.BinanceOrder, error) // nil if the venue doesn't know the order
+	CancelOrder(symbol, orderID string) (*models.BinanceOrder, error)
+	GetSymbolRules(symbol string) (*SymbolInfo, error)
+	GetBalances() (map[string]decimal.Decimal, error) // Free balances by asset
+}
+
+// Supported values of EXCHANGE
+const (
+	VenueBinance = "binance"
+	VenueKraken  = "kraken"
+)
+
+var (
+	_ Exchange = (*BinanceClient)(nil)
+	_ Exchange = (*KrakenClient)(nil)
+)
+
+// GetSymbolRules returns the symbol's cached trading rules
+func (bc *BinanceClient) GetSymbolRules(symbol string) (*SymbolInfo, error) {
+	return bc.getSymbolInfo(symbol)
+}
+
+// GetBalances returns the free balances of the client's spot, futures or margin account
+func (bc *BinanceClient) GetBalances() (map[string]decimal.Decimal, error) {
+	return bc.GetFreeBalances()
+}
+
+// SymbolAssets returns the base and quote asset and the quantity step of a symbol from a venue's trading rules
+func SymbolAssets(venue Exchange, symbol string) (base, quote string, stepSize decimal.Decimal, err error) {
+	info, err := venue.GetSymbolRules(symbol)
+	if err != nil {
+		return "", "", decimal.Zero, err
+	}
+	return info.BaseAsset, info.QuoteAsset, info.StepSize, nil
+}
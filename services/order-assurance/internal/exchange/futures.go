@@ -0,0 +1,287 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	FuturesAPIURL = "https://fapi.binance.com"
+
+	// Binance answers these when the requested setting is already active
+	errCodeNoPositionModeChange = -4059
+	errCodeNoMarginChange       = -4046
+)
+
+// FuturesConfig sets up a client for USDT-M perpetuals
+type FuturesConfig struct {
+	APIURL    string // fapi host (empty = FuturesAPIURL); spot and wallet paths keep the spot host
+	Leverage  int    // Applied per symbol before its first order
+	HedgeMode bool   // Hedge (dual-side) position mode; one-way otherwise
+}
+
+// futuresSettings tracks which account-level settings have been applied to Binance
+type futuresSettings struct {
+	FuturesConfig
+	mu              sync.Mutex
+	positionModeSet bool
+	leverageSet     map[string]bool
+}
+
+// futuresPaths maps spot REST paths to their USDT-M futures equivalents
+var futuresPaths = map[string]string{
+	"/api/v3/order":        "/fapi/v1/order",
+	"/api/v3/allOrders":    "/fapi/v1/allOrders",
+	"/api/v3/openOrders":   "/fapi/v1/openOrders",
+	"/api/v3/myTrades":     "/fapi/v1/userTrades",
+	"/api/v3/exchangeInfo": "/fapi/v1/exchangeInfo",
+	"/api/v3/ping":         "/fapi/v1/ping",
+	"/api/v3/time":         "/fapi/v1/time",
+
+	"/api/v3/ticker/bookTicker": "/fapi/v1/ticker/bookTicker",
+}
+
+// EnableFutures switches order and market data calls to USDT-M futures (fapi) endpoints.
+// Grids only go long: buys open or add to the long position, sells only ever reduce it.
+func (bc *BinanceClient) EnableFutures(cfg FuturesConfig) {
+	cfg.APIURL = strings.TrimSuffix(cfg.APIURL, "/")
+	if cfg.APIURL == "" {
+		cfg.APIURL = FuturesAPIURL
+	}
+	bc.futures = &futuresSettings{FuturesConfig: cfg, leverageSet: make(map[string]bool)}
+	bc.limiter.setLimits(futuresWeightLimit, futuresOrderLimit)
+	mode := "one-way"
+	if cfg.HedgeMode {
+		mode = "hedge"
+	}
+	slog.Info("USDT-M futures enabled", "leverage", cfg.Leverage, "position_mode", mode)
+}
+
+// IsFutures reports whether the client trades USDT-M futures
+func (bc *BinanceClient) IsFutures() bool {
+	return bc.futures != nil
+}
+
+// endpoint returns the URL of a spot REST path, or of its futures/margin equivalent in those modes
+func (bc *BinanceClient) endpoint(path string) string {
+	if bc.futures != nil {
+		if futuresPath, ok := futuresPaths[path]; ok {
+			return bc.futures.APIURL + futuresPath
+		}
+	}
+	if bc.margin != nil {
+		if marginPath, ok := marginPaths[path]; ok {
+			return bc.baseURL + marginPath
+		}
+	}
+	return bc.baseURL + path
+}
+
+// hostFor returns the host serving a REST path: fapi paths go to the futures host, the rest
+// (spot, wallet and /sapi system endpoints) to the spot host
+func (bc *BinanceClient) hostFor(path string) string {
+	if bc.futures != nil && strings.HasPrefix(path, "/fapi/") {
+		return bc.futures.APIURL
+	}
+	return bc.baseURL
+}
+
+// futuresOrderParams makes sells reduce-only so a grid can never open a short
+func (bc *BinanceClient) futuresOrderParams(params url.Values, side string) {
+	if bc.futures.HedgeMode {
+		params.Set("positionSide", "LONG")
+		return
+	}
+	if side == "SELL" {
+		params.Set("reduceOnly", "true")
+	}
+}
+
+// prepareFutures applies the position mode once and the leverage once per symbol
+func (bc *BinanceClient) prepareFutures(symbol string) error {
+	bc.futures.mu.Lock()
+	defer bc.futures.mu.Unlock()
+
+	if !bc.futures.positionModeSet {
+		params := url.Values{}
+		params.Set("dualSidePosition", strconv.FormatBool(bc.futures.HedgeMode))
+		if _, err := bc.signedRequest("POST", "/fapi/v1/positionSide/dual", params); err != nil && !isBinanceCode(err, errCodeNoPositionModeChange) {
+			return fmt.Errorf("failed to set position mode: %w", err)
+		}
+		bc.futures.positionModeSet = true
+	}
+
+	if !bc.futures.leverageSet[symbol] {
+		params := url.Values{}
+		params.Set("symbol", symbol)
+		params.Set("leverage", strconv.Itoa(bc.futures.Leverage))
+		if _, err := bc.signedRequest("POST", "/fapi/v1/leverage", params); err != nil && !isBinanceCode(err, errCodeNoMarginChange) {
+			return fmt.Errorf("failed to set %s leverage: %w", symbol, err)
+		}
+		bc.futures.leverageSet[symbol] = true
+		slog.Info("Futures leverage set", logging.KeySymbol, symbol, "leverage", bc.futures.Leverage)
+	}
+
+	return nil
+}
+
+// getFuturesBalances returns available margin per asset plus every long position as a
+// balance of its base asset, so sell pre-checks see the coins a grid can close
+func (bc *BinanceClient) getFuturesBalances() (map[string]decimal.Decimal, error) {
+	body, err := bc.signedRequest("GET", "/fapi/v2/balance", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	var assets []struct {
+		Asset            string `json:"asset"`
+		AvailableBalance string `json:"availableBalance"`
+	}
+	if err := json.Unmarshal(body, &assets); err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]decimal.Decimal, len(assets))
+	for _, a := range assets {
+		if available, err := decimal.NewFromString(a.AvailableBalance); err == nil && !available.IsZero() {
+			balances[a.Asset] = available
+		}
+	}
+
+	positions, err := bc.GetPositions()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range positions {
+		if p.PositionAmt.IsPositive() {
+			asset := bc.baseAssetOf(p.Symbol)
+			balances[asset] = balances[asset].Add(p.PositionAmt)
+		}
+	}
+
+	return balances, nil
+}
+
+// baseAssetOf returns the traded coin of a symbol from cached rules, e.g. ETH for ETHUSDT
+func (bc *BinanceClient) baseAssetOf(symbol string) string {
+	bc.symbolInfoMutex.RLock()
+	defer bc.symbolInfoMutex.RUnlock()
+	if info, ok := bc.symbolInfo[symbol]; ok && info.BaseAsset != "" {
+		return info.BaseAsset
+	}
+	return strings.TrimSuffix(symbol, "USDT")
+}
+
+// GetPositions returns open USDT-M positions
+func (bc *BinanceClient) GetPositions() ([]contracts.FuturesPosition, error) {
+	if bc.futures == nil {
+		return nil, errNotFutures()
+	}
+
+	body, err := bc.signedRequest("GET", "/fapi/v2/positionRisk", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []struct {
+		Symbol           string `json:"symbol"`
+		PositionSide     string `json:"positionSide"`
+		PositionAmt      string `json:"positionAmt"`
+		EntryPrice       string `json:"entryPrice"`
+		MarkPrice        string `json:"markPrice"`
+		UnRealizedProfit string `json:"unRealizedProfit"`
+		Leverage         string `json:"leverage"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	positions := []contracts.FuturesPosition{}
+	for _, r := range raw {
+		amount, _ := decimal.NewFromString(r.PositionAmt)
+		if amount.IsZero() {
+			continue
+		}
+		position := contracts.FuturesPosition{
+			Symbol:       r.Symbol,
+			PositionSide: r.PositionSide,
+			PositionAmt:  amount,
+		}
+		position.EntryPrice, _ = decimal.NewFromString(r.EntryPrice)
+		position.MarkPrice, _ = decimal.NewFromString(r.MarkPrice)
+		position.UnrealizedProfit, _ = decimal.NewFromString(r.UnRealizedProfit)
+		position.Leverage, _ = strconv.Atoi(r.Leverage)
+		positions = append(positions, position)
+	}
+
+	return positions, nil
+}
+
+// GetFundingFees returns funding payments since the given time, oldest first (at most 1000)
+func (bc *BinanceClient) GetFundingFees(symbol string, since time.Time) ([]contracts.FundingFee, error) {
+	if bc.futures == nil {
+		return nil, errNotFutures()
+	}
+
+	params := url.Values{}
+	params.Set("incomeType", "FUNDING_FEE")
+	params.Set("limit", "1000")
+	if symbol != "" {
+		params.Set("symbol", symbol)
+	}
+	if !since.IsZero() {
+		params.Set("startTime", strconv.FormatInt(since.UnixMilli(), 10))
+	}
+
+	body, err := bc.signedRequest("GET", "/fapi/v1/income", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []struct {
+		Symbol string      `json:"symbol"`
+		Income string      `json:"income"`
+		Asset  string      `json:"asset"`
+		Time   int64       `json:"time"`
+		TranID json.Number `json:"tranId"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	fees := make([]contracts.FundingFee, 0, len(raw))
+	for _, r := range raw {
+		amount, err := decimal.NewFromString(r.Income)
+		if err != nil {
+			continue
+		}
+		fees = append(fees, contracts.FundingFee{
+			ID:       r.TranID.String(),
+			Symbol:   r.Symbol,
+			Asset:    r.Asset,
+			Amount:   amount,
+			FundedAt: time.UnixMilli(r.Time).UTC(),
+		})
+	}
+
+	return fees, nil
+}
+
+func isBinanceCode(err error, code int) bool {
+	orderErr, ok := err.(*OrderError)
+	return ok && orderErr.BinanceCode == code
+}
+
+func errNotFutures() *OrderError {
+	return &OrderError{Code: ErrNotFutures, Message: "account does not trade futures - use the futures account"}
+}
@@ -0,0 +1,496 @@
+package exchange
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+const KrakenAPIURL = "https://api.kraken.com"
+
+// KrakenClient trades a Kraken spot account (EXCHANGE=kraken). Kraken names its orders with
+// string txids, while the grid tracks numeric order IDs - every order is placed with a unique
+// userref, which Kraken accepts in place of the txid for queries and cancels, and that userref
+// is the order ID reported back.
+type KrakenClient struct {
+	apiKey    string
+	apiSecret string
+	baseURL   string
+	client    *http.Client
+
+	// Nonces and userrefs only ever increase
+	counterMu sync.Mutex
+	lastNonce int64
+	lastRef   int64
+
+	// Trading rules by symbol (e.g. ETHUSDT)
+	pairs   map[string]*SymbolInfo
+	pairsMu sync.RWMutex
+}
+
+func NewKrakenClient(apiKey, apiSecret string) *KrakenClient {
+	return &KrakenClient{
+		apiKey:    apiKey,
+		apiSecret: apiSecret,
+		baseURL:   KrakenAPIURL,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		pairs:     make(map[string]*SymbolInfo),
+	}
+}
+
+// UseAPIURL points calls at another Kraken-compatible endpoint
+func (kc *KrakenClient) UseAPIURL(baseURL string) {
+	kc.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// krakenOrder is an order as OpenOrders and ClosedOrders return it
+type krakenOrder struct {
+	UserRef int64   `json:"userref"`
+	Status  string  `json:"status"` // pending | open | closed | canceled | expired
+	OpenTm  float64 `json:"opentm"`
+	CloseTm float64 `json:"closetm"`
+	Descr   struct {
+		Type      string `json:"type"` // buy | sell
+		OrderType string `json:"ordertype"`
+		Price     string `json:"price"`
+	} `json:"descr"`
+	Vol     string `json:"vol"`
+	VolExec string `json:"vol_exec"`
+	Cost    string `json:"cost"`
+}
+
+// PlaceOrder places a GTC limit or a market order, adjusting price and quantity to the pair's
+// rules like BinanceClient.PlaceOrder does
+func (kc *KrakenClient) PlaceOrder(symbol string, side models.OrderSide, orderType models.OrderType, price, quantity decimal.Decimal, clientOrderID string) (*models.BinanceOrder, error) {
+	if kc.apiKey == "" || kc.apiSecret == "" {
+		return nil, fmt.Errorf("Kraken API credentials not configured - cannot place orders")
+	}
+
+	info, err := kc.GetSymbolRules(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol info: %w", err)
+	}
+
+	requestedQuantity := quantity
+	if info.TickSize.IsPositive() {
+		price = price.Div(info.TickSize).Round(0).Mul(info.TickSize)
+	}
+	if info.StepSize.IsPositive() {
+		quantity = quantity.Div(info.StepSize).Round(0).Mul(info.StepSize)
+	}
+
+	// Raise the quantity to the pair's minimum cost and volume, with the same 1% buffer as Binance orders
+	if price.Mul(quantity).LessThan(info.MinNotional) {
+		quantity = info.MinNotional.Mul(decimal.NewFromFloat(1.01)).Div(price)
+		if info.StepSize.IsPositive() {
+			quantity = quantity.Div(info.StepSize).Ceil().Mul(info.StepSize)
+		}
+		slog.Info("Adjusted quantity to meet Kraken min cost", logging.KeySymbol, symbol, "requested", requestedQuantity,
+			"quantity", quantity, "min_notional", info.MinNotional)
+	}
+	if quantity.LessThan(info.MinQty) {
+		slog.Info("Adjusted quantity to meet Kraken min volume", logging.KeySymbol, symbol, "requested", requestedQuantity,
+			"quantity", info.MinQty)
+		quantity = info.MinQty
+	}
+	if !quantity.IsPositive() {
+		return nil, newFilterError(ErrOrderTooSmall, "LOT_SIZE", info, price, requestedQuantity, quantity,
+			fmt.Sprintf("adjusted quantity %s is not positive", quantity))
+	}
+
+	// Kraken counts rejected orders against the trading rate limit - fail fast on insufficient balance
+	if err := kc.checkBalance(info, side, price, quantity); err != nil {
+		return nil, err
+	}
+
+	userRef := kc.nextUserRef()
+	params := url.Values{}
+	params.Set("pair", symbol)
+	params.Set("type", strings.ToLower(string(side)))
+	if orderType == models.OrderTypeMarket {
+		params.Set("ordertype", "market")
+	} else {
+		params.Set("ordertype", "limit")
+		params.Set("price", price.String())
+	}
+	params.Set("volume", quantity.String())
+	params.Set("userref", strconv.FormatInt(userRef, 10))
+
+	var result struct {
+		TxID []string `json:"txid"`
+	}
+	if err := kc.private("/0/private/AddOrder", params, &result); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Placed order on Kraken", logging.KeyOrderID, userRef, "txid", strings.Join(result.TxID, ","),
+		logging.KeySymbol, symbol, "side", side, "price", price, "quantity", quantity)
+
+	return &models.BinanceOrder{
+		Symbol:              symbol,
+		OrderID:             userRef,
+		ClientOrderID:       clientOrderID,
+		Price:               price.String(),
+		OrigQty:             quantity.String(),
+		ExecutedQty:         "0",
+		CummulativeQuoteQty: "0",
+		Status:              "NEW",
+		Type:                strings.ToUpper(params.Get("ordertype")),
+		Side:                strings.ToUpper(string(side)),
+		Time:                time.Now().UnixMilli(),
+		IsWorking:           true,
+	}, nil
+}
+
+// GetOrder looks the order up among open orders, then closed ones. Returns nil if Kraken has neither.
+func (kc *KrakenClient) GetOrder(symbol, orderID string) (*models.BinanceOrder, error) {
+	if kc.apiKey == "" || kc.apiSecret == "" {
+		return nil, fmt.Errorf("Kraken API credentials not configured - cannot get order status")
+	}
+	if _, err := strconv.ParseInt(orderID, 10, 32); err != nil {
+		return nil, fmt.Errorf("invalid Kraken order ID %q", orderID)
+	}
+
+	params := url.Values{}
+	params.Set("userref", orderID)
+	var open struct {
+		Open map[string]krakenOrder `json:"open"`
+	}
+	if err := kc.private("/0/private/OpenOrders", params, &open); err != nil {
+		return nil, err
+	}
+	if order := newestKrakenOrder(open.Open); order != nil {
+		return order.toBinance(symbol), nil
+	}
+
+	params = url.Values{}
+	params.Set("userref", orderID)
+	var closed struct {
+		Closed map[string]krakenOrder `json:"closed"`
+	}
+	if err := kc.private("/0/private/ClosedOrders", params, &closed); err != nil {
+		return nil, err
+	}
+	if order := newestKrakenOrder(closed.Closed); order != nil {
+		return order.toBinance(symbol), nil
+	}
+	return nil, nil
+}
+
+// CancelOrder cancels an open order and returns its final state
+func (kc *KrakenClient) CancelOrder(symbol, orderID string) (*models.BinanceOrder, error) {
+	if kc.apiKey == "" || kc.apiSecret == "" {
+		return nil, fmt.Errorf("Kraken API credentials not configured - cannot cancel orders")
+	}
+
+	params := url.Values{}
+	params.Set("txid", orderID) // A userref cancels the orders placed with it
+	if err := kc.private("/0/private/CancelOrder", params, nil); err != nil {
+		return nil, err
+	}
+
+	order, err := kc.GetOrder(symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, fmt.Errorf("order %s not found on Kraken after cancel", orderID)
+	}
+	slog.Info("Cancelled Kraken order", logging.KeyOrderID, orderID, logging.KeySymbol, symbol)
+	return order, nil
+}
+
+// GetSymbolRules returns a pair's trading rules, fetching them on first use or when stale
+func (kc *KrakenClient) GetSymbolRules(symbol string) (*SymbolInfo, error) {
+	kc.pairsMu.RLock()
+	info, ok := kc.pairs[symbol]
+	kc.pairsMu.RUnlock()
+	if ok && time.Since(info.FetchedAt) < symbolInfoMaxAge {
+		return info, nil
+	}
+
+	slog.Info("Fetching pair info from Kraken", logging.KeySymbol, symbol)
+	var pairs map[string]struct {
+		WSName       string `json:"wsname"` // BASE/QUOTE
+		PairDecimals int32  `json:"pair_decimals"`
+		LotDecimals  int32  `json:"lot_decimals"`
+		OrderMin     string `json:"ordermin"`
+		CostMin      string `json:"costmin"`
+		TickSize     string `json:"tick_size"`
+	}
+	if err := kc.public("/0/public/AssetPairs?pair="+url.QueryEscape(symbol), &pairs); err != nil {
+		return nil, err
+	}
+
+	for _, pair := range pairs {
+		base, quote, ok := strings.Cut(pair.WSName, "/")
+		if !ok {
+			continue
+		}
+		info = &SymbolInfo{
+			BaseAsset:  krakenAsset(base),
+			QuoteAsset: krakenAsset(quote),
+			StepSize:   decimal.New(1, -pair.LotDecimals),
+			TickSize:   decimal.New(1, -pair.PairDecimals),
+			FetchedAt:  time.Now(),
+		}
+		info.MinQty, _ = decimal.NewFromString(pair.OrderMin)
+		info.MinNotional, _ = decimal.NewFromString(pair.CostMin)
+		if tick, err := decimal.NewFromString(pair.TickSize); err == nil && tick.IsPositive() {
+			info.TickSize = tick
+		}
+
+		kc.pairsMu.Lock()
+		kc.pairs[symbol] = info
+		kc.pairsMu.Unlock()
+		return info, nil
+	}
+	return nil, &OrderError{Code: ErrInvalidSymbol, Message: fmt.Sprintf("Kraken has no pair %s", symbol)}
+}
+
+// GetBalances returns each asset's balance less what open orders hold, under Binance asset names
+func (kc *KrakenClient) GetBalances() (map[string]decimal.Decimal, error) {
+	if kc.apiKey == "" || kc.apiSecret == "" {
+		return nil, fmt.Errorf("Kraken API credentials not configured - cannot get account balances")
+	}
+
+	var result map[string]struct {
+		Balance   string `json:"balance"`
+		HoldTrade string `json:"hold_trade"`
+	}
+	if err := kc.private("/0/private/BalanceEx", url.Values{}, &result); err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]decimal.Decimal, len(result))
+	for asset, b := range result {
+		balance, err := decimal.NewFromString(b.Balance)
+		if err != nil {
+			continue
+		}
+		held, _ := decimal.NewFromString(b.HoldTrade)
+		if free := balance.Sub(held); free.IsPositive() {
+			balances[krakenAsset(asset)] = free
+		}
+	}
+	return balances, nil
+}
+
+// checkBalance verifies the free balance covers the order like BinanceClient's pre-check, reading
+// balances fresh - Kraken places too few grid orders to warrant a cache. Lookup failures are
+// logged and do not block the order.
+func (kc *KrakenClient) checkBalance(info *SymbolInfo, side models.OrderSide, price, quantity decimal.Decimal) error {
+	asset, required := info.QuoteAsset, price.Mul(quantity)
+	if side == models.SideSell {
+		asset, required = info.BaseAsset, quantity
+	}
+	if asset == "" {
+		return nil
+	}
+
+	balances, err := kc.GetBalances()
+	if err != nil {
+		slog.Warn("Balance pre-check skipped, failed to fetch Kraken balances", logging.Err(err))
+		return nil
+	}
+	if free := balances[asset]; free.LessThan(required) {
+		return insufficientFunds(asset, required, free)
+	}
+	return nil
+}
+
+// public calls an unauthenticated endpoint
+func (kc *KrakenClient) public(path string, result interface{}) error {
+	req, err := http.NewRequest("GET", kc.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	return kc.do(req, result)
+}
+
+// private signs a call with API-Sign = HMAC-SHA512(path + SHA256(nonce + body)) keyed with the decoded secret
+func (kc *KrakenClient) private(path string, params url.Values, result interface{}) error {
+	secret, err := base64.StdEncoding.DecodeString(kc.apiSecret)
+	if err != nil {
+		return &OrderError{Code: ErrAuthFailed, Message: "Kraken API secret is not valid base64"}
+	}
+
+	nonce := strconv.FormatInt(kc.nextNonce(), 10)
+	params.Set("nonce", nonce)
+	body := params.Encode()
+
+	digest := sha256.Sum256([]byte(nonce + body))
+	mac := hmac.New(sha512.New, secret)
+	mac.Write([]byte(path))
+	mac.Write(digest[:])
+
+	req, err := http.NewRequest("POST", kc.baseURL+path, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("API-Key", kc.apiKey)
+	req.Header.Set("API-Sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return kc.do(req, result)
+}
+
+// do sends a request and unwraps Kraken's {error, result} envelope
+func (kc *KrakenClient) do(req *http.Request, result interface{}) error {
+	resp, err := kc.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return &OrderError{Code: ErrExchangeFailure, Message: fmt.Sprintf("kraken returned status %d", resp.StatusCode)}
+	}
+
+	var envelope struct {
+		Error  []string        `json:"error"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("invalid Kraken response (status %d): %w", resp.StatusCode, err)
+	}
+	if len(envelope.Error) > 0 {
+		return parseKrakenError(envelope.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(envelope.Result, result)
+}
+
+func (kc *KrakenClient) nextNonce() int64 {
+	kc.counterMu.Lock()
+	defer kc.counterMu.Unlock()
+
+	nonce := time.Now().UnixMicro()
+	if nonce <= kc.lastNonce {
+		nonce = kc.lastNonce + 1
+	}
+	kc.lastNonce = nonce
+	return nonce
+}
+
+// nextUserRef returns a userref unique for years: tenths of a second, wrapping at Kraken's
+// signed 32-bit limit after about 6.8 years
+func (kc *KrakenClient) nextUserRef() int64 {
+	kc.counterMu.Lock()
+	defer kc.counterMu.Unlock()
+
+	ref := time.Now().UnixMilli() / 100 % math.MaxInt32
+	if ref <= kc.lastRef {
+		ref = kc.lastRef + 1
+	}
+	kc.lastRef = ref
+	return ref
+}
+
+// newestKrakenOrder picks the latest order of a userref query
+func newestKrakenOrder(orders map[string]krakenOrder) *krakenOrder {
+	var newest *krakenOrder
+	for _, order := range orders {
+		order := order
+		if newest == nil || order.OpenTm > newest.OpenTm {
+			newest = &order
+		}
+	}
+	return newest
+}
+
+// toBinance converts the order to Binance's shape and status names
+func (o *krakenOrder) toBinance(symbol string) *models.BinanceOrder {
+	executed, _ := decimal.NewFromString(o.VolExec)
+	status := "NEW"
+	switch o.Status {
+	case "closed":
+		status = "FILLED"
+	case "canceled":
+		status = "CANCELED"
+	case "expired":
+		status = "EXPIRED"
+	default:
+		if executed.IsPositive() {
+			status = "PARTIALLY_FILLED"
+		}
+	}
+
+	order := &models.BinanceOrder{
+		Symbol:              symbol,
+		OrderID:             o.UserRef,
+		Price:               o.Descr.Price,
+		OrigQty:             o.Vol,
+		ExecutedQty:         o.VolExec,
+		CummulativeQuoteQty: o.Cost,
+		Status:              status,
+		Type:                strings.ToUpper(o.Descr.OrderType),
+		Side:                strings.ToUpper(o.Descr.Type),
+		Time:                int64(o.OpenTm * 1000),
+		UpdateTime:          int64(o.CloseTm * 1000),
+		IsWorking:           status == "NEW" || status == "PARTIALLY_FILLED",
+	}
+	if order.UpdateTime == 0 {
+		order.UpdateTime = order.Time
+	}
+	return order
+}
+
+// krakenAsset maps Kraken asset codes (XXBT, ZUSD, XDG) to Binance's (BTC, USD, DOGE)
+func krakenAsset(asset string) string {
+	if len(asset) == 4 && (asset[0] == 'X' || asset[0] == 'Z') {
+		asset = asset[1:]
+	}
+	switch asset {
+	case "XBT":
+		return "BTC"
+	case "XDG":
+		return "DOGE"
+	}
+	return asset
+}
+
+// parseKrakenError classifies Kraken's "Category:Message" errors into the shared taxonomy
+func parseKrakenError(errs []string) *OrderError {
+	msg := strings.Join(errs, "; ")
+	code := ErrExchangeRejected
+	switch {
+	case strings.Contains(msg, "Insufficient funds"):
+		code = ErrInsufficientFunds
+	case strings.Contains(msg, "minimum not met"):
+		code = ErrOrderTooSmall
+	case strings.Contains(msg, "Unknown asset pair"), strings.Contains(msg, "Invalid asset pair"):
+		code = ErrInvalidSymbol
+	case strings.Contains(msg, "Rate limit"), strings.Contains(msg, "Too many requests"):
+		code = ErrRateLimited
+	case strings.Contains(msg, "Invalid nonce"):
+		code = ErrClockSkew
+	case strings.HasPrefix(msg, "EAPI:Invalid key"), strings.HasPrefix(msg, "EAPI:Invalid signature"),
+		strings.Contains(msg, "Permission denied"):
+		code = ErrAuthFailed
+	case strings.HasPrefix(msg, "EService:"), strings.Contains(msg, "Internal error"):
+		code = ErrExchangeFailure
+	}
+	return &OrderError{Code: code, Message: "kraken: " + msg}
+}
@@ -0,0 +1,253 @@
+package exchange
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/shopspring/decimal"
+)
+
+// MarginConfig sets up a client for cross or isolated margin
+type MarginConfig struct {
+	Isolated   bool // Isolated margin (one account per symbol); cross margin otherwise
+	AutoBorrow bool // Buys borrow the missing quote asset (MARGIN_BUY)
+}
+
+// marginPaths maps spot REST paths to their margin equivalents. Market data stays on /api/v3.
+var marginPaths = map[string]string{
+	"/api/v3/order":      "/sapi/v1/margin/order",
+	"/api/v3/allOrders":  "/sapi/v1/margin/allOrders",
+	"/api/v3/openOrders": "/sapi/v1/margin/openOrders",
+	"/api/v3/myTrades":   "/sapi/v1/margin/myTrades",
+}
+
+// EnableMargin switches the client's orders to the margin account. Sells always repay
+// debt first (AUTO_REPAY); buys only borrow with AutoBorrow.
+func (bc *BinanceClient) EnableMargin(cfg MarginConfig) {
+	bc.margin = &cfg
+	mode := "cross"
+	if cfg.Isolated {
+		mode = "isolated"
+	}
+	slog.Info("Margin enabled", "mode", mode, "auto_borrow", cfg.AutoBorrow)
+}
+
+// IsMargin reports whether the client trades on the margin account
+func (bc *BinanceClient) IsMargin() bool {
+	return bc.margin != nil
+}
+
+// IsSpot reports whether the client trades on the spot wallet
+func (bc *BinanceClient) IsSpot() bool {
+	return bc.futures == nil && bc.margin == nil
+}
+
+// marginParams marks order requests of an isolated margin client
+func (bc *BinanceClient) marginParams(params url.Values) {
+	if bc.margin != nil && bc.margin.Isolated {
+		params.Set("isIsolated", "TRUE")
+	}
+}
+
+// marginOrderParams sets the borrow/repay side effect of a new order
+func (bc *BinanceClient) marginOrderParams(params url.Values, side string) {
+	switch {
+	case side == "SELL":
+		params.Set("sideEffectType", "AUTO_REPAY")
+	case bc.margin.AutoBorrow:
+		params.Set("sideEffectType", "MARGIN_BUY")
+	default:
+		params.Set("sideEffectType", "NO_SIDE_EFFECT")
+	}
+}
+
+// marginAsset is one asset of a margin account
+type marginAsset struct {
+	Asset    string `json:"asset"`
+	Free     string `json:"free"`
+	Locked   string `json:"locked"`
+	Borrowed string `json:"borrowed"`
+	Interest string `json:"interest"`
+	NetAsset string `json:"netAsset"`
+}
+
+// getMarginAssets returns the assets of the cross margin account, or of every isolated pair
+func (bc *BinanceClient) getMarginAssets() ([]contracts.MarginDebt, error) {
+	var assets []contracts.MarginDebt
+	add := func(symbol string, a marginAsset) {
+		debt := contracts.MarginDebt{Symbol: symbol, Asset: a.Asset}
+		debt.Free, _ = decimal.NewFromString(a.Free)
+		debt.Borrowed, _ = decimal.NewFromString(a.Borrowed)
+		debt.Interest, _ = decimal.NewFromString(a.Interest)
+		debt.NetAsset, _ = decimal.NewFromString(a.NetAsset)
+		if !debt.Free.IsZero() || !debt.Borrowed.IsZero() || !debt.Interest.IsZero() {
+			assets = append(assets, debt)
+		}
+	}
+
+	if bc.margin.Isolated {
+		body, err := bc.signedRequest("GET", "/sapi/v1/margin/isolated/account", url.Values{})
+		if err != nil {
+			return nil, err
+		}
+		var account struct {
+			Assets []struct {
+				Symbol     string      `json:"symbol"`
+				BaseAsset  marginAsset `json:"baseAsset"`
+				QuoteAsset marginAsset `json:"quoteAsset"`
+			} `json:"assets"`
+		}
+		if err := json.Unmarshal(body, &account); err != nil {
+			return nil, err
+		}
+		for _, pair := range account.Assets {
+			add(pair.Symbol, pair.BaseAsset)
+			add(pair.Symbol, pair.QuoteAsset)
+		}
+		return assets, nil
+	}
+
+	body, err := bc.signedRequest("GET", "/sapi/v1/margin/account", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	var account struct {
+		UserAssets []marginAsset `json:"userAssets"`
+	}
+	if err := json.Unmarshal(body, &account); err != nil {
+		return nil, err
+	}
+	for _, a := range account.UserAssets {
+		add("", a)
+	}
+	return assets, nil
+}
+
+// getMarginBalances returns free margin balances. Isolated pairs are summed per asset,
+// which is exact as long as each symbol's grid only trades its own pair.
+func (bc *BinanceClient) getMarginBalances() (map[string]decimal.Decimal, error) {
+	assets, err := bc.getMarginAssets()
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]decimal.Decimal, len(assets))
+	for _, a := range assets {
+		if !a.Free.IsZero() {
+			balances[a.Asset] = balances[a.Asset].Add(a.Free)
+		}
+	}
+	return balances, nil
+}
+
+// GetMarginDebts returns borrowed amounts and outstanding interest per asset
+func (bc *BinanceClient) GetMarginDebts() ([]contracts.MarginDebt, error) {
+	if bc.margin == nil {
+		return nil, errNotMargin()
+	}
+
+	assets, err := bc.getMarginAssets()
+	if err != nil {
+		return nil, err
+	}
+
+	debts := []contracts.MarginDebt{}
+	for _, a := range assets {
+		if a.Borrowed.IsPositive() || a.Interest.IsPositive() {
+			debts = append(debts, a)
+		}
+	}
+	return debts, nil
+}
+
+// GetMarginInterest returns interest charged since the given time, oldest first (at most
+// 100 per cross account or isolated pair)
+func (bc *BinanceClient) GetMarginInterest(since time.Time) ([]contracts.MarginInterest, error) {
+	if bc.margin == nil {
+		return nil, errNotMargin()
+	}
+
+	if !bc.margin.Isolated {
+		return bc.getMarginInterest("", since)
+	}
+
+	// Isolated interest is only listed per pair
+	assets, err := bc.getMarginAssets()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	charges := []contracts.MarginInterest{}
+	for _, a := range assets {
+		if seen[a.Symbol] {
+			continue
+		}
+		seen[a.Symbol] = true
+		pairCharges, err := bc.getMarginInterest(a.Symbol, since)
+		if err != nil {
+			return nil, err
+		}
+		charges = append(charges, pairCharges...)
+	}
+	sort.SliceStable(charges, func(i, j int) bool { return charges[i].ChargedAt.Before(charges[j].ChargedAt) })
+
+	return charges, nil
+}
+
+func (bc *BinanceClient) getMarginInterest(isolatedSymbol string, since time.Time) ([]contracts.MarginInterest, error) {
+	params := url.Values{}
+	params.Set("size", "100")
+	if isolatedSymbol != "" {
+		params.Set("isolatedSymbol", isolatedSymbol)
+	}
+	if !since.IsZero() {
+		params.Set("startTime", strconv.FormatInt(since.UnixMilli(), 10))
+	}
+
+	body, err := bc.signedRequest("GET", "/sapi/v1/margin/interestHistory", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var history struct {
+		Rows []struct {
+			TxID           json.Number `json:"txId"`
+			Asset          string      `json:"asset"`
+			Interest       string      `json:"interest"`
+			IsolatedSymbol string      `json:"isolatedSymbol"`
+			AccruedTime    int64       `json:"interestAccuredTime"` // Sic
+		} `json:"rows"`
+	}
+	if err := json.Unmarshal(body, &history); err != nil {
+		return nil, err
+	}
+
+	charges := make([]contracts.MarginInterest, 0, len(history.Rows))
+	for _, r := range history.Rows {
+		amount, err := decimal.NewFromString(r.Interest)
+		if err != nil {
+			continue
+		}
+		charges = append(charges, contracts.MarginInterest{
+			ID:        r.TxID.String(),
+			Symbol:    r.IsolatedSymbol,
+			Asset:     r.Asset,
+			Amount:    amount,
+			ChargedAt: time.UnixMilli(r.AccruedTime).UTC(),
+		})
+	}
+
+	// Binance returns the newest first
+	sort.SliceStable(charges, func(i, j int) bool { return charges[i].ChargedAt.Before(charges[j].ChargedAt) })
+
+	return charges, nil
+}
+
+func errNotMargin() *OrderError {
+	return &OrderError{Code: ErrNotMargin, Message: "account does not trade margin - use the margin account"}
+}
@@ -0,0 +1,95 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Ping checks connectivity to the Binance REST API
+func (bc *BinanceClient) Ping() error {
+	req, err := http.NewRequest("GET", bc.endpoint("/api/v3/ping"), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := bc.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return parseBinanceError(resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// GetServerTime returns Binance server time
+func (bc *BinanceClient) GetServerTime() (time.Time, error) {
+	req, err := http.NewRequest("GET", bc.endpoint("/api/v3/time"), nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	resp, err := bc.do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, parseBinanceError(resp.StatusCode, body)
+	}
+
+	var serverTime struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+	if err := json.Unmarshal(body, &serverTime); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse server time: %w", err)
+	}
+
+	return time.UnixMilli(serverTime.ServerTime), nil
+}
+
+// GetSystemStatus reports whether Binance is under system maintenance, with its message
+func (bc *BinanceClient) GetSystemStatus() (maintenance bool, msg string, err error) {
+	req, err := http.NewRequest("GET", bc.endpoint("/sapi/v1/system/status"), nil)
+	if err != nil {
+		return false, "", err
+	}
+
+	resp, err := bc.do(req)
+	if err != nil {
+		return false, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, "", parseBinanceError(resp.StatusCode, body)
+	}
+
+	var status struct {
+		Status int    `json:"status"` // 0 normal, 1 system maintenance
+		Msg    string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return false, "", fmt.Errorf("failed to parse system status: %w", err)
+	}
+
+	return status.Status != 0, status.Msg, nil
+}
@@ -0,0 +1,75 @@
+package exchange
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// PlaceMarketSell sells quantity coins at the best bid with a MARKET order, e.g. to close a
+// grid's holdings when it is torn down. The quantity is rounded down to the step size so no
+// more than is held is sold; price is only the estimate the minimum notional is checked with.
+func (bc *BinanceClient) PlaceMarketSell(symbol string, price, quantity decimal.Decimal, clientOrderID string) (*models.BinanceOrder, error) {
+	info, err := bc.getSymbolInfo(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol info: %w", err)
+	}
+
+	requested := quantity
+	if info.StepSize.IsPositive() {
+		quantity = quantity.Div(info.StepSize).Floor().Mul(info.StepSize)
+	}
+	if quantity.LessThan(info.MinQty) || !quantity.IsPositive() {
+		return nil, newFilterError(ErrOrderTooSmall, "LOT_SIZE", info, price, requested, quantity,
+			fmt.Sprintf("quantity %s below minimum %s", quantity, info.MinQty))
+	}
+	if notional := price.Mul(quantity); notional.LessThan(info.MinNotional) {
+		return nil, newFilterError(ErrOrderTooSmall, "MIN_NOTIONAL", info, price, requested, quantity,
+			fmt.Sprintf("notional %s below minimum %s", notional, info.MinNotional))
+	}
+
+	if !bc.hasCredentials() {
+		return nil, fmt.Errorf("Binance API credentials not configured - cannot place orders")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("side", "SELL")
+	params.Set("type", "MARKET")
+	params.Set("quantity", quantity.String())
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	params.Set("recvWindow", "5000")
+	if clientOrderID != "" {
+		params.Set("newClientOrderId", clientOrderID)
+	}
+	if bc.futures != nil {
+		bc.futuresOrderParams(params, "SELL")
+		if err := bc.prepareFutures(symbol); err != nil {
+			return nil, err
+		}
+	}
+	if bc.margin != nil {
+		bc.marginOrderParams(params, "SELL")
+	}
+
+	if err := bc.checkBalance(info, models.SideSell, price, quantity); err != nil {
+		return nil, err
+	}
+
+	order, err := bc.submitOrder(params)
+	if err != nil {
+		return nil, err
+	}
+
+	bc.invalidateBalances()
+	slog.Info("Placed market sell on Binance", logging.KeyOrderID, order.OrderID, logging.KeySymbol, symbol,
+		"quantity", quantity, "executed", order.ExecutedQty, "quote", order.CummulativeQuoteQty)
+
+	return order, nil
+}
@@ -0,0 +1,136 @@
+package exchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// stopLegSuffix tells the stop-loss leg's client order ID from the limit leg's
+const stopLegSuffix = "-sl"
+
+// OCOSell is a one-cancels-the-other sell placed on Binance
+type OCOSell struct {
+	ListID int64
+	Limit  *models.BinanceOrder // LIMIT_MAKER at the take-profit price
+	Stop   *models.BinanceOrder // STOP_LOSS, a market sell once the price falls to the stop price
+}
+
+// PlaceOCOSell sells quantity coins with an OCO order list: a LIMIT_MAKER at price and a STOP_LOSS
+// triggered at stopPrice. Whichever leg fills, Binance expires the other, and cancelling either
+// cancels both. The limit leg takes clientOrderID, so a lost response is recovered like a single
+// order's; the stop leg takes it with a "-sl" suffix. The quantity is rounded down to the step size
+// so no more than is held is sold. Live spot accounts only, sent over REST.
+func (bc *BinanceClient) PlaceOCOSell(symbol string, price, stopPrice, quantity decimal.Decimal, clientOrderID string) (*OCOSell, error) {
+	if !bc.IsSpot() || bc.paper != nil {
+		return nil, &OrderError{Code: ErrUnsupportedOrder, Message: "OCO orders are only available on live spot accounts"}
+	}
+
+	info, err := bc.getSymbolInfo(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol info: %w", err)
+	}
+
+	requested := quantity
+	price = bc.roundToTickSize(price, info.TickSize)
+	stopPrice = bc.roundToTickSize(stopPrice, info.TickSize)
+	if info.StepSize.IsPositive() {
+		quantity = quantity.Div(info.StepSize).Floor().Mul(info.StepSize)
+	}
+
+	for _, p := range []decimal.Decimal{price, stopPrice} {
+		if (info.MinPrice.IsPositive() && p.LessThan(info.MinPrice)) || (info.MaxPrice.IsPositive() && p.GreaterThan(info.MaxPrice)) {
+			return nil, newFilterError(ErrFilterFailure, "PRICE_FILTER", info, p, requested, quantity,
+				fmt.Sprintf("price %s outside allowed range [%s - %s]", p, info.MinPrice, info.MaxPrice))
+		}
+	}
+	if !stopPrice.LessThan(price) {
+		return nil, &OrderError{
+			Code:    ErrFilterFailure,
+			Message: fmt.Sprintf("stop price %s must be below the limit price %s", stopPrice, price),
+			Details: map[string]string{"price": price.String(), "stop_price": stopPrice.String()},
+		}
+	}
+	if quantity.LessThan(info.MinQty) || !quantity.IsPositive() {
+		return nil, newFilterError(ErrOrderTooSmall, "LOT_SIZE", info, stopPrice, requested, quantity,
+			fmt.Sprintf("quantity %s below minimum %s", quantity, info.MinQty))
+	}
+	// The stop leg is the smaller of the two
+	if notional := stopPrice.Mul(quantity); notional.LessThan(info.MinNotional) {
+		return nil, newFilterError(ErrOrderTooSmall, "MIN_NOTIONAL", info, stopPrice, requested, quantity,
+			fmt.Sprintf("notional %s below minimum %s", notional, info.MinNotional))
+	}
+
+	if !bc.hasCredentials() {
+		return nil, fmt.Errorf("Binance API credentials not configured - cannot place orders")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("side", "SELL")
+	params.Set("quantity", quantity.String())
+	params.Set("aboveType", "LIMIT_MAKER")
+	params.Set("abovePrice", price.String())
+	params.Set("belowType", "STOP_LOSS")
+	params.Set("belowStopPrice", stopPrice.String())
+	params.Set("newOrderRespType", "RESULT")
+	if clientOrderID != "" {
+		params.Set("aboveClientOrderId", clientOrderID)
+		params.Set("belowClientOrderId", clientOrderID+stopLegSuffix)
+	}
+
+	if err := bc.checkBalance(info, models.SideSell, price, quantity); err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	body, err := bc.signedRequest("POST", "/api/v3/orderList/oco", params)
+	if err != nil {
+		status := 0
+		var orderErr *OrderError
+		if errors.As(err, &orderErr) {
+			status = orderErr.HTTPStatus
+		}
+		bc.auditPlacement(placementRest, params, status, nil, nil, err, started)
+		return nil, err
+	}
+
+	var list struct {
+		OrderListID  int64                 `json:"orderListId"`
+		OrderReports []models.BinanceOrder `json:"orderReports"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		bc.auditPlacement(placementRest, params, http.StatusOK, body, nil, err, started)
+		return nil, fmt.Errorf("failed to decode OCO order list: %w", err)
+	}
+
+	oco := &OCOSell{ListID: list.OrderListID}
+	for i := range list.OrderReports {
+		switch report := &list.OrderReports[i]; report.Type {
+		case "LIMIT_MAKER":
+			oco.Limit = report
+		case "STOP_LOSS":
+			oco.Stop = report
+		}
+	}
+	if oco.Limit == nil || oco.Stop == nil {
+		err := fmt.Errorf("OCO order list %d came back without both legs", list.OrderListID)
+		bc.auditPlacement(placementRest, params, http.StatusOK, body, nil, err, started)
+		return nil, err
+	}
+	bc.auditPlacement(placementRest, params, http.StatusOK, body, oco.Limit, nil, started)
+
+	bc.invalidateBalances()
+	slog.Info("Placed OCO sell on Binance", "list_id", oco.ListID, logging.KeySymbol, symbol, "quantity", quantity,
+		"price", price, logging.KeyOrderID, oco.Limit.OrderID, "stop_price", stopPrice, "stop_order_id", oco.Stop.OrderID)
+
+	return oco, nil
+}
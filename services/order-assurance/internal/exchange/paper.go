@@ -0,0 +1,15 @@
+package exchange
+
+import "net/http"
+
+// PaperExchange answers order and account requests in place of Binance (TRADING_MODE=paper).
+// Response returns nil for requests it leaves to Binance, such as market data.
+type PaperExchange interface {
+	Response(req *http.Request) *http.Response
+}
+
+// EnablePaperTrading sends order and account requests to a simulated exchange. Spot only -
+// the WebSocket API, futures and margin must stay off.
+func (bc *BinanceClient) EnablePaperTrading(paper PaperExchange) {
+	bc.paper = paper
+}
@@ -0,0 +1,85 @@
+package exchange
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+// Transports an order submission can take
+const (
+	placementRest      = "rest"
+	placementWebSocket = "websocket"
+)
+
+// maxAuditedReply caps the stored reply - Binance order replies are well under 2KB
+const maxAuditedReply = 16 * 1024
+
+// PlacementAttempt is one order submission to Binance: the parameters exactly as sent, without
+// the signature and API key, and the exchange's raw reply
+type PlacementAttempt struct {
+	Transport  string     // rest | websocket
+	Params     url.Values // Sanitized request parameters
+	StatusCode int        // HTTP or WebSocket API status (0 = no reply arrived)
+	Reply      string     // Raw reply body
+	OrderID    string     // Set when the reply placed an order
+	Err        error
+	Duration   time.Duration
+}
+
+// UsePlacementAudit hands every order submission of this client to record, successful or not.
+// record runs synchronously on the placement path, so it must be quick.
+func (bc *BinanceClient) UsePlacementAudit(record func(PlacementAttempt)) {
+	bc.placementAudit = record
+}
+
+func (bc *BinanceClient) auditPlacement(transport string, params url.Values, status int, reply []byte, order *models.BinanceOrder, err error, started time.Time) {
+	if bc.placementAudit == nil {
+		return
+	}
+
+	attempt := PlacementAttempt{
+		Transport:  transport,
+		Params:     sanitizeParams(params),
+		StatusCode: status,
+		Err:        err,
+		Duration:   time.Since(started),
+	}
+	if len(reply) > maxAuditedReply {
+		reply = reply[:maxAuditedReply]
+	}
+	attempt.Reply = string(reply)
+	if order != nil {
+		attempt.OrderID = strconv.FormatInt(order.OrderID, 10)
+	}
+
+	bc.placementAudit(attempt)
+}
+
+// auditWSPlacement audits an order.place call; reply is nil when the request got no answer
+func (bc *BinanceClient) auditWSPlacement(params url.Values, reply *wsResponse, order *models.BinanceOrder, err error, started time.Time) {
+	if reply == nil {
+		bc.auditPlacement(placementWebSocket, params, 0, nil, order, err, started)
+		return
+	}
+
+	body := []byte(reply.Result)
+	if reply.Status != 200 {
+		body = []byte(reply.Error)
+	}
+	bc.auditPlacement(placementWebSocket, params, reply.Status, body, order, err, started)
+}
+
+// sanitizeParams copies request parameters without credentials
+func sanitizeParams(params url.Values) url.Values {
+	clean := url.Values{}
+	for key, values := range params {
+		if key == "signature" || key == "apiKey" {
+			continue
+		}
+		clean[key] = append([]string(nil), values...)
+	}
+	return clean
+}
@@ -0,0 +1,78 @@
+package exchange
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// quoteAmountPlaces is the precision quote amounts are sent with - Binance accepts up to 8 decimals
+const quoteAmountPlaces = 8
+
+// PlaceQuoteBuy spends exactly quoteAmount of the quote asset with a MARKET buy (quoteOrderQty).
+// Binance works out the coin quantity from the book, so there is no quantity rounding; the
+// executed quantity and cost come back on the order. Spot and margin only - USDT-M futures
+// have no quote-quantity orders.
+func (bc *BinanceClient) PlaceQuoteBuy(symbol string, quoteAmount decimal.Decimal, clientOrderID string) (*models.BinanceOrder, error) {
+	if bc.futures != nil {
+		return nil, &OrderError{Code: ErrUnsupportedOrder, Message: "quote-quantity orders are not available on futures"}
+	}
+
+	info, err := bc.getSymbolInfo(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol info: %w", err)
+	}
+
+	quoteAmount = quoteAmount.RoundFloor(quoteAmountPlaces)
+	if quoteAmount.LessThan(info.MinNotional) {
+		return nil, &OrderError{
+			Code:    ErrOrderTooSmall,
+			Message: fmt.Sprintf("MIN_NOTIONAL filter failed: quote amount %s below minimum %s", quoteAmount, info.MinNotional),
+			Details: map[string]string{
+				"filter":       "MIN_NOTIONAL",
+				"quote_amount": quoteAmount.String(),
+				"min_notional": info.MinNotional.String(),
+			},
+		}
+	}
+
+	if !bc.hasCredentials() {
+		return nil, fmt.Errorf("Binance API credentials not configured - cannot place orders")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("side", "BUY")
+	params.Set("type", "MARKET")
+	params.Set("quoteOrderQty", quoteAmount.String())
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	params.Set("recvWindow", "5000")
+	if clientOrderID != "" {
+		params.Set("newClientOrderId", clientOrderID)
+	}
+	if bc.margin != nil {
+		bc.marginOrderParams(params, "BUY")
+	}
+
+	// Quote amount × 1 is the quote asset a buy locks
+	if err := bc.checkBalance(info, models.SideBuy, quoteAmount, decimal.NewFromInt(1)); err != nil {
+		return nil, err
+	}
+
+	order, err := bc.submitOrder(params)
+	if err != nil {
+		return nil, err
+	}
+
+	bc.invalidateBalances()
+	slog.Info("Placed quote-quantity buy on Binance", logging.KeyOrderID, order.OrderID, logging.KeySymbol, symbol,
+		"quote_amount", quoteAmount, "executed", order.ExecutedQty, "quote", order.CummulativeQuoteQty)
+
+	return order, nil
+}
@@ -0,0 +1,336 @@
+package exchange
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Binance REST limits per minute of request weight (per IP) and per 10 seconds of new orders
+// (per account). See https://developers.binance.com/docs/binance-spot-api-docs/rest-api/limits
+const (
+	spotWeightLimit    = 6000
+	spotOrderLimit     = 100
+	futuresWeightLimit = 2400
+	futuresOrderLimit  = 300
+)
+
+// rateLimitHeadroom is the share of each limit the scheduler leaves unused, for other clients
+// on the same IP and for estimates that run low
+const rateLimitHeadroom = 0.1
+
+// Defaults of RateLimitConfig
+const (
+	defaultRateLimitMaxWait = 10 * time.Second
+	defaultRateLimitRetries = 2
+)
+
+// RateLimitConfig tunes how long requests are held back and how 429s are retried
+type RateLimitConfig struct {
+	MaxWait time.Duration // Longest a request is delayed before failing with rate_limited
+	Retries int           // 429 responses retried after their Retry-After (0 = none)
+}
+
+// RateLimitStatus is a snapshot of an account's Binance usage, as last reported in response headers
+type RateLimitStatus struct {
+	Account       string `json:"account"`
+	UsedWeight1m  int    `json:"used_weight_1m"` // X-MBX-USED-WEIGHT-1M
+	WeightLimit1m int    `json:"weight_limit_1m"`
+	OrderCount10s int    `json:"order_count_10s"` // X-MBX-ORDER-COUNT-10S
+	OrderCount1d  int    `json:"order_count_1d,omitempty"`
+	OrderLimit10s int    `json:"order_limit_10s"`
+	Delayed       int64  `json:"delayed_requests"` // Requests held back so far to stay under the limits
+	Retried       int64  `json:"retried_requests"` // 429s retried after Retry-After
+	BackoffUntil  string `json:"backoff_until,omitempty"`
+	UpdatedAt     string `json:"updated_at,omitempty"` // When the headers were last read
+}
+
+// rateLimiter schedules a client's REST requests with token buckets of request weight and new
+// orders, refilled at the Binance limits less the headroom. Every response's usage headers pull
+// the buckets down to what Binance counted, so traffic from elsewhere on the IP is accounted for.
+// A request the buckets can't cover waits for them to refill; a 429/418 holds every request back
+// for its Retry-After.
+type rateLimiter struct {
+	mu  sync.Mutex
+	cfg RateLimitConfig
+
+	weightLimit  int
+	orderLimit   int
+	weightTokens float64
+	orderTokens  float64
+	refilledAt   time.Time
+	backoffUntil time.Time
+
+	usedWeight    int
+	orderCount10s int
+	orderCount1d  int
+	headersAt     time.Time
+	delayed       int64
+	retried       int64
+}
+
+func newRateLimiter(weightLimit, orderLimit int) *rateLimiter {
+	l := &rateLimiter{cfg: RateLimitConfig{MaxWait: defaultRateLimitMaxWait, Retries: defaultRateLimitRetries}}
+	l.setLimits(weightLimit, orderLimit)
+	return l
+}
+
+// UseRateLimits changes how long requests wait for the rate limits and how 429s are retried
+func (bc *BinanceClient) UseRateLimits(cfg RateLimitConfig) {
+	bc.limiter.mu.Lock()
+	defer bc.limiter.mu.Unlock()
+	bc.limiter.cfg = cfg
+}
+
+// RateLimitStatus returns the client's Binance usage for /status
+func (bc *BinanceClient) RateLimitStatus() RateLimitStatus {
+	return bc.limiter.status()
+}
+
+// setLimits resets the buckets full for new limits
+func (l *rateLimiter) setLimits(weightLimit, orderLimit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.weightLimit, l.orderLimit = weightLimit, orderLimit
+	l.weightTokens, l.orderTokens = l.capacity(weightLimit), l.capacity(orderLimit)
+	l.refilledAt = time.Now()
+}
+
+func (l *rateLimiter) capacity(limit int) float64 {
+	return float64(limit) * (1 - rateLimitHeadroom)
+}
+
+// refill tops the buckets up for the time since the last refill (mu held)
+func (l *rateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.refilledAt).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	l.refilledAt = now
+	l.weightTokens = minFloat(l.capacity(l.weightLimit), l.weightTokens+elapsed*l.capacity(l.weightLimit)/60)
+	l.orderTokens = minFloat(l.capacity(l.orderLimit), l.orderTokens+elapsed*l.capacity(l.orderLimit)/10)
+}
+
+// reserve takes a request's weight and orders from the buckets, returning how long the request
+// must wait for them. Requests that would wait longer than MaxWait take nothing and fail.
+func (l *rateLimiter) reserve(weight, orders int) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.refill(now)
+
+	var wait time.Duration
+	if l.backoffUntil.After(now) {
+		wait = l.backoffUntil.Sub(now)
+	}
+	if short := float64(weight) - l.weightTokens; short > 0 {
+		wait = maxDuration(wait, secondsDuration(short*60/l.capacity(l.weightLimit)))
+	}
+	if short := float64(orders) - l.orderTokens; orders > 0 && short > 0 {
+		wait = maxDuration(wait, secondsDuration(short*10/l.capacity(l.orderLimit)))
+	}
+
+	if wait > l.cfg.MaxWait {
+		return 0, &OrderError{
+			Code:    ErrRateLimited,
+			Message: fmt.Sprintf("Binance rate limit reached (%d/%d weight used) - request would wait %s", l.usedWeight, l.weightLimit, wait.Round(time.Second)),
+			Details: map[string]string{"retry_after_sec": strconv.Itoa(int(wait.Seconds()) + 1)},
+		}
+	}
+
+	// Later requests queue behind this one's reservation
+	l.weightTokens -= float64(weight)
+	l.orderTokens -= float64(orders)
+	if wait > 0 {
+		l.delayed++
+	}
+	return wait, nil
+}
+
+// observe reads Binance's usage headers, and the Retry-After of a 429 or 418
+func (l *rateLimiter) observe(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.refill(now)
+	if used, ok := headerInt(resp, "X-MBX-USED-WEIGHT-1M"); ok {
+		l.usedWeight = used
+		l.weightTokens = minFloat(l.weightTokens, l.capacity(l.weightLimit)-float64(used))
+		l.headersAt = now
+	}
+	if count, ok := headerInt(resp, "X-MBX-ORDER-COUNT-10S"); ok {
+		l.orderCount10s = count
+		l.orderTokens = minFloat(l.orderTokens, l.capacity(l.orderLimit)-float64(count))
+		l.headersAt = now
+	}
+	if count, ok := headerInt(resp, "X-MBX-ORDER-COUNT-1D"); ok {
+		l.orderCount1d = count
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+		if until := now.Add(retryAfter(resp)); until.After(l.backoffUntil) {
+			l.backoffUntil = until
+			slog.Warn("Binance rate limit hit, holding requests back", "status", resp.StatusCode,
+				"used_weight", l.usedWeight, "weight_limit", l.weightLimit, "until", until.Format(time.RFC3339))
+		}
+	}
+}
+
+// retryWait returns how long to wait before retrying a 429, false if it shouldn't be retried:
+// out of retries, or the wait is longer than MaxWait. 418 (IP banned) is never retried.
+func (l *rateLimiter) retryWait(resp *http.Response, attempt int) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if resp.StatusCode != http.StatusTooManyRequests || attempt >= l.cfg.Retries {
+		return 0, false
+	}
+	wait := retryAfter(resp)
+	if wait > l.cfg.MaxWait {
+		return 0, false
+	}
+	l.retried++
+	return wait, true
+}
+
+func (l *rateLimiter) status() RateLimitStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	status := RateLimitStatus{
+		UsedWeight1m:  l.usedWeight,
+		WeightLimit1m: l.weightLimit,
+		OrderCount10s: l.orderCount10s,
+		OrderCount1d:  l.orderCount1d,
+		OrderLimit10s: l.orderLimit,
+		Delayed:       l.delayed,
+		Retried:       l.retried,
+	}
+	if l.backoffUntil.After(time.Now()) {
+		status.BackoffUntil = l.backoffUntil.Format(time.RFC3339)
+	}
+	if !l.headersAt.IsZero() {
+		status.UpdatedAt = l.headersAt.Format(time.RFC3339)
+	}
+	return status
+}
+
+// requestCost estimates the weight of a request and the orders it places; the usage headers
+// correct the estimate once the response arrives
+func requestCost(req *http.Request) (weight, orders int) {
+	path := req.URL.Path
+	switch {
+	case req.Method == "POST" && (path == "/api/v3/order" || path == "/fapi/v1/order" || path == "/sapi/v1/margin/order"):
+		return 1, 1
+	case req.Method == "POST" && path == "/api/v3/orderList/oco":
+		return 1, 2
+	case req.Method == "GET" && path == "/api/v3/order":
+		return 4, 0
+	case path == "/api/v3/openOrders":
+		if req.URL.Query().Get("symbol") == "" {
+			return 80, 0
+		}
+		return 6, 0
+	case path == "/api/v3/allOrders", path == "/api/v3/myTrades", path == "/api/v3/account", path == "/api/v3/exchangeInfo":
+		return 20, 0
+	case path == "/api/v3/ticker/bookTicker":
+		return 2, 0
+	default:
+		return 1, 0
+	}
+}
+
+// retryRequest rebuilds a request for another attempt. A signed request gets a fresh timestamp
+// and signature, so it is still inside recvWindow after the wait.
+func (bc *BinanceClient) retryRequest(req *http.Request) (*http.Request, error) {
+	retry := req.Clone(req.Context())
+	apiKey := req.Header.Get("X-MBX-APIKEY")
+
+	if query := retry.URL.Query(); query.Get("signature") != "" {
+		if err := bc.resign(query, apiKey); err != nil {
+			return nil, err
+		}
+		retry.URL.RawQuery = query.Encode()
+	}
+
+	if req.GetBody == nil {
+		return retry, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if form, err := url.ParseQuery(string(payload)); err == nil && form.Get("signature") != "" {
+		if err := bc.resign(form, apiKey); err != nil {
+			return nil, err
+		}
+		payload = []byte(form.Encode())
+	}
+	retry.Body = io.NopCloser(bytes.NewReader(payload))
+	retry.ContentLength = int64(len(payload))
+	retry.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(payload)), nil
+	}
+	return retry, nil
+}
+
+// resign signs params again with the secret of apiKey and the current time
+func (bc *BinanceClient) resign(params url.Values, apiKey string) error {
+	secret, ok := bc.keys.secretOf(apiKey)
+	if !ok {
+		return fmt.Errorf("cannot re-sign request: API key no longer configured")
+	}
+	params.Del("signature")
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	params.Set("signature", signPayload(secret, params.Encode()))
+	return nil
+}
+
+// retryAfter reads Retry-After in seconds, 1s when it is missing or unreadable
+func retryAfter(resp *http.Response) time.Duration {
+	if sec, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After"))); err == nil && sec > 0 {
+		return time.Duration(sec) * time.Second
+	}
+	return time.Second
+}
+
+func headerInt(resp *http.Response, name string) (int, bool) {
+	v := resp.Header.Get(name)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	return n, err == nil
+}
+
+func secondsDuration(sec float64) time.Duration {
+	return time.Duration(sec * float64(time.Second))
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,143 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SubscribeFlexibleEarn moves spot funds into the asset's Simple Earn flexible product
+// and returns the purchase ID
+func (bc *BinanceClient) SubscribeFlexibleEarn(asset string, amount decimal.Decimal) (string, error) {
+	params := url.Values{}
+	params.Set("asset", asset)
+	body, err := bc.signedRequest("GET", "/sapi/v1/simple-earn/flexible/list", params)
+	if err != nil {
+		return "", err
+	}
+
+	var products struct {
+		Rows []struct {
+			Asset       string `json:"asset"`
+			ProductID   string `json:"productId"`
+			CanPurchase bool   `json:"canPurchase"`
+		} `json:"rows"`
+	}
+	if err := json.Unmarshal(body, &products); err != nil {
+		return "", fmt.Errorf("failed to decode earn products: %w", err)
+	}
+
+	productID := ""
+	for _, p := range products.Rows {
+		if p.Asset == asset && p.CanPurchase {
+			productID = p.ProductID
+			break
+		}
+	}
+	if productID == "" {
+		return "", fmt.Errorf("no purchasable flexible earn product for %s", asset)
+	}
+
+	params = url.Values{}
+	params.Set("productId", productID)
+	params.Set("amount", amount.String())
+	body, err = bc.signedRequest("POST", "/sapi/v1/simple-earn/flexible/subscribe", params)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		PurchaseID int64 `json:"purchaseId"`
+		Success    bool  `json:"success"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to decode earn subscription: %w", err)
+	}
+	if !result.Success {
+		return "", fmt.Errorf("earn subscription to %s was not successful", productID)
+	}
+
+	bc.invalidateBalances()
+	return strconv.FormatInt(result.PurchaseID, 10), nil
+}
+
+// Withdraw sends funds to an external address (which must be whitelisted on the API key)
+// and returns Binance's withdrawal ID
+func (bc *BinanceClient) Withdraw(asset, address, network string, amount decimal.Decimal, withdrawOrderID string) (string, error) {
+	params := url.Values{}
+	params.Set("coin", asset)
+	params.Set("address", address)
+	if network != "" {
+		params.Set("network", network)
+	}
+	params.Set("amount", amount.String())
+	params.Set("withdrawOrderId", withdrawOrderID)
+
+	body, err := bc.signedRequest("POST", "/sapi/v1/capital/withdraw/apply", params)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to decode withdrawal: %w", err)
+	}
+
+	bc.invalidateBalances()
+	return result.ID, nil
+}
+
+// signedRequest sends a signed request to a REST path on the host serving it and returns the response body
+func (bc *BinanceClient) signedRequest(method, path string, params url.Values) ([]byte, error) {
+	// Check if we have credentials
+	if !bc.hasCredentials() {
+		return nil, fmt.Errorf("Binance API credentials not configured - cannot call %s", path)
+	}
+
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	params.Set("recvWindow", "5000")
+
+	apiKey := bc.signParams(params)
+
+	var req *http.Request
+	var err error
+	if method == "GET" {
+		req, err = http.NewRequest(method, bc.hostFor(path)+path+"?"+params.Encode(), nil)
+	} else {
+		req, err = http.NewRequest(method, bc.hostFor(path)+path, strings.NewReader(params.Encode()))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-MBX-APIKEY", apiKey)
+
+	resp, err := bc.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseBinanceError(resp.StatusCode, body)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,186 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+const (
+	BinanceStreamURL = "wss://stream.binance.com:9443/ws"
+
+	listenKeyKeepAlive       = 30 * time.Minute // Binance expires listen keys after 60 minutes
+	userStreamReconnectDelay = 5 * time.Second
+)
+
+// UserDataStream follows an account's user-data stream and hands every
+// executionReport to onReport. Events sent while disconnected are lost.
+type UserDataStream struct {
+	binance  *BinanceClient
+	url      string
+	onReport func(models.ExecutionReport)
+}
+
+func NewUserDataStream(binance *BinanceClient, streamURL string, onReport func(models.ExecutionReport)) *UserDataStream {
+	return &UserDataStream{
+		binance:  binance,
+		url:      streamURL,
+		onReport: onReport,
+	}
+}
+
+// Run streams until ctx is cancelled, creating a fresh listen key after every failure
+func (s *UserDataStream) Run(ctx context.Context) {
+	if !s.binance.hasCredentials() {
+		slog.Warn("Binance API credentials not configured - user-data stream disabled")
+		return
+	}
+
+	for {
+		err := s.session(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("User-data stream disconnected, reconnecting", "retry_after", userStreamReconnectDelay, logging.Err(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(userStreamReconnectDelay):
+		}
+	}
+}
+
+func (s *UserDataStream) session(ctx context.Context) error {
+	listenKey, err := s.binance.CreateListenKey()
+	if err != nil {
+		return err
+	}
+
+	dialer := websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+	conn, _, err := dialer.DialContext(ctx, s.url+"/"+listenKey, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	slog.Info("Connected to Binance user-data stream")
+
+	// Close the connection on shutdown and keep the listen key alive meanwhile
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(listenKeyKeepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				conn.Close()
+				return
+			case <-ticker.C:
+				if err := s.binance.KeepAliveListenKey(listenKey); err != nil {
+					slog.Error("Failed to keep user-data stream alive", logging.Err(err))
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var event struct {
+			Type string `json:"e"`
+			Time int64  `json:"E"` // Declared so "E" doesn't case-fold onto "e"
+		}
+		if err := json.Unmarshal(message, &event); err != nil {
+			slog.Warn("Ignoring malformed user-data event", logging.Err(err))
+			continue
+		}
+
+		switch event.Type {
+		case "executionReport":
+			var report models.ExecutionReport
+			if err := json.Unmarshal(message, &report); err != nil {
+				slog.Error("Failed to decode executionReport", "message", string(message), logging.Err(err))
+				continue
+			}
+			s.onReport(report)
+		case "listenKeyExpired":
+			return fmt.Errorf("listen key expired")
+		}
+	}
+}
+
+// CreateListenKey opens a user-data stream for the active API key
+func (bc *BinanceClient) CreateListenKey() (string, error) {
+	body, err := bc.userDataStreamRequest("POST", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		ListenKey string `json:"listenKey"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to decode listen key: %w", err)
+	}
+	return resp.ListenKey, nil
+}
+
+// KeepAliveListenKey extends a listen key's validity by 60 minutes
+func (bc *BinanceClient) KeepAliveListenKey(listenKey string) error {
+	params := url.Values{}
+	params.Set("listenKey", listenKey)
+	_, err := bc.userDataStreamRequest("PUT", params)
+	return err
+}
+
+// userDataStreamRequest calls /api/v3/userDataStream, which takes the API key but no signature
+func (bc *BinanceClient) userDataStreamRequest(method string, params url.Values) ([]byte, error) {
+	reqURL := bc.baseURL + "/api/v3/userDataStream"
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequest(method, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-MBX-APIKEY", bc.keys.current().APIKey)
+
+	resp, err := bc.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp map[string]interface{}
+		json.Unmarshal(body, &errResp)
+		return nil, fmt.Errorf("binance error %d: %v", resp.StatusCode, errResp)
+	}
+
+	return body, nil
+}
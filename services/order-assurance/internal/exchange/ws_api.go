@@ -0,0 +1,246 @@
+package exchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+const (
+	BinanceWSAPIURL = "wss://ws-api.binance.com:443/ws-api/v3"
+
+	wsRequestTimeout = 5 * time.Second
+)
+
+// errWSUnavailable means the request never reached Binance, so REST can safely retry it
+var errWSUnavailable = errors.New("websocket API unavailable")
+
+type wsRequest struct {
+	ID     string                 `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params"`
+}
+
+type wsResponse struct {
+	ID     string          `json:"id"`
+	Status int             `json:"status"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// WSAPIClient sends signed requests over Binance's WebSocket API.
+// The connection is opened lazily and re-dialled after any failure.
+type WSAPIClient struct {
+	url string
+
+	mu      sync.Mutex // Guards conn and pending
+	writeMu sync.Mutex
+	conn    *websocket.Conn
+	pending map[string]chan wsResponse
+	nextID  atomic.Int64
+}
+
+func NewWSAPIClient(wsURL string) *WSAPIClient {
+	return &WSAPIClient{
+		url:     wsURL,
+		pending: make(map[string]chan wsResponse),
+	}
+}
+
+// PlaceOrder submits a LIMIT order via order.place, also returning the raw reply (nil if none arrived)
+func (ws *WSAPIClient) PlaceOrder(params url.Values, key APIKeyPair) (*models.BinanceOrder, *wsResponse, error) {
+	resp, err := ws.request("order.place", params, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	order, err := decodeOrderResponse("order.place", resp)
+	return order, resp, err
+}
+
+// CancelOrder cancels an order via order.cancel
+func (ws *WSAPIClient) CancelOrder(params url.Values, key APIKeyPair) (*models.BinanceOrder, error) {
+	return ws.orderRequest("order.cancel", params, key)
+}
+
+// BookTicker reads the best bid and ask via ticker.book, a public request sent unsigned
+func (ws *WSAPIClient) BookTicker(symbol string) (*wsResponse, error) {
+	return ws.send("ticker.book", map[string]interface{}{"symbol": symbol})
+}
+
+// Close drops the connection and fails any in-flight requests
+func (ws *WSAPIClient) Close() {
+	ws.mu.Lock()
+	conn := ws.conn
+	ws.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+func (ws *WSAPIClient) orderRequest(method string, params url.Values, key APIKeyPair) (*models.BinanceOrder, error) {
+	resp, err := ws.request(method, params, key)
+	if err != nil {
+		return nil, err
+	}
+	return decodeOrderResponse(method, resp)
+}
+
+// decodeOrderResponse returns the order of a successful reply, or the Binance error of a failed one
+func decodeOrderResponse(method string, resp *wsResponse) (*models.BinanceOrder, error) {
+	if resp.Status != 200 {
+		return nil, parseBinanceError(resp.Status, resp.Error)
+	}
+
+	var order models.BinanceOrder
+	if err := json.Unmarshal(resp.Result, &order); err != nil {
+		return nil, fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return &order, nil
+}
+
+// request signs params and waits for the matching response
+func (ws *WSAPIClient) request(method string, params url.Values, key APIKeyPair) (*wsResponse, error) {
+	return ws.send(method, signedWSParams(params, key))
+}
+
+// send writes a request and waits for the matching response
+func (ws *WSAPIClient) send(method string, params map[string]interface{}) (*wsResponse, error) {
+	conn, err := ws.connection()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errWSUnavailable, err)
+	}
+
+	id := strconv.FormatInt(ws.nextID.Add(1), 10)
+	respCh := make(chan wsResponse, 1)
+
+	ws.mu.Lock()
+	ws.pending[id] = respCh
+	ws.mu.Unlock()
+
+	defer func() {
+		ws.mu.Lock()
+		delete(ws.pending, id)
+		ws.mu.Unlock()
+	}()
+
+	req := wsRequest{ID: id, Method: method, Params: params}
+
+	ws.writeMu.Lock()
+	conn.SetWriteDeadline(time.Now().Add(wsRequestTimeout))
+	err = conn.WriteJSON(req)
+	ws.writeMu.Unlock()
+	if err != nil {
+		ws.drop(conn, err)
+		return nil, fmt.Errorf("%w: %v", errWSUnavailable, err)
+	}
+
+	select {
+	case resp, ok := <-respCh:
+		if !ok {
+			// Connection died after sending - outcome unknown, must not resend
+			return nil, &OrderError{Code: ErrExchangeFailure, Message: fmt.Sprintf("websocket closed awaiting %s response", method)}
+		}
+		return &resp, nil
+	case <-time.After(wsRequestTimeout):
+		return nil, &OrderError{Code: ErrExchangeFailure, Message: fmt.Sprintf("timed out awaiting %s response", method)}
+	}
+}
+
+// signedParams adds apiKey and signature; WebSocket API signs the alphabetically sorted params
+func signedWSParams(params url.Values, key APIKeyPair) map[string]interface{} {
+	values := url.Values{}
+	for key, vals := range params {
+		values[key] = vals
+	}
+	values.Set("apiKey", key.APIKey)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	signed := make(map[string]interface{}, len(keys)+1)
+	for _, key := range keys {
+		parts = append(parts, key+"="+values.Get(key))
+		signed[key] = values.Get(key)
+	}
+	signed["signature"] = signPayload(key.APISecret, strings.Join(parts, "&"))
+
+	return signed
+}
+
+// connection returns the live connection, dialling a new one if needed
+func (ws *WSAPIClient) connection() (*websocket.Conn, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.conn != nil {
+		return ws.conn, nil
+	}
+
+	dialer := websocket.Dialer{HandshakeTimeout: wsRequestTimeout}
+	conn, _, err := dialer.Dial(ws.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Connected to Binance WebSocket API", "url", ws.url)
+	ws.conn = conn
+	go ws.readLoop(conn)
+	return conn, nil
+}
+
+// readLoop routes responses to waiting requests until the connection fails
+func (ws *WSAPIClient) readLoop(conn *websocket.Conn) {
+	for {
+		var resp wsResponse
+		if err := conn.ReadJSON(&resp); err != nil {
+			ws.drop(conn, err)
+			return
+		}
+
+		ws.mu.Lock()
+		respCh, ok := ws.pending[resp.ID]
+		if ok {
+			delete(ws.pending, resp.ID)
+		}
+		ws.mu.Unlock()
+
+		if ok {
+			respCh <- resp
+		}
+	}
+}
+
+// drop discards a failed connection and fails every request waiting on it
+func (ws *WSAPIClient) drop(conn *websocket.Conn, err error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.conn != conn {
+		return
+	}
+
+	slog.Warn("Binance WebSocket API connection lost", logging.Err(err))
+	conn.Close()
+	ws.conn = nil
+	for id, respCh := range ws.pending {
+		close(respCh)
+		delete(ws.pending, id)
+	}
+}
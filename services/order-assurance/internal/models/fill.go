@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BinanceTrade is a single execution from /api/v3/myTrades
+type BinanceTrade struct {
+	Symbol          string `json:"symbol"`
+	ID              int64  `json:"id"`
+	OrderID         int64  `json:"orderId"`
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	QuoteQty        string `json:"quoteQty"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            int64  `json:"time"`
+	IsBuyer         bool   `json:"isBuyer"`
+	IsMaker         bool   `json:"isMaker"`
+}
+
+// OrderFill is a stored trade with its commission
+type OrderFill struct {
+	ID              int             `json:"id"`
+	Account         string          `json:"account,omitempty"`
+	Symbol          string          `json:"symbol"`
+	OrderID         string          `json:"order_id"`
+	TradeID         int64           `json:"trade_id"`
+	Side            string          `json:"side"`
+	Price           decimal.Decimal `json:"price"`
+	Quantity        decimal.Decimal `json:"quantity"`
+	QuoteQuantity   decimal.Decimal `json:"quote_quantity"`
+	Commission      decimal.Decimal `json:"commission"`
+	CommissionAsset string          `json:"commission_asset"`
+	IsMaker         bool            `json:"is_maker"`
+	TradedAt        time.Time       `json:"traded_at"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExecutionReport is an order update from the Binance user-data stream.
+// encoding/json matches keys case-insensitively, so the upper-case keys that
+// collide with used lower-case ones (C, I, M, O) are declared to absorb them.
+type ExecutionReport struct {
+	EventType       string `json:"e"`
+	EventTime       int64  `json:"E"`
+	Symbol          string `json:"s"`
+	ClientOrderID   string `json:"c"`
+	Side            string `json:"S"`
+	OrderType       string `json:"o"`
+	ExecutionType   string `json:"x"` // TRADE for executions
+	OrderStatus     string `json:"X"`
+	OrderID         int64  `json:"i"`
+	LastQty         string `json:"l"`
+	LastPrice       string `json:"L"`
+	LastQuoteQty    string `json:"Y"`
+	Commission      string `json:"n"`
+	CommissionAsset string `json:"N"` // null when no commission was charged
+	TradeTime       int64  `json:"T"`
+	TradeID         int64  `json:"t"`
+	CumulativeQty   string `json:"z"` // Filled so far - the whole order once OrderStatus is FILLED
+	CumulativeQuote string `json:"Z"`
+	IsMaker         bool   `json:"m"`
+
+	OrigClientOrderID string `json:"C"`
+	OrderCreatedAt    int64  `json:"O"`
+	IgnoreI           int64  `json:"I"`
+	IgnoreM           bool   `json:"M"`
+}
+
+// JournalEntry is one execution in the append-only trade journal
+type JournalEntry struct {
+	ID              int             `json:"id"`
+	Account         string          `json:"account,omitempty"`
+	Symbol          string          `json:"symbol"`
+	OrderID         string          `json:"order_id"`
+	ClientOrderID   string          `json:"client_order_id"`
+	TradeID         int64           `json:"trade_id"`
+	Side            string          `json:"side"`
+	Price           decimal.Decimal `json:"price"`
+	Quantity        decimal.Decimal `json:"quantity"`
+	QuoteQuantity   decimal.Decimal `json:"quote_quantity"`
+	Commission      decimal.Decimal `json:"commission"`
+	CommissionAsset string          `json:"commission_asset"`
+	IsMaker         bool            `json:"is_maker"`
+	OrderStatus     string          `json:"order_status"`
+	TradedAt        time.Time       `json:"traded_at"`
+	RecordedAt      time.Time       `json:"recorded_at"`
+}
+
+// JournalFilter selects journal entries; zero values match everything
+type JournalFilter struct {
+	Symbol  string
+	Account string
+	From    time.Time // Traded at or after
+	To      time.Time // Traded before
+}
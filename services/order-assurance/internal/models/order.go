@@ -1,36 +1,62 @@
 package models
 
 import (
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
 	"github.com/shopspring/decimal"
 )
 
-type OrderSide string
+// Payloads shared with grid-trading
+type (
+	OrderSide                = contracts.OrderSide
+	OrderType                = contracts.OrderType
+	OrderRequest             = contracts.OrderRequest
+	OrderResponse            = contracts.OrderResponse
+	OrderStatus              = contracts.OrderStatus
+	OrderStatusQuery         = contracts.OrderStatusQuery
+	BatchOrderStatusRequest  = contracts.BatchOrderStatusRequest
+	BatchOrderStatus         = contracts.BatchOrderStatus
+	BatchOrderStatusResponse = contracts.BatchOrderStatusResponse
+	FillNotification         = contracts.FillNotification
+	ErrorNotification        = contracts.ErrorNotification
+)
 
 const (
-	SideBuy  OrderSide = "buy"
-	SideSell OrderSide = "sell"
-)
+	SideBuy  = contracts.SideBuy
+	SideSell = contracts.SideSell
 
-// OrderRequest from grid-trading service
-type OrderRequest struct {
-	Symbol string          `json:"symbol"`
-	Price  decimal.Decimal `json:"price"`
-	Side   OrderSide       `json:"side"`
-	Amount decimal.Decimal `json:"amount"` // USDT for buy, coin amount for sell
-}
+	OrderTypeLimit  = contracts.OrderTypeLimit
+	OrderTypeMarket = contracts.OrderTypeMarket
+
+	StatusNotFound = contracts.StatusNotFound
+	StatusError    = contracts.StatusError
+)
 
-// OrderResponse to grid-trading service
-type OrderResponse struct {
-	OrderID string `json:"order_id"`
-	Status  string `json:"status"` // "assured" means order placed on exchange
+// PlacedOrder is an order recorded in the local order store when it was placed
+type PlacedOrder struct {
+	ID        int             `json:"id"`
+	Account   string          `json:"account,omitempty"`
+	Symbol    string          `json:"symbol"`
+	OrderID   string          `json:"order_id"`
+	Side      OrderSide       `json:"side"`
+	Price     decimal.Decimal `json:"price"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	Status    string          `json:"status"` // open, filled, cancelled - as last seen on the exchange
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt *time.Time      `json:"updated_at,omitempty"` // When the status last changed
+	ExpiresAt *time.Time      `json:"expires_at,omitempty"` // When the TTL worker cancels it, if still open
 }
 
-// OrderStatus response
-type OrderStatus struct {
-	OrderID      string           `json:"order_id"`
-	Status       string           `json:"status"` // open, filled, cancelled
-	FilledAmount *decimal.Decimal `json:"filled_amount,omitempty"`
-	FillPrice    *decimal.Decimal `json:"fill_price,omitempty"`
+// OrderFilter selects stored orders; zero values match everything
+type OrderFilter struct {
+	Symbol  string
+	Account string
+	Status  string
+	From    time.Time // Placed at or after
+	To      time.Time // Placed before
+	Limit   int
+	Offset  int
 }
 
 // Binance order structure
@@ -42,6 +68,7 @@ type BinanceOrder struct {
 	OrigQty             string `json:"origQty"`
 	ExecutedQty         string `json:"executedQty"`
 	CummulativeQuoteQty string `json:"cummulativeQuoteQty"`
+	CumQuote            string `json:"cumQuote"` // Futures name for cummulativeQuoteQty
 	Status              string `json:"status"`
 	Type                string `json:"type"`
 	Side                string `json:"side"`
@@ -50,23 +77,4 @@ type BinanceOrder struct {
 	Time                int64  `json:"time"`
 	UpdateTime          int64  `json:"updateTime"`
 	IsWorking           bool   `json:"isWorking"`
-}
-
-// FillNotification to send to grid-trading service
-type FillNotification struct {
-	OrderID      string          `json:"order_id"`
-	Symbol       string          `json:"symbol"`
-	Price        decimal.Decimal `json:"price"`
-	Side         string          `json:"side"`
-	Status       string          `json:"status"`
-	FilledAmount decimal.Decimal `json:"filled_amount"`
-	FillPrice    decimal.Decimal `json:"fill_price"`
-}
-
-// ErrorNotification to send to grid-trading service
-type ErrorNotification struct {
-	OrderID string `json:"order_id"`
-	Symbol  string `json:"symbol"`
-	Side    string `json:"side"`
-	Error   string `json:"error"`
 }
\ No newline at end of file
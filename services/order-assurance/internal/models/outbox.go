@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "PENDING"
+	OutboxDelivered OutboxStatus = "DELIVERED"
+	OutboxDead      OutboxStatus = "DEAD"
+)
+
+const (
+	NotificationKindFill           = "fill"
+	NotificationKindError          = "error"
+	NotificationKindExchangeStatus = "exchange_status" // Re-sent on the next check, never outboxed
+)
+
+// LoggedNotification is a fill or error notification numbered for replay
+type LoggedNotification struct {
+	Seq       int64
+	Kind      string
+	OrderID   string
+	Payload   string
+	CreatedAt time.Time
+}
+
+// OutboxEntry is a grid-trading notification that exhausted in-memory retries
+type OutboxEntry struct {
+	ID            int          `json:"id"`
+	Kind          string       `json:"kind"`
+	OrderID       string       `json:"order_id"`
+	Payload       string       `json:"payload"`
+	Status        OutboxStatus `json:"status"`
+	Attempts      int          `json:"attempts"`
+	LastError     string       `json:"last_error,omitempty"`
+	NextAttemptAt time.Time    `json:"next_attempt_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/shopspring/decimal"
+)
+
+// PendingPlacement is an order submitted to Binance whose outcome was not yet recorded
+type PendingPlacement struct {
+	ID            int             `json:"id"`
+	Account       string          `json:"account,omitempty"`
+	Symbol        string          `json:"symbol"`
+	ClientOrderID string          `json:"client_order_id"`
+	Side          OrderSide       `json:"side"`
+	Price         decimal.Decimal `json:"price"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// StatusPlaced marks a corrective notification for an order grid-trading never got a response for
+const StatusPlaced = contracts.StatusPlaced
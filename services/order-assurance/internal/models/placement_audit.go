@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// PlacementAudit is one order submission to the exchange as it was sent and answered
+type PlacementAudit struct {
+	ID            int               `json:"id"`
+	Account       string            `json:"account,omitempty"`
+	Symbol        string            `json:"symbol"`
+	Side          string            `json:"side"`
+	ClientOrderID string            `json:"client_order_id"`
+	OrderID       string            `json:"order_id,omitempty"`
+	Transport     string            `json:"transport"`
+	Request       map[string]string `json:"request"`     // Parameters sent, without signature and API key
+	StatusCode    int               `json:"status_code"` // 0 = no reply arrived
+	Response      string            `json:"response"`    // Raw exchange reply
+	Error         string            `json:"error,omitempty"`
+	DurationMs    int64             `json:"duration_ms"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// PlacementAuditFilter selects audited submissions; zero values match everything
+type PlacementAuditFilter struct {
+	Symbol        string
+	Account       string
+	ClientOrderID string
+	OrderID       string
+	From          time.Time
+	To            time.Time
+	Limit         int
+}
@@ -0,0 +1,15 @@
+package models
+
+import "github.com/grid-trading-bot/pkg/contracts"
+
+// Profit sweep destinations
+const (
+	SweepToEarn       = contracts.SweepToEarn
+	SweepToWithdrawal = contracts.SweepToWithdrawal
+)
+
+// Payloads shared with grid-trading
+type (
+	SweepRequest = contracts.SweepRequest
+	SweepResult  = contracts.SweepResult
+)
@@ -0,0 +1,208 @@
+package paper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+// apiError is a Binance-style error ({"code": -2010, "msg": "..."})
+type apiError struct {
+	HTTPStatus int    `json:"-"`
+	Code       int    `json:"code"`
+	Msg        string `json:"msg"`
+}
+
+var (
+	errInvalidSymbol = &apiError{HTTPStatus: http.StatusBadRequest, Code: -1121, Msg: "Invalid symbol."}
+	errNoSuchOrder   = &apiError{HTTPStatus: http.StatusBadRequest, Code: -2013, Msg: "Order does not exist."}
+	errUnknownOrder  = &apiError{HTTPStatus: http.StatusBadRequest, Code: -2011, Msg: "Unknown order sent."}
+	errInsufficient  = &apiError{HTTPStatus: http.StatusBadRequest, Code: -2010, Msg: "Account has insufficient balance for requested action."}
+	errDuplicateID   = &apiError{HTTPStatus: http.StatusBadRequest, Code: -2010, Msg: "Duplicate order sent."}
+	errNoUserStream  = &apiError{HTTPStatus: http.StatusBadRequest, Code: -1100, Msg: "Paper trading has no user-data stream."}
+)
+
+func badParam(msg string) *apiError {
+	return &apiError{HTTPStatus: http.StatusBadRequest, Code: -1102, Msg: msg}
+}
+
+func internalError(err error) *apiError {
+	log.Printf("ERROR: [PAPER] %v", err)
+	return &apiError{HTTPStatus: http.StatusInternalServerError, Code: -1001, Msg: "Paper trading failed: " + err.Error()}
+}
+
+// Account answers one account's spot order and account requests in place of Binance
+type Account struct {
+	exchange *Exchange
+	name     string
+}
+
+// Response returns the simulated reply to req, or nil for requests paper trading leaves
+// to Binance (market data, trading rules, system status)
+func (a *Account) Response(req *http.Request) *http.Response {
+	switch req.URL.Path {
+	case "/api/v3/order", "/api/v3/openOrders", "/api/v3/allOrders", "/api/v3/myTrades",
+		"/api/v3/account", "/api/v3/userDataStream":
+	default:
+		return nil
+	}
+
+	params := req.URL.Query()
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for key, values := range form {
+				params[key] = values
+			}
+		}
+	}
+
+	result, apiErr := a.handle(req.Method, req.URL.Path, params)
+	if apiErr != nil {
+		return response(req, apiErr.HTTPStatus, apiErr)
+	}
+	return response(req, http.StatusOK, result)
+}
+
+func (a *Account) handle(method, path string, params url.Values) (interface{}, *apiError) {
+	ex := a.exchange
+	symbol := strings.ToUpper(params.Get("symbol"))
+	orderID, _ := strconv.ParseInt(params.Get("orderId"), 10, 64)
+
+	switch {
+	case path == "/api/v3/order" && method == http.MethodPost:
+		o, apiErr := ex.place(a.name, params)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		return binanceOrder(o), nil
+
+	case path == "/api/v3/order" && method == http.MethodGet:
+		o, err := findOrder(ex.db, a.name, symbol, orderID, params.Get("origClientOrderId"))
+		if err != nil {
+			return nil, internalError(err)
+		}
+		if o == nil {
+			return nil, errNoSuchOrder
+		}
+		return binanceOrder(o), nil
+
+	case path == "/api/v3/order" && method == http.MethodDelete:
+		o, apiErr := ex.cancel(a.name, symbol, orderID, params.Get("origClientOrderId"))
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		return binanceOrder(o), nil
+
+	case path == "/api/v3/openOrders":
+		orders, err := openOrders(ex.db, a.name, symbol)
+		if err != nil {
+			return nil, internalError(err)
+		}
+		return binanceOrders(orders), nil
+
+	case path == "/api/v3/allOrders":
+		limit, err := strconv.Atoi(params.Get("limit"))
+		if err != nil || limit <= 0 {
+			limit = 500
+		}
+		orders, err := allOrders(ex.db, a.name, symbol, orderID, limit)
+		if err != nil {
+			return nil, internalError(err)
+		}
+		return binanceOrders(orders), nil
+
+	case path == "/api/v3/myTrades":
+		trades, err := orderTrades(ex.db, a.name, symbol, orderID)
+		if err != nil {
+			return nil, internalError(err)
+		}
+		result := make([]models.BinanceTrade, len(trades))
+		for i, t := range trades {
+			result[i] = models.BinanceTrade{
+				Symbol:          t.Symbol,
+				ID:              t.ID,
+				OrderID:         t.OrderID,
+				Price:           t.Price.String(),
+				Qty:             t.Qty.String(),
+				QuoteQty:        t.QuoteQty.String(),
+				Commission:      t.Commission.String(),
+				CommissionAsset: t.CommissionAsset,
+				Time:            t.Time,
+				IsBuyer:         t.IsBuyer,
+				IsMaker:         t.IsMaker,
+			}
+		}
+		return result, nil
+
+	case path == "/api/v3/account":
+		balances, err := accountBalances(ex.db, a.name)
+		if err != nil {
+			return nil, internalError(err)
+		}
+		type accountBalance struct {
+			Asset  string `json:"asset"`
+			Free   string `json:"free"`
+			Locked string `json:"locked"`
+		}
+		result := make([]accountBalance, len(balances))
+		for i, b := range balances {
+			result[i] = accountBalance{Asset: b.Asset, Free: b.Free.String(), Locked: b.Locked.String()}
+		}
+		return map[string]interface{}{"canTrade": true, "accountType": "SPOT", "balances": result}, nil
+
+	case path == "/api/v3/userDataStream":
+		return nil, errNoUserStream
+	}
+
+	return nil, badParam(fmt.Sprintf("Unsupported request %s %s.", method, path))
+}
+
+func binanceOrder(o *order) *models.BinanceOrder {
+	return &models.BinanceOrder{
+		Symbol:              o.Symbol,
+		OrderID:             o.OrderID,
+		ClientOrderID:       o.ClientOrderID,
+		Price:               o.Price.String(),
+		OrigQty:             o.OrigQty.String(),
+		ExecutedQty:         o.ExecutedQty.String(),
+		CummulativeQuoteQty: o.CumQuote.String(),
+		Status:              o.Status,
+		Type:                o.Type,
+		Side:                o.Side,
+		Time:                o.CreatedAt,
+		UpdateTime:          o.UpdatedAt,
+		IsWorking:           o.Status == "NEW",
+	}
+}
+
+func binanceOrders(orders []*order) []*models.BinanceOrder {
+	result := make([]*models.BinanceOrder, len(orders))
+	for i, o := range orders {
+		result[i] = binanceOrder(o)
+	}
+	return result
+}
+
+func response(req *http.Request, status int, payload interface{}) *http.Response {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		status = http.StatusInternalServerError
+		body = []byte(`{"code":-1001,"msg":"Paper trading failed to encode its reply."}`)
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(string(body))),
+		Request:    req,
+	}
+}
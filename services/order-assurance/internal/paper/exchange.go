@@ -0,0 +1,417 @@
+// Package paper simulates Binance spot order handling for paper trading (TRADING_MODE=paper).
+// Orders, executions and balances live in SQLite; limit orders fill when the live ticker
+// price crosses them. Market data - prices, trading rules, the order book - still comes
+// from Binance, so grids run against the real market without risking funds.
+package paper
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/shopspring/decimal"
+)
+
+// marketQtyPlaces is the precision of quantities bought with a quote amount
+const marketQtyPlaces = 8
+
+// Config sets up the simulation
+type Config struct {
+	MarketURL      string                     // Binance REST endpoint ticker prices are read from
+	Balances       map[string]decimal.Decimal // Starting balances of every account
+	CommissionRate decimal.Decimal            // Charged in the asset received, like Binance without BNB
+	FillInterval   time.Duration              // How often open orders are checked against the price
+
+	// Assets returns a symbol's base and quote asset (from the exchange's trading rules)
+	Assets func(symbol string) (base, quote string, err error)
+}
+
+// Exchange matches simulated orders of every account against live prices
+type Exchange struct {
+	db     *sql.DB
+	cfg    Config
+	client *http.Client
+
+	mu sync.Mutex // Serializes order and balance changes
+
+	ctx  context.Context
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// NewExchange creates the simulation; call Account for each account and Start to begin fills
+func NewExchange(db *sql.DB, cfg Config) *Exchange {
+	ctx, stop := context.WithCancel(context.Background())
+	return &Exchange{
+		db:     db,
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		ctx:    ctx,
+		stop:   stop,
+	}
+}
+
+// Account returns the simulated exchange of one account, giving it the starting
+// balances on first use
+func (ex *Exchange) Account(name string) *Account {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	seeded, err := seedBalances(ex.db, name, ex.cfg.Balances)
+	if err != nil {
+		slog.Error("Failed to seed paper balances", logging.KeyAccount, name, logging.Err(err))
+	} else if seeded {
+		slog.Info("[PAPER] Account starts with simulated balances", logging.KeyAccount, name, "balances", len(ex.cfg.Balances))
+	}
+	return &Account{exchange: ex, name: name}
+}
+
+// Start checks open orders against the price every FillInterval
+func (ex *Exchange) Start() {
+	log.Printf("Starting paper trading fills, checking open orders every %s", ex.cfg.FillInterval)
+	ex.wg.Add(1)
+	go ex.loop()
+}
+
+// Stop stops the fill loop
+func (ex *Exchange) Stop() {
+	ex.stop()
+	ex.wg.Wait()
+}
+
+func (ex *Exchange) loop() {
+	defer ex.wg.Done()
+
+	ticker := time.NewTicker(ex.cfg.FillInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ex.ctx.Done():
+			return
+		case <-ticker.C:
+			ex.matchAll()
+		}
+	}
+}
+
+// matchAll fills the open orders the current price of their symbol crosses
+func (ex *Exchange) matchAll() {
+	symbols, err := openSymbols(ex.db)
+	if err != nil {
+		slog.Error("Failed to list symbols with open paper orders", logging.Err(err))
+		return
+	}
+
+	for _, symbol := range symbols {
+		price, err := ex.price(symbol)
+		if err != nil {
+			slog.Warn("[PAPER] No price, the orders wait", logging.KeySymbol, symbol, logging.Err(err))
+			continue
+		}
+		if err := ex.match(symbol, price); err != nil {
+			slog.Error("[PAPER] Failed to match orders", logging.KeySymbol, symbol, logging.Err(err))
+		}
+	}
+}
+
+func (ex *Exchange) match(symbol string, price decimal.Decimal) error {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	tx, err := ex.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	orders, err := symbolOrders(tx, symbol)
+	if err != nil {
+		return err
+	}
+	for _, o := range orders {
+		if crosses(o, price) {
+			// Resting orders fill at their own price as maker
+			if err := ex.fill(tx, o, o.Price, true); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// place accepts a GTC LIMIT order, or a MARKET order by quantity or quoteOrderQty (buys),
+// filling it at once if it crosses the current price
+func (ex *Exchange) place(account string, params url.Values) (*order, *apiError) {
+	symbol := strings.ToUpper(params.Get("symbol"))
+	base, quote, err := ex.cfg.Assets(symbol)
+	if err != nil {
+		return nil, errInvalidSymbol
+	}
+
+	o := &order{
+		Account:       account,
+		Symbol:        symbol,
+		ClientOrderID: params.Get("newClientOrderId"),
+		Side:          params.Get("side"),
+		Type:          params.Get("type"),
+		Status:        "NEW",
+	}
+	if o.Side != "BUY" && o.Side != "SELL" {
+		return nil, badParam("Invalid side.")
+	}
+	if o.ClientOrderID == "" {
+		o.ClientOrderID = fmt.Sprintf("paper_%d", time.Now().UnixNano())
+	}
+
+	// The price is read before taking the lock - it is a network call
+	market, priceErr := ex.price(symbol)
+
+	switch o.Type {
+	case "LIMIT":
+		if o.Price, err = decimal.NewFromString(params.Get("price")); err != nil || !o.Price.IsPositive() {
+			return nil, badParam("Invalid price.")
+		}
+		if o.OrigQty, err = decimal.NewFromString(params.Get("quantity")); err != nil || !o.OrigQty.IsPositive() {
+			return nil, badParam("Invalid quantity.")
+		}
+	case "MARKET":
+		if priceErr != nil {
+			return nil, &apiError{HTTPStatus: http.StatusServiceUnavailable, Code: -1001, Msg: "Paper trading has no price for " + symbol + "."}
+		}
+		if v := params.Get("quoteOrderQty"); v != "" {
+			quoteQty, err := decimal.NewFromString(v)
+			if err != nil || !quoteQty.IsPositive() || o.Side != "BUY" {
+				return nil, badParam("Invalid quoteOrderQty.")
+			}
+			o.OrigQty = quoteQty.Div(market).RoundFloor(marketQtyPlaces)
+		} else if o.OrigQty, err = decimal.NewFromString(params.Get("quantity")); err != nil || !o.OrigQty.IsPositive() {
+			return nil, badParam("Invalid quantity.")
+		}
+	default:
+		return nil, badParam("Unsupported order type " + o.Type + ".")
+	}
+
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	tx, err := ex.db.Begin()
+	if err != nil {
+		return nil, internalError(err)
+	}
+	defer tx.Rollback()
+
+	if dup, err := findOrder(tx, account, symbol, 0, o.ClientOrderID); err != nil {
+		return nil, internalError(err)
+	} else if dup != nil && dup.Status == "NEW" {
+		return nil, errDuplicateID
+	}
+
+	// Lock what the order can spend; market orders spend it right away
+	lockAsset, lockAmount := quote, o.Price.Mul(o.OrigQty)
+	if o.Type == "MARKET" {
+		lockAmount = market.Mul(o.OrigQty)
+	}
+	if o.Side == "SELL" {
+		lockAsset, lockAmount = base, o.OrigQty
+	}
+	bal, err := getBalance(tx, account, lockAsset)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	if bal.Free.LessThan(lockAmount) {
+		return nil, errInsufficient
+	}
+	bal.Free = bal.Free.Sub(lockAmount)
+	bal.Locked = bal.Locked.Add(lockAmount)
+	if err := saveBalance(tx, account, bal); err != nil {
+		return nil, internalError(err)
+	}
+
+	now := time.Now().UnixMilli()
+	o.CreatedAt, o.UpdatedAt = now, now
+	if err := insertOrder(tx, o); err != nil {
+		return nil, internalError(err)
+	}
+	slog.Info("[PAPER] Order placed", logging.KeyAccount, o.Account, logging.KeySymbol, symbol, logging.KeyOrderID, o.OrderID,
+		"type", o.Type, "side", o.Side, "quantity", o.OrigQty, "price", o.Price)
+
+	// Marketable orders take liquidity at the current price
+	if o.Type == "MARKET" || (priceErr == nil && crosses(o, market)) {
+		if err := ex.fill(tx, o, market, false); err != nil {
+			return nil, internalError(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, internalError(err)
+	}
+	return o, nil
+}
+
+// cancel cancels an open order by ID or client order ID, releasing its locked balance
+func (ex *Exchange) cancel(account, symbol string, orderID int64, clientOrderID string) (*order, *apiError) {
+	base, quote, err := ex.cfg.Assets(symbol)
+	if err != nil {
+		return nil, errInvalidSymbol
+	}
+
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	tx, err := ex.db.Begin()
+	if err != nil {
+		return nil, internalError(err)
+	}
+	defer tx.Rollback()
+
+	o, err := findOrder(tx, account, symbol, orderID, clientOrderID)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	if o == nil || o.Status != "NEW" {
+		return nil, errUnknownOrder
+	}
+
+	lockAsset, lockAmount := quote, o.Price.Mul(o.OrigQty)
+	if o.Side == "SELL" {
+		lockAsset, lockAmount = base, o.OrigQty
+	}
+	bal, err := getBalance(tx, account, lockAsset)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	bal.Locked = bal.Locked.Sub(lockAmount)
+	bal.Free = bal.Free.Add(lockAmount)
+	if err := saveBalance(tx, account, bal); err != nil {
+		return nil, internalError(err)
+	}
+
+	o.Status = "CANCELED"
+	o.UpdatedAt = time.Now().UnixMilli()
+	if err := updateOrder(tx, o); err != nil {
+		return nil, internalError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, internalError(err)
+	}
+
+	slog.Info("[PAPER] Order cancelled", logging.KeyAccount, o.Account, logging.KeySymbol, o.Symbol, logging.KeyOrderID, o.OrderID)
+	return o, nil
+}
+
+// fill executes an order completely at price and settles balances. Caller holds ex.mu.
+func (ex *Exchange) fill(tx *sql.Tx, o *order, price decimal.Decimal, isMaker bool) error {
+	base, quote, err := ex.cfg.Assets(o.Symbol)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	cost := price.Mul(o.OrigQty)
+	t := &trade{
+		OrderID:  o.OrderID,
+		Symbol:   o.Symbol,
+		Price:    price,
+		Qty:      o.OrigQty,
+		QuoteQty: cost,
+		IsBuyer:  o.Side == "BUY",
+		IsMaker:  isMaker,
+		Time:     now,
+	}
+
+	// Commission is charged in the asset received
+	spent, received := quote, base
+	reserved, gain := o.Price.Mul(o.OrigQty), o.OrigQty
+	if o.Type == "MARKET" {
+		reserved = cost
+	}
+	if o.Side == "SELL" {
+		spent, received = base, quote
+		reserved, gain = o.OrigQty, cost
+	}
+	t.Commission = gain.Mul(ex.cfg.CommissionRate)
+	t.CommissionAsset = received
+
+	out, err := getBalance(tx, o.Account, spent)
+	if err != nil {
+		return err
+	}
+	out.Locked = out.Locked.Sub(reserved)
+	if o.Side == "BUY" {
+		out.Free = out.Free.Add(reserved.Sub(cost)) // Refund price improvement
+	}
+	if err := saveBalance(tx, o.Account, out); err != nil {
+		return err
+	}
+
+	in, err := getBalance(tx, o.Account, received)
+	if err != nil {
+		return err
+	}
+	in.Free = in.Free.Add(gain.Sub(t.Commission))
+	if err := saveBalance(tx, o.Account, in); err != nil {
+		return err
+	}
+
+	o.Status = "FILLED"
+	o.ExecutedQty = o.OrigQty
+	o.CumQuote = cost
+	o.UpdatedAt = now
+	if err := updateOrder(tx, o); err != nil {
+		return err
+	}
+	if err := insertTrade(tx, o.Account, t); err != nil {
+		return err
+	}
+
+	slog.Info("[PAPER] Order filled", logging.KeyAccount, o.Account, logging.KeySymbol, o.Symbol, logging.KeyOrderID, o.OrderID,
+		"side", o.Side, "quantity", o.OrigQty, "price", price)
+	return nil
+}
+
+// price reads a symbol's last price from the market data endpoint
+func (ex *Exchange) price(symbol string) (decimal.Decimal, error) {
+	resp, err := ex.client.Get(ex.cfg.MarketURL + "/api/v3/ticker/price?symbol=" + url.QueryEscape(symbol))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get %s price: %w", symbol, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return decimal.Zero, fmt.Errorf("ticker returned status %d: %s", resp.StatusCode, body)
+	}
+
+	var ticker struct {
+		Price decimal.Decimal `json:"price"`
+	}
+	if err := json.Unmarshal(body, &ticker); err != nil {
+		return decimal.Zero, fmt.Errorf("invalid ticker response: %w", err)
+	}
+	if !ticker.Price.IsPositive() {
+		return decimal.Zero, fmt.Errorf("no price for %s", symbol)
+	}
+	return ticker.Price, nil
+}
+
+// crosses reports whether price reaches an open limit order
+func crosses(o *order, price decimal.Decimal) bool {
+	if o.Side == "BUY" {
+		return price.LessThanOrEqual(o.Price)
+	}
+	return price.GreaterThanOrEqual(o.Price)
+}
@@ -0,0 +1,277 @@
+package paper
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// querier is what the store functions need from *sql.DB and *sql.Tx
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// order is a simulated order as stored in paper_orders
+type order struct {
+	OrderID       int64
+	Account       string
+	Symbol        string
+	ClientOrderID string
+	Side          string
+	Type          string
+	Price         decimal.Decimal
+	OrigQty       decimal.Decimal
+	ExecutedQty   decimal.Decimal
+	CumQuote      decimal.Decimal
+	Status        string
+	CreatedAt     int64
+	UpdatedAt     int64
+}
+
+const orderColumns = `order_id, account, symbol, client_order_id, side, type, price, orig_qty,
+	executed_qty, cummulative_quote_qty, status, created_at, updated_at`
+
+func insertOrder(q querier, o *order) error {
+	query := `
+		INSERT INTO paper_orders (account, symbol, client_order_id, side, type, price, orig_qty,
+			executed_qty, cummulative_quote_qty, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	result, err := q.Exec(query, o.Account, o.Symbol, o.ClientOrderID, o.Side, o.Type, o.Price.String(), o.OrigQty.String(),
+		o.ExecutedQty.String(), o.CumQuote.String(), o.Status, o.CreatedAt, o.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store paper order: %w", err)
+	}
+	o.OrderID, err = result.LastInsertId()
+	return err
+}
+
+func updateOrder(q querier, o *order) error {
+	query := `
+		UPDATE paper_orders
+		SET executed_qty = $1, cummulative_quote_qty = $2, status = $3, updated_at = $4
+		WHERE order_id = $5
+	`
+	if _, err := q.Exec(query, o.ExecutedQty.String(), o.CumQuote.String(), o.Status, o.UpdatedAt, o.OrderID); err != nil {
+		return fmt.Errorf("failed to update paper order %d: %w", o.OrderID, err)
+	}
+	return nil
+}
+
+// findOrder looks an account's order up by ID, or by client order ID when orderID is 0
+// (the newest such order). Returns nil if there is none.
+func findOrder(q querier, account, symbol string, orderID int64, clientOrderID string) (*order, error) {
+	where, arg := "order_id = $3", interface{}(orderID)
+	if orderID == 0 {
+		where, arg = "client_order_id = $3", clientOrderID
+	}
+	orders, err := queryOrders(q, `WHERE account = $1 AND symbol = $2 AND `+where+` ORDER BY order_id DESC LIMIT 1`, account, symbol, arg)
+	if err != nil || len(orders) == 0 {
+		return nil, err
+	}
+	return orders[0], nil
+}
+
+// openOrders returns an account's open orders, of every symbol if symbol is empty
+func openOrders(q querier, account, symbol string) ([]*order, error) {
+	if symbol == "" {
+		return queryOrders(q, `WHERE account = $1 AND status = 'NEW' ORDER BY order_id`, account)
+	}
+	return queryOrders(q, `WHERE account = $1 AND symbol = $2 AND status = 'NEW' ORDER BY order_id`, account, symbol)
+}
+
+// allOrders returns up to limit orders of an account's symbol, oldest first: from fromOrderID
+// on when it is set, else the most recent ones
+func allOrders(q querier, account, symbol string, fromOrderID int64, limit int) ([]*order, error) {
+	if fromOrderID > 0 {
+		return queryOrders(q, `WHERE account = $1 AND symbol = $2 AND order_id >= $3 ORDER BY order_id LIMIT $4`,
+			account, symbol, fromOrderID, limit)
+	}
+	return queryOrders(q, `WHERE order_id IN (
+			SELECT order_id FROM paper_orders WHERE account = $1 AND symbol = $2 ORDER BY order_id DESC LIMIT $3
+		) ORDER BY order_id`, account, symbol, limit)
+}
+
+// symbolOrders returns the open orders of a symbol across all accounts
+func symbolOrders(q querier, symbol string) ([]*order, error) {
+	return queryOrders(q, `WHERE symbol = $1 AND status = 'NEW' ORDER BY order_id`, symbol)
+}
+
+// openSymbols returns the symbols with open orders
+func openSymbols(q querier) ([]string, error) {
+	rows, err := q.Query(`SELECT DISTINCT symbol FROM paper_orders WHERE status = 'NEW'`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query paper order symbols: %w", err)
+	}
+	defer rows.Close()
+
+	var symbols []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			return nil, fmt.Errorf("failed to scan paper order symbol: %w", err)
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols, rows.Err()
+}
+
+func queryOrders(q querier, where string, args ...interface{}) ([]*order, error) {
+	rows, err := q.Query(`SELECT `+orderColumns+` FROM paper_orders `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query paper orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*order
+	for rows.Next() {
+		o := &order{}
+		var price, origQty, executedQty, cumQuote string
+		err := rows.Scan(&o.OrderID, &o.Account, &o.Symbol, &o.ClientOrderID, &o.Side, &o.Type, &price, &origQty,
+			&executedQty, &cumQuote, &o.Status, &o.CreatedAt, &o.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan paper order: %w", err)
+		}
+		o.Price, _ = decimal.NewFromString(price)
+		o.OrigQty, _ = decimal.NewFromString(origQty)
+		o.ExecutedQty, _ = decimal.NewFromString(executedQty)
+		o.CumQuote, _ = decimal.NewFromString(cumQuote)
+		orders = append(orders, o)
+	}
+	return orders, rows.Err()
+}
+
+// trade is one simulated execution as stored in paper_trades
+type trade struct {
+	ID              int64
+	OrderID         int64
+	Symbol          string
+	Price           decimal.Decimal
+	Qty             decimal.Decimal
+	QuoteQty        decimal.Decimal
+	Commission      decimal.Decimal
+	CommissionAsset string
+	IsBuyer         bool
+	IsMaker         bool
+	Time            int64
+}
+
+func insertTrade(q querier, account string, t *trade) error {
+	query := `
+		INSERT INTO paper_trades (account, order_id, symbol, price, qty, quote_qty, commission, commission_asset, is_buyer, is_maker, time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	result, err := q.Exec(query, account, t.OrderID, t.Symbol, t.Price.String(), t.Qty.String(), t.QuoteQty.String(),
+		t.Commission.String(), t.CommissionAsset, t.IsBuyer, t.IsMaker, t.Time)
+	if err != nil {
+		return fmt.Errorf("failed to store paper trade of order %d: %w", t.OrderID, err)
+	}
+	t.ID, err = result.LastInsertId()
+	return err
+}
+
+func orderTrades(q querier, account, symbol string, orderID int64) ([]*trade, error) {
+	query := `
+		SELECT id, order_id, symbol, price, qty, quote_qty, commission, commission_asset, is_buyer, is_maker, time
+		FROM paper_trades
+		WHERE account = $1 AND symbol = $2 AND order_id = $3
+		ORDER BY id
+	`
+	rows, err := q.Query(query, account, symbol, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query paper trades: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []*trade
+	for rows.Next() {
+		t := &trade{}
+		var price, qty, quoteQty, commission string
+		err := rows.Scan(&t.ID, &t.OrderID, &t.Symbol, &price, &qty, &quoteQty, &commission, &t.CommissionAsset,
+			&t.IsBuyer, &t.IsMaker, &t.Time)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan paper trade: %w", err)
+		}
+		t.Price, _ = decimal.NewFromString(price)
+		t.Qty, _ = decimal.NewFromString(qty)
+		t.QuoteQty, _ = decimal.NewFromString(quoteQty)
+		t.Commission, _ = decimal.NewFromString(commission)
+		trades = append(trades, t)
+	}
+	return trades, rows.Err()
+}
+
+// balance is one simulated asset balance of an account
+type balance struct {
+	Asset  string
+	Free   decimal.Decimal
+	Locked decimal.Decimal
+}
+
+func getBalance(q querier, account, asset string) (*balance, error) {
+	b := &balance{Asset: asset}
+	var free, locked string
+	err := q.QueryRow(`SELECT free, locked FROM paper_balances WHERE account = $1 AND asset = $2`, account, asset).Scan(&free, &locked)
+	if err == sql.ErrNoRows {
+		return b, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read paper %s balance: %w", asset, err)
+	}
+	b.Free, _ = decimal.NewFromString(free)
+	b.Locked, _ = decimal.NewFromString(locked)
+	return b, nil
+}
+
+func saveBalance(q querier, account string, b *balance) error {
+	query := `
+		INSERT INTO paper_balances (account, asset, free, locked) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (account, asset) DO UPDATE SET free = excluded.free, locked = excluded.locked
+	`
+	if _, err := q.Exec(query, account, b.Asset, b.Free.String(), b.Locked.String()); err != nil {
+		return fmt.Errorf("failed to store paper %s balance: %w", b.Asset, err)
+	}
+	return nil
+}
+
+func accountBalances(q querier, account string) ([]*balance, error) {
+	rows, err := q.Query(`SELECT asset, free, locked FROM paper_balances WHERE account = $1 ORDER BY asset`, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query paper balances: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []*balance
+	for rows.Next() {
+		b := &balance{}
+		var free, locked string
+		if err := rows.Scan(&b.Asset, &free, &locked); err != nil {
+			return nil, fmt.Errorf("failed to scan paper balance: %w", err)
+		}
+		b.Free, _ = decimal.NewFromString(free)
+		b.Locked, _ = decimal.NewFromString(locked)
+		balances = append(balances, b)
+	}
+	return balances, rows.Err()
+}
+
+// seedBalances gives an account its starting balances unless it has balances already
+func seedBalances(q querier, account string, starting map[string]decimal.Decimal) (bool, error) {
+	var count int
+	if err := q.QueryRow(`SELECT COUNT(*) FROM paper_balances WHERE account = $1`, account).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to count paper balances: %w", err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+	for asset, amount := range starting {
+		if err := saveBalance(q, account, &balance{Asset: strings.ToUpper(asset), Free: amount}); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
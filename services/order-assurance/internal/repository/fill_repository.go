@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+type FillRepository struct {
+	db *sql.DB
+}
+
+func NewFillRepository(db *sql.DB) *FillRepository {
+	return &FillRepository{db: db}
+}
+
+func (r *FillRepository) scanFill(scanner interface{ Scan(...interface{}) error }) (*models.OrderFill, error) {
+	fill := &models.OrderFill{}
+	var tradedAt string
+	err := scanner.Scan(
+		&fill.ID, &fill.Account, &fill.Symbol, &fill.OrderID, &fill.TradeID, &fill.Side,
+		&fill.Price, &fill.Quantity, &fill.QuoteQuantity,
+		&fill.Commission, &fill.CommissionAsset, &fill.IsMaker, &tradedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse timestamps from TEXT format
+	fill.TradedAt, _ = time.Parse("2006-01-02 15:04:05", tradedAt)
+
+	return fill, nil
+}
+
+// SaveTrades stores an order's executions, skipping trades already recorded
+func (r *FillRepository) SaveTrades(account string, trades []models.BinanceTrade) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO order_fills (
+			account, symbol, order_id, trade_id, side,
+			price, quantity, quote_quantity,
+			commission, commission_asset, is_maker, traded_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (account, symbol, trade_id) DO NOTHING
+	`
+
+	for _, trade := range trades {
+		side := "SELL"
+		if trade.IsBuyer {
+			side = "BUY"
+		}
+
+		_, err := tx.Exec(
+			query,
+			account,
+			trade.Symbol,
+			strconv.FormatInt(trade.OrderID, 10),
+			trade.ID,
+			side,
+			trade.Price,
+			trade.Qty,
+			trade.QuoteQty,
+			trade.Commission,
+			strings.ToUpper(trade.CommissionAsset),
+			trade.IsMaker,
+			time.UnixMilli(trade.Time).UTC().Format("2006-01-02 15:04:05"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to store trade %d: %w", trade.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByOrderID returns the stored executions of an order
+func (r *FillRepository) GetByOrderID(account, symbol, orderID string) ([]*models.OrderFill, error) {
+	query := `
+		SELECT id, account, symbol, order_id, trade_id, side,
+		       price, quantity, quote_quantity,
+		       commission, commission_asset, is_maker, traded_at
+		FROM order_fills
+		WHERE account = $1 AND symbol = $2 AND order_id = $3
+		ORDER BY trade_id ASC
+	`
+
+	rows, err := r.db.Query(query, account, symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fills []*models.OrderFill
+	for rows.Next() {
+		fill, err := r.scanFill(rows)
+		if err != nil {
+			return nil, err
+		}
+		fills = append(fills, fill)
+	}
+
+	return fills, rows.Err()
+}
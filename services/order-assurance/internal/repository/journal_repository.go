@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+// JournalRepository writes the trade journal. It only ever inserts;
+// triggers in the schema reject updates and deletes.
+type JournalRepository struct {
+	db *sql.DB
+}
+
+func NewJournalRepository(db *sql.DB) *JournalRepository {
+	return &JournalRepository{db: db}
+}
+
+func (r *JournalRepository) scanEntry(scanner interface{ Scan(...interface{}) error }) (*models.JournalEntry, error) {
+	entry := &models.JournalEntry{}
+	var tradedAt, recordedAt string
+	err := scanner.Scan(
+		&entry.ID, &entry.Account, &entry.Symbol, &entry.OrderID, &entry.ClientOrderID, &entry.TradeID,
+		&entry.Side, &entry.Price, &entry.Quantity, &entry.QuoteQuantity,
+		&entry.Commission, &entry.CommissionAsset, &entry.IsMaker, &entry.OrderStatus,
+		&tradedAt, &recordedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse timestamps from TEXT format
+	entry.TradedAt, _ = time.Parse("2006-01-02 15:04:05", tradedAt)
+	entry.RecordedAt, _ = time.Parse("2006-01-02 15:04:05", recordedAt)
+
+	return entry, nil
+}
+
+// Append journals an execution; returns false if the trade was already recorded
+func (r *JournalRepository) Append(account string, report models.ExecutionReport) (bool, error) {
+	query := `
+		INSERT INTO trade_journal (
+			account, symbol, order_id, client_order_id, trade_id, side,
+			price, quantity, quote_quantity,
+			commission, commission_asset, is_maker, order_status, traded_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (account, symbol, trade_id) DO NOTHING
+	`
+
+	result, err := r.db.Exec(
+		query,
+		account,
+		report.Symbol,
+		strconv.FormatInt(report.OrderID, 10),
+		report.ClientOrderID,
+		report.TradeID,
+		report.Side,
+		report.LastPrice,
+		report.LastQty,
+		report.LastQuoteQty,
+		report.Commission,
+		strings.ToUpper(report.CommissionAsset),
+		report.IsMaker,
+		report.OrderStatus,
+		time.UnixMilli(report.TradeTime).UTC().Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to journal trade %d: %w", report.TradeID, err)
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// List returns journal entries matching filter in trade order
+func (r *JournalRepository) List(filter models.JournalFilter) ([]*models.JournalEntry, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.Symbol != "" {
+		addCondition("symbol = $%d", filter.Symbol)
+	}
+	if filter.Account != "" {
+		addCondition("account = $%d", filter.Account)
+	}
+	if !filter.From.IsZero() {
+		addCondition("traded_at >= $%d", filter.From.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if !filter.To.IsZero() {
+		addCondition("traded_at < $%d", filter.To.UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := `
+		SELECT id, account, symbol, order_id, client_order_id, trade_id, side,
+		       price, quantity, quote_quantity,
+		       commission, commission_asset, is_maker, order_status, traded_at, recorded_at
+		FROM trade_journal
+		` + where + `
+		ORDER BY traded_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade journal: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.JournalEntry
+	for rows.Next() {
+		entry, err := r.scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
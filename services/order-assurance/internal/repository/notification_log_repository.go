@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+// NotificationLogRepository numbers fill and error notifications and keeps them for replay
+type NotificationLogRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationLogRepository(db *sql.DB) *NotificationLogRepository {
+	return &NotificationLogRepository{db: db}
+}
+
+// Append logs a notification and returns its sequence number
+func (r *NotificationLogRepository) Append(kind, orderID, payload string) (int64, error) {
+	result, err := r.db.Exec(`INSERT INTO notification_log (kind, order_id, payload) VALUES ($1, $2, $3)`, kind, orderID, payload)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// After returns up to limit notifications with a sequence number above afterSeq, oldest first
+func (r *NotificationLogRepository) After(afterSeq int64, limit int) ([]*models.LoggedNotification, error) {
+	rows, err := r.db.Query(`
+		SELECT seq, kind, order_id, payload, created_at FROM notification_log
+		WHERE seq > $1 ORDER BY seq ASC LIMIT $2
+	`, afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.LoggedNotification
+	for rows.Next() {
+		entry := &models.LoggedNotification{}
+		var createdAt string
+		if err := rows.Scan(&entry.Seq, &entry.Kind, &entry.OrderID, &entry.Payload, &createdAt); err != nil {
+			return nil, err
+		}
+		entry.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		result = append(result, entry)
+	}
+	return result, rows.Err()
+}
+
+// LastSeq returns the newest sequence number handed out, 0 if none
+func (r *NotificationLogRepository) LastSeq() (int64, error) {
+	var seq sql.NullInt64
+	if err := r.db.QueryRow(`SELECT MAX(seq) FROM notification_log`).Scan(&seq); err != nil {
+		return 0, err
+	}
+	return seq.Int64, nil
+}
+
+// DeleteBefore drops notifications logged before a time. AUTOINCREMENT never reuses their
+// sequence numbers.
+func (r *NotificationLogRepository) DeleteBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM notification_log WHERE created_at < $1`, before.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type OrderRepository struct {
+	db *sql.DB
+}
+
+func NewOrderRepository(db *sql.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+func (r *OrderRepository) scanOrder(scanner interface{ Scan(...interface{}) error }) (*models.PlacedOrder, error) {
+	order := &models.PlacedOrder{}
+	var createdAt string
+	var updatedAt, expiresAt sql.NullString
+	err := scanner.Scan(
+		&order.ID, &order.Account, &order.Symbol, &order.OrderID,
+		&order.Side, &order.Price, &order.Quantity, &order.Status, &createdAt, &updatedAt, &expiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse timestamps from TEXT format
+	order.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+	if updatedAt.Valid {
+		if t, err := time.Parse("2006-01-02 15:04:05", updatedAt.String); err == nil {
+			order.UpdatedAt = &t
+		}
+	}
+	if expiresAt.Valid {
+		if t, err := time.Parse("2006-01-02 15:04:05", expiresAt.String); err == nil {
+			order.ExpiresAt = &t
+		}
+	}
+
+	return order, nil
+}
+
+// Save records a placed order; re-saving an order returned by the idempotency cache is a no-op
+func (r *OrderRepository) Save(account, symbol, orderID string, side models.OrderSide, price, quantity decimal.Decimal) error {
+	query := `
+		INSERT INTO orders (account, symbol, order_id, side, price, quantity)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (account, symbol, order_id) DO NOTHING
+	`
+
+	if _, err := r.db.Exec(query, account, symbol, orderID, side, price.String(), quantity.String()); err != nil {
+		return fmt.Errorf("failed to store order %s: %w", orderID, err)
+	}
+	return nil
+}
+
+// GetByOrderID returns every stored order with this exchange ID.
+// Binance order IDs are only unique per symbol, so more than one match is possible.
+func (r *OrderRepository) GetByOrderID(orderID string) ([]*models.PlacedOrder, error) {
+	query := `
+		SELECT id, account, symbol, order_id, side, price, quantity, status, created_at, updated_at, expires_at
+		FROM orders
+		WHERE order_id = $1
+		ORDER BY id DESC
+	`
+
+	return r.queryOrders(query, orderID)
+}
+
+// UpdateStatus records the latest exchange status of an order, touching updated_at only on change
+func (r *OrderRepository) UpdateStatus(account, symbol, orderID, status string) error {
+	query := `
+		UPDATE orders
+		SET status = $1, updated_at = datetime('now')
+		WHERE account = $2 AND symbol = $3 AND order_id = $4 AND status != $1
+	`
+
+	if _, err := r.db.Exec(query, status, account, symbol, orderID); err != nil {
+		return fmt.Errorf("failed to update status of order %s: %w", orderID, err)
+	}
+	return nil
+}
+
+// SetExpiry stores when the TTL worker should cancel an order
+func (r *OrderRepository) SetExpiry(account, symbol, orderID string, expiresAt time.Time) error {
+	query := `
+		UPDATE orders
+		SET expires_at = $1
+		WHERE account = $2 AND symbol = $3 AND order_id = $4
+	`
+
+	if _, err := r.db.Exec(query, expiresAt.UTC().Format("2006-01-02 15:04:05"), account, symbol, orderID); err != nil {
+		return fmt.Errorf("failed to store expiry of order %s: %w", orderID, err)
+	}
+	return nil
+}
+
+// GetExpiring returns the open orders that have an expiry, oldest first
+func (r *OrderRepository) GetExpiring() ([]*models.PlacedOrder, error) {
+	query := `
+		SELECT id, account, symbol, order_id, side, price, quantity, status, created_at, updated_at, expires_at
+		FROM orders
+		WHERE expires_at IS NOT NULL AND status = 'open'
+		ORDER BY expires_at
+	`
+
+	return r.queryOrders(query)
+}
+
+// List returns one page of stored orders matching filter, newest first, and the total match count
+func (r *OrderRepository) List(filter models.OrderFilter) ([]*models.PlacedOrder, int, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.Symbol != "" {
+		addCondition("symbol = $%d", filter.Symbol)
+	}
+	if filter.Account != "" {
+		addCondition("account = $%d", filter.Account)
+	}
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		addCondition("created_at >= $%d", filter.From.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if !filter.To.IsZero() {
+		addCondition("created_at < $%d", filter.To.UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM orders "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, account, symbol, order_id, side, price, quantity, status, created_at, updated_at, expires_at
+		FROM orders
+		%s
+		ORDER BY id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+
+	orders, err := r.queryOrders(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list orders: %w", err)
+	}
+	return orders, total, nil
+}
+
+func (r *OrderRepository) queryOrders(query string, args ...interface{}) ([]*models.PlacedOrder, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*models.PlacedOrder
+	for rows.Next() {
+		order, err := r.scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, rows.Err()
+}
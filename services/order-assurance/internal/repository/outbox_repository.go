@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) scanEntry(scanner interface{ Scan(...interface{}) error }) (*models.OutboxEntry, error) {
+	entry := &models.OutboxEntry{}
+	var orderID, lastError sql.NullString
+	var nextAttemptAt, createdAt, updatedAt string
+	err := scanner.Scan(
+		&entry.ID, &entry.Kind, &orderID, &entry.Payload, &entry.Status,
+		&entry.Attempts, &lastError, &nextAttemptAt, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.OrderID = orderID.String
+	entry.LastError = lastError.String
+
+	// Parse timestamps from TEXT format
+	entry.NextAttemptAt, _ = time.Parse("2006-01-02 15:04:05", nextAttemptAt)
+	entry.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+	entry.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+
+	return entry, nil
+}
+
+func (r *OutboxRepository) queryEntries(query string, args ...interface{}) ([]*models.OutboxEntry, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.OutboxEntry
+	for rows.Next() {
+		entry, err := r.scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// Enqueue persists an undelivered notification for scheduled redelivery
+func (r *OutboxRepository) Enqueue(kind, orderID, payload, lastError string) error {
+	query := `
+		INSERT INTO notification_outbox (kind, order_id, payload, last_error)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	if _, err := r.db.Exec(query, kind, orderID, payload, lastError); err != nil {
+		log.Printf("ERROR: Failed to persist %s notification for order %s: %v", kind, orderID, err)
+		return err
+	}
+
+	log.Printf("WARNING: Persisted undelivered %s notification for order %s to outbox", kind, orderID)
+	return nil
+}
+
+// GetDue returns pending entries whose next attempt time has passed
+func (r *OutboxRepository) GetDue(limit int) ([]*models.OutboxEntry, error) {
+	query := `
+		SELECT id, kind, order_id, payload, status, attempts, last_error,
+		       next_attempt_at, created_at, updated_at
+		FROM notification_outbox
+		WHERE status = $1 AND next_attempt_at <= datetime('now')
+		ORDER BY id ASC
+		LIMIT $2
+	`
+
+	return r.queryEntries(query, models.OutboxPending, limit)
+}
+
+// GetByStatus returns entries in the given status, newest first
+func (r *OutboxRepository) GetByStatus(status models.OutboxStatus) ([]*models.OutboxEntry, error) {
+	query := `
+		SELECT id, kind, order_id, payload, status, attempts, last_error,
+		       next_attempt_at, created_at, updated_at
+		FROM notification_outbox
+		WHERE status = $1
+		ORDER BY id DESC
+	`
+
+	return r.queryEntries(query, status)
+}
+
+func (r *OutboxRepository) MarkDelivered(id int) error {
+	query := `
+		UPDATE notification_outbox
+		SET status = $1, attempts = attempts + 1, updated_at = datetime('now')
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(query, models.OutboxDelivered, id)
+	return err
+}
+
+// MarkFailed records a failed redelivery, scheduling the next attempt or
+// moving the entry to the dead-letter set once maxAttempts is reached
+func (r *OutboxRepository) MarkFailed(id int, lastError string, nextAttemptIn time.Duration, maxAttempts int) error {
+	query := `
+		UPDATE notification_outbox
+		SET attempts = attempts + 1,
+		    last_error = $1,
+		    status = CASE WHEN attempts + 1 >= $2 THEN $3 ELSE status END,
+		    next_attempt_at = datetime('now', $4),
+		    updated_at = datetime('now')
+		WHERE id = $5
+	`
+
+	modifier := fmt.Sprintf("+%d seconds", int(nextAttemptIn.Seconds()))
+	_, err := r.db.Exec(query, lastError, maxAttempts, models.OutboxDead, modifier, id)
+	return err
+}
+
+// Requeue moves a dead-letter entry back to pending for immediate redelivery
+func (r *OutboxRepository) Requeue(id int) (bool, error) {
+	query := `
+		UPDATE notification_outbox
+		SET status = $1, attempts = 0, next_attempt_at = datetime('now'), updated_at = datetime('now')
+		WHERE id = $2 AND status = $3
+	`
+
+	result, err := r.db.Exec(query, models.OutboxPending, id, models.OutboxDead)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected > 0 {
+		log.Printf("INFO: Requeued dead-letter notification %d", id)
+	}
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+// PlacementAuditRepository writes the placement audit. It only ever inserts;
+// triggers in the schema reject updates and deletes.
+type PlacementAuditRepository struct {
+	db *sql.DB
+}
+
+func NewPlacementAuditRepository(db *sql.DB) *PlacementAuditRepository {
+	return &PlacementAuditRepository{db: db}
+}
+
+// Append records one order submission
+func (r *PlacementAuditRepository) Append(audit *models.PlacementAudit) error {
+	request, err := json.Marshal(audit.Request)
+	if err != nil {
+		return fmt.Errorf("failed to encode audited request: %w", err)
+	}
+
+	query := `
+		INSERT INTO placement_audit (
+			account, symbol, side, client_order_id, order_id, transport,
+			request, status_code, response, error, duration_ms
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err = r.db.Exec(query,
+		audit.Account, audit.Symbol, audit.Side, audit.ClientOrderID, audit.OrderID, audit.Transport,
+		string(request), audit.StatusCode, audit.Response, audit.Error, audit.DurationMs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to audit %s placement %s: %w", audit.Symbol, audit.ClientOrderID, err)
+	}
+	return nil
+}
+
+// List returns audited submissions matching filter, newest first
+func (r *PlacementAuditRepository) List(filter models.PlacementAuditFilter) ([]*models.PlacementAudit, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.Symbol != "" {
+		addCondition("symbol = $%d", filter.Symbol)
+	}
+	if filter.Account != "" {
+		addCondition("account = $%d", filter.Account)
+	}
+	if filter.ClientOrderID != "" {
+		addCondition("client_order_id = $%d", filter.ClientOrderID)
+	}
+	if filter.OrderID != "" {
+		addCondition("order_id = $%d", filter.OrderID)
+	}
+	if !filter.From.IsZero() {
+		addCondition("created_at >= $%d", filter.From.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if !filter.To.IsZero() {
+		addCondition("created_at < $%d", filter.To.UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit)
+	query := `
+		SELECT id, account, symbol, side, client_order_id, order_id, transport,
+		       request, status_code, response, error, duration_ms, created_at
+		FROM placement_audit
+		` + where + `
+		ORDER BY id DESC
+		LIMIT $` + fmt.Sprint(len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query placement audit: %w", err)
+	}
+	defer rows.Close()
+
+	var audits []*models.PlacementAudit
+	for rows.Next() {
+		audit := &models.PlacementAudit{}
+		var request, createdAt string
+		err := rows.Scan(
+			&audit.ID, &audit.Account, &audit.Symbol, &audit.Side, &audit.ClientOrderID, &audit.OrderID, &audit.Transport,
+			&request, &audit.StatusCode, &audit.Response, &audit.Error, &audit.DurationMs, &createdAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan placement audit: %w", err)
+		}
+		if err := json.Unmarshal([]byte(request), &audit.Request); err != nil {
+			return nil, fmt.Errorf("failed to decode audited request %d: %w", audit.ID, err)
+		}
+		audit.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		audits = append(audits, audit)
+	}
+
+	return audits, rows.Err()
+}
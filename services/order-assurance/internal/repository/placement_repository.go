@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+type PlacementRepository struct {
+	db *sql.DB
+}
+
+func NewPlacementRepository(db *sql.DB) *PlacementRepository {
+	return &PlacementRepository{db: db}
+}
+
+// Create records an order about to be submitted to the exchange
+func (r *PlacementRepository) Create(p *models.PendingPlacement) error {
+	query := `
+		INSERT INTO pending_placements (account, symbol, client_order_id, side, price, quantity)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if _, err := r.db.Exec(query, p.Account, p.Symbol, p.ClientOrderID, p.Side, p.Price.String(), p.Quantity.String()); err != nil {
+		return fmt.Errorf("failed to record pending placement %s: %w", p.ClientOrderID, err)
+	}
+	return nil
+}
+
+// Delete removes a placement whose outcome is known
+func (r *PlacementRepository) Delete(clientOrderID string) error {
+	if _, err := r.db.Exec(`DELETE FROM pending_placements WHERE client_order_id = $1`, clientOrderID); err != nil {
+		return fmt.Errorf("failed to clear pending placement %s: %w", clientOrderID, err)
+	}
+	return nil
+}
+
+// List returns every unresolved placement, oldest first
+func (r *PlacementRepository) List() ([]*models.PendingPlacement, error) {
+	query := `
+		SELECT id, account, symbol, client_order_id, side, price, quantity, created_at
+		FROM pending_placements
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending placements: %w", err)
+	}
+	defer rows.Close()
+
+	var placements []*models.PendingPlacement
+	for rows.Next() {
+		p := &models.PendingPlacement{}
+		var createdAt string
+		if err := rows.Scan(&p.ID, &p.Account, &p.Symbol, &p.ClientOrderID, &p.Side, &p.Price, &p.Quantity, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan pending placement: %w", err)
+		}
+		p.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		placements = append(placements, p)
+	}
+
+	return placements, rows.Err()
+}
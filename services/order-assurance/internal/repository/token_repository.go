@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+type TokenRepository struct {
+	db *sql.DB
+}
+
+func NewTokenRepository(db *sql.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+func parseNullTime(value sql.NullString) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02 15:04:05", value.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func (r *TokenRepository) scanToken(scanner interface{ Scan(...interface{}) error }) (*models.APIToken, error) {
+	token := &models.APIToken{}
+	var scopes, createdAt string
+	var expiresAt, lastUsedAt, revokedAt sql.NullString
+	err := scanner.Scan(
+		&token.ID, &token.Name, &token.Prefix, &scopes,
+		&expiresAt, &lastUsedAt, &revokedAt, &createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Scopes = strings.Split(scopes, ",")
+
+	// Parse timestamps from TEXT format
+	token.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+	token.ExpiresAt = parseNullTime(expiresAt)
+	token.LastUsedAt = parseNullTime(lastUsedAt)
+	token.RevokedAt = parseNullTime(revokedAt)
+
+	return token, nil
+}
+
+// Create stores a new token under its hash
+func (r *TokenRepository) Create(name, tokenHash, prefix string, scopes []string, expiresAt *time.Time) (*models.APIToken, error) {
+	query := `
+		INSERT INTO api_tokens (name, token_hash, prefix, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, name, prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+	`
+
+	var expires interface{}
+	if expiresAt != nil {
+		expires = expiresAt.UTC().Format("2006-01-02 15:04:05")
+	}
+
+	return r.scanToken(r.db.QueryRow(query, name, tokenHash, prefix, strings.Join(scopes, ","), expires))
+}
+
+// List returns every token, revoked and expired ones included, newest first
+func (r *TokenRepository) List() ([]*models.APIToken, error) {
+	query := `
+		SELECT id, name, prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM api_tokens
+		ORDER BY id DESC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*models.APIToken
+	for rows.Next() {
+		token, err := r.scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+// GetByHash returns the token with the given hash, or nil if there is none
+func (r *TokenRepository) GetByHash(tokenHash string) (*models.APIToken, error) {
+	query := `
+		SELECT id, name, prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM api_tokens
+		WHERE token_hash = $1
+	`
+
+	token, err := r.scanToken(r.db.QueryRow(query, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return token, err
+}
+
+// Revoke disables a token for good. Returns false if it doesn't exist or was already revoked.
+func (r *TokenRepository) Revoke(id int) (bool, error) {
+	query := `
+		UPDATE api_tokens
+		SET revoked_at = datetime('now')
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// TouchLastUsed records a use, at most once a minute so busy callers don't write on every request
+func (r *TokenRepository) TouchLastUsed(id int) error {
+	query := `
+		UPDATE api_tokens
+		SET last_used_at = datetime('now')
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < datetime('now', '-60 seconds'))
+	`
+
+	_, err := r.db.Exec(query, id)
+	return err
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+)
+
+// GetBookTicker returns the best bid and ask of a symbol on an account's market
+func (s *OrderService) GetBookTicker(account, symbol string) (*contracts.BookTicker, error) {
+	binance, err := s.accounts.Binance(account)
+	if err != nil {
+		return nil, err
+	}
+	return binance.GetBookTicker(strings.ToUpper(symbol))
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+// CancelOrder cancels an open order on request (e.g. grid-trading pausing a symbol). grid-trading
+// learns about it through the usual cancel notification, with any part that filled before the cancel.
+func (s *OrderService) CancelOrder(account, symbol, orderID string) (*models.OrderStatus, error) {
+	venue, err := s.accounts.Get(account)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol = strings.ToUpper(symbol)
+	logger := orderLogger(account, symbol, orderID)
+	logger.Info("Cancelling order on request")
+
+	var cancelled *models.BinanceOrder
+	if queueErr := s.queue.Do(queueKey(account, symbol), func() {
+		cancelled, err = venue.CancelOrder(symbol, orderID)
+	}); queueErr != nil {
+		err = queueErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status := exchange.ConvertBinanceStatus(cancelled.Status)
+	if err := s.orders.UpdateStatus(account, symbol, orderID, status); err != nil {
+		logger.Error("Failed to update stored order status", logging.Err(err))
+	}
+
+	side := models.OrderSide(strings.ToLower(cancelled.Side))
+	s.sendCancelNotification(venue, account, symbol, side, cancelled)
+
+	return &models.OrderStatus{OrderID: strconv.FormatInt(cancelled.OrderID, 10), Status: status}, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// recordCommission stores a filled order's trades and returns the total commission paid.
+// Trades are fetched from Binance once; later lookups are served from the local store.
+// Returns an empty asset when the commission could not be determined, as on other venues.
+func (s *OrderService) recordCommission(venue exchange.Exchange, account string, order *models.BinanceOrder) (decimal.Decimal, string) {
+	orderID := strconv.FormatInt(order.OrderID, 10)
+	executedQty, _ := decimal.NewFromString(order.ExecutedQty)
+	logger := orderLogger(account, order.Symbol, orderID)
+
+	fills, err := s.fills.GetByOrderID(account, order.Symbol, orderID)
+	if err != nil {
+		logger.Error("Failed to read stored fills", logging.Err(err))
+	}
+
+	if !fillsCover(fills, executedQty) {
+		binance, ok := venue.(*exchange.BinanceClient)
+		if !ok {
+			return decimal.Zero, ""
+		}
+		trades, err := binance.GetOrderTrades(order.Symbol, orderID)
+		if err != nil {
+			logger.Warn("Failed to fetch trades, commission unknown", logging.Err(err))
+			return decimal.Zero, ""
+		}
+
+		if err := s.fills.SaveTrades(account, trades); err != nil {
+			logger.Error("Failed to store trades", logging.Err(err))
+		}
+		fills = fillsFromTrades(account, trades)
+	}
+
+	return summarizeCommission(orderID, fills)
+}
+
+// fillsCover reports whether stored fills account for the whole executed quantity
+func fillsCover(fills []*models.OrderFill, executedQty decimal.Decimal) bool {
+	if len(fills) == 0 {
+		return false
+	}
+
+	total := decimal.Zero
+	for _, fill := range fills {
+		total = total.Add(fill.Quantity)
+	}
+	return total.GreaterThanOrEqual(executedQty)
+}
+
+func fillsFromTrades(account string, trades []models.BinanceTrade) []*models.OrderFill {
+	fills := make([]*models.OrderFill, 0, len(trades))
+	for _, trade := range trades {
+		price, _ := decimal.NewFromString(trade.Price)
+		qty, _ := decimal.NewFromString(trade.Qty)
+		quoteQty, _ := decimal.NewFromString(trade.QuoteQty)
+		commission, _ := decimal.NewFromString(trade.Commission)
+
+		side := "SELL"
+		if trade.IsBuyer {
+			side = "BUY"
+		}
+
+		fills = append(fills, &models.OrderFill{
+			Account:         account,
+			Symbol:          trade.Symbol,
+			OrderID:         strconv.FormatInt(trade.OrderID, 10),
+			TradeID:         trade.ID,
+			Side:            side,
+			Price:           price,
+			Quantity:        qty,
+			QuoteQuantity:   quoteQty,
+			Commission:      commission,
+			CommissionAsset: strings.ToUpper(trade.CommissionAsset),
+			IsMaker:         trade.IsMaker,
+			TradedAt:        time.UnixMilli(trade.Time).UTC(),
+		})
+	}
+	return fills
+}
+
+// summarizeCommission totals commission across fills. Binance charges one asset per order
+// in practice (BNB when fee discount is on); mixed assets report the first one only.
+func summarizeCommission(orderID string, fills []*models.OrderFill) (decimal.Decimal, string) {
+	total := decimal.Zero
+	asset := ""
+	for _, fill := range fills {
+		if asset == "" {
+			asset = fill.CommissionAsset
+		}
+		if fill.CommissionAsset != asset {
+			slog.Warn("Order paid commission in two assets, reporting the first only", logging.KeyOrderID, orderID,
+				"commission_asset", asset, "other_asset", fill.CommissionAsset)
+			continue
+		}
+		total = total.Add(fill.Commission)
+	}
+	return total, asset
+}
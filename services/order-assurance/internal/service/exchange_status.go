@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/client"
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+)
+
+// exchangeStatusValidChecks is how many check intervals a degraded broadcast holds
+// grid-trading paused for without being refreshed
+const exchangeStatusValidChecks = 3
+
+// ExchangeStatusMonitor checks Binance's system status and the circuit breakers, and tells
+// grid-trading when the exchange becomes degraded (maintenance or repeated 5xx) and when it recovers
+type ExchangeStatusMonitor struct {
+	accounts   *exchange.Accounts
+	gridClient *client.Notifier
+	interval   time.Duration
+	noSystem   bool // Only watch the circuit breakers - the testnet has no system status endpoint
+
+	mu      sync.Mutex
+	status  contracts.ExchangeStatus
+	pending bool // Last broadcast failed - send again even if the state didn't change
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewExchangeStatusMonitor(accounts *exchange.Accounts, gridClient *client.Notifier, interval time.Duration) *ExchangeStatusMonitor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ExchangeStatusMonitor{
+		accounts:   accounts,
+		gridClient: gridClient,
+		interval:   interval,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+func (m *ExchangeStatusMonitor) Start() {
+	log.Printf("Starting exchange status monitor with interval: %s", m.interval)
+	m.wg.Add(1)
+	go m.loop()
+}
+
+func (m *ExchangeStatusMonitor) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// SkipSystemStatus leaves out Binance's maintenance status and watches only the circuit breakers
+func (m *ExchangeStatusMonitor) SkipSystemStatus() {
+	m.noSystem = true
+}
+
+// Status returns the result of the last check
+func (m *ExchangeStatusMonitor) Status() contracts.ExchangeStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+func (m *ExchangeStatusMonitor) loop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	m.check()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// check broadcasts the exchange status on every change, and on every check while degraded
+// so grid-trading's pause doesn't lapse
+func (m *ExchangeStatusMonitor) check() {
+	status := contracts.ExchangeStatus{
+		CheckedAt:   time.Now().UTC(),
+		ValidForSec: int((exchangeStatusValidChecks * m.interval).Seconds()),
+	}
+	status.Degraded, status.Reason = m.degraded()
+
+	m.mu.Lock()
+	previous := m.status
+	m.status = status
+	send := status.Degraded || status.Degraded != previous.Degraded || m.pending
+	m.mu.Unlock()
+
+	switch {
+	case status.Degraded && !previous.Degraded:
+		slog.Warn("Exchange degraded, pausing grid-trading", "reason", status.Reason)
+	case !status.Degraded && previous.Degraded:
+		slog.Info("Exchange recovered, resuming grid-trading")
+	}
+
+	if !send {
+		return
+	}
+
+	err := m.gridClient.SendExchangeStatus(status)
+	m.mu.Lock()
+	m.pending = err != nil
+	m.mu.Unlock()
+	if err != nil {
+		slog.Error("Failed to send exchange status to grid-trading, retrying next check", logging.Err(err))
+	}
+}
+
+// degraded reports maintenance announced by Binance, or any open circuit breaker
+func (m *ExchangeStatusMonitor) degraded() (bool, string) {
+	var maintenance bool
+	var msg string
+	var err error
+	if !m.noSystem {
+		var binance *exchange.BinanceClient
+		if binance, err = m.accounts.Binance(""); err == nil {
+			maintenance, msg, err = binance.GetSystemStatus()
+		}
+	}
+	if err == nil && maintenance {
+		if msg == "" {
+			msg = "system maintenance"
+		}
+		return true, msg
+	}
+
+	// A single failed status request isn't an outage - repeated ones open the market breaker.
+	// A breaker past its cooldown waits for its next request to probe, so it no longer counts.
+	for _, binance := range m.accounts.All() {
+		for _, breaker := range binance.CircuitStatuses() {
+			if breaker.State == exchange.CircuitOpen && breaker.RetryAfterSec > 0 {
+				return true, fmt.Sprintf("%s circuit open (last: %s)", breaker.Name, breaker.LastError)
+			}
+		}
+	}
+
+	if err != nil {
+		slog.Warn("Failed to get exchange system status", logging.Err(err))
+	}
+	return false, ""
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"strconv"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+// PushFill handles an executionReport from an account's user-data stream. Each trade of an order
+// placed here is stored with its commission, and once the order is FILLED grid-trading is notified
+// straight away instead of on its next sync. Orders placed elsewhere are ignored, and so are orders
+// already seen filled - a sync that got there first has notified grid-trading already.
+func (s *OrderService) PushFill(account string, report models.ExecutionReport) {
+	if report.ExecutionType != "TRADE" {
+		return
+	}
+
+	orderID := strconv.FormatInt(report.OrderID, 10)
+	logger := orderLogger(account, report.Symbol, orderID)
+	placed, err := s.placedOrder(account, report.Symbol, orderID)
+	if err != nil {
+		logger.Error("Failed to look up streamed order", logging.Err(err))
+		return
+	}
+	if placed == nil {
+		return
+	}
+
+	trade := models.BinanceTrade{
+		Symbol:          report.Symbol,
+		ID:              report.TradeID,
+		OrderID:         report.OrderID,
+		Price:           report.LastPrice,
+		Qty:             report.LastQty,
+		QuoteQty:        report.LastQuoteQty,
+		Commission:      report.Commission,
+		CommissionAsset: report.CommissionAsset,
+		Time:            report.TradeTime,
+		IsBuyer:         report.Side == "BUY",
+		IsMaker:         report.IsMaker,
+	}
+	if err := s.fills.SaveTrades(account, []models.BinanceTrade{trade}); err != nil {
+		logger.Error("Failed to store streamed trade", "trade_id", report.TradeID, logging.Err(err))
+	}
+
+	if report.OrderStatus != "FILLED" || placed.Status == "filled" {
+		return
+	}
+
+	venue, err := s.accounts.Get(account)
+	if err != nil {
+		logger.Error("Failed to push fill", logging.Err(err))
+		return
+	}
+
+	// The stored trades cover the order, so the commission needs no myTrades call
+	logger.Info("Order filled on the user-data stream")
+	s.orderStatus(venue, account, &models.BinanceOrder{
+		Symbol:              report.Symbol,
+		OrderID:             report.OrderID,
+		ClientOrderID:       report.ClientOrderID,
+		ExecutedQty:         report.CumulativeQty,
+		CummulativeQuoteQty: report.CumulativeQuote,
+		Status:              report.OrderStatus,
+		Type:                report.OrderType,
+		Side:                report.Side,
+	})
+}
+
+// placedOrder returns the stored order with this ID on the account and symbol, nil if it wasn't placed here
+func (s *OrderService) placedOrder(account, symbol, orderID string) (*models.PlacedOrder, error) {
+	stored, err := s.orders.GetByOrderID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range stored {
+		if order.Account == account && order.Symbol == symbol {
+			return order, nil
+		}
+	}
+	return nil, nil
+}
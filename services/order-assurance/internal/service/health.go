@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+)
+
+// maxClockSkew stays well inside the 5000ms recvWindow used for signed requests
+const maxClockSkew = 1 * time.Second
+
+// HealthCheck is the result of a single deep health probe
+type HealthCheck struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	Detail    string `json:"detail,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// HealthReport aggregates deep health probes
+type HealthReport struct {
+	Status string        `json:"status"` // healthy | unhealthy
+	Checks []HealthCheck `json:"checks"`
+}
+
+// DeepHealthCheck verifies the service is able to trade, not just running:
+// Binance reachability, credential validity and clock skew (credentials only on other venues)
+func (s *OrderService) DeepHealthCheck() *HealthReport {
+	binance, err := s.accounts.Binance("")
+	if err != nil {
+		return newHealthReport(s.credentialChecks())
+	}
+
+	checks := []HealthCheck{
+		runHealthCheck("binance_reachable", func() (string, error) {
+			return "", binance.Ping()
+		}),
+	}
+	checks = append(checks, s.credentialChecks()...)
+	checks = append(checks,
+		runHealthCheck("clock_skew", func() (string, error) {
+			before := time.Now()
+			serverTime, err := binance.GetServerTime()
+			if err != nil {
+				return "", err
+			}
+			// Compare against the midpoint of the request to discount network latency
+			localTime := before.Add(time.Since(before) / 2)
+			skew := localTime.Sub(serverTime)
+			if skew.Abs() > maxClockSkew {
+				return "", fmt.Errorf("local clock off by %s (max %s)", skew, maxClockSkew)
+			}
+			return fmt.Sprintf("skew %s", skew), nil
+		}),
+	)
+	return newHealthReport(checks)
+}
+
+// credentialChecks probes the credentials of the master account and every sub-account
+func (s *OrderService) credentialChecks() []HealthCheck {
+	checks := []HealthCheck{
+		runHealthCheck("credentials_valid", func() (string, error) {
+			return checkCredentials(s.accounts.Master())
+		}),
+	}
+	for _, name := range s.accounts.Names() {
+		venue, _ := s.accounts.Get(name)
+		checks = append(checks, runHealthCheck("credentials_valid:"+name, func() (string, error) {
+			return checkCredentials(venue)
+		}))
+	}
+	return checks
+}
+
+func newHealthReport(checks []HealthCheck) *HealthReport {
+	report := &HealthReport{Status: "healthy", Checks: checks}
+	for _, check := range checks {
+		if !check.Healthy {
+			report.Status = "unhealthy"
+		}
+	}
+
+	return report
+}
+
+// checkCredentials proves an API key pair works by reading the account's balances
+func checkCredentials(venue exchange.Exchange) (string, error) {
+	balances, err := venue.GetBalances()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d assets with balance", len(balances)), nil
+}
+
+func runHealthCheck(name string, probe func() (string, error)) HealthCheck {
+	start := time.Now()
+	detail, err := probe()
+	check := HealthCheck{
+		Name:      name,
+		Healthy:   err == nil,
+		Detail:    detail,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		check.Detail = err.Error()
+	}
+	return check
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/grid-trading-bot/services/order-assurance/internal/repository"
+)
+
+// ErrNotificationLogOff means notifications are not numbered, so there is nothing to replay
+var ErrNotificationLogOff = errors.New("notification log is not enabled")
+
+// UseNotificationLog serves the notifications the notifier numbered into log, so grid-trading
+// can pull the ones it missed with ReplayNotifications
+func (s *OrderService) UseNotificationLog(log *repository.NotificationLogRepository) {
+	s.notificationLog = log
+}
+
+// ReplayNotifications returns up to limit logged notifications after afterSeq, oldest first,
+// each with its sequence number set
+func (s *OrderService) ReplayNotifications(afterSeq int64, limit int) (*contracts.NotificationReplay, error) {
+	if s.notificationLog == nil {
+		return nil, ErrNotificationLogOff
+	}
+
+	entries, err := s.notificationLog.After(afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notifications after seq %d: %w", afterSeq, err)
+	}
+	// Read last, so it is never below a returned seq
+	lastSeq, err := s.notificationLog.LastSeq()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last notification seq: %w", err)
+	}
+
+	replay := &contracts.NotificationReplay{Notifications: []contracts.ReplayedNotification{}, LastSeq: lastSeq}
+	for _, entry := range entries {
+		notification := contracts.ReplayedNotification{Seq: entry.Seq, CreatedAt: entry.CreatedAt}
+		switch entry.Kind {
+		case models.NotificationKindFill:
+			notification.Fill = &contracts.FillNotification{}
+			err = json.Unmarshal([]byte(entry.Payload), notification.Fill)
+			notification.Fill.Seq = entry.Seq
+		case models.NotificationKindError:
+			notification.Error = &contracts.ErrorNotification{}
+			err = json.Unmarshal([]byte(entry.Payload), notification.Error)
+			notification.Error.Seq = entry.Seq
+		default:
+			err = fmt.Errorf("unknown kind %q", entry.Kind)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode notification %d: %w", entry.Seq, err)
+		}
+		replay.Notifications = append(replay.Notifications, notification)
+	}
+	return replay, nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/shopspring/decimal"
+)
+
+// GetOpenOrders lists every order open on a symbol of an account, including ones placed outside
+// the grid, so callers can find orders nothing tracks
+func (s *OrderService) GetOpenOrders(account, symbol string) (*contracts.OpenOrdersResponse, error) {
+	binance, err := s.accounts.Binance(account)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol = strings.ToUpper(symbol)
+	orders, err := binance.GetOpenOrders(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &contracts.OpenOrdersResponse{Symbol: symbol, Account: account, Orders: []contracts.OpenOrder{}}
+	for _, order := range orders {
+		price, _ := decimal.NewFromString(order.Price)
+		quantity, _ := decimal.NewFromString(order.OrigQty)
+		executed, _ := decimal.NewFromString(order.ExecutedQty)
+		resp.Orders = append(resp.Orders, contracts.OpenOrder{
+			OrderID:     strconv.FormatInt(order.OrderID, 10),
+			Side:        contracts.OrderSide(strings.ToLower(order.Side)),
+			Price:       price,
+			Quantity:    quantity,
+			ExecutedQty: executed,
+			CreatedAt:   time.UnixMilli(order.Time).UTC(),
+		})
+	}
+	return resp, nil
+}
@@ -1,83 +1,405 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"strconv"
+	"time"
 
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/pkg/tracing"
+	"github.com/grid-trading-bot/services/order-assurance/internal/client"
 	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
 	"github.com/grid-trading-bot/services/order-assurance/internal/models"
-	"github.com/grid-trading-bot/services/order-assurance/internal/client"
+	"github.com/grid-trading-bot/services/order-assurance/internal/repository"
 	"github.com/shopspring/decimal"
 )
 
 type OrderService struct {
-	binance    *exchange.BinanceClient
+	accounts   *exchange.Accounts
 	gridClient *client.Notifier
+	ttlWorker  *TTLWorker
+	queue      *SymbolQueue
+	outbox     *repository.OutboxRepository
+	fills      *repository.FillRepository
+	orders     *repository.OrderRepository
+	journal    *repository.JournalRepository
+	placements *repository.PlacementRepository
+
+	// Whitelisted profit sweep withdrawal target (empty = withdrawals disabled)
+	withdrawAddress string
+	withdrawNetwork string
+
+	// Placement request/response audit (nil = not recorded)
+	placementAudit *repository.PlacementAuditRepository
+
+	// Numbered fill and error notifications for replay (nil = not numbered)
+	notificationLog *repository.NotificationLogRepository
 }
 
-func NewOrderService(binance *exchange.BinanceClient, gridClient *client.Notifier) *OrderService {
+// ErrAmbiguousOrder means an order ID matched orders on several symbols or accounts
+var ErrAmbiguousOrder = errors.New("order ID matches more than one order - specify the symbol")
+
+func NewOrderService(accounts *exchange.Accounts, gridClient *client.Notifier, ttlWorker *TTLWorker, queue *SymbolQueue, outbox *repository.OutboxRepository, fills *repository.FillRepository, orders *repository.OrderRepository, journal *repository.JournalRepository, placements *repository.PlacementRepository) *OrderService {
 	return &OrderService{
-		binance:    binance,
+		accounts:   accounts,
 		gridClient: gridClient,
+		ttlWorker:  ttlWorker,
+		queue:      queue,
+		outbox:     outbox,
+		fills:      fills,
+		orders:     orders,
+		journal:    journal,
+		placements: placements,
 	}
 }
 
-// PlaceOrder handles idempotent order placement
-func (s *OrderService) PlaceOrder(req models.OrderRequest) (*models.OrderResponse, error) {
+// PlaceOrder handles idempotent order placement. ctx carries the caller's trace; the pending
+// placement record, the wait for the symbol queue and the exchange call are traced separately.
+func (s *OrderService) PlaceOrder(ctx context.Context, req models.OrderRequest) (resp *models.OrderResponse, err error) {
+	ctx, span := tracing.Start(ctx, "place order")
+	span.SetAttr("symbol", req.Symbol)
+	span.SetAttr("side", string(req.Side))
+	span.SetAttr("account", accountName(req.Account))
+	if req.OrderType != "" {
+		span.SetAttr("order_type", string(req.OrderType))
+	}
+	if req.StopPrice != nil {
+		span.SetAttr("stop_price", req.StopPrice.String())
+	}
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	// Convert USDT amount to coin amount for buy orders
+	logger := orderLogger(req.Account, req.Symbol, "").With("side", req.Side, "price", req.Price)
 	quantity := req.Amount
 	if req.Side == models.SideBuy {
 		// For buy orders, amount is in USDT, need to convert to coin quantity
 		quantity = req.Amount.Div(req.Price)
-		log.Printf("INFO: Converting buy amount - %s USDT @ %s = %s coins", req.Amount, req.Price, quantity)
+		logger.Info("Converting buy amount", "amount_usdt", req.Amount, "quantity", quantity)
 	}
 
-	log.Printf("INFO: Placing order - Symbol: %s, Side: %s, Price: %s, Quantity: %s", req.Symbol, req.Side, req.Price, quantity)
+	logger = logger.With("quantity", quantity)
+	logger.Info("Placing order")
+
+	venue, err := s.accounts.Get(req.Account)
+	if err != nil {
+		return nil, err
+	}
+	var binance *exchange.BinanceClient
+	if req.QuoteOrderQty || req.Market || req.StopPrice != nil {
+		if binance, err = s.accounts.Binance(req.Account); err != nil {
+			return nil, err
+		}
+	}
 
-	// Place order on Binance (idempotent via cache)
-	binanceOrder, err := s.binance.PlaceOrder(req.Symbol, req.Side, req.Price, quantity)
+	// Record the placement first so it can be reconciled if we crash before answering
+	pending := &models.PendingPlacement{
+		Account:       req.Account,
+		Symbol:        req.Symbol,
+		ClientOrderID: newClientOrderID(),
+		Side:          req.Side,
+		Price:         req.Price,
+		Quantity:      quantity,
+	}
+	_, dbSpan := tracing.Start(ctx, "db record pending placement")
+	err = s.placements.Create(pending)
+	dbSpan.RecordError(err)
+	dbSpan.End()
 	if err != nil {
-		log.Printf("ERROR: Order placement failed - Symbol: %s, Side: %s, Price: %s, Quantity: %s, Error: %v",
-			req.Symbol, req.Side, req.Price, quantity, err)
+		return nil, err
+	}
+
+	// Place order on Binance (idempotent via cache), serialized with other operations on this symbol
+	var binanceOrder *models.BinanceOrder
+	var oco *exchange.OCOSell
+	_, waitSpan := tracing.Start(ctx, "symbol queue wait")
+	if queueErr := s.queue.Do(queueKey(req.Account, req.Symbol), func() {
+		waitSpan.End()
+		_, exchangeSpan := tracing.StartKind(ctx, "binance place order", tracing.KindClient)
+		exchangeSpan.SetAttr("client_order_id", pending.ClientOrderID)
+		defer func() {
+			exchangeSpan.RecordError(err)
+			exchangeSpan.End()
+		}()
+
+		if req.QuoteOrderQty {
+			// Spend exactly the USDT amount - the quantity above is only an estimate
+			binanceOrder, err = binance.PlaceQuoteBuy(req.Symbol, req.Amount, pending.ClientOrderID)
+			return
+		}
+		if req.Market {
+			binanceOrder, err = binance.PlaceMarketSell(req.Symbol, req.Price, quantity, pending.ClientOrderID)
+			return
+		}
+		if req.StopPrice != nil {
+			if oco, err = binance.PlaceOCOSell(req.Symbol, req.Price, *req.StopPrice, quantity, pending.ClientOrderID); err == nil {
+				binanceOrder = oco.Limit
+			}
+			return
+		}
+		binanceOrder, err = venue.PlaceOrder(req.Symbol, req.Side, req.OrderType, req.Price, quantity, pending.ClientOrderID)
+	}); queueErr != nil {
+		waitSpan.RecordError(queueErr)
+		waitSpan.End()
+		err = queueErr
+	}
+
+	// An order that may have reached Binance stays pending until the next startup check
+	if err == nil || !placementMayHaveLanded(err) {
+		if delErr := s.placements.Delete(pending.ClientOrderID); delErr != nil {
+			logger.Error("Failed to delete pending placement", logging.Err(delErr))
+		}
+	}
+
+	if err != nil {
+		logger.Error("Order placement failed", logging.Err(err))
+
+		var orderErr *exchange.OrderError
+		if errors.As(err, &orderErr) && orderErr.Terminal() {
+			go s.sendRejectionNotification(req, orderErr)
+		}
+
 		return nil, fmt.Errorf("failed to place order on Binance: %w", err)
 	}
 
-	log.Printf("SUCCESS: Order assured - Order ID: %s, Symbol: %s, Side: %s", strconv.FormatInt(binanceOrder.OrderID, 10), req.Symbol, req.Side)
+	orderID := strconv.FormatInt(binanceOrder.OrderID, 10)
+	market := req.QuoteOrderQty || req.Market || req.OrderType == models.OrderTypeMarket
+	if executed, err := decimal.NewFromString(binanceOrder.ExecutedQty); market && err == nil && executed.IsPositive() {
+		quantity = executed
+	}
+	logger = logger.With(logging.KeyOrderID, orderID)
+	logger.Info("Order assured")
+	span.SetAttr("order_id", orderID)
+
+	// The order is live either way - a missing record only breaks lookups by order ID alone
+	_, dbSpan = tracing.Start(ctx, "db save order")
+	if err := s.orders.Save(req.Account, req.Symbol, orderID, req.Side, req.Price, quantity); err != nil {
+		dbSpan.RecordError(err)
+		logger.Error("Failed to record order in local store", logging.Err(err))
+	}
+	dbSpan.End()
 
-	return &models.OrderResponse{
-		OrderID: strconv.FormatInt(binanceOrder.OrderID, 10),
+	resp = &models.OrderResponse{
+		OrderID: orderID,
 		Status:  "assured",
-	}, nil
+	}
+
+	// Both legs of an OCO are looked up by order ID; cancelling the limit leg on TTL expiry cancels the stop too
+	if oco != nil {
+		resp.StopOrderID = strconv.FormatInt(oco.Stop.OrderID, 10)
+		span.SetAttr("stop_order_id", resp.StopOrderID)
+		if err := s.orders.Save(req.Account, req.Symbol, resp.StopOrderID, req.Side, *req.StopPrice, quantity); err != nil {
+			logger.Error("Failed to record stop-loss order in local store", "stop_order_id", resp.StopOrderID, logging.Err(err))
+		}
+	}
+
+	if req.TTLSeconds > 0 {
+		s.ttlWorker.Track(orderID, req.Account, req.Symbol, req.Side, time.Duration(req.TTLSeconds)*time.Second)
+	}
+
+	return resp, nil
+}
+
+// CircuitStatuses returns the exchange circuit breaker states
+func (s *OrderService) CircuitStatuses() []exchange.CircuitStatus {
+	binance, err := s.accounts.Binance("")
+	if err != nil {
+		return []exchange.CircuitStatus{} // Breakers guard Binance calls only
+	}
+	return binance.CircuitStatuses()
+}
+
+// RateLimitStatuses returns the Binance rate limit usage of every Binance account, master first
+func (s *OrderService) RateLimitStatuses() []exchange.RateLimitStatus {
+	statuses := []exchange.RateLimitStatus{}
+	for _, account := range append([]string{""}, s.accounts.Names()...) {
+		binance, err := s.accounts.Binance(account)
+		if err != nil {
+			continue // Other venues have limits of their own
+		}
+		status := binance.RateLimitStatus()
+		status.Account = account
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// APIKeyStatuses returns the health of an account's configured API keys
+func (s *OrderService) APIKeyStatuses(account string) ([]exchange.KeyStatus, error) {
+	binance, err := s.accounts.Binance(account)
+	if err != nil {
+		return nil, err
+	}
+	return binance.APIKeyStatuses(), nil
+}
+
+// GetFreeBalances returns a fresh snapshot of an account's free spot balances
+func (s *OrderService) GetFreeBalances(account string) (map[string]decimal.Decimal, error) {
+	venue, err := s.accounts.Get(account)
+	if err != nil {
+		return nil, err
+	}
+	return venue.GetBalances()
+}
+
+// GetSymbolAssets names the base and quote asset and the quantity step and price tick of a symbol traded on an account
+func (s *OrderService) GetSymbolAssets(account, symbol string) (*contracts.SymbolAssets, error) {
+	venue, err := s.accounts.Get(account)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := venue.GetSymbolRules(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &contracts.SymbolAssets{Symbol: symbol, BaseAsset: info.BaseAsset, QuoteAsset: info.QuoteAsset, StepSize: info.StepSize, TickSize: info.TickSize}, nil
+}
+
+// GetPositions returns the open positions of a futures account
+func (s *OrderService) GetPositions(account string) ([]contracts.FuturesPosition, error) {
+	binance, err := s.accounts.Binance(account)
+	if err != nil {
+		return nil, err
+	}
+	return binance.GetPositions()
+}
+
+// GetFundingFees returns the funding payments of a futures account since the given time
+func (s *OrderService) GetFundingFees(account, symbol string, since time.Time) ([]contracts.FundingFee, error) {
+	binance, err := s.accounts.Binance(account)
+	if err != nil {
+		return nil, err
+	}
+	return binance.GetFundingFees(symbol, since)
 }
 
-// GetOrderStatus retrieves current order status from Binance
-func (s *OrderService) GetOrderStatus(symbol, orderID string) (*models.OrderStatus, error) {
-	return s.fetchOrderStatus(symbol, orderID)
+// GetMarginDebts returns the borrowed assets of a margin account
+func (s *OrderService) GetMarginDebts(account string) ([]contracts.MarginDebt, error) {
+	binance, err := s.accounts.Binance(account)
+	if err != nil {
+		return nil, err
+	}
+	return binance.GetMarginDebts()
 }
 
-func (s *OrderService) fetchOrderStatus(symbol, orderID string) (*models.OrderStatus, error) {
-	binanceOrder, err := s.binance.GetOrder(symbol, orderID)
+// GetMarginInterest returns the interest charged to a margin account since the given time
+func (s *OrderService) GetMarginInterest(account string, since time.Time) ([]contracts.MarginInterest, error) {
+	binance, err := s.accounts.Binance(account)
 	if err != nil {
-		log.Printf("ERROR: Failed to fetch order status for %s: %v", orderID, err)
+		return nil, err
+	}
+	return binance.GetMarginInterest(since)
+}
+
+// RotateAPIKey switches an account to its next API key
+func (s *OrderService) RotateAPIKey(account string) (exchange.KeyStatus, error) {
+	binance, err := s.accounts.Binance(account)
+	if err != nil {
+		return exchange.KeyStatus{}, err
+	}
+	return binance.RotateAPIKey()
+}
+
+// GetOutboxEntries lists persisted notifications by status (DEAD = dead-letter set)
+func (s *OrderService) GetOutboxEntries(status models.OutboxStatus) ([]*models.OutboxEntry, error) {
+	return s.outbox.GetByStatus(status)
+}
+
+// RequeueNotification moves a dead-letter notification back for immediate redelivery
+func (s *OrderService) RequeueNotification(id int) (bool, error) {
+	return s.outbox.Requeue(id)
+}
+
+// GetOrderStatus retrieves current order status from the exchange
+func (s *OrderService) GetOrderStatus(account, symbol, orderID string) (*models.OrderStatus, error) {
+	return s.fetchOrderStatus(account, symbol, orderID)
+}
+
+// ListOrders returns a page of the local order store and the total number of matches
+func (s *OrderService) ListOrders(filter models.OrderFilter) ([]*models.PlacedOrder, int, error) {
+	return s.orders.List(filter)
+}
+
+// TradeJournal returns journaled executions for export
+func (s *OrderService) TradeJournal(filter models.JournalFilter) ([]*models.JournalEntry, error) {
+	return s.journal.List(filter)
+}
+
+// GetOrderStatusByID resolves the symbol (and account, if not given) from the local
+// order store, for callers that only know the order ID
+func (s *OrderService) GetOrderStatusByID(account, orderID string) (*models.OrderStatus, error) {
+	stored, err := s.orders.GetByOrderID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up order %s: %w", orderID, err)
+	}
+
+	var matches []*models.PlacedOrder
+	for _, order := range stored {
+		if account == "" || order.Account == account {
+			matches = append(matches, order)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		slog.Warn("Order not found in local order store", logging.KeyOrderID, orderID)
+		return nil, nil
+	case 1:
+		return s.fetchOrderStatus(matches[0].Account, matches[0].Symbol, orderID)
+	default:
+		return nil, ErrAmbiguousOrder
+	}
+}
+
+func (s *OrderService) fetchOrderStatus(account, symbol, orderID string) (*models.OrderStatus, error) {
+	venue, err := s.accounts.Get(account)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := orderLogger(account, symbol, orderID)
+	binanceOrder, err := venue.GetOrder(symbol, orderID)
+	if err != nil {
+		logger.Error("Failed to fetch order status", logging.Err(err))
 		return nil, err
 	}
 
 	if binanceOrder == nil {
-		log.Printf("WARNING: Order %s not found on Binance", orderID)
+		logger.Warn("Order not found on Binance")
 		return nil, nil
 	}
 
+	return s.orderStatus(venue, account, binanceOrder), nil
+}
+
+// orderStatus converts a Binance order, recording commission and notifying grid-trading if it filled
+func (s *OrderService) orderStatus(venue exchange.Exchange, account string, binanceOrder *models.BinanceOrder) *models.OrderStatus {
+	orderID := strconv.FormatInt(binanceOrder.OrderID, 10)
 	status := exchange.ConvertBinanceStatus(binanceOrder.Status)
+	logger := orderLogger(account, binanceOrder.Symbol, orderID)
+
+	if err := s.orders.UpdateStatus(account, binanceOrder.Symbol, orderID, status); err != nil {
+		logger.Error("Failed to update stored order status", logging.Err(err))
+	}
 	result := &models.OrderStatus{
 		OrderID: orderID,
 		Status:  status,
 	}
 
-	// Add fill details if filled
-	if status == "filled" {
-		executedQty, _ := decimal.NewFromString(binanceOrder.ExecutedQty)
+	// Add fill details if filled, or the part that filled before a cancel - grid-trading keeps it
+	executedQty, _ := decimal.NewFromString(binanceOrder.ExecutedQty)
+	if status == "filled" || (status == "cancelled" && executedQty.IsPositive()) {
 		cummulativeQuoteQty, _ := decimal.NewFromString(binanceOrder.CummulativeQuoteQty)
+		if binanceOrder.CumQuote != "" {
+			cummulativeQuoteQty, _ = decimal.NewFromString(binanceOrder.CumQuote)
+		}
 
 		// Calculate average fill price
 		fillPrice := decimal.Zero
@@ -88,34 +410,138 @@ func (s *OrderService) fetchOrderStatus(symbol, orderID string) (*models.OrderSt
 		result.FilledAmount = &executedQty
 		result.FillPrice = &fillPrice
 
-		log.Printf("INFO: Order %s filled - Executed: %s @ %s (Quote: %s)",
-			orderID, executedQty, fillPrice, cummulativeQuoteQty)
+		commission, commissionAsset := s.recordCommission(venue, account, binanceOrder)
+		if commissionAsset != "" {
+			result.Commission = &commission
+			result.CommissionAsset = commissionAsset
+		}
+
+		// Lets grid-trading tell a commission charged in the bought coin from one in USDT or BNB
+		baseAsset, _, _, err := exchange.SymbolAssets(venue, binanceOrder.Symbol)
+		if err != nil {
+			logger.Warn("Failed to get base asset", logging.Err(err))
+		}
+		result.BaseAsset = baseAsset
+
+		logger = logger.With("executed", executedQty, "price", fillPrice, "commission", commission, "commission_asset", commissionAsset)
+		if status == "cancelled" {
+			logger.Info("Order cancelled after filling")
+			return result
+		}
+
+		logger.Info("Order filled", "quote", cummulativeQuoteQty)
 
 		// Send fill notification
-		s.sendFillNotification(binanceOrder, executedQty, fillPrice)
+		s.sendFillNotification(account, binanceOrder, executedQty, fillPrice, commission, commissionAsset, baseAsset)
 	}
 
-	return result, nil
+	return result
 }
 
 
-func (s *OrderService) sendFillNotification(order *models.BinanceOrder, filledAmount, fillPrice decimal.Decimal) {
+func (s *OrderService) sendFillNotification(account string, order *models.BinanceOrder, filledAmount, fillPrice, commission decimal.Decimal, commissionAsset, baseAsset string) {
 	notification := models.FillNotification{
-		OrderID:      strconv.FormatInt(order.OrderID, 10),
-		Symbol:       s.stripUSDT(order.Symbol),
-		Price:        fillPrice,
-		Side:         order.Side,
-		Status:       "filled",
-		FilledAmount: filledAmount,
-		FillPrice:    fillPrice,
+		OrderID:         strconv.FormatInt(order.OrderID, 10),
+		Symbol:          s.stripUSDT(order.Symbol),
+		Price:           fillPrice,
+		Side:            order.Side,
+		Status:          "filled",
+		FilledAmount:    filledAmount,
+		FillPrice:       fillPrice,
+		Account:         account,
+		Commission:      commission,
+		CommissionAsset: commissionAsset,
+		BaseAsset:       baseAsset,
 	}
 
+	logger := orderLogger(account, order.Symbol, notification.OrderID).With("side", order.Side)
 	if err := s.gridClient.SendFillNotification(notification); err != nil {
-		log.Printf("ERROR: Failed to send fill notification for order %d: %v", order.OrderID, err)
+		logger.Error("Failed to send fill notification", logging.Err(err))
 	} else {
-		log.Printf("INFO: Sent fill notification - Order: %d, Symbol: %s, Side: %s, Amount: %s @ %s",
-			order.OrderID, notification.Symbol, order.Side, filledAmount, fillPrice)
+		logger.Info("Sent fill notification", "amount", filledAmount, "price", fillPrice)
+	}
+}
+
+// sendCancelNotification reports an order cancelled by order-assurance (TTL expiry) to grid-trading,
+// with the part that filled before the cancel so the level keeps what it bought or sold
+func (s *OrderService) sendCancelNotification(venue exchange.Exchange, account, symbol string, side models.OrderSide, order *models.BinanceOrder) {
+	orderID := strconv.FormatInt(order.OrderID, 10)
+	notification := models.FillNotification{
+		OrderID: orderID,
+		Symbol:  symbol,
+		Side:    string(side),
+		Status:  "cancelled",
+		Account: account,
+	}
+
+	executedQty, _ := decimal.NewFromString(order.ExecutedQty)
+	if executedQty.IsPositive() {
+		quoteQty, _ := decimal.NewFromString(order.CummulativeQuoteQty)
+		if order.CumQuote != "" {
+			quoteQty, _ = decimal.NewFromString(order.CumQuote)
+		}
+		notification.FilledAmount = executedQty
+		notification.FillPrice = quoteQty.Div(executedQty)
+		notification.Price = notification.FillPrice
+
+		notification.Commission, notification.CommissionAsset = s.recordCommission(venue, account, order)
+		baseAsset, _, _, err := exchange.SymbolAssets(venue, order.Symbol)
+		if err != nil {
+			orderLogger(account, order.Symbol, orderID).Warn("Failed to get base asset", logging.Err(err))
+		}
+		notification.BaseAsset = baseAsset
+	}
+
+	logger := orderLogger(account, order.Symbol, orderID).With("side", notification.Side)
+	if err := s.gridClient.SendFillNotification(notification); err != nil {
+		logger.Error("Failed to send cancel notification", logging.Err(err))
+	} else {
+		logger.Info("Sent cancel notification", "filled", executedQty)
+	}
+}
+
+// sendRejectionNotification reports a terminal order rejection to grid-trading
+func (s *OrderService) sendRejectionNotification(req models.OrderRequest, orderErr *exchange.OrderError) {
+	notification := models.ErrorNotification{
+		Symbol:    req.Symbol,
+		Side:      string(req.Side),
+		Price:     req.Price,
+		ErrorCode: string(orderErr.Code),
+		Error:     orderErr.Message,
+		Account:   req.Account,
+	}
+
+	logger := orderLogger(req.Account, req.Symbol, "").With("side", req.Side, "price", req.Price)
+	if err := s.gridClient.SendErrorNotification(notification); err != nil {
+		logger.Error("Failed to send rejection notification", logging.Err(err))
+	} else {
+		logger.Info("Sent rejection notification", "code", orderErr.Code)
+	}
+}
+
+// queueKey serializes operations per account and symbol - sub-accounts don't contend with each other
+func queueKey(account, symbol string) string {
+	if account == "" {
+		return symbol
+	}
+	return account + ":" + symbol
+}
+
+// orderLogger tags log lines with an order's account, symbol and ID (left out while unknown)
+func orderLogger(account, symbol, orderID string) *slog.Logger {
+	logger := slog.With(logging.KeyAccount, accountName(account), logging.KeySymbol, symbol)
+	if orderID != "" {
+		logger = logger.With(logging.KeyOrderID, orderID)
+	}
+	return logger
+}
+
+// accountName labels the master account in logs
+func accountName(account string) string {
+	if account == "" {
+		return "master"
 	}
+	return account
 }
 
 func (s *OrderService) stripUSDT(symbol string) string {
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"strconv"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+const (
+	// allOrdersPageSize is the Binance maximum for /api/v3/allOrders
+	allOrdersPageSize = 1000
+	// maxAllOrdersPages bounds how far one symbol is paged before falling back to single lookups
+	maxAllOrdersPages = 5
+)
+
+type batchGroup struct {
+	account string
+	symbol  string
+}
+
+// GetOrderStatuses resolves many orders with a few Binance calls per (account, symbol):
+// one openOrders call, then allOrders pages starting at the oldest unresolved order ID.
+// Orders still unresolved after that, and every order on venues other than Binance, fall back
+// to a single-order lookup.
+func (s *OrderService) GetOrderStatuses(queries []models.OrderStatusQuery) []models.BatchOrderStatus {
+	results := make([]models.BatchOrderStatus, len(queries))
+	groups := make(map[batchGroup][]int)
+	var order []batchGroup
+
+	for i, q := range queries {
+		results[i] = models.BatchOrderStatus{
+			Symbol:      q.Symbol,
+			Account:     q.Account,
+			OrderStatus: models.OrderStatus{OrderID: q.OrderID},
+		}
+
+		key := batchGroup{account: q.Account, symbol: q.Symbol}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	for _, key := range order {
+		s.resolveBatchGroup(key, queries, groups[key], results)
+	}
+
+	return results
+}
+
+func (s *OrderService) resolveBatchGroup(key batchGroup, queries []models.OrderStatusQuery, indexes []int, results []models.BatchOrderStatus) {
+	fail := func(indexes []int, err error) {
+		for _, i := range indexes {
+			results[i].Status = models.StatusError
+			results[i].Error = err.Error()
+		}
+	}
+
+	venue, err := s.accounts.Get(key.account)
+	if err != nil {
+		fail(indexes, err)
+		return
+	}
+
+	// Order IDs wanted in this group (duplicates in the request share one lookup)
+	wanted := make(map[int64][]int)
+	for _, i := range indexes {
+		id, err := strconv.ParseInt(queries[i].OrderID, 10, 64)
+		if err != nil {
+			results[i].Status = models.StatusError
+			results[i].Error = "invalid order ID"
+			continue
+		}
+		wanted[id] = append(wanted[id], i)
+	}
+
+	resolve := func(binanceOrder *models.BinanceOrder) {
+		slots, ok := wanted[binanceOrder.OrderID]
+		if !ok {
+			return
+		}
+		status := s.orderStatus(venue, key.account, binanceOrder)
+		for _, i := range slots {
+			results[i].OrderStatus = *status
+		}
+		delete(wanted, binanceOrder.OrderID)
+	}
+
+	logger := orderLogger(key.account, key.symbol, "")
+	calls := 0
+	if binance, ok := venue.(*exchange.BinanceClient); ok {
+		calls++
+		openOrders, err := binance.GetOpenOrders(key.symbol)
+		if err != nil {
+			logger.Error("Batch status - failed to get open orders", logging.Err(err))
+			for _, slots := range wanted {
+				fail(slots, err)
+			}
+			return
+		}
+		for _, binanceOrder := range openOrders {
+			resolve(binanceOrder)
+		}
+
+		// Page through allOrders from the oldest unresolved ID for filled/cancelled orders
+		for page := 0; page < maxAllOrdersPages && len(wanted) > 0; page++ {
+			orders, err := binance.GetAllOrders(key.symbol, minOrderID(wanted), allOrdersPageSize)
+			calls++
+			if err != nil {
+				logger.Error("Batch status - failed to get all orders", logging.Err(err))
+				break
+			}
+			for _, binanceOrder := range orders {
+				resolve(binanceOrder)
+			}
+			if len(orders) < allOrdersPageSize {
+				// Reached the newest order - anything left does not exist on this symbol
+				for _, slots := range wanted {
+					for _, i := range slots {
+						results[i].Status = models.StatusNotFound
+					}
+				}
+				wanted = nil
+			}
+		}
+	}
+
+	// Too far apart to page through (or paging failed, or the venue lists no orders) - look the rest up one by one
+	for id, slots := range wanted {
+		orderID := strconv.FormatInt(id, 10)
+		status, err := s.fetchOrderStatus(key.account, key.symbol, orderID)
+		calls++
+		switch {
+		case err != nil:
+			fail(slots, err)
+		case status == nil:
+			for _, i := range slots {
+				results[i].Status = models.StatusNotFound
+			}
+		default:
+			for _, i := range slots {
+				results[i].OrderStatus = *status
+			}
+		}
+	}
+
+	logger.Info("Batch status resolved", "orders", len(indexes), "exchange_calls", calls)
+}
+
+func minOrderID(wanted map[int64][]int) int64 {
+	var lowest int64
+	for id := range wanted {
+		if lowest == 0 || id < lowest {
+			lowest = id
+		}
+	}
+	return lowest
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/client"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/grid-trading-bot/services/order-assurance/internal/repository"
+)
+
+const (
+	outboxBatchSize   = 50
+	outboxMaxAttempts = 20
+	outboxMaxBackoff  = 1 * time.Hour
+
+	notificationLogPruneEvery = 1 * time.Hour
+)
+
+// OutboxWorker redelivers persisted notifications with exponential backoff,
+// moving them to the dead-letter set after outboxMaxAttempts failures
+type OutboxWorker struct {
+	outbox     *repository.OutboxRepository
+	gridClient *client.Notifier
+	interval   time.Duration
+
+	// Logged notifications older than retention are pruned hourly (nil log or 0 = kept)
+	notificationLog *repository.NotificationLogRepository
+	retention       time.Duration
+	prunedAt        time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewOutboxWorker(outbox *repository.OutboxRepository, gridClient *client.Notifier, interval time.Duration) *OutboxWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutboxWorker{
+		outbox:     outbox,
+		gridClient: gridClient,
+		interval:   interval,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// PruneNotificationLog drops logged notifications once they are older than retention - by then
+// grid-trading has either replayed them or the hourly sync has caught up
+func (w *OutboxWorker) PruneNotificationLog(log *repository.NotificationLogRepository, retention time.Duration) {
+	w.notificationLog = log
+	w.retention = retention
+}
+
+func (w *OutboxWorker) Start() {
+	log.Printf("Starting notification outbox worker with interval: %s", w.interval)
+	w.wg.Add(1)
+	go w.loop()
+}
+
+func (w *OutboxWorker) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *OutboxWorker) loop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.redeliverDue()
+			w.pruneNotificationLog()
+		}
+	}
+}
+
+func (w *OutboxWorker) redeliverDue() {
+	entries, err := w.outbox.GetDue(outboxBatchSize)
+	if err != nil {
+		slog.Error("Failed to load due outbox entries", logging.Err(err))
+		return
+	}
+
+	for _, entry := range entries {
+		w.redeliver(entry)
+	}
+}
+
+func (w *OutboxWorker) redeliver(entry *models.OutboxEntry) {
+	logger := slog.With("notification_id", entry.ID, "kind", entry.Kind, logging.KeyOrderID, entry.OrderID)
+	if err := w.gridClient.Redeliver(entry); err != nil {
+		backoff := w.interval * time.Duration(1<<uint(min(entry.Attempts, 10)))
+		if backoff > outboxMaxBackoff {
+			backoff = outboxMaxBackoff
+		}
+
+		if err := w.outbox.MarkFailed(entry.ID, err.Error(), backoff, outboxMaxAttempts); err != nil {
+			logger.Error("Failed to update outbox entry", logging.Err(err))
+		}
+
+		if entry.Attempts+1 >= outboxMaxAttempts {
+			logger.Error("Notification moved to dead-letter", "attempts", entry.Attempts+1, logging.Err(err))
+		} else {
+			logger.Warn("Redelivery of notification failed", "retry_after", backoff, logging.Err(err))
+		}
+		return
+	}
+
+	if err := w.outbox.MarkDelivered(entry.ID); err != nil {
+		logger.Error("Failed to mark outbox entry delivered", logging.Err(err))
+		return
+	}
+
+	logger.Info("Redelivered notification")
+}
+
+func (w *OutboxWorker) pruneNotificationLog() {
+	if w.notificationLog == nil || w.retention <= 0 || time.Since(w.prunedAt) < notificationLogPruneEvery {
+		return
+	}
+	w.prunedAt = time.Now()
+
+	deleted, err := w.notificationLog.DeleteBefore(time.Now().Add(-w.retention))
+	if err != nil {
+		slog.Error("Failed to prune notification log", logging.Err(err))
+		return
+	}
+	if deleted > 0 {
+		slog.Info("Pruned logged notifications", "deleted", deleted, "retention", w.retention)
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"log/slog"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/grid-trading-bot/services/order-assurance/internal/repository"
+)
+
+// UsePlacementAudit records every order submission of every account - parameters as sent
+// and the exchange's reply - so a disputed placement can be replayed from the audit
+func (s *OrderService) UsePlacementAudit(audit *repository.PlacementAuditRepository) {
+	s.placementAudit = audit
+
+	accounts := append([]string{""}, s.accounts.Names()...)
+	for _, account := range accounts {
+		binance, err := s.accounts.Binance(account)
+		if err != nil {
+			continue // Placements on other venues are not audited
+		}
+
+		account := account
+		binance.UsePlacementAudit(func(attempt exchange.PlacementAttempt) {
+			if err := audit.Append(placementAuditRecord(account, attempt)); err != nil {
+				slog.Error("Failed to audit placement", logging.KeyAccount, accountName(account), logging.Err(err))
+			}
+		})
+	}
+}
+
+// PlacementAudit returns audited order submissions, newest first
+func (s *OrderService) PlacementAudit(filter models.PlacementAuditFilter) ([]*models.PlacementAudit, error) {
+	if s.placementAudit == nil {
+		return nil, nil
+	}
+	return s.placementAudit.List(filter)
+}
+
+func placementAuditRecord(account string, attempt exchange.PlacementAttempt) *models.PlacementAudit {
+	request := make(map[string]string, len(attempt.Params))
+	for key := range attempt.Params {
+		request[key] = attempt.Params.Get(key)
+	}
+
+	record := &models.PlacementAudit{
+		Account:       account,
+		Symbol:        attempt.Params.Get("symbol"),
+		Side:          attempt.Params.Get("side"),
+		ClientOrderID: attempt.Params.Get("newClientOrderId"),
+		OrderID:       attempt.OrderID,
+		Transport:     attempt.Transport,
+		Request:       request,
+		StatusCode:    attempt.StatusCode,
+		Response:      attempt.Reply,
+		DurationMs:    attempt.Duration.Milliseconds(),
+	}
+	if attempt.Err != nil {
+		record.Error = attempt.Err.Error()
+	}
+	return record
+}
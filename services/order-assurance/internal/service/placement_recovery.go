@@ -0,0 +1,129 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Placements that still can't be checked after this long are dropped for manual review
+const pendingPlacementMaxAge = 24 * time.Hour
+
+// newClientOrderID returns a unique newClientOrderId (Binance allows up to 36 characters)
+func newClientOrderID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "oa-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return "oa-" + hex.EncodeToString(b)
+}
+
+// placementMayHaveLanded reports whether a failed placement could still have created an order:
+// rejections decided locally or answered by Binance did not, timeouts and 5xx may have
+func placementMayHaveLanded(err error) bool {
+	var orderErr *exchange.OrderError
+	if errors.As(err, &orderErr) {
+		return orderErr.Code == exchange.ErrExchangeFailure
+	}
+	return true
+}
+
+// RecoverPendingPlacements checks placements left unresolved by a crash or lost response.
+// Orders that reached Binance are recorded and reported to grid-trading, which never got
+// their order ID; orders that never landed are just cleared. Run before serving requests.
+func (s *OrderService) RecoverPendingPlacements() {
+	pending, err := s.placements.List()
+	if err != nil {
+		slog.Error("Placement recovery skipped", logging.Err(err))
+		return
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	slog.Info("Checking pending placements from before restart", "placements", len(pending))
+
+	for _, p := range pending {
+		if s.recoverPlacement(p) {
+			if err := s.placements.Delete(p.ClientOrderID); err != nil {
+				slog.Error("Failed to delete pending placement", "client_order_id", p.ClientOrderID, logging.Err(err))
+			}
+		}
+	}
+}
+
+// recoverPlacement resolves one placement, returning false if it should be retried next startup
+func (s *OrderService) recoverPlacement(p *models.PendingPlacement) bool {
+	logger := orderLogger(p.Account, p.Symbol, "").With("client_order_id", p.ClientOrderID, "side", p.Side, "price", p.Price)
+
+	// Only Binance finds orders by client order ID
+	binance, err := s.accounts.Binance(p.Account)
+	if err != nil {
+		logger.Error("Pending placement dropped", logging.Err(err))
+		return true
+	}
+
+	var order *models.BinanceOrder
+	if queueErr := s.queue.Do(queueKey(p.Account, p.Symbol), func() {
+		order, err = binance.GetOrderByClientID(p.Symbol, p.ClientOrderID)
+	}); queueErr != nil {
+		err = queueErr
+	}
+	if err != nil {
+		if time.Since(p.CreatedAt) > pendingPlacementMaxAge {
+			logger.Error("Pending placement dropped - check the exchange manually", "max_age", pendingPlacementMaxAge, logging.Err(err))
+			return true
+		}
+		logger.Error("Failed to check pending placement", logging.Err(err))
+		return false
+	}
+
+	if order == nil {
+		logger.Info("Pending placement never reached the exchange")
+		return true
+	}
+
+	orderID := strconv.FormatInt(order.OrderID, 10)
+	logger = logger.With(logging.KeyOrderID, orderID)
+	logger.Warn("Recovered order placed before restart, response may not have reached grid-trading", "status", order.Status)
+
+	quantity := p.Quantity
+	if qty, err := decimal.NewFromString(order.OrigQty); err == nil {
+		quantity = qty
+	}
+	if err := s.orders.Save(p.Account, p.Symbol, orderID, p.Side, p.Price, quantity); err != nil {
+		logger.Error("Failed to record order in local store", logging.Err(err))
+	}
+
+	// Nothing for grid-trading to track if the order is already gone
+	if exchange.ConvertBinanceStatus(order.Status) == "cancelled" {
+		s.orderStatus(binance, p.Account, order)
+		return true
+	}
+
+	notification := models.FillNotification{
+		OrderID: orderID,
+		Symbol:  p.Symbol,
+		Price:   p.Price,
+		Side:    string(p.Side),
+		Status:  models.StatusPlaced,
+		Account: p.Account,
+	}
+	if err := s.gridClient.SendFillNotification(notification); err != nil {
+		logger.Error("Failed to send placed notification", logging.Err(err))
+	}
+
+	// Records the status and reports a fill, now that grid-trading can match the order ID
+	s.orderStatus(binance, p.Account, order)
+
+	return true
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+)
+
+// EnableWithdrawals allows profit sweeps to the single whitelisted address.
+// Without it only sweeps into Earn are accepted.
+func (s *OrderService) EnableWithdrawals(address, network string) {
+	s.withdrawAddress = address
+	s.withdrawNetwork = network
+	log.Printf("INFO: Profit sweep withdrawals enabled to %s (network: %s)", address, network)
+}
+
+// SweepProfit moves realized profit from spot into Earn or to the whitelisted address
+func (s *OrderService) SweepProfit(req models.SweepRequest) (*models.SweepResult, error) {
+	asset := strings.ToUpper(req.Asset)
+	result := &models.SweepResult{
+		Account:     req.Account,
+		Asset:       asset,
+		Amount:      req.Amount,
+		Destination: req.Destination,
+		DryRun:      req.DryRun,
+	}
+
+	switch req.Destination {
+	case models.SweepToEarn:
+	case models.SweepToWithdrawal:
+		if s.withdrawAddress == "" {
+			return nil, &exchange.OrderError{Code: exchange.ErrSweepRejected, Message: "withdrawals are disabled - no whitelisted address configured"}
+		}
+		result.Address = s.withdrawAddress
+	default:
+		return nil, &exchange.OrderError{Code: exchange.ErrSweepRejected, Message: fmt.Sprintf("unknown destination %q", req.Destination)}
+	}
+
+	binance, err := s.accounts.Binance(req.Account)
+	if err != nil {
+		return nil, err
+	}
+	if !binance.IsSpot() {
+		return nil, &exchange.OrderError{Code: exchange.ErrSweepRejected, Message: "sweeps move spot balances - not supported on the " + req.Account + " account"}
+	}
+
+	balances, err := binance.GetFreeBalances()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s balance: %w", asset, err)
+	}
+	result.FreeBalance = balances[asset]
+
+	// Profit still tied up in open buy orders isn't free to move
+	if result.FreeBalance.LessThan(req.Amount) {
+		return nil, &exchange.OrderError{
+			Code:    exchange.ErrInsufficientFunds,
+			Message: fmt.Sprintf("sweep of %s %s exceeds free balance %s", req.Amount, asset, result.FreeBalance),
+			Details: map[string]string{
+				"asset":     asset,
+				"required":  req.Amount.String(),
+				"available": result.FreeBalance.String(),
+			},
+		}
+	}
+
+	if req.DryRun {
+		log.Printf("INFO: [DRY RUN] Would sweep %s %s to %s (account %s, free %s)",
+			req.Amount, asset, req.Destination, accountName(req.Account), result.FreeBalance)
+		return result, nil
+	}
+
+	if req.Destination == models.SweepToEarn {
+		result.Reference, err = binance.SubscribeFlexibleEarn(asset, req.Amount)
+	} else {
+		withdrawOrderID := fmt.Sprintf("sweep-%d", time.Now().UnixMilli())
+		result.Reference, err = binance.Withdraw(asset, s.withdrawAddress, s.withdrawNetwork, req.Amount, withdrawOrderID)
+	}
+	if err != nil {
+		log.Printf("ERROR: Profit sweep of %s %s to %s failed (account %s): %v", req.Amount, asset, req.Destination, accountName(req.Account), err)
+		return nil, fmt.Errorf("profit sweep failed: %w", err)
+	}
+
+	log.Printf("SUCCESS: Swept %s %s to %s (account %s, reference %s)",
+		req.Amount, asset, req.Destination, accountName(req.Account), result.Reference)
+	return result, nil
+}
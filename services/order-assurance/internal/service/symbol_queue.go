@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+type queueJob struct {
+	run  func()
+	done chan struct{}
+}
+
+// symbolJobs is one symbol's job channel. senders counts Do calls between
+// enqueue decision and send, so Stop only closes the channel once they're done.
+type symbolJobs struct {
+	jobs    chan queueJob
+	senders sync.WaitGroup
+}
+
+// SymbolQueue serializes order operations per symbol in FIFO order.
+// Each symbol gets its own worker, so different symbols still run in parallel.
+type SymbolQueue struct {
+	bufferSize int
+
+	mu      sync.Mutex
+	queues  map[string]*symbolJobs
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+func NewSymbolQueue(bufferSize int) *SymbolQueue {
+	return &SymbolQueue{
+		bufferSize: bufferSize,
+		queues:     make(map[string]*symbolJobs),
+	}
+}
+
+// Do runs fn on the symbol's worker and blocks until it has completed
+func (q *SymbolQueue) Do(symbol string, fn func()) error {
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return fmt.Errorf("order queue stopped - rejecting operation for %s", symbol)
+	}
+
+	queue, ok := q.queues[symbol]
+	if !ok {
+		queue = &symbolJobs{jobs: make(chan queueJob, q.bufferSize)}
+		q.queues[symbol] = queue
+		q.wg.Add(1)
+		go q.worker(symbol, queue.jobs)
+		slog.Info("Started order queue worker", "queue", symbol)
+	}
+	queue.senders.Add(1)
+	q.mu.Unlock()
+
+	// Send outside the lock - a full buffer only blocks callers of this symbol
+	job := queueJob{run: fn, done: make(chan struct{})}
+	queue.jobs <- job
+	queue.senders.Done()
+
+	<-job.done
+	return nil
+}
+
+// Stop drains queued operations and stops all workers
+func (q *SymbolQueue) Stop() {
+	q.mu.Lock()
+	q.stopped = true
+	q.mu.Unlock()
+
+	// No new senders after stopped is set, so waiting for the in-flight ones makes close safe
+	for _, queue := range q.queues {
+		queue.senders.Wait()
+		close(queue.jobs)
+	}
+
+	q.wg.Wait()
+}
+
+func (q *SymbolQueue) worker(symbol string, queue chan queueJob) {
+	defer q.wg.Done()
+
+	for job := range queue {
+		job.run()
+		close(job.done)
+	}
+
+	slog.Info("Stopped order queue worker", "queue", symbol)
+}
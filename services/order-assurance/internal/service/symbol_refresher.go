@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+)
+
+// SymbolInfoRefresher periodically refreshes cached exchange trading rules
+// so filter changes don't surface as order rejections
+type SymbolInfoRefresher struct {
+	accounts *exchange.Accounts
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewSymbolInfoRefresher(accounts *exchange.Accounts, interval time.Duration) *SymbolInfoRefresher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SymbolInfoRefresher{
+		accounts: accounts,
+		interval: interval,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+func (r *SymbolInfoRefresher) Start() {
+	log.Printf("Starting symbol info refresher with interval: %s", r.interval)
+	r.wg.Add(1)
+	go r.loop()
+}
+
+func (r *SymbolInfoRefresher) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *SymbolInfoRefresher) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			// Every account client keeps its own rules cache
+			for _, binance := range r.accounts.All() {
+				if err := binance.RefreshSymbolInfo(); err != nil {
+					slog.Error("Failed to refresh symbol info", logging.Err(err))
+				}
+			}
+		}
+	}
+}
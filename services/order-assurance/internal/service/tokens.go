@@ -0,0 +1,90 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/grid-trading-bot/services/order-assurance/internal/repository"
+)
+
+const (
+	tokenPrefix     = "gtb_"
+	tokenPrefixShow = len(tokenPrefix) + 8 // Characters kept for listings
+)
+
+// TokenService manages API tokens. Tokens are random, returned once on creation and
+// stored only as a SHA-256 hash - a leaked database doesn't leak usable credentials.
+type TokenService struct {
+	repo *repository.TokenRepository
+}
+
+func NewTokenService(repo *repository.TokenRepository) *TokenService {
+	return &TokenService{repo: repo}
+}
+
+// Create issues a token with validated scopes. ttl 0 means it never expires.
+func (s *TokenService) Create(name string, scopes []string, ttl time.Duration) (string, *models.APIToken, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := tokenPrefix + hex.EncodeToString(secret)
+
+	var expiresAt *time.Time
+	if ttl > 0 {
+		t := time.Now().UTC().Add(ttl).Truncate(time.Second)
+		expiresAt = &t
+	}
+
+	created, err := s.repo.Create(name, hashToken(token), token[:tokenPrefixShow], scopes, expiresAt)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to store token: %w", err)
+	}
+
+	log.Printf("INFO: Created API token %d (%s) for %q with scopes %v", created.ID, created.Prefix, created.Name, created.Scopes)
+	return token, created, nil
+}
+
+func (s *TokenService) List() ([]*models.APIToken, error) {
+	return s.repo.List()
+}
+
+// Revoke disables a token immediately. Returns false if it doesn't exist or was already revoked.
+func (s *TokenService) Revoke(id int) (bool, error) {
+	revoked, err := s.repo.Revoke(id)
+	if revoked {
+		log.Printf("INFO: Revoked API token %d", id)
+	}
+	return revoked, err
+}
+
+// Verify returns the active token matching token, or nil if it is unknown, revoked or expired
+func (s *TokenService) Verify(token string) (*models.APIToken, error) {
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return nil, nil
+	}
+
+	found, err := s.repo.GetByHash(hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up token: %w", err)
+	}
+	if found == nil || !found.Active(time.Now()) {
+		return nil, nil
+	}
+
+	if err := s.repo.TouchLastUsed(found.ID); err != nil {
+		log.Printf("WARNING: Failed to record use of API token %d: %v", found.ID, err)
+	}
+	return found, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/grid-trading-bot/services/order-assurance/internal/repository"
+)
+
+// TradeCaptureWorker journals every execution from each account's user-data stream.
+// Trades executed while a stream is reconnecting are not captured; order_fills
+// (fetched via myTrades on fill) covers those for reconciliation.
+type TradeCaptureWorker struct {
+	accounts  *exchange.Accounts
+	journal   *repository.JournalRepository
+	streamURL string
+
+	// Pushes fills of its orders to grid-trading as they stream in (nil = journal only)
+	orderService *OrderService
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewTradeCaptureWorker(accounts *exchange.Accounts, journal *repository.JournalRepository, streamURL string) *TradeCaptureWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TradeCaptureWorker{
+		accounts:  accounts,
+		journal:   journal,
+		streamURL: streamURL,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// EnableFillPush also hands every execution to orderService, which notifies grid-trading of fills
+// without waiting for its sync. Call before Start.
+func (w *TradeCaptureWorker) EnableFillPush(orderService *OrderService) {
+	w.orderService = orderService
+}
+
+func (w *TradeCaptureWorker) Start() {
+	log.Printf("Starting trade capture from user-data streams at %s", w.streamURL)
+
+	for _, account := range append([]string{""}, w.accounts.Names()...) {
+		binance, err := w.accounts.Binance(account)
+		if err != nil || !binance.IsSpot() { // Spot user-data streams only
+			continue
+		}
+
+		account := account
+		stream := exchange.NewUserDataStream(binance, w.streamURL, func(report models.ExecutionReport) {
+			w.record(account, report)
+			if w.orderService != nil {
+				w.orderService.PushFill(account, report)
+			}
+		})
+
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			stream.Run(w.ctx)
+		}()
+	}
+}
+
+func (w *TradeCaptureWorker) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *TradeCaptureWorker) record(account string, report models.ExecutionReport) {
+	if report.ExecutionType != "TRADE" {
+		return
+	}
+
+	logger := orderLogger(account, report.Symbol, strconv.FormatInt(report.OrderID, 10)).With("trade_id", report.TradeID)
+	inserted, err := w.journal.Append(account, report)
+	if err != nil {
+		logger.Error("CRITICAL - Failed to journal trade", logging.Err(err))
+		return
+	}
+
+	if inserted {
+		logger.Info("Journaled trade", "side", report.Side, "quantity", report.LastQty, "price", report.LastPrice,
+			"commission", report.Commission, "commission_asset", report.CommissionAsset)
+	}
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/order-assurance/internal/exchange"
+	"github.com/grid-trading-bot/services/order-assurance/internal/models"
+	"github.com/grid-trading-bot/services/order-assurance/internal/repository"
+)
+
+type trackedOrder struct {
+	account   string
+	symbol    string
+	side      models.OrderSide
+	expiresAt time.Time
+}
+
+// TTLWorker cancels orders whose caller-supplied TTL has elapsed.
+// Expiry times are stored with the order, so open orders are tracked again after a restart.
+type TTLWorker struct {
+	accounts      *exchange.Accounts
+	orderService  *OrderService // Reports cancellations to grid-trading
+	queue         *SymbolQueue
+	store         *repository.OrderRepository
+	checkInterval time.Duration
+
+	orders map[string]trackedOrder
+	mu     sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewTTLWorker(accounts *exchange.Accounts, queue *SymbolQueue, store *repository.OrderRepository, checkInterval time.Duration) *TTLWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TTLWorker{
+		accounts:      accounts,
+		queue:         queue,
+		store:         store,
+		checkInterval: checkInterval,
+		orders:        make(map[string]trackedOrder),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// UseOrderService sets the service that notifies grid-trading of cancelled orders; call before Start
+func (w *TTLWorker) UseOrderService(orderService *OrderService) {
+	w.orderService = orderService
+}
+
+// Track registers an order for cancellation once ttl has elapsed
+func (w *TTLWorker) Track(orderID, account, symbol string, side models.OrderSide, ttl time.Duration) {
+	expiresAt := time.Now().Add(ttl)
+	logger := orderLogger(account, symbol, orderID)
+	if err := w.store.SetExpiry(account, symbol, orderID, expiresAt); err != nil {
+		logger.Error("Failed to store order TTL, order expires only if this process keeps running", logging.Err(err))
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.orders[orderID] = trackedOrder{
+		account:   account,
+		symbol:    symbol,
+		side:      side,
+		expiresAt: expiresAt,
+	}
+	logger.Info("Tracking order with TTL", "side", side, "ttl", ttl)
+}
+
+func (w *TTLWorker) Start() {
+	log.Printf("Starting order TTL worker with check interval: %s", w.checkInterval)
+	w.restore()
+	w.wg.Add(1)
+	go w.loop()
+}
+
+// restore tracks the open orders that were given a TTL before the last restart
+func (w *TTLWorker) restore() {
+	orders, err := w.store.GetExpiring()
+	if err != nil {
+		slog.Error("Failed to load orders with a TTL, they will not expire", logging.Err(err))
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, order := range orders {
+		w.orders[order.OrderID] = trackedOrder{
+			account:   order.Account,
+			symbol:    order.Symbol,
+			side:      order.Side,
+			expiresAt: *order.ExpiresAt,
+		}
+	}
+	if len(orders) > 0 {
+		slog.Info("Tracking open orders with a TTL from before the restart", "orders", len(orders))
+	}
+}
+
+func (w *TTLWorker) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *TTLWorker) loop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.cancelExpired()
+		}
+	}
+}
+
+func (w *TTLWorker) cancelExpired() {
+	now := time.Now()
+
+	w.mu.Lock()
+	expired := make(map[string]trackedOrder)
+	for orderID, order := range w.orders {
+		if now.After(order.expiresAt) {
+			expired[orderID] = order
+			delete(w.orders, orderID)
+		}
+	}
+	w.mu.Unlock()
+
+	for orderID, order := range expired {
+		w.cancelOrder(orderID, order)
+	}
+}
+
+func (w *TTLWorker) cancelOrder(orderID string, order trackedOrder) {
+	logger := orderLogger(order.account, order.symbol, orderID).With("side", order.side)
+	logger.Info("Order TTL expired, cancelling")
+
+	venue, err := w.accounts.Get(order.account)
+	if err != nil {
+		logger.Error("Cannot cancel expired order", logging.Err(err))
+		return
+	}
+
+	var cancelled *models.BinanceOrder
+	if queueErr := w.queue.Do(queueKey(order.account, order.symbol), func() {
+		cancelled, err = venue.CancelOrder(order.symbol, orderID)
+	}); queueErr != nil {
+		err = queueErr
+	}
+	if err != nil {
+		// Order is most likely already filled or cancelled - sync job will reconcile it
+		logger.Warn("Failed to cancel expired order", logging.Err(err))
+		return
+	}
+
+	if err := w.store.UpdateStatus(order.account, order.symbol, orderID, exchange.ConvertBinanceStatus(cancelled.Status)); err != nil {
+		logger.Error("Failed to update stored order status", logging.Err(err))
+	}
+
+	w.orderService.sendCancelNotification(venue, order.account, order.symbol, order.side, cancelled)
+}
@@ -0,0 +1,21 @@
+-- Create notification_outbox table for undelivered grid-trading notifications
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,              -- fill | error
+    order_id TEXT,                   -- Exchange order ID (empty for pre-placement rejections)
+    payload TEXT NOT NULL,           -- JSON body as originally sent
+    status TEXT NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TEXT NOT NULL DEFAULT (datetime('now')),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+
+    -- Constraints
+    CONSTRAINT check_kind CHECK (kind IN ('fill', 'error')),
+    CONSTRAINT check_status CHECK (status IN ('PENDING', 'DELIVERED', 'DEAD'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_outbox_status_next ON notification_outbox(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_order_id ON notification_outbox(order_id);
@@ -0,0 +1,26 @@
+-- Create order_fills table with per-trade execution and commission details
+CREATE TABLE IF NOT EXISTS order_fills (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account TEXT NOT NULL DEFAULT '',  -- Sub-account name (empty = master)
+    symbol TEXT NOT NULL,
+    order_id TEXT NOT NULL,            -- Exchange order ID
+    trade_id INTEGER NOT NULL,         -- Binance trade ID
+    side TEXT NOT NULL,                -- BUY | SELL
+    price TEXT NOT NULL,
+    quantity TEXT NOT NULL,            -- Base asset amount
+    quote_quantity TEXT NOT NULL,      -- Quote asset amount
+    commission TEXT NOT NULL,
+    commission_asset TEXT NOT NULL,    -- Asset the fee was charged in (e.g. BNB, USDT, ETH)
+    is_maker INTEGER NOT NULL DEFAULT 0,
+    traded_at TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+
+    -- Constraints
+    CONSTRAINT unique_trade UNIQUE (account, symbol, trade_id),
+    CONSTRAINT check_side CHECK (side IN ('BUY', 'SELL'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_fills_order_id ON order_fills(order_id);
+CREATE INDEX IF NOT EXISTS idx_fills_commission_asset ON order_fills(commission_asset);
+CREATE INDEX IF NOT EXISTS idx_fills_traded_at ON order_fills(traded_at);
@@ -0,0 +1,22 @@
+-- Create orders table recording every order placed on the exchange, so orders can be found by ID alone
+CREATE TABLE IF NOT EXISTS orders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account TEXT NOT NULL DEFAULT '',  -- Sub-account name (empty = master)
+    symbol TEXT NOT NULL,
+    order_id TEXT NOT NULL,            -- Exchange order ID
+    side TEXT NOT NULL,                -- buy | sell
+    price TEXT NOT NULL,
+    quantity TEXT NOT NULL,            -- Base asset amount
+    status TEXT NOT NULL DEFAULT 'open', -- Latest exchange status seen
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT,                   -- When status last changed
+    expires_at TEXT,                   -- Cancelled by the TTL worker after this (NULL = no TTL)
+
+    -- Constraints
+    CONSTRAINT unique_order UNIQUE (account, symbol, order_id),
+    CONSTRAINT check_side CHECK (side IN ('buy', 'sell'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_orders_order_id ON orders(order_id);
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders(created_at);
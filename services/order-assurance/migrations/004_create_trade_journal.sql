@@ -0,0 +1,40 @@
+-- Create trade_journal table: append-only record of every execution reported by the user-data stream
+CREATE TABLE IF NOT EXISTS trade_journal (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account TEXT NOT NULL DEFAULT '',  -- Sub-account name (empty = master)
+    symbol TEXT NOT NULL,
+    order_id TEXT NOT NULL,            -- Exchange order ID
+    client_order_id TEXT NOT NULL,
+    trade_id INTEGER NOT NULL,         -- Binance trade ID
+    side TEXT NOT NULL,                -- BUY | SELL
+    price TEXT NOT NULL,
+    quantity TEXT NOT NULL,            -- Base asset amount of this execution
+    quote_quantity TEXT NOT NULL,      -- Quote asset amount of this execution
+    commission TEXT NOT NULL,
+    commission_asset TEXT NOT NULL,
+    is_maker INTEGER NOT NULL DEFAULT 0,
+    order_status TEXT NOT NULL,        -- Binance order status after this execution (PARTIALLY_FILLED | FILLED)
+    traded_at TEXT NOT NULL,
+    recorded_at TEXT NOT NULL DEFAULT (datetime('now')),
+
+    -- Constraints
+    CONSTRAINT unique_journal_trade UNIQUE (account, symbol, trade_id),
+    CONSTRAINT check_side CHECK (side IN ('BUY', 'SELL'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_journal_traded_at ON trade_journal(traded_at);
+CREATE INDEX IF NOT EXISTS idx_journal_order_id ON trade_journal(order_id);
+
+-- Journal rows are never changed or removed
+CREATE TRIGGER IF NOT EXISTS trade_journal_no_update
+BEFORE UPDATE ON trade_journal
+BEGIN
+    SELECT RAISE(ABORT, 'trade_journal is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS trade_journal_no_delete
+BEFORE DELETE ON trade_journal
+BEGIN
+    SELECT RAISE(ABORT, 'trade_journal is append-only');
+END;
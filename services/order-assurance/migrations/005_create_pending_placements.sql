@@ -0,0 +1,17 @@
+-- Create pending_placements table: an order is recorded here (with the clientOrderId sent to
+-- Binance) before it is submitted and removed once the outcome is known, so orders placed just
+-- before a crash can be found and reported to grid-trading on the next startup
+CREATE TABLE IF NOT EXISTS pending_placements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account TEXT NOT NULL DEFAULT '',   -- Sub-account name (empty = master)
+    symbol TEXT NOT NULL,
+    client_order_id TEXT NOT NULL,      -- newClientOrderId sent to Binance
+    side TEXT NOT NULL,                 -- buy | sell
+    price TEXT NOT NULL,                -- Requested price (as sent by grid-trading)
+    quantity TEXT NOT NULL,             -- Requested base asset amount
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+
+    -- Constraints
+    CONSTRAINT unique_client_order_id UNIQUE (client_order_id),
+    CONSTRAINT check_pending_side CHECK (side IN ('buy', 'sell'))
+);
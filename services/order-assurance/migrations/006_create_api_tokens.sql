@@ -0,0 +1,16 @@
+-- Create api_tokens table: credentials for clients and services, managed over the API so they
+-- can be rotated without redeploying. Only the SHA-256 hash of a token is stored.
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,                 -- Who uses the token, e.g. grid-trading, dashboard
+    token_hash TEXT NOT NULL,           -- Hex SHA-256 of the token
+    prefix TEXT NOT NULL,               -- First characters of the token, to recognize it in listings
+    scopes TEXT NOT NULL,               -- Comma-separated: read | write | admin
+    expires_at TEXT,                    -- NULL = never expires
+    last_used_at TEXT,
+    revoked_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+
+    -- Constraints
+    CONSTRAINT unique_token_hash UNIQUE (token_hash)
+);
@@ -0,0 +1,35 @@
+-- Create placement_audit table: append-only record of every order submission sent to Binance,
+-- with the parameters as sent (signature and API key removed) and the exchange's raw reply
+CREATE TABLE IF NOT EXISTS placement_audit (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account TEXT NOT NULL DEFAULT '',  -- Sub-account name (empty = master)
+    symbol TEXT NOT NULL,
+    side TEXT NOT NULL,                -- BUY | SELL as sent
+    client_order_id TEXT NOT NULL DEFAULT '',
+    order_id TEXT NOT NULL DEFAULT '', -- Exchange order ID when the submission placed an order
+    transport TEXT NOT NULL,           -- rest | websocket
+    request TEXT NOT NULL,             -- JSON object of the request parameters
+    status_code INTEGER NOT NULL,      -- HTTP or WebSocket API status (0 = no reply)
+    response TEXT NOT NULL,            -- Raw reply body
+    error TEXT NOT NULL DEFAULT '',
+    duration_ms INTEGER NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_placement_audit_created_at ON placement_audit(created_at);
+CREATE INDEX IF NOT EXISTS idx_placement_audit_client_order_id ON placement_audit(client_order_id);
+CREATE INDEX IF NOT EXISTS idx_placement_audit_order_id ON placement_audit(order_id);
+
+-- Audit rows are never changed or removed
+CREATE TRIGGER IF NOT EXISTS placement_audit_no_update
+BEFORE UPDATE ON placement_audit
+BEGIN
+    SELECT RAISE(ABORT, 'placement_audit is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS placement_audit_no_delete
+BEFORE DELETE ON placement_audit
+BEGIN
+    SELECT RAISE(ABORT, 'placement_audit is append-only');
+END;
@@ -0,0 +1,45 @@
+-- Create paper trading tables (TRADING_MODE=paper): simulated spot orders, their executions
+-- and the simulated balances of every account. Unused in live mode.
+CREATE TABLE IF NOT EXISTS paper_orders (
+    order_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account TEXT NOT NULL DEFAULT '',      -- Sub-account name (empty = master)
+    symbol TEXT NOT NULL,
+    client_order_id TEXT NOT NULL,
+    side TEXT NOT NULL,                    -- BUY | SELL
+    type TEXT NOT NULL,                    -- LIMIT | MARKET
+    price TEXT NOT NULL,                   -- Limit price (0 for market orders)
+    orig_qty TEXT NOT NULL,
+    executed_qty TEXT NOT NULL DEFAULT '0',
+    cummulative_quote_qty TEXT NOT NULL DEFAULT '0',
+    status TEXT NOT NULL,                  -- NEW | FILLED | CANCELED
+    created_at INTEGER NOT NULL,           -- Epoch ms
+    updated_at INTEGER NOT NULL            -- Epoch ms
+);
+
+CREATE TABLE IF NOT EXISTS paper_trades (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account TEXT NOT NULL DEFAULT '',
+    order_id INTEGER NOT NULL,
+    symbol TEXT NOT NULL,
+    price TEXT NOT NULL,
+    qty TEXT NOT NULL,
+    quote_qty TEXT NOT NULL,
+    commission TEXT NOT NULL,
+    commission_asset TEXT NOT NULL,
+    is_buyer INTEGER NOT NULL,
+    is_maker INTEGER NOT NULL,
+    time INTEGER NOT NULL                  -- Epoch ms
+);
+
+CREATE TABLE IF NOT EXISTS paper_balances (
+    account TEXT NOT NULL DEFAULT '',
+    asset TEXT NOT NULL,
+    free TEXT NOT NULL,
+    locked TEXT NOT NULL,
+    PRIMARY KEY (account, asset)
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_paper_orders_status ON paper_orders(status, symbol);
+CREATE INDEX IF NOT EXISTS idx_paper_orders_account_symbol ON paper_orders(account, symbol, order_id);
+CREATE INDEX IF NOT EXISTS idx_paper_trades_order ON paper_trades(account, order_id);
@@ -0,0 +1,15 @@
+-- Create notification_log table: every fill and error notification in send order, so grid-trading
+-- can pull the ones it missed by sequence number (GET /notifications/replay)
+CREATE TABLE IF NOT EXISTS notification_log (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,              -- fill | error
+    order_id TEXT NOT NULL DEFAULT '', -- Exchange order ID (empty for pre-placement rejections)
+    payload TEXT NOT NULL,           -- JSON body without its seq
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+
+    -- Constraints
+    CONSTRAINT check_kind CHECK (kind IN ('fill', 'error'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_notification_log_created_at ON notification_log(created_at);
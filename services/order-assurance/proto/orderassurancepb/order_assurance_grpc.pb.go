@@ -0,0 +1,261 @@
+// Typed order-assurance contract for grid-trading, mirroring the HTTP API.
+//
+// Regenerate the Go stubs in orderassurancepb after changing this file
+// (requires protoc, protoc-gen-go and protoc-gen-go-grpc):
+//   protoc --go_out=paths=source_relative:services/order-assurance/proto/orderassurancepb \
+//     --go-grpc_out=paths=source_relative:services/order-assurance/proto/orderassurancepb \
+//     -I services/order-assurance/proto order_assurance.proto
+//
+// Decimal values are strings to keep exact precision, as in the JSON API.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: order_assurance.proto
+
+package orderassurancepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OrderAssurance_PlaceOrder_FullMethodName       = "/orderassurance.v1.OrderAssurance/PlaceOrder"
+	OrderAssurance_GetOrderStatus_FullMethodName   = "/orderassurance.v1.OrderAssurance/GetOrderStatus"
+	OrderAssurance_GetOrderStatuses_FullMethodName = "/orderassurance.v1.OrderAssurance/GetOrderStatuses"
+	OrderAssurance_SubscribeFills_FullMethodName   = "/orderassurance.v1.OrderAssurance/SubscribeFills"
+)
+
+// OrderAssuranceClient is the client API for OrderAssurance service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OrderAssuranceClient interface {
+	// Same as POST /order-assurance
+	PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*PlaceOrderResponse, error)
+	// Same as GET /order-status/{symbol}/{order_id}
+	GetOrderStatus(ctx context.Context, in *GetOrderStatusRequest, opts ...grpc.CallOption) (*OrderStatus, error)
+	// Same as POST /order-status/batch; results are in request order
+	GetOrderStatuses(ctx context.Context, in *GetOrderStatusesRequest, opts ...grpc.CallOption) (*GetOrderStatusesResponse, error)
+	// Streams fills and order errors as they happen. Subscribers get copies of the
+	// webhook/NATS notifications, which are still sent and outboxed as before; a
+	// subscriber that falls too far behind is disconnected.
+	SubscribeFills(ctx context.Context, in *SubscribeFillsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderEvent], error)
+}
+
+type orderAssuranceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrderAssuranceClient(cc grpc.ClientConnInterface) OrderAssuranceClient {
+	return &orderAssuranceClient{cc}
+}
+
+func (c *orderAssuranceClient) PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*PlaceOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlaceOrderResponse)
+	err := c.cc.Invoke(ctx, OrderAssurance_PlaceOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderAssuranceClient) GetOrderStatus(ctx context.Context, in *GetOrderStatusRequest, opts ...grpc.CallOption) (*OrderStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderStatus)
+	err := c.cc.Invoke(ctx, OrderAssurance_GetOrderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderAssuranceClient) GetOrderStatuses(ctx context.Context, in *GetOrderStatusesRequest, opts ...grpc.CallOption) (*GetOrderStatusesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderStatusesResponse)
+	err := c.cc.Invoke(ctx, OrderAssurance_GetOrderStatuses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderAssuranceClient) SubscribeFills(ctx context.Context, in *SubscribeFillsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderAssurance_ServiceDesc.Streams[0], OrderAssurance_SubscribeFills_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeFillsRequest, OrderEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderAssurance_SubscribeFillsClient = grpc.ServerStreamingClient[OrderEvent]
+
+// OrderAssuranceServer is the server API for OrderAssurance service.
+// All implementations must embed UnimplementedOrderAssuranceServer
+// for forward compatibility.
+type OrderAssuranceServer interface {
+	// Same as POST /order-assurance
+	PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error)
+	// Same as GET /order-status/{symbol}/{order_id}
+	GetOrderStatus(context.Context, *GetOrderStatusRequest) (*OrderStatus, error)
+	// Same as POST /order-status/batch; results are in request order
+	GetOrderStatuses(context.Context, *GetOrderStatusesRequest) (*GetOrderStatusesResponse, error)
+	// Streams fills and order errors as they happen. Subscribers get copies of the
+	// webhook/NATS notifications, which are still sent and outboxed as before; a
+	// subscriber that falls too far behind is disconnected.
+	SubscribeFills(*SubscribeFillsRequest, grpc.ServerStreamingServer[OrderEvent]) error
+	mustEmbedUnimplementedOrderAssuranceServer()
+}
+
+// UnimplementedOrderAssuranceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrderAssuranceServer struct{}
+
+func (UnimplementedOrderAssuranceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
+}
+func (UnimplementedOrderAssuranceServer) GetOrderStatus(context.Context, *GetOrderStatusRequest) (*OrderStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderStatus not implemented")
+}
+func (UnimplementedOrderAssuranceServer) GetOrderStatuses(context.Context, *GetOrderStatusesRequest) (*GetOrderStatusesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderStatuses not implemented")
+}
+func (UnimplementedOrderAssuranceServer) SubscribeFills(*SubscribeFillsRequest, grpc.ServerStreamingServer[OrderEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeFills not implemented")
+}
+func (UnimplementedOrderAssuranceServer) mustEmbedUnimplementedOrderAssuranceServer() {}
+func (UnimplementedOrderAssuranceServer) testEmbeddedByValue()                        {}
+
+// UnsafeOrderAssuranceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrderAssuranceServer will
+// result in compilation errors.
+type UnsafeOrderAssuranceServer interface {
+	mustEmbedUnimplementedOrderAssuranceServer()
+}
+
+func RegisterOrderAssuranceServer(s grpc.ServiceRegistrar, srv OrderAssuranceServer) {
+	// If the following call pancis, it indicates UnimplementedOrderAssuranceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OrderAssurance_ServiceDesc, srv)
+}
+
+func _OrderAssurance_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderAssuranceServer).PlaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderAssurance_PlaceOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderAssuranceServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderAssurance_GetOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderAssuranceServer).GetOrderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderAssurance_GetOrderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderAssuranceServer).GetOrderStatus(ctx, req.(*GetOrderStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderAssurance_GetOrderStatuses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderStatusesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderAssuranceServer).GetOrderStatuses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderAssurance_GetOrderStatuses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderAssuranceServer).GetOrderStatuses(ctx, req.(*GetOrderStatusesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderAssurance_SubscribeFills_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeFillsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderAssuranceServer).SubscribeFills(m, &grpc.GenericServerStream[SubscribeFillsRequest, OrderEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderAssurance_SubscribeFillsServer = grpc.ServerStreamingServer[OrderEvent]
+
+// OrderAssurance_ServiceDesc is the grpc.ServiceDesc for OrderAssurance service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrderAssurance_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orderassurance.v1.OrderAssurance",
+	HandlerType: (*OrderAssuranceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PlaceOrder",
+			Handler:    _OrderAssurance_PlaceOrder_Handler,
+		},
+		{
+			MethodName: "GetOrderStatus",
+			Handler:    _OrderAssurance_GetOrderStatus_Handler,
+		},
+		{
+			MethodName: "GetOrderStatuses",
+			Handler:    _OrderAssurance_GetOrderStatuses_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeFills",
+			Handler:       _OrderAssurance_SubscribeFills_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "order_assurance.proto",
+}
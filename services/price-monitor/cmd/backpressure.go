@@ -0,0 +1,64 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Widest spacing between triggers of a symbol while grid-trading keeps refusing them
+const triggerMaxSpacing = 2 * time.Minute
+
+// backOff widens the trigger spacing after grid-trading refused a trigger with 429: no
+// trigger goes out for retryAfter, then each symbol triggers at most once per spacing,
+// which doubles on every refusal. Callers hold pm.mu.
+func (pm *PriceMonitor) backOff(retryAfter time.Duration) {
+	pm.triggersHeldUntil = time.Now().Add(retryAfter)
+
+	spacing := pm.triggerSpacing * 2
+	if base := time.Duration(pm.cfg.PriceCheckIntervalMs) * time.Millisecond; spacing < base {
+		spacing = base
+	}
+	if spacing < retryAfter {
+		spacing = retryAfter
+	}
+	if spacing > triggerMaxSpacing {
+		spacing = triggerMaxSpacing
+	}
+	pm.triggerSpacing = spacing
+	slog.Warn("grid-trading is saturated, holding triggers", "retry_after", retryAfter, "spacing", spacing)
+}
+
+// restoreTriggerRate narrows the trigger spacing again after an accepted trigger, halving it until
+// it is below the check interval and triggers flow as usual. Callers hold pm.mu.
+func (pm *PriceMonitor) restoreTriggerRate() {
+	if pm.triggerSpacing == 0 {
+		return
+	}
+	pm.triggerSpacing /= 2
+	if pm.triggerSpacing < time.Duration(pm.cfg.PriceCheckIntervalMs)*time.Millisecond {
+		pm.triggerSpacing = 0
+		slog.Info("grid-trading accepts triggers again, back to the usual trigger rate")
+	}
+}
+
+// throttled reports whether a symbol's trigger has to wait for grid-trading. The trigger is
+// not lost: the symbol's last sent price stays, so the next check sends the then-current price.
+// Callers hold pm.mu.
+func (pm *PriceMonitor) throttled(symbol string) bool {
+	now := time.Now()
+	if now.Before(pm.triggersHeldUntil) ||
+		(pm.triggerSpacing > 0 && now.Sub(pm.lastTrigger[symbol]) < pm.triggerSpacing) {
+		pm.throttledTriggers++
+		return true
+	}
+	return false
+}
+
+// effectiveTriggerInterval is how often a symbol can trigger at most right now. Callers hold pm.mu.
+func (pm *PriceMonitor) effectiveTriggerInterval() time.Duration {
+	interval := time.Duration(pm.cfg.PriceCheckIntervalMs) * time.Millisecond
+	if pm.triggerSpacing > interval {
+		return pm.triggerSpacing
+	}
+	return interval
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,6 +15,11 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/pkg/natsjs"
+	"github.com/grid-trading-bot/pkg/redis"
+	"github.com/grid-trading-bot/pkg/tracing"
 	"github.com/grid-trading-bot/services/price-monitor/internal/client"
 	"github.com/grid-trading-bot/services/price-monitor/internal/config"
 	"github.com/grid-trading-bot/services/price-monitor/internal/ticker"
@@ -20,10 +28,15 @@ import (
 
 type PriceMonitor struct {
 	cfg         *config.Config
-	ticker      *ticker.BinanceTicker
+	ticker      ticker.PriceSource
+	failover    *ticker.FailoverTicker // nil = primary only
+	stream      *ticker.BinanceStream  // nil = REST polling only
 	gridClient  *client.GridTradingClient
+	triggers    client.TriggerSender
+	priceCache  *client.PriceCacheWriter
 	lastTrigger map[string]time.Time
 	lastPrice   map[string]decimal.Decimal
+	sequence    uint64 // Last trigger sequence number, seeded from the clock so it keeps increasing across restarts
 	symbols     []string
 	mu          sync.RWMutex
 
@@ -35,26 +48,56 @@ type PriceMonitor struct {
 	lastSymbolsFetch time.Time
 	checkCount       int64
 	errorCount       int64
+	lastStreamTime   time.Time
+	streamDrops      int64
+
+	// Backpressure from grid-trading (429), see backpressure.go
+	triggersHeldUntil time.Time
+	triggerSpacing    time.Duration // Minimum time between triggers of a symbol, 0 = none
+	throttledTriggers int64
 }
 
 func NewPriceMonitor(cfg *config.Config) *PriceMonitor {
 	ctx, cancel := context.WithCancel(context.Background())
+	gridClient := client.NewGridTradingClient(cfg.GridTradingURL, cfg.GridTradingAPIKey)
 	return &PriceMonitor{
 		cfg:         cfg,
-		ticker:      ticker.NewBinanceTicker(),
-		gridClient:  client.NewGridTradingClient(cfg.GridTradingURL),
+		ticker:      ticker.NewBinanceTicker(cfg.BinanceAPIURL),
+		gridClient:  gridClient,
+		triggers:    gridClient,
 		lastTrigger: make(map[string]time.Time),
 		lastPrice:   make(map[string]decimal.Decimal),
+		sequence:    uint64(time.Now().UnixNano()),
 		ctx:         ctx,
 		cancel:      cancel,
 	}
 }
 
+// UseTriggerSender replaces the HTTP webhook for price triggers (symbols are still fetched over HTTP)
+func (pm *PriceMonitor) UseTriggerSender(triggers client.TriggerSender) {
+	pm.triggers = triggers
+}
+
+// UseFailover reads prices through a failover ticker wrapping the primary source
+func (pm *PriceMonitor) UseFailover(failover *ticker.FailoverTicker) {
+	pm.ticker = failover
+	pm.failover = failover
+}
+
+// UseAggregate reads prices as the median of several exchanges instead of the primary alone
+func (pm *PriceMonitor) UseAggregate(aggregate *ticker.AggregateTicker) {
+	pm.ticker = aggregate
+}
+
+// UsePriceCache publishes every fetched price to the shared Redis cache
+func (pm *PriceMonitor) UsePriceCache(cache *client.PriceCacheWriter) {
+	pm.priceCache = cache
+}
+
 func (pm *PriceMonitor) Start() error {
 	// Fetch symbols from grid service
 	if err := pm.refreshSymbols(); err != nil {
-		log.Printf("Warning: Failed to fetch symbols from grid service: %v", err)
-		log.Printf("Will retry in next cycle")
+		slog.Warn("Failed to fetch symbols from grid service, retrying next cycle", logging.Err(err))
 	}
 
 	log.Printf("Starting price monitor with polling interval: %dms", pm.cfg.PriceCheckIntervalMs)
@@ -64,6 +107,11 @@ func (pm *PriceMonitor) Start() error {
 	pm.wg.Add(1)
 	go pm.pollingLoop()
 
+	if pm.stream != nil {
+		pm.wg.Add(1)
+		go pm.streamLoop()
+	}
+
 	return nil
 }
 
@@ -91,21 +139,24 @@ func (pm *PriceMonitor) pollingLoop() {
 	// Do initial check immediately
 	pm.checkPrices()
 
+	runs := 1
 	for {
 		select {
 		case <-pm.ctx.Done():
 			return
 		case <-ticker.C:
-			// Refresh symbols every other run (on even check counts)
-			pm.mu.RLock()
-			shouldRefresh := pm.checkCount%2 == 0
-			pm.mu.RUnlock()
-
-			if shouldRefresh {
+			// Refresh symbols every other run
+			runs++
+			if runs%2 == 0 {
 				if err := pm.refreshSymbols(); err != nil {
-					log.Printf("Failed to refresh symbols: %v", err)
+					slog.Error("Failed to refresh symbols", logging.Err(err))
 				}
 			}
+
+			// The stream delivers prices while it is up - REST only fills in during outages
+			if pm.streaming() {
+				continue
+			}
 			pm.checkPrices()
 		}
 	}
@@ -124,22 +175,48 @@ func (pm *PriceMonitor) checkPrices() {
 	}
 
 	// Fetch prices for all symbols
-	prices, err := pm.ticker.GetPrices(symbols)
+	snapshot, err := pm.ticker.GetPrices(symbols)
 	if err != nil {
 		pm.mu.Lock()
 		pm.errorCount++
 		pm.mu.Unlock()
-		log.Printf("Failed to fetch prices: %v", err)
+		slog.Error("Failed to fetch prices", logging.Err(err))
 		return
 	}
 
+	if pm.priceCache != nil {
+		fetchedAt := time.Now()
+		for symbol, price := range snapshot.Prices {
+			if err := pm.priceCache.StorePrice(symbol, price, fetchedAt); err != nil {
+				slog.Error("Failed to cache price", logging.KeySymbol, symbol, logging.Err(err))
+			}
+		}
+	}
+
+	feed := snapshot.Feed
+	if feed == "" {
+		feed = contracts.FeedREST
+	}
+
 	// Process each price update
-	for symbol, price := range prices {
-		pm.handlePriceUpdate(symbol, price)
+	for symbol, price := range snapshot.Prices {
+		origin := priceOrigin{source: snapshot.Source, feed: feed, exchange: snapshot.Exchange, confidence: 1}
+		if confidence, ok := snapshot.Confidence[symbol]; ok {
+			origin.confidence = confidence
+		}
+		pm.handlePriceUpdate(symbol, price, snapshot.ServerTime, origin)
 	}
 }
 
-func (pm *PriceMonitor) handlePriceUpdate(symbol string, price decimal.Decimal) {
+// priceOrigin is where a price came from, passed on in its trigger
+type priceOrigin struct {
+	source     string // Failover feed, see ticker.Snapshot
+	feed       string // contracts.FeedWebsocket, FeedREST or FeedAggregated
+	exchange   string
+	confidence float64
+}
+
+func (pm *PriceMonitor) handlePriceUpdate(symbol string, price decimal.Decimal, exchangeTime time.Time, origin priceOrigin) {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
@@ -150,19 +227,51 @@ func (pm *PriceMonitor) handlePriceUpdate(symbol string, price decimal.Decimal)
 			return // Skip - insignificant change
 		}
 	}
+	if pm.throttled(symbol) {
+		return
+	}
 
 	// Send trigger to grid-trading
-	if err := pm.gridClient.SendPriceTrigger(symbol, price); err != nil {
-		log.Printf("Failed to send trigger for %s at %s: %v",
-			symbol, price, err)
+	pm.sequence++
+	trigger := contracts.PriceTrigger{
+		Symbol:       symbol,
+		Price:        price,
+		Sequence:     pm.sequence,
+		Source:       origin.source,
+		Feed:         origin.feed,
+		Exchange:     origin.exchange,
+		Confidence:   origin.confidence,
+	}
+	if !exchangeTime.IsZero() {
+		trigger.ExchangeTime = &exchangeTime
+	}
+	// The root span of everything this tick sets off in grid-trading and order-assurance
+	ctx, span := tracing.Start(context.Background(), "price tick")
+	defer span.End()
+	span.SetAttr("symbol", symbol)
+	span.SetAttr("price", price.String())
+	span.SetAttr("feed", origin.feed)
+	span.SetAttr("exchange", origin.exchange)
+	span.SetAttr("sequence", int64(pm.sequence))
+
+	if err := pm.triggers.SendPriceTrigger(ctx, trigger); err != nil {
+		span.RecordError(err)
+		var backpressure *client.BackpressureError
+		if errors.As(err, &backpressure) {
+			pm.backOff(backpressure.RetryAfter)
+			return
+		}
+		slog.Error("Failed to send trigger", logging.KeySymbol, symbol, "price", price, logging.Err(err))
 		return
 	}
+	pm.restoreTriggerRate()
 
 	// Update tracking
 	pm.lastTrigger[symbol] = time.Now()
 	pm.lastPrice[symbol] = price
 
-	log.Printf("Triggered %s at %s", symbol, price)
+	slog.Info("Triggered", logging.KeySymbol, symbol, "price", price, "feed", origin.feed, "exchange", origin.exchange,
+		"confidence", origin.confidence)
 }
 
 func (pm *PriceMonitor) GetStatus() map[string]interface{} {
@@ -189,6 +298,26 @@ func (pm *PriceMonitor) GetStatus() map[string]interface{} {
 		lastTriggers[symbol] = t.Format(time.RFC3339)
 	}
 	status["last_triggers"] = lastTriggers
+	status["effective_trigger_interval_ms"] = pm.effectiveTriggerInterval().Milliseconds()
+	status["throttled_triggers"] = pm.throttledTriggers
+	if time.Now().Before(pm.triggersHeldUntil) {
+		status["triggers_held_until"] = pm.triggersHeldUntil.Format(time.RFC3339)
+	}
+
+	if pm.failover != nil {
+		status["market_data"] = pm.failover.Status()
+	}
+
+	if pm.stream != nil {
+		status["price_source"] = "ws"
+		status["stream_connected"] = pm.stream.Connected()
+		status["stream_drops"] = pm.streamDrops
+		if !pm.lastStreamTime.IsZero() {
+			status["last_stream_time"] = pm.lastStreamTime.Format(time.RFC3339)
+		}
+	} else {
+		status["price_source"] = pm.cfg.PriceSource
+	}
 
 	return status
 }
@@ -200,12 +329,68 @@ func (pm *PriceMonitor) Shutdown() {
 }
 
 func main() {
+	logging.Setup("price-monitor")
+	tracer := tracing.Setup("price-monitor")
+
 	// Load configuration
 	cfg := config.LoadConfig()
 
 	// Create price monitor
 	monitor := NewPriceMonitor(cfg)
 
+	if cfg.Transport == "nats" {
+		conn, err := natsjs.Connect(cfg.NATSURL, "price-monitor")
+		if err != nil {
+			log.Fatal("Failed to connect to NATS:", err)
+		}
+		defer conn.Close()
+
+		publisher, err := client.NewTriggerPublisher(natsjs.New(conn))
+		if err != nil {
+			log.Fatal("Failed to set up trigger stream:", err)
+		}
+		monitor.UseTriggerSender(publisher)
+		log.Printf("Publishing price triggers to NATS JetStream at %s", cfg.NATSURL)
+	}
+
+	if cfg.ShardRouting {
+		monitor.UseTriggerSender(client.NewShardRouter(cfg.GridTradingURL, cfg.GridTradingAPIKey))
+		log.Printf("Routing price triggers to the grid-trading instance holding each symbol (shard map from %s/shards)", cfg.GridTradingURL)
+	}
+
+	if cfg.RedisURL != "" {
+		rc, err := redis.Dial(cfg.RedisURL)
+		if err != nil {
+			log.Fatal("Failed to connect to Redis:", err)
+		}
+		defer rc.Close()
+
+		monitor.UsePriceCache(client.NewPriceCacheWriter(rc, time.Duration(cfg.PriceCacheTTLSec)*time.Second))
+		log.Printf("Publishing prices to the Redis price cache (TTL %ds)", cfg.PriceCacheTTLSec)
+	}
+
+	if cfg.SecondaryExchange != "" {
+		var secondary ticker.PriceSource
+		if cfg.SecondaryExchange == "bybit" {
+			secondary = ticker.NewBybitTicker(cfg.SecondaryAPIURL)
+		} else {
+			secondary = ticker.NewBinanceTicker(cfg.SecondaryAPIURL)
+		}
+		if cfg.PriceSource == "aggregated" {
+			monitor.UseAggregate(ticker.NewAggregateTicker(cfg.AggregateBandPct, ticker.NewBinanceTicker(cfg.BinanceAPIURL), secondary))
+			log.Printf("Prices are the median of Binance and %s, venues within %.2f%% of it count towards the confidence", cfg.SecondaryExchange, cfg.AggregateBandPct)
+		} else {
+			monitor.UseFailover(ticker.NewFailoverTicker(ticker.NewBinanceTicker(cfg.BinanceAPIURL), secondary, cfg.SecondaryExchange,
+				time.Duration(cfg.FailoverAfterSec)*time.Second, cfg.SecondaryBandPct))
+			log.Printf("Market data failover to %s after %ds of primary errors (band %.2f%%)", cfg.SecondaryExchange, cfg.FailoverAfterSec, cfg.SecondaryBandPct)
+		}
+	}
+
+	if cfg.PriceSource == "ws" {
+		monitor.UseStream(ticker.NewBinanceStream(cfg.BinanceStreamURL))
+		log.Printf("Streaming prices from the Binance market data stream, REST polling while it is down")
+	}
+
 	// Start monitoring
 	if err := monitor.Start(); err != nil {
 		log.Fatal("Failed to start monitor:", err)
@@ -226,6 +411,11 @@ func main() {
 		json.NewEncoder(w).Encode(monitor.GetStatus())
 	})
 
+	if cfg.APIKey != "" {
+		router.Use(apiKeyMiddleware(cfg.APIKey))
+		log.Println("API key authentication enabled for /status")
+	}
+
 	// Start HTTP server
 	srv := &http.Server{
 		Addr:    ":" + cfg.ServerPort,
@@ -234,7 +424,9 @@ func main() {
 
 	go func() {
 		log.Printf("Price Monitor starting on port %s", cfg.ServerPort)
-		log.Printf("Using Binance REST API with polling")
+		if cfg.PriceSource == "rest" {
+			log.Printf("Using Binance REST API with polling")
+		}
 
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatal("Server failed:", err)
@@ -252,5 +444,22 @@ func main() {
 
 	monitor.Shutdown()
 	srv.Shutdown(ctx)
+	tracer.Shutdown()
 	log.Println("Server stopped")
+}
+
+// apiKeyMiddleware rejects requests without the key in X-API-Key; /health stays open for orchestrators
+func apiKeyMiddleware(apiKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/health" && subtle.ConstantTimeCompare([]byte(r.Header.Get("X-API-Key")), []byte(apiKey)) != 1 {
+				slog.Warn("Rejected unauthenticated request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
 }
\ No newline at end of file
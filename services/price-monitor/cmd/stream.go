@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/services/price-monitor/internal/ticker"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// Reconnect delays after a dropped stream, doubling up to the max
+	streamMinBackoff = time.Second
+	streamMaxBackoff = time.Minute
+
+	// A connection that lasted this long was healthy - its drop starts the backoff over
+	streamHealthyAfter = time.Minute
+)
+
+// UseStream takes prices from a market data stream. The polling loop keeps refreshing symbols
+// and reads prices over REST only while the stream is down, so triggers keep flowing.
+func (pm *PriceMonitor) UseStream(stream *ticker.BinanceStream) {
+	pm.stream = stream
+}
+
+// streaming reports whether prices currently arrive from the stream
+func (pm *PriceMonitor) streaming() bool {
+	return pm.stream != nil && pm.stream.Connected()
+}
+
+// streamLoop keeps a stream open for the current symbols, resubscribing when they change
+// and reconnecting with exponential backoff when the connection drops
+func (pm *PriceMonitor) streamLoop() {
+	defer pm.wg.Done()
+
+	checkInterval := time.Duration(pm.cfg.PriceCheckIntervalMs) * time.Millisecond
+	backoff := streamMinBackoff
+	for {
+		pm.mu.RLock()
+		symbols := pm.symbols
+		pm.mu.RUnlock()
+
+		if len(symbols) == 0 {
+			if !pm.sleep(checkInterval) {
+				return
+			}
+			continue
+		}
+
+		connCtx, cancel := context.WithCancel(pm.ctx)
+		go pm.cancelOnSymbolChange(connCtx, cancel, symbols, checkInterval)
+
+		started := time.Now()
+		err := pm.stream.Stream(connCtx, symbols, pm.handleStreamPrice)
+		resubscribe := connCtx.Err() != nil
+		cancel()
+
+		if pm.ctx.Err() != nil {
+			return
+		}
+		if resubscribe {
+			slog.Info("Monitored symbols changed, resubscribing the price stream")
+			backoff = streamMinBackoff
+			continue
+		}
+
+		pm.mu.Lock()
+		pm.streamDrops++
+		pm.mu.Unlock()
+		if time.Since(started) >= streamHealthyAfter {
+			backoff = streamMinBackoff
+		}
+		slog.Warn("Price stream down, polling REST until it reconnects", "retry_after", backoff, logging.Err(err))
+		if !pm.sleep(backoff) {
+			return
+		}
+		backoff *= 2
+		if backoff > streamMaxBackoff {
+			backoff = streamMaxBackoff
+		}
+	}
+}
+
+// cancelOnSymbolChange ends a subscription once the monitored symbols differ from it
+func (pm *PriceMonitor) cancelOnSymbolChange(ctx context.Context, cancel context.CancelFunc, subscribed []string, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			pm.mu.RLock()
+			symbols := pm.symbols
+			pm.mu.RUnlock()
+			if !sameSymbols(symbols, subscribed) {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func (pm *PriceMonitor) handleStreamPrice(symbol string, price decimal.Decimal, eventTime time.Time) {
+	if pm.priceCache != nil {
+		if err := pm.priceCache.StorePrice(symbol, price, time.Now()); err != nil {
+			slog.Error("Failed to cache price", logging.KeySymbol, symbol, logging.Err(err))
+		}
+	}
+
+	pm.mu.Lock()
+	pm.lastStreamTime = time.Now()
+	pm.mu.Unlock()
+
+	pm.handlePriceUpdate(symbol, price, eventTime, priceOrigin{source: "stream", feed: contracts.FeedWebsocket, exchange: "binance", confidence: 1})
+}
+
+// sleep waits for d, returning false if the monitor shuts down first
+func (pm *PriceMonitor) sleep(d time.Duration) bool {
+	select {
+	case <-pm.ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+func sameSymbols(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, symbol := range a {
+		seen[symbol] = true
+	}
+	for _, symbol := range b {
+		if !seen[symbol] {
+			return false
+		}
+	}
+	return true
+}
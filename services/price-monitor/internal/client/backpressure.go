@@ -0,0 +1,33 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Hold-off when grid-trading answers 429 without a usable Retry-After
+const defaultRetryAfter = 5 * time.Second
+
+// BackpressureError means grid-trading is saturated and refused a trigger (429)
+type BackpressureError struct {
+	RetryAfter time.Duration // How long grid-trading asks to hold triggers back
+}
+
+func (e *BackpressureError) Error() string {
+	return fmt.Sprintf("grid-trading is saturated, retry after %s", e.RetryAfter)
+}
+
+// retryAfter reads a Retry-After header given in seconds or as an HTTP date
+func retryAfter(header string) time.Duration {
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return defaultRetryAfter
+}
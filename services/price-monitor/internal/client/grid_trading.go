@@ -2,54 +2,58 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
-	"github.com/shopspring/decimal"
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/tracing"
 )
 
 type GridTradingClient struct {
 	baseURL    string
+	apiKey     string // Sent as X-API-Key
 	httpClient *http.Client
 }
 
-type PriceTrigger struct {
-	Symbol string          `json:"symbol"`
-	Price  decimal.Decimal `json:"price"`
-}
-
-func NewGridTradingClient(baseURL string) *GridTradingClient {
+func NewGridTradingClient(baseURL, apiKey string) *GridTradingClient {
 	return &GridTradingClient{
 		baseURL: baseURL,
+		apiKey:  apiKey,
 		httpClient: &http.Client{
-			Timeout: 5 * time.Second,
+			Timeout:   5 * time.Second,
+			Transport: tracing.Transport(nil),
 		},
 	}
 }
 
-func (c *GridTradingClient) SendPriceTrigger(symbol string, price decimal.Decimal) error {
-	trigger := PriceTrigger{
-		Symbol: symbol,
-		Price:  price,
-	}
-
+func (c *GridTradingClient) SendPriceTrigger(ctx context.Context, trigger contracts.PriceTrigger) error {
 	data, err := json.Marshal(trigger)
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.httpClient.Post(
-		c.baseURL+"/trigger-for-price",
-		"application/json",
-		bytes.NewBuffer(data),
-	)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/trigger-for-price", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send trigger: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &BackpressureError{RetryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+	}
+	if resp.StatusCode == http.StatusMisdirectedRequest {
+		return ErrMisdirected
+	}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
@@ -62,7 +66,13 @@ type GridSymbolsResponse struct {
 }
 
 func (c *GridTradingClient) GetGridSymbols() ([]string, error) {
-	resp, err := c.httpClient.Get(c.baseURL + "/levels/symbols")
+	req, err := http.NewRequest("GET", c.baseURL+"/levels/symbols", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch grid symbols: %w", err)
 	}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/redis"
+	"github.com/shopspring/decimal"
+)
+
+// PriceCacheWriter publishes every fetched price to the shared Redis price cache
+type PriceCacheWriter struct {
+	redis *redis.Client
+	ttl   time.Duration // Quotes disappear if the monitor stops updating them for this long
+}
+
+func NewPriceCacheWriter(rc *redis.Client, ttl time.Duration) *PriceCacheWriter {
+	return &PriceCacheWriter{redis: rc, ttl: ttl}
+}
+
+func (w *PriceCacheWriter) StorePrice(symbol string, price decimal.Decimal, at time.Time) error {
+	data, err := json.Marshal(contracts.PriceQuote{
+		Symbol:    symbol,
+		Price:     price,
+		UpdatedAt: at.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := w.redis.Set(contracts.PriceKeyPrefix+symbol, string(data), w.ttl); err != nil {
+		return fmt.Errorf("failed to cache price: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/grid-trading-bot/pkg/tracing"
+)
+
+// How long a fetched shard map is used before it is fetched again
+const shardMapMaxAge = 10 * time.Second
+
+// ErrMisdirected means the grid-trading instance a trigger went to does not hold the symbol's lease (421)
+var ErrMisdirected = errors.New("trigger reached an instance that does not trade the symbol")
+
+// ShardRouter sends each symbol's triggers to the grid-trading instance holding its lease
+// (SHARD_ROUTING). Symbols nobody holds a lease of go to every live instance, or to the
+// base URL if there are none. The shard map comes from GET /shards on the base URL.
+type ShardRouter struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	owners    map[string]string // Instance URL by leased symbol
+	instances []string          // URLs of the live instances
+	fetchedAt time.Time
+}
+
+func NewShardRouter(baseURL, apiKey string) *ShardRouter {
+	return &ShardRouter{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		httpClient: &http.Client{
+			Timeout:   5 * time.Second,
+			Transport: tracing.Transport(nil),
+		},
+	}
+}
+
+func (r *ShardRouter) SendPriceTrigger(ctx context.Context, trigger contracts.PriceTrigger) error {
+	err := r.send(ctx, trigger, false)
+	if err == ErrMisdirected {
+		// The lease moved since the map was fetched
+		err = r.send(ctx, trigger, true)
+	}
+	return err
+}
+
+func (r *ShardRouter) send(ctx context.Context, trigger contracts.PriceTrigger, refresh bool) error {
+	targets := r.targets(trigger.Symbol, refresh)
+
+	var firstErr error
+	for _, url := range targets {
+		err := r.post(ctx, url, trigger)
+		if err == nil {
+			continue
+		}
+		// A broadcast copy refused by an instance that lost the race for the symbol is fine
+		if err == ErrMisdirected && len(targets) > 1 {
+			continue
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (r *ShardRouter) post(ctx context.Context, url string, trigger contracts.PriceTrigger) error {
+	c := &GridTradingClient{baseURL: url, apiKey: r.apiKey, httpClient: r.httpClient}
+	return c.SendPriceTrigger(ctx, trigger)
+}
+
+// targets returns the instance URLs a symbol's trigger goes to, fetching the shard map if it is stale
+func (r *ShardRouter) targets(symbol string, refresh bool) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if refresh || time.Since(r.fetchedAt) > shardMapMaxAge {
+		if err := r.fetch(); err != nil {
+			slog.Warn("Failed to fetch the shard map, using the last one", logging.Err(err))
+		}
+		// At most one fetch per max age while grid-trading is unreachable
+		r.fetchedAt = time.Now()
+	}
+
+	if url, ok := r.owners[symbol]; ok {
+		return []string{url}
+	}
+	if len(r.instances) > 0 {
+		return r.instances
+	}
+	return []string{r.baseURL}
+}
+
+func (r *ShardRouter) fetch() error {
+	req, err := http.NewRequest("GET", r.baseURL+"/shards", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-Key", r.apiKey)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch shard map: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var shards contracts.ShardMap
+	if err := json.NewDecoder(resp.Body).Decode(&shards); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	now := time.Now()
+	r.owners = make(map[string]string, len(shards.Leases))
+	for _, lease := range shards.Leases {
+		if lease.URL != "" && lease.ExpiresAt.After(now) {
+			r.owners[lease.Symbol] = lease.URL
+		}
+	}
+	r.instances = nil
+	for _, instance := range shards.Instances {
+		if instance.URL != "" {
+			r.instances = append(r.instances, instance.URL)
+		}
+	}
+	return nil
+}
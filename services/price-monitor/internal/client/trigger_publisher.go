@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/grid-trading-bot/pkg/natsjs"
+)
+
+// TriggerSender delivers price triggers to grid-trading, continuing the trace of ctx where the
+// transport can carry it
+type TriggerSender interface {
+	SendPriceTrigger(ctx context.Context, trigger contracts.PriceTrigger) error
+}
+
+// TriggerPublisher queues price triggers on NATS JetStream instead of calling grid-trading (TRANSPORT=nats)
+type TriggerPublisher struct {
+	js *natsjs.JetStream
+}
+
+func NewTriggerPublisher(js *natsjs.JetStream) (*TriggerPublisher, error) {
+	if err := js.EnsureStream(contracts.TriggerStream); err != nil {
+		return nil, err
+	}
+	return &TriggerPublisher{js: js}, nil
+}
+
+// SendPriceTrigger publishes a trigger. Messages have no headers here, so grid-trading starts a
+// new trace for it.
+func (p *TriggerPublisher) SendPriceTrigger(_ context.Context, trigger contracts.PriceTrigger) error {
+	data, err := json.Marshal(trigger)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.js.Publish(contracts.TriggerSubjectPrefix+trigger.Symbol, data); err != nil {
+		return fmt.Errorf("failed to publish trigger: %w", err)
+	}
+	return nil
+}
@@ -4,13 +4,29 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
 	ServerPort           string
 	GridTradingURL       string
+	GridTradingAPIKey    string // Shared key grid-trading requires (GRID_TRADING_API_KEY)
+	APIKey               string // Key /status callers must send as X-API-Key (empty = open)
+	BinanceAPIURL        string
 	PriceCheckIntervalMs int
 	MinPriceChangePct    float64
+	Transport            string // http (webhooks) or nats (JetStream)
+	NATSURL              string
+	RedisURL             string // Shared price cache (empty = disabled)
+	PriceCacheTTLSec     int
+	SecondaryExchange    string // Market data failover: binance (any Binance-compatible API) or bybit (empty = disabled)
+	SecondaryAPIURL      string
+	FailoverAfterSec     int     // Primary must fail this long before the secondary is used
+	SecondaryBandPct     float64 // Max move of a secondary price from the last one before it needs confirming
+	PriceSource          string  // rest (polling), ws (market data stream, REST while it is down) or aggregated (median of Binance and SECONDARY_EXCHANGE)
+	AggregateBandPct     float64 // Venues within this of the aggregated median count towards a trigger's confidence
+	BinanceStreamURL     string
+	ShardRouting         bool // Send each symbol's triggers to the grid-trading instance holding its lease
 }
 
 func LoadConfig() *Config {
@@ -45,10 +61,102 @@ func LoadConfig() *Config {
 		log.Fatal("MIN_PRICE_CHANGE_PCT must be a non-negative number")
 	}
 
+	transport := os.Getenv("TRANSPORT")
+	if transport == "" {
+		transport = "http"
+	}
+	if transport != "http" && transport != "nats" {
+		log.Fatal("TRANSPORT must be http or nats")
+	}
+
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://localhost:4222"
+	}
+
+	priceCacheTTL := 300
+	if v := os.Getenv("PRICE_CACHE_TTL_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			log.Fatal("PRICE_CACHE_TTL_SEC must be a positive integer")
+		}
+		priceCacheTTL = parsed
+	}
+
+	secondaryExchange := strings.ToLower(os.Getenv("SECONDARY_EXCHANGE"))
+	secondaryAPIURL := os.Getenv("SECONDARY_API_URL")
+	switch secondaryExchange {
+	case "", "bybit":
+	case "binance":
+		if secondaryAPIURL == "" {
+			log.Fatal("SECONDARY_API_URL is required for SECONDARY_EXCHANGE=binance")
+		}
+	default:
+		log.Fatal("SECONDARY_EXCHANGE must be binance or bybit")
+	}
+
+	failoverAfter := 60
+	if v := os.Getenv("PRICE_FAILOVER_AFTER_SEC"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Fatal("PRICE_FAILOVER_AFTER_SEC must be a non-negative integer")
+		}
+		failoverAfter = parsed
+	}
+
+	secondaryBand := 5.0
+	if v := os.Getenv("SECONDARY_PRICE_BAND_PCT"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed <= 0 {
+			log.Fatal("SECONDARY_PRICE_BAND_PCT must be a positive number")
+		}
+		secondaryBand = parsed
+	}
+
+	priceSource := strings.ToLower(os.Getenv("PRICE_SOURCE"))
+	if priceSource == "" {
+		priceSource = "rest"
+	}
+	if priceSource != "rest" && priceSource != "ws" && priceSource != "aggregated" {
+		log.Fatal("PRICE_SOURCE must be rest, ws or aggregated")
+	}
+	if priceSource == "aggregated" && secondaryExchange == "" {
+		log.Fatal("PRICE_SOURCE=aggregated needs SECONDARY_EXCHANGE to aggregate with")
+	}
+
+	aggregateBand := 0.5
+	if v := os.Getenv("AGGREGATE_BAND_PCT"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed <= 0 {
+			log.Fatal("AGGREGATE_BAND_PCT must be a positive number")
+		}
+		aggregateBand = parsed
+	}
+
+	shardRouting := os.Getenv("SHARD_ROUTING") == "true"
+	if shardRouting && transport == "nats" {
+		log.Fatal("SHARD_ROUTING needs TRANSPORT=http")
+	}
+
 	return &Config{
 		ServerPort:           serverPort,
 		GridTradingURL:       gridTradingURL,
+		GridTradingAPIKey:    os.Getenv("GRID_TRADING_API_KEY"),
+		APIKey:               os.Getenv("PRICE_MONITOR_API_KEY"),
+		BinanceAPIURL:        os.Getenv("BINANCE_API_URL"), // Empty = Binance production
 		PriceCheckIntervalMs: priceCheckInterval,
 		MinPriceChangePct:    minPriceChange,
+		Transport:            transport,
+		NATSURL:              natsURL,
+		RedisURL:             os.Getenv("REDIS_URL"),
+		PriceCacheTTLSec:     priceCacheTTL,
+		SecondaryExchange:    secondaryExchange,
+		SecondaryAPIURL:      secondaryAPIURL,
+		FailoverAfterSec:     failoverAfter,
+		SecondaryBandPct:     secondaryBand,
+		PriceSource:          priceSource,
+		AggregateBandPct:     aggregateBand,
+		BinanceStreamURL:     os.Getenv("BINANCE_STREAM_URL"), // Empty = Binance production
+		ShardRouting:         shardRouting,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,88 @@
+package ticker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/grid-trading-bot/pkg/contracts"
+	"github.com/shopspring/decimal"
+)
+
+// AggregateTicker reads every source on each poll and reports the median price of each symbol.
+// A symbol's confidence is the share of the sources whose price is within bandPct of the
+// median - 1 while all venues agree, lower while one is down or off. The poll only fails when
+// no source answers.
+//
+// Venues run their own clocks, so aggregated snapshots carry no server time and grid-trading
+// orders them by sequence alone.
+type AggregateTicker struct {
+	sources []PriceSource
+	bandPct decimal.Decimal
+}
+
+func NewAggregateTicker(bandPct float64, sources ...PriceSource) *AggregateTicker {
+	return &AggregateTicker{sources: sources, bandPct: decimal.NewFromFloat(bandPct)}
+}
+
+// GetPrices polls all sources at once and aggregates what they return
+func (at *AggregateTicker) GetPrices(symbols []string) (*Snapshot, error) {
+	snapshots := make([]*Snapshot, len(at.sources))
+	errs := make([]error, len(at.sources))
+	var wg sync.WaitGroup
+	for i, source := range at.sources {
+		wg.Add(1)
+		go func(i int, source PriceSource) {
+			defer wg.Done()
+			snapshots[i], errs[i] = source.GetPrices(symbols)
+		}(i, source)
+	}
+	wg.Wait()
+
+	var exchanges, failures []string
+	quotes := make(map[string][]decimal.Decimal)
+	for i, snapshot := range snapshots {
+		if errs[i] != nil {
+			failures = append(failures, errs[i].Error())
+			continue
+		}
+		exchanges = append(exchanges, snapshot.Exchange)
+		for symbol, price := range snapshot.Prices {
+			quotes[symbol] = append(quotes[symbol], price)
+		}
+	}
+	if len(exchanges) == 0 {
+		return nil, fmt.Errorf("all %d aggregated sources failed: %s", len(at.sources), strings.Join(failures, "; "))
+	}
+
+	aggregated := &Snapshot{
+		Prices:     make(map[string]decimal.Decimal, len(quotes)),
+		Exchange:   strings.Join(exchanges, "+"),
+		Feed:       contracts.FeedAggregated,
+		Confidence: make(map[string]float64, len(quotes)),
+	}
+	for symbol, prices := range quotes {
+		median := medianOf(prices)
+		agreeing := 0
+		for _, price := range prices {
+			if withinPct(price, median, at.bandPct) {
+				agreeing++
+			}
+		}
+		aggregated.Prices[symbol] = median
+		aggregated.Confidence[symbol] = float64(agreeing) / float64(len(at.sources))
+	}
+	return aggregated, nil
+}
+
+// medianOf returns the middle price, the mean of the two middle ones for an even count
+func medianOf(prices []decimal.Decimal) decimal.Decimal {
+	sorted := append([]decimal.Decimal(nil), prices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return sorted[middle-1].Add(sorted[middle]).Div(decimal.NewFromInt(2))
+}
@@ -0,0 +1,113 @@
+package ticker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	BinanceStreamURL = "wss://stream.binance.com:9443"
+
+	// Binance pings every 20s; a connection silent for longer than this is dead
+	streamReadTimeout = time.Minute
+)
+
+// BinanceStream pushes prices from Binance's market data streams (<symbol>@miniTicker,
+// one update per second while the price moves) instead of polling the REST API
+type BinanceStream struct {
+	baseURL   string
+	dialer    *websocket.Dialer
+	connected atomic.Bool
+}
+
+// miniTickerEvent is one message of a combined stream
+type miniTickerEvent struct {
+	Stream string `json:"stream"`
+	Data   struct {
+		EventTime int64           `json:"E"`
+		Symbol    string          `json:"s"`
+		Close     decimal.Decimal `json:"c"`
+	} `json:"data"`
+}
+
+func NewBinanceStream(baseURL string) *BinanceStream {
+	if baseURL == "" {
+		baseURL = BinanceStreamURL
+	}
+	return &BinanceStream{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		dialer: &websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// Connected reports whether a stream is open
+func (bs *BinanceStream) Connected() bool {
+	return bs.connected.Load()
+}
+
+// Stream subscribes to the symbols and calls onPrice with every price until ctx ends or
+// the connection drops. It always returns an error - ctx.Err() once ctx is done.
+func (bs *BinanceStream) Stream(ctx context.Context, symbols []string, onPrice func(symbol string, price decimal.Decimal, eventTime time.Time)) error {
+	streams := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		streams[i] = strings.ToLower(symbol) + "@miniTicker"
+	}
+
+	conn, _, err := bs.dialer.DialContext(ctx, bs.baseURL+"/stream?streams="+strings.Join(streams, "/"), nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", bs.baseURL, err)
+	}
+	defer conn.Close()
+
+	// Closing the connection is what unblocks a pending read once ctx ends
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(5*time.Second))
+	})
+
+	bs.connected.Store(true)
+	defer bs.connected.Store(false)
+	slog.Info("Streaming prices", "symbols", len(symbols), "url", bs.baseURL)
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("price stream read failed: %w", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+
+		var event miniTickerEvent
+		if err := json.Unmarshal(msg, &event); err != nil || event.Data.Symbol == "" {
+			slog.Warn("Ignoring unexpected price stream message", "message", string(msg), logging.Err(err))
+			continue
+		}
+		onPrice(event.Data.Symbol, event.Data.Close, time.UnixMilli(event.Data.EventTime).UTC())
+	}
+}
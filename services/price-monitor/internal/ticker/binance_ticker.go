@@ -4,11 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/grid-trading-bot/pkg/logging"
 	"github.com/shopspring/decimal"
 )
 
@@ -26,17 +27,20 @@ type BinanceTicker struct {
 	baseURL string
 }
 
-func NewBinanceTicker() *BinanceTicker {
+func NewBinanceTicker(baseURL string) *BinanceTicker {
+	if baseURL == "" {
+		baseURL = BinanceAPIURL
+	}
 	return &BinanceTicker{
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		baseURL: BinanceAPIURL,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
 	}
 }
 
 // GetPrices fetches current prices for multiple symbols
-func (bt *BinanceTicker) GetPrices(symbols []string) (map[string]decimal.Decimal, error) {
+func (bt *BinanceTicker) GetPrices(symbols []string) (*Snapshot, error) {
 	// Normalize symbols to uppercase
 	normalizedSymbols := make([]string, len(symbols))
 	for i, symbol := range symbols {
@@ -61,7 +65,7 @@ func (bt *BinanceTicker) GetPrices(symbols []string) (map[string]decimal.Decimal
 
 	resp, err := bt.client.Do(req)
 	if err != nil {
-		log.Printf("ERROR: Failed to fetch prices from Binance: %v", err)
+		slog.Error("Failed to fetch prices from Binance", logging.Err(err))
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
@@ -72,7 +76,7 @@ func (bt *BinanceTicker) GetPrices(symbols []string) (map[string]decimal.Decimal
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("ERROR: Binance API error %d: %s", resp.StatusCode, body)
+		slog.Error("Binance API error", "status", resp.StatusCode, "body", string(body))
 		return nil, fmt.Errorf("binance API error %d: %s", resp.StatusCode, body)
 	}
 
@@ -91,24 +95,29 @@ func (bt *BinanceTicker) GetPrices(symbols []string) (map[string]decimal.Decimal
 	for _, ticker := range tickers {
 		price, err := decimal.NewFromString(ticker.Price)
 		if err != nil {
-			log.Printf("WARNING: Invalid price for %s: %s (error: %v)", ticker.Symbol, ticker.Price, err)
+			slog.Warn("Invalid price", logging.KeySymbol, ticker.Symbol, "price", ticker.Price, logging.Err(err))
 			continue
 		}
 
 		result[ticker.Symbol] = price
 	}
 
-	return result, nil
+	// The price endpoint carries no timestamp, the Date header is Binance's clock to the second
+	snapshot := &Snapshot{Prices: result, Exchange: "binance"}
+	if serverTime, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		snapshot.ServerTime = serverTime.UTC()
+	}
+	return snapshot, nil
 }
 
 // GetPrice fetches current price for a single symbol
 func (bt *BinanceTicker) GetPrice(symbol string) (decimal.Decimal, error) {
-	prices, err := bt.GetPrices([]string{symbol})
+	snapshot, err := bt.GetPrices([]string{symbol})
 	if err != nil {
 		return decimal.Zero, err
 	}
 
-	price, ok := prices[symbol]
+	price, ok := snapshot.Prices[symbol]
 	if !ok {
 		return decimal.Zero, fmt.Errorf("price not found for symbol %s", symbol)
 	}
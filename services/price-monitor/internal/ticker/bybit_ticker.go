@@ -0,0 +1,93 @@
+package ticker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	BybitAPIURL = "https://api.bybit.com"
+)
+
+// BybitTicker reads Bybit spot prices. Bybit names spot pairs like Binance (ETHUSDT).
+type BybitTicker struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewBybitTicker(baseURL string) *BybitTicker {
+	if baseURL == "" {
+		baseURL = BybitAPIURL
+	}
+	return &BybitTicker{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// GetPrices fetches all spot tickers in one request and keeps the requested symbols
+func (bt *BybitTicker) GetPrices(symbols []string) (*Snapshot, error) {
+	resp, err := bt.client.Get(bt.baseURL + "/v5/market/tickers?category=spot")
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bybit API error %d: %s", resp.StatusCode, body)
+	}
+
+	var tickers struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Time    int64  `json:"time"` // Server time in ms
+		Result  struct {
+			List []struct {
+				Symbol    string `json:"symbol"`
+				LastPrice string `json:"lastPrice"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &tickers); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if tickers.RetCode != 0 {
+		return nil, fmt.Errorf("bybit API error %d: %s", tickers.RetCode, tickers.RetMsg)
+	}
+
+	wanted := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		wanted[strings.ToUpper(symbol)] = true
+	}
+
+	result := make(map[string]decimal.Decimal)
+	for _, ticker := range tickers.Result.List {
+		if !wanted[ticker.Symbol] {
+			continue
+		}
+		price, err := decimal.NewFromString(ticker.LastPrice)
+		if err != nil || !price.IsPositive() {
+			continue
+		}
+		result[ticker.Symbol] = price
+	}
+
+	snapshot := &Snapshot{Prices: result, Exchange: "bybit"}
+	if tickers.Time > 0 {
+		snapshot.ServerTime = time.UnixMilli(tickers.Time).UTC()
+	}
+	return snapshot, nil
+}
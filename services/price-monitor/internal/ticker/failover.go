@@ -0,0 +1,155 @@
+package ticker
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/grid-trading-bot/pkg/logging"
+	"github.com/shopspring/decimal"
+)
+
+// PriceSource fetches current prices for multiple symbols
+type PriceSource interface {
+	GetPrices(symbols []string) (*Snapshot, error)
+}
+
+// Snapshot is one poll of an exchange
+type Snapshot struct {
+	Prices     map[string]decimal.Decimal
+	ServerTime time.Time          // When the exchange answered, by its clock (zero = not reported)
+	Source     string             // Feed that answered: "primary" or "secondary" behind a FailoverTicker, empty otherwise
+	Exchange   string             // Venue that answered, venues joined by "+" behind an AggregateTicker
+	Feed       string             // contracts.FeedAggregated behind an AggregateTicker, empty = REST
+	Confidence map[string]float64 // Per symbol behind an AggregateTicker, nil = 1 (one venue's own price)
+}
+
+// FailoverTicker reads the primary source, and the secondary one once the primary has
+// failed for longer than failoverAfter. The primary is tried first on every poll, so it
+// takes over again as soon as it answers.
+//
+// Another venue's prices can be off, so a secondary price more than bandPct away from
+// the last accepted price of its symbol is held back until the next poll confirms it.
+type FailoverTicker struct {
+	primary       PriceSource
+	secondary     PriceSource
+	secondaryName string
+	failoverAfter time.Duration
+	bandPct       decimal.Decimal
+
+	mu             sync.Mutex
+	failingSince   time.Time                  // Zero while the primary answers
+	onSecondary    bool                       // Last prices came from the secondary
+	lastPrices     map[string]decimal.Decimal // Last accepted price per symbol, either source
+	unconfirmed    map[string]decimal.Decimal // Secondary prices outside the band, awaiting confirmation
+	secondaryPolls int64
+}
+
+// FailoverStatus describes which source prices currently come from
+type FailoverStatus struct {
+	Source         string `json:"source"` // primary | secondary
+	Secondary      string `json:"secondary"`
+	FailingSince   string `json:"primary_failing_since,omitempty"`
+	FailoverAfter  string `json:"failover_after"`
+	BandPct        string `json:"band_pct"`
+	SecondaryPolls int64  `json:"secondary_polls"`
+}
+
+func NewFailoverTicker(primary, secondary PriceSource, secondaryName string, failoverAfter time.Duration, bandPct float64) *FailoverTicker {
+	return &FailoverTicker{
+		primary:       primary,
+		secondary:     secondary,
+		secondaryName: secondaryName,
+		failoverAfter: failoverAfter,
+		bandPct:       decimal.NewFromFloat(bandPct),
+		lastPrices:    make(map[string]decimal.Decimal),
+		unconfirmed:   make(map[string]decimal.Decimal),
+	}
+}
+
+// GetPrices returns primary prices, or banded secondary prices during an extended primary outage
+func (ft *FailoverTicker) GetPrices(symbols []string) (*Snapshot, error) {
+	snapshot, err := ft.primary.GetPrices(symbols)
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	if err == nil {
+		if ft.onSecondary {
+			slog.Info("Primary market data recovered", "down_for", time.Since(ft.failingSince).Round(time.Second), "secondary", ft.secondaryName)
+		}
+		ft.failingSince = time.Time{}
+		ft.onSecondary = false
+		ft.unconfirmed = make(map[string]decimal.Decimal)
+		for symbol, price := range snapshot.Prices {
+			ft.lastPrices[symbol] = price
+		}
+		snapshot.Source = "primary"
+		return snapshot, nil
+	}
+
+	if ft.failingSince.IsZero() {
+		ft.failingSince = time.Now()
+	}
+	if time.Since(ft.failingSince) < ft.failoverAfter {
+		return nil, err
+	}
+
+	secondary, secondaryErr := ft.secondary.GetPrices(symbols)
+	if secondaryErr != nil {
+		return nil, fmt.Errorf("%w; secondary %s also failed: %v", err, ft.secondaryName, secondaryErr)
+	}
+	if !ft.onSecondary {
+		slog.Warn("Primary market data down, failing over", "since", ft.failingSince.Format(time.RFC3339),
+			"secondary", ft.secondaryName, logging.Err(err))
+		ft.onSecondary = true
+	}
+	ft.secondaryPolls++
+
+	return &Snapshot{Prices: ft.banded(secondary.Prices), ServerTime: secondary.ServerTime, Source: "secondary", Exchange: secondary.Exchange}, nil
+}
+
+// banded drops secondary prices outside the band, unless the previous poll saw them too
+func (ft *FailoverTicker) banded(prices map[string]decimal.Decimal) map[string]decimal.Decimal {
+	accepted := make(map[string]decimal.Decimal, len(prices))
+	for symbol, price := range prices {
+		if last, ok := ft.lastPrices[symbol]; ok && !withinPct(price, last, ft.bandPct) {
+			if pending, ok := ft.unconfirmed[symbol]; !ok || !withinPct(price, pending, ft.bandPct) {
+				slog.Warn("Secondary price outside the band, waiting for the next poll to confirm it", logging.KeySymbol, symbol,
+					"secondary", ft.secondaryName, "price", price, "last_price", last, "band_pct", ft.bandPct)
+				ft.unconfirmed[symbol] = price
+				continue
+			}
+		}
+		delete(ft.unconfirmed, symbol)
+		ft.lastPrices[symbol] = price
+		accepted[symbol] = price
+	}
+	return accepted
+}
+
+// Status reports the active source
+func (ft *FailoverTicker) Status() FailoverStatus {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	status := FailoverStatus{
+		Source:         "primary",
+		Secondary:      ft.secondaryName,
+		FailoverAfter:  ft.failoverAfter.String(),
+		BandPct:        ft.bandPct.String(),
+		SecondaryPolls: ft.secondaryPolls,
+	}
+	if ft.onSecondary {
+		status.Source = "secondary"
+	}
+	if !ft.failingSince.IsZero() {
+		status.FailingSince = ft.failingSince.Format(time.RFC3339)
+	}
+	return status
+}
+
+func withinPct(price, reference, pct decimal.Decimal) bool {
+	return price.Sub(reference).Abs().Div(reference).Mul(decimal.NewFromInt(100)).LessThanOrEqual(pct)
+}